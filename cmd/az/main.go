@@ -1,13 +1,23 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/cdobbyn/azure-go-cli/internal/account"
+	"github.com/cdobbyn/azure-go-cli/internal/ad"
 	"github.com/cdobbyn/azure-go-cli/internal/aks"
 	"github.com/cdobbyn/azure-go-cli/internal/auth"
+	"github.com/cdobbyn/azure-go-cli/internal/bicep"
+	"github.com/cdobbyn/azure-go-cli/internal/cliconfig"
+	"github.com/cdobbyn/azure-go-cli/internal/cloud"
+	"github.com/cdobbyn/azure-go-cli/internal/completion"
 	"github.com/cdobbyn/azure-go-cli/internal/dataprotection"
+	"github.com/cdobbyn/azure-go-cli/internal/deployment"
 	"github.com/cdobbyn/azure-go-cli/internal/devops"
 	"github.com/cdobbyn/azure-go-cli/internal/devops/boards"
 	"github.com/cdobbyn/azure-go-cli/internal/devops/pipelines"
@@ -23,14 +33,17 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/monitor"
 	"github.com/cdobbyn/azure-go-cli/internal/network"
 	"github.com/cdobbyn/azure-go-cli/internal/pim"
+	"github.com/cdobbyn/azure-go-cli/internal/policy"
 	"github.com/cdobbyn/azure-go-cli/internal/postgres"
 	"github.com/cdobbyn/azure-go-cli/internal/quota"
 	"github.com/cdobbyn/azure-go-cli/internal/resource"
+	"github.com/cdobbyn/azure-go-cli/internal/rest"
 	"github.com/cdobbyn/azure-go-cli/internal/role"
 	"github.com/cdobbyn/azure-go-cli/internal/storage"
 	"github.com/cdobbyn/azure-go-cli/internal/vm"
 	"github.com/cdobbyn/azure-go-cli/internal/vmss"
 	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
 
@@ -47,18 +60,26 @@ func main() {
 		Short: "Azure CLI implemented in Go",
 		Long:  "A lightweight Azure CLI implementation in Go with core authentication and management commands",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			// Enable debug logging if --debug flag is set
-			debug, _ := cmd.Flags().GetBool("debug")
-			if debug {
-				logger.EnableDebug()
+			if err := configureLogging(cmd); err != nil {
+				logger.Warning("%v", err)
+			}
+
+			// Fill flags the user didn't pass from `az config` defaults. A
+			// broken config file shouldn't block every command, including
+			// the `az config` ones that would fix it.
+			if err := cliconfig.ApplyDefaults(cmd); err != nil {
+				logger.Warning("Ignoring configured defaults: %v", err)
 			}
 		},
 	}
 
 	// Add global flags
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Log each HTTP request with its status, request IDs and duration")
+	rootCmd.PersistentFlags().Bool("only-show-errors", false, "Only show errors, suppressing warnings")
+	rootCmd.PersistentFlags().String("log-format", logger.FormatText, "Log format on stderr ("+logger.FormatText+", "+logger.FormatJSON+")")
 	rootCmd.PersistentFlags().String("subscription", "", "Subscription ID or name (overrides default)")
-	rootCmd.PersistentFlags().StringP("output", "o", "json", "Output format (json, table, tsv, yaml, none)")
+	rootCmd.PersistentFlags().StringP("output", "o", "json", "Output format ("+strings.Join(output.Formats, ", ")+")")
 	rootCmd.PersistentFlags().String("query", "", "JMESPath query string to filter output")
 
 	// Add version command (required by Terraform azurerm provider)
@@ -79,9 +100,15 @@ func main() {
 		auth.NewLoginCommand(),
 		auth.NewLogoutCommand(),
 		account.NewAccountCommand(),
+		ad.NewADCommand(),
 		aks.NewAKSCommand(),
+		bicep.NewBicepCommand(),
 		boards.NewBoardsCommand(),
+		cloud.NewCloudCommand(),
+		cliconfig.NewConfigCommand(),
+		completion.NewCompletionCommand(),
 		dataprotection.NewDataProtectionCommand(),
+		deployment.NewDeploymentCommand(),
 		devops.NewDevOpsCommand(),
 		disk.NewDiskCommand(),
 		encryptionset.NewEncryptionSetCommand(),
@@ -94,19 +121,59 @@ func main() {
 		network.NewNetworkCommand(),
 		pim.NewPIMCommand(),
 		pipelines.NewPipelinesCommand(),
+		policy.NewPolicyCommand(),
 		storage.NewStorageCommand(),
 		postgres.NewPostgresCommand(),
 		keyvault.NewKeyVaultCommand(),
 		quota.NewQuotaCommand(),
 		repos.NewReposCommand(),
 		resource.NewResourceCommand(),
+		rest.NewRestCommand(),
 		role.NewRoleCmd(),
 		vm.NewVMCommand(),
 		vmss.NewVmssCommand(),
 	)
+	completion.Register(rootCmd)
 
-	if err := rootCmd.Execute(); err != nil {
+	start := time.Now()
+	err := rootCmd.Execute()
+	logger.Verbose("Command ran in %.3f seconds", time.Since(start).Seconds())
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		// Commands that run something else, such as az aks command invoke,
+		// pass on its exit code.
+		code := 1
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			code = exitErr.ExitCode()
+		}
+		os.Exit(code)
 	}
 }
+
+// configureLogging sets the console level from --debug, --verbose and
+// --only-show-errors, in that order of precedence, and opens the
+// AZURE_GO_CLI_LOG_FILE sink. A bad log setting is only warned about; it
+// shouldn't stop the command.
+func configureLogging(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	debug, _ := flags.GetBool("debug")
+	verbose, _ := flags.GetBool("verbose")
+	quiet, _ := flags.GetBool("only-show-errors")
+	format, _ := flags.GetString("log-format")
+
+	level := slog.LevelInfo
+	switch {
+	case debug:
+		level = slog.LevelDebug
+	case verbose:
+		level = logger.LevelVerbose
+	case quiet:
+		level = slog.LevelError
+	}
+	return logger.Configure(logger.Options{
+		Level:  level,
+		Format: format,
+		File:   os.Getenv(logger.LogFileEnv),
+	})
+}
@@ -33,7 +33,7 @@ func ListLocations(ctx context.Context, cmd *cobra.Command) error {
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armsubscriptions.NewClient(cred, nil)
+	client, err := armsubscriptions.NewClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create subscriptions client: %w", err)
 	}
@@ -0,0 +1,74 @@
+package managementgroup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+)
+
+// SubscriptionAncestors returns the names of the management groups above a
+// subscription, nearest first, ending with the tenant root group.
+func SubscriptionAncestors(ctx context.Context, subscriptionID string) ([]string, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+	client, err := armmanagementgroups.NewEntitiesClient(cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entities client: %w", err)
+	}
+	pager := client.NewListPager(&armmanagementgroups.EntitiesClientListOptions{
+		Filter: to.Ptr(fmt.Sprintf("name eq '%s'", subscriptionID)),
+		Select: to.Ptr("Name,Type,ParentNameChain"),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up the management groups of subscription %s: %w", subscriptionID, err)
+		}
+		for _, e := range page.Value {
+			if !strings.EqualFold(azure.GetStringValue(e.Name), subscriptionID) || e.Properties == nil {
+				continue
+			}
+			// The chain runs from the root down to the immediate parent.
+			chain := e.Properties.ParentNameChain
+			names := make([]string, 0, len(chain))
+			for i := len(chain) - 1; i >= 0; i-- {
+				names = append(names, azure.GetStringValue(chain[i]))
+			}
+			return names, nil
+		}
+	}
+	return nil, fmt.Errorf("subscription %s not found in the management group hierarchy", subscriptionID)
+}
+
+// GroupAncestors returns the names of the management groups above the named
+// one, nearest first.
+func GroupAncestors(ctx context.Context, name string) ([]string, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(ctx, name, &armmanagementgroups.ClientGetOptions{
+		Expand: to.Ptr(armmanagementgroups.ManagementGroupExpandTypeAncestors),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get management group %q: %w", name, err)
+	}
+	var names []string
+	if p := resp.Properties; p != nil && p.Details != nil {
+		for _, a := range p.Details.ManagementGroupAncestorsChain {
+			names = append(names, azure.GetStringValue(a.Name))
+		}
+	}
+	return names, nil
+}
+
+// GroupID returns the fully qualified ID of the named management group.
+func GroupID(name string) string {
+	return mgProviderPrefix + name
+}
@@ -38,7 +38,7 @@ func newClient() (*armmanagementgroups.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return armmanagementgroups.NewClient(cred, nil)
+	return armmanagementgroups.NewClient(cred, azure.ARMClientOptions())
 }
 
 func groupInfo(g *armmanagementgroups.ManagementGroup) MGInfo {
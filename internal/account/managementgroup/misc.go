@@ -32,7 +32,7 @@ func CheckNameAvailability(ctx context.Context, cmd *cobra.Command, name string)
 	if err != nil {
 		return err
 	}
-	apiClient, err := armmanagementgroups.NewAPIClient(cred, nil)
+	apiClient, err := armmanagementgroups.NewAPIClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create management-groups API client: %w", err)
 	}
@@ -63,7 +63,7 @@ func ListEntities(ctx context.Context, cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
-	entClient, err := armmanagementgroups.NewEntitiesClient(cred, nil)
+	entClient, err := armmanagementgroups.NewEntitiesClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create entities client: %w", err)
 	}
@@ -98,7 +98,7 @@ func ShowHierarchySettings(ctx context.Context, cmd *cobra.Command, name string)
 	if err != nil {
 		return err
 	}
-	hsClient, err := armmanagementgroups.NewHierarchySettingsClient(cred, nil)
+	hsClient, err := armmanagementgroups.NewHierarchySettingsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create hierarchy-settings client: %w", err)
 	}
@@ -13,7 +13,7 @@ func newSubscriptionsClient() (*armmanagementgroups.ManagementGroupSubscriptions
 	if err != nil {
 		return nil, err
 	}
-	return armmanagementgroups.NewManagementGroupSubscriptionsClient(cred, nil)
+	return armmanagementgroups.NewManagementGroupSubscriptionsClient(cred, azure.ARMClientOptions())
 }
 
 // AddSubscription associates a subscription with a management group.
@@ -15,7 +15,7 @@ func newAPIClient() (*armmanagementgroups.APIClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	return armmanagementgroups.NewAPIClient(cred, nil)
+	return armmanagementgroups.NewAPIClient(cred, azure.ARMClientOptions())
 }
 
 // GetTenantBackfill returns the tenant backfill status.
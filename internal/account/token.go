@@ -54,7 +54,7 @@ func GetAccessToken(cmd *cobra.Command, resource string, scopes []string, subscr
 		scope := resource + "/.default"
 		tokenScopes = []string{scope}
 	} else {
-		tokenScopes = []string{"https://management.azure.com/.default"}
+		tokenScopes = []string{azure.ARMScope()}
 	}
 	logger.Debug("Requesting token with scopes: %v", tokenScopes)
 
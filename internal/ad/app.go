@@ -0,0 +1,169 @@
+package ad
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultListLimit is how many applications or service principals list
+// returns without --all; a tenant can hold tens of thousands.
+const defaultListLimit = 100
+
+func newAppCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "app",
+		Short: "Manage Microsoft Entra ID applications",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List applications",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListApps(context.Background(), cmd)
+		},
+	}
+	listCmd.Flags().String("display-name", "", "Only applications whose display name starts with this")
+	listCmd.Flags().String("app-id", "", "Only the application with this application (client) ID")
+	listCmd.Flags().String("identifier-uri", "", "Only the application with this identifier URI")
+	listCmd.Flags().String("filter", "", "OData filter")
+	listCmd.Flags().Bool("all", false, fmt.Sprintf("List all applications; without it at most %d are returned", defaultListLimit))
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show an application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowApp(context.Background(), cmd)
+		},
+	}
+	addAppIDFlag(showCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateApp(context.Background(), cmd)
+		},
+	}
+	createCmd.Flags().String("display-name", "", "Display name of the application")
+	createCmd.Flags().String("sign-in-audience", "", "AzureADMyOrg, AzureADMultipleOrgs, AzureADandPersonalMicrosoftAccount or PersonalMicrosoftAccount")
+	createCmd.Flags().StringSlice("identifier-uris", nil, "Identifier URIs, e.g. api://my-app")
+	createCmd.Flags().StringSlice("web-redirect-uris", nil, "Redirect URIs of the web platform")
+	createCmd.Flags().StringSlice("public-client-redirect-uris", nil, "Redirect URIs of the public client platform")
+	createCmd.MarkFlagRequired("display-name")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete an application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteApp(context.Background(), cmd)
+		},
+	}
+	addAppIDFlag(deleteCmd)
+
+	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd, newCredentialCmd(), newFederatedCredentialCmd())
+	return cmd
+}
+
+func addAppIDFlag(cmd *cobra.Command) {
+	cmd.Flags().String("id", "", "Application (client) ID, object ID or identifier URI of the application")
+	cmd.MarkFlagRequired("id")
+}
+
+// listLimited lists collection, capped at defaultListLimit unless --all is
+// set or a filter narrows the query.
+func listLimited(ctx context.Context, cmd *cobra.Command, c *graph.Client, collection string, query url.Values) ([]interface{}, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	top := 0
+	if !all && len(query) == 0 {
+		top = defaultListLimit
+	}
+	items, err := c.List(ctx, collection, query, top)
+	if err != nil {
+		return nil, err
+	}
+	if top > 0 && len(items) == top {
+		logger.Warning("The result is not complete. Use --all to list everything, or a filter to narrow it down")
+	}
+	return items, nil
+}
+
+func ListApps(ctx context.Context, cmd *cobra.Command) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	appID, _ := cmd.Flags().GetString("app-id")
+	uri, _ := cmd.Flags().GetString("identifier-uri")
+	filter, _ := cmd.Flags().GetString("filter")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	query := listFilter(filter,
+		clause("startswith(displayName,%s)", displayName),
+		clause("appId eq %s", appID),
+		clause("identifierUris/any(s:s eq %s)", uri))
+	apps, err := listLimited(ctx, cmd, c, "/applications", query)
+	if err != nil {
+		return fmt.Errorf("failed to list applications: %w", err)
+	}
+	return output.PrintJSON(cmd, apps)
+}
+
+func ShowApp(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	app, err := getApp(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, app)
+}
+
+func CreateApp(ctx context.Context, cmd *cobra.Command) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	body := map[string]interface{}{"displayName": displayName}
+	if audience, _ := cmd.Flags().GetString("sign-in-audience"); audience != "" {
+		body["signInAudience"] = audience
+	}
+	if uris, _ := cmd.Flags().GetStringSlice("identifier-uris"); len(uris) > 0 {
+		body["identifierUris"] = uris
+	}
+	if uris, _ := cmd.Flags().GetStringSlice("web-redirect-uris"); len(uris) > 0 {
+		body["web"] = map[string]interface{}{"redirectUris": uris}
+	}
+	if uris, _ := cmd.Flags().GetStringSlice("public-client-redirect-uris"); len(uris) > 0 {
+		body["publicClient"] = map[string]interface{}{"redirectUris": uris}
+	}
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	app, err := c.Post(ctx, "/applications", body)
+	if err != nil {
+		return fmt.Errorf("failed to create application: %w", err)
+	}
+	return output.PrintJSON(cmd, app)
+}
+
+func DeleteApp(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	app, err := getApp(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, "/applications/"+graph.ID(app)); err != nil {
+		return fmt.Errorf("failed to delete application: %w", err)
+	}
+	return nil
+}
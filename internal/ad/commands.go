@@ -0,0 +1,22 @@
+package ad
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewADCommand wires `az ad`. Everything goes to Microsoft Graph with a
+// Graph-scoped token from the same credential chain as ARM commands.
+func NewADCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ad",
+		Short: "Manage Microsoft Entra ID users, groups, applications and service principals",
+	}
+	cmd.AddCommand(
+		newUserCmd(),
+		newGroupCmd(),
+		newAppCmd(),
+		newSPCmd(),
+		newSignedInUserCmd(),
+	)
+	return cmd
+}
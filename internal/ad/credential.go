@@ -0,0 +1,347 @@
+package ad
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// workloadIdentityAudience is the audience Entra ID expects in tokens
+// exchanged through a federated credential.
+const workloadIdentityAudience = "api://AzureADTokenExchange"
+
+func newCredentialCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credential",
+		Short: "Manage the password credentials (client secrets) of an application",
+	}
+
+	resetCmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Create a new client secret for an application",
+		Long:  "Create a new client secret for an application. Existing secrets are removed unless --append is given.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ResetCredential(context.Background(), cmd)
+		},
+	}
+	addAppIDFlag(resetCmd)
+	resetCmd.Flags().Bool("append", false, "Keep the existing secrets")
+	resetCmd.Flags().String("display-name", "", "Display name of the secret")
+	addExpiryFlags(resetCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the client secrets of an application (without their values)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			c, err := graph.NewClient()
+			if err != nil {
+				return err
+			}
+			app, err := getApp(context.Background(), c, id)
+			if err != nil {
+				return err
+			}
+			return output.PrintJSON(cmd, passwordCredentials(app))
+		},
+	}
+	addAppIDFlag(listCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a client secret of an application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteCredential(context.Background(), cmd)
+		},
+	}
+	addAppIDFlag(deleteCmd)
+	deleteCmd.Flags().String("key-id", "", "Key ID of the secret")
+	deleteCmd.MarkFlagRequired("key-id")
+
+	cmd.AddCommand(resetCmd, listCmd, deleteCmd)
+	return cmd
+}
+
+func newFederatedCredentialCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "federated-credential",
+		Short: "Manage the federated identity credentials of an application",
+		Long: `Manage the federated identity credentials of an application, which let
+workloads such as AKS pods or GitHub Actions sign in as it with their own
+OIDC tokens instead of a secret.`,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a federated identity credential",
+		Example: `  az ad app federated-credential create --id <app-id> --parameters '{
+    "name": "my-pod",
+    "issuer": "<aks-oidc-issuer-url>",
+    "subject": "system:serviceaccount:default:my-sa"
+  }'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateFederatedCredential(context.Background(), cmd)
+		},
+	}
+	addAppIDFlag(createCmd)
+	createCmd.Flags().String("parameters", "", "Credential (name, issuer, subject, audiences, description): JSON, @file or a file path. audiences defaults to "+workloadIdentityAudience)
+	createCmd.MarkFlagRequired("parameters")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List federated identity credentials",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListFederatedCredentials(context.Background(), cmd)
+		},
+	}
+	addAppIDFlag(listCmd)
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a federated identity credential",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowFederatedCredential(context.Background(), cmd)
+		},
+	}
+	addFederatedCredentialIDFlags(showCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a federated identity credential",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteFederatedCredential(context.Background(), cmd)
+		},
+	}
+	addFederatedCredentialIDFlags(deleteCmd)
+
+	cmd.AddCommand(createCmd, listCmd, showCmd, deleteCmd)
+	return cmd
+}
+
+func addExpiryFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("years", 1, "Number of years the secret is valid for")
+	cmd.Flags().String("end-date", "", "Expiry as an RFC 3339 time or a date; overrides --years")
+}
+
+func addFederatedCredentialIDFlags(cmd *cobra.Command) {
+	addAppIDFlag(cmd)
+	cmd.Flags().String("federated-credential-id", "", "ID or name of the federated identity credential")
+	cmd.MarkFlagRequired("federated-credential-id")
+}
+
+// passwordCredential builds the addPassword body from the expiry flags.
+func passwordCredential(displayName string, years int, endDate string, now time.Time) (map[string]interface{}, error) {
+	end := now.AddDate(years, 0, 0)
+	if endDate != "" {
+		t, err := time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", endDate); err != nil {
+				return nil, fmt.Errorf("invalid --end-date %q: expected an RFC 3339 time or a date", endDate)
+			}
+		}
+		end = t
+	}
+	if !end.After(now) {
+		return nil, fmt.Errorf("the secret would already have expired at %s", end.Format(time.RFC3339))
+	}
+	cred := map[string]interface{}{"endDateTime": end.UTC().Format(time.RFC3339)}
+	if displayName != "" {
+		cred["displayName"] = displayName
+	}
+	return map[string]interface{}{"passwordCredential": cred}, nil
+}
+
+func passwordCredentials(app graph.Object) []interface{} {
+	creds, _ := app["passwordCredentials"].([]interface{})
+	if creds == nil {
+		creds = []interface{}{}
+	}
+	return creds
+}
+
+// addPassword creates a secret on the application with object ID id and
+// returns its value, which Graph only ever shows once.
+func addPassword(ctx context.Context, cmd *cobra.Command, c *graph.Client, id, displayName string) (string, error) {
+	years, _ := cmd.Flags().GetInt("years")
+	endDate, _ := cmd.Flags().GetString("end-date")
+	body, err := passwordCredential(displayName, years, endDate, time.Now())
+	if err != nil {
+		return "", err
+	}
+	result, err := c.Post(ctx, "/applications/"+id+"/addPassword", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to add a client secret: %w", err)
+	}
+	secret, _ := result["secretText"].(string)
+	return secret, nil
+}
+
+// tenantID returns the ID of the signed-in directory.
+func tenantID(ctx context.Context, c *graph.Client) (string, error) {
+	orgs, err := c.List(ctx, "/organization", nil, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the tenant: %w", err)
+	}
+	if len(orgs) == 0 {
+		return "", fmt.Errorf("failed to get the tenant: no organization found")
+	}
+	org, _ := orgs[0].(graph.Object)
+	return graph.ID(org), nil
+}
+
+func ResetCredential(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	appendSecret, _ := cmd.Flags().GetBool("append")
+	displayName, _ := cmd.Flags().GetString("display-name")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	app, err := getApp(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	objectID := graph.ID(app)
+	password, err := addPassword(ctx, cmd, c, objectID, displayName)
+	if err != nil {
+		return err
+	}
+	// Remove the old secrets only once the new one exists, so a failure
+	// never leaves the application without any.
+	if !appendSecret {
+		for _, cred := range passwordCredentials(app) {
+			keyID, _ := cred.(graph.Object)["keyId"].(string)
+			if _, err := c.Post(ctx, "/applications/"+objectID+"/removePassword", map[string]interface{}{"keyId": keyID}); err != nil {
+				return fmt.Errorf("failed to remove client secret %s: %w", keyID, err)
+			}
+		}
+	}
+	tenant, err := tenantID(ctx, c)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, map[string]interface{}{
+		"appId":    app["appId"],
+		"password": password,
+		"tenant":   tenant,
+	})
+}
+
+func DeleteCredential(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	keyID, _ := cmd.Flags().GetString("key-id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	app, err := getApp(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	if _, err := c.Post(ctx, "/applications/"+graph.ID(app)+"/removePassword", map[string]interface{}{"keyId": keyID}); err != nil {
+		return fmt.Errorf("failed to delete client secret: %w", err)
+	}
+	return nil
+}
+
+// federatedCredentials returns the credentials collection path of --id.
+func federatedCredentials(ctx context.Context, cmd *cobra.Command, c *graph.Client) (string, error) {
+	id, _ := cmd.Flags().GetString("id")
+	app, err := getApp(ctx, c, id)
+	if err != nil {
+		return "", err
+	}
+	return "/applications/" + graph.ID(app) + "/federatedIdentityCredentials", nil
+}
+
+// federatedCredentialBody validates --parameters and fills in the default
+// audience.
+func federatedCredentialBody(params graph.Object) (graph.Object, error) {
+	for _, key := range []string{"name", "issuer", "subject"} {
+		if v, _ := params[key].(string); v == "" {
+			return nil, fmt.Errorf("--parameters must include %q", key)
+		}
+	}
+	if params["audiences"] == nil {
+		params["audiences"] = []string{workloadIdentityAudience}
+	}
+	return params, nil
+}
+
+func CreateFederatedCredential(ctx context.Context, cmd *cobra.Command) error {
+	raw, _ := cmd.Flags().GetString("parameters")
+	params, err := readJSON("parameters", raw)
+	if err != nil {
+		return err
+	}
+	body, err := federatedCredentialBody(params)
+	if err != nil {
+		return err
+	}
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	path, err := federatedCredentials(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	result, err := c.Post(ctx, path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create federated identity credential: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func ListFederatedCredentials(ctx context.Context, cmd *cobra.Command) error {
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	path, err := federatedCredentials(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	items, err := c.List(ctx, path, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list federated identity credentials: %w", err)
+	}
+	return output.PrintJSON(cmd, items)
+}
+
+func ShowFederatedCredential(ctx context.Context, cmd *cobra.Command) error {
+	credID, _ := cmd.Flags().GetString("federated-credential-id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	path, err := federatedCredentials(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	result, err := c.Get(ctx, path+"/"+credID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get federated identity credential: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func DeleteFederatedCredential(ctx context.Context, cmd *cobra.Command) error {
+	credID, _ := cmd.Flags().GetString("federated-credential-id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	path, err := federatedCredentials(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, path+"/"+credID); err != nil {
+		return fmt.Errorf("failed to delete federated identity credential: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package ad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group",
+		Short: "Manage Microsoft Entra ID groups and their members",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List groups",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListGroups(context.Background(), cmd)
+		},
+	}
+	listCmd.Flags().String("display-name", "", "Only groups whose display name starts with this")
+	listCmd.Flags().String("filter", "", "OData filter, e.g. \"securityEnabled eq true\"")
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowGroup(context.Background(), cmd)
+		},
+	}
+	addGroupFlag(showCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a security group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateGroup(context.Background(), cmd)
+		},
+	}
+	createCmd.Flags().String("display-name", "", "Display name of the group")
+	createCmd.Flags().String("mail-nickname", "", "Mail alias of the group")
+	createCmd.Flags().String("description", "", "Description of the group")
+	createCmd.MarkFlagRequired("display-name")
+	createCmd.MarkFlagRequired("mail-nickname")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteGroup(context.Background(), cmd)
+		},
+	}
+	addGroupFlag(deleteCmd)
+
+	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd, newGroupMemberCmd())
+	return cmd
+}
+
+func newGroupMemberCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "member",
+		Short: "Manage group members",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the direct members of a group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListGroupMembers(context.Background(), cmd)
+		},
+	}
+	addGroupFlag(listCmd)
+
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a member to a group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return AddGroupMember(context.Background(), cmd)
+		},
+	}
+	addMemberFlags(addCmd)
+
+	removeCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a member from a group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RemoveGroupMember(context.Background(), cmd)
+		},
+	}
+	addMemberFlags(removeCmd)
+
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check whether an object is a member of a group, directly or through nested groups",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CheckGroupMember(context.Background(), cmd)
+		},
+	}
+	addMemberFlags(checkCmd)
+
+	cmd.AddCommand(listCmd, addCmd, removeCmd, checkCmd)
+	return cmd
+}
+
+func addGroupFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("group", "g", "", "Object ID or display name of the group")
+	cmd.MarkFlagRequired("group")
+}
+
+func addMemberFlags(cmd *cobra.Command) {
+	addGroupFlag(cmd)
+	cmd.Flags().String("member-id", "", "Object ID of the user, group or service principal")
+	cmd.MarkFlagRequired("member-id")
+}
+
+// groupID resolves --group to an object ID.
+func groupID(ctx context.Context, cmd *cobra.Command, c *graph.Client) (string, error) {
+	id, _ := cmd.Flags().GetString("group")
+	if isGUID(id) {
+		return id, nil
+	}
+	group, err := getGroup(ctx, c, id)
+	if err != nil {
+		return "", err
+	}
+	return graph.ID(group), nil
+}
+
+func ListGroups(ctx context.Context, cmd *cobra.Command) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	filter, _ := cmd.Flags().GetString("filter")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	groups, err := c.List(ctx, "/groups", listFilter(filter, clause("startswith(displayName,%s)", displayName)), 0)
+	if err != nil {
+		return fmt.Errorf("failed to list groups: %w", err)
+	}
+	return output.PrintJSON(cmd, groups)
+}
+
+func ShowGroup(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("group")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	group, err := getGroup(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, group)
+}
+
+func CreateGroup(ctx context.Context, cmd *cobra.Command) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	nickname, _ := cmd.Flags().GetString("mail-nickname")
+	body := map[string]interface{}{
+		"displayName":     displayName,
+		"mailNickname":    nickname,
+		"mailEnabled":     false,
+		"securityEnabled": true,
+	}
+	if description, _ := cmd.Flags().GetString("description"); description != "" {
+		body["description"] = description
+	}
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	group, err := c.Post(ctx, "/groups", body)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	return output.PrintJSON(cmd, group)
+}
+
+func DeleteGroup(ctx context.Context, cmd *cobra.Command) error {
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	id, err := groupID(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, "/groups/"+id); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	return nil
+}
+
+func ListGroupMembers(ctx context.Context, cmd *cobra.Command) error {
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	id, err := groupID(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	members, err := c.List(ctx, "/groups/"+id+"/members", nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list group members: %w", err)
+	}
+	return output.PrintJSON(cmd, members)
+}
+
+func AddGroupMember(ctx context.Context, cmd *cobra.Command) error {
+	member, _ := cmd.Flags().GetString("member-id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	id, err := groupID(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	ref := map[string]interface{}{
+		"@odata.id": azure.GraphEndpoint() + "/" + graph.Version + "/directoryObjects/" + member,
+	}
+	if _, err := c.Post(ctx, "/groups/"+id+"/members/$ref", ref); err != nil {
+		// Adding an existing member is a no-op, as in az ad group member add.
+		if strings.Contains(err.Error(), "already exist") {
+			return nil
+		}
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+	return nil
+}
+
+func RemoveGroupMember(ctx context.Context, cmd *cobra.Command) error {
+	member, _ := cmd.Flags().GetString("member-id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	id, err := groupID(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, "/groups/"+id+"/members/"+member+"/$ref"); err != nil {
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+	return nil
+}
+
+func CheckGroupMember(ctx context.Context, cmd *cobra.Command) error {
+	member, _ := cmd.Flags().GetString("member-id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	id, err := groupID(ctx, cmd, c)
+	if err != nil {
+		return err
+	}
+	result, err := c.Post(ctx, "/directoryObjects/"+member+"/checkMemberGroups", map[string]interface{}{
+		"groupIds": []string{id},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check group membership: %w", err)
+	}
+	groups, _ := result["value"].([]interface{})
+	return output.PrintJSON(cmd, map[string]interface{}{"value": len(groups) > 0})
+}
@@ -0,0 +1,144 @@
+package ad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/google/uuid"
+)
+
+func isGUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+// findOne returns the single object matching filter in collection, naming
+// the object by what in the errors.
+func findOne(ctx context.Context, c *graph.Client, collection, filter, what string) (graph.Object, error) {
+	items, err := c.List(ctx, collection, url.Values{"$filter": {filter}}, 2)
+	if err != nil {
+		return nil, err
+	}
+	switch len(items) {
+	case 0:
+		return nil, fmt.Errorf("%s not found", what)
+	case 1:
+		obj, _ := items[0].(graph.Object)
+		return obj, nil
+	}
+	return nil, fmt.Errorf("more than one %s found; please use the object ID", what)
+}
+
+// getByAppIDOrObjectID looks a GUID up first as an appId, then as an object
+// ID, since `--id` takes either for applications and service principals.
+func getByAppIDOrObjectID(ctx context.Context, c *graph.Client, collection, id string) (graph.Object, error) {
+	obj, err := c.Get(ctx, fmt.Sprintf("%s(appId=%s)", collection, graph.Quote(id)), nil)
+	if graph.IsNotFound(err) {
+		obj, err = c.Get(ctx, collection+"/"+id, nil)
+	}
+	return obj, err
+}
+
+// getUser takes an object ID or a user principal name; Graph accepts both
+// in the path.
+func getUser(ctx context.Context, c *graph.Client, id string) (graph.Object, error) {
+	user, err := c.Get(ctx, "/users/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user '%s': %w", id, err)
+	}
+	return user, nil
+}
+
+// getGroup takes an object ID or a display name.
+func getGroup(ctx context.Context, c *graph.Client, id string) (graph.Object, error) {
+	var group graph.Object
+	var err error
+	if isGUID(id) {
+		group, err = c.Get(ctx, "/groups/"+id, nil)
+	} else {
+		group, err = findOne(ctx, c, "/groups", "displayName eq "+graph.Quote(id), fmt.Sprintf("group '%s'", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group '%s': %w", id, err)
+	}
+	return group, nil
+}
+
+// getApp takes an application (client) ID, an object ID or an identifier
+// URI, as az ad app --id does.
+func getApp(ctx context.Context, c *graph.Client, id string) (graph.Object, error) {
+	var app graph.Object
+	var err error
+	if isGUID(id) {
+		app, err = getByAppIDOrObjectID(ctx, c, "/applications", id)
+	} else {
+		app, err = findOne(ctx, c, "/applications", fmt.Sprintf("identifierUris/any(s:s eq %s)", graph.Quote(id)), fmt.Sprintf("application '%s'", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application '%s': %w", id, err)
+	}
+	return app, nil
+}
+
+// getSP takes an application ID, an object ID or a service principal name.
+func getSP(ctx context.Context, c *graph.Client, id string) (graph.Object, error) {
+	var sp graph.Object
+	var err error
+	if isGUID(id) {
+		sp, err = getByAppIDOrObjectID(ctx, c, "/servicePrincipals", id)
+	} else {
+		sp, err = findOne(ctx, c, "/servicePrincipals", fmt.Sprintf("servicePrincipalNames/any(s:s eq %s)", graph.Quote(id)), fmt.Sprintf("service principal '%s'", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service principal '%s': %w", id, err)
+	}
+	return sp, nil
+}
+
+// listFilter joins the OData clauses for the list filter flags with the
+// free-form --filter.
+func listFilter(extra string, clauses ...string) url.Values {
+	var parts []string
+	for _, c := range clauses {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+	if extra != "" {
+		parts = append(parts, "("+extra+")")
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return url.Values{"$filter": {strings.Join(parts, " and ")}}
+}
+
+// clause formats an OData clause, or returns "" when value is empty.
+func clause(format, value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf(format, graph.Quote(value))
+}
+
+// readJSON accepts inline JSON, @file or a file path.
+func readJSON(flag, value string) (graph.Object, error) {
+	data := []byte(value)
+	if strings.HasPrefix(value, "@") || !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		d, err := os.ReadFile(strings.TrimPrefix(value, "@"))
+		if err != nil {
+			return nil, fmt.Errorf("--%s: expected JSON or a JSON file: %w", flag, err)
+		}
+		data = d
+	}
+	var v graph.Object
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("--%s: failed to parse JSON: %w", flag, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,127 @@
+package ad
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+)
+
+// directory answers Graph requests from a map of "path?filter" to body;
+// anything else is a 404.
+type directory map[string]string
+
+func (d directory) Do(req *http.Request) (*http.Response, error) {
+	key := strings.TrimPrefix(req.URL.Path, "/v1.0")
+	if f := req.URL.Query().Get("$filter"); f != "" {
+		key += "?" + f
+	}
+	status, body := 200, d[key]
+	if body == "" {
+		status, body = 404, `{"error": {"code": "Request_ResourceNotFound"}}`
+	}
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func testClient(t *testing.T, d directory) *graph.Client {
+	t.Setenv("HOME", t.TempDir())
+	pl := runtime.NewPipeline("test", "v0", runtime.PipelineOptions{}, &policy.ClientOptions{Transport: d})
+	return graph.NewClientWithPipeline(pl)
+}
+
+const (
+	appID    = "11111111-1111-1111-1111-111111111111"
+	objectID = "22222222-2222-2222-2222-222222222222"
+)
+
+func TestGetApp(t *testing.T) {
+	c := testClient(t, directory{
+		"/applications(appId='" + appID + "')":                     `{"id": "` + objectID + `", "appId": "` + appID + `"}`,
+		"/applications/" + objectID:                                `{"id": "` + objectID + `", "appId": "` + appID + `"}`,
+		"/applications?identifierUris/any(s:s eq 'api://my-app')":  `{"value": [{"id": "` + objectID + `"}]}`,
+		"/applications?identifierUris/any(s:s eq 'api://twice')":   `{"value": [{"id": "a"}, {"id": "b"}]}`,
+		"/applications?identifierUris/any(s:s eq 'api://nowhere')": `{"value": []}`,
+	})
+	for _, id := range []string{appID, objectID, "api://my-app"} {
+		app, err := getApp(context.Background(), c, id)
+		if err != nil || graph.ID(app) != objectID {
+			t.Errorf("%s: got %v, %v", id, app, err)
+		}
+	}
+	if _, err := getApp(context.Background(), c, "api://twice"); err == nil || !strings.Contains(err.Error(), "more than one") {
+		t.Errorf("ambiguous: err = %v", err)
+	}
+	if _, err := getApp(context.Background(), c, "api://nowhere"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing: err = %v", err)
+	}
+}
+
+func TestGetGroupByName(t *testing.T) {
+	c := testClient(t, directory{
+		"/groups?displayName eq 'O''Brien team'": `{"value": [{"id": "g1"}]}`,
+	})
+	group, err := getGroup(context.Background(), c, "O'Brien team")
+	if err != nil || graph.ID(group) != "g1" {
+		t.Errorf("got %v, %v", group, err)
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	got := listFilter("accountEnabled eq true", clause("startswith(displayName,%s)", "ali"), clause("userPrincipalName eq %s", ""))
+	want := "startswith(displayName,'ali') and (accountEnabled eq true)"
+	if got.Get("$filter") != want {
+		t.Errorf("got %q, want %q", got.Get("$filter"), want)
+	}
+	if listFilter("", clause("x eq %s", "")) != nil {
+		t.Error("expected no query without filters")
+	}
+}
+
+func TestPasswordCredential(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	body, err := passwordCredential("rbac", 2, "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred := body["passwordCredential"].(map[string]interface{})
+	if cred["endDateTime"] != "2028-10-17T12:00:00Z" || cred["displayName"] != "rbac" {
+		t.Errorf("got %v", cred)
+	}
+	body, _ = passwordCredential("", 1, "2027-03-01", now)
+	if got := body["passwordCredential"].(map[string]interface{})["endDateTime"]; got != "2027-03-01T00:00:00Z" {
+		t.Errorf("end date: got %v", got)
+	}
+	if _, err := passwordCredential("", 1, "2020-01-01", now); err == nil {
+		t.Error("expected an error for a past end date")
+	}
+	if _, err := passwordCredential("", 1, "soon", now); err == nil {
+		t.Error("expected an error for a bad end date")
+	}
+}
+
+func TestFederatedCredentialBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fic.json")
+	os.WriteFile(path, []byte(`{"name": "pod", "issuer": "https://oidc.example", "subject": "system:serviceaccount:default:sa"}`), 0600)
+	params, err := readJSON("parameters", "@"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := federatedCredentialBody(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aud, _ := body["audiences"].([]string); len(aud) != 1 || aud[0] != workloadIdentityAudience {
+		t.Errorf("audiences = %v", body["audiences"])
+	}
+	if _, err := federatedCredentialBody(graph.Object{"name": "pod", "issuer": "x"}); err == nil || !strings.Contains(err.Error(), "subject") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package ad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newSignedInUserCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signed-in-user",
+		Short: "Show the signed-in user and the objects it owns",
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the signed-in user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := graph.NewClient()
+			if err != nil {
+				return err
+			}
+			me, err := c.Get(context.Background(), "/me", nil)
+			if err != nil {
+				return fmt.Errorf("failed to get the signed-in user (service principals have none): %w", err)
+			}
+			return output.PrintJSON(cmd, me)
+		},
+	}
+
+	ownedCmd := &cobra.Command{
+		Use:   "list-owned-objects",
+		Short: "List the directory objects owned by the signed-in user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListOwnedObjects(context.Background(), cmd)
+		},
+	}
+	ownedCmd.Flags().String("type", "", "Only objects of this type, e.g. application or servicePrincipal")
+
+	cmd.AddCommand(showCmd, ownedCmd)
+	return cmd
+}
+
+func ListOwnedObjects(ctx context.Context, cmd *cobra.Command) error {
+	objectType, _ := cmd.Flags().GetString("type")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	objects, err := c.List(ctx, "/me/ownedObjects", nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list owned objects: %w", err)
+	}
+	if objectType == "" {
+		return output.PrintJSON(cmd, objects)
+	}
+	// @odata.type is "#microsoft.graph.<type>".
+	kept := []interface{}{}
+	for _, o := range objects {
+		obj, _ := o.(graph.Object)
+		t, _ := obj["@odata.type"].(string)
+		if strings.EqualFold(strings.TrimPrefix(t, "#microsoft.graph."), objectType) {
+			kept = append(kept, o)
+		}
+	}
+	return output.PrintJSON(cmd, kept)
+}
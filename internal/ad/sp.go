@@ -0,0 +1,217 @@
+package ad
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v3"
+	roleassignment "github.com/cdobbyn/azure-go-cli/internal/role/assignment"
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newSPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sp",
+		Short: "Manage Microsoft Entra ID service principals",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List service principals",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListSPs(context.Background(), cmd)
+		},
+	}
+	listCmd.Flags().String("display-name", "", "Only service principals whose display name starts with this")
+	listCmd.Flags().String("spn", "", "Only the service principal with this service principal name")
+	listCmd.Flags().String("filter", "", "OData filter")
+	listCmd.Flags().Bool("all", false, fmt.Sprintf("List all service principals; without it at most %d are returned", defaultListLimit))
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a service principal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowSP(context.Background(), cmd)
+		},
+	}
+	addSPIDFlag(showCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a service principal for an application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateSP(context.Background(), cmd)
+		},
+	}
+	createCmd.Flags().String("id", "", "Application (client) ID or identifier URI of the application")
+	createCmd.MarkFlagRequired("id")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a service principal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteSP(context.Background(), cmd)
+		},
+	}
+	addSPIDFlag(deleteCmd)
+
+	rbacCmd := &cobra.Command{
+		Use:   "create-for-rbac",
+		Short: "Create an application and service principal with a client secret, optionally granting it a role",
+		Long: `Create an application and its service principal, or reuse the application
+with the same display name, add a client secret and print the credentials.
+With --role and --scopes the service principal is also granted that role.`,
+		Example: `  az ad sp create-for-rbac -n deployer --role Contributor --scopes /subscriptions/<id>/resourceGroups/MyRG`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateForRBAC(context.Background(), cmd)
+		},
+	}
+	rbacCmd.Flags().StringP("display-name", "n", "", "Display name of the application; defaults to azure-go-cli-<timestamp>")
+	rbacCmd.Flags().String("role", "", "Role to grant the service principal at --scopes")
+	rbacCmd.Flags().StringSlice("scopes", nil, "Scopes to grant --role at")
+	rbacCmd.MarkFlagsRequiredTogether("role", "scopes")
+	addExpiryFlags(rbacCmd)
+
+	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd, rbacCmd)
+	return cmd
+}
+
+func addSPIDFlag(cmd *cobra.Command) {
+	cmd.Flags().String("id", "", "Application (client) ID, object ID or service principal name")
+	cmd.MarkFlagRequired("id")
+}
+
+func ListSPs(ctx context.Context, cmd *cobra.Command) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	spn, _ := cmd.Flags().GetString("spn")
+	filter, _ := cmd.Flags().GetString("filter")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	query := listFilter(filter,
+		clause("startswith(displayName,%s)", displayName),
+		clause("servicePrincipalNames/any(s:s eq %s)", spn))
+	sps, err := listLimited(ctx, cmd, c, "/servicePrincipals", query)
+	if err != nil {
+		return fmt.Errorf("failed to list service principals: %w", err)
+	}
+	return output.PrintJSON(cmd, sps)
+}
+
+func ShowSP(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	sp, err := getSP(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, sp)
+}
+
+func CreateSP(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	app, err := getApp(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	sp, err := c.Post(ctx, "/servicePrincipals", map[string]interface{}{"appId": app["appId"]})
+	if err != nil {
+		return fmt.Errorf("failed to create service principal: %w", err)
+	}
+	return output.PrintJSON(cmd, sp)
+}
+
+func DeleteSP(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	sp, err := getSP(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, "/servicePrincipals/"+graph.ID(sp)); err != nil {
+		return fmt.Errorf("failed to delete service principal: %w", err)
+	}
+	return nil
+}
+
+func CreateForRBAC(ctx context.Context, cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("display-name")
+	role, _ := cmd.Flags().GetString("role")
+	scopes, _ := cmd.Flags().GetStringSlice("scopes")
+	if name == "" {
+		name = "azure-go-cli-" + time.Now().UTC().Format("2006-01-02-15-04-05")
+	}
+
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+
+	// Reusing an application of the same name makes the command safe to
+	// rerun, as in az ad sp create-for-rbac.
+	apps, err := c.List(ctx, "/applications", graph.Filter("displayName eq %s", graph.Quote(name)), 2)
+	if err != nil {
+		return fmt.Errorf("failed to look up application '%s': %w", name, err)
+	}
+	var app graph.Object
+	switch len(apps) {
+	case 0:
+		if app, err = c.Post(ctx, "/applications", map[string]interface{}{"displayName": name}); err != nil {
+			return fmt.Errorf("failed to create application: %w", err)
+		}
+	case 1:
+		app, _ = apps[0].(graph.Object)
+		logger.Warning("Found an existing application instance: (id) %s. We will patch it.", graph.ID(app))
+	default:
+		return fmt.Errorf("more than one application has the display name '%s'", name)
+	}
+	appID, _ := app["appId"].(string)
+
+	sp, err := c.Get(ctx, fmt.Sprintf("/servicePrincipals(appId=%s)", graph.Quote(appID)), nil)
+	if graph.IsNotFound(err) {
+		sp, err = c.Post(ctx, "/servicePrincipals", map[string]interface{}{"appId": appID})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create service principal: %w", err)
+	}
+
+	password, err := addPassword(ctx, cmd, c, graph.ID(app), "rbac")
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range scopes {
+		logger.Warning("Creating '%s' role assignment under scope '%s'", role, scope)
+		if _, err := roleassignment.AssignNewPrincipal(ctx, scope, graph.ID(sp), to.Ptr(armauthorization.PrincipalTypeServicePrincipal), role); err != nil {
+			return fmt.Errorf("failed to assign '%s' at %s: %w", role, scope, err)
+		}
+	}
+
+	tenant, err := tenantID(ctx, c)
+	if err != nil {
+		return err
+	}
+	logger.Warning("The output includes credentials that you must protect. Be sure that you do not include these credentials in your code or check the credentials into your source control.")
+	return output.PrintJSON(cmd, map[string]interface{}{
+		"appId":       appID,
+		"displayName": name,
+		"password":    password,
+		"tenant":      tenant,
+	})
+}
@@ -0,0 +1,146 @@
+package ad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage Microsoft Entra ID users",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListUsers(context.Background(), cmd)
+		},
+	}
+	listCmd.Flags().String("display-name", "", "Only users whose display name starts with this")
+	listCmd.Flags().String("upn", "", "Only the user with this user principal name")
+	listCmd.Flags().String("filter", "", "OData filter, e.g. \"accountEnabled eq false\"")
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowUser(context.Background(), cmd)
+		},
+	}
+	addUserIDFlag(showCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateUser(context.Background(), cmd)
+		},
+	}
+	createCmd.Flags().String("display-name", "", "Display name of the user")
+	createCmd.Flags().String("user-principal-name", "", "User principal name, e.g. alice@contoso.com")
+	createCmd.Flags().String("password", "", "Initial password")
+	createCmd.Flags().String("mail-nickname", "", "Mail alias; defaults to the part of the UPN before '@'")
+	createCmd.Flags().Bool("force-change-password-next-sign-in", false, "Require the user to change the password at next sign-in")
+	createCmd.MarkFlagRequired("display-name")
+	createCmd.MarkFlagRequired("user-principal-name")
+	createCmd.MarkFlagRequired("password")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteUser(context.Background(), cmd)
+		},
+	}
+	addUserIDFlag(deleteCmd)
+
+	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd)
+	return cmd
+}
+
+func addUserIDFlag(cmd *cobra.Command) {
+	cmd.Flags().String("id", "", "Object ID or user principal name of the user")
+	cmd.MarkFlagRequired("id")
+}
+
+func ListUsers(ctx context.Context, cmd *cobra.Command) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	upn, _ := cmd.Flags().GetString("upn")
+	filter, _ := cmd.Flags().GetString("filter")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	query := listFilter(filter, clause("startswith(displayName,%s)", displayName), clause("userPrincipalName eq %s", upn))
+	users, err := c.List(ctx, "/users", query, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	return output.PrintJSON(cmd, users)
+}
+
+func ShowUser(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	user, err := getUser(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, user)
+}
+
+func CreateUser(ctx context.Context, cmd *cobra.Command) error {
+	displayName, _ := cmd.Flags().GetString("display-name")
+	upn, _ := cmd.Flags().GetString("user-principal-name")
+	password, _ := cmd.Flags().GetString("password")
+	nickname, _ := cmd.Flags().GetString("mail-nickname")
+	forceChange, _ := cmd.Flags().GetBool("force-change-password-next-sign-in")
+	if nickname == "" {
+		nickname, _, _ = strings.Cut(upn, "@")
+	}
+
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	user, err := c.Post(ctx, "/users", map[string]interface{}{
+		"accountEnabled":    true,
+		"displayName":       displayName,
+		"userPrincipalName": upn,
+		"mailNickname":      nickname,
+		"passwordProfile": map[string]interface{}{
+			"password":                      password,
+			"forceChangePasswordNextSignIn": forceChange,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return output.PrintJSON(cmd, user)
+}
+
+func DeleteUser(ctx context.Context, cmd *cobra.Command) error {
+	id, _ := cmd.Flags().GetString("id")
+	c, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	user, err := getUser(ctx, c, id)
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, "/users/"+graph.ID(user)); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
@@ -20,7 +20,7 @@ func Disable(ctx context.Context, clusterName, resourceGroup, addonName string)
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
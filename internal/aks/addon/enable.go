@@ -20,7 +20,7 @@ func Enable(ctx context.Context, clusterName, resourceGroup, addonName string, a
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, clusterName, resourceGroup, a
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
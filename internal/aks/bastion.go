@@ -69,7 +69,7 @@ func Bastion(ctx context.Context, opts BastionOptions) error {
 	}
 
 	// Get cluster info
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
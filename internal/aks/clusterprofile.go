@@ -0,0 +1,291 @@
+package aks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/enumflag"
+	"github.com/spf13/cobra"
+)
+
+// autoscalerProfileKeys are the keys --cluster-autoscaler-profile accepts,
+// which are also the API's JSON names. The bool ones take true/false; the
+// rest are strings the API validates itself.
+var autoscalerProfileKeys = map[string]bool{
+	"balance-similar-node-groups":           false,
+	"daemonset-eviction-for-empty-nodes":    true,
+	"daemonset-eviction-for-occupied-nodes": true,
+	"expander":                              false,
+	"ignore-daemonsets-utilization":         true,
+	"max-empty-bulk-delete":                 false,
+	"max-graceful-termination-sec":          false,
+	"max-node-provision-time":               false,
+	"max-total-unready-percentage":          false,
+	"new-pod-scale-up-delay":                false,
+	"ok-total-unready-count":                false,
+	"scale-down-delay-after-add":            false,
+	"scale-down-delay-after-delete":         false,
+	"scale-down-delay-after-failure":        false,
+	"scale-down-unneeded-time":              false,
+	"scale-down-unready-time":               false,
+	"scale-down-utilization-threshold":      false,
+	"scan-interval":                         false,
+	"skip-nodes-with-local-storage":         false,
+	"skip-nodes-with-system-pods":           false,
+}
+
+// addProfileFlags registers the flags create and update share. Each only
+// touches the cluster when given, so update leaves everything else alone.
+func addProfileFlags(cmd *cobra.Command) {
+	cmd.Flags().String("network-policy", "", "Network policy engine: azure, calico, cilium or none")
+	cmd.Flags().String("network-plugin-mode", "", "Network plugin mode: overlay (requires --network-plugin azure)")
+	cmd.Flags().String("network-dataplane", "", "Network dataplane: azure or cilium (required for --network-policy cilium)")
+	cmd.Flags().String("api-server-authorized-ip-ranges", "", "Comma-separated CIDRs allowed to reach the API server; \"\" clears the list")
+	cmd.Flags().Bool("enable-aad", false, "Enable AKS-managed Entra ID integration")
+	cmd.Flags().String("aad-admin-group-object-ids", "", "Comma-separated Entra ID group object IDs given cluster admin")
+	cmd.Flags().String("aad-tenant-id", "", "Entra ID tenant for authentication (default: the subscription's tenant)")
+	cmd.Flags().Bool("enable-azure-rbac", false, "Use Azure RBAC for Kubernetes authorization (requires Entra ID integration)")
+	cmd.Flags().Bool("enable-oidc-issuer", false, "Enable the OIDC issuer")
+	cmd.Flags().Bool("enable-workload-identity", false, "Enable workload identity (requires the OIDC issuer)")
+	cmd.Flags().String("assign-identity", "", "Resource ID of a user-assigned identity for the control plane")
+	cmd.Flags().StringSlice("cluster-autoscaler-profile", nil, "Cluster autoscaler settings as key=value, e.g. scan-interval=30s expander=least-waste")
+	cmd.Flags().String("tier", "", "Pricing tier: free, standard or premium")
+	cmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
+}
+
+// applyProfileFlags applies the shared flags that were given to mc.
+func applyProfileFlags(cmd *cobra.Command, mc *armcontainerservice.ManagedCluster) error {
+	flags := cmd.Flags()
+	if mc.Properties == nil {
+		mc.Properties = &armcontainerservice.ManagedClusterProperties{}
+	}
+	props := mc.Properties
+	network := func() *armcontainerservice.NetworkProfile {
+		if props.NetworkProfile == nil {
+			props.NetworkProfile = &armcontainerservice.NetworkProfile{}
+		}
+		return props.NetworkProfile
+	}
+	apiServer := func() *armcontainerservice.ManagedClusterAPIServerAccessProfile {
+		if props.APIServerAccessProfile == nil {
+			props.APIServerAccessProfile = &armcontainerservice.ManagedClusterAPIServerAccessProfile{}
+		}
+		return props.APIServerAccessProfile
+	}
+	aad := func() *armcontainerservice.ManagedClusterAADProfile {
+		if props.AADProfile == nil {
+			props.AADProfile = &armcontainerservice.ManagedClusterAADProfile{}
+		}
+		return props.AADProfile
+	}
+
+	if flags.Changed("network-policy") {
+		v, _ := flags.GetString("network-policy")
+		policy, err := enumflag.Parse("network-policy", v, armcontainerservice.PossibleNetworkPolicyValues())
+		if err != nil {
+			return err
+		}
+		network().NetworkPolicy = &policy
+	}
+	if flags.Changed("network-plugin-mode") {
+		v, _ := flags.GetString("network-plugin-mode")
+		mode, err := enumflag.Parse("network-plugin-mode", v, armcontainerservice.PossibleNetworkPluginModeValues())
+		if err != nil {
+			return err
+		}
+		network().NetworkPluginMode = &mode
+	}
+	if flags.Changed("network-dataplane") {
+		v, _ := flags.GetString("network-dataplane")
+		dataplane, err := enumflag.Parse("network-dataplane", v, armcontainerservice.PossibleNetworkDataplaneValues())
+		if err != nil {
+			return err
+		}
+		network().NetworkDataplane = &dataplane
+	}
+	if flags.Changed("api-server-authorized-ip-ranges") {
+		v, _ := flags.GetString("api-server-authorized-ip-ranges")
+		// An empty, non-nil list is what clears the ranges on a PUT.
+		apiServer().AuthorizedIPRanges = to.SliceOfPtrs(splitList(v)...)
+	}
+
+	if enable, _ := flags.GetBool("enable-aad"); enable {
+		aad().Managed = to.Ptr(true)
+	}
+	if flags.Changed("aad-admin-group-object-ids") {
+		v, _ := flags.GetString("aad-admin-group-object-ids")
+		aad().AdminGroupObjectIDs = to.SliceOfPtrs(splitList(v)...)
+	}
+	if flags.Changed("aad-tenant-id") {
+		v, _ := flags.GetString("aad-tenant-id")
+		aad().TenantID = to.Ptr(v)
+	}
+	if flags.Changed("enable-azure-rbac") {
+		v, _ := flags.GetBool("enable-azure-rbac")
+		aad().EnableAzureRBAC = to.Ptr(v)
+	}
+
+	if flags.Changed("enable-oidc-issuer") {
+		v, _ := flags.GetBool("enable-oidc-issuer")
+		props.OidcIssuerProfile = &armcontainerservice.ManagedClusterOIDCIssuerProfile{Enabled: to.Ptr(v)}
+	}
+	if flags.Changed("enable-workload-identity") {
+		v, _ := flags.GetBool("enable-workload-identity")
+		if props.SecurityProfile == nil {
+			props.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{}
+		}
+		props.SecurityProfile.WorkloadIdentity = &armcontainerservice.ManagedClusterSecurityProfileWorkloadIdentity{Enabled: to.Ptr(v)}
+	}
+
+	if flags.Changed("assign-identity") {
+		v, _ := flags.GetString("assign-identity")
+		if !strings.Contains(strings.ToLower(v), "/providers/microsoft.managedidentity/userassignedidentities/") {
+			return fmt.Errorf("invalid --assign-identity %q: expected a user-assigned identity resource ID", v)
+		}
+		mc.Identity = &armcontainerservice.ManagedClusterIdentity{
+			Type: to.Ptr(armcontainerservice.ResourceIdentityTypeUserAssigned),
+			UserAssignedIdentities: map[string]*armcontainerservice.ManagedServiceIdentityUserAssignedIdentitiesValue{
+				v: {},
+			},
+		}
+	}
+
+	if flags.Changed("cluster-autoscaler-profile") {
+		pairs, _ := flags.GetStringSlice("cluster-autoscaler-profile")
+		profile, err := mergeAutoscalerProfile(props.AutoScalerProfile, pairs)
+		if err != nil {
+			return err
+		}
+		props.AutoScalerProfile = profile
+	}
+
+	if flags.Changed("tier") {
+		v, _ := flags.GetString("tier")
+		tier, err := enumflag.Parse("tier", v, armcontainerservice.PossibleManagedClusterSKUTierValues())
+		if err != nil {
+			return err
+		}
+		mc.SKU = &armcontainerservice.ManagedClusterSKU{Name: to.Ptr(armcontainerservice.ManagedClusterSKUNameBase), Tier: &tier}
+	}
+
+	if flags.Changed("tags") {
+		tags, _ := flags.GetStringToString("tags")
+		mc.Tags = make(map[string]*string, len(tags))
+		for k, v := range tags {
+			mc.Tags[k] = to.Ptr(v)
+		}
+	}
+	return nil
+}
+
+// validateProfile catches the combinations AKS would reject, after the
+// flags are applied, so update checks them against the cluster's existing
+// settings too and fails before the long-running operation starts.
+func validateProfile(mc *armcontainerservice.ManagedCluster) error {
+	props := mc.Properties
+	if props == nil {
+		return nil
+	}
+	if p := props.AADProfile; p != nil && p.EnableAzureRBAC != nil && *p.EnableAzureRBAC && (p.Managed == nil || !*p.Managed) {
+		return fmt.Errorf("--enable-azure-rbac requires Entra ID integration; add --enable-aad")
+	}
+	if s := props.SecurityProfile; s != nil && s.WorkloadIdentity != nil && s.WorkloadIdentity.Enabled != nil && *s.WorkloadIdentity.Enabled {
+		if o := props.OidcIssuerProfile; o == nil || o.Enabled == nil || !*o.Enabled {
+			return fmt.Errorf("--enable-workload-identity requires the OIDC issuer; add --enable-oidc-issuer")
+		}
+	}
+	if a := props.APIServerAccessProfile; a != nil && a.EnablePrivateCluster != nil && *a.EnablePrivateCluster && len(a.AuthorizedIPRanges) > 0 {
+		return fmt.Errorf("--api-server-authorized-ip-ranges cannot be used with a private cluster")
+	}
+	if n := props.NetworkProfile; n != nil {
+		if n.NetworkPluginMode != nil && (n.NetworkPlugin == nil || *n.NetworkPlugin != armcontainerservice.NetworkPluginAzure) {
+			return fmt.Errorf("--network-plugin-mode %s requires --network-plugin azure", *n.NetworkPluginMode)
+		}
+		if n.NetworkPolicy != nil && *n.NetworkPolicy == armcontainerservice.NetworkPolicyCilium &&
+			(n.NetworkDataplane == nil || *n.NetworkDataplane != armcontainerservice.NetworkDataplaneCilium) {
+			return fmt.Errorf("--network-policy cilium requires --network-dataplane cilium")
+		}
+	}
+	return nil
+}
+
+// mergeAutoscalerProfile sets key=value pairs on a copy of current. It goes
+// through the profile's JSON form because the API's keys are the flag's keys.
+func mergeAutoscalerProfile(current *armcontainerservice.ManagedClusterPropertiesAutoScalerProfile, pairs []string) (*armcontainerservice.ManagedClusterPropertiesAutoScalerProfile, error) {
+	fields := map[string]interface{}{}
+	if current != nil {
+		raw, err := json.Marshal(current)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+	}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		isBool, known := autoscalerProfileKeys[key]
+		if !ok || !known {
+			return nil, fmt.Errorf("invalid --cluster-autoscaler-profile %q: expected key=value with a cluster autoscaler setting as key", pair)
+		}
+		if !isBool {
+			fields[key] = value
+			continue
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --cluster-autoscaler-profile %q: %s takes true or false", pair, key)
+		}
+		fields[key] = b
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	profile := &armcontainerservice.ManagedClusterPropertiesAutoScalerProfile{}
+	if err := json.Unmarshal(raw, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// splitList splits a comma-separated flag value, dropping blanks.
+func splitList(s string) []string {
+	out := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+var dnsPrefixInvalid = regexp.MustCompile(`[^A-Za-z0-9-]`)
+
+// defaultDNSPrefix derives the DNS prefix the way Python az does, so a
+// cluster created by either CLI gets the same FQDN.
+func defaultDNSPrefix(name, resourceGroup, subscriptionID string) string {
+	namePart := truncate(dnsPrefixInvalid.ReplaceAllString(name, ""), 10)
+	if namePart == "" || !isLetter(namePart[0]) {
+		namePart = "a" + truncate(namePart, 9)
+	}
+	rgPart := truncate(dnsPrefixInvalid.ReplaceAllString(resourceGroup, ""), 16)
+	return fmt.Sprintf("%s-%s-%s", namePart, rgPart, truncate(subscriptionID, 6))
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
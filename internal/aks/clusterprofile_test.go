@@ -0,0 +1,174 @@
+package aks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+)
+
+func TestDefaultDNSPrefix(t *testing.T) {
+	tests := []struct {
+		name, rg, sub, want string
+	}{
+		{"aks1", "rg", "0123456789", "aks1-rg-012345"},
+		{"my_long.cluster-name", "resource_group.with-a-long-name", "abcdef01", "mylongclus-resourcegroupwit-abcdef"},
+		{"1cluster", "rg", "abc", "a1cluster-rg-abc"},
+	}
+	for _, tt := range tests {
+		if got := defaultDNSPrefix(tt.name, tt.rg, tt.sub); got != tt.want {
+			t.Errorf("defaultDNSPrefix(%q, %q, %q) = %q, want %q", tt.name, tt.rg, tt.sub, got, tt.want)
+		}
+	}
+}
+
+func TestMergeAutoscalerProfile(t *testing.T) {
+	current := &armcontainerservice.ManagedClusterPropertiesAutoScalerProfile{ScanInterval: to.Ptr("10s"), Expander: to.Ptr(armcontainerservice.ExpanderRandom)}
+	got, err := mergeAutoscalerProfile(current, []string{"scan-interval=30s", "daemonset-eviction-for-empty-nodes=true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got.ScanInterval != "30s" || *got.Expander != armcontainerservice.ExpanderRandom || !*got.DaemonsetEvictionForEmptyNodes {
+		t.Errorf("merged profile = %+v", got)
+	}
+	if *current.ScanInterval != "10s" {
+		t.Error("mergeAutoscalerProfile modified the current profile")
+	}
+
+	for _, bad := range []string{"scan-interval", "no-such-key=1", "ignore-daemonsets-utilization=maybe"} {
+		if _, err := mergeAutoscalerProfile(nil, []string{bad}); err == nil {
+			t.Errorf("mergeAutoscalerProfile(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestBuildCluster(t *testing.T) {
+	cmd := newCreateCmd()
+	err := cmd.Flags().Parse([]string{
+		"-n", "aks1", "-g", "rg",
+		"--network-plugin", "Azure", "--network-plugin-mode", "overlay", "--network-policy", "calico",
+		"--vnet-subnet-id", "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/v/subnets/aks",
+		"--api-server-authorized-ip-ranges", "203.0.113.0/24, 198.51.100.7/32",
+		"--enable-aad", "--enable-azure-rbac", "--aad-admin-group-object-ids", "g1,g2",
+		"--enable-oidc-issuer", "--enable-workload-identity",
+		"--enable-cluster-autoscaler", "--min-count", "1", "--max-count", "5",
+		"--cluster-autoscaler-profile", "expander=least-waste",
+		"--tier", "standard", "--tags", "env=prod",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc, err := buildCluster(cmd, "aks1", "rg", "0123456789", "westeurope")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	props := mc.Properties
+	if *mc.Location != "westeurope" || *props.DNSPrefix != "aks1-rg-012345" {
+		t.Errorf("location/dnsPrefix = %s/%s", *mc.Location, *props.DNSPrefix)
+	}
+	if *mc.Identity.Type != armcontainerservice.ResourceIdentityTypeSystemAssigned {
+		t.Errorf("identity = %s, want SystemAssigned", *mc.Identity.Type)
+	}
+	pool := props.AgentPoolProfiles[0]
+	if *pool.Name != "nodepool1" || *pool.Mode != armcontainerservice.AgentPoolModeSystem || !*pool.EnableAutoScaling || *pool.MaxCount != 5 || pool.VnetSubnetID == nil {
+		t.Errorf("system pool = %+v", pool)
+	}
+	if *props.NetworkProfile.NetworkPlugin != armcontainerservice.NetworkPluginAzure || *props.NetworkProfile.NetworkPolicy != armcontainerservice.NetworkPolicyCalico {
+		t.Errorf("network profile = %+v", props.NetworkProfile)
+	}
+	if ranges := props.APIServerAccessProfile.AuthorizedIPRanges; len(ranges) != 2 || *ranges[1] != "198.51.100.7/32" {
+		t.Errorf("authorized IP ranges = %v", ranges)
+	}
+	if !*props.AADProfile.Managed || !*props.AADProfile.EnableAzureRBAC || len(props.AADProfile.AdminGroupObjectIDs) != 2 {
+		t.Errorf("aad profile = %+v", props.AADProfile)
+	}
+	if !*props.OidcIssuerProfile.Enabled || !*props.SecurityProfile.WorkloadIdentity.Enabled {
+		t.Error("OIDC issuer and workload identity not enabled")
+	}
+	if *props.AutoScalerProfile.Expander != armcontainerservice.ExpanderLeastWaste {
+		t.Errorf("expander = %s", *props.AutoScalerProfile.Expander)
+	}
+	if *mc.SKU.Tier != armcontainerservice.ManagedClusterSKUTierStandard || *mc.Tags["env"] != "prod" {
+		t.Errorf("sku/tags = %+v/%v", mc.SKU, mc.Tags)
+	}
+}
+
+func TestBuildClusterRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"azure rbac without aad", []string{"--enable-azure-rbac"}, "--enable-aad"},
+		{"workload identity without oidc", []string{"--enable-workload-identity"}, "--enable-oidc-issuer"},
+		{"private with ip ranges", []string{"--enable-private-cluster", "--api-server-authorized-ip-ranges", "1.2.3.4/32"}, "private cluster"},
+		{"overlay with kubenet", []string{"--network-plugin", "kubenet", "--network-plugin-mode", "overlay"}, "--network-plugin azure"},
+		{"cilium without dataplane", []string{"--network-plugin", "azure", "--network-policy", "cilium"}, "--network-dataplane cilium"},
+		{"counts without autoscaler", []string{"--min-count", "1"}, "--enable-cluster-autoscaler"},
+		{"bad identity", []string{"--assign-identity", "not-an-id"}, "user-assigned identity"},
+		{"bad plugin", []string{"--network-plugin", "flannel"}, "allowed values"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newCreateCmd()
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			_, err := buildCluster(cmd, "aks1", "rg", "sub", "westeurope")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("buildCluster(%v) error = %v, want one mentioning %q", tt.args, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyUpdateFlags(t *testing.T) {
+	existing := func() armcontainerservice.ManagedCluster {
+		return armcontainerservice.ManagedCluster{
+			Tags: map[string]*string{"env": to.Ptr("dev")},
+			Properties: &armcontainerservice.ManagedClusterProperties{
+				AADProfile:        &armcontainerservice.ManagedClusterAADProfile{Managed: to.Ptr(true), EnableAzureRBAC: to.Ptr(true)},
+				OidcIssuerProfile: &armcontainerservice.ManagedClusterOIDCIssuerProfile{Enabled: to.Ptr(true)},
+				APIServerAccessProfile: &armcontainerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: []*string{to.Ptr("1.2.3.4/32")},
+				},
+			},
+		}
+	}
+
+	cmd := newUpdateCmd()
+	if err := cmd.Flags().Parse([]string{"--disable-azure-rbac", "--enable-workload-identity", "--api-server-authorized-ip-ranges", ""}); err != nil {
+		t.Fatal(err)
+	}
+	mc := existing()
+	if err := applyUpdateFlags(cmd, &mc); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateProfile(&mc); err != nil {
+		t.Fatalf("validateProfile: %v (workload identity should see the existing OIDC issuer)", err)
+	}
+	props := mc.Properties
+	if *props.AADProfile.EnableAzureRBAC || !*props.AADProfile.Managed {
+		t.Errorf("aad profile = %+v, want Azure RBAC off and still managed", props.AADProfile)
+	}
+	if ranges := props.APIServerAccessProfile.AuthorizedIPRanges; ranges == nil || len(ranges) != 0 {
+		t.Errorf("authorized IP ranges = %v, want an empty list", ranges)
+	}
+	if *mc.Tags["env"] != "dev" {
+		t.Error("tags changed without --tags")
+	}
+
+	cmd = newUpdateCmd()
+	if err := cmd.Flags().Parse([]string{"--assign-identity", "/subscriptions/s/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1"}); err != nil {
+		t.Fatal(err)
+	}
+	mc = existing()
+	if err := applyUpdateFlags(cmd, &mc); err != nil {
+		t.Fatal(err)
+	}
+	if *mc.Identity.Type != armcontainerservice.ResourceIdentityTypeUserAssigned || len(mc.Identity.UserAssignedIdentities) != 1 {
+		t.Errorf("identity = %+v", mc.Identity)
+	}
+}
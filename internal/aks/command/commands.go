@@ -15,17 +15,30 @@ func NewCommandCommand() *cobra.Command {
 	invokeCmd := &cobra.Command{
 		Use:   "invoke",
 		Short: "Run a shell command in the cluster's context",
+		Long: `Run a shell command in a pod with kubectl and helm, authenticated to the
+cluster, through the managed run-command API. This reaches private clusters
+without network access to the API server.
+
+Files attached with --file are placed in the command's working directory.
+The command's output is printed as it arrives, and az exits with the
+command's exit code.`,
+		Example: `  az aks command invoke -g rg -n aks1 --command "kubectl get pods -A"
+  az aks command invoke -g rg -n aks1 --command "kubectl apply -f deployment.yaml" --file deployment.yaml
+  az aks command invoke -g rg -n aks1 --command "helm install app ./chart -f values.yaml" --file .`,
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name, _ := cmd.Flags().GetString("name")
 			rg, _ := cmd.Flags().GetString("resource-group")
 			command, _ := cmd.Flags().GetString("command")
+			files, _ := cmd.Flags().GetStringArray("file")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Invoke(context.Background(), cmd, name, rg, command, noWait)
+			return Invoke(context.Background(), cmd, name, rg, command, files, noWait)
 		},
 	}
 	invokeCmd.Flags().StringP("name", "n", "", "AKS cluster name")
 	invokeCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
 	invokeCmd.Flags().String("command", "", "Command to run")
+	invokeCmd.Flags().StringArrayP("file", "f", nil, "File to attach; repeatable. Use '.' to attach the current directory")
 	invokeCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
 	invokeCmd.MarkFlagRequired("name")
 	invokeCmd.MarkFlagRequired("resource-group")
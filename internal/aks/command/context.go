@@ -0,0 +1,96 @@
+package command
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// buildContext zips the files attached with --file into the base64 payload
+// the run-command API unpacks into the command's working directory. As in
+// the Python CLI, "." attaches the current directory with its layout kept,
+// while individual files are flattened to their base names.
+func buildContext(files []string) (string, error) {
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	entries := map[string]string{} // zip entry -> path on disk
+	if len(files) == 1 && files[0] == "." {
+		err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			entries[filepath.ToSlash(path)] = path
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to read the current directory: %w", err)
+		}
+	} else {
+		for _, f := range files {
+			if f == "." {
+				return "", fmt.Errorf("--file . attaches the whole current directory and cannot be combined with other files")
+			}
+			info, err := os.Stat(f)
+			if err != nil || !info.Mode().IsRegular() {
+				return "", fmt.Errorf("%s is not a file or cannot be read", f)
+			}
+			name := filepath.Base(f)
+			if prev, ok := entries[name]; ok && prev != f {
+				return "", fmt.Errorf("%s and %s would both be attached as %s", prev, f, name)
+			}
+			entries[name] = f
+		}
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		if err := addToZip(zw, name, entries[name]); err != nil {
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("failed to zip attached files: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func addToZip(zw *zip.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return fmt.Errorf("failed to zip %s: %w", path, err)
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("failed to zip %s: %w", path, err)
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("failed to zip %s: %w", path, err)
+	}
+	return nil
+}
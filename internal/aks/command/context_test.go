@@ -0,0 +1,102 @@
+package command
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// unzip decodes a context payload into entry name -> contents.
+func unzip(t *testing.T, payload string) map[string]string {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	return files
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildContextFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "manifests", "deploy.yaml"), "kind: Deployment")
+	writeFile(t, filepath.Join(dir, "values.yaml"), "replicas: 2")
+
+	payload, err := buildContext([]string{filepath.Join(dir, "manifests", "deploy.yaml"), filepath.Join(dir, "values.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := unzip(t, payload)
+	if len(got) != 2 || got["deploy.yaml"] != "kind: Deployment" || got["values.yaml"] != "replicas: 2" {
+		t.Errorf("context = %v, want both files flattened to their base names", got)
+	}
+
+	if payload, err := buildContext(nil); err != nil || payload != "" {
+		t.Errorf("buildContext(nil) = %q, %v; want no context", payload, err)
+	}
+}
+
+func TestBuildContextCurrentDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "chart", "Chart.yaml"), "name: app")
+	writeFile(t, filepath.Join(dir, "run.sh"), "helm install app ./chart")
+	t.Chdir(dir)
+
+	payload, err := buildContext([]string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := unzip(t, payload)
+	if len(got) != 2 || got["chart/Chart.yaml"] != "name: app" || got["run.sh"] == "" {
+		t.Errorf("context = %v, want the directory with its layout kept", got)
+	}
+}
+
+func TestBuildContextRejects(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a", "values.yaml"), "a")
+	writeFile(t, filepath.Join(dir, "b", "values.yaml"), "b")
+
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{[]string{".", filepath.Join(dir, "a", "values.yaml")}, "cannot be combined"},
+		{[]string{filepath.Join(dir, "missing.yaml")}, "not a file"},
+		{[]string{dir}, "not a file"},
+		{[]string{filepath.Join(dir, "a", "values.yaml"), filepath.Join(dir, "b", "values.yaml")}, "both be attached"},
+	}
+	for _, tt := range tests {
+		if _, err := buildContext(tt.files); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("buildContext(%v) error = %v, want one mentioning %q", tt.files, err, tt.want)
+		}
+	}
+}
@@ -2,8 +2,13 @@ package command
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
@@ -12,7 +17,30 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func Invoke(ctx context.Context, cmd *cobra.Command, name, resourceGroup, command string, noWait bool) error {
+// pollInterval is how often a running command's result is fetched for new
+// output.
+const pollInterval = 2 * time.Second
+
+// ExitError reports a non-zero exit code from the command run in the
+// cluster, so az exits with the same code.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command exited with code %d", e.Code)
+}
+
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+func Invoke(ctx context.Context, cmd *cobra.Command, name, resourceGroup, command string, files []string, noWait bool) error {
+	attachments, err := buildContext(files)
+	if err != nil {
+		return err
+	}
+
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
@@ -23,7 +51,7 @@ func Invoke(ctx context.Context, cmd *cobra.Command, name, resourceGroup, comman
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create managed clusters client: %w", err)
 	}
@@ -31,6 +59,9 @@ func Invoke(ctx context.Context, cmd *cobra.Command, name, resourceGroup, comman
 	req := armcontainerservice.RunCommandRequest{
 		Command: to.Ptr(command),
 	}
+	if attachments != "" {
+		req.Context = to.Ptr(attachments)
+	}
 
 	fmt.Printf("Running command against cluster '%s'...\n", name)
 	poller, err := client.BeginRunCommand(ctx, resourceGroup, name, req, nil)
@@ -42,10 +73,118 @@ func Invoke(ctx context.Context, cmd *cobra.Command, name, resourceGroup, comman
 		return output.PrintJSON(cmd, map[string]string{"status": "run command started"})
 	}
 
-	resp, err := poller.PollUntilDone(ctx, nil)
+	result, err := follow(ctx, poller, cmd.OutOrStdout())
 	if err != nil {
 		return fmt.Errorf("run command failed: %w", err)
 	}
+	return finish(result, cmd.ErrOrStderr())
+}
+
+// follow polls the command until it finishes, writing its logs to w as
+// they grow rather than all at once at the end.
+func follow(ctx context.Context, poller *runtime.Poller[armcontainerservice.ManagedClustersClientRunCommandResponse], w io.Writer) (armcontainerservice.RunCommandResult, error) {
+	var logs logWriter
+	for {
+		resp, err := poller.Poll(ctx)
+		if err != nil {
+			return armcontainerservice.RunCommandResult{}, err
+		}
+		// The result is read with Payload, which caches the body for
+		// poller.Result to read again.
+		if body, err := runtime.Payload(resp); err == nil && len(body) > 0 {
+			var partial armcontainerservice.RunCommandResult
+			if json.Unmarshal(body, &partial) == nil && partial.Properties != nil {
+				logs.write(w, azure.GetStringValue(partial.Properties.Logs))
+			}
+		}
+		if poller.Done() {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return armcontainerservice.RunCommandResult{}, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+
+	resp, err := poller.Result(ctx)
+	if err != nil {
+		return armcontainerservice.RunCommandResult{}, err
+	}
+	if resp.Properties != nil {
+		logs.write(w, azure.GetStringValue(resp.Properties.Logs))
+	}
+	return resp.RunCommandResult, nil
+}
+
+// finish reports how the command ended, as the Python CLI does, and turns a
+// failure or non-zero exit code into an error.
+func finish(result armcontainerservice.RunCommandResult, w io.Writer) error {
+	props := result.Properties
+	if props == nil {
+		return fmt.Errorf("run command returned no result")
+	}
+	if strings.EqualFold(azure.GetStringValue(props.ProvisioningState), "Failed") {
+		return fmt.Errorf("run command failed: %s", azure.GetStringValue(props.Reason))
+	}
 
-	return output.PrintJSON(cmd, resp.RunCommandResult)
+	code := 0
+	if props.ExitCode != nil {
+		code = int(*props.ExitCode)
+	}
+	fmt.Fprintf(w, "command started at %s, finished at %s with exitcode=%d\n", formatTime(props.StartedAt), formatTime(props.FinishedAt), code)
+	if code != 0 {
+		return &ExitError{Code: code}
+	}
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "unknown"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// logWriter writes only the part of the logs not yet written. Each poll
+// returns the whole log so far; if it doesn't extend what was written, the
+// service has replaced it and it is written again in full.
+type logWriter struct {
+	written string
+}
+
+func (l *logWriter) write(w io.Writer, logs string) {
+	if logs == l.written {
+		return
+	}
+	if strings.HasPrefix(logs, l.written) {
+		io.WriteString(w, logs[len(l.written):])
+	} else {
+		io.WriteString(w, logs)
+	}
+	l.written = logs
+}
+
+// Run runs command in the cluster and waits for its result, for callers
+// that need the output rather than printing it.
+func Run(ctx context.Context, subscriptionID, resourceGroup, name, command string) (armcontainerservice.RunCommandResult, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return armcontainerservice.RunCommandResult{}, err
+	}
+
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return armcontainerservice.RunCommandResult{}, fmt.Errorf("failed to create managed clusters client: %w", err)
+	}
+
+	poller, err := client.BeginRunCommand(ctx, resourceGroup, name, armcontainerservice.RunCommandRequest{Command: to.Ptr(command)}, nil)
+	if err != nil {
+		return armcontainerservice.RunCommandResult{}, fmt.Errorf("failed to begin run command: %w", err)
+	}
+	resp, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return armcontainerservice.RunCommandResult{}, fmt.Errorf("run command failed: %w", err)
+	}
+	return resp.RunCommandResult, nil
 }
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+)
+
+func TestLogWriter(t *testing.T) {
+	var out strings.Builder
+	var logs logWriter
+	for _, l := range []string{"", "pod/a created\n", "pod/a created\n", "pod/a created\npod/b created\n"} {
+		logs.write(&out, l)
+	}
+	if got := out.String(); got != "pod/a created\npod/b created\n" {
+		t.Errorf("streamed %q, want each line once", got)
+	}
+
+	logs.write(&out, "replaced\n")
+	if !strings.HasSuffix(out.String(), "pod/b created\nreplaced\n") {
+		t.Errorf("streamed %q, want a replaced log written in full", out.String())
+	}
+}
+
+func TestFinish(t *testing.T) {
+	result := func(state string, code int32) armcontainerservice.RunCommandResult {
+		return armcontainerservice.RunCommandResult{Properties: &armcontainerservice.CommandResultProperties{
+			ProvisioningState: to.Ptr(state),
+			ExitCode:          to.Ptr(code),
+			Reason:            to.Ptr("pod could not be scheduled"),
+		}}
+	}
+
+	var out strings.Builder
+	if err := finish(result("Succeeded", 0), &out); err != nil {
+		t.Errorf("finish(exit 0) = %v", err)
+	}
+	if !strings.Contains(out.String(), "exitcode=0") {
+		t.Errorf("summary = %q", out.String())
+	}
+
+	var exitErr *ExitError
+	if err := finish(result("Succeeded", 3), &out); !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("finish(exit 3) = %v, want an ExitError with code 3", err)
+	}
+
+	if err := finish(result("Failed", 0), &out); err == nil || !strings.Contains(err.Error(), "could not be scheduled") {
+		t.Errorf("finish(Failed) = %v, want the reason", err)
+	}
+}
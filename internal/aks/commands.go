@@ -14,25 +14,27 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/aks/snapshot"
 	"github.com/cdobbyn/azure-go-cli/internal/aks/trustedaccess"
 	"github.com/cdobbyn/azure-go-cli/internal/network/bastion"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
-func newClusterActionCmd(use, short string, action func(context.Context, string, string, bool) error) *cobra.Command {
+const clusterResourceType = "Microsoft.ContainerService/managedClusters"
+
+func newClusterActionCmd(use, short string, action func(context.Context, bulk.Target, bool) error) *cobra.Command {
 	c := &cobra.Command{
 		Use:   use,
 		Short: short,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return action(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, clusterResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, action(ctx, t, noWait)
+			})
 		},
 	}
 	c.Flags().StringP("name", "n", "", "AKS cluster name")
 	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(c)
 	c.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	c.MarkFlagRequired("name")
-	c.MarkFlagRequired("resource-group")
 	return c
 }
 
@@ -176,15 +178,12 @@ Dependencies: kubectl (install with: sudo az aks install-cli)`,
 		Use:   "show",
 		Short: "Show details of an AKS cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, clusterName, resourceGroup)
+			return bulk.Run(cmd, clusterResourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "AKS cluster name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	installCliCmd := &cobra.Command{
 		Use:   "install-cli",
@@ -202,17 +201,16 @@ Run with: sudo az aks install-cli`,
 		Use:   "delete",
 		Short: "Delete an AKS cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, clusterResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "AKS cluster name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	startCmd := newClusterActionCmd(
 		"start",
@@ -304,6 +302,8 @@ Run with: sudo az aks install-cli`,
 		bastionCmd,
 		listCmd,
 		showCmd,
+		newCreateCmd(),
+		newUpdateCmd(),
 		installCliCmd,
 		deleteCmd,
 		startCmd,
@@ -312,6 +312,7 @@ Run with: sudo az aks install-cli`,
 		reconcileCmd,
 		getVersionsCmd,
 		getUpgradesCmd,
+		newUpgradeCmd(),
 		rotateCertsCmd,
 		waitCmd,
 		newGetTokenCmd(),
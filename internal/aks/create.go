@@ -0,0 +1,202 @@
+package aks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/enumflag"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newCreateCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "create",
+		Short: "Create a managed Kubernetes cluster",
+		Long: `Create a managed Kubernetes cluster with one system node pool.
+
+The cluster gets a system-assigned identity unless --assign-identity names a
+user-assigned one. The location defaults to the resource group's.`,
+		Example: `  az aks create -g rg -n aks1 --network-plugin azure --network-plugin-mode overlay \
+    --enable-aad --enable-azure-rbac --enable-oidc-issuer --enable-workload-identity --tier standard`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			noWait, _ := cmd.Flags().GetBool("no-wait")
+			return Create(context.Background(), cmd, name, resourceGroup, noWait)
+		},
+	}
+	c.Flags().StringP("name", "n", "", "AKS cluster name")
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	c.Flags().StringP("location", "l", "", "Azure region (default: the resource group's location)")
+	c.Flags().StringP("kubernetes-version", "k", "", "Kubernetes version (default: the region's default)")
+	c.Flags().String("dns-name-prefix", "", "DNS name prefix for the API server FQDN (default: derived from the names)")
+	c.Flags().String("nodepool-name", "nodepool1", "Name of the system node pool")
+	c.Flags().Int32P("node-count", "c", 3, "Number of nodes in the system node pool")
+	c.Flags().StringP("node-vm-size", "s", "Standard_DS2_v2", "VM size of the system node pool")
+	c.Flags().Bool("enable-cluster-autoscaler", false, "Enable the cluster autoscaler on the system node pool")
+	c.Flags().Int32("min-count", 0, "Minimum node count when the autoscaler is enabled")
+	c.Flags().Int32("max-count", 0, "Maximum node count when the autoscaler is enabled")
+	c.Flags().String("network-plugin", "", "Network plugin: azure, kubenet or none")
+	c.Flags().String("pod-cidr", "", "CIDR for pod IPs with kubenet or Azure CNI overlay")
+	c.Flags().String("service-cidr", "", "CIDR for service cluster IPs")
+	c.Flags().String("dns-service-ip", "", "IP of the cluster DNS service, within --service-cidr")
+	c.Flags().String("vnet-subnet-id", "", "Resource ID of an existing subnet for the system node pool")
+	c.Flags().Bool("enable-private-cluster", false, "Make the API server reachable only from the virtual network")
+	c.Flags().String("private-dns-zone", "", "Private DNS zone for a private cluster: system, none or a zone resource ID")
+	addProfileFlags(c)
+	c.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+	c.MarkFlagRequired("name")
+	c.MarkFlagRequired("resource-group")
+	return c
+}
+
+func Create(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, noWait bool) error {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	location, _ := cmd.Flags().GetString("location")
+	if location == "" {
+		groups, err := armresources.NewResourceGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
+		if err != nil {
+			return fmt.Errorf("failed to create resource groups client: %w", err)
+		}
+		group, err := groups.Get(ctx, resourceGroup, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get resource group: %w", err)
+		}
+		location = *group.Location
+	}
+
+	cluster, err := buildCluster(cmd, name, resourceGroup, subscriptionID, location)
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AKS client: %w", err)
+	}
+
+	if _, err := client.Get(ctx, resourceGroup, name, nil); err == nil {
+		return fmt.Errorf("AKS cluster '%s' already exists in resource group '%s'; use 'az aks update' to change it", name, resourceGroup)
+	}
+
+	fmt.Printf("Creating AKS cluster '%s'...\n", name)
+	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, name, cluster, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin create: %w", err)
+	}
+
+	if noWait {
+		return output.PrintJSON(cmd, map[string]string{"status": "cluster creation started"})
+	}
+
+	result, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create AKS cluster: %w", err)
+	}
+
+	return output.PrintJSON(cmd, result.ManagedCluster)
+}
+
+// buildCluster assembles the cluster create sends from its flags.
+func buildCluster(cmd *cobra.Command, name, resourceGroup, subscriptionID, location string) (armcontainerservice.ManagedCluster, error) {
+	flags := cmd.Flags()
+	dnsPrefix, _ := flags.GetString("dns-name-prefix")
+	if dnsPrefix == "" {
+		dnsPrefix = defaultDNSPrefix(name, resourceGroup, subscriptionID)
+	}
+	poolName, _ := flags.GetString("nodepool-name")
+	nodeCount, _ := flags.GetInt32("node-count")
+	vmSize, _ := flags.GetString("node-vm-size")
+
+	pool := &armcontainerservice.ManagedClusterAgentPoolProfile{
+		Name:   to.Ptr(poolName),
+		Count:  to.Ptr(nodeCount),
+		VMSize: to.Ptr(vmSize),
+		Mode:   to.Ptr(armcontainerservice.AgentPoolModeSystem),
+		OSType: to.Ptr(armcontainerservice.OSTypeLinux),
+		Type:   to.Ptr(armcontainerservice.AgentPoolTypeVirtualMachineScaleSets),
+	}
+	if autoscale, _ := flags.GetBool("enable-cluster-autoscaler"); autoscale {
+		minCount, _ := flags.GetInt32("min-count")
+		maxCount, _ := flags.GetInt32("max-count")
+		if minCount < 1 || maxCount < minCount {
+			return armcontainerservice.ManagedCluster{}, fmt.Errorf("--enable-cluster-autoscaler needs --min-count >= 1 and --max-count >= --min-count")
+		}
+		pool.EnableAutoScaling = to.Ptr(true)
+		pool.MinCount = to.Ptr(minCount)
+		pool.MaxCount = to.Ptr(maxCount)
+	} else if flags.Changed("min-count") || flags.Changed("max-count") {
+		return armcontainerservice.ManagedCluster{}, fmt.Errorf("--min-count and --max-count require --enable-cluster-autoscaler")
+	}
+	if subnet, _ := flags.GetString("vnet-subnet-id"); subnet != "" {
+		pool.VnetSubnetID = to.Ptr(subnet)
+	}
+
+	cluster := armcontainerservice.ManagedCluster{
+		Location: to.Ptr(location),
+		Identity: &armcontainerservice.ManagedClusterIdentity{
+			Type: to.Ptr(armcontainerservice.ResourceIdentityTypeSystemAssigned),
+		},
+		Properties: &armcontainerservice.ManagedClusterProperties{
+			DNSPrefix:         to.Ptr(dnsPrefix),
+			EnableRBAC:        to.Ptr(true),
+			AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{pool},
+		},
+	}
+	props := cluster.Properties
+	if version, _ := flags.GetString("kubernetes-version"); version != "" {
+		props.KubernetesVersion = to.Ptr(version)
+	}
+
+	network := &armcontainerservice.NetworkProfile{}
+	if v, _ := flags.GetString("network-plugin"); v != "" {
+		plugin, err := enumflag.Parse("network-plugin", v, armcontainerservice.PossibleNetworkPluginValues())
+		if err != nil {
+			return armcontainerservice.ManagedCluster{}, err
+		}
+		network.NetworkPlugin = &plugin
+	}
+	if v, _ := flags.GetString("pod-cidr"); v != "" {
+		network.PodCidr = to.Ptr(v)
+	}
+	if v, _ := flags.GetString("service-cidr"); v != "" {
+		network.ServiceCidr = to.Ptr(v)
+	}
+	if v, _ := flags.GetString("dns-service-ip"); v != "" {
+		network.DNSServiceIP = to.Ptr(v)
+	}
+	props.NetworkProfile = network
+
+	if private, _ := flags.GetBool("enable-private-cluster"); private {
+		props.APIServerAccessProfile = &armcontainerservice.ManagedClusterAPIServerAccessProfile{EnablePrivateCluster: to.Ptr(true)}
+		if zone, _ := flags.GetString("private-dns-zone"); zone != "" {
+			props.APIServerAccessProfile.PrivateDNSZone = to.Ptr(zone)
+		}
+	} else if flags.Changed("private-dns-zone") {
+		return armcontainerservice.ManagedCluster{}, fmt.Errorf("--private-dns-zone requires --enable-private-cluster")
+	}
+
+	if err := applyProfileFlags(cmd, &cluster); err != nil {
+		return armcontainerservice.ManagedCluster{}, err
+	}
+	if err := validateProfile(&cluster); err != nil {
+		return armcontainerservice.ManagedCluster{}, err
+	}
+	return cluster, nil
+}
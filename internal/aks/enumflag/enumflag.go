@@ -0,0 +1,21 @@
+// Package enumflag parses flags whose values come from an SDK enum, shared
+// by the aks command packages.
+package enumflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse matches value case-insensitively against an enum's values,
+// returning the canonical spelling. flag names the flag in the error.
+func Parse[T ~string](flag, value string, possible []T) (T, error) {
+	names := make([]string, 0, len(possible))
+	for _, p := range possible {
+		if strings.EqualFold(string(p), value) {
+			return p, nil
+		}
+		names = append(names, string(p))
+	}
+	return "", fmt.Errorf("invalid --%s %q: allowed values are %s", flag, value, strings.Join(names, ", "))
+}
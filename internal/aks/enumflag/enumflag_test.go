@@ -0,0 +1,18 @@
+package enumflag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse("tier", "STANDARD", armcontainerservice.PossibleManagedClusterSKUTierValues())
+	if err != nil || got != armcontainerservice.ManagedClusterSKUTierStandard {
+		t.Fatalf("Parse(STANDARD) = %q, %v", got, err)
+	}
+	if _, err := Parse("tier", "gold", armcontainerservice.PossibleManagedClusterSKUTierValues()); err == nil || !strings.Contains(err.Error(), "Standard") {
+		t.Fatalf("Parse(gold) error = %v, want one listing the allowed values", err)
+	}
+}
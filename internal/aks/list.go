@@ -22,12 +22,19 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
 
-	var clusters []map[string]interface{}
+	stream := output.NewStream(cmd)
+	writePage := func(page []*armcontainerservice.ManagedCluster) error {
+		clusters := make([]map[string]interface{}, 0, len(page))
+		for _, cluster := range page {
+			clusters = append(clusters, formatCluster(cluster))
+		}
+		return output.StreamPage(stream, clusters)
+	}
 
 	if resourceGroup != "" {
 		// List clusters in specific resource group
@@ -35,11 +42,10 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list AKS clusters: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list AKS clusters: %w", err))
 			}
-
-			for _, cluster := range page.Value {
-				clusters = append(clusters, formatCluster(cluster))
+			if err := writePage(page.Value); err != nil {
+				return err
 			}
 		}
 	} else {
@@ -48,16 +54,15 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list AKS clusters: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list AKS clusters: %w", err))
 			}
-
-			for _, cluster := range page.Value {
-				clusters = append(clusters, formatCluster(cluster))
+			if err := writePage(page.Value); err != nil {
+				return err
 			}
 		}
 	}
 
-	return output.PrintJSON(cmd, clusters)
+	return stream.Close()
 }
 
 func formatCluster(cluster *armcontainerservice.ManagedCluster) map[string]interface{} {
@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, clusterName, nodepoolName, re
 		return err
 	}
 
-	client, err := armcontainerservice.NewMachinesClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create machines client: %w", err)
 	}
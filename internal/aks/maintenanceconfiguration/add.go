@@ -0,0 +1,53 @@
+package maintenanceconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func Add(ctx context.Context, cmd *cobra.Command, clusterName, configName, resourceGroup string) error {
+	name, err := checkName(configName)
+	if err != nil {
+		return err
+	}
+
+	props := &armcontainerservice.MaintenanceConfigurationProperties{}
+	if err := applyScheduleFlags(cmd, props); err != nil {
+		return err
+	}
+	if err := validate(name, props); err != nil {
+		return err
+	}
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewMaintenanceConfigurationsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance configurations client: %w", err)
+	}
+
+	if _, err := client.Get(ctx, resourceGroup, clusterName, name, nil); err == nil {
+		return fmt.Errorf("maintenance configuration '%s' already exists in cluster '%s'; use update to change it", name, clusterName)
+	}
+
+	resp, err := client.CreateOrUpdate(ctx, resourceGroup, clusterName, name, armcontainerservice.MaintenanceConfiguration{Properties: props}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance configuration: %w", err)
+	}
+
+	return output.PrintJSON(cmd, resp.MaintenanceConfiguration)
+}
@@ -2,6 +2,8 @@ package maintenanceconfiguration
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -44,6 +46,126 @@ func NewMaintenanceConfigurationCommand() *cobra.Command {
 	showCmd.MarkFlagRequired("name")
 	showCmd.MarkFlagRequired("resource-group")
 
-	cmd.AddCommand(listCmd, showCmd)
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a maintenance configuration to an AKS cluster",
+		Long: `Add a maintenance configuration to an AKS cluster.
+
+The default configuration allows planned maintenance in weekly hour slots,
+set with --weekday and --start-hour. aksManagedAutoUpgradeSchedule and
+aksManagedNodeOSUpgradeSchedule schedule cluster and node OS auto-upgrades
+in a maintenance window, set with --schedule-type and its flags.
+
+Dates maintenance must not run on go in --config-file, in the API's shape:
+notAllowedDates under maintenanceWindow, or notAllowedTime for default.
+Use preview to check a schedule's windows before adding it.`,
+		Example: `  az aks maintenanceconfiguration add -g rg --cluster-name aks1 -n default --weekday Monday --start-hour 1
+  az aks maintenanceconfiguration add -g rg --cluster-name aks1 -n aksManagedAutoUpgradeSchedule \
+    --schedule-type Weekly --day-of-week Saturday --interval-weeks 1 \
+    --duration 6 --start-time 22:00 --utc-offset +01:00 --config-file freeze.json
+  az aks maintenanceconfiguration add -g rg --cluster-name aks1 -n aksManagedNodeOSUpgradeSchedule \
+    --schedule-type RelativeMonthly --week-index First --day-of-week Sunday --interval-months 1 \
+    --duration 4 --start-time 02:00 --start-date 2026-01-01`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, _ := cmd.Flags().GetString("cluster-name")
+			configName, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			return Add(context.Background(), cmd, clusterName, configName, resourceGroup)
+		},
+	}
+	addCmd.Flags().String("cluster-name", "", "AKS cluster name")
+	addCmd.Flags().StringP("name", "n", "", "Maintenance configuration name: default, aksManagedAutoUpgradeSchedule or aksManagedNodeOSUpgradeSchedule")
+	addCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addScheduleFlags(addCmd)
+	addCmd.MarkFlagRequired("cluster-name")
+	addCmd.MarkFlagRequired("name")
+	addCmd.MarkFlagRequired("resource-group")
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update a maintenance configuration of an AKS cluster",
+		Long: `Update a maintenance configuration of an AKS cluster. Only the settings
+whose flags are given change; --schedule-type replaces the whole schedule.`,
+		Example: `  az aks maintenanceconfiguration update -g rg --cluster-name aks1 -n aksManagedAutoUpgradeSchedule --start-time 23:00
+  az aks maintenanceconfiguration update -g rg --cluster-name aks1 -n aksManagedAutoUpgradeSchedule --config-file freeze.json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, _ := cmd.Flags().GetString("cluster-name")
+			configName, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			return Update(context.Background(), cmd, clusterName, configName, resourceGroup)
+		},
+	}
+	updateCmd.Flags().String("cluster-name", "", "AKS cluster name")
+	updateCmd.Flags().StringP("name", "n", "", "Maintenance configuration name")
+	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addScheduleFlags(updateCmd)
+	updateCmd.MarkFlagRequired("cluster-name")
+	updateCmd.MarkFlagRequired("name")
+	updateCmd.MarkFlagRequired("resource-group")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a maintenance configuration from an AKS cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, _ := cmd.Flags().GetString("cluster-name")
+			configName, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			return Delete(context.Background(), clusterName, configName, resourceGroup)
+		},
+	}
+	deleteCmd.Flags().String("cluster-name", "", "AKS cluster name")
+	deleteCmd.Flags().StringP("name", "n", "", "Maintenance configuration name")
+	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	deleteCmd.MarkFlagRequired("cluster-name")
+	deleteCmd.MarkFlagRequired("name")
+	deleteCmd.MarkFlagRequired("resource-group")
+
+	previewCmd := &cobra.Command{
+		Use:   "preview",
+		Short: "List the next windows a maintenance schedule allows, without applying it",
+		Long: `List the next maintenance windows of a schedule, worked out locally from
+the same flags add and update take. Windows that overlap a not-allowed date
+range are left out.
+
+With --cluster-name and --resource-group, the flags change the cluster's
+current configuration, previewing what update would apply.`,
+		Example: `  az aks maintenanceconfiguration preview -n aksManagedAutoUpgradeSchedule \
+    --schedule-type RelativeMonthly --week-index Last --day-of-week Friday --interval-months 1 \
+    --duration 8 --start-time 20:00 --utc-offset -05:00 --count 6
+  az aks maintenanceconfiguration preview -g rg --cluster-name aks1 -n aksManagedAutoUpgradeSchedule`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, _ := cmd.Flags().GetString("cluster-name")
+			configName, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			count, _ := cmd.Flags().GetInt("count")
+			if count < 1 {
+				return fmt.Errorf("--count must be at least 1")
+			}
+			from := time.Now()
+			if v, _ := cmd.Flags().GetString("from"); v != "" {
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					if t, err = time.Parse(dateLayout, v); err != nil {
+						return fmt.Errorf("invalid --from %q: expected YYYY-MM-DD or an RFC 3339 time", v)
+					}
+				}
+				from = t
+			}
+			return Preview(context.Background(), cmd, clusterName, configName, resourceGroup, count, from)
+		},
+	}
+	previewCmd.Flags().String("cluster-name", "", "AKS cluster whose configuration to start from")
+	previewCmd.Flags().StringP("name", "n", "", "Maintenance configuration name")
+	previewCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	previewCmd.Flags().Int("count", 5, "Number of windows to list")
+	previewCmd.Flags().String("from", "", "List windows starting at or after this UTC date or RFC 3339 time (default now)")
+	addScheduleFlags(previewCmd)
+	previewCmd.MarkFlagRequired("name")
+	previewCmd.MarkFlagsRequiredTogether("cluster-name", "resource-group")
+
+	cmd.AddCommand(listCmd, showCmd, addCmd, updateCmd, deleteCmd, previewCmd)
 	return cmd
 }
@@ -0,0 +1,39 @@
+package maintenanceconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+func Delete(ctx context.Context, clusterName, configName, resourceGroup string) error {
+	name, err := checkName(configName)
+	if err != nil {
+		return err
+	}
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewMaintenanceConfigurationsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance configurations client: %w", err)
+	}
+
+	if _, err := client.Delete(ctx, resourceGroup, clusterName, name, nil); err != nil {
+		return fmt.Errorf("failed to delete maintenance configuration: %w", err)
+	}
+
+	fmt.Printf("Deleted maintenance configuration '%s' from cluster '%s'\n", name, clusterName)
+	return nil
+}
@@ -0,0 +1,269 @@
+package maintenanceconfiguration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// previewHorizon bounds the search for windows, so a schedule that every
+// not-allowed range blocks still ends.
+const previewHorizon = 5 * 366 * 24 * time.Hour
+
+// Window is one maintenance window a schedule allows.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Preview prints the next windows of a schedule without applying it. With
+// a cluster, the flags change its current configuration, previewing what
+// update would apply.
+func Preview(ctx context.Context, cmd *cobra.Command, clusterName, configName, resourceGroup string, count int, from time.Time) error {
+	name, err := checkName(configName)
+	if err != nil {
+		return err
+	}
+
+	props := &armcontainerservice.MaintenanceConfigurationProperties{}
+	if clusterName != "" {
+		cred, err := azure.GetCredential()
+		if err != nil {
+			return err
+		}
+		subscriptionID, err := config.GetDefaultSubscription()
+		if err != nil {
+			return err
+		}
+		client, err := armcontainerservice.NewMaintenanceConfigurationsClient(subscriptionID, cred, azure.ARMClientOptions())
+		if err != nil {
+			return fmt.Errorf("failed to create maintenance configurations client: %w", err)
+		}
+		current, err := client.Get(ctx, resourceGroup, clusterName, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get maintenance configuration: %w", err)
+		}
+		if current.Properties != nil {
+			props = current.Properties
+		}
+	}
+
+	if err := applyScheduleFlags(cmd, props); err != nil {
+		return err
+	}
+	if err := validate(name, props); err != nil {
+		return err
+	}
+
+	return output.PrintJSON(cmd, nextWindows(props, from, count))
+}
+
+// nextWindows returns up to count windows starting at or after from,
+// leaving out any that overlap a not-allowed range.
+func nextWindows(props *armcontainerservice.MaintenanceConfigurationProperties, from time.Time, count int) []Window {
+	if props.MaintenanceWindow != nil {
+		return scheduleWindows(props.MaintenanceWindow, from, count)
+	}
+	return weekWindows(props, from, count)
+}
+
+// scheduleWindows expands a maintenance window's schedule. Times and dates
+// are in the window's UTC offset. Intervals count from --start-date, or from
+// the preview's start when there is none, since AKS doesn't say which
+// occurrence the service itself counts from.
+func scheduleWindows(w *armcontainerservice.MaintenanceWindow, from time.Time, count int) []Window {
+	loc := offsetZone(w.UTCOffset)
+	from = from.In(loc)
+	hour, minute := parseClock(*w.StartTime)
+	duration := time.Duration(*w.DurationHours) * time.Hour
+
+	anchor := midnight(from)
+	if w.StartDate != nil {
+		anchor = time.Date(w.StartDate.Year(), w.StartDate.Month(), w.StartDate.Day(), 0, 0, 0, 0, loc)
+	}
+
+	var blocked []Window
+	for _, span := range w.NotAllowedDates {
+		// A date range blocks from the start of its first day to the end of
+		// its last.
+		start := time.Date(span.Start.Year(), span.Start.Month(), span.Start.Day(), 0, 0, 0, 0, loc)
+		end := time.Date(span.End.Year(), span.End.Month(), span.End.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+		blocked = append(blocked, Window{start, end})
+	}
+
+	var windows []Window
+	limit := from.Add(previewHorizon)
+	for _, day := range occurrences(w.Schedule, anchor, limit) {
+		start := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+		win := Window{start, start.Add(duration)}
+		if start.Before(from) || overlapsAny(win, blocked) {
+			continue
+		}
+		windows = append(windows, win)
+		if len(windows) == count {
+			break
+		}
+	}
+	return windows
+}
+
+// occurrences lists the days a schedule falls on from anchor until limit.
+func occurrences(s *armcontainerservice.Schedule, anchor, limit time.Time) []time.Time {
+	var days []time.Time
+	switch {
+	case s.Daily != nil:
+		for d := anchor; d.Before(limit); d = d.AddDate(0, 0, int(*s.Daily.IntervalDays)) {
+			days = append(days, d)
+		}
+	case s.Weekly != nil:
+		d := anchor
+		for weekday(d) != *s.Weekly.DayOfWeek {
+			d = d.AddDate(0, 0, 1)
+		}
+		for ; d.Before(limit); d = d.AddDate(0, 0, 7*int(*s.Weekly.IntervalWeeks)) {
+			days = append(days, d)
+		}
+	case s.AbsoluteMonthly != nil:
+		// Months too short for the day are skipped rather than moved to
+		// their last day.
+		interval := int(*s.AbsoluteMonthly.IntervalMonths)
+		for m := firstOfMonth(anchor); m.Before(limit); m = m.AddDate(0, interval, 0) {
+			d := m.AddDate(0, 0, int(*s.AbsoluteMonthly.DayOfMonth)-1)
+			if d.Month() == m.Month() && !d.Before(anchor) {
+				days = append(days, d)
+			}
+		}
+	case s.RelativeMonthly != nil:
+		r := s.RelativeMonthly
+		for m := firstOfMonth(anchor); m.Before(limit); m = m.AddDate(0, int(*r.IntervalMonths), 0) {
+			if d := nthWeekday(m, *r.DayOfWeek, *r.WeekIndex); !d.Before(anchor) {
+				days = append(days, d)
+			}
+		}
+	}
+	return days
+}
+
+// nthWeekday returns the first, second, third, fourth or last given day of
+// the week in month.
+func nthWeekday(month time.Time, day armcontainerservice.WeekDay, index armcontainerservice.Type) time.Time {
+	if index == armcontainerservice.TypeLast {
+		d := month.AddDate(0, 1, -1)
+		for weekday(d) != day {
+			d = d.AddDate(0, 0, -1)
+		}
+		return d
+	}
+	d := month
+	for weekday(d) != day {
+		d = d.AddDate(0, 0, 1)
+	}
+	weeks := map[armcontainerservice.Type]int{
+		armcontainerservice.TypeFirst:  0,
+		armcontainerservice.TypeSecond: 1,
+		armcontainerservice.TypeThird:  2,
+		armcontainerservice.TypeFourth: 3,
+	}
+	return d.AddDate(0, 0, 7*weeks[index])
+}
+
+// weekWindows expands the default configuration's hour slots, which are in
+// UTC, merging adjacent slots into one window.
+func weekWindows(props *armcontainerservice.MaintenanceConfigurationProperties, from time.Time, count int) []Window {
+	from = from.UTC()
+	slots := map[armcontainerservice.WeekDay][]int{}
+	for _, t := range props.TimeInWeek {
+		if t.Day == nil {
+			continue
+		}
+		for _, h := range t.HourSlots {
+			slots[*t.Day] = append(slots[*t.Day], int(*h))
+		}
+	}
+
+	var blocked []Window
+	for _, span := range props.NotAllowedTime {
+		blocked = append(blocked, Window{*span.Start, *span.End})
+	}
+
+	var windows []Window
+	var current *Window
+	flush := func() {
+		if current != nil && !current.Start.Before(from) && !overlapsAny(*current, blocked) {
+			windows = append(windows, *current)
+		}
+		current = nil
+	}
+	for day := midnight(from); day.Before(from.Add(previewHorizon)) && len(windows) < count; day = day.AddDate(0, 0, 1) {
+		hours := append([]int(nil), slots[weekday(day)]...)
+		sort.Ints(hours)
+		for _, h := range hours {
+			start := day.Add(time.Duration(h) * time.Hour)
+			if current != nil && !current.End.Before(start) {
+				current.End = start.Add(time.Hour)
+				continue
+			}
+			flush()
+			current = &Window{start, start.Add(time.Hour)}
+		}
+		// A window still open at midnight may carry on into the next day.
+		if current != nil && current.End.Before(day.AddDate(0, 0, 1)) {
+			flush()
+		}
+	}
+	flush()
+	if len(windows) > count {
+		windows = windows[:count]
+	}
+	return windows
+}
+
+func overlapsAny(w Window, blocked []Window) bool {
+	for _, b := range blocked {
+		if w.Start.Before(b.End) && b.Start.Before(w.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// offsetZone turns a +/-HH:mm UTC offset into a fixed zone; none means UTC.
+func offsetZone(offset *string) *time.Location {
+	if offset == nil || *offset == "" {
+		return time.UTC
+	}
+	o := *offset
+	hours, _ := strconv.Atoi(o[1:3])
+	minutes, _ := strconv.Atoi(o[4:6])
+	seconds := hours*3600 + minutes*60
+	if o[0] == '-' {
+		seconds = -seconds
+	}
+	return time.FixedZone(o, seconds)
+}
+
+func parseClock(hhmm string) (int, int) {
+	hour, _ := strconv.Atoi(hhmm[:2])
+	minute, _ := strconv.Atoi(hhmm[3:])
+	return hour, minute
+}
+
+func weekday(t time.Time) armcontainerservice.WeekDay {
+	return armcontainerservice.WeekDay(t.Weekday().String())
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
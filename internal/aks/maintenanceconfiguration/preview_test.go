@@ -0,0 +1,123 @@
+package maintenanceconfiguration
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+)
+
+func date(s string) *time.Time {
+	t, _ := time.Parse(dateLayout, s)
+	return &t
+}
+
+// starts formats the windows' starts for comparison.
+func starts(windows []Window) []string {
+	var out []string
+	for _, w := range windows {
+		out = append(out, w.Start.Format("2006-01-02T15:04Z07:00"))
+	}
+	return out
+}
+
+func TestScheduleWindows(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule armcontainerservice.Schedule
+		want     []string
+	}{
+		{
+			"every other Saturday",
+			armcontainerservice.Schedule{Weekly: &armcontainerservice.WeeklySchedule{DayOfWeek: to.Ptr(armcontainerservice.WeekDaySaturday), IntervalWeeks: to.Ptr[int32](2)}},
+			[]string{"2026-01-03T22:00+01:00", "2026-01-17T22:00+01:00", "2026-01-31T22:00+01:00"},
+		},
+		{
+			"31st, skipping short months",
+			armcontainerservice.Schedule{AbsoluteMonthly: &armcontainerservice.AbsoluteMonthlySchedule{DayOfMonth: to.Ptr[int32](31), IntervalMonths: to.Ptr[int32](1)}},
+			[]string{"2026-01-31T22:00+01:00", "2026-03-31T22:00+01:00", "2026-05-31T22:00+01:00"},
+		},
+		{
+			"last Friday every quarter",
+			armcontainerservice.Schedule{RelativeMonthly: &armcontainerservice.RelativeMonthlySchedule{DayOfWeek: to.Ptr(armcontainerservice.WeekDayFriday), WeekIndex: to.Ptr(armcontainerservice.TypeLast), IntervalMonths: to.Ptr[int32](3)}},
+			[]string{"2026-01-30T22:00+01:00", "2026-04-24T22:00+01:00", "2026-07-31T22:00+01:00"},
+		},
+		{
+			"second Tuesday",
+			armcontainerservice.Schedule{RelativeMonthly: &armcontainerservice.RelativeMonthlySchedule{DayOfWeek: to.Ptr(armcontainerservice.WeekDayTuesday), WeekIndex: to.Ptr(armcontainerservice.TypeSecond), IntervalMonths: to.Ptr[int32](1)}},
+			[]string{"2026-01-13T22:00+01:00", "2026-02-10T22:00+01:00", "2026-03-10T22:00+01:00"},
+		},
+		{
+			"every three days",
+			armcontainerservice.Schedule{Daily: &armcontainerservice.DailySchedule{IntervalDays: to.Ptr[int32](3)}},
+			[]string{"2026-01-01T22:00+01:00", "2026-01-04T22:00+01:00", "2026-01-07T22:00+01:00"},
+		},
+	}
+	for _, tt := range tests {
+		w := &armcontainerservice.MaintenanceWindow{
+			Schedule: &tt.schedule, DurationHours: to.Ptr[int32](6), StartTime: to.Ptr("22:00"), UTCOffset: to.Ptr("+01:00"),
+		}
+		got := scheduleWindows(w, from, 3)
+		if !slices.Equal(starts(got), tt.want) {
+			t.Errorf("%s: windows start %v, want %v", tt.name, starts(got), tt.want)
+		}
+		if len(got) > 0 && got[0].End.Sub(got[0].Start) != 6*time.Hour {
+			t.Errorf("%s: window lasts %s, want 6h", tt.name, got[0].End.Sub(got[0].Start))
+		}
+	}
+}
+
+func TestScheduleWindowsStartDateAndNotAllowed(t *testing.T) {
+	w := &armcontainerservice.MaintenanceWindow{
+		Schedule:      &armcontainerservice.Schedule{Weekly: &armcontainerservice.WeeklySchedule{DayOfWeek: to.Ptr(armcontainerservice.WeekDayMonday), IntervalWeeks: to.Ptr[int32](1)}},
+		DurationHours: to.Ptr[int32](4),
+		StartTime:     to.Ptr("02:00"),
+		StartDate:     date("2026-12-01"),
+		// Blocks the Mondays of 21 and 28 December and 4 January, the last
+		// because the range's end date is blocked all day.
+		NotAllowedDates: []*armcontainerservice.DateSpan{{Start: date("2026-12-20"), End: date("2027-01-04")}},
+	}
+	got := starts(scheduleWindows(w, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), 4))
+	want := []string{"2026-12-07T02:00Z", "2026-12-14T02:00Z", "2027-01-11T02:00Z", "2027-01-18T02:00Z"}
+	if !slices.Equal(got, want) {
+		t.Errorf("windows start %v, want %v", got, want)
+	}
+
+	// Every window is blocked: the horizon stops the search.
+	w.NotAllowedDates = []*armcontainerservice.DateSpan{{Start: date("2026-01-01"), End: date("2040-01-01")}}
+	if got := scheduleWindows(w, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), 4); len(got) != 0 {
+		t.Errorf("windows = %v, want none", got)
+	}
+}
+
+func TestWeekWindows(t *testing.T) {
+	slots := func(hours ...int32) []*int32 {
+		var out []*int32
+		for _, h := range hours {
+			out = append(out, to.Ptr(h))
+		}
+		return out
+	}
+	props := &armcontainerservice.MaintenanceConfigurationProperties{
+		TimeInWeek: []*armcontainerservice.TimeInWeek{
+			{Day: to.Ptr(armcontainerservice.WeekDaySunday), HourSlots: slots(22, 23)},
+			{Day: to.Ptr(armcontainerservice.WeekDayMonday), HourSlots: slots(0, 5)},
+		},
+		NotAllowedTime: []*armcontainerservice.TimeSpan{{
+			Start: to.Ptr(time.Date(2026, 1, 12, 4, 0, 0, 0, time.UTC)),
+			End:   to.Ptr(time.Date(2026, 1, 12, 7, 0, 0, 0, time.UTC)),
+		}},
+	}
+	got := weekWindows(props, time.Date(2026, 1, 5, 3, 0, 0, 0, time.UTC), 3)
+	want := []string{"2026-01-05T05:00Z", "2026-01-11T22:00Z", "2026-01-18T22:00Z"}
+	if !slices.Equal(starts(got), want) {
+		t.Fatalf("windows start %v, want %v", starts(got), want)
+	}
+	// Sunday 22:00 to Monday 01:00 is one window.
+	if got[1].End != time.Date(2026, 1, 12, 1, 0, 0, 0, time.UTC) {
+		t.Errorf("window ends %s, want it to run into Monday", got[1].End)
+	}
+}
@@ -0,0 +1,349 @@
+package maintenanceconfiguration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/enumflag"
+	"github.com/spf13/cobra"
+)
+
+// The only configuration names AKS accepts. default gates planned
+// maintenance with weekly hour slots; the other two gate cluster and node OS
+// auto-upgrades with a maintenance window.
+const (
+	configDefault       = "default"
+	configAutoUpgrade   = "aksManagedAutoUpgradeSchedule"
+	configNodeOSUpgrade = "aksManagedNodeOSUpgradeSchedule"
+	scheduleDaily       = "Daily"
+	scheduleWeekly      = "Weekly"
+	scheduleAbsolute    = "AbsoluteMonthly"
+	scheduleRelative    = "RelativeMonthly"
+	dateLayout          = "2006-01-02"
+)
+
+var (
+	configNames   = []string{configDefault, configAutoUpgrade, configNodeOSUpgrade}
+	scheduleTypes = []string{scheduleDaily, scheduleWeekly, scheduleAbsolute, scheduleRelative}
+
+	startTimePattern = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+	utcOffsetPattern = regexp.MustCompile(`^[+-](0\d|1[0-4]):[0-5]\d$`)
+)
+
+// addScheduleFlags registers the flags add, update and preview share.
+func addScheduleFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.String("config-file", "", "JSON file with the configuration's properties, in the API's shape; flags override it")
+	f.String("weekday", "", "Day of the week for the default configuration, such as Monday")
+	f.Int32("start-hour", 0, "Hour of --weekday, 0-23 UTC, that maintenance may start in, for the default configuration")
+	f.String("schedule-type", "", "Schedule of the maintenance window: Daily, Weekly, AbsoluteMonthly or RelativeMonthly")
+	f.Int32("interval-days", 0, "Days between windows of a Daily schedule, 1-7")
+	f.Int32("interval-weeks", 0, "Weeks between windows of a Weekly schedule, 1-4")
+	f.Int32("interval-months", 0, "Months between windows of a monthly schedule, 1-6")
+	f.String("day-of-week", "", "Day of the week of a Weekly or RelativeMonthly schedule")
+	f.Int32("day-of-month", 0, "Day of the month of an AbsoluteMonthly schedule, 1-31")
+	f.String("week-index", "", "Week of the month of a RelativeMonthly schedule: First, Second, Third, Fourth or Last")
+	f.Int32("duration", 0, "Length of the maintenance window in hours, 4-24")
+	f.String("start-time", "", "Time the maintenance window starts, as HH:mm in --utc-offset")
+	f.String("start-date", "", "Date the maintenance window takes effect, as YYYY-MM-DD")
+	f.String("utc-offset", "", "UTC offset the window's times and dates are in, as +/-HH:mm (default +00:00)")
+}
+
+// applyScheduleFlags applies --config-file and then the flags given to
+// props. --schedule-type replaces the schedule; the other window flags only
+// change their own field, so update can adjust one setting at a time.
+func applyScheduleFlags(cmd *cobra.Command, props *armcontainerservice.MaintenanceConfigurationProperties) error {
+	flags := cmd.Flags()
+	if path, _ := flags.GetString("config-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		var fromFile armcontainerservice.MaintenanceConfigurationProperties
+		if err := json.Unmarshal(data, &fromFile); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		mergeProperties(props, fromFile)
+	}
+
+	if flags.Changed("weekday") || flags.Changed("start-hour") {
+		if !flags.Changed("weekday") || !flags.Changed("start-hour") {
+			return fmt.Errorf("--weekday and --start-hour must be given together")
+		}
+		v, _ := flags.GetString("weekday")
+		day, err := enumflag.Parse("weekday", v, armcontainerservice.PossibleWeekDayValues())
+		if err != nil {
+			return err
+		}
+		hour, _ := flags.GetInt32("start-hour")
+		if hour < 0 || hour > 23 {
+			return fmt.Errorf("--start-hour must be between 0 and 23")
+		}
+		props.TimeInWeek = []*armcontainerservice.TimeInWeek{{Day: to.Ptr(day), HourSlots: []*int32{to.Ptr(hour)}}}
+	}
+
+	if !flags.Changed("schedule-type") {
+		for _, name := range []string{"interval-days", "interval-weeks", "interval-months", "day-of-week", "day-of-month", "week-index"} {
+			if flags.Changed(name) {
+				return fmt.Errorf("--%s requires --schedule-type", name)
+			}
+		}
+	}
+	changed := false
+	for _, name := range []string{"schedule-type", "duration", "start-time", "start-date", "utc-offset"} {
+		changed = changed || flags.Changed(name)
+	}
+	if !changed {
+		return nil
+	}
+
+	if props.MaintenanceWindow == nil {
+		props.MaintenanceWindow = &armcontainerservice.MaintenanceWindow{}
+	}
+	window := props.MaintenanceWindow
+	if flags.Changed("schedule-type") {
+		schedule, err := buildSchedule(cmd)
+		if err != nil {
+			return err
+		}
+		window.Schedule = schedule
+	}
+	if flags.Changed("duration") {
+		v, _ := flags.GetInt32("duration")
+		window.DurationHours = to.Ptr(v)
+	}
+	if flags.Changed("start-time") {
+		v, _ := flags.GetString("start-time")
+		window.StartTime = to.Ptr(v)
+	}
+	if flags.Changed("start-date") {
+		v, _ := flags.GetString("start-date")
+		d, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return fmt.Errorf("invalid --start-date %q: expected YYYY-MM-DD", v)
+		}
+		window.StartDate = to.Ptr(d)
+	}
+	if flags.Changed("utc-offset") {
+		v, _ := flags.GetString("utc-offset")
+		window.UTCOffset = to.Ptr(v)
+	}
+	return nil
+}
+
+// buildSchedule builds the schedule --schedule-type names from the flags
+// that type uses, rejecting those it doesn't.
+func buildSchedule(cmd *cobra.Command) (*armcontainerservice.Schedule, error) {
+	flags := cmd.Flags()
+	v, _ := flags.GetString("schedule-type")
+	scheduleType, err := enumflag.Parse("schedule-type", v, scheduleTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	uses := map[string][]string{
+		scheduleDaily:    {"interval-days"},
+		scheduleWeekly:   {"interval-weeks", "day-of-week"},
+		scheduleAbsolute: {"interval-months", "day-of-month"},
+		scheduleRelative: {"interval-months", "day-of-week", "week-index"},
+	}
+	for _, name := range []string{"interval-days", "interval-weeks", "interval-months", "day-of-week", "day-of-month", "week-index"} {
+		used := false
+		for _, u := range uses[scheduleType] {
+			used = used || u == name
+		}
+		if used && !flags.Changed(name) {
+			return nil, fmt.Errorf("--schedule-type %s requires --%s", scheduleType, name)
+		}
+		if !used && flags.Changed(name) {
+			return nil, fmt.Errorf("--%s cannot be used with --schedule-type %s", name, scheduleType)
+		}
+	}
+
+	dayOfWeek := func() (*armcontainerservice.WeekDay, error) {
+		v, _ := flags.GetString("day-of-week")
+		day, err := enumflag.Parse("day-of-week", v, armcontainerservice.PossibleWeekDayValues())
+		return &day, err
+	}
+	interval := func(name string) *int32 {
+		v, _ := flags.GetInt32(name)
+		return &v
+	}
+
+	schedule := &armcontainerservice.Schedule{}
+	switch scheduleType {
+	case scheduleDaily:
+		schedule.Daily = &armcontainerservice.DailySchedule{IntervalDays: interval("interval-days")}
+	case scheduleWeekly:
+		day, err := dayOfWeek()
+		if err != nil {
+			return nil, err
+		}
+		schedule.Weekly = &armcontainerservice.WeeklySchedule{DayOfWeek: day, IntervalWeeks: interval("interval-weeks")}
+	case scheduleAbsolute:
+		schedule.AbsoluteMonthly = &armcontainerservice.AbsoluteMonthlySchedule{DayOfMonth: interval("day-of-month"), IntervalMonths: interval("interval-months")}
+	case scheduleRelative:
+		day, err := dayOfWeek()
+		if err != nil {
+			return nil, err
+		}
+		v, _ := flags.GetString("week-index")
+		index, err := enumflag.Parse("week-index", v, armcontainerservice.PossibleTypeValues())
+		if err != nil {
+			return nil, err
+		}
+		schedule.RelativeMonthly = &armcontainerservice.RelativeMonthlySchedule{DayOfWeek: day, WeekIndex: &index, IntervalMonths: interval("interval-months")}
+	}
+	return schedule, nil
+}
+
+// mergeProperties copies the parts of a config file that are set over props.
+func mergeProperties(props *armcontainerservice.MaintenanceConfigurationProperties, from armcontainerservice.MaintenanceConfigurationProperties) {
+	if from.TimeInWeek != nil {
+		props.TimeInWeek = from.TimeInWeek
+	}
+	if from.NotAllowedTime != nil {
+		props.NotAllowedTime = from.NotAllowedTime
+	}
+	if from.MaintenanceWindow == nil {
+		return
+	}
+	if props.MaintenanceWindow == nil {
+		props.MaintenanceWindow = from.MaintenanceWindow
+		return
+	}
+	w, f := props.MaintenanceWindow, from.MaintenanceWindow
+	if f.Schedule != nil {
+		w.Schedule = f.Schedule
+	}
+	if f.DurationHours != nil {
+		w.DurationHours = f.DurationHours
+	}
+	if f.StartTime != nil {
+		w.StartTime = f.StartTime
+	}
+	if f.StartDate != nil {
+		w.StartDate = f.StartDate
+	}
+	if f.UTCOffset != nil {
+		w.UTCOffset = f.UTCOffset
+	}
+	if f.NotAllowedDates != nil {
+		w.NotAllowedDates = f.NotAllowedDates
+	}
+}
+
+// validate checks props against the rules AKS applies to the named
+// configuration, so mistakes surface before anything is sent.
+func validate(name string, props *armcontainerservice.MaintenanceConfigurationProperties) error {
+	if name == configDefault {
+		if props.MaintenanceWindow != nil {
+			return fmt.Errorf("the default configuration uses --weekday and --start-hour, not a maintenance window schedule")
+		}
+		if len(props.TimeInWeek) == 0 {
+			return fmt.Errorf("the default configuration needs --weekday and --start-hour, or timeInWeek in --config-file")
+		}
+		for _, t := range props.TimeInWeek {
+			for _, h := range t.HourSlots {
+				if h == nil || *h < 0 || *h > 23 {
+					return fmt.Errorf("hour slots must be between 0 and 23")
+				}
+			}
+		}
+		for _, span := range props.NotAllowedTime {
+			if span.Start == nil || span.End == nil || !span.End.After(*span.Start) {
+				return fmt.Errorf("each notAllowedTime needs a start before its end")
+			}
+		}
+		return nil
+	}
+
+	if len(props.TimeInWeek) > 0 || len(props.NotAllowedTime) > 0 {
+		return fmt.Errorf("%s uses a maintenance window schedule; --weekday, --start-hour, timeInWeek and notAllowedTime are for the default configuration", name)
+	}
+	w := props.MaintenanceWindow
+	if w == nil || w.Schedule == nil {
+		return fmt.Errorf("%s needs a schedule; give --schedule-type", name)
+	}
+	s := w.Schedule
+	set := 0
+	for _, present := range []bool{s.Daily != nil, s.Weekly != nil, s.AbsoluteMonthly != nil, s.RelativeMonthly != nil} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("a schedule must be exactly one of daily, weekly, absoluteMonthly or relativeMonthly")
+	}
+
+	switch {
+	case s.Daily != nil:
+		// AKS only lets node OS upgrades run daily.
+		if name != configNodeOSUpgrade {
+			return fmt.Errorf("a Daily schedule is only allowed for %s", configNodeOSUpgrade)
+		}
+		if err := checkRange("interval-days", s.Daily.IntervalDays, 1, 7); err != nil {
+			return err
+		}
+	case s.Weekly != nil:
+		if err := checkRange("interval-weeks", s.Weekly.IntervalWeeks, 1, 4); err != nil {
+			return err
+		}
+		if s.Weekly.DayOfWeek == nil {
+			return fmt.Errorf("a Weekly schedule needs --day-of-week")
+		}
+	case s.AbsoluteMonthly != nil:
+		if err := checkRange("interval-months", s.AbsoluteMonthly.IntervalMonths, 1, 6); err != nil {
+			return err
+		}
+		if err := checkRange("day-of-month", s.AbsoluteMonthly.DayOfMonth, 1, 31); err != nil {
+			return err
+		}
+	case s.RelativeMonthly != nil:
+		if err := checkRange("interval-months", s.RelativeMonthly.IntervalMonths, 1, 6); err != nil {
+			return err
+		}
+		if s.RelativeMonthly.DayOfWeek == nil || s.RelativeMonthly.WeekIndex == nil {
+			return fmt.Errorf("a RelativeMonthly schedule needs --day-of-week and --week-index")
+		}
+	}
+
+	if err := checkRange("duration", w.DurationHours, 4, 24); err != nil {
+		return err
+	}
+	if w.StartTime == nil || !startTimePattern.MatchString(*w.StartTime) {
+		return fmt.Errorf("--start-time is required, as HH:mm between 00:00 and 23:59")
+	}
+	if w.UTCOffset != nil && *w.UTCOffset != "" && !utcOffsetPattern.MatchString(*w.UTCOffset) {
+		return fmt.Errorf("invalid --utc-offset %q: expected +/-HH:mm, such as +05:30", *w.UTCOffset)
+	}
+	for _, span := range w.NotAllowedDates {
+		if span.Start == nil || span.End == nil || span.End.Before(*span.Start) {
+			return fmt.Errorf("each notAllowedDates range needs a start on or before its end")
+		}
+	}
+	return nil
+}
+
+func checkRange(flag string, v *int32, min, max int32) error {
+	if v == nil || *v < min || *v > max {
+		return fmt.Errorf("--%s must be between %d and %d", flag, min, max)
+	}
+	return nil
+}
+
+// checkName rejects names AKS would not accept, returning the canonical
+// spelling of one it would.
+func checkName(name string) (string, error) {
+	n, err := enumflag.Parse("name", name, configNames)
+	if err != nil {
+		return "", fmt.Errorf("invalid maintenance configuration name %q: must be one of %s", name, strings.Join(configNames, ", "))
+	}
+	return n, nil
+}
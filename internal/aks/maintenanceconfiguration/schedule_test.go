@@ -0,0 +1,140 @@
+package maintenanceconfiguration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/spf13/cobra"
+)
+
+// scheduleFlags returns a command carrying just the schedule flags, with
+// args parsed.
+func scheduleFlags(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{}
+	addScheduleFlags(cmd)
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestApplyScheduleFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freeze.json")
+	freeze := `{"maintenanceWindow": {"notAllowedDates": [{"start": "2026-12-20", "end": "2027-01-03"}]}}`
+	if err := os.WriteFile(path, []byte(freeze), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := scheduleFlags(t, "--schedule-type", "relativemonthly", "--week-index", "last", "--day-of-week", "friday",
+		"--interval-months", "1", "--duration", "8", "--start-time", "20:00", "--utc-offset", "-05:00",
+		"--start-date", "2026-11-01", "--config-file", path)
+	props := &armcontainerservice.MaintenanceConfigurationProperties{}
+	if err := applyScheduleFlags(cmd, props); err != nil {
+		t.Fatal(err)
+	}
+	if err := validate(configAutoUpgrade, props); err != nil {
+		t.Fatal(err)
+	}
+	w := props.MaintenanceWindow
+	r := w.Schedule.RelativeMonthly
+	if r == nil || *r.WeekIndex != armcontainerservice.TypeLast || *r.DayOfWeek != armcontainerservice.WeekDayFriday {
+		t.Errorf("schedule = %+v", w.Schedule)
+	}
+	if *w.DurationHours != 8 || *w.StartTime != "20:00" || *w.UTCOffset != "-05:00" || w.StartDate.Format(dateLayout) != "2026-11-01" {
+		t.Errorf("window = %+v", w)
+	}
+	if len(w.NotAllowedDates) != 1 || w.NotAllowedDates[0].End.Format(dateLayout) != "2027-01-03" {
+		t.Errorf("notAllowedDates = %v", w.NotAllowedDates)
+	}
+}
+
+func TestApplyScheduleFlagsUpdate(t *testing.T) {
+	props := &armcontainerservice.MaintenanceConfigurationProperties{MaintenanceWindow: &armcontainerservice.MaintenanceWindow{
+		Schedule:      &armcontainerservice.Schedule{Weekly: &armcontainerservice.WeeklySchedule{DayOfWeek: to.Ptr(armcontainerservice.WeekDaySunday), IntervalWeeks: to.Ptr[int32](1)}},
+		DurationHours: to.Ptr[int32](4),
+		StartTime:     to.Ptr("01:00"),
+	}}
+	if err := applyScheduleFlags(scheduleFlags(t, "--start-time", "03:30"), props); err != nil {
+		t.Fatal(err)
+	}
+	w := props.MaintenanceWindow
+	if *w.StartTime != "03:30" || w.Schedule.Weekly == nil || *w.DurationHours != 4 {
+		t.Errorf("window = %+v, want only the start time changed", w)
+	}
+}
+
+func TestApplyScheduleFlagsRejects(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--interval-weeks", "2"}, "requires --schedule-type"},
+		{[]string{"--start-time", "03:00", "--day-of-week", "Monday"}, "requires --schedule-type"},
+		{[]string{"--schedule-type", "Weekly", "--interval-weeks", "1"}, "requires --day-of-week"},
+		{[]string{"--schedule-type", "Weekly", "--interval-weeks", "1", "--day-of-week", "Monday", "--day-of-month", "3"}, "cannot be used"},
+		{[]string{"--schedule-type", "Hourly"}, "allowed values"},
+		{[]string{"--weekday", "Monday"}, "together"},
+		{[]string{"--start-date", "01/02/2026"}, "YYYY-MM-DD"},
+	}
+	for _, tt := range tests {
+		err := applyScheduleFlags(scheduleFlags(t, tt.args...), &armcontainerservice.MaintenanceConfigurationProperties{})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("applyScheduleFlags(%v) error = %v, want one mentioning %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	window := func(s *armcontainerservice.Schedule, duration int32, start, offset string) *armcontainerservice.MaintenanceConfigurationProperties {
+		return &armcontainerservice.MaintenanceConfigurationProperties{MaintenanceWindow: &armcontainerservice.MaintenanceWindow{
+			Schedule: s, DurationHours: to.Ptr(duration), StartTime: to.Ptr(start), UTCOffset: to.Ptr(offset),
+		}}
+	}
+	daily := &armcontainerservice.Schedule{Daily: &armcontainerservice.DailySchedule{IntervalDays: to.Ptr[int32](1)}}
+	weekly := &armcontainerservice.Schedule{Weekly: &armcontainerservice.WeeklySchedule{DayOfWeek: to.Ptr(armcontainerservice.WeekDayMonday), IntervalWeeks: to.Ptr[int32](5)}}
+	monthly := &armcontainerservice.Schedule{AbsoluteMonthly: &armcontainerservice.AbsoluteMonthlySchedule{DayOfMonth: to.Ptr[int32](15), IntervalMonths: to.Ptr[int32](1)}}
+	defaultProps := &armcontainerservice.MaintenanceConfigurationProperties{
+		TimeInWeek: []*armcontainerservice.TimeInWeek{{Day: to.Ptr(armcontainerservice.WeekDayMonday), HourSlots: []*int32{to.Ptr[int32](1)}}},
+	}
+
+	if err := validate(configNodeOSUpgrade, window(daily, 4, "00:00", "+05:30")); err != nil {
+		t.Errorf("daily node OS schedule: %v", err)
+	}
+	if err := validate(configDefault, defaultProps); err != nil {
+		t.Errorf("default config: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		props *armcontainerservice.MaintenanceConfigurationProperties
+		want  string
+	}{
+		{configAutoUpgrade, window(daily, 4, "00:00", ""), "only allowed for"},
+		{configAutoUpgrade, window(weekly, 4, "00:00", ""), "--interval-weeks"},
+		{configAutoUpgrade, window(monthly, 2, "00:00", ""), "--duration"},
+		{configAutoUpgrade, window(monthly, 4, "24:00", ""), "--start-time"},
+		{configAutoUpgrade, window(monthly, 4, "00:00", "+5"), "--utc-offset"},
+		{configAutoUpgrade, defaultProps, "maintenance window schedule"},
+		{configDefault, window(monthly, 4, "00:00", ""), "--weekday"},
+		{configNodeOSUpgrade, &armcontainerservice.MaintenanceConfigurationProperties{}, "--schedule-type"},
+	}
+	for _, tt := range tests {
+		if err := validate(tt.name, tt.props); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("validate(%s) error = %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestCheckName(t *testing.T) {
+	if name, err := checkName("aksmanagedautoupgradeschedule"); err != nil || name != configAutoUpgrade {
+		t.Errorf("checkName = %q, %v", name, err)
+	}
+	if _, err := checkName("nightly"); err == nil {
+		t.Error("checkName(nightly) succeeded, want error")
+	}
+}
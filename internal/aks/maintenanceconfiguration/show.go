@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, clusterName, configName, reso
 		return err
 	}
 
-	client, err := armcontainerservice.NewMaintenanceConfigurationsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewMaintenanceConfigurationsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create maintenance configurations client: %w", err)
 	}
@@ -0,0 +1,57 @@
+package maintenanceconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// Update reads the configuration, applies the given flags and PUTs it back.
+func Update(ctx context.Context, cmd *cobra.Command, clusterName, configName, resourceGroup string) error {
+	name, err := checkName(configName)
+	if err != nil {
+		return err
+	}
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewMaintenanceConfigurationsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance configurations client: %w", err)
+	}
+
+	current, err := client.Get(ctx, resourceGroup, clusterName, name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance configuration: %w", err)
+	}
+	if current.Properties == nil {
+		current.Properties = &armcontainerservice.MaintenanceConfigurationProperties{}
+	}
+
+	if err := applyScheduleFlags(cmd, current.Properties); err != nil {
+		return err
+	}
+	if err := validate(name, current.Properties); err != nil {
+		return err
+	}
+
+	resp, err := client.CreateOrUpdate(ctx, resourceGroup, clusterName, name, current.MaintenanceConfiguration, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update maintenance configuration: %w", err)
+	}
+
+	return output.PrintJSON(cmd, resp.MaintenanceConfiguration)
+}
@@ -4,12 +4,19 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/enumflag"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/snapshot"
+	"github.com/cdobbyn/azure-go-cli/internal/vm"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
 	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/spf13/cobra"
 )
 
-func Add(ctx context.Context, clusterName, nodepoolName, resourceGroup string, nodeCount int32, vmSize string) error {
+func Add(ctx context.Context, cmd *cobra.Command, clusterName, nodepoolName, resourceGroup string) error {
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
@@ -20,7 +27,7 @@ func Add(ctx context.Context, clusterName, nodepoolName, resourceGroup string, n
 		return err
 	}
 
-	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create agent pools client: %w", err)
 	}
@@ -31,24 +38,54 @@ func Add(ctx context.Context, clusterName, nodepoolName, resourceGroup string, n
 		return fmt.Errorf("node pool '%s' already exists in cluster '%s'", nodepoolName, clusterName)
 	}
 
-	fmt.Printf("Creating node pool '%s' in cluster '%s'...\n", nodepoolName, clusterName)
+	var snap *armcontainerservice.Snapshot
+	if id, _ := cmd.Flags().GetString("snapshot-id"); id != "" {
+		s, err := snapshot.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		snap = &s
+	}
 
-	// Create node pool with basic configuration
-	nodePool := armcontainerservice.AgentPool{
-		Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
-			Count:  &nodeCount,
-			VMSize: &vmSize,
-			OSType: azure.GetOSType("Linux"),
-			Mode:   azure.GetAgentPoolMode("User"),
-		},
+	nodePool, err := buildPool(cmd, snap)
+	if err != nil {
+		return err
+	}
+
+	// Check the size against what the cluster's region offers this
+	// subscription, rather than waiting for the create to fail.
+	clusters, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AKS client: %w", err)
+	}
+	cluster, err := clusters.Get(ctx, resourceGroup, clusterName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+	location := azure.GetStringValue(cluster.Location)
+	vmSize := *nodePool.Properties.VMSize
+	sku, err := vm.FindSKU(ctx, subscriptionID, location, vmSize)
+	if err != nil {
+		return err
+	}
+	zones, _ := cmd.Flags().GetStringSlice("zones")
+	if err := checkVMSize(sku, vmSize, location, zones); err != nil {
+		return err
 	}
 
+	fmt.Printf("Creating node pool '%s' in cluster '%s'...\n", nodepoolName, clusterName)
+
 	// Start the create operation (long-running operation)
 	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, clusterName, nodepoolName, nodePool, nil)
 	if err != nil {
 		return fmt.Errorf("failed to start node pool create operation: %w", err)
 	}
 
+	if noWait {
+		fmt.Printf("Node pool '%s' is being created (running in background)\n", nodepoolName)
+		return nil
+	}
+
 	fmt.Println("Node pool create operation started. Waiting for completion...")
 
 	// Wait for the operation to complete
@@ -65,3 +102,112 @@ func Add(ctx context.Context, clusterName, nodepoolName, resourceGroup string, n
 	fmt.Printf("Successfully created node pool '%s' with %d nodes\n", nodepoolName, count)
 	return nil
 }
+
+// buildPool assembles the node pool add sends from its flags. A snapshot
+// supplies the VM size, OS and Kubernetes version unless flags override them.
+func buildPool(cmd *cobra.Command, snap *armcontainerservice.Snapshot) (armcontainerservice.AgentPool, error) {
+	flags := cmd.Flags()
+	nodeCount, _ := flags.GetInt32("node-count")
+	vmSize, _ := flags.GetString("node-vm-size")
+
+	props := &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+		Count:  &nodeCount,
+		VMSize: &vmSize,
+		OSType: azure.GetOSType("Linux"),
+		Mode:   azure.GetAgentPoolMode("User"),
+	}
+	if snap != nil && snap.Properties != nil {
+		sp := snap.Properties
+		props.CreationData = &armcontainerservice.CreationData{SourceResourceID: snap.ID}
+		if !flags.Changed("node-vm-size") && sp.VMSize != nil {
+			props.VMSize = sp.VMSize
+		}
+		if sp.OSType != nil {
+			props.OSType = sp.OSType
+		}
+		props.OSSKU = sp.OSSKU
+		props.OrchestratorVersion = sp.KubernetesVersion
+		props.EnableFIPS = sp.EnableFIPS
+	}
+
+	if v, _ := flags.GetString("os-type"); v != "" {
+		osType, err := enumflag.Parse("os-type", v, armcontainerservice.PossibleOSTypeValues())
+		if err != nil {
+			return armcontainerservice.AgentPool{}, err
+		}
+		props.OSType = &osType
+	}
+	if v, _ := flags.GetString("os-sku"); v != "" {
+		osSKU, err := enumflag.Parse("os-sku", v, armcontainerservice.PossibleOSSKUValues())
+		if err != nil {
+			return armcontainerservice.AgentPool{}, err
+		}
+		props.OSSKU = &osSKU
+	}
+	if v, _ := flags.GetString("kubernetes-version"); v != "" {
+		props.OrchestratorVersion = to.Ptr(v)
+	}
+	if zones, _ := flags.GetStringSlice("zones"); len(zones) > 0 {
+		props.AvailabilityZones = to.SliceOfPtrs(zones...)
+	}
+	if v, _ := flags.GetString("vnet-subnet-id"); v != "" {
+		props.VnetSubnetID = to.Ptr(v)
+	}
+	if v, _ := flags.GetString("pod-subnet-id"); v != "" {
+		props.PodSubnetID = to.Ptr(v)
+	}
+
+	if err := applyPoolFlags(cmd, props); err != nil {
+		return armcontainerservice.AgentPool{}, err
+	}
+	if err := applyPriority(cmd, props); err != nil {
+		return armcontainerservice.AgentPool{}, err
+	}
+	enable, _ := flags.GetBool("enable-cluster-autoscaler")
+	if err := applyAutoscaler(props, enable, false, countFlag(cmd, "min-count"), countFlag(cmd, "max-count")); err != nil {
+		return armcontainerservice.AgentPool{}, err
+	}
+	return armcontainerservice.AgentPool{Properties: props}, nil
+}
+
+// applyPriority sets up a Spot pool. The eviction policy and max price only
+// mean something for Spot, so they are rejected on Regular pools.
+func applyPriority(cmd *cobra.Command, props *armcontainerservice.ManagedClusterAgentPoolProfileProperties) error {
+	flags := cmd.Flags()
+	priority := armcontainerservice.ScaleSetPriorityRegular
+	if v, _ := flags.GetString("priority"); v != "" {
+		p, err := enumflag.Parse("priority", v, armcontainerservice.PossibleScaleSetPriorityValues())
+		if err != nil {
+			return err
+		}
+		priority = p
+	}
+	if priority != armcontainerservice.ScaleSetPrioritySpot {
+		if flags.Changed("eviction-policy") || flags.Changed("spot-max-price") {
+			return fmt.Errorf("--eviction-policy and --spot-max-price require --priority Spot")
+		}
+		return nil
+	}
+
+	if props.Mode != nil && *props.Mode == armcontainerservice.AgentPoolModeSystem {
+		return fmt.Errorf("a Spot node pool cannot be a System pool")
+	}
+	props.ScaleSetPriority = to.Ptr(priority)
+	policy := armcontainerservice.ScaleSetEvictionPolicyDelete
+	if v, _ := flags.GetString("eviction-policy"); v != "" {
+		p, err := enumflag.Parse("eviction-policy", v, armcontainerservice.PossibleScaleSetEvictionPolicyValues())
+		if err != nil {
+			return err
+		}
+		policy = p
+	}
+	props.ScaleSetEvictionPolicy = to.Ptr(policy)
+	// -1 caps the price at the on-demand price, so nodes are only evicted
+	// for capacity.
+	price, _ := flags.GetFloat32("spot-max-price")
+	if price != -1 && price <= 0 {
+		return fmt.Errorf("--spot-max-price must be -1 (on-demand price) or a positive price in US dollars")
+	}
+	props.SpotMaxPrice = to.Ptr(price)
+	return nil
+}
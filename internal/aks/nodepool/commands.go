@@ -3,6 +3,8 @@ package nodepool
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/internal/aks/preflight"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
@@ -67,13 +69,20 @@ func NewNodePoolCommand() *cobra.Command {
 	addCmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add a new node pool to an AKS cluster",
+		Long: `Add a new node pool to an AKS cluster.
+
+--node-vm-size is checked against the sizes the cluster's region offers the
+subscription before anything is created. With --snapshot-id the pool starts
+from a node pool snapshot, taking its VM size, OS and Kubernetes version
+unless they are given.`,
+		Example: `  az aks nodepool add -g rg --cluster-name aks1 -n spot1 --priority Spot --eviction-policy Delete \
+    --spot-max-price -1 --zones 1,2,3 --enable-cluster-autoscaler --min-count 0 --max-count 10 --node-count 1`,
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName, _ := cmd.Flags().GetString("cluster-name")
 			nodepoolName, _ := cmd.Flags().GetString("name")
 			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			nodeCount, _ := cmd.Flags().GetInt32("node-count")
-			vmSize, _ := cmd.Flags().GetString("node-vm-size")
-			return Add(context.Background(), clusterName, nodepoolName, resourceGroup, nodeCount, vmSize)
+			return Add(context.Background(), cmd, clusterName, nodepoolName, resourceGroup)
 		},
 	}
 	addCmd.Flags().String("cluster-name", "", "AKS cluster name")
@@ -81,10 +90,54 @@ func NewNodePoolCommand() *cobra.Command {
 	addCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
 	addCmd.Flags().Int32("node-count", 3, "Number of nodes")
 	addCmd.Flags().String("node-vm-size", "Standard_DS2_v2", "VM size for nodes")
+	addCmd.Flags().StringP("kubernetes-version", "k", "", "Kubernetes version (default: the control plane's)")
+	addCmd.Flags().String("os-type", "", "OS type: Linux or Windows")
+	addCmd.Flags().String("os-sku", "", "OS SKU, e.g. Ubuntu, AzureLinux, Windows2022")
+	addCmd.Flags().StringSlice("zones", nil, "Availability zones, e.g. 1,2,3")
+	addCmd.Flags().String("priority", "", "Scale set priority: Regular or Spot")
+	addCmd.Flags().String("eviction-policy", "", "Spot eviction policy: Delete (default) or Deallocate")
+	addCmd.Flags().Float32("spot-max-price", -1, "Spot max price in US dollars; -1 caps it at the on-demand price")
+	addCmd.Flags().String("snapshot-id", "", "Resource ID of a node pool snapshot to create the pool from")
+	addCmd.Flags().String("vnet-subnet-id", "", "Resource ID of the subnet for the nodes")
+	addCmd.Flags().String("pod-subnet-id", "", "Resource ID of the subnet pods get their IPs from (Azure CNI dynamic IP allocation)")
+	addPoolFlags(addCmd)
+	addCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
 	addCmd.MarkFlagRequired("cluster-name")
 	addCmd.MarkFlagRequired("name")
 	addCmd.MarkFlagRequired("resource-group")
 
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the settings of a node pool",
+		Long: `Update the settings of a node pool. Only the flags given are changed.
+
+AKS decides which settings an existing pool can change; it rejects, for
+example, a new --max-pods on pools that don't support it.`,
+		Example: `  az aks nodepool update -g rg --cluster-name aks1 -n np1 --enable-cluster-autoscaler --min-count 1 --max-count 5
+  az aks nodepool update -g rg --cluster-name aks1 -n np1 --node-taints sku=gpu:NoSchedule --labels team=ml`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, _ := cmd.Flags().GetString("cluster-name")
+			nodepoolName, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			return Update(context.Background(), cmd, clusterName, nodepoolName, resourceGroup)
+		},
+	}
+	updateCmd.Flags().String("cluster-name", "", "AKS cluster name")
+	updateCmd.Flags().StringP("name", "n", "", "Node pool name")
+	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addPoolFlags(updateCmd)
+	updateCmd.Flags().Bool("disable-cluster-autoscaler", false, "Disable the cluster autoscaler on the node pool")
+	updateCmd.MarkFlagsMutuallyExclusive("enable-cluster-autoscaler", "disable-cluster-autoscaler")
+	genericupdate.AddFlags(updateCmd)
+	updateCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+	updateCmd.MarkFlagRequired("cluster-name")
+	updateCmd.MarkFlagRequired("name")
+	updateCmd.MarkFlagRequired("resource-group")
+
+	startCmd := newPowerCmd("start", "Start a stopped node pool", Start)
+	stopCmd := newPowerCmd("stop", "Stop a node pool, deallocating its nodes", Stop)
+
 	deleteCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete a node pool from an AKS cluster",
@@ -188,6 +241,62 @@ func NewNodePoolCommand() *cobra.Command {
 	waitCmd.MarkFlagRequired("name")
 	waitCmd.MarkFlagRequired("resource-group")
 
-	cmd.AddCommand(listCmd, showCmd, scaleCmd, addCmd, deleteCmd, getUpgradesCmd, operationAbortCmd, deleteMachinesCmd, waitCmd)
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade a node pool to a newer Kubernetes version or node image",
+		Long: `Upgrade a node pool to a newer Kubernetes version (by default the control
+plane's) or, with --node-image-only, to the latest node image.
+
+Before starting, a pre-flight report checks for PodDisruptionBudgets that
+would stop nodes draining and vCPU quota for the surge nodes. A failed
+check stops the upgrade unless --force is given. The PodDisruptionBudget
+check runs kubectl through az aks command invoke; if that fails, it is
+reported as unknown with a warning and does not block the upgrade.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, _ := cmd.Flags().GetString("cluster-name")
+			nodepoolName, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			return Upgrade(context.Background(), cmd, clusterName, nodepoolName, resourceGroup)
+		},
+	}
+	upgradeCmd.Flags().String("cluster-name", "", "AKS cluster name")
+	upgradeCmd.Flags().StringP("name", "n", "", "Node pool name")
+	upgradeCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	upgradeCmd.Flags().StringP("kubernetes-version", "k", "", "Version to upgrade to (default: the control plane's version)")
+	upgradeCmd.Flags().Bool("node-image-only", false, "Upgrade only the node image")
+	upgradeCmd.Flags().String("max-surge", "", "Extra nodes added during the upgrade, as a count (5) or a percentage of the pool (33%)")
+	upgradeCmd.Flags().Int32("drain-timeout", 0, "Minutes to wait for pods to be evicted from each node before failing")
+	upgradeCmd.Flags().Int32("node-soak-duration", 0, "Minutes to wait after draining a node before reimaging it")
+	preflight.AddFlags(upgradeCmd)
+	upgradeCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+	upgradeCmd.MarkFlagRequired("cluster-name")
+	upgradeCmd.MarkFlagRequired("name")
+	upgradeCmd.MarkFlagRequired("resource-group")
+	upgradeCmd.MarkFlagsMutuallyExclusive("node-image-only", "kubernetes-version")
+
+	cmd.AddCommand(listCmd, showCmd, scaleCmd, addCmd, updateCmd, startCmd, stopCmd, deleteCmd, getUpgradesCmd, upgradeCmd, operationAbortCmd, deleteMachinesCmd, waitCmd)
 	return cmd
 }
+
+func newPowerCmd(use, short string, action func(context.Context, string, string, string, bool) error) *cobra.Command {
+	c := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, _ := cmd.Flags().GetString("cluster-name")
+			nodepoolName, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			noWait, _ := cmd.Flags().GetBool("no-wait")
+			return action(context.Background(), clusterName, nodepoolName, resourceGroup, noWait)
+		},
+	}
+	c.Flags().String("cluster-name", "", "AKS cluster name")
+	c.Flags().StringP("name", "n", "", "Node pool name")
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	c.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+	c.MarkFlagRequired("cluster-name")
+	c.MarkFlagRequired("name")
+	c.MarkFlagRequired("resource-group")
+	return c
+}
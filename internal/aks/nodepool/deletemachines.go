@@ -23,7 +23,7 @@ func DeleteMachines(ctx context.Context, cmd *cobra.Command, clusterName, nodepo
 		return err
 	}
 
-	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create agent pools client: %w", err)
 	}
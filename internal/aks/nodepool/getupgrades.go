@@ -22,7 +22,7 @@ func GetUpgrades(ctx context.Context, cmd *cobra.Command, clusterName, nodepoolN
 		return err
 	}
 
-	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create agent pools client: %w", err)
 	}
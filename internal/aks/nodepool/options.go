@@ -0,0 +1,178 @@
+package nodepool
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/enumflag"
+	"github.com/spf13/cobra"
+)
+
+// taintEffects are the effects Kubernetes accepts on a node taint.
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// addPoolFlags registers the flags add and update share. Each only touches
+// the pool when given, so update leaves everything else alone.
+func addPoolFlags(cmd *cobra.Command) {
+	cmd.Flags().String("mode", "", "Node pool mode: System or User")
+	cmd.Flags().Int32("max-pods", 0, "Maximum pods per node")
+	cmd.Flags().String("node-taints", "", "Comma-separated taints as key=value:Effect; \"\" clears them on update")
+	cmd.Flags().StringToString("labels", nil, "Space-separated node labels: key1=value1 key2=value2")
+	cmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
+	cmd.Flags().Bool("enable-cluster-autoscaler", false, "Enable the cluster autoscaler on the node pool")
+	cmd.Flags().Int32("min-count", 0, "Minimum node count for the cluster autoscaler")
+	cmd.Flags().Int32("max-count", 0, "Maximum node count for the cluster autoscaler")
+}
+
+// applyPoolFlags applies the shared flags that were given to props.
+func applyPoolFlags(cmd *cobra.Command, props *armcontainerservice.ManagedClusterAgentPoolProfileProperties) error {
+	flags := cmd.Flags()
+	if flags.Changed("mode") {
+		v, _ := flags.GetString("mode")
+		mode, err := enumflag.Parse("mode", v, armcontainerservice.PossibleAgentPoolModeValues())
+		if err != nil {
+			return err
+		}
+		props.Mode = &mode
+	}
+	if flags.Changed("max-pods") {
+		v, _ := flags.GetInt32("max-pods")
+		if v < 10 || v > 250 {
+			return fmt.Errorf("--max-pods must be between 10 and 250")
+		}
+		props.MaxPods = to.Ptr(v)
+	}
+	if flags.Changed("node-taints") {
+		v, _ := flags.GetString("node-taints")
+		taints, err := parseTaints(v)
+		if err != nil {
+			return err
+		}
+		props.NodeTaints = taints
+	}
+	if flags.Changed("labels") {
+		labels, _ := flags.GetStringToString("labels")
+		props.NodeLabels = toPtrMap(labels)
+	}
+	if flags.Changed("tags") {
+		tags, _ := flags.GetStringToString("tags")
+		props.Tags = toPtrMap(tags)
+	}
+	return nil
+}
+
+// applyAutoscaler turns the autoscaler on with --min-count and --max-count,
+// changes the counts of one already on, or turns it off. On updates, counts
+// not given keep their current values.
+func applyAutoscaler(props *armcontainerservice.ManagedClusterAgentPoolProfileProperties, enable, disable bool, minCount, maxCount *int32) error {
+	enabled := props.EnableAutoScaling != nil && *props.EnableAutoScaling
+	switch {
+	case disable:
+		if minCount != nil || maxCount != nil {
+			return fmt.Errorf("--min-count and --max-count cannot be used with --disable-cluster-autoscaler")
+		}
+		props.EnableAutoScaling = to.Ptr(false)
+		props.MinCount = nil
+		props.MaxCount = nil
+		return nil
+	case enable:
+		if minCount == nil || maxCount == nil {
+			return fmt.Errorf("--enable-cluster-autoscaler requires --min-count and --max-count")
+		}
+	case minCount == nil && maxCount == nil:
+		return nil
+	case !enabled:
+		return fmt.Errorf("--min-count and --max-count require the cluster autoscaler; add --enable-cluster-autoscaler")
+	}
+
+	if minCount != nil {
+		props.MinCount = minCount
+	}
+	if maxCount != nil {
+		props.MaxCount = maxCount
+	}
+	if props.MinCount == nil || props.MaxCount == nil || *props.MinCount < 0 || *props.MaxCount < *props.MinCount || *props.MaxCount < 1 {
+		return fmt.Errorf("the cluster autoscaler needs 0 <= --min-count <= --max-count and --max-count >= 1")
+	}
+	props.EnableAutoScaling = to.Ptr(true)
+	return nil
+}
+
+// countFlag returns a count flag's value, or nil when it wasn't given.
+func countFlag(cmd *cobra.Command, name string) *int32 {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	v, _ := cmd.Flags().GetInt32(name)
+	return &v
+}
+
+// parseTaints validates comma-separated key=value:Effect taints. An empty
+// string yields an empty list, which clears the taints on a PUT.
+func parseTaints(s string) ([]*string, error) {
+	taints := []*string{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		kv, effect, ok := strings.Cut(t, ":")
+		key, _, _ := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid taint %q: expected key=value:Effect", t)
+		}
+		valid := false
+		for _, e := range taintEffects {
+			valid = valid || e == effect
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid taint %q: effect must be one of %s", t, strings.Join(taintEffects, ", "))
+		}
+		taints = append(taints, to.Ptr(t))
+	}
+	return taints, nil
+}
+
+func toPtrMap(m map[string]string) map[string]*string {
+	out := make(map[string]*string, len(m))
+	for k, v := range m {
+		out[k] = to.Ptr(v)
+	}
+	return out
+}
+
+// checkVMSize reports why sku can't be used for a node pool in location and
+// zones: the location doesn't offer it, offers it but not to this
+// subscription, or restricts it in one of the zones.
+func checkVMSize(sku *armcompute.ResourceSKU, vmSize, location string, zones []string) error {
+	if sku == nil {
+		return fmt.Errorf("VM size '%s' is not offered in %s; see 'az vm list-skus -l %s'", vmSize, location, location)
+	}
+	for _, r := range sku.Restrictions {
+		if r == nil || r.Type == nil {
+			continue
+		}
+		reason := "restricted"
+		if r.ReasonCode != nil {
+			reason = string(*r.ReasonCode)
+		}
+		switch *r.Type {
+		case armcompute.ResourceSKURestrictionsTypeLocation:
+			return fmt.Errorf("VM size '%s' is not available in %s (%s); see 'az vm list-skus -l %s'", vmSize, location, reason, location)
+		case armcompute.ResourceSKURestrictionsTypeZone:
+			if r.RestrictionInfo == nil {
+				continue
+			}
+			for _, z := range r.RestrictionInfo.Zones {
+				if z != nil && slices.Contains(zones, *z) {
+					return fmt.Errorf("VM size '%s' is not available in zone %s of %s (%s); see 'az vm list-skus -l %s --size %s'", vmSize, *z, location, reason, location, vmSize)
+				}
+			}
+		}
+	}
+	return nil
+}
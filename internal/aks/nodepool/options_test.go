@@ -0,0 +1,176 @@
+package nodepool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/spf13/cobra"
+)
+
+// subcommand returns a fresh nodepool subcommand with args parsed.
+func subcommand(t *testing.T, name string, args ...string) *cobra.Command {
+	t.Helper()
+	cmd, _, err := NewNodePoolCommand().Find([]string{name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestParseTaints(t *testing.T) {
+	got, err := parseTaints("sku=gpu:NoSchedule, dedicated:NoExecute")
+	if err != nil || len(got) != 2 || *got[1] != "dedicated:NoExecute" {
+		t.Fatalf("parseTaints = %v, %v", got, err)
+	}
+	if got, err := parseTaints(""); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("parseTaints(\"\") = %v, %v; want an empty list", got, err)
+	}
+	for _, bad := range []string{"sku=gpu", "sku=gpu:Never", "=gpu:NoSchedule"} {
+		if _, err := parseTaints(bad); err == nil {
+			t.Errorf("parseTaints(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestApplyAutoscaler(t *testing.T) {
+	i := func(v int32) *int32 { return &v }
+	enabled := func() *armcontainerservice.ManagedClusterAgentPoolProfileProperties {
+		return &armcontainerservice.ManagedClusterAgentPoolProfileProperties{EnableAutoScaling: to.Ptr(true), MinCount: i(1), MaxCount: i(5)}
+	}
+
+	props := &armcontainerservice.ManagedClusterAgentPoolProfileProperties{}
+	if err := applyAutoscaler(props, true, false, i(0), i(3)); err != nil || !*props.EnableAutoScaling || *props.MaxCount != 3 {
+		t.Errorf("enable: err=%v props=%+v", err, props)
+	}
+
+	props = enabled()
+	if err := applyAutoscaler(props, false, false, nil, i(8)); err != nil || *props.MinCount != 1 || *props.MaxCount != 8 {
+		t.Errorf("update max only: err=%v min=%d max=%d", err, *props.MinCount, *props.MaxCount)
+	}
+
+	props = enabled()
+	if err := applyAutoscaler(props, false, true, nil, nil); err != nil || *props.EnableAutoScaling || props.MinCount != nil {
+		t.Errorf("disable: err=%v props=%+v", err, props)
+	}
+
+	tests := []struct {
+		name     string
+		props    *armcontainerservice.ManagedClusterAgentPoolProfileProperties
+		enable   bool
+		disable  bool
+		min, max *int32
+	}{
+		{"enable without counts", &armcontainerservice.ManagedClusterAgentPoolProfileProperties{}, true, false, i(1), nil},
+		{"counts while off", &armcontainerservice.ManagedClusterAgentPoolProfileProperties{}, false, false, i(1), i(3)},
+		{"min above max", enabled(), false, false, i(6), nil},
+		{"disable with counts", enabled(), false, true, i(1), nil},
+	}
+	for _, tt := range tests {
+		if err := applyAutoscaler(tt.props, tt.enable, tt.disable, tt.min, tt.max); err == nil {
+			t.Errorf("%s: applyAutoscaler succeeded, want error", tt.name)
+		}
+	}
+}
+
+func TestBuildPool(t *testing.T) {
+	cmd := subcommand(t, "add", "--priority", "spot", "--eviction-policy", "deallocate", "--zones", "1,2",
+		"--os-sku", "azurelinux", "--pod-subnet-id", "/subscriptions/s/.../subnets/pods",
+		"--node-taints", "sku=gpu:NoSchedule", "--labels", "team=ml", "--max-pods", "50",
+		"--enable-cluster-autoscaler", "--min-count", "0", "--max-count", "4")
+	pool, err := buildPool(cmd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pool.Properties
+	if *p.ScaleSetPriority != armcontainerservice.ScaleSetPrioritySpot || *p.ScaleSetEvictionPolicy != armcontainerservice.ScaleSetEvictionPolicyDeallocate || *p.SpotMaxPrice != -1 {
+		t.Errorf("spot settings = %s/%s/%v", *p.ScaleSetPriority, *p.ScaleSetEvictionPolicy, *p.SpotMaxPrice)
+	}
+	if len(p.AvailabilityZones) != 2 || *p.OSSKU != armcontainerservice.OSSKUAzureLinux || p.PodSubnetID == nil {
+		t.Errorf("zones/os sku/pod subnet = %v/%s/%v", p.AvailabilityZones, *p.OSSKU, p.PodSubnetID)
+	}
+	if len(p.NodeTaints) != 1 || *p.NodeLabels["team"] != "ml" || *p.MaxPods != 50 || !*p.EnableAutoScaling || *p.Mode != armcontainerservice.AgentPoolModeUser {
+		t.Errorf("pool = %+v", p)
+	}
+
+	snap := &armcontainerservice.Snapshot{
+		ID: to.Ptr("/subscriptions/s/resourceGroups/rg/providers/Microsoft.ContainerService/snapshots/snap1"),
+		Properties: &armcontainerservice.SnapshotProperties{
+			VMSize: to.Ptr("Standard_D8s_v5"), KubernetesVersion: to.Ptr("1.29.8"), OSSKU: to.Ptr(armcontainerservice.OSSKUUbuntu),
+		},
+	}
+	pool, err = buildPool(subcommand(t, "add", "-k", "1.30.4"), snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = pool.Properties
+	if *p.VMSize != "Standard_D8s_v5" || *p.OrchestratorVersion != "1.30.4" || *p.CreationData.SourceResourceID != *snap.ID {
+		t.Errorf("snapshot pool = size %s, version %s, source %v", *p.VMSize, *p.OrchestratorVersion, p.CreationData)
+	}
+}
+
+func TestBuildPoolRejects(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--eviction-policy", "Delete"}, "--priority Spot"},
+		{[]string{"--priority", "Spot", "--mode", "System"}, "System"},
+		{[]string{"--priority", "Spot", "--spot-max-price", "0"}, "--spot-max-price"},
+		{[]string{"--os-sku", "Gentoo"}, "allowed values"},
+		{[]string{"--max-pods", "500"}, "--max-pods"},
+	}
+	for _, tt := range tests {
+		_, err := buildPool(subcommand(t, "add", tt.args...), nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("buildPool(%v) error = %v, want one mentioning %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestCheckVMSize(t *testing.T) {
+	if err := checkVMSize(nil, "Standard_X", "westeurope", nil); err == nil || !strings.Contains(err.Error(), "not offered") {
+		t.Errorf("checkVMSize(nil) = %v", err)
+	}
+	restricted := &armcompute.ResourceSKU{Restrictions: []*armcompute.ResourceSKURestrictions{{
+		Type:       to.Ptr(armcompute.ResourceSKURestrictionsTypeLocation),
+		ReasonCode: to.Ptr(armcompute.ResourceSKURestrictionsReasonCodeNotAvailableForSubscription),
+	}}}
+	if err := checkVMSize(restricted, "Standard_X", "westeurope", nil); err == nil || !strings.Contains(err.Error(), "NotAvailableForSubscription") {
+		t.Errorf("checkVMSize(restricted) = %v", err)
+	}
+	zonal := &armcompute.ResourceSKU{Restrictions: []*armcompute.ResourceSKURestrictions{{
+		Type:            to.Ptr(armcompute.ResourceSKURestrictionsTypeZone),
+		ReasonCode:      to.Ptr(armcompute.ResourceSKURestrictionsReasonCodeNotAvailableForSubscription),
+		RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Zones: to.SliceOfPtrs("3")},
+	}}}
+	if err := checkVMSize(zonal, "Standard_X", "westeurope", nil); err != nil {
+		t.Errorf("checkVMSize(zone restriction, no zones) = %v, want nil", err)
+	}
+	if err := checkVMSize(zonal, "Standard_X", "westeurope", []string{"1", "2"}); err != nil {
+		t.Errorf("checkVMSize(zone restriction, other zones) = %v, want nil", err)
+	}
+	if err := checkVMSize(zonal, "Standard_X", "westeurope", []string{"1", "2", "3"}); err == nil || !strings.Contains(err.Error(), "zone 3") {
+		t.Errorf("checkVMSize(restricted zone) = %v, want an error naming zone 3", err)
+	}
+}
+
+func TestApplyUpdateFlags(t *testing.T) {
+	props := &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+		NodeTaints: []*string{to.Ptr("a=b:NoSchedule")},
+		NodeLabels: map[string]*string{"team": to.Ptr("web")},
+		Mode:       to.Ptr(armcontainerservice.AgentPoolModeUser),
+	}
+	cmd := subcommand(t, "update", "--node-taints", "", "--mode", "system")
+	if err := applyUpdateFlags(cmd, props); err != nil {
+		t.Fatal(err)
+	}
+	if props.NodeTaints == nil || len(props.NodeTaints) != 0 || *props.Mode != armcontainerservice.AgentPoolModeSystem || *props.NodeLabels["team"] != "web" {
+		t.Errorf("props = taints %v, mode %s, labels %v", props.NodeTaints, *props.Mode, props.NodeLabels)
+	}
+}
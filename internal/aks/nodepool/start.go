@@ -0,0 +1,69 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+func Start(ctx context.Context, clusterName, nodepoolName, resourceGroup string, noWait bool) error {
+	return setPowerState(ctx, clusterName, nodepoolName, resourceGroup, armcontainerservice.CodeRunning, noWait)
+}
+
+// setPowerState starts or stops a pool. Agent pools have no start or stop
+// action; the power state is set on the pool itself.
+func setPowerState(ctx context.Context, clusterName, nodepoolName, resourceGroup string, code armcontainerservice.Code, noWait bool) error {
+	verb, done := "Starting", "Started"
+	if code == armcontainerservice.CodeStopped {
+		verb, done = "Stopping", "Stopped"
+	}
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create agent pools client: %w", err)
+	}
+
+	pool, err := client.Get(ctx, resourceGroup, clusterName, nodepoolName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get node pool: %w", err)
+	}
+	if pool.Properties == nil {
+		return fmt.Errorf("node pool '%s' has no properties", nodepoolName)
+	}
+	if ps := pool.Properties.PowerState; ps != nil && ps.Code != nil && *ps.Code == code {
+		fmt.Printf("Node pool '%s' is already %s\n", nodepoolName, code)
+		return nil
+	}
+	pool.Properties.PowerState = &armcontainerservice.PowerState{Code: &code}
+
+	fmt.Printf("%s node pool '%s'...\n", verb, nodepoolName)
+	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, clusterName, nodepoolName, pool.AgentPool, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin %s: %w", verb, err)
+	}
+
+	if noWait {
+		fmt.Printf("%s node pool '%s' (running in background)\n", verb, nodepoolName)
+		return nil
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("failed to %s node pool: %w", code, err)
+	}
+
+	fmt.Printf("%s node pool '%s'\n", done, nodepoolName)
+	return nil
+}
@@ -0,0 +1,13 @@
+package nodepool
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+)
+
+// Stop deallocates a pool's nodes. AKS only stops User pools on
+// VMSS-backed clusters; it rejects stopping the last System pool.
+func Stop(ctx context.Context, clusterName, nodepoolName, resourceGroup string, noWait bool) error {
+	return setPowerState(ctx, clusterName, nodepoolName, resourceGroup, armcontainerservice.CodeStopped, noWait)
+}
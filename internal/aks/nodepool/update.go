@@ -0,0 +1,73 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// Update reads the node pool, applies the given flags and PUTs it back.
+func Update(ctx context.Context, cmd *cobra.Command, clusterName, nodepoolName, resourceGroup string) error {
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create agent pools client: %w", err)
+	}
+
+	pool, err := client.Get(ctx, resourceGroup, clusterName, nodepoolName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get node pool: %w", err)
+	}
+	if pool.Properties == nil {
+		return fmt.Errorf("node pool '%s' has no properties", nodepoolName)
+	}
+
+	if err := applyUpdateFlags(cmd, pool.Properties); err != nil {
+		return err
+	}
+	if err := genericupdate.ApplyFlags(cmd, &pool.AgentPool); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updating node pool '%s'...\n", nodepoolName)
+	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, clusterName, nodepoolName, pool.AgentPool, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start node pool update: %w", err)
+	}
+
+	if noWait {
+		return output.PrintJSON(cmd, map[string]string{"status": "node pool update started"})
+	}
+
+	result, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("node pool update failed: %w", err)
+	}
+	return output.PrintJSON(cmd, result.AgentPool)
+}
+
+func applyUpdateFlags(cmd *cobra.Command, props *armcontainerservice.ManagedClusterAgentPoolProfileProperties) error {
+	if err := applyPoolFlags(cmd, props); err != nil {
+		return err
+	}
+	enable, _ := cmd.Flags().GetBool("enable-cluster-autoscaler")
+	disable, _ := cmd.Flags().GetBool("disable-cluster-autoscaler")
+	return applyAutoscaler(props, enable, disable, countFlag(cmd, "min-count"), countFlag(cmd, "max-count"))
+}
@@ -0,0 +1,187 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/preflight"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func Upgrade(ctx context.Context, cmd *cobra.Command, clusterName, nodepoolName, resourceGroup string) error {
+	target, _ := cmd.Flags().GetString("kubernetes-version")
+	nodeImageOnly, _ := cmd.Flags().GetBool("node-image-only")
+	skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	clusters, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AKS client: %w", err)
+	}
+	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create agent pools client: %w", err)
+	}
+
+	cluster, err := clusters.Get(ctx, resourceGroup, clusterName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+	pool, err := client.Get(ctx, resourceGroup, clusterName, nodepoolName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get node pool: %w", err)
+	}
+	if pool.Properties == nil {
+		return fmt.Errorf("node pool '%s' has no properties", nodepoolName)
+	}
+	props := pool.Properties
+
+	settingsChanged, err := applyUpgradeSettings(cmd, props)
+	if err != nil {
+		return err
+	}
+
+	if !nodeImageOnly {
+		// A pool can't run ahead of the control plane, so that is the default.
+		if target == "" && cluster.Properties != nil {
+			target = azure.GetStringValue(cluster.Properties.KubernetesVersion)
+		}
+		profile, err := client.GetUpgradeProfile(ctx, resourceGroup, clusterName, nodepoolName, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get node pool upgrade profile: %w", err)
+		}
+		if err := preflight.ValidateTarget(azure.GetStringValue(props.CurrentOrchestratorVersion), poolUpgrades(profile.AgentPoolUpgradeProfile), target); err != nil {
+			return err
+		}
+	}
+
+	if !skipPreflight {
+		p := preflight.Pool{
+			Name:   nodepoolName,
+			VMSize: azure.GetStringValue(props.VMSize),
+			Spot:   props.ScaleSetPriority != nil && *props.ScaleSetPriority == armcontainerservice.ScaleSetPrioritySpot,
+		}
+		if props.Count != nil {
+			p.Count = *props.Count
+		}
+		if props.UpgradeSettings != nil {
+			p.MaxSurge = azure.GetStringValue(props.UpgradeSettings.MaxSurge)
+		}
+		report := preflight.Run(ctx, preflight.Options{
+			SubscriptionID: subscriptionID,
+			ResourceGroup:  resourceGroup,
+			Cluster:        clusterName,
+			Location:       azure.GetStringValue(cluster.Location),
+			Pools:          []preflight.Pool{p},
+		})
+		if err := preflight.Enforce(cmd, report); err != nil {
+			return err
+		}
+	}
+
+	if nodeImageOnly {
+		// The node image upgrade takes no body, so new settings are saved first.
+		if settingsChanged {
+			fmt.Printf("Saving upgrade settings of node pool '%s'...\n", nodepoolName)
+			poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, clusterName, nodepoolName, pool.AgentPool, nil)
+			if err != nil {
+				return fmt.Errorf("failed to start node pool update: %w", err)
+			}
+			if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+				return fmt.Errorf("node pool update failed: %w", err)
+			}
+		}
+
+		fmt.Printf("Upgrading node image of node pool '%s'...\n", nodepoolName)
+		poller, err := client.BeginUpgradeNodeImageVersion(ctx, resourceGroup, clusterName, nodepoolName, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start node image upgrade: %w", err)
+		}
+		if noWait {
+			return output.PrintJSON(cmd, map[string]string{"status": "node image upgrade started"})
+		}
+		result, err := poller.PollUntilDone(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("node image upgrade failed: %w", err)
+		}
+		return output.PrintJSON(cmd, result.AgentPool)
+	}
+
+	props.OrchestratorVersion = to.Ptr(target)
+	fmt.Printf("Upgrading node pool '%s' to Kubernetes %s...\n", nodepoolName, target)
+	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, clusterName, nodepoolName, pool.AgentPool, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start node pool upgrade: %w", err)
+	}
+	if noWait {
+		return output.PrintJSON(cmd, map[string]string{"status": "node pool upgrade started"})
+	}
+	result, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("node pool upgrade failed: %w", err)
+	}
+	return output.PrintJSON(cmd, result.AgentPool)
+}
+
+// applyUpgradeSettings copies --max-surge, --drain-timeout and
+// --node-soak-duration onto the pool, reporting whether any was given.
+func applyUpgradeSettings(cmd *cobra.Command, props *armcontainerservice.ManagedClusterAgentPoolProfileProperties) (bool, error) {
+	flags := cmd.Flags()
+	if !flags.Changed("max-surge") && !flags.Changed("drain-timeout") && !flags.Changed("node-soak-duration") {
+		return false, nil
+	}
+	if props.UpgradeSettings == nil {
+		props.UpgradeSettings = &armcontainerservice.AgentPoolUpgradeSettings{}
+	}
+	settings := props.UpgradeSettings
+	if flags.Changed("max-surge") {
+		v, _ := flags.GetString("max-surge")
+		if _, err := preflight.SurgeNodes(v, 1); err != nil {
+			return false, fmt.Errorf("invalid --max-surge %q: expected a node count such as 5 or a percentage such as 33%%", v)
+		}
+		settings.MaxSurge = to.Ptr(v)
+	}
+	if flags.Changed("drain-timeout") {
+		v, _ := flags.GetInt32("drain-timeout")
+		if v < 1 {
+			return false, fmt.Errorf("--drain-timeout must be at least 1 minute")
+		}
+		settings.DrainTimeoutInMinutes = to.Ptr(v)
+	}
+	if flags.Changed("node-soak-duration") {
+		v, _ := flags.GetInt32("node-soak-duration")
+		if v < 0 {
+			return false, fmt.Errorf("--node-soak-duration cannot be negative")
+		}
+		settings.NodeSoakDurationInMinutes = to.Ptr(v)
+	}
+	return true, nil
+}
+
+func poolUpgrades(profile armcontainerservice.AgentPoolUpgradeProfile) []string {
+	var available []string
+	if profile.Properties == nil {
+		return available
+	}
+	for _, u := range profile.Properties.Upgrades {
+		if u != nil && u.KubernetesVersion != nil {
+			available = append(available, *u.KubernetesVersion)
+		}
+	}
+	return available
+}
@@ -0,0 +1,47 @@
+package nodepool
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+)
+
+func TestApplyUpgradeSettings(t *testing.T) {
+	upgradeCmd := func(args ...string) (bool, *armcontainerservice.ManagedClusterAgentPoolProfileProperties, error) {
+		t.Helper()
+		cmd := NewNodePoolCommand()
+		sub, _, err := cmd.Find([]string{"upgrade"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sub.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		props := &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+			UpgradeSettings: &armcontainerservice.AgentPoolUpgradeSettings{MaxSurge: to.Ptr("10%"), DrainTimeoutInMinutes: to.Ptr[int32](30)},
+		}
+		changed, err := applyUpgradeSettings(sub, props)
+		return changed, props, err
+	}
+
+	changed, props, err := upgradeCmd()
+	if err != nil || changed || *props.UpgradeSettings.MaxSurge != "10%" {
+		t.Errorf("no flags: changed=%v err=%v settings=%+v", changed, err, props.UpgradeSettings)
+	}
+
+	changed, props, err = upgradeCmd("--max-surge", "33%", "--node-soak-duration", "5")
+	if err != nil || !changed {
+		t.Fatalf("changed=%v err=%v", changed, err)
+	}
+	s := props.UpgradeSettings
+	if *s.MaxSurge != "33%" || *s.NodeSoakDurationInMinutes != 5 || *s.DrainTimeoutInMinutes != 30 {
+		t.Errorf("settings = max surge %s, soak %d, drain %d", *s.MaxSurge, *s.NodeSoakDurationInMinutes, *s.DrainTimeoutInMinutes)
+	}
+
+	for _, args := range [][]string{{"--max-surge", "lots"}, {"--drain-timeout", "0"}, {"--node-soak-duration", "-1"}} {
+		if _, _, err := upgradeCmd(args...); err == nil {
+			t.Errorf("applyUpgradeSettings(%v) succeeded, want error", args)
+		}
+	}
+}
@@ -22,7 +22,7 @@ func RotateSigningKeys(ctx context.Context, cmd *cobra.Command, name, resourceGr
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create managed clusters client: %w", err)
 	}
@@ -33,14 +33,15 @@ func Show(ctx context.Context, cmd *cobra.Command, clusterName, resourceGroup, o
 	}
 
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://management.azure.com/.default"},
+		Scopes: []string{azure.ARMScope()},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to acquire token: %w", err)
 	}
 
 	endpoint := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s/operations/%s?api-version=%s",
+		"%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s/operations/%s?api-version=%s",
+		azure.ARMEndpoint(),
 		url.PathEscape(subscriptionID),
 		url.PathEscape(resourceGroup),
 		url.PathEscape(clusterName),
@@ -116,7 +117,7 @@ func ShowLatest(ctx context.Context, cmd *cobra.Command, clusterName, resourceGr
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create clusters client: %w", err)
 	}
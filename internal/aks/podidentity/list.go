@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, clusterName, resourceGroup st
 		return err
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create clusters client: %w", err)
 	}
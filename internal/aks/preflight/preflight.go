@@ -0,0 +1,382 @@
+// Package preflight checks an AKS cluster for the problems that most often
+// make an upgrade fail part-way: workloads still calling APIs the target
+// version removes, PodDisruptionBudgets that stop nodes draining, and too
+// little vCPU quota for the surge nodes.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cdobbyn/azure-go-cli/internal/aks/command"
+	"github.com/cdobbyn/azure-go-cli/internal/quota"
+	"github.com/cdobbyn/azure-go-cli/internal/vm"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/spf13/cobra"
+)
+
+// Check statuses. Unknown means the check itself could not run, which is
+// reported but does not block the upgrade.
+const (
+	StatusPass    = "pass"
+	StatusWarn    = "warn"
+	StatusFail    = "fail"
+	StatusUnknown = "unknown"
+)
+
+// defaultMaxSurge is what AKS uses when a pool has no max surge set.
+const defaultMaxSurge = "10%"
+
+// pdbMarker separates the two outputs of the in-cluster script.
+const pdbMarker = "---pdb---"
+
+// clusterScript collects the deprecated API request counters, which the API
+// server keeps since it last restarted, and every PDB's allowed disruptions.
+const clusterScript = "kubectl get --raw /metrics | grep '^apiserver_requested_deprecated_apis{'; " +
+	"echo '" + pdbMarker + "'; " +
+	"kubectl get pdb -A --no-headers -o custom-columns=NS:.metadata.namespace,NAME:.metadata.name,ALLOWED:.status.disruptionsAllowed,EXPECTED:.status.expectedPods"
+
+// Check is one line of the report.
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Report is the outcome of Run.
+type Report struct {
+	Checks []Check `json:"checks"`
+}
+
+// Pool is a node pool about to be upgraded, and so to surge.
+type Pool struct {
+	Name     string
+	VMSize   string
+	Count    int32
+	MaxSurge string
+	Spot     bool
+}
+
+// Options says what is being upgraded. TargetVersion is empty for node
+// image upgrades, which don't change the API server; Pools is empty for
+// control-plane-only upgrades, which don't drain nodes.
+type Options struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Cluster        string
+	Location       string
+	TargetVersion  string
+	Pools          []Pool
+}
+
+// Failed reports whether any check failed.
+func (r Report) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes the report as a table.
+func (r Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+	for _, c := range r.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Name, c.Status, c.Detail)
+	}
+	return tw.Flush()
+}
+
+// AddFlags registers --skip-preflight and --force on an upgrade command.
+func AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("skip-preflight", false, "Upgrade without running the pre-flight checks")
+	cmd.Flags().Bool("force", false, "Upgrade even if a pre-flight check fails")
+}
+
+// Enforce prints the report and stops the upgrade if a check failed,
+// unless --force was given. Checks that could not run are also warned
+// about on stderr, as the upgrade goes ahead without them.
+func Enforce(cmd *cobra.Command, r Report) error {
+	if len(r.Checks) == 0 {
+		return nil
+	}
+	if err := r.Print(cmd.OutOrStdout()); err != nil {
+		return err
+	}
+	for _, c := range r.Checks {
+		if c.Status == StatusUnknown {
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: pre-flight check %s did not run and will not block the upgrade: %s\n", c.Name, c.Detail)
+		}
+	}
+	if force, _ := cmd.Flags().GetBool("force"); r.Failed() && !force {
+		return fmt.Errorf("pre-flight checks failed; fix the problems above or pass --force to upgrade anyway")
+	}
+	return nil
+}
+
+// Run performs the checks that apply to opts.
+func Run(ctx context.Context, opts Options) Report {
+	var report Report
+	if opts.TargetVersion == "" && len(opts.Pools) == 0 {
+		return report
+	}
+
+	fmt.Printf("Running pre-flight checks against cluster '%s'...\n", opts.Cluster)
+	var metrics, pdbs string
+	result, err := command.Run(ctx, opts.SubscriptionID, opts.ResourceGroup, opts.Cluster, clusterScript)
+	if err == nil && result.Properties != nil {
+		metrics, pdbs, _ = strings.Cut(azure.GetStringValue(result.Properties.Logs), pdbMarker)
+	}
+	unknown := func(name string) Check {
+		return Check{Name: name, Status: StatusUnknown, Detail: fmt.Sprintf("could not run a command in the cluster: %v", err)}
+	}
+
+	if opts.TargetVersion != "" {
+		if err != nil {
+			report.Checks = append(report.Checks, unknown("deprecated-apis"))
+		} else {
+			report.Checks = append(report.Checks, deprecatedAPICheck(parseDeprecatedAPIs(metrics), opts.TargetVersion))
+		}
+	}
+	if len(opts.Pools) > 0 {
+		if err != nil {
+			report.Checks = append(report.Checks, unknown("pod-disruption-budgets"))
+		} else {
+			report.Checks = append(report.Checks, pdbCheck(parseBlockingPDBs(pdbs)))
+		}
+		report.Checks = append(report.Checks, quotaChecks(ctx, opts)...)
+	}
+	return report
+}
+
+// ValidateTarget accepts the current version, which brings lagging node
+// pools up to the control plane or re-applies upgrade settings, or one of
+// the offered upgrades.
+func ValidateTarget(current string, available []string, target string) error {
+	if target == current {
+		return nil
+	}
+	for _, v := range available {
+		if v == target {
+			return nil
+		}
+	}
+	if len(available) == 0 {
+		return fmt.Errorf("cannot upgrade to %s: no upgrades are available from %s", target, current)
+	}
+	return fmt.Errorf("cannot upgrade from %s to %s: available versions are %s", current, target, strings.Join(available, ", "))
+}
+
+// deprecatedAPI is one deprecated API the cluster has been asked for.
+type deprecatedAPI struct {
+	Group          string
+	Version        string
+	Resource       string
+	RemovedRelease string
+}
+
+func (a deprecatedAPI) String() string {
+	gv := a.Version
+	if a.Group != "" {
+		gv = a.Group + "/" + a.Version
+	}
+	return gv + " " + a.Resource
+}
+
+var metricLabel = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseDeprecatedAPIs reads apiserver_requested_deprecated_apis samples.
+// The API server reports every deprecated API it serves with value 1 once
+// something has requested it.
+func parseDeprecatedAPIs(metrics string) []deprecatedAPI {
+	seen := map[deprecatedAPI]bool{}
+	var apis []deprecatedAPI
+	for _, line := range strings.Split(metrics, "\n") {
+		line = strings.TrimSpace(line)
+		open, end := strings.Index(line, "{"), strings.LastIndex(line, "}")
+		if !strings.HasPrefix(line, "apiserver_requested_deprecated_apis{") || end < open {
+			continue
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(line[end+1:]), 64); err != nil || v == 0 {
+			continue
+		}
+		labels := map[string]string{}
+		for _, m := range metricLabel.FindAllStringSubmatch(line[open+1:end], -1) {
+			labels[m[1]] = m[2]
+		}
+		api := deprecatedAPI{Group: labels["group"], Version: labels["version"], Resource: labels["resource"], RemovedRelease: labels["removed_release"]}
+		if !seen[api] {
+			seen[api] = true
+			apis = append(apis, api)
+		}
+	}
+	sort.Slice(apis, func(i, j int) bool { return apis[i].String() < apis[j].String() })
+	return apis
+}
+
+// deprecatedAPICheck fails when something still uses an API the target
+// version no longer serves, and warns about ones removed later.
+func deprecatedAPICheck(apis []deprecatedAPI, target string) Check {
+	check := Check{Name: "deprecated-apis", Status: StatusPass, Detail: "no deprecated API requests recorded"}
+	targetMinor, ok := minorVersion(target)
+	var removed, later []string
+	for _, a := range apis {
+		if m, known := minorVersion(a.RemovedRelease); ok && known && m <= targetMinor {
+			removed = append(removed, fmt.Sprintf("%s (removed in %s)", a, a.RemovedRelease))
+		} else {
+			later = append(later, a.String())
+		}
+	}
+	switch {
+	case len(removed) > 0:
+		check.Status = StatusFail
+		check.Detail = "still requested but not served by " + target + ": " + strings.Join(removed, ", ")
+	case len(later) > 0:
+		check.Status = StatusWarn
+		check.Detail = "deprecated APIs still requested: " + strings.Join(later, ", ")
+	}
+	return check
+}
+
+// minorVersion returns the minor number of a version like 1.29 or 1.29.4.
+func minorVersion(v string) (int, bool) {
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	if len(parts) < 2 {
+		return 0, false
+	}
+	m, err := strconv.Atoi(parts[1])
+	return m, err == nil
+}
+
+// parseBlockingPDBs returns namespace/name for every PDB that currently
+// allows no disruptions while covering some pods; draining a node running
+// one of its pods waits until the drain timeout and then fails.
+func parseBlockingPDBs(out string) []string {
+	var blocking []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		allowed, err1 := strconv.Atoi(fields[2])
+		expected, err2 := strconv.Atoi(fields[3])
+		if err1 == nil && err2 == nil && allowed == 0 && expected > 0 {
+			blocking = append(blocking, fields[0]+"/"+fields[1])
+		}
+	}
+	return blocking
+}
+
+func pdbCheck(blocking []string) Check {
+	if len(blocking) == 0 {
+		return Check{Name: "pod-disruption-budgets", Status: StatusPass, Detail: "no PodDisruptionBudget blocks node drain"}
+	}
+	return Check{Name: "pod-disruption-budgets", Status: StatusFail,
+		Detail: "allow no disruptions, so nodes cannot drain: " + strings.Join(blocking, ", ")}
+}
+
+// SurgeNodes is how many extra nodes a pool of count nodes adds during an
+// upgrade with the given max surge, a node count or a percentage of the
+// pool rounded up.
+func SurgeNodes(maxSurge string, count int32) (int32, error) {
+	if maxSurge == "" {
+		maxSurge = defaultMaxSurge
+	}
+	if pct, ok := strings.CutSuffix(maxSurge, "%"); ok {
+		p, err := strconv.ParseFloat(pct, 64)
+		if err != nil || p <= 0 {
+			return 0, fmt.Errorf("invalid max surge %q", maxSurge)
+		}
+		n := int32(math.Ceil(float64(count) * p / 100))
+		if n < 1 {
+			n = 1
+		}
+		return n, nil
+	}
+	n, err := strconv.Atoi(maxSurge)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid max surge %q", maxSurge)
+	}
+	return int32(n), nil
+}
+
+// quotaNeed is the extra vCPUs the surge draws from one regional quota.
+type quotaNeed struct {
+	resource string
+	vCPUs    int32
+	pools    []string
+}
+
+// quotaChecks compares the vCPUs the surge nodes need against the free
+// regional quota. Pools are counted as if they all surge at once, which is
+// the most AKS can ask for.
+func quotaChecks(ctx context.Context, opts Options) []Check {
+	var checks []Check
+	needs := map[string]*quotaNeed{}
+	var order []string
+	need := func(resource string, vCPUs int32, pool string) {
+		n, ok := needs[resource]
+		if !ok {
+			n = &quotaNeed{resource: resource}
+			needs[resource] = n
+			order = append(order, resource)
+		}
+		n.vCPUs += vCPUs
+		n.pools = append(n.pools, pool)
+	}
+
+	for _, p := range opts.Pools {
+		surge, err := SurgeNodes(p.MaxSurge, p.Count)
+		if err != nil {
+			checks = append(checks, Check{Name: "quota", Status: StatusUnknown, Detail: fmt.Sprintf("pool %s: %v", p.Name, err)})
+			continue
+		}
+		sku, err := vm.FindSKU(ctx, opts.SubscriptionID, opts.Location, p.VMSize)
+		if err != nil || sku == nil {
+			checks = append(checks, Check{Name: "quota", Status: StatusUnknown, Detail: fmt.Sprintf("pool %s: could not look up VM size %s in %s", p.Name, p.VMSize, opts.Location)})
+			continue
+		}
+		perNode, _ := strconv.Atoi(vm.SKUCapability(sku, "vCPUs"))
+		vCPUs := surge * int32(perNode)
+		label := fmt.Sprintf("%s(+%d)", p.Name, surge)
+		if p.Spot {
+			need("lowPriorityCores", vCPUs, label)
+			continue
+		}
+		need("cores", vCPUs, label)
+		if family := azure.GetStringValue(sku.Family); family != "" {
+			need(family, vCPUs, label)
+		}
+	}
+
+	scope := fmt.Sprintf("subscriptions/%s/providers/Microsoft.Compute/locations/%s", opts.SubscriptionID, opts.Location)
+	for _, resource := range order {
+		n := needs[resource]
+		name := "quota " + resource
+		info, err := quota.Usage(ctx, scope, resource)
+		if err != nil {
+			checks = append(checks, Check{Name: name, Status: StatusUnknown, Detail: err.Error()})
+			continue
+		}
+		checks = append(checks, quotaCheck(name, info, n.vCPUs, n.pools))
+	}
+	return checks
+}
+
+func quotaCheck(name string, info quota.QuotaInfo, vCPUs int32, pools []string) Check {
+	free := info.Limit - info.CurrentValue
+	detail := fmt.Sprintf("surge needs %d vCPUs for %s; %d of %d free", vCPUs, strings.Join(pools, ", "), free, info.Limit)
+	if vCPUs > free {
+		return Check{Name: name, Status: StatusFail, Detail: detail}
+	}
+	return Check{Name: name, Status: StatusPass, Detail: detail}
+}
@@ -0,0 +1,155 @@
+package preflight
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cdobbyn/azure-go-cli/internal/quota"
+	"github.com/spf13/cobra"
+)
+
+const sampleMetrics = `apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.32",resource="flowschemas",subresource="",version="v1beta3"} 1
+apiserver_requested_deprecated_apis{group="batch",removed_release="1.25",resource="cronjobs",subresource="",version="v1beta1"} 0
+`
+
+func TestParseDeprecatedAPIs(t *testing.T) {
+	apis := parseDeprecatedAPIs(sampleMetrics + "\nsome other output\n")
+	if len(apis) != 2 {
+		t.Fatalf("parseDeprecatedAPIs found %d APIs, want 2: %+v", len(apis), apis)
+	}
+	if apis[0].String() != "flowcontrol.apiserver.k8s.io/v1beta3 flowschemas" || apis[1].RemovedRelease != "1.25" {
+		t.Errorf("parseDeprecatedAPIs = %+v", apis)
+	}
+}
+
+func TestDeprecatedAPICheck(t *testing.T) {
+	apis := parseDeprecatedAPIs(sampleMetrics)
+	tests := []struct {
+		target string
+		status string
+		want   string
+	}{
+		{"1.24.9", StatusWarn, "policy/v1beta1 podsecuritypolicies"},
+		{"1.25.0", StatusFail, "podsecuritypolicies (removed in 1.25)"},
+		{"1.32.1", StatusFail, "flowschemas (removed in 1.32)"},
+	}
+	for _, tt := range tests {
+		got := deprecatedAPICheck(apis, tt.target)
+		if got.Status != tt.status || !strings.Contains(got.Detail, tt.want) {
+			t.Errorf("deprecatedAPICheck(%s) = %+v, want status %s mentioning %q", tt.target, got, tt.status, tt.want)
+		}
+	}
+	if got := deprecatedAPICheck(nil, "1.30.0"); got.Status != StatusPass {
+		t.Errorf("deprecatedAPICheck(no APIs) = %+v, want pass", got)
+	}
+}
+
+func TestParseBlockingPDBs(t *testing.T) {
+	out := `
+default        web          0        3
+kube-system    coredns      1        2
+prod           db           0        0
+prod           broken       <none>   <none>
+`
+	got := parseBlockingPDBs(out)
+	if len(got) != 1 || got[0] != "default/web" {
+		t.Errorf("parseBlockingPDBs = %v, want [default/web]", got)
+	}
+	if c := pdbCheck(got); c.Status != StatusFail {
+		t.Errorf("pdbCheck = %+v, want fail", c)
+	}
+}
+
+func TestSurgeNodes(t *testing.T) {
+	tests := []struct {
+		maxSurge string
+		count    int32
+		want     int32
+		wantErr  bool
+	}{
+		{"", 20, 2, false},
+		{"", 3, 1, false},
+		{"33%", 10, 4, false},
+		{"100%", 5, 5, false},
+		{"5", 3, 5, false},
+		{"0", 3, 0, true},
+		{"abc", 3, 0, true},
+		{"-10%", 3, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := SurgeNodes(tt.maxSurge, tt.count)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("SurgeNodes(%q, %d) = %d, %v; want %d, error %v", tt.maxSurge, tt.count, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestQuotaCheck(t *testing.T) {
+	info := quota.QuotaInfo{Name: "cores", Limit: 100, CurrentValue: 90}
+	if c := quotaCheck("quota cores", info, 8, []string{"np1(+4)"}); c.Status != StatusPass {
+		t.Errorf("quotaCheck(8 of 10 free) = %+v, want pass", c)
+	}
+	c := quotaCheck("quota cores", info, 12, []string{"np1(+6)"})
+	if c.Status != StatusFail || !strings.Contains(c.Detail, "10 of 100 free") {
+		t.Errorf("quotaCheck(12 of 10 free) = %+v, want fail", c)
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	available := []string{"1.30.4", "1.31.1"}
+	for _, target := range []string{"1.29.8", "1.30.4", "1.31.1"} {
+		if err := ValidateTarget("1.29.8", available, target); err != nil {
+			t.Errorf("ValidateTarget(%s) = %v", target, err)
+		}
+	}
+	if err := ValidateTarget("1.29.8", available, "1.32.0"); err == nil || !strings.Contains(err.Error(), "1.30.4, 1.31.1") {
+		t.Errorf("ValidateTarget(1.32.0) = %v, want error listing available versions", err)
+	}
+	if err := ValidateTarget("1.31.1", nil, "1.32.0"); err == nil {
+		t.Error("ValidateTarget with no upgrades succeeded")
+	}
+}
+
+func TestReportFailed(t *testing.T) {
+	r := Report{Checks: []Check{{Status: StatusPass}, {Status: StatusUnknown}, {Status: StatusWarn}}}
+	if r.Failed() {
+		t.Error("Failed() = true without a failed check")
+	}
+	r.Checks = append(r.Checks, Check{Status: StatusFail})
+	if !r.Failed() {
+		t.Error("Failed() = false with a failed check")
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	newCmd := func(args ...string) (*cobra.Command, *strings.Builder) {
+		cmd := &cobra.Command{}
+		AddFlags(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		var stderr strings.Builder
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(&stderr)
+		return cmd, &stderr
+	}
+
+	unknown := Check{Name: "pod-disruption-budgets", Status: StatusUnknown, Detail: "could not run a command in the cluster: forbidden"}
+	cmd, stderr := newCmd()
+	if err := Enforce(cmd, Report{Checks: []Check{unknown}}); err != nil {
+		t.Errorf("Enforce(unknown) = %v, want the upgrade to go ahead", err)
+	}
+	if !strings.Contains(stderr.String(), "WARNING: pre-flight check pod-disruption-budgets did not run") {
+		t.Errorf("stderr = %q, want a warning about the check that did not run", stderr.String())
+	}
+
+	failed := Report{Checks: []Check{{Name: "quota-cores", Status: StatusFail}}}
+	if cmd, _ := newCmd(); Enforce(cmd, failed) == nil {
+		t.Error("Enforce(failed) succeeded without --force")
+	}
+	if cmd, _ := newCmd("--force"); Enforce(cmd, failed) != nil {
+		t.Error("Enforce(failed) with --force returned an error")
+	}
+}
@@ -6,38 +6,33 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Reconcile(ctx context.Context, name, resourceGroup string, noWait bool) error {
+func Reconcile(ctx context.Context, t bulk.Target, noWait bool) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
 
-	got, err := client.Get(ctx, resourceGroup, name, nil)
+	got, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	fmt.Printf("Reconciling AKS cluster '%s' (no-op PUT)...\n", name)
-	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, name, got.ManagedCluster, nil)
+	fmt.Printf("Reconciling AKS cluster '%s' (no-op PUT)...\n", t.Name)
+	poller, err := client.BeginCreateOrUpdate(ctx, t.ResourceGroup, t.Name, got.ManagedCluster, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin reconcile: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Reconcile initiated for AKS cluster '%s' (running in background)\n", name)
+		fmt.Printf("Reconcile initiated for AKS cluster '%s' (running in background)\n", t.Name)
 		return nil
 	}
 
@@ -45,6 +40,6 @@ func Reconcile(ctx context.Context, name, resourceGroup string, noWait bool) err
 		return fmt.Errorf("failed to reconcile cluster: %w", err)
 	}
 
-	fmt.Printf("Reconciled AKS cluster '%s'\n", name)
+	fmt.Printf("Reconciled AKS cluster '%s'\n", t.Name)
 	return nil
 }
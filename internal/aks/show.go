@@ -7,34 +7,27 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, clusterName, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armcontainerservice.NewManagedClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create AKS client: %w", err)
 	}
 
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	cluster, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create AKS client: %w", err)
-	}
-
-	cluster, err := client.Get(ctx, resourceGroup, clusterName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get AKS cluster: %w", err)
+		return nil, fmt.Errorf("failed to get AKS cluster: %w", err)
 	}
 
 	// Return properties directly for easier querying (no need for 'properties.' prefix)
-	return output.PrintJSON(cmd, cluster.Properties)
+	return cluster.Properties, nil
 }
 
 // Helper function to extract resource group from Azure resource ID
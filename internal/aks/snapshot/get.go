@@ -0,0 +1,36 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+)
+
+// GetByID fetches a snapshot by resource ID, as commands taking
+// --snapshot-id are given it.
+func GetByID(ctx context.Context, id string) (armcontainerservice.Snapshot, error) {
+	parsed, err := arm.ParseResourceID(id)
+	if err != nil || !strings.EqualFold(parsed.ResourceType.String(), "Microsoft.ContainerService/snapshots") {
+		return armcontainerservice.Snapshot{}, fmt.Errorf("invalid snapshot ID %q: expected /subscriptions/.../providers/Microsoft.ContainerService/snapshots/<name>", id)
+	}
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return armcontainerservice.Snapshot{}, err
+	}
+
+	client, err := armcontainerservice.NewSnapshotsClient(parsed.SubscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return armcontainerservice.Snapshot{}, fmt.Errorf("failed to create snapshots client: %w", err)
+	}
+
+	resp, err := client.Get(ctx, parsed.ResourceGroupName, parsed.Name, nil)
+	if err != nil {
+		return armcontainerservice.Snapshot{}, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	return resp.Snapshot, nil
+}
@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, snapshotName, resourceGroup s
 		return err
 	}
 
-	client, err := armcontainerservice.NewSnapshotsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewSnapshotsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create snapshots client: %w", err)
 	}
@@ -6,33 +6,28 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Start(ctx context.Context, name, resourceGroup string, noWait bool) error {
+func Start(ctx context.Context, t bulk.Target, noWait bool) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
 
-	fmt.Printf("Starting AKS cluster '%s'...\n", name)
-	poller, err := client.BeginStart(ctx, resourceGroup, name, nil)
+	fmt.Printf("Starting AKS cluster '%s'...\n", t.Name)
+	poller, err := client.BeginStart(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin start: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started AKS cluster '%s' (running in background)\n", name)
+		fmt.Printf("Started AKS cluster '%s' (running in background)\n", t.Name)
 		return nil
 	}
 
@@ -40,6 +35,6 @@ func Start(ctx context.Context, name, resourceGroup string, noWait bool) error {
 		return fmt.Errorf("failed to start AKS cluster: %w", err)
 	}
 
-	fmt.Printf("Started AKS cluster '%s'\n", name)
+	fmt.Printf("Started AKS cluster '%s'\n", t.Name)
 	return nil
 }
@@ -6,33 +6,28 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Stop(ctx context.Context, name, resourceGroup string, noWait bool) error {
+func Stop(ctx context.Context, t bulk.Target, noWait bool) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewManagedClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
 
-	fmt.Printf("Stopping AKS cluster '%s'...\n", name)
-	poller, err := client.BeginStop(ctx, resourceGroup, name, nil)
+	fmt.Printf("Stopping AKS cluster '%s'...\n", t.Name)
+	poller, err := client.BeginStop(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin stop: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Stopping AKS cluster '%s' (running in background)\n", name)
+		fmt.Printf("Stopping AKS cluster '%s' (running in background)\n", t.Name)
 		return nil
 	}
 
@@ -40,6 +35,6 @@ func Stop(ctx context.Context, name, resourceGroup string, noWait bool) error {
 		return fmt.Errorf("failed to stop AKS cluster: %w", err)
 	}
 
-	fmt.Printf("Stopped AKS cluster '%s'\n", name)
+	fmt.Printf("Stopped AKS cluster '%s'\n", t.Name)
 	return nil
 }
@@ -22,7 +22,7 @@ func RoleList(ctx context.Context, cmd *cobra.Command, location string) error {
 		return err
 	}
 
-	client, err := armcontainerservice.NewTrustedAccessRolesClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewTrustedAccessRolesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create trusted access roles client: %w", err)
 	}
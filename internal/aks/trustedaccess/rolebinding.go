@@ -23,7 +23,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName st
 		return err
 	}
 
-	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create trusted access role bindings client: %w", err)
 	}
@@ -52,7 +52,7 @@ func Show(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName, n
 		return err
 	}
 
-	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create trusted access role bindings client: %w", err)
 	}
@@ -76,7 +76,7 @@ func CreateOrUpdate(ctx context.Context, cmd *cobra.Command, resourceGroup, clus
 		return err
 	}
 
-	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create trusted access role bindings client: %w", err)
 	}
@@ -122,7 +122,7 @@ func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName,
 		return err
 	}
 
-	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, nil)
+	client, err := armcontainerservice.NewTrustedAccessRoleBindingsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create trusted access role bindings client: %w", err)
 	}
@@ -0,0 +1,120 @@
+package aks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
+	"github.com/spf13/cobra"
+)
+
+func newUpdateCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "update",
+		Short: "Update a managed Kubernetes cluster",
+		Long: `Update the settings of a managed Kubernetes cluster.
+
+Only the flags given are changed. The cluster is read, modified and written
+back whole, so --set, --add and --remove can reach any other property.`,
+		Example: `  az aks update -g rg -n aks1 --enable-oidc-issuer --enable-workload-identity
+  az aks update -g rg -n aks1 --api-server-authorized-ip-ranges 203.0.113.0/24
+  az aks update -g rg -n aks1 --cluster-autoscaler-profile scan-interval=30s,expander=least-waste`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noWait, _ := cmd.Flags().GetBool("no-wait")
+			return bulk.Run(cmd, clusterResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, noWait)
+			})
+		},
+	}
+	c.Flags().StringP("name", "n", "", "AKS cluster name")
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(c)
+	addProfileFlags(c)
+	c.Flags().Bool("enable-managed-identity", false, "Switch a service principal cluster to a system-assigned identity")
+	c.Flags().Bool("disable-azure-rbac", false, "Stop using Azure RBAC for Kubernetes authorization")
+	c.Flags().Bool("disable-workload-identity", false, "Disable workload identity")
+	c.MarkFlagsMutuallyExclusive("enable-azure-rbac", "disable-azure-rbac")
+	c.MarkFlagsMutuallyExclusive("enable-workload-identity", "disable-workload-identity")
+	c.MarkFlagsMutuallyExclusive("enable-managed-identity", "assign-identity")
+	genericupdate.AddFlags(c)
+	c.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+	return c
+}
+
+// Update reads the cluster, applies the given flags and PUTs it back. The
+// managed clusters API has no PATCH for these settings.
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, noWait bool) (interface{}, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armcontainerservice.NewManagedClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AKS client: %w", err)
+	}
+
+	current, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AKS cluster: %w", err)
+	}
+	cluster := current.ManagedCluster
+	if err := applyUpdateFlags(cmd, &cluster); err != nil {
+		return nil, err
+	}
+	if err := genericupdate.ApplyFlags(cmd, &cluster); err != nil {
+		return nil, err
+	}
+	if err := validateProfile(&cluster); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Updating AKS cluster '%s'...\n", t.Name)
+	poller, err := client.BeginCreateOrUpdate(ctx, t.ResourceGroup, t.Name, cluster, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin update: %w", err)
+	}
+
+	if noWait {
+		return map[string]string{"status": "cluster update started"}, nil
+	}
+
+	result, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update AKS cluster: %w", err)
+	}
+	return result.ManagedCluster, nil
+}
+
+// applyUpdateFlags applies the shared flags plus the ones only update has,
+// which undo or convert settings a cluster already carries.
+func applyUpdateFlags(cmd *cobra.Command, cluster *armcontainerservice.ManagedCluster) error {
+	if err := applyProfileFlags(cmd, cluster); err != nil {
+		return err
+	}
+	flags := cmd.Flags()
+	props := cluster.Properties
+	if disable, _ := flags.GetBool("disable-azure-rbac"); disable {
+		if props.AADProfile == nil {
+			return fmt.Errorf("--disable-azure-rbac: the cluster has no Entra ID integration")
+		}
+		props.AADProfile.EnableAzureRBAC = to.Ptr(false)
+	}
+	if disable, _ := flags.GetBool("disable-workload-identity"); disable {
+		if props.SecurityProfile == nil {
+			props.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{}
+		}
+		props.SecurityProfile.WorkloadIdentity = &armcontainerservice.ManagedClusterSecurityProfileWorkloadIdentity{Enabled: to.Ptr(false)}
+	}
+	if enable, _ := flags.GetBool("enable-managed-identity"); enable {
+		cluster.Identity = &armcontainerservice.ManagedClusterIdentity{
+			Type: to.Ptr(armcontainerservice.ResourceIdentityTypeSystemAssigned),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package aks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+	"github.com/cdobbyn/azure-go-cli/internal/aks/preflight"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newUpgradeCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade a managed Kubernetes cluster to a newer version",
+		Long: `Upgrade the control plane and node pools of a managed Kubernetes cluster,
+only the control plane, or only the node images.
+
+Before starting, a pre-flight report checks for deprecated APIs still in use
+that the target version removes, PodDisruptionBudgets that would stop nodes
+draining, and vCPU quota for the surge nodes. A failed check stops the
+upgrade unless --force is given.
+
+The cluster's upgrade profile only lists versions, not deprecated API
+usage, so that check reads the API server's
+apiserver_requested_deprecated_apis metric instead. It and the
+PodDisruptionBudget check run kubectl through az aks command invoke; if
+that fails, they are reported as unknown with a warning and do not block
+the upgrade.`,
+		Example: `  az aks get-upgrades -g rg -n aks1
+  az aks upgrade -g rg -n aks1 --kubernetes-version 1.30.4
+  az aks upgrade -g rg -n aks1 --kubernetes-version 1.30.4 --control-plane-only
+  az aks upgrade -g rg -n aks1 --node-image-only`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			resourceGroup, _ := cmd.Flags().GetString("resource-group")
+			return Upgrade(context.Background(), cmd, name, resourceGroup)
+		},
+	}
+	c.Flags().StringP("name", "n", "", "AKS cluster name")
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	c.Flags().StringP("kubernetes-version", "k", "", "Version to upgrade to, as listed by az aks get-upgrades")
+	c.Flags().Bool("control-plane-only", false, "Upgrade only the control plane, leaving node pools on their version")
+	c.Flags().Bool("node-image-only", false, "Upgrade only the node images of every node pool")
+	preflight.AddFlags(c)
+	c.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+	c.MarkFlagRequired("name")
+	c.MarkFlagRequired("resource-group")
+	c.MarkFlagsMutuallyExclusive("node-image-only", "kubernetes-version")
+	c.MarkFlagsMutuallyExclusive("node-image-only", "control-plane-only")
+	return c
+}
+
+func Upgrade(ctx context.Context, cmd *cobra.Command, name, resourceGroup string) error {
+	target, _ := cmd.Flags().GetString("kubernetes-version")
+	controlPlaneOnly, _ := cmd.Flags().GetBool("control-plane-only")
+	nodeImageOnly, _ := cmd.Flags().GetBool("node-image-only")
+	skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+	if target == "" && !nodeImageOnly {
+		return fmt.Errorf("--kubernetes-version is required unless --node-image-only is given")
+	}
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create AKS client: %w", err)
+	}
+
+	current, err := client.Get(ctx, resourceGroup, name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get AKS cluster: %w", err)
+	}
+	cluster := current.ManagedCluster
+	if cluster.Properties == nil {
+		return fmt.Errorf("cluster properties are nil")
+	}
+
+	if !nodeImageOnly {
+		profile, err := client.GetUpgradeProfile(ctx, resourceGroup, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get upgrade profile: %w", err)
+		}
+		version, available := controlPlaneUpgrades(profile.ManagedClusterUpgradeProfile)
+		if err := preflight.ValidateTarget(version, available, target); err != nil {
+			return err
+		}
+	}
+
+	if !skipPreflight {
+		opts := preflight.Options{
+			SubscriptionID: subscriptionID,
+			ResourceGroup:  resourceGroup,
+			Cluster:        name,
+			Location:       azure.GetStringValue(cluster.Location),
+			TargetVersion:  target,
+		}
+		if !controlPlaneOnly {
+			opts.Pools = upgradingPools(cluster.Properties.AgentPoolProfiles, target)
+		}
+		if err := preflight.Enforce(cmd, preflight.Run(ctx, opts)); err != nil {
+			return err
+		}
+	}
+
+	if nodeImageOnly {
+		return upgradeNodeImages(ctx, cmd, subscriptionID, resourceGroup, name, cluster.Properties.AgentPoolProfiles, noWait)
+	}
+
+	cluster.Properties.KubernetesVersion = to.Ptr(target)
+	if !controlPlaneOnly {
+		for _, p := range cluster.Properties.AgentPoolProfiles {
+			p.OrchestratorVersion = to.Ptr(target)
+		}
+	}
+
+	fmt.Printf("Upgrading AKS cluster '%s' to Kubernetes %s...\n", name, target)
+	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, name, cluster, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin upgrade: %w", err)
+	}
+
+	if noWait {
+		return output.PrintJSON(cmd, map[string]string{"status": "cluster upgrade started"})
+	}
+
+	result, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade AKS cluster: %w", err)
+	}
+
+	return output.PrintJSON(cmd, result.ManagedCluster)
+}
+
+// upgradeNodeImages starts a node image upgrade on every pool before
+// waiting on any, as AKS runs them side by side.
+func upgradeNodeImages(ctx context.Context, cmd *cobra.Command, subscriptionID, resourceGroup, name string, pools []*armcontainerservice.ManagedClusterAgentPoolProfile, noWait bool) error {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return err
+	}
+
+	client, err := armcontainerservice.NewAgentPoolsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create agent pools client: %w", err)
+	}
+
+	type started struct {
+		pool   string
+		poller *runtime.Poller[armcontainerservice.AgentPoolsClientUpgradeNodeImageVersionResponse]
+	}
+	var pollers []started
+	for _, p := range pools {
+		pool := azure.GetStringValue(p.Name)
+		fmt.Printf("Upgrading node image of node pool '%s'...\n", pool)
+		poller, err := client.BeginUpgradeNodeImageVersion(ctx, resourceGroup, name, pool, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin node image upgrade of node pool '%s': %w", pool, err)
+		}
+		pollers = append(pollers, started{pool, poller})
+	}
+
+	if noWait {
+		return output.PrintJSON(cmd, map[string]string{"status": "node image upgrade started"})
+	}
+
+	results := make([]armcontainerservice.AgentPool, 0, len(pollers))
+	for _, s := range pollers {
+		resp, err := s.poller.PollUntilDone(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade node image of node pool '%s': %w", s.pool, err)
+		}
+		results = append(results, resp.AgentPool)
+	}
+
+	return output.PrintJSON(cmd, results)
+}
+
+// controlPlaneUpgrades returns the control plane's version and the versions
+// it can be upgraded to.
+func controlPlaneUpgrades(profile armcontainerservice.ManagedClusterUpgradeProfile) (string, []string) {
+	if profile.Properties == nil || profile.Properties.ControlPlaneProfile == nil {
+		return "", nil
+	}
+	cp := profile.Properties.ControlPlaneProfile
+	var available []string
+	for _, u := range cp.Upgrades {
+		if u != nil && u.KubernetesVersion != nil {
+			available = append(available, *u.KubernetesVersion)
+		}
+	}
+	return azure.GetStringValue(cp.KubernetesVersion), available
+}
+
+// upgradingPools lists the pools an upgrade to target will roll, which is
+// every pool for a node image upgrade (target "").
+func upgradingPools(profiles []*armcontainerservice.ManagedClusterAgentPoolProfile, target string) []preflight.Pool {
+	var pools []preflight.Pool
+	for _, p := range profiles {
+		if p == nil {
+			continue
+		}
+		if target != "" && azure.GetStringValue(p.CurrentOrchestratorVersion) == target {
+			continue
+		}
+		pool := preflight.Pool{
+			Name:   azure.GetStringValue(p.Name),
+			VMSize: azure.GetStringValue(p.VMSize),
+			Spot:   p.ScaleSetPriority != nil && *p.ScaleSetPriority == armcontainerservice.ScaleSetPrioritySpot,
+		}
+		if p.Count != nil {
+			pool.Count = *p.Count
+		}
+		if p.UpgradeSettings != nil {
+			pool.MaxSurge = azure.GetStringValue(p.UpgradeSettings.MaxSurge)
+		}
+		pools = append(pools, pool)
+	}
+	return pools
+}
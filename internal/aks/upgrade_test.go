@@ -0,0 +1,50 @@
+package aks
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v6"
+)
+
+func TestUpgradingPools(t *testing.T) {
+	profiles := []*armcontainerservice.ManagedClusterAgentPoolProfile{
+		{Name: to.Ptr("system"), VMSize: to.Ptr("Standard_D4s_v5"), Count: to.Ptr[int32](3), CurrentOrchestratorVersion: to.Ptr("1.29.8")},
+		{Name: to.Ptr("done"), VMSize: to.Ptr("Standard_D4s_v5"), Count: to.Ptr[int32](3), CurrentOrchestratorVersion: to.Ptr("1.30.4")},
+		{Name: to.Ptr("spot"), VMSize: to.Ptr("Standard_D8s_v5"), Count: to.Ptr[int32](10), CurrentOrchestratorVersion: to.Ptr("1.29.8"),
+			ScaleSetPriority: to.Ptr(armcontainerservice.ScaleSetPrioritySpot),
+			UpgradeSettings:  &armcontainerservice.AgentPoolUpgradeSettings{MaxSurge: to.Ptr("50%")}},
+	}
+
+	pools := upgradingPools(profiles, "1.30.4")
+	if len(pools) != 2 || pools[0].Name != "system" || pools[1].Name != "spot" {
+		t.Fatalf("upgradingPools(1.30.4) = %+v, want system and spot", pools)
+	}
+	if !pools[1].Spot || pools[1].MaxSurge != "50%" || pools[1].Count != 10 {
+		t.Errorf("spot pool = %+v", pools[1])
+	}
+	if pools := upgradingPools(profiles, ""); len(pools) != 3 {
+		t.Errorf("upgradingPools(node image) = %d pools, want all 3", len(pools))
+	}
+}
+
+func TestControlPlaneUpgrades(t *testing.T) {
+	profile := armcontainerservice.ManagedClusterUpgradeProfile{
+		Properties: &armcontainerservice.ManagedClusterUpgradeProfileProperties{
+			ControlPlaneProfile: &armcontainerservice.ManagedClusterPoolUpgradeProfile{
+				KubernetesVersion: to.Ptr("1.29.8"),
+				Upgrades: []*armcontainerservice.ManagedClusterPoolUpgradeProfileUpgradesItem{
+					{KubernetesVersion: to.Ptr("1.30.4")},
+					{KubernetesVersion: to.Ptr("1.30.5"), IsPreview: to.Ptr(true)},
+				},
+			},
+		},
+	}
+	current, available := controlPlaneUpgrades(profile)
+	if current != "1.29.8" || len(available) != 2 || available[1] != "1.30.5" {
+		t.Errorf("controlPlaneUpgrades = %s, %v", current, available)
+	}
+	if current, available := controlPlaneUpgrades(armcontainerservice.ManagedClusterUpgradeProfile{}); current != "" || available != nil {
+		t.Errorf("controlPlaneUpgrades(empty) = %q, %v", current, available)
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spf13/cobra"
 )
@@ -10,12 +11,48 @@ func NewLoginCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Log in to Azure",
-		Long:  "Log in to Azure using device code flow",
+		Long: `Log in to Azure interactively, or non-interactively as a service principal
+(client secret, PEM/PFX certificate or federated token) or managed identity.`,
+		Example: `  # Service principal with a client secret
+  az login --service-principal -u <app-id> -p <secret> --tenant <tenant>
+
+  # Service principal with a certificate (PEM or PFX containing the private key)
+  az login --service-principal -u <app-id> --certificate ./sp.pem --tenant <tenant>
+
+  # Workload identity federation, e.g. in GitHub Actions
+  az login --service-principal -u <app-id> --tenant <tenant> --federated-token "$TOKEN"
+
+  # Managed identity (system-assigned, or user-assigned by client ID)
+  az login --identity
+  az login --identity --client-id <client-id>`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			tenantSelection, _ := cmd.Flags().GetBool("tenant-selection")
 			subscription, _ := cmd.Flags().GetString("subscription")
 			tenant, _ := cmd.Flags().GetString("tenant")
 			useAzureCLI, _ := cmd.Flags().GetBool("use-azure-cli")
+
+			var flags LoginFlags
+			flags.ServicePrincipal, _ = cmd.Flags().GetBool("service-principal")
+			flags.Username, _ = cmd.Flags().GetString("username")
+			flags.Password, _ = cmd.Flags().GetString("password")
+			flags.Certificate, _ = cmd.Flags().GetString("certificate")
+			flags.FederatedToken, _ = cmd.Flags().GetString("federated-token")
+			flags.Identity, _ = cmd.Flags().GetBool("identity")
+			flags.ClientID, _ = cmd.Flags().GetString("client-id")
+			flags.ObjectID, _ = cmd.Flags().GetString("object-id")
+			flags.ResourceID, _ = cmd.Flags().GetString("resource-id")
+			flags.Tenant = tenant
+
+			login, err := resolveNonInteractiveLogin(flags)
+			if err != nil {
+				return err
+			}
+			if login != nil {
+				if useAzureCLI {
+					return fmt.Errorf("usage error: --use-azure-cli cannot be combined with --service-principal or --identity")
+				}
+				return LoginNonInteractive(context.Background(), login, subscription)
+			}
 			return Login(context.Background(), tenantSelection, subscription, tenant, useAzureCLI)
 		},
 	}
@@ -23,6 +60,15 @@ func NewLoginCommand() *cobra.Command {
 	cmd.Flags().Bool("tenant-selection", false, "Always show tenant selection (useful with many subscriptions)")
 	cmd.Flags().StringP("tenant", "t", "", "Tenant ID or domain to sign in to (prompts for subscription unless --subscription is given)")
 	cmd.Flags().Bool("use-azure-cli", false, "Borrow tokens from the Python Azure CLI (az) instead of signing in directly - satisfies device-based Conditional Access via its WAM broker on Windows")
+	cmd.Flags().Bool("service-principal", false, "Log in as a service principal")
+	cmd.Flags().StringP("username", "u", "", "Service principal application (client) ID")
+	cmd.Flags().StringP("password", "p", "", "Service principal client secret, or the path of a PEM/PFX certificate file")
+	cmd.Flags().String("certificate", "", "Path of a PEM/PFX certificate file (with private key) for service principal login")
+	cmd.Flags().String("federated-token", "", "Federated (OIDC) token for service principal login via workload identity federation")
+	cmd.Flags().Bool("identity", false, "Log in using the managed identity of the Azure host")
+	cmd.Flags().String("client-id", "", "Client ID of the user-assigned managed identity (with --identity)")
+	cmd.Flags().String("object-id", "", "Object ID of the user-assigned managed identity (with --identity)")
+	cmd.Flags().String("resource-id", "", "Resource ID of the user-assigned managed identity (with --identity)")
 
 	return cmd
 }
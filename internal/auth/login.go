@@ -49,7 +49,7 @@ func Login(ctx context.Context, forceTenantSelection bool, subscriptionFilter, t
 		logger.Println("Signing in with Windows (WAM broker).")
 		logger.Println("")
 	} else {
-		logger.Println("A web browser has been opened at https://login.microsoftonline.com/organizations/oauth2/v2.0/authorize.")
+		logger.Println("A web browser has been opened at %s/oauth2/v2.0/authorize.", azure.Authority("organizations"))
 		logger.Println("Please continue the login in the web browser.")
 		logger.Println("If no web browser is available or if the web browser fails to open, use device code flow with `az login --use-device-code`.")
 		logger.Println("")
@@ -513,7 +513,7 @@ func promptForSubscriptionFlatWithMFA(ctx context.Context, activeTenants []azure
 		Name:            selected.Name,
 		State:           selected.State,
 		TenantID:        selected.TenantID,
-		EnvironmentName: "AzureCloud",
+		EnvironmentName: azure.ActiveCloud().Name,
 		IsDefault:       false,
 	}
 	return &result, nil
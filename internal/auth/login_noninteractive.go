@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+)
+
+// NonInteractiveLogin describes a login that needs no user: a service
+// principal (secret, certificate or federated token) or a managed identity.
+type NonInteractiveLogin struct {
+	// Mode is one of azure.AuthModeServicePrincipal,
+	// azure.AuthModeFederatedToken or azure.AuthModeManagedIdentity.
+	Mode string
+
+	// Service principal modes.
+	ClientID        string
+	TenantID        string
+	ClientSecret    string
+	CertificatePath string
+	FederatedToken  string
+
+	// Managed identity mode; nil selects the system-assigned identity.
+	ManagedIdentity *config.ManagedIdentity
+}
+
+// LoginFlags are the raw `az login` flags that select a non-interactive login.
+type LoginFlags struct {
+	ServicePrincipal bool
+	Username         string
+	Password         string
+	Certificate      string
+	FederatedToken   string
+	Tenant           string
+	Identity         bool
+	ClientID         string
+	ObjectID         string
+	ResourceID       string
+}
+
+// resolveNonInteractiveLogin validates the login flags and returns the
+// non-interactive login they describe, or nil for an interactive login.
+// Mirrors the Python CLI: -p is a client secret unless it names an existing
+// file, in which case it is a PEM/PFX certificate.
+func resolveNonInteractiveLogin(f LoginFlags) (*NonInteractiveLogin, error) {
+	if f.Identity {
+		if f.ServicePrincipal || f.Password != "" || f.Certificate != "" || f.FederatedToken != "" {
+			return nil, fmt.Errorf("usage error: --identity cannot be combined with service principal credentials")
+		}
+		set := 0
+		for _, v := range []string{f.ClientID, f.ObjectID, f.ResourceID} {
+			if v != "" {
+				set++
+			}
+		}
+		if set > 1 {
+			return nil, fmt.Errorf("usage error: specify only one of --client-id, --object-id or --resource-id")
+		}
+		login := &NonInteractiveLogin{Mode: azure.AuthModeManagedIdentity}
+		if set == 1 {
+			login.ManagedIdentity = &config.ManagedIdentity{
+				ClientID:   f.ClientID,
+				ObjectID:   f.ObjectID,
+				ResourceID: f.ResourceID,
+			}
+		}
+		return login, nil
+	}
+
+	if f.ClientID != "" || f.ObjectID != "" || f.ResourceID != "" {
+		return nil, fmt.Errorf("usage error: --client-id, --object-id and --resource-id require --identity")
+	}
+
+	if !f.ServicePrincipal && f.FederatedToken == "" {
+		if f.Username != "" || f.Password != "" || f.Certificate != "" {
+			return nil, fmt.Errorf("usage error: --username, --password and --certificate require --service-principal")
+		}
+		return nil, nil
+	}
+
+	if f.Username == "" {
+		return nil, fmt.Errorf("usage error: --service-principal requires --username (the application client ID)")
+	}
+	if f.Tenant == "" {
+		return nil, fmt.Errorf("usage error: --service-principal requires --tenant")
+	}
+
+	login := &NonInteractiveLogin{
+		Mode:     azure.AuthModeServicePrincipal,
+		ClientID: f.Username,
+		TenantID: f.Tenant,
+	}
+
+	certificate := f.Certificate
+	secret := f.Password
+	if certificate == "" && secret != "" {
+		if info, err := os.Stat(secret); err == nil && !info.IsDir() {
+			certificate, secret = secret, ""
+		}
+	}
+
+	credentials := 0
+	for _, v := range []string{secret, certificate, f.FederatedToken} {
+		if v != "" {
+			credentials++
+		}
+	}
+	switch {
+	case credentials == 0:
+		return nil, fmt.Errorf("usage error: --service-principal requires one of --password, --certificate or --federated-token")
+	case credentials > 1:
+		return nil, fmt.Errorf("usage error: specify only one of --password, --certificate or --federated-token")
+	case f.FederatedToken != "":
+		login.Mode = azure.AuthModeFederatedToken
+		login.FederatedToken = f.FederatedToken
+	case certificate != "":
+		login.CertificatePath = certificate
+	default:
+		login.ClientSecret = secret
+	}
+	return login, nil
+}
+
+// LoginNonInteractive signs in as a service principal or managed identity and
+// saves a profile whose later commands get the matching azidentity credential
+// from azure.GetCredential. There are no prompts: the default subscription is
+// the one named by subscriptionFilter, otherwise the first enabled one.
+func LoginNonInteractive(ctx context.Context, login *NonInteractiveLogin, subscriptionFilter string) error {
+	// Clear any existing profile (and its credentials) to ensure fresh login
+	if err := config.Delete(); err != nil {
+		// Ignore errors if profile doesn't exist
+		_ = err
+	}
+
+	azure.SetAuthMode(login.Mode)
+
+	user := config.User{Name: login.ClientID, Type: "servicePrincipal"}
+	if login.Mode == azure.AuthModeManagedIdentity {
+		azure.SetManagedIdentity(login.ManagedIdentity)
+		user.Name = "systemAssignedIdentity"
+		if login.ManagedIdentity != nil {
+			user.Name = "userAssignedIdentity"
+		}
+	} else {
+		// The credential is built from the saved entry, so it must be on disk
+		// before discovery authenticates.
+		entry := &config.ServicePrincipalEntry{
+			ClientID:        login.ClientID,
+			TenantID:        login.TenantID,
+			ClientSecret:    login.ClientSecret,
+			CertificatePath: login.CertificatePath,
+			ClientAssertion: login.FederatedToken,
+		}
+		if err := config.SaveServicePrincipal(entry); err != nil {
+			return err
+		}
+	}
+
+	cred, err := azure.BaseCredential()
+	if err != nil {
+		return fmt.Errorf("failed to create credential: %w", err)
+	}
+
+	logger.Info("Retrieving subscriptions...")
+	var subscriptions []config.Subscription
+	if login.Mode == azure.AuthModeManagedIdentity {
+		// A managed identity's tenant isn't known up front; discovery lists
+		// the tenants it can see, which is just its own.
+		tenantInfos, _, err := azure.DiscoverAllSubscriptionsWithAuth(ctx, cred)
+		if err != nil {
+			return fmt.Errorf("failed to discover subscriptions: %w", err)
+		}
+		subscriptions = azure.GetAllSubscriptions(tenantInfos)
+	} else {
+		subscriptions, err = azure.DiscoverTenantSubscriptions(ctx, login.TenantID, cred)
+		if err != nil {
+			return fmt.Errorf("failed to discover subscriptions: %w", err)
+		}
+	}
+
+	if len(subscriptions) == 0 {
+		return fmt.Errorf("no subscriptions found for this identity; grant it a role on a subscription and try again")
+	}
+
+	selected := defaultSubscription(subscriptions, subscriptionFilter)
+	if selected == nil {
+		return fmt.Errorf("subscription '%s' not found among accessible subscriptions", subscriptionFilter)
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].User = user
+		subscriptions[i].IsDefault = subscriptions[i].ID == selected.ID
+	}
+
+	profile := config.Profile{
+		Subscriptions:   subscriptions,
+		AuthMode:        login.Mode,
+		ManagedIdentity: login.ManagedIdentity,
+	}
+	if err := config.Save(&profile); err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	logger.Println("Tenant: %s", selected.TenantID)
+	logger.Println("Subscription: %s (%s)", selected.Name, selected.ID)
+	logger.Info("You have successfully logged in")
+
+	return nil
+}
+
+// defaultSubscription picks the subscription a non-interactive login
+// defaults to: the one matching query if given, otherwise the first enabled
+// subscription, otherwise the first one.
+func defaultSubscription(subs []config.Subscription, query string) *config.Subscription {
+	if query != "" {
+		return resolveSubscription(subs, query)
+	}
+	for i := range subs {
+		if subs[i].State == "Enabled" {
+			return &subs[i]
+		}
+	}
+	if len(subs) > 0 {
+		return &subs[0]
+	}
+	return nil
+}
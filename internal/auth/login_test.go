@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
@@ -74,3 +76,113 @@ func TestFilterTenants(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveNonInteractiveLogin(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "sp.pem")
+	if err := os.WriteFile(certFile, []byte("-----BEGIN CERTIFICATE-----"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		flags    LoginFlags
+		wantNil  bool
+		wantErr  bool
+		wantMode string
+		check    func(t *testing.T, l *NonInteractiveLogin)
+	}{
+		{name: "interactive", flags: LoginFlags{Tenant: "contoso.com"}, wantNil: true},
+		{
+			name:     "secret",
+			flags:    LoginFlags{ServicePrincipal: true, Username: "app", Password: "s3cret", Tenant: "t"},
+			wantMode: azure.AuthModeServicePrincipal,
+			check: func(t *testing.T, l *NonInteractiveLogin) {
+				if l.ClientSecret != "s3cret" || l.CertificatePath != "" {
+					t.Errorf("got secret %q cert %q", l.ClientSecret, l.CertificatePath)
+				}
+			},
+		},
+		{
+			name:     "password names a certificate file",
+			flags:    LoginFlags{ServicePrincipal: true, Username: "app", Password: certFile, Tenant: "t"},
+			wantMode: azure.AuthModeServicePrincipal,
+			check: func(t *testing.T, l *NonInteractiveLogin) {
+				if l.CertificatePath != certFile || l.ClientSecret != "" {
+					t.Errorf("got secret %q cert %q", l.ClientSecret, l.CertificatePath)
+				}
+			},
+		},
+		{
+			name:     "federated token",
+			flags:    LoginFlags{ServicePrincipal: true, Username: "app", FederatedToken: "eyJ", Tenant: "t"},
+			wantMode: azure.AuthModeFederatedToken,
+		},
+		{name: "missing tenant", flags: LoginFlags{ServicePrincipal: true, Username: "app", Password: "x"}, wantErr: true},
+		{name: "missing username", flags: LoginFlags{ServicePrincipal: true, Password: "x", Tenant: "t"}, wantErr: true},
+		{name: "missing credential", flags: LoginFlags{ServicePrincipal: true, Username: "app", Tenant: "t"}, wantErr: true},
+		{name: "two credentials", flags: LoginFlags{ServicePrincipal: true, Username: "app", Password: "x", FederatedToken: "y", Tenant: "t"}, wantErr: true},
+		{name: "username without service principal", flags: LoginFlags{Username: "app"}, wantErr: true},
+		{name: "system-assigned identity", flags: LoginFlags{Identity: true}, wantMode: azure.AuthModeManagedIdentity,
+			check: func(t *testing.T, l *NonInteractiveLogin) {
+				if l.ManagedIdentity != nil {
+					t.Errorf("ManagedIdentity = %+v, want nil", l.ManagedIdentity)
+				}
+			},
+		},
+		{name: "user-assigned identity", flags: LoginFlags{Identity: true, ClientID: "cid"}, wantMode: azure.AuthModeManagedIdentity,
+			check: func(t *testing.T, l *NonInteractiveLogin) {
+				if l.ManagedIdentity == nil || l.ManagedIdentity.ClientID != "cid" {
+					t.Errorf("ManagedIdentity = %+v, want client ID cid", l.ManagedIdentity)
+				}
+			},
+		},
+		{name: "two identity selectors", flags: LoginFlags{Identity: true, ClientID: "a", ObjectID: "b"}, wantErr: true},
+		{name: "client-id without identity", flags: LoginFlags{ClientID: "a"}, wantErr: true},
+		{name: "identity with secret", flags: LoginFlags{Identity: true, Password: "x"}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveNonInteractiveLogin(tc.flags)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("resolveNonInteractiveLogin() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveNonInteractiveLogin() error = %v", err)
+			}
+			if tc.wantNil {
+				if got != nil {
+					t.Errorf("resolveNonInteractiveLogin() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Mode != tc.wantMode {
+				t.Fatalf("resolveNonInteractiveLogin() = %+v, want mode %s", got, tc.wantMode)
+			}
+			if tc.check != nil {
+				tc.check(t, got)
+			}
+		})
+	}
+}
+
+func TestDefaultSubscription(t *testing.T) {
+	subs := []config.Subscription{
+		{ID: "1", Name: "Old", State: "Disabled"},
+		{ID: "2", Name: "Prod", State: "Enabled"},
+		{ID: "3", Name: "Dev", State: "Enabled"},
+	}
+
+	if got := defaultSubscription(subs, ""); got == nil || got.ID != "2" {
+		t.Errorf("defaultSubscription(no filter) = %+v, want first enabled (2)", got)
+	}
+	if got := defaultSubscription(subs, "Dev"); got == nil || got.ID != "3" {
+		t.Errorf("defaultSubscription(Dev) = %+v, want 3", got)
+	}
+	if got := defaultSubscription(subs, "Staging"); got != nil {
+		t.Errorf("defaultSubscription(Staging) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,200 @@
+package bicep
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+)
+
+// releaseURL is where Bicep CLI builds are published, one asset per platform.
+const releaseURL = "https://github.com/Azure/bicep/releases"
+
+// binDir is the directory under ~/.azure that managed installs go to. The
+// Python CLI uses the same place, so either CLI picks up the other's install.
+const binDir = "bin"
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// binaryPath returns where the managed bicep executable lives.
+func binaryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	name := "bicep"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(home, config.ConfigDir, binDir, name), nil
+}
+
+// usePath reads bicep.use_binary_from_path: "true" runs the bicep on PATH,
+// "false" only the managed install; anything else prefers the managed
+// install but falls back to PATH before downloading.
+func usePath() string {
+	e, ok, err := config.GetConfigValue("bicep", "use_binary_from_path")
+	if err != nil || !ok {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(e.Value))
+}
+
+// Find returns the bicep executable to run, installing the latest release
+// into ~/.azure/bin when there is none. Progress goes to w.
+func Find(ctx context.Context, w io.Writer) (string, error) {
+	mode := usePath()
+	if mode == "true" {
+		path, err := exec.LookPath("bicep")
+		if err != nil {
+			return "", fmt.Errorf("bicep.use_binary_from_path is true but bicep was not found on PATH")
+		}
+		return path, nil
+	}
+
+	managed, err := binaryPath()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(managed); err == nil {
+		return managed, nil
+	}
+	if mode != "false" {
+		if path, err := exec.LookPath("bicep"); err == nil {
+			logger.Debug("Using bicep from PATH: %s", path)
+			return path, nil
+		}
+	}
+	if err := Install(ctx, w, ""); err != nil {
+		return "", err
+	}
+	return managed, nil
+}
+
+// Install downloads the given Bicep CLI release (the latest when version is
+// empty) into ~/.azure/bin, replacing any earlier install.
+func Install(ctx context.Context, w io.Writer, version string) error {
+	asset, err := assetName(runtime.GOOS, runtime.GOARCH, isMusl())
+	if err != nil {
+		return err
+	}
+	url := releaseURL + "/latest/download/" + asset
+	label := "the latest Bicep CLI"
+	if version != "" {
+		version = strings.TrimPrefix(version, "v")
+		url = fmt.Sprintf("%s/download/v%s/%s", releaseURL, version, asset)
+		label = "Bicep CLI v" + version
+	}
+	dest, err := binaryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+
+	fmt.Fprintf(w, "Installing %s to %s...\n", label, dest)
+	logger.Debug("Download URL: %s", url)
+	// Download next to the destination and rename, so an interrupted
+	// download never leaves a truncated bicep behind.
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".bicep-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := download(ctx, url, tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to download %s: %w", label, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp.Name(), err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to make bicep executable: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("failed to install bicep: %w", err)
+	}
+	return nil
+}
+
+// Uninstall removes the managed install. A bicep on PATH is left alone.
+func Uninstall() error {
+	path, err := binaryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
+
+func download(ctx context.Context, url string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// assetName maps a platform to the name of its release asset.
+func assetName(goos, goarch string, musl bool) (string, error) {
+	arch := map[string]string{"amd64": "x64", "arm64": "arm64"}[goarch]
+	if arch == "" {
+		return "", fmt.Errorf("bicep is not published for %s/%s", goos, goarch)
+	}
+	switch goos {
+	case "linux":
+		if musl && arch == "x64" {
+			return "bicep-linux-musl-x64", nil
+		}
+		return "bicep-linux-" + arch, nil
+	case "darwin":
+		return "bicep-osx-" + arch, nil
+	case "windows":
+		return "bicep-win-" + arch + ".exe", nil
+	}
+	return "", fmt.Errorf("bicep is not published for %s/%s", goos, goarch)
+}
+
+// isMusl reports whether this is a musl-based Linux such as Alpine, where
+// the glibc build doesn't run.
+func isMusl() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	matches, _ := filepath.Glob("/lib/ld-musl-*.so.1")
+	return len(matches) > 0
+}
+
+// Version returns the version of the bicep executable at path, e.g. "0.30.23".
+func Version(ctx context.Context, path string) (string, error) {
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", path, err)
+	}
+	v := versionPattern.FindString(string(out))
+	if v == "" {
+		return "", fmt.Errorf("unexpected output from %s --version: %q", path, strings.TrimSpace(string(out)))
+	}
+	return v, nil
+}
@@ -0,0 +1,106 @@
+package bicep
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAssetName(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		musl         bool
+		want         string
+	}{
+		{"linux", "amd64", false, "bicep-linux-x64"},
+		{"linux", "amd64", true, "bicep-linux-musl-x64"},
+		{"linux", "arm64", true, "bicep-linux-arm64"},
+		{"darwin", "arm64", false, "bicep-osx-arm64"},
+		{"windows", "amd64", false, "bicep-win-x64.exe"},
+	}
+	for _, tt := range tests {
+		got, err := assetName(tt.goos, tt.goarch, tt.musl)
+		if err != nil || got != tt.want {
+			t.Errorf("%s/%s musl=%v: got %q, %v", tt.goos, tt.goarch, tt.musl, got, err)
+		}
+	}
+	if _, err := assetName("linux", "386", false); err == nil {
+		t.Error("expected an error for linux/386")
+	}
+}
+
+func writeScript(t *testing.T, path, script string) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindAndVersion(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AZ_SESSION", "")
+	pathDir := t.TempDir()
+	t.Setenv("PATH", pathDir)
+	writeScript(t, filepath.Join(pathDir, "bicep"), "echo 'Bicep CLI version 0.29.47 (132ade51bc)'\n")
+
+	// With no managed install, the one on PATH is used.
+	ctx := context.Background()
+	var progress bytes.Buffer
+	path, err := Find(ctx, &progress)
+	if err != nil || path != filepath.Join(pathDir, "bicep") {
+		t.Fatalf("Find = %q, %v", path, err)
+	}
+	if v, err := Version(ctx, path); err != nil || v != "0.29.47" {
+		t.Errorf("Version = %q, %v", v, err)
+	}
+
+	// A managed install wins over PATH, unless configured otherwise.
+	managed := filepath.Join(home, ".azure", "bin", "bicep")
+	writeScript(t, managed, "echo 'Bicep CLI version 0.30.23 (ec3612b75a)'\n")
+	if path, _ := Find(ctx, &progress); path != managed {
+		t.Errorf("Find = %q, want the managed install", path)
+	}
+	t.Setenv("AZURE_BICEP_USE_BINARY_FROM_PATH", "true")
+	if path, _ := Find(ctx, &progress); path != filepath.Join(pathDir, "bicep") {
+		t.Errorf("use_binary_from_path: Find = %q", path)
+	}
+	if progress.Len() != 0 {
+		t.Errorf("unexpected install: %s", progress.String())
+	}
+
+	if err := Uninstall(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(managed); !os.IsNotExist(err) {
+		t.Errorf("managed install still present: %v", err)
+	}
+}
+
+func TestBuildParams(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AZ_SESSION", "")
+	writeScript(t, filepath.Join(home, ".azure", "bin", "bicep"), `[ "$1" = build-params ] && [ "$3" = --stdout ] || exit 1
+echo '{"parametersJson": "{\"parameters\": {}}", "templateJson": "{\"resources\": []}", "templateSpecId": null}'
+echo 'Warning: unused parameter' >&2
+`)
+
+	var stderr bytes.Buffer
+	p, err := BuildParams(context.Background(), &stderr, "main.bicepparam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(p.Parameters) != `{"parameters": {}}` || string(p.Template) != `{"resources": []}` {
+		t.Errorf("got %s / %s", p.Parameters, p.Template)
+	}
+	if stderr.String() != "Warning: unused parameter\n" {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+	if _, err := Build(context.Background(), &stderr, "main.bicep"); err == nil {
+		t.Error("expected a failing bicep to be reported")
+	}
+}
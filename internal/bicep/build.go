@@ -0,0 +1,109 @@
+package bicep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// Params is a compiled .bicepparam file.
+type Params struct {
+	// Parameters is an ARM parameters file.
+	Parameters []byte
+	// Template is the ARM template of the .bicep file named by `using`.
+	Template []byte
+}
+
+// run runs bicep with args and returns its stdout. Bicep writes warnings
+// and errors to stderr, which is passed through to stderr so they read the
+// same as when running bicep directly.
+func run(ctx context.Context, stderr io.Writer, args ...string) ([]byte, error) {
+	path, err := Find(ctx, stderr)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	c := exec.CommandContext(ctx, path, args...)
+	c.Stdout = &out
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("bicep %s failed: %w", args[0], err)
+	}
+	return out.Bytes(), nil
+}
+
+// Build compiles a .bicep file and returns the ARM template.
+func Build(ctx context.Context, stderr io.Writer, file string) ([]byte, error) {
+	return run(ctx, stderr, "build", file, "--stdout")
+}
+
+// BuildParams compiles a .bicepparam file together with the template it
+// refers to.
+func BuildParams(ctx context.Context, stderr io.Writer, file string) (*Params, error) {
+	out, err := run(ctx, stderr, "build-params", file, "--stdout")
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		ParametersJSON string `json:"parametersJson"`
+		TemplateJSON   string `json:"templateJson"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse bicep build-params output: %w", err)
+	}
+	return &Params{Parameters: []byte(result.ParametersJSON), Template: []byte(result.TemplateJSON)}, nil
+}
+
+// BuildFile runs `bicep build` for `az bicep build`, writing the template
+// next to the source unless --outfile, --outdir or --stdout says otherwise.
+func BuildFile(ctx context.Context, stdout, stderr io.Writer, file, outFile, outDir string, toStdout bool) error {
+	args := []string{"build", file}
+	switch {
+	case toStdout:
+		args = append(args, "--stdout")
+	case outFile != "":
+		args = append(args, "--outfile", outFile)
+	case outDir != "":
+		args = append(args, "--outdir", outDir)
+	}
+	out, err := run(ctx, stderr, args...)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
+
+// BuildParamsFile runs `bicep build-params` for `az bicep build-params`.
+func BuildParamsFile(ctx context.Context, stdout, stderr io.Writer, file, outFile string, toStdout bool) error {
+	args := []string{"build-params", file}
+	switch {
+	case toStdout:
+		args = append(args, "--stdout")
+	case outFile != "":
+		args = append(args, "--outfile", outFile)
+	}
+	out, err := run(ctx, stderr, args...)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
+
+// Decompile turns an ARM template into a .bicep file next to it.
+func Decompile(ctx context.Context, stdout, stderr io.Writer, file string, force bool) error {
+	args := []string{"decompile", file}
+	if force {
+		args = append(args, "--force")
+	}
+	out, err := run(ctx, stderr, args...)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
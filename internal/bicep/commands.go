@@ -0,0 +1,112 @@
+package bicep
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewBicepCommand wires `az bicep`. Every subcommand runs the Bicep CLI,
+// installing it into ~/.azure/bin on first use.
+func NewBicepCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bicep",
+		Short: "Bicep CLI command group",
+		Long:  "Build and decompile Bicep files with a Bicep CLI that is installed and kept in ~/.azure/bin",
+	}
+
+	buildCmd := &cobra.Command{
+		Use:   "build",
+		Short: "Build a Bicep file into an ARM template",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, _ := cmd.Flags().GetString("file")
+			outFile, _ := cmd.Flags().GetString("outfile")
+			outDir, _ := cmd.Flags().GetString("outdir")
+			toStdout, _ := cmd.Flags().GetBool("stdout")
+			return BuildFile(context.Background(), cmd.OutOrStdout(), cmd.ErrOrStderr(), file, outFile, outDir, toStdout)
+		},
+	}
+	buildCmd.Flags().StringP("file", "f", "", "The Bicep file to build")
+	buildCmd.Flags().String("outfile", "", "The output file path")
+	buildCmd.Flags().String("outdir", "", "The output directory")
+	buildCmd.Flags().Bool("stdout", false, "Print the template to stdout")
+	buildCmd.MarkFlagRequired("file")
+	buildCmd.MarkFlagsMutuallyExclusive("outfile", "outdir", "stdout")
+
+	buildParamsCmd := &cobra.Command{
+		Use:   "build-params",
+		Short: "Build a .bicepparam file into an ARM parameters file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, _ := cmd.Flags().GetString("file")
+			outFile, _ := cmd.Flags().GetString("outfile")
+			toStdout, _ := cmd.Flags().GetBool("stdout")
+			return BuildParamsFile(context.Background(), cmd.OutOrStdout(), cmd.ErrOrStderr(), file, outFile, toStdout)
+		},
+	}
+	buildParamsCmd.Flags().StringP("file", "f", "", "The .bicepparam file to build")
+	buildParamsCmd.Flags().String("outfile", "", "The output file path")
+	buildParamsCmd.Flags().Bool("stdout", false, "Print the parameters and template to stdout")
+	buildParamsCmd.MarkFlagRequired("file")
+	buildParamsCmd.MarkFlagsMutuallyExclusive("outfile", "stdout")
+
+	decompileCmd := &cobra.Command{
+		Use:   "decompile",
+		Short: "Attempt to decompile an ARM template into a Bicep file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, _ := cmd.Flags().GetString("file")
+			force, _ := cmd.Flags().GetBool("force")
+			return Decompile(context.Background(), cmd.OutOrStdout(), cmd.ErrOrStderr(), file, force)
+		},
+	}
+	decompileCmd.Flags().StringP("file", "f", "", "The ARM template to decompile")
+	decompileCmd.Flags().Bool("force", false, "Overwrite an existing .bicep file")
+	decompileCmd.MarkFlagRequired("file")
+
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show the installed version of the Bicep CLI",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			path, err := Find(ctx, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			v, err := Version(ctx, path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Bicep CLI version %s (%s)\n", v, path)
+			return nil
+		},
+	}
+
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the Bicep CLI into ~/.azure/bin",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, _ := cmd.Flags().GetString("version")
+			return Install(context.Background(), cmd.ErrOrStderr(), version)
+		},
+	}
+	installCmd.Flags().StringP("version", "v", "", "The version to install, e.g. 0.30.23 (defaults to the latest)")
+
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the Bicep CLI in ~/.azure/bin to the latest version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Install(context.Background(), cmd.ErrOrStderr(), "")
+		},
+	}
+
+	uninstallCmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the Bicep CLI from ~/.azure/bin",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Uninstall()
+		},
+	}
+
+	cmd.AddCommand(buildCmd, buildParamsCmd, decompileCmd, versionCmd, installCmd, upgradeCmd, uninstallCmd)
+	return cmd
+}
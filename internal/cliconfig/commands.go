@@ -0,0 +1,68 @@
+package cliconfig
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage Azure CLI configuration",
+		Long: `Manage persistent CLI settings such as default argument values.
+
+Settings live in ~/.azure/config (per session when AZ_SESSION is set). With
+--local they are written to .azure/config in the working directory instead,
+and apply to every command run from that directory or below it. Environment
+variables named AZURE_<SECTION>_<KEY>, e.g. AZURE_DEFAULTS_GROUP, override
+both files.
+
+Every defaults.<name> setting fills the --<name> flag of any command that has
+one and where it wasn't given; defaults.group fills --resource-group.
+core.output sets the default output format and core.only_show_errors=true
+hides warnings.`,
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set KEY=VALUE [KEY=VALUE ...]",
+		Short: "Set configurations",
+		Example: `  # Default resource group and location for every command
+  az config set defaults.group=MyResourceGroup defaults.location=westus2
+
+  # Table output, only for commands run from this directory
+  az config set core.output=table --local`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			local, _ := cmd.Flags().GetBool("local")
+			return Set(args, local)
+		},
+	}
+	setCmd.Flags().Bool("local", false, "Set the configuration in the working directory")
+
+	getCmd := &cobra.Command{
+		Use:   "get [KEY]",
+		Short: "Get a configuration",
+		Long:  "Get all configurations, every configuration of a section (e.g. 'defaults'), or a single key (e.g. 'defaults.group').",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := ""
+			if len(args) == 1 {
+				key = args[0]
+			}
+			return Get(cmd, key)
+		},
+	}
+
+	unsetCmd := &cobra.Command{
+		Use:   "unset KEY [KEY ...]",
+		Short: "Unset configurations",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			local, _ := cmd.Flags().GetBool("local")
+			return Unset(args, local)
+		},
+	}
+	unsetCmd.Flags().Bool("local", false, "Unset the configuration in the working directory")
+
+	cmd.AddCommand(setCmd, getCmd, unsetCmd)
+	return cmd
+}
@@ -0,0 +1,76 @@
+package cliconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func Set(pairs []string, local bool) error {
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("%q is not in KEY=VALUE format", pair)
+		}
+		section, key, err := config.SplitConfigKey(name)
+		if err != nil {
+			return err
+		}
+		if err := config.SetConfigValue(section, key, strings.TrimSpace(value), local); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get prints every setting, one section's, or a single key's, matching the
+// shapes `az config get` returns in the Python CLI.
+func Get(cmd *cobra.Command, name string) error {
+	if name == "" {
+		all, err := config.ListConfig()
+		if err != nil {
+			return err
+		}
+		return output.PrintJSON(cmd, all)
+	}
+
+	if !strings.Contains(name, ".") {
+		entries, err := config.GetConfigSection(name)
+		if err != nil {
+			return err
+		}
+		if entries == nil {
+			entries = []config.ConfigEntry{}
+		}
+		return output.PrintJSON(cmd, entries)
+	}
+
+	section, key, err := config.SplitConfigKey(name)
+	if err != nil {
+		return err
+	}
+	entry, ok, err := config.GetConfigValue(section, key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("configuration '%s.%s' is not set", section, key)
+	}
+	return output.PrintJSON(cmd, entry)
+}
+
+func Unset(names []string, local bool) error {
+	for _, name := range names {
+		section, key, err := config.SplitConfigKey(name)
+		if err != nil {
+			return err
+		}
+		if err := config.UnsetConfigValue(section, key, local); err != nil {
+			return err
+		}
+	}
+	return nil
+}
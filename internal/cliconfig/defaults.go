@@ -0,0 +1,72 @@
+package cliconfig
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// flagForDefault maps [defaults] keys that aren't flag names to the flag they
+// fill, following the Python CLI's key names.
+var flagForDefault = map[string]string{
+	"group": "resource-group",
+}
+
+// ApplyDefaults fills flags of cmd that weren't given on the command line from
+// the [defaults] and [core] settings. It runs before any RunE and before
+// cobra checks required flags, so a configured default behaves exactly as if
+// it had been passed.
+func ApplyDefaults(cmd *cobra.Command) error {
+	all, err := config.ListConfig()
+	if err != nil {
+		return err
+	}
+
+	// --ids names resources completely; filling -g alongside it would trip
+	// the commands' "either --ids or --resource-group" checks.
+	if ids := cmd.Flags().Lookup("ids"); ids == nil || !ids.Changed {
+		for _, e := range all["defaults"] {
+			name := e.Name
+			if f, ok := flagForDefault[name]; ok {
+				name = f
+			}
+			if err := setDefault(cmd, name, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, e := range all["core"] {
+		switch e.Name {
+		case "output":
+			if err := setDefault(cmd, "output", e); err != nil {
+				return err
+			}
+		case "only_show_errors":
+			// An explicit --debug or --verbose asks for more output than
+			// the configured default.
+			debug, _ := cmd.Flags().GetBool("debug")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			if quiet, _ := strconv.ParseBool(e.Value); quiet && !debug && !verbose {
+				logger.SetLogLevel(slog.LevelError)
+			}
+		}
+	}
+	return nil
+}
+
+func setDefault(cmd *cobra.Command, name string, e config.ConfigEntry) error {
+	f := cmd.Flags().Lookup(name)
+	if f == nil || f.Changed || e.Value == "" {
+		return nil
+	}
+	if err := cmd.Flags().Set(name, e.Value); err != nil {
+		return fmt.Errorf("invalid default %q for --%s from %s: %w", e.Value, name, e.Source, err)
+	}
+	logger.Debug("Using default --%s=%s from %s", name, e.Value, e.Source)
+	return nil
+}
@@ -0,0 +1,123 @@
+package cliconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// setupConfigDirs points HOME at a temp dir and changes into a project
+// directory below it, so global and local config files are both isolated.
+func setupConfigDirs(t *testing.T) (home, project string) {
+	t.Helper()
+	home = t.TempDir()
+	project = filepath.Join(home, "src", "project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("AZ_SESSION", "")
+	t.Chdir(project)
+	return home, project
+}
+
+func newTestCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "show", RunE: func(*cobra.Command, []string) error { return nil }}
+	cmd.Flags().StringP("resource-group", "g", "", "")
+	cmd.Flags().StringP("location", "l", "", "")
+	cmd.Flags().String("ids", "", "")
+	cmd.Flags().StringP("output", "o", "json", "")
+	return cmd
+}
+
+func TestApplyDefaults(t *testing.T) {
+	setupConfigDirs(t)
+	if err := Set([]string{"defaults.group=rg-global", "defaults.location=westus", "core.output=table"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newTestCommand()
+	cmd.ParseFlags([]string{"--location", "eastus"})
+	if err := ApplyDefaults(cmd); err != nil {
+		t.Fatal(err)
+	}
+	for flag, want := range map[string]string{"resource-group": "rg-global", "location": "eastus", "output": "table"} {
+		if got, _ := cmd.Flags().GetString(flag); got != want {
+			t.Errorf("--%s = %q, want %q", flag, got, want)
+		}
+	}
+
+	cmd = newTestCommand()
+	cmd.ParseFlags([]string{"--ids", "/subscriptions/s/resourceGroups/rg/providers/x/y/z"})
+	if err := ApplyDefaults(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cmd.Flags().GetString("resource-group"); got != "" {
+		t.Errorf("--resource-group = %q alongside --ids, want it left empty", got)
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	_, project := setupConfigDirs(t)
+	if err := Set([]string{"defaults.group=rg-global"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, ok, err := config.GetConfigValue("defaults", "group")
+	if err != nil || !ok || entry.Value != "rg-global" {
+		t.Fatalf("global: got %+v, %v, %v", entry, ok, err)
+	}
+
+	if err := Set([]string{"defaults.group=rg-local"}, true); err != nil {
+		t.Fatal(err)
+	}
+	// A local config applies to subdirectories too.
+	sub := filepath.Join(project, "deploy")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	entry, _, _ = config.GetConfigValue("defaults", "group")
+	if entry.Value != "rg-local" || entry.Source != filepath.Join(project, ".azure", "config") {
+		t.Errorf("local: got %+v", entry)
+	}
+
+	t.Setenv("AZURE_DEFAULTS_GROUP", "rg-env")
+	entry, _, _ = config.GetConfigValue("defaults", "group")
+	if entry.Value != "rg-env" || entry.Source != "AZURE_DEFAULTS_GROUP" {
+		t.Errorf("env: got %+v", entry)
+	}
+	os.Unsetenv("AZURE_DEFAULTS_GROUP")
+
+	if err := Unset([]string{"defaults.group"}, true); err != nil {
+		t.Fatal(err)
+	}
+	entry, _, _ = config.GetConfigValue("defaults", "group")
+	if entry.Value != "rg-global" {
+		t.Errorf("after unset --local: got %+v", entry)
+	}
+}
+
+func TestSessionConfigPath(t *testing.T) {
+	home, _ := setupConfigDirs(t)
+	t.Setenv("AZ_SESSION", "ci")
+	path, err := config.GetCLIConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".azure", "config-ci"); path != want {
+		t.Errorf("got %q, want %q", path, want)
+	}
+}
+
+func TestSetRejectsBadKeys(t *testing.T) {
+	setupConfigDirs(t)
+	for _, pair := range []string{"group=rg", "defaults.group", ".group=rg"} {
+		if err := Set([]string{pair}, false); err == nil {
+			t.Errorf("Set(%q) succeeded, want an error", pair)
+		}
+	}
+}
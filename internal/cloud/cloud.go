@@ -0,0 +1,189 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// metadataAPIVersion is the resource manager metadata API version the Python
+// CLI uses to discover a custom cloud's endpoints. It is served by both
+// public ARM and Azure Stack Hub.
+const metadataAPIVersion = "2015-01-01"
+
+func List(cmd *cobra.Command) error {
+	clouds, err := config.ListClouds()
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, clouds)
+}
+
+func Show(cmd *cobra.Command, name string) error {
+	if name == "" {
+		return output.PrintJSON(cmd, config.GetActiveCloud())
+	}
+	c, err := config.GetCloud(name)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, c)
+}
+
+func Set(name string) error {
+	c, err := config.SetActiveCloud(name)
+	if err != nil {
+		return err
+	}
+
+	logger.Println("Switched active cloud to '%s'.", c.Name)
+	if profile, err := config.Load(); err == nil && len(profile.Subscriptions) > 0 &&
+		!strings.EqualFold(profile.Subscriptions[0].EnvironmentName, c.Name) {
+		logger.Println("Use 'az login' to log in to this cloud.")
+	}
+	return nil
+}
+
+func Unregister(name string) error {
+	return config.UnregisterCloud(name)
+}
+
+// Register registers a new custom cloud, or updates an existing one.
+func Register(ctx context.Context, cmd *cobra.Command, update bool) error {
+	name, _ := cmd.Flags().GetString("name")
+
+	var c config.Cloud
+	if update {
+		existing, err := config.GetCloud(name)
+		if err != nil {
+			return err
+		}
+		c = *existing
+	}
+
+	if raw, _ := cmd.Flags().GetString("cloud-config"); raw != "" {
+		if err := readCloudConfig(raw, &c); err != nil {
+			return err
+		}
+	}
+	c.Name = name
+
+	applyFlag(cmd, "endpoint-active-directory", &c.Endpoints.ActiveDirectory)
+	applyFlag(cmd, "endpoint-active-directory-graph-resource-id", &c.Endpoints.ActiveDirectoryGraphResourceID)
+	applyFlag(cmd, "endpoint-active-directory-resource-id", &c.Endpoints.ActiveDirectoryResourceID)
+	applyFlag(cmd, "endpoint-gallery", &c.Endpoints.Gallery)
+	applyFlag(cmd, "endpoint-management", &c.Endpoints.Management)
+	applyFlag(cmd, "endpoint-microsoft-graph-resource-id", &c.Endpoints.MicrosoftGraphResourceID)
+	applyFlag(cmd, "endpoint-resource-manager", &c.Endpoints.ResourceManager)
+	applyFlag(cmd, "suffix-acr-login-server-endpoint", &c.Suffixes.AcrLoginServerEndpoint)
+	applyFlag(cmd, "suffix-keyvault-dns", &c.Suffixes.KeyvaultDNS)
+	applyFlag(cmd, "suffix-sql-server-hostname", &c.Suffixes.SQLServerHostname)
+	applyFlag(cmd, "suffix-storage-endpoint", &c.Suffixes.StorageEndpoint)
+
+	// Like the Python CLI, a resource manager endpoint alone is enough: the
+	// authority and audience come from its metadata endpoint.
+	if c.Endpoints.ResourceManager != "" && c.Endpoints.ActiveDirectory == "" {
+		metadata, err := fetchMetadata(ctx, c.Endpoints.ResourceManager)
+		if err != nil {
+			return err
+		}
+		applyMetadata(&c, metadata)
+	}
+
+	if err := config.RegisterCloud(c, update); err != nil {
+		return err
+	}
+	registered, err := config.GetCloud(c.Name)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, registered)
+}
+
+func applyFlag(cmd *cobra.Command, flag string, dst *string) {
+	if v, _ := cmd.Flags().GetString(flag); v != "" {
+		*dst = v
+	}
+}
+
+// readCloudConfig merges a cloud definition, given inline or as @file, into
+// c. Only the fields present in the document are overwritten.
+func readCloudConfig(raw string, c *config.Cloud) error {
+	data := []byte(raw)
+	if strings.HasPrefix(raw, "@") {
+		path := raw[1:]
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read cloud config file %q: %w", path, err)
+		}
+		data = b
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse cloud config JSON: %w", err)
+	}
+	return nil
+}
+
+// armMetadata is the response of {resourceManager}/metadata/endpoints.
+type armMetadata struct {
+	GalleryEndpoint string `json:"galleryEndpoint"`
+	GraphEndpoint   string `json:"graphEndpoint"`
+	PortalEndpoint  string `json:"portalEndpoint"`
+	Authentication  struct {
+		LoginEndpoint string   `json:"loginEndpoint"`
+		Audiences     []string `json:"audiences"`
+	} `json:"authentication"`
+}
+
+func fetchMetadata(ctx context.Context, resourceManager string) (*armMetadata, error) {
+	url := strings.TrimSuffix(resourceManager, "/") + "/metadata/endpoints?api-version=" + metadataAPIVersion
+	logger.Debug("Discovering cloud endpoints from %s", url)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve cloud metadata from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to retrieve cloud metadata from %s: %s", url, resp.Status)
+	}
+
+	var metadata armMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse cloud metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
+// applyMetadata fills the endpoints the metadata describes, leaving any the
+// user set explicitly alone.
+func applyMetadata(c *config.Cloud, m *armMetadata) {
+	setIfEmpty := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	setIfEmpty(&c.Endpoints.ActiveDirectory, strings.TrimSuffix(m.Authentication.LoginEndpoint, "/"))
+	if len(m.Authentication.Audiences) > 0 {
+		setIfEmpty(&c.Endpoints.ActiveDirectoryResourceID, m.Authentication.Audiences[0])
+		setIfEmpty(&c.Endpoints.Management, m.Authentication.Audiences[0])
+	}
+	setIfEmpty(&c.Endpoints.ActiveDirectoryGraphResourceID, m.GraphEndpoint)
+	setIfEmpty(&c.Endpoints.Gallery, m.GalleryEndpoint)
+	setIfEmpty(&c.Endpoints.Portal, m.PortalEndpoint)
+}
@@ -0,0 +1,52 @@
+package cloud
+
+import (
+	"testing"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+func TestApplyMetadata(t *testing.T) {
+	var m armMetadata
+	m.GalleryEndpoint = "https://portal.local.azurestack.external:30015/"
+	m.GraphEndpoint = "https://graph.windows.net/"
+	m.PortalEndpoint = "https://portal.local.azurestack.external/"
+	m.Authentication.LoginEndpoint = "https://login.microsoftonline.com/"
+	m.Authentication.Audiences = []string{"https://management.contoso.onmicrosoft.com/abc"}
+
+	c := config.Cloud{
+		Name: "AzureStackUser",
+		Endpoints: config.CloudEndpoints{
+			ResourceManager: "https://management.local.azurestack.external",
+			Gallery:         "https://explicit.example/",
+		},
+	}
+	applyMetadata(&c, &m)
+
+	if c.Endpoints.ActiveDirectory != "https://login.microsoftonline.com" {
+		t.Errorf("ActiveDirectory = %q", c.Endpoints.ActiveDirectory)
+	}
+	if c.Endpoints.ActiveDirectoryResourceID != m.Authentication.Audiences[0] {
+		t.Errorf("ActiveDirectoryResourceID = %q", c.Endpoints.ActiveDirectoryResourceID)
+	}
+	if c.Endpoints.Gallery != "https://explicit.example/" {
+		t.Errorf("Gallery = %q, explicit value should win over metadata", c.Endpoints.Gallery)
+	}
+	if c.Endpoints.Portal != m.PortalEndpoint {
+		t.Errorf("Portal = %q", c.Endpoints.Portal)
+	}
+}
+
+func TestReadCloudConfig(t *testing.T) {
+	c := config.Cloud{Suffixes: config.CloudSuffixes{StorageEndpoint: "keep.example"}}
+	raw := `{"endpoints": {"resourceManager": "https://arm.example/", "activeDirectory": "https://login.example"}, "suffixes": {"keyvaultDns": ".vault.example"}}`
+	if err := readCloudConfig(raw, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Endpoints.ResourceManager != "https://arm.example/" || c.Suffixes.KeyvaultDNS != ".vault.example" {
+		t.Errorf("readCloudConfig() = %+v", c)
+	}
+	if c.Suffixes.StorageEndpoint != "keep.example" {
+		t.Errorf("StorageEndpoint = %q, fields absent from the document should be kept", c.Suffixes.StorageEndpoint)
+	}
+}
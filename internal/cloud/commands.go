@@ -0,0 +1,102 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCloudCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cloud",
+		Short: "Manage registered Azure clouds",
+		Long:  "Commands to list, register and switch between Azure clouds (public, US Government, China and custom clouds such as Azure Stack Hub)",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List registered clouds",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return List(cmd)
+		},
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Get the details of a registered cloud (the active cloud by default)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return Show(cmd, name)
+		},
+	}
+	showCmd.Flags().StringP("name", "n", "", "Name of a registered cloud (default: the active cloud)")
+
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the active cloud",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return Set(name)
+		},
+	}
+	setCmd.Flags().StringP("name", "n", "", "Name of a registered cloud")
+	setCmd.MarkFlagRequired("name")
+
+	registerCmd := &cobra.Command{
+		Use:   "register",
+		Short: "Register a custom cloud",
+		Long: `Register a custom cloud. Endpoints can be given as flags, as a JSON document
+with --cloud-config (same shape as 'az cloud show'), or discovered from the
+resource manager's metadata endpoint when only --endpoint-resource-manager is set.`,
+		Example: `  # Azure Stack Hub, discovering the remaining endpoints from ARM metadata
+  az cloud register -n AzureStackUser --endpoint-resource-manager https://management.local.azurestack.external \
+    --suffix-storage-endpoint local.azurestack.external --suffix-keyvault-dns .vault.local.azurestack.external
+
+  # From a JSON file
+  az cloud register -n MyCloud --cloud-config @cloud.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Register(context.Background(), cmd, false)
+		},
+	}
+	addCloudDefinitionFlags(registerCmd)
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the configuration of a custom cloud",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Register(context.Background(), cmd, true)
+		},
+	}
+	addCloudDefinitionFlags(updateCmd)
+
+	unregisterCmd := &cobra.Command{
+		Use:   "unregister",
+		Short: "Unregister a custom cloud",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return Unregister(name)
+		},
+	}
+	unregisterCmd.Flags().StringP("name", "n", "", "Name of a registered custom cloud")
+	unregisterCmd.MarkFlagRequired("name")
+
+	cmd.AddCommand(listCmd, showCmd, setCmd, registerCmd, updateCmd, unregisterCmd)
+	return cmd
+}
+
+func addCloudDefinitionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("name", "n", "", "Name of the cloud")
+	cmd.Flags().String("cloud-config", "", "JSON cloud definition, or @file to read it from a file")
+	cmd.Flags().String("endpoint-active-directory", "", "Microsoft Entra ID authority endpoint")
+	cmd.Flags().String("endpoint-active-directory-graph-resource-id", "", "Legacy Azure AD Graph resource ID")
+	cmd.Flags().String("endpoint-active-directory-resource-id", "", "Resource ID of the management service")
+	cmd.Flags().String("endpoint-gallery", "", "Template gallery endpoint")
+	cmd.Flags().String("endpoint-management", "", "Classic management endpoint")
+	cmd.Flags().String("endpoint-microsoft-graph-resource-id", "", "Microsoft Graph endpoint")
+	cmd.Flags().String("endpoint-resource-manager", "", "Azure Resource Manager endpoint")
+	cmd.Flags().String("suffix-acr-login-server-endpoint", "", "Container registry login server suffix")
+	cmd.Flags().String("suffix-keyvault-dns", "", "Key Vault DNS suffix")
+	cmd.Flags().String("suffix-sql-server-hostname", "", "SQL server hostname suffix")
+	cmd.Flags().String("suffix-storage-endpoint", "", "Storage endpoint suffix")
+	cmd.MarkFlagRequired("name")
+}
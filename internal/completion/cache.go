@@ -0,0 +1,79 @@
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+// cacheDir holds completion results under ~/.azure, one file per lookup.
+const cacheDir = "completion-cache"
+
+// defaultCacheTTL keeps repeated presses of Tab from each paying for an ARM
+// round trip, while names created a few minutes ago still show up.
+const defaultCacheTTL = 2 * time.Minute
+
+type cacheEntry struct {
+	Time   time.Time `json:"time"`
+	Values []string  `json:"values"`
+}
+
+// cacheTTL reads core.completion_cache_ttl (seconds). 0 disables caching.
+func cacheTTL() time.Duration {
+	e, ok, err := config.GetConfigValue("core", "completion_cache_ttl")
+	if err != nil || !ok {
+		return defaultCacheTTL
+	}
+	secs, err := strconv.Atoi(strings.TrimSpace(e.Value))
+	if err != nil || secs < 0 {
+		return defaultCacheTTL
+	}
+	return time.Duration(secs) * time.Second
+}
+
+func cachePath(key []string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(key, "\x00")))
+	return filepath.Join(home, config.ConfigDir, cacheDir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// cached returns the values stored under key if they're younger than ttl,
+// otherwise calls fetch and stores its result. Cache failures never fail
+// completion; they only make it slower.
+func cached(key []string, ttl time.Duration, fetch func() ([]string, error)) ([]string, error) {
+	if ttl <= 0 {
+		return fetch()
+	}
+	path, err := cachePath(key)
+	if err != nil {
+		return fetch()
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		var entry cacheEntry
+		if json.Unmarshal(data, &entry) == nil && time.Since(entry.Time) < ttl {
+			return entry.Values, nil
+		}
+	}
+
+	values, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(cacheEntry{Time: time.Now(), Values: values}); err == nil {
+		if os.MkdirAll(filepath.Dir(path), 0700) == nil {
+			os.WriteFile(path, data, 0600)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,76 @@
+package completion
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCompletionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a completion script for bash, zsh, fish or PowerShell.
+
+Besides commands and flags, --resource-group, --subscription and the -n/--name
+of AKS clusters, VMs, key vaults and storage accounts complete from your
+subscription. Those lookups are cached under ~/.azure/completion-cache for
+core.completion_cache_ttl seconds (default 120; 0 disables the cache).`,
+	}
+
+	bashCmd := &cobra.Command{
+		Use:   "bash",
+		Short: "Generate the bash completion script",
+		Long: `Generate the bash completion script. Requires the bash-completion package.
+
+  # current shell
+  source <(az completion bash)
+
+  # every new shell (Linux)
+  az completion bash > /etc/bash_completion.d/az`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Root().GenBashCompletionV2(cmd.OutOrStdout(), true)
+		},
+	}
+
+	zshCmd := &cobra.Command{
+		Use:   "zsh",
+		Short: "Generate the zsh completion script",
+		Long: `Generate the zsh completion script. Completion must be enabled
+("autoload -U compinit; compinit" in ~/.zshrc).
+
+  az completion zsh > "${fpath[1]}/_az"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Root().GenZshCompletion(cmd.OutOrStdout())
+		},
+	}
+
+	fishCmd := &cobra.Command{
+		Use:   "fish",
+		Short: "Generate the fish completion script",
+		Long: `Generate the fish completion script.
+
+  az completion fish > ~/.config/fish/completions/az.fish`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Root().GenFishCompletion(cmd.OutOrStdout(), true)
+		},
+	}
+
+	powershellCmd := &cobra.Command{
+		Use:   "powershell",
+		Short: "Generate the PowerShell completion script",
+		Long: `Generate the PowerShell completion script.
+
+  az completion powershell | Out-String | Invoke-Expression
+
+Add the line to your PowerShell profile to load it in every session.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Root().GenPowerShellCompletionWithDesc(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.AddCommand(bashCmd, zshCmd, fishCmd, powershellCmd)
+	return cmd
+}
@@ -0,0 +1,173 @@
+package completion
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func setupHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AZ_SESSION", "")
+	t.Chdir(home)
+	return home
+}
+
+func TestCached(t *testing.T) {
+	setupHome(t)
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"rg-a", "rg-b"}, nil
+	}
+	key := []string{"AzureCloud", "sub", "groups"}
+
+	for i := 0; i < 2; i++ {
+		got, err := cached(key, time.Minute, fetch)
+		if err != nil || !reflect.DeepEqual(got, []string{"rg-a", "rg-b"}) {
+			t.Fatalf("got %v, %v", got, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+
+	// An entry older than the TTL is refetched.
+	path, _ := cachePath(key)
+	old := time.Now().Add(-time.Hour)
+	os.WriteFile(path, []byte(`{"time":"`+old.Format(time.RFC3339)+`","values":["stale"]}`), 0600)
+	if got, _ := cached(key, time.Minute, fetch); got[0] != "rg-a" || calls != 2 {
+		t.Errorf("expired entry served: %v (calls %d)", got, calls)
+	}
+
+	// A zero TTL bypasses the cache, and errors aren't cached.
+	cached(key, 0, fetch)
+	if calls != 3 {
+		t.Errorf("zero TTL used the cache")
+	}
+	other := []string{"AzureCloud", "sub", "resources"}
+	if _, err := cached(other, time.Minute, func() ([]string, error) { return nil, errors.New("boom") }); err == nil {
+		t.Fatal("expected the fetch error")
+	}
+	if p, _ := cachePath(other); fileExists(p) {
+		t.Error("failed fetch was cached")
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestCacheTTLFromConfig(t *testing.T) {
+	setupHome(t)
+	if got := cacheTTL(); got != defaultCacheTTL {
+		t.Errorf("default TTL = %v", got)
+	}
+	t.Setenv("AZURE_CORE_COMPLETION_CACHE_TTL", "30")
+	if got := cacheTTL(); got != 30*time.Second {
+		t.Errorf("TTL = %v, want 30s", got)
+	}
+	t.Setenv("AZURE_CORE_COMPLETION_CACHE_TTL", "soon")
+	if got := cacheTTL(); got != defaultCacheTTL {
+		t.Errorf("invalid TTL = %v, want default", got)
+	}
+}
+
+func newTree() *cobra.Command {
+	leaf := func(use string, flags ...string) *cobra.Command {
+		c := &cobra.Command{Use: use, RunE: func(*cobra.Command, []string) error { return nil }}
+		for _, f := range flags {
+			c.Flags().String(f, "", "")
+		}
+		return c
+	}
+	group := func(use string, children ...*cobra.Command) *cobra.Command {
+		c := &cobra.Command{Use: use}
+		c.AddCommand(children...)
+		return c
+	}
+
+	root := &cobra.Command{Use: "az"}
+	root.PersistentFlags().String("subscription", "", "")
+	root.PersistentFlags().String("output", "json", "")
+	root.AddCommand(
+		group("aks",
+			leaf("show", "name", "resource-group"),
+			leaf("create", "name", "resource-group"),
+			group("nodepool", leaf("show", "name", "cluster-name", "resource-group")),
+		),
+		group("group", leaf("show", "name")),
+		group("hdinsight", leaf("show", "name", "cluster-name")),
+	)
+	return root
+}
+
+func TestRegister(t *testing.T) {
+	root := newTree()
+	Register(root)
+
+	tests := []struct {
+		path []string
+		flag string
+		want bool
+	}{
+		{[]string{"aks", "show"}, "name", true},
+		{[]string{"aks", "show"}, "resource-group", true},
+		{[]string{"aks", "create"}, "name", false},
+		{[]string{"aks", "create"}, "resource-group", true},
+		{[]string{"aks", "nodepool", "show"}, "name", false},
+		{[]string{"aks", "nodepool", "show"}, "cluster-name", true},
+		{[]string{"group", "show"}, "name", true},
+		{[]string{"hdinsight", "show"}, "name", false},
+		{[]string{"hdinsight", "show"}, "cluster-name", false},
+		{nil, "subscription", true},
+		{nil, "output", true},
+	}
+	for _, tt := range tests {
+		cmd, _, err := root.Find(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, ok := cmd.GetFlagCompletionFunc(tt.flag)
+		if ok != tt.want {
+			t.Errorf("%s --%s: registered = %v, want %v", cmd.CommandPath(), tt.flag, ok, tt.want)
+		}
+	}
+}
+
+func TestCompleteSubscriptions(t *testing.T) {
+	home := setupHome(t)
+	profile := `{"subscriptions": [
+		{"id": "1111-aaaa", "name": "Production", "isDefault": true},
+		{"id": "2222-bbbb", "name": "Dev"}
+	]}`
+	os.MkdirAll(filepath.Join(home, ".azure"), 0700)
+	if err := os.WriteFile(filepath.Join(home, ".azure", "azureProfile.json"), []byte(profile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, directive := completeSubscriptions(nil, nil, "")
+	want := []string{"1111-aaaa\tProduction", "Production\t1111-aaaa", "2222-bbbb\tDev", "Dev\t2222-bbbb"}
+	if !reflect.DeepEqual(got, want) || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("got %q, %v", got, directive)
+	}
+	got, _ = completeSubscriptions(nil, nil, "pro")
+	if !reflect.DeepEqual(got, []string{"Production\t1111-aaaa"}) {
+		t.Errorf("prefix: got %q", got)
+	}
+}
+
+func TestFilterPrefix(t *testing.T) {
+	got := filterPrefix([]string{"aks-prod", "AKS-dev", "vm1"}, "aks")
+	if !reflect.DeepEqual(got, []string{"aks-prod", "AKS-dev"}) {
+		t.Errorf("got %v", got)
+	}
+}
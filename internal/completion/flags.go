@@ -0,0 +1,96 @@
+package completion
+
+import (
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	managedClusters = "Microsoft.ContainerService/managedClusters"
+	virtualMachines = "Microsoft.Compute/virtualMachines"
+	vaults          = "Microsoft.KeyVault/vaults"
+	storageAccounts = "Microsoft.Storage/storageAccounts"
+)
+
+// nameTypes maps the command groups whose -n/--name is an existing resource
+// to that resource's type. Groups not listed use --name for something else
+// (a node pool, a secret, ...).
+var nameTypes = map[string]string{
+	"az aks":                   managedClusters,
+	"az aks command":           managedClusters,
+	"az aks oidc-issuer":       managedClusters,
+	"az aks operation":         managedClusters,
+	"az vm":                    virtualMachines,
+	"az vm boot-diagnostics":   virtualMachines,
+	"az vm identity":           virtualMachines,
+	"az keyvault":              vaults,
+	"az keyvault network-rule": vaults,
+	"az storage account":       storageAccounts,
+}
+
+// parentFlags are flags naming the parent resource of a subcommand, such as
+// `az aks nodepool list --cluster-name`. They complete anywhere under
+// prefix.
+var parentFlags = []struct {
+	prefix, flag, resourceType string
+}{
+	{"az aks", "cluster-name", managedClusters},
+	{"az vm", "vm-name", virtualMachines},
+	{"az keyvault", "vault-name", vaults},
+	{"az storage", "account-name", storageAccounts},
+}
+
+// newNameCommands take the name of a resource that doesn't exist yet, or of
+// a deleted one ARM no longer lists, so completing existing names would only
+// mislead.
+var newNameCommands = map[string]bool{
+	"create":       true,
+	"check-name":   true,
+	"show-deleted": true,
+	"purge":        true,
+	"recover":      true,
+}
+
+// Register adds dynamic flag completion to every command under root. Call it
+// once the command tree is complete.
+func Register(root *cobra.Command) {
+	root.RegisterFlagCompletionFunc("subscription", completeSubscriptions)
+	root.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return output.Formats, cobra.ShellCompDirectiveNoFileComp
+	})
+	walk(root, registerCommand)
+}
+
+func walk(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+	for _, child := range cmd.Commands() {
+		walk(child, fn)
+	}
+}
+
+func registerCommand(cmd *cobra.Command) {
+	flags := cmd.LocalNonPersistentFlags()
+	if flags.Lookup("resource-group") != nil {
+		cmd.RegisterFlagCompletionFunc("resource-group", completeResourceGroups)
+	}
+	for _, pf := range parentFlags {
+		if flags.Lookup(pf.flag) != nil && underPrefix(cmd.CommandPath(), pf.prefix) {
+			cmd.RegisterFlagCompletionFunc(pf.flag, completeResourceNames(pf.resourceType))
+		}
+	}
+	if !cmd.HasParent() || flags.Lookup("name") == nil || newNameCommands[cmd.Name()] {
+		return
+	}
+	switch parent := cmd.Parent().CommandPath(); {
+	case parent == "az group":
+		cmd.RegisterFlagCompletionFunc("name", completeResourceGroups)
+	case nameTypes[parent] != "":
+		cmd.RegisterFlagCompletionFunc("name", completeResourceNames(nameTypes[parent]))
+	}
+}
+
+func underPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+" ")
+}
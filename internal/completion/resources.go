@@ -0,0 +1,170 @@
+package completion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/internal/cliconfig"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// lookupTimeout bounds a live lookup; a shell waiting on Tab is worse off
+// with a hang than with no suggestions.
+const lookupTimeout = 10 * time.Second
+
+type completeFunc func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)
+
+// completeSubscriptions offers the IDs and names in azureProfile.json. It
+// never touches the network, so it isn't cached.
+func completeSubscriptions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profile, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	for _, s := range profile.Subscriptions {
+		if strings.HasPrefix(s.ID, toComplete) {
+			out = append(out, s.ID+"\t"+s.Name)
+		}
+		if s.Name != "" && strings.HasPrefix(strings.ToLower(s.Name), strings.ToLower(toComplete)) {
+			out = append(out, s.Name+"\t"+s.ID)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeResourceGroups(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	subscriptionID, err := completionSubscription(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	key := []string{azure.ActiveCloud().Name, subscriptionID, "groups"}
+	names, err := cached(key, cacheTTL(), func() ([]string, error) {
+		return listResourceGroups(subscriptionID)
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeResourceNames completes the names of resources of resourceType,
+// within --resource-group when one is given or configured.
+func completeResourceNames(resourceType string) completeFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		subscriptionID, err := completionSubscription(cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var group string
+		if cmd.Flags().Lookup("resource-group") != nil {
+			group, _ = cmd.Flags().GetString("resource-group")
+		}
+		key := []string{azure.ActiveCloud().Name, subscriptionID, "resources", strings.ToLower(group), strings.ToLower(resourceType)}
+		names, err := cached(key, cacheTTL(), func() ([]string, error) {
+			return listResourceNames(subscriptionID, group, resourceType)
+		})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionSubscription resolves the subscription a completed command
+// would run against. Cobra skips PersistentPreRun while completing, so the
+// configured defaults are applied here; a broken config only costs defaults.
+func completionSubscription(cmd *cobra.Command) (string, error) {
+	cliconfig.ApplyDefaults(cmd)
+	sub, _ := cmd.Flags().GetString("subscription")
+	return config.GetSubscription(sub)
+}
+
+func listResourceGroups(subscriptionID string) ([]string, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource groups client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	var names []string
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resource groups: %w", err)
+		}
+		for _, g := range page.Value {
+			names = append(names, azure.GetStringValue(g.Name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func listResourceNames(subscriptionID, group, resourceType string) ([]string, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+	client, err := armresources.NewClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resources client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	filter := fmt.Sprintf("resourceType eq '%s'", resourceType)
+	var names []string
+	if group != "" {
+		pager := client.NewListByResourceGroupPager(group, &armresources.ClientListByResourceGroupOptions{Filter: &filter})
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list resources: %w", err)
+			}
+			for _, r := range page.Value {
+				names = append(names, azure.GetStringValue(r.Name))
+			}
+		}
+	} else {
+		pager := client.NewListPager(&armresources.ClientListOptions{Filter: &filter})
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list resources: %w", err)
+			}
+			for _, r := range page.Value {
+				names = append(names, azure.GetStringValue(r.Name))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// filterPrefix keeps the names starting with prefix. Azure names are
+// case-insensitive, so matching is too.
+func filterPrefix(names []string, prefix string) []string {
+	var out []string
+	lower := strings.ToLower(prefix)
+	for _, n := range names {
+		if strings.HasPrefix(strings.ToLower(n), lower) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
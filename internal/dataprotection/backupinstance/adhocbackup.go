@@ -49,7 +49,7 @@ func AdhocBackup(cmd *cobra.Command, ctx context.Context, resourceGroup, vaultNa
     return err
   }
 
-  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup instances client: %w", err)
   }
@@ -47,7 +47,7 @@ func CreateBackupInstance(ctx context.Context, cmd *cobra.Command, resourceGroup
     return err
   }
 
-  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup instances client: %w", err)
   }
@@ -44,7 +44,7 @@ func DeleteBackupInstance(ctx context.Context, resourceGroup, vaultName, name st
     return err
   }
 
-  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup instances client: %w", err)
   }
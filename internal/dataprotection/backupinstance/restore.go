@@ -50,7 +50,7 @@ func TriggerRestore(ctx context.Context, cmd *cobra.Command, resourceGroup, vaul
     return err
   }
 
-  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupInstancesClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup instances client: %w", err)
   }
@@ -48,7 +48,7 @@ func CreateBackupPolicy(ctx context.Context, cmd *cobra.Command, resourceGroup,
     return err
   }
 
-  client, err := armdataprotection.NewBackupPoliciesClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupPoliciesClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup policies client: %w", err)
   }
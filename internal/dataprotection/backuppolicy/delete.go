@@ -42,7 +42,7 @@ func DeleteBackupPolicy(ctx context.Context, resourceGroup, vaultName, name stri
     return err
   }
 
-  client, err := armdataprotection.NewBackupPoliciesClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupPoliciesClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup policies client: %w", err)
   }
@@ -43,7 +43,7 @@ func ShowBackupPolicy(ctx context.Context, cmd *cobra.Command, resourceGroup, va
     return err
   }
 
-  client, err := armdataprotection.NewBackupPoliciesClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupPoliciesClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup policies client: %w", err)
   }
@@ -50,7 +50,7 @@ func CreateBackupVault(ctx context.Context, cmd *cobra.Command, resourceGroup, v
     return err
   }
 
-  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup vaults client: %w", err)
   }
@@ -41,7 +41,7 @@ func DeleteBackupVault(ctx context.Context, resourceGroup, vaultName string, noW
     return err
   }
 
-  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup vaults client: %w", err)
   }
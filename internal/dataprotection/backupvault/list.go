@@ -36,7 +36,7 @@ func ListBackupVaults(ctx context.Context, cmd *cobra.Command, resourceGroup str
     return err
   }
 
-  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup vaults client: %w", err)
   }
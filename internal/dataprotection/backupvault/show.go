@@ -40,7 +40,7 @@ func ShowBackupVault(ctx context.Context, cmd *cobra.Command, resourceGroup, vau
     return err
   }
 
-  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup vaults client: %w", err)
   }
@@ -7,6 +7,7 @@ import (
   "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dataprotection/armdataprotection/v3"
   "github.com/cdobbyn/azure-go-cli/pkg/azure"
   "github.com/cdobbyn/azure-go-cli/pkg/config"
+  "github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
   "github.com/cdobbyn/azure-go-cli/pkg/output"
   "github.com/spf13/cobra"
 )
@@ -15,7 +16,7 @@ func newUpdateCommand() *cobra.Command {
   cmd := &cobra.Command{
     Use:   "update",
     Short: "Update a backup vault",
-    Long:  "Updates tags on a backup vault",
+    Long:  "Updates tags on a backup vault, or any other property with --set/--add/--remove",
     RunE: func(cmd *cobra.Command, args []string) error {
       resourceGroup, _ := cmd.Flags().GetString("resource-group")
       vaultName, _ := cmd.Flags().GetString("vault-name")
@@ -28,6 +29,7 @@ func newUpdateCommand() *cobra.Command {
   cmd.Flags().StringP("vault-name", "v", "", "Name of the backup vault")
   cmd.Flags().StringToString("tags", nil, "Space-separated tags in key=value format")
   cmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
+  genericupdate.AddFlags(cmd)
   cmd.MarkFlagRequired("resource-group")
   cmd.MarkFlagRequired("vault-name")
   return cmd
@@ -44,19 +46,38 @@ func UpdateBackupVault(ctx context.Context, cmd *cobra.Command, resourceGroup, v
     return err
   }
 
-  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewBackupVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create backup vaults client: %w", err)
   }
 
-  tagPtrs := make(map[string]*string, len(tags))
-  for k, v := range tags {
-    v := v
-    tagPtrs[k] = &v
+  patchInput := armdataprotection.PatchResourceRequestInput{}
+  ops, err := genericupdate.OpsFromFlags(cmd)
+  if err != nil {
+    return err
+  }
+  if len(ops) > 0 {
+    current, err := client.Get(ctx, resourceGroup, vaultName, nil)
+    if err != nil {
+      return fmt.Errorf("failed to get backup vault: %w", err)
+    }
+    if err := genericupdate.Seed(&patchInput, current.BackupVaultResource); err != nil {
+      return err
+    }
   }
 
-  patchInput := armdataprotection.PatchResourceRequestInput{
-    Tags: tagPtrs,
+  if cmd.Flags().Changed("tags") {
+    tagPtrs := make(map[string]*string, len(tags))
+    for k, v := range tags {
+      v := v
+      tagPtrs[k] = &v
+    }
+    patchInput.Tags = tagPtrs
+  }
+  if len(ops) > 0 {
+    if err := genericupdate.ApplyModel(&patchInput, ops); err != nil {
+      return err
+    }
   }
 
   poller, err := client.BeginUpdate(ctx, resourceGroup, vaultName, patchInput, nil)
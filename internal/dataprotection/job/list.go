@@ -39,7 +39,7 @@ func ListJobs(ctx context.Context, cmd *cobra.Command, resourceGroup, vaultName
     return err
   }
 
-  client, err := armdataprotection.NewJobsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewJobsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create jobs client: %w", err)
   }
@@ -42,7 +42,7 @@ func ListRecoveryPoints(ctx context.Context, cmd *cobra.Command, resourceGroup,
     return err
   }
 
-  client, err := armdataprotection.NewRecoveryPointsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewRecoveryPointsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create recovery points client: %w", err)
   }
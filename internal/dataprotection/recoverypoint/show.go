@@ -45,7 +45,7 @@ func ShowRecoveryPoint(ctx context.Context, cmd *cobra.Command, resourceGroup, v
     return err
   }
 
-  client, err := armdataprotection.NewRecoveryPointsClient(subscriptionID, cred, nil)
+  client, err := armdataprotection.NewRecoveryPointsClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create recovery points client: %w", err)
   }
@@ -0,0 +1,195 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewDeploymentCommand wires `az deployment`: one group per scope, each with
+// the same verbs, plus `az deployment operation`.
+func NewDeploymentCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deployment",
+		Short: "Manage Azure Resource Manager template deployments",
+		Long:  "Deploy ARM templates at resource group, subscription, management group or tenant scope, preview them with what-if, and inspect deployment history",
+	}
+	cmd.AddCommand(
+		newScopeCommand(groupScope, "Manage deployments at resource group scope"),
+		newScopeCommand(subscriptionScope, "Manage deployments at subscription scope"),
+		newScopeCommand(managementGroupScope, "Manage deployments at management group scope"),
+		newScopeCommand(tenantScope, "Manage deployments at tenant scope"),
+		newOperationCommand(),
+	)
+	return cmd
+}
+
+func newScopeCommand(kind scopeKind, short string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   scopeNames[kind],
+		Short: short,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Start a deployment",
+		Long:  "Deploy a template and wait for it to finish, printing each resource operation to stderr as its state changes.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Create(context.Background(), cmd, kind)
+		},
+	}
+	addTemplateFlags(createCmd, kind)
+	createCmd.Flags().Bool("no-wait", false, "Do not wait for the deployment to finish")
+	createCmd.Flags().BoolP("what-if", "w", false, "Preview the changes instead of deploying")
+	addWhatIfFlags(createCmd)
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate whether a template is syntactically correct and would be accepted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Validate(context.Background(), cmd, kind)
+		},
+	}
+	addTemplateFlags(validateCmd, kind)
+
+	whatIfCmd := &cobra.Command{
+		Use:   "what-if",
+		Short: "Preview the changes a deployment would make",
+		Long:  "Show the resources a deployment would create, modify or delete as a change tree. Use --no-pretty-print for the raw result.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return WhatIf(context.Background(), cmd, kind)
+		},
+	}
+	addTemplateFlags(whatIfCmd, kind)
+	addWhatIfFlags(whatIfCmd)
+	whatIfCmd.Flags().Bool("no-pretty-print", false, "Print the what-if result as JSON instead of a change tree")
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a deployment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return Show(context.Background(), cmd, kind, name)
+		},
+	}
+	addNameFlags(showCmd, kind)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List deployments",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, _ := cmd.Flags().GetString("filter")
+			return List(context.Background(), cmd, kind, filter)
+		},
+	}
+	addScopeFlags(listCmd, kind)
+	listCmd.Flags().String("filter", "", "OData filter, e.g. \"provisioningState eq 'Failed'\"")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a deployment from the deployment history",
+		Long:  "Delete a deployment from the deployment history. The resources it deployed are not deleted.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			noWait, _ := cmd.Flags().GetBool("no-wait")
+			return Delete(context.Background(), cmd, kind, name, noWait)
+		},
+	}
+	addNameFlags(deleteCmd, kind)
+	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+
+	cancelCmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a running deployment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return Cancel(context.Background(), cmd, kind, name)
+		},
+	}
+	addNameFlags(cancelCmd, kind)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the template used by a deployment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return Export(context.Background(), cmd, kind, name)
+		},
+	}
+	addNameFlags(exportCmd, kind)
+
+	cmd.AddCommand(createCmd, validateCmd, whatIfCmd, showCmd, listCmd, deleteCmd, cancelCmd, exportCmd)
+	return cmd
+}
+
+func newOperationCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "operation",
+		Short: "Manage deployment operations",
+	}
+	for _, kind := range []scopeKind{groupScope, subscriptionScope, managementGroupScope, tenantScope} {
+		group := &cobra.Command{
+			Use:   scopeNames[kind],
+			Short: fmt.Sprintf("Manage operations of deployments at %s scope", scopeLabel(kind)),
+		}
+		group.AddCommand(newOperationListCmd(kind))
+		cmd.AddCommand(group)
+	}
+	// `az deployment operation list` is the subscription-scope form the
+	// Python CLI started with.
+	cmd.AddCommand(newOperationListCmd(subscriptionScope))
+	return cmd
+}
+
+func newOperationListCmd(kind scopeKind) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the operations of a deployment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return ListOperations(context.Background(), cmd, kind, name)
+		},
+	}
+	addNameFlags(cmd, kind)
+	return cmd
+}
+
+func scopeLabel(kind scopeKind) string {
+	switch kind {
+	case groupScope:
+		return "resource group"
+	case subscriptionScope:
+		return "subscription"
+	case managementGroupScope:
+		return "management group"
+	}
+	return "tenant"
+}
+
+// addNameFlags registers the flags naming an existing deployment.
+func addNameFlags(cmd *cobra.Command, kind scopeKind) {
+	cmd.Flags().StringP("name", "n", "", "Deployment name")
+	cmd.MarkFlagRequired("name")
+	addScopeFlags(cmd, kind)
+}
+
+// addTemplateFlags registers the flags of create, validate and what-if.
+func addTemplateFlags(cmd *cobra.Command, kind scopeKind) {
+	cmd.Flags().StringP("name", "n", "", "Deployment name (defaults to the template file name)")
+	addScopeFlags(cmd, kind)
+	cmd.Flags().StringP("template-file", "f", "", "Path to a JSON template file")
+	cmd.Flags().StringP("template-uri", "u", "", "URI of a remote template file")
+	cmd.Flags().StringArrayP("parameters", "p", nil, "Parameters: a parameters file (path or @path), inline JSON, or key=value (key=@path reads the value from a file). Repeatable; later values win")
+	if kind == groupScope {
+		cmd.Flags().String("mode", "Incremental", "Deployment mode: Incremental or Complete")
+	} else {
+		cmd.Flags().StringP("location", "l", "", "Location to store the deployment metadata")
+		cmd.MarkFlagRequired("location")
+	}
+}
+
+func addWhatIfFlags(cmd *cobra.Command) {
+	cmd.Flags().String("result-format", "FullResourcePayloads", "What-if result format: FullResourcePayloads or ResourceIdOnly")
+	cmd.Flags().StringSlice("exclude-change-types", nil, "Change types to leave out of the what-if result (Create, Delete, Modify, Deploy, Ignore, NoChange, Unsupported)")
+}
@@ -0,0 +1,162 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// request is what create, validate and what-if share: where to deploy, what
+// and under which name.
+type request struct {
+	scope    scope
+	source   *source
+	name     string
+	location string
+	mode     armresources.DeploymentMode
+}
+
+func newRequest(ctx context.Context, cmd *cobra.Command, kind scopeKind) (*request, error) {
+	s, err := resolveScope(cmd, kind)
+	if err != nil {
+		return nil, err
+	}
+	src, err := loadSource(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	r := &request{scope: s, source: src, mode: armresources.DeploymentModeIncremental}
+	r.name, _ = cmd.Flags().GetString("name")
+	if r.name == "" {
+		r.name = src.defaultName
+	}
+	if kind != groupScope {
+		r.location, _ = cmd.Flags().GetString("location")
+	}
+	if cmd.Flags().Lookup("mode") != nil {
+		mode, _ := cmd.Flags().GetString("mode")
+		r.mode = armresources.DeploymentMode(mode)
+		if r.mode != armresources.DeploymentModeIncremental && r.mode != armresources.DeploymentModeComplete {
+			return nil, fmt.Errorf("invalid --mode %q (expected Incremental or Complete)", mode)
+		}
+	}
+	return r, nil
+}
+
+// deployment returns the request body for group, subscription and
+// management group scopes.
+func (r *request) deployment() armresources.Deployment {
+	d := armresources.Deployment{Properties: r.source.properties(r.mode)}
+	if r.location != "" {
+		d.Location = to.Ptr(r.location)
+	}
+	return d
+}
+
+// scopedDeployment returns the request body for the tenant scope.
+func (r *request) scopedDeployment() armresources.ScopedDeployment {
+	return armresources.ScopedDeployment{Location: to.Ptr(r.location), Properties: r.source.properties(r.mode)}
+}
+
+// Create starts a deployment and, unless --no-wait is given, follows it to
+// the end, printing each resource operation as it changes state.
+func Create(ctx context.Context, cmd *cobra.Command, kind scopeKind) error {
+	r, err := newRequest(ctx, cmd, kind)
+	if err != nil {
+		return err
+	}
+	if whatIf, _ := cmd.Flags().GetBool("what-if"); whatIf {
+		return runWhatIf(ctx, cmd, r)
+	}
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+
+	client, err := newDeploymentsClient(r.scope)
+	if err != nil {
+		return err
+	}
+	opsClient, err := newOperationsClient(r.scope)
+	if err != nil {
+		return err
+	}
+	p := newProgress(cmd.ErrOrStderr(), opsClient, r.scope, r.name)
+
+	var result armresources.DeploymentExtended
+	if r.scope.isTenant() {
+		poller, err := client.BeginCreateOrUpdateAtTenantScope(ctx, r.name, r.scopedDeployment(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to start deployment: %w", err)
+		}
+		if noWait {
+			return printStarted(cmd, r)
+		}
+		resp, err := wait(ctx, poller, p)
+		if err != nil {
+			return fmt.Errorf("deployment '%s' failed: %w", r.name, err)
+		}
+		result = resp.DeploymentExtended
+	} else {
+		poller, err := client.BeginCreateOrUpdateAtScope(ctx, r.scope.path(), r.name, r.deployment(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to start deployment: %w", err)
+		}
+		if noWait {
+			return printStarted(cmd, r)
+		}
+		resp, err := wait(ctx, poller, p)
+		if err != nil {
+			return fmt.Errorf("deployment '%s' failed: %w", r.name, err)
+		}
+		result = resp.DeploymentExtended
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+// printStarted reports a deployment left running by --no-wait, with the ID
+// to follow it by.
+func printStarted(cmd *cobra.Command, r *request) error {
+	return output.PrintJSON(cmd, map[string]string{
+		"id":     r.scope.deploymentID(r.name),
+		"name":   r.name,
+		"status": fmt.Sprintf("Deployment '%s' started.", r.name),
+	})
+}
+
+// Validate checks that the template would be accepted, without deploying.
+func Validate(ctx context.Context, cmd *cobra.Command, kind scopeKind) error {
+	r, err := newRequest(ctx, cmd, kind)
+	if err != nil {
+		return err
+	}
+	client, err := newDeploymentsClient(r.scope)
+	if err != nil {
+		return err
+	}
+
+	var result armresources.DeploymentValidateResult
+	if r.scope.isTenant() {
+		poller, err := client.BeginValidateAtTenantScope(ctx, r.name, r.scopedDeployment(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to validate deployment: %w", err)
+		}
+		resp, err := poller.PollUntilDone(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to validate deployment: %w", err)
+		}
+		result = resp.DeploymentValidateResult
+	} else {
+		poller, err := client.BeginValidateAtScope(ctx, r.scope.path(), r.name, r.deployment(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to validate deployment: %w", err)
+		}
+		resp, err := poller.PollUntilDone(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to validate deployment: %w", err)
+		}
+		result = resp.DeploymentValidateResult
+	}
+	return output.PrintJSON(cmd, result)
+}
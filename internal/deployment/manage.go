@@ -0,0 +1,173 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func Show(ctx context.Context, cmd *cobra.Command, kind scopeKind, name string) error {
+	s, err := resolveScope(cmd, kind)
+	if err != nil {
+		return err
+	}
+	client, err := newDeploymentsClient(s)
+	if err != nil {
+		return err
+	}
+	if s.isTenant() {
+		resp, err := client.GetAtTenantScope(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get deployment: %w", err)
+		}
+		return output.PrintJSON(cmd, resp.DeploymentExtended)
+	}
+	resp, err := client.GetAtScope(ctx, s.path(), name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+	return output.PrintJSON(cmd, resp.DeploymentExtended)
+}
+
+// List lists the deployments at a scope, optionally only those in one
+// provisioning state (--filter "provisioningState eq 'Failed'").
+func List(ctx context.Context, cmd *cobra.Command, kind scopeKind, filter string) error {
+	s, err := resolveScope(cmd, kind)
+	if err != nil {
+		return err
+	}
+	client, err := newDeploymentsClient(s)
+	if err != nil {
+		return err
+	}
+	var f *string
+	if filter != "" {
+		f = &filter
+	}
+
+	var deployments []*armresources.DeploymentExtended
+	if s.isTenant() {
+		pager := client.NewListAtTenantScopePager(&armresources.DeploymentsClientListAtTenantScopeOptions{Filter: f})
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list deployments: %w", err)
+			}
+			deployments = append(deployments, page.Value...)
+		}
+	} else {
+		pager := client.NewListAtScopePager(s.path(), &armresources.DeploymentsClientListAtScopeOptions{Filter: f})
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list deployments: %w", err)
+			}
+			deployments = append(deployments, page.Value...)
+		}
+	}
+	return output.PrintJSON(cmd, deployments)
+}
+
+// Delete removes a deployment from the history. The resources it deployed
+// are left alone.
+func Delete(ctx context.Context, cmd *cobra.Command, kind scopeKind, name string, noWait bool) error {
+	s, err := resolveScope(cmd, kind)
+	if err != nil {
+		return err
+	}
+	client, err := newDeploymentsClient(s)
+	if err != nil {
+		return err
+	}
+	if s.isTenant() {
+		poller, err := client.BeginDeleteAtTenantScope(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to delete deployment: %w", err)
+		}
+		if !noWait {
+			if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+				return fmt.Errorf("failed to delete deployment: %w", err)
+			}
+		}
+		return nil
+	}
+	poller, err := client.BeginDeleteAtScope(ctx, s.path(), name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete deployment: %w", err)
+	}
+	if !noWait {
+		if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+			return fmt.Errorf("failed to delete deployment: %w", err)
+		}
+	}
+	return nil
+}
+
+// Cancel stops a running deployment. ARM leaves the resources it already
+// deployed in place.
+func Cancel(ctx context.Context, cmd *cobra.Command, kind scopeKind, name string) error {
+	s, err := resolveScope(cmd, kind)
+	if err != nil {
+		return err
+	}
+	client, err := newDeploymentsClient(s)
+	if err != nil {
+		return err
+	}
+	if s.isTenant() {
+		_, err = client.CancelAtTenantScope(ctx, name, nil)
+	} else {
+		_, err = client.CancelAtScope(ctx, s.path(), name, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel deployment: %w", err)
+	}
+	return nil
+}
+
+// Export prints the template a deployment used.
+func Export(ctx context.Context, cmd *cobra.Command, kind scopeKind, name string) error {
+	s, err := resolveScope(cmd, kind)
+	if err != nil {
+		return err
+	}
+	client, err := newDeploymentsClient(s)
+	if err != nil {
+		return err
+	}
+	var template interface{}
+	if s.isTenant() {
+		resp, err := client.ExportTemplateAtTenantScope(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to export deployment template: %w", err)
+		}
+		template = resp.Template
+	} else {
+		resp, err := client.ExportTemplateAtScope(ctx, s.path(), name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to export deployment template: %w", err)
+		}
+		template = resp.Template
+	}
+	return output.PrintJSON(cmd, template)
+}
+
+// ListOperations lists the per-resource operations of a deployment.
+func ListOperations(ctx context.Context, cmd *cobra.Command, kind scopeKind, name string) error {
+	s, err := resolveScope(cmd, kind)
+	if err != nil {
+		return err
+	}
+	client, err := newOperationsClient(s)
+	if err != nil {
+		return err
+	}
+	ops, err := listOperations(ctx, client, s, name)
+	if err != nil {
+		return fmt.Errorf("failed to list deployment operations: %w", err)
+	}
+	return output.PrintJSON(cmd, ops)
+}
@@ -0,0 +1,147 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+)
+
+// pollInterval is how often a running deployment and its operations are
+// checked.
+const pollInterval = 5 * time.Second
+
+// progress prints a line to w whenever one of a deployment's operations
+// changes state, so a long deployment shows which resources are done.
+type progress struct {
+	w     io.Writer
+	list  func(ctx context.Context) ([]*armresources.DeploymentOperation, error)
+	state map[string]string
+}
+
+func newProgress(w io.Writer, client *armresources.DeploymentOperationsClient, s scope, name string) *progress {
+	return &progress{
+		w: w,
+		list: func(ctx context.Context) ([]*armresources.DeploymentOperation, error) {
+			return listOperations(ctx, client, s, name)
+		},
+		state: map[string]string{},
+	}
+}
+
+// report prints the operations that changed since the last call. Listing
+// failures only cost progress output, never the deployment.
+func (p *progress) report(ctx context.Context) {
+	ops, err := p.list(ctx)
+	if err != nil {
+		logger.Debug("Failed to list deployment operations: %v", err)
+		return
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		ti, tj := timestamp(ops[i]), timestamp(ops[j])
+		return ti.Before(tj)
+	})
+	for _, op := range ops {
+		if op.Properties == nil || op.Properties.TargetResource == nil || op.OperationID == nil {
+			continue
+		}
+		props := op.Properties
+		state := deref(props.ProvisioningState)
+		if state == "" || p.state[*op.OperationID] == state {
+			continue
+		}
+		p.state[*op.OperationID] = state
+
+		target := deref(props.TargetResource.ResourceType) + "/" + deref(props.TargetResource.ResourceName)
+		line := fmt.Sprintf("%-10s %s", state, target)
+		if d := isoDuration(deref(props.Duration)); d > 0 && state != "Running" && state != "Accepted" {
+			line += fmt.Sprintf(" (%s)", d.Round(time.Second))
+		}
+		if props.StatusMessage != nil && props.StatusMessage.Error != nil && strings.EqualFold(state, "Failed") {
+			e := props.StatusMessage.Error
+			line += fmt.Sprintf(": %s: %s", deref(e.Code), deref(e.Message))
+		}
+		fmt.Fprintln(p.w, line)
+	}
+}
+
+func timestamp(op *armresources.DeploymentOperation) time.Time {
+	if op.Properties == nil || op.Properties.Timestamp == nil {
+		return time.Time{}
+	}
+	return *op.Properties.Timestamp
+}
+
+var isoDurationRE = regexp.MustCompile(`^P(?:(\d+)D)?T?(?:(\d+)H)?(?:(\d+)M)?(?:([\d.]+)S)?$`)
+
+// isoDuration parses the ISO 8601 durations ARM reports, e.g. PT1M3.5S.
+func isoDuration(s string) time.Duration {
+	m := isoDurationRE.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	var d time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	for i, u := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		f, _ := strconv.ParseFloat(m[i+1], 64)
+		d += time.Duration(f * float64(u))
+	}
+	return d
+}
+
+// wait polls poller to completion, reporting progress after every poll.
+func wait[T any](ctx context.Context, poller *runtime.Poller[T], p *progress) (T, error) {
+	for !poller.Done() {
+		if _, err := poller.Poll(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		p.report(ctx)
+		if poller.Done() {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+	p.report(ctx)
+	return poller.Result(ctx)
+}
+
+func listOperations(ctx context.Context, client *armresources.DeploymentOperationsClient, s scope, name string) ([]*armresources.DeploymentOperation, error) {
+	var ops []*armresources.DeploymentOperation
+	if s.isTenant() {
+		pager := client.NewListAtTenantScopePager(name, nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, page.Value...)
+		}
+		return ops, nil
+	}
+	pager := client.NewListAtScopePager(s.path(), name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, page.Value...)
+	}
+	return ops, nil
+}
@@ -0,0 +1,81 @@
+package deployment
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+func operation(id, state, duration string, at time.Time) *armresources.DeploymentOperation {
+	return &armresources.DeploymentOperation{
+		OperationID: to.Ptr(id),
+		Properties: &armresources.DeploymentOperationProperties{
+			ProvisioningState: to.Ptr(state),
+			Duration:          to.Ptr(duration),
+			Timestamp:         to.Ptr(at),
+			TargetResource: &armresources.TargetResource{
+				ResourceType: to.Ptr("Microsoft.Storage/storageAccounts"),
+				ResourceName: to.Ptr("sa" + id),
+			},
+		},
+	}
+}
+
+func TestProgressReportsStateChanges(t *testing.T) {
+	now := time.Now()
+	var ops []*armresources.DeploymentOperation
+	var out bytes.Buffer
+	p := &progress{
+		w:     &out,
+		list:  func(context.Context) ([]*armresources.DeploymentOperation, error) { return ops, nil },
+		state: map[string]string{},
+	}
+
+	ops = []*armresources.DeploymentOperation{operation("1", "Running", "PT1S", now)}
+	p.report(context.Background())
+	p.report(context.Background())
+	failed := operation("2", "Failed", "PT2S", now.Add(time.Second))
+	failed.Properties.StatusMessage = &armresources.StatusMessage{Error: &armresources.ErrorResponse{Code: to.Ptr("Conflict"), Message: to.Ptr("name taken")}}
+	ops = []*armresources.DeploymentOperation{failed, operation("1", "Succeeded", "PT1M3.5S", now)}
+	p.report(context.Background())
+
+	want := "Running    Microsoft.Storage/storageAccounts/sa1\n" +
+		"Succeeded  Microsoft.Storage/storageAccounts/sa1 (1m4s)\n" +
+		"Failed     Microsoft.Storage/storageAccounts/sa2 (2s): Conflict: name taken\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestISODuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT12.5S":   12500 * time.Millisecond,
+		"PT1H2M":    time.Hour + 2*time.Minute,
+		"P1DT1S":    24*time.Hour + time.Second,
+		"":          0,
+		"yesterday": 0,
+	}
+	for in, want := range tests {
+		if got := isoDuration(in); got != want {
+			t.Errorf("isoDuration(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestScopePath(t *testing.T) {
+	tests := map[string]scope{
+		"/subscriptions/s/resourceGroups/rg":                 {kind: groupScope, subscriptionID: "s", resourceGroup: "rg"},
+		"/subscriptions/s":                                   {kind: subscriptionScope, subscriptionID: "s"},
+		"/providers/Microsoft.Management/managementGroups/m": {kind: managementGroupScope, managementGroup: "m"},
+		"": {kind: tenantScope},
+	}
+	for want, s := range tests {
+		if got := s.path(); got != want {
+			t.Errorf("path() = %q, want %q", got, want)
+		}
+	}
+}
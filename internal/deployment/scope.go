@@ -0,0 +1,115 @@
+package deployment
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// scopeKind is the level a deployment targets; each has its own command
+// group (az deployment group|sub|mg|tenant).
+type scopeKind int
+
+const (
+	groupScope scopeKind = iota
+	subscriptionScope
+	managementGroupScope
+	tenantScope
+)
+
+var scopeNames = map[scopeKind]string{
+	groupScope:           "group",
+	subscriptionScope:    "sub",
+	managementGroupScope: "mg",
+	tenantScope:          "tenant",
+}
+
+// scope identifies where deployments live. Everything except the tenant is
+// addressed through the SDK's *AtScope methods with path(); the tenant has
+// no scope path and uses the *AtTenantScope ones.
+type scope struct {
+	kind            scopeKind
+	subscriptionID  string
+	resourceGroup   string
+	managementGroup string
+}
+
+func (s scope) path() string {
+	switch s.kind {
+	case groupScope:
+		return "/subscriptions/" + s.subscriptionID + "/resourceGroups/" + s.resourceGroup
+	case subscriptionScope:
+		return "/subscriptions/" + s.subscriptionID
+	case managementGroupScope:
+		return "/providers/Microsoft.Management/managementGroups/" + s.managementGroup
+	}
+	return ""
+}
+
+// deploymentID returns the resource ID of the named deployment in s.
+func (s scope) deploymentID(name string) string {
+	return s.path() + "/providers/Microsoft.Resources/deployments/" + name
+}
+
+func (s scope) isTenant() bool {
+	return s.kind == tenantScope
+}
+
+// addScopeFlags registers the flags that select a scope of kind.
+func addScopeFlags(cmd *cobra.Command, kind scopeKind) {
+	switch kind {
+	case groupScope:
+		cmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+		cmd.MarkFlagRequired("resource-group")
+	case managementGroupScope:
+		cmd.Flags().StringP("management-group-id", "m", "", "Management group ID")
+		cmd.MarkFlagRequired("management-group-id")
+	}
+}
+
+// resolveScope builds the scope selected by cmd's flags. Management group
+// and tenant deployments don't need a subscription, so one is only looked up
+// for the others.
+func resolveScope(cmd *cobra.Command, kind scopeKind) (scope, error) {
+	s := scope{kind: kind}
+	switch kind {
+	case groupScope, subscriptionScope:
+		sub, _ := cmd.Flags().GetString("subscription")
+		subscriptionID, err := config.GetSubscription(sub)
+		if err != nil {
+			return s, fmt.Errorf("failed to get subscription: %w", err)
+		}
+		s.subscriptionID = subscriptionID
+		s.resourceGroup, _ = cmd.Flags().GetString("resource-group")
+	case managementGroupScope:
+		s.managementGroup, _ = cmd.Flags().GetString("management-group-id")
+	}
+	return s, nil
+}
+
+func newDeploymentsClient(s scope) (*armresources.DeploymentsClient, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+	client, err := armresources.NewDeploymentsClient(s.subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployments client: %w", err)
+	}
+	return client, nil
+}
+
+func newOperationsClient(s scope) (*armresources.DeploymentOperationsClient, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+	client, err := armresources.NewDeploymentOperationsClient(s.subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployment operations client: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,36 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPrintStarted(t *testing.T) {
+	tests := []struct {
+		scope scope
+		want  string
+	}{
+		{scope{kind: groupScope, subscriptionID: "s1", resourceGroup: "rg"}, "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Resources/deployments/app"},
+		{scope{kind: tenantScope}, "/providers/Microsoft.Resources/deployments/app"},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("output", "json", "")
+		cmd.Flags().String("query", "", "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := printStarted(cmd, &request{scope: tt.scope, name: "app"}); err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]string
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got["id"] != tt.want || got["name"] != "app" || got["status"] != "Deployment 'app' started." {
+			t.Errorf("printed %v, want id %s", got, tt.want)
+		}
+	}
+}
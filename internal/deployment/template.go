@@ -0,0 +1,308 @@
+package deployment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/internal/bicep"
+	"github.com/spf13/cobra"
+)
+
+// source is a template with its parameters, ready to be sent in a
+// deployment, validation or what-if request.
+type source struct {
+	template     map[string]interface{}
+	templateLink *armresources.TemplateLink
+	parameters   map[string]interface{}
+	// defaultName names the deployment when -n isn't given, after the
+	// template file as the Python CLI does.
+	defaultName string
+}
+
+// properties returns the deployment properties for s in the given mode.
+func (s *source) properties(mode armresources.DeploymentMode) *armresources.DeploymentProperties {
+	props := &armresources.DeploymentProperties{
+		Mode:         to.Ptr(mode),
+		TemplateLink: s.templateLink,
+		Parameters:   s.parameters,
+	}
+	if s.template != nil {
+		props.Template = s.template
+	}
+	return props
+}
+
+// loadSource reads --template-file or --template-uri and --parameters.
+// Bicep files are compiled first, so the rest only ever sees ARM JSON.
+func loadSource(ctx context.Context, cmd *cobra.Command) (*source, error) {
+	file, _ := cmd.Flags().GetString("template-file")
+	uri, _ := cmd.Flags().GetString("template-uri")
+	params, _ := cmd.Flags().GetStringArray("parameters")
+	// Like az, take `-p a=1 b=2` as well as `-p a=1 -p b=2`: pflag leaves b=2
+	// as a positional argument.
+	if cmd.Flags().Changed("parameters") {
+		params = append(params, cmd.Flags().Args()...)
+	}
+
+	// A .bicepparam file is compiled together with the template its `using`
+	// names, and stands in the parameter list as the parameters it yields.
+	paramIndex := -1
+	for i, p := range params {
+		if strings.EqualFold(filepath.Ext(p), ".bicepparam") {
+			if paramIndex >= 0 {
+				return nil, fmt.Errorf("only one .bicepparam file can be given")
+			}
+			paramIndex = i
+		}
+	}
+	switch {
+	case file != "" && uri != "":
+		return nil, fmt.Errorf("--template-file and --template-uri are mutually exclusive")
+	case paramIndex >= 0 && (uri != "" || file != "" && !isBicep(file)):
+		return nil, fmt.Errorf("a .bicepparam file can only be used with a .bicep template file")
+	}
+	var compiled *bicep.Params
+	var paramFile string
+	if paramIndex >= 0 {
+		paramFile = strings.TrimPrefix(params[paramIndex], "@")
+		var err error
+		if compiled, err = bicep.BuildParams(ctx, cmd.ErrOrStderr(), paramFile); err != nil {
+			return nil, fmt.Errorf("failed to build %s: %w", paramFile, err)
+		}
+		params[paramIndex] = string(compiled.Parameters)
+	}
+
+	s := &source{}
+	switch {
+	case file != "":
+		template, err := readTemplateFile(ctx, cmd, file)
+		if err != nil {
+			return nil, err
+		}
+		s.template = template
+		s.defaultName = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	case uri != "":
+		s.templateLink = &armresources.TemplateLink{URI: to.Ptr(uri)}
+		base := uri
+		if i := strings.IndexAny(base, "?#"); i >= 0 {
+			base = base[:i]
+		}
+		s.defaultName = strings.TrimSuffix(base[strings.LastIndex(base, "/")+1:], ".json")
+	case compiled != nil:
+		if err := unmarshalJSONC(compiled.Template, &s.template); err != nil {
+			return nil, fmt.Errorf("failed to parse the template built from %s: %w", paramFile, err)
+		}
+		s.defaultName = strings.TrimSuffix(filepath.Base(paramFile), filepath.Ext(paramFile))
+	default:
+		return nil, fmt.Errorf("please specify --template-file or --template-uri")
+	}
+
+	parameters, err := parseParameters(params, templateParameterTypes(s.template))
+	if err != nil {
+		return nil, err
+	}
+	if s.template != nil {
+		if missing := missingParameters(s.template, parameters); len(missing) > 0 {
+			return nil, fmt.Errorf("missing value for template parameter(s): %s", strings.Join(missing, ", "))
+		}
+	}
+	s.parameters = parameters
+	return s, nil
+}
+
+func isBicep(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".bicep")
+}
+
+func readTemplateFile(ctx context.Context, cmd *cobra.Command, path string) (map[string]interface{}, error) {
+	var data []byte
+	var err error
+	if isBicep(path) {
+		data, err = bicep.Build(ctx, cmd.ErrOrStderr(), path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s: %w", path, err)
+		}
+	} else if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+	var template map[string]interface{}
+	if err := unmarshalJSONC(data, &template); err != nil {
+		return nil, fmt.Errorf("failed to parse template file %s: %w", path, err)
+	}
+	return template, nil
+}
+
+// unmarshalJSONC decodes JSON that may contain // and /* */ comments, which
+// ARM accepts in templates and parameter files.
+func unmarshalJSONC(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(stripComments(data)))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func stripComments(data []byte) []byte {
+	var out bytes.Buffer
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+		} else if c == '/' && i+1 < len(data) && data[i+1] == '/' {
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			out.WriteByte('\n')
+			continue
+		} else if c == '/' && i+1 < len(data) && data[i+1] == '*' {
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				break
+			}
+			i += end + 3
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return out.Bytes()
+}
+
+// templateParameterTypes maps each declared parameter to its lower-cased
+// type, so key=value overrides of string parameters stay strings.
+func templateParameterTypes(template map[string]interface{}) map[string]string {
+	types := map[string]string{}
+	declared, _ := template["parameters"].(map[string]interface{})
+	for name, def := range declared {
+		if d, ok := def.(map[string]interface{}); ok {
+			t, _ := d["type"].(string)
+			types[strings.ToLower(name)] = strings.ToLower(t)
+		}
+	}
+	return types
+}
+
+// missingParameters lists declared parameters that have neither a default
+// nor a supplied value, in sorted order.
+func missingParameters(template map[string]interface{}, supplied map[string]interface{}) []string {
+	have := map[string]bool{}
+	for name := range supplied {
+		have[strings.ToLower(name)] = true
+	}
+	var missing []string
+	declared, _ := template["parameters"].(map[string]interface{})
+	for name, def := range declared {
+		d, _ := def.(map[string]interface{})
+		if _, ok := d["defaultValue"]; ok || have[strings.ToLower(name)] {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// parseParameters merges --parameters values, later ones winning. Each is a
+// parameters file (path or @path), an inline JSON object in the same shape,
+// or a key=value override; key=@path reads the value from a file.
+func parseParameters(args []string, types map[string]string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	for _, arg := range args {
+		obj, err := parameterObject(arg)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			for k, v := range obj {
+				out[k] = v
+			}
+			continue
+		}
+
+		eq := strings.Index(arg, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("--parameters %q: expected a parameters file, a JSON object or key=value", arg)
+		}
+		key, raw := arg[:eq], arg[eq+1:]
+		if strings.HasPrefix(raw, "@") {
+			data, err := os.ReadFile(raw[1:])
+			if err != nil {
+				return nil, fmt.Errorf("failed to read value of parameter %s: %w", key, err)
+			}
+			raw = string(data)
+		}
+		out[key] = map[string]interface{}{"value": parameterValue(raw, types[strings.ToLower(key)])}
+	}
+	return out, nil
+}
+
+// parameterObject returns the parameters in a file or inline JSON argument,
+// or nil if arg is a key=value override.
+func parameterObject(arg string) (map[string]interface{}, error) {
+	var data []byte
+	switch {
+	case strings.HasPrefix(arg, "@"):
+		d, err := os.ReadFile(arg[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parameters file: %w", err)
+		}
+		data = d
+	case strings.HasPrefix(strings.TrimSpace(arg), "{"):
+		data = []byte(arg)
+	default:
+		if _, err := os.Stat(arg); err != nil {
+			return nil, nil
+		}
+		d, err := os.ReadFile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parameters file: %w", err)
+		}
+		data = d
+	}
+
+	var obj map[string]interface{}
+	if err := unmarshalJSONC(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters %q: %w", arg, err)
+	}
+	// A full parameters file wraps the values in "parameters" next to
+	// $schema and contentVersion.
+	if inner, ok := obj["parameters"].(map[string]interface{}); ok {
+		if _, schema := obj["$schema"]; schema || obj["contentVersion"] != nil {
+			obj = inner
+		}
+	}
+	return obj, nil
+}
+
+// parameterValue converts a key=value override. String parameters keep the
+// text as is; anything else is read as JSON when it parses.
+func parameterValue(raw, paramType string) interface{} {
+	if paramType == "string" || paramType == "securestring" {
+		return raw
+	}
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		return v
+	}
+	return raw
+}
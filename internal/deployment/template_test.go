@@ -0,0 +1,171 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+const testTemplate = `{
+  // ARM allows comments in templates.
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "name":   {"type": "string"},
+    "count":  {"type": "int", "defaultValue": 1},
+    "tags":   {"type": "object"},
+    "secret": {"type": "securestring"} /* no default */
+  },
+  "resources": [{"name": "[concat('a//b', parameters('name'))]"}]
+}`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func templateCommand(args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "create"}
+	addTemplateFlags(cmd, subscriptionScope)
+	cmd.ParseFlags(args)
+	return cmd
+}
+
+func TestLoadSource(t *testing.T) {
+	dir := t.TempDir()
+	template := writeFile(t, dir, "main.json", testTemplate)
+	paramsFile := writeFile(t, dir, "main.parameters.json", `{
+		"$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+		"contentVersion": "1.0.0.0",
+		"parameters": {"name": {"value": "fromfile"}, "tags": {"value": {"env": "dev"}}}
+	}`)
+	secret := writeFile(t, dir, "secret.txt", "123")
+
+	cmd := templateCommand("-f", template, "-p", paramsFile, "-p", "name=override", "count=5", "secret=@"+secret)
+	src, err := loadSource(context.Background(), cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.defaultName != "main" {
+		t.Errorf("default name = %q", src.defaultName)
+	}
+	// The comment-looking text inside a string must survive stripping.
+	resources := src.template["resources"].([]interface{})
+	if got := resources[0].(map[string]interface{})["name"]; got != "[concat('a//b', parameters('name'))]" {
+		t.Errorf("resource name = %v", got)
+	}
+
+	got, _ := json.Marshal(src.parameters)
+	want := `{"count":{"value":5},"name":{"value":"override"},"secret":{"value":"123"},"tags":{"value":{"env":"dev"}}}`
+	if string(got) != want {
+		t.Errorf("parameters = %s\nwant %s", got, want)
+	}
+}
+
+func TestLoadSourceErrors(t *testing.T) {
+	dir := t.TempDir()
+	template := writeFile(t, dir, "main.json", testTemplate)
+	tests := map[string][]string{
+		"missing value for template parameter(s): secret, tags": {"-f", template, "-p", "name=x"},
+		"please specify --template-file or --template-uri":      {},
+		"mutually exclusive":                      {"-f", template, "-u", "https://example.com/t.json"},
+		"expected a parameters file":              {"-u", "https://example.com/t.json", "-p", "nonsense"},
+		"can only be used with a .bicep template": {"-f", template, "-p", "main.bicepparam"},
+		"only one .bicepparam file can be given":  {"-p", "a.bicepparam", "-p", "@b.bicepparam"},
+	}
+	for want, args := range tests {
+		_, err := loadSource(context.Background(), templateCommand(args...))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%v: err = %v, want %q", args, err, want)
+		}
+	}
+}
+
+func TestTemplateURIDefaultName(t *testing.T) {
+	src, err := loadSource(context.Background(), templateCommand("-u", "https://example.com/templates/network.json?sv=1", "-p", `{"a": {"value": true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.defaultName != "network" || src.templateLink == nil || src.template != nil {
+		t.Errorf("source = %+v", src)
+	}
+	if !reflect.DeepEqual(src.parameters, map[string]interface{}{"a": map[string]interface{}{"value": true}}) {
+		t.Errorf("parameters = %v", src.parameters)
+	}
+}
+
+func TestParameterValue(t *testing.T) {
+	tests := []struct {
+		raw, typ string
+		want     interface{}
+	}{
+		{"123", "string", "123"},
+		{"true", "bool", true},
+		{`["a"]`, "array", []interface{}{"a"}},
+		{"hello", "", "hello"},
+		{"1 2", "int", "1 2"},
+	}
+	for _, tt := range tests {
+		got := parameterValue(tt.raw, tt.typ)
+		if b, _ := json.Marshal(got); string(b) != mustMarshal(tt.want) {
+			t.Errorf("parameterValue(%q, %q) = %v", tt.raw, tt.typ, got)
+		}
+	}
+}
+
+func mustMarshal(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// fakeBicep installs a stand-in bicep into a temporary ~/.azure/bin that
+// prints the given template for build and the given parameters file (with
+// the template) for build-params.
+func fakeBicep(t *testing.T, template, parameters string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AZ_SESSION", "")
+	bin := filepath.Join(home, ".azure", "bin")
+	os.MkdirAll(bin, 0755)
+	buildParams, _ := json.Marshal(map[string]string{"parametersJson": parameters, "templateJson": template})
+	writeFile(t, bin, "template.json", template)
+	writeFile(t, bin, "params.json", string(buildParams))
+	script := "#!/bin/sh\ncase \"$1\" in\nbuild) cat " + filepath.Join(bin, "template.json") + " ;;\nbuild-params) cat " + filepath.Join(bin, "params.json") + " ;;\n*) exit 1 ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(bin, "bicep"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSourceBicep(t *testing.T) {
+	fakeBicep(t, testTemplate, `{"$schema": "x", "contentVersion": "1.0.0.0", "parameters": {"name": {"value": "p"}, "tags": {"value": {}}, "secret": {"value": "s"}}}`)
+
+	src, err := loadSource(context.Background(), templateCommand("-f", "infra/main.bicep", "-p", "name=x", "tags={}", "secret=s"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.defaultName != "main" || src.template["contentVersion"] != "1.0.0.0" {
+		t.Errorf("source = %+v", src)
+	}
+
+	// A .bicepparam file alone brings its template; later overrides still win.
+	src, err = loadSource(context.Background(), templateCommand("-p", "infra/prod.bicepparam", "-p", "name=override"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(src.parameters)
+	want := `{"name":{"value":"override"},"secret":{"value":"s"},"tags":{"value":{}}}`
+	if src.defaultName != "prod" || src.template == nil || string(got) != want {
+		t.Errorf("name %q, parameters %s", src.defaultName, got)
+	}
+}
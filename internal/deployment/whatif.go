@@ -0,0 +1,136 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// WhatIf previews the changes a deployment would make.
+func WhatIf(ctx context.Context, cmd *cobra.Command, kind scopeKind) error {
+	r, err := newRequest(ctx, cmd, kind)
+	if err != nil {
+		return err
+	}
+	return runWhatIf(ctx, cmd, r)
+}
+
+func runWhatIf(ctx context.Context, cmd *cobra.Command, r *request) error {
+	resultFormat, _ := cmd.Flags().GetString("result-format")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude-change-types")
+	noPrettyPrint, _ := cmd.Flags().GetBool("no-pretty-print")
+
+	format := armresources.WhatIfResultFormatFullResourcePayloads
+	if resultFormat != "" {
+		format = armresources.WhatIfResultFormat(resultFormat)
+		if format != armresources.WhatIfResultFormatFullResourcePayloads && format != armresources.WhatIfResultFormatResourceIDOnly {
+			return fmt.Errorf("invalid --result-format %q (expected FullResourcePayloads or ResourceIdOnly)", resultFormat)
+		}
+	}
+
+	client, err := newDeploymentsClient(r.scope)
+	if err != nil {
+		return err
+	}
+	src := r.source
+	props := &armresources.DeploymentWhatIfProperties{
+		Mode:           to.Ptr(r.mode),
+		TemplateLink:   src.templateLink,
+		Parameters:     src.parameters,
+		WhatIfSettings: &armresources.DeploymentWhatIfSettings{ResultFormat: to.Ptr(format)},
+	}
+	if src.template != nil {
+		props.Template = src.template
+	}
+	var location *string
+	if r.location != "" {
+		location = to.Ptr(r.location)
+	}
+
+	var result armresources.WhatIfOperationResult
+	switch r.scope.kind {
+	case groupScope:
+		poller, err := client.BeginWhatIf(ctx, r.scope.resourceGroup, r.name, armresources.DeploymentWhatIf{Properties: props}, nil)
+		if err == nil {
+			var resp armresources.DeploymentsClientWhatIfResponse
+			resp, err = poller.PollUntilDone(ctx, nil)
+			result = resp.WhatIfOperationResult
+		}
+		if err != nil {
+			return fmt.Errorf("what-if failed: %w", err)
+		}
+	case subscriptionScope:
+		poller, err := client.BeginWhatIfAtSubscriptionScope(ctx, r.name, armresources.DeploymentWhatIf{Location: location, Properties: props}, nil)
+		if err == nil {
+			var resp armresources.DeploymentsClientWhatIfAtSubscriptionScopeResponse
+			resp, err = poller.PollUntilDone(ctx, nil)
+			result = resp.WhatIfOperationResult
+		}
+		if err != nil {
+			return fmt.Errorf("what-if failed: %w", err)
+		}
+	case managementGroupScope:
+		poller, err := client.BeginWhatIfAtManagementGroupScope(ctx, r.scope.managementGroup, r.name, armresources.ScopedDeploymentWhatIf{Location: location, Properties: props}, nil)
+		if err == nil {
+			var resp armresources.DeploymentsClientWhatIfAtManagementGroupScopeResponse
+			resp, err = poller.PollUntilDone(ctx, nil)
+			result = resp.WhatIfOperationResult
+		}
+		if err != nil {
+			return fmt.Errorf("what-if failed: %w", err)
+		}
+	default:
+		poller, err := client.BeginWhatIfAtTenantScope(ctx, r.name, armresources.ScopedDeploymentWhatIf{Location: location, Properties: props}, nil)
+		if err == nil {
+			var resp armresources.DeploymentsClientWhatIfAtTenantScopeResponse
+			resp, err = poller.PollUntilDone(ctx, nil)
+			result = resp.WhatIfOperationResult
+		}
+		if err != nil {
+			return fmt.Errorf("what-if failed: %w", err)
+		}
+	}
+	if result.Error != nil {
+		return fmt.Errorf("what-if failed: %s: %s", deref(result.Error.Code), deref(result.Error.Message))
+	}
+	if result.Properties != nil {
+		result.Properties.Changes = excludeChanges(result.Properties.Changes, exclude)
+	}
+
+	if noPrettyPrint {
+		return output.PrintJSON(cmd, result)
+	}
+	var changes []*armresources.WhatIfChange
+	if result.Properties != nil {
+		changes = result.Properties.Changes
+	}
+	w := cmd.OutOrStdout()
+	_, err = fmt.Fprint(w, formatWhatIf(changes, output.ColorEnabled(w)))
+	return err
+}
+
+// excludeChanges drops resource changes whose type is in exclude.
+func excludeChanges(changes []*armresources.WhatIfChange, exclude []string) []*armresources.WhatIfChange {
+	if len(exclude) == 0 {
+		return changes
+	}
+	var out []*armresources.WhatIfChange
+	for _, c := range changes {
+		skip := false
+		for _, e := range exclude {
+			if strings.EqualFold(e, string(changeType(c))) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,467 @@
+package deployment
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// Colours used by the Python CLI's what-if formatter.
+const (
+	colorGreen  = "\x1b[38;5;77m"
+	colorOrange = "\x1b[38;5;208m"
+	colorPurple = "\x1b[38;5;141m"
+	colorBlue   = "\x1b[38;5;39m"
+	colorGray   = "\x1b[38;5;246m"
+	colorReset  = "\x1b[0m"
+)
+
+// changeStyle is how one kind of change is drawn. Resource and property
+// change types share symbols, so both map onto these.
+type changeStyle struct {
+	symbol string
+	label  string
+	color  string
+	// dim draws the whole line in the colour, not just the symbol, for
+	// changes that won't touch anything.
+	dim bool
+}
+
+var (
+	styleDelete      = changeStyle{"-", "Delete", colorOrange, false}
+	styleCreate      = changeStyle{"+", "Create", colorGreen, false}
+	styleDeploy      = changeStyle{"!", "Deploy", colorBlue, false}
+	styleModify      = changeStyle{"~", "Modify", colorPurple, false}
+	styleUnsupported = changeStyle{"x", "Unsupported", colorGray, true}
+	styleNoChange    = changeStyle{"=", "NoChange", colorGray, true}
+	styleIgnore      = changeStyle{"*", "Ignore", colorGray, true}
+	styleNoEffect    = changeStyle{"x", "NoEffect", colorGray, true}
+)
+
+// resourceOrder sorts resources within a scope and the summary counts.
+var resourceOrder = []armresources.ChangeType{
+	armresources.ChangeTypeDelete,
+	armresources.ChangeTypeCreate,
+	armresources.ChangeTypeDeploy,
+	armresources.ChangeTypeModify,
+	armresources.ChangeTypeUnsupported,
+	armresources.ChangeTypeNoChange,
+	armresources.ChangeTypeIgnore,
+}
+
+var resourceStyles = map[armresources.ChangeType]changeStyle{
+	armresources.ChangeTypeDelete:      styleDelete,
+	armresources.ChangeTypeCreate:      styleCreate,
+	armresources.ChangeTypeDeploy:      styleDeploy,
+	armresources.ChangeTypeModify:      styleModify,
+	armresources.ChangeTypeUnsupported: styleUnsupported,
+	armresources.ChangeTypeNoChange:    styleNoChange,
+	armresources.ChangeTypeIgnore:      styleIgnore,
+}
+
+var summaryLabels = map[armresources.ChangeType]string{
+	armresources.ChangeTypeDelete:      "to delete",
+	armresources.ChangeTypeCreate:      "to create",
+	armresources.ChangeTypeDeploy:      "to deploy",
+	armresources.ChangeTypeModify:      "to modify",
+	armresources.ChangeTypeUnsupported: "unsupported",
+	armresources.ChangeTypeNoChange:    "no change",
+	armresources.ChangeTypeIgnore:      "to ignore",
+}
+
+var propertyStyles = map[armresources.PropertyChangeType]changeStyle{
+	armresources.PropertyChangeTypeDelete:   styleDelete,
+	armresources.PropertyChangeTypeCreate:   styleCreate,
+	armresources.PropertyChangeTypeModify:   styleModify,
+	armresources.PropertyChangeTypeArray:    styleModify,
+	armresources.PropertyChangeTypeNoEffect: styleNoEffect,
+}
+
+// legendOrder lists the styles in the order the legend shows them.
+var legendOrder = []changeStyle{styleDelete, styleCreate, styleModify, styleDeploy, styleNoChange, styleIgnore, styleNoEffect, styleUnsupported}
+
+// whatIfPrinter renders a what-if result as the change tree the Python CLI
+// prints.
+type whatIfPrinter struct {
+	b     strings.Builder
+	color bool
+	used  map[string]bool
+}
+
+// formatWhatIf renders changes grouped by scope, with a legend of the symbols
+// used and a count of resource changes.
+func formatWhatIf(changes []*armresources.WhatIfChange, color bool) string {
+	p := &whatIfPrinter{color: color, used: map[string]bool{}}
+
+	byScope := map[string][]*armresources.WhatIfChange{}
+	var scopes []string
+	counts := map[armresources.ChangeType]int{}
+	for _, c := range changes {
+		sc, _ := splitResourceID(deref(c.ResourceID))
+		if _, ok := byScope[strings.ToLower(sc)]; !ok {
+			scopes = append(scopes, sc)
+		}
+		byScope[strings.ToLower(sc)] = append(byScope[strings.ToLower(sc)], c)
+		if c.ChangeType != nil {
+			counts[*c.ChangeType]++
+		}
+	}
+	sort.Slice(scopes, func(i, j int) bool { return strings.ToLower(scopes[i]) < strings.ToLower(scopes[j]) })
+
+	var body whatIfPrinter
+	body.color, body.used = color, p.used
+	for _, sc := range scopes {
+		body.printScope(sc, byScope[strings.ToLower(sc)])
+	}
+
+	p.b.WriteString("Note: The result may contain false positive predictions (noise).\n")
+	p.b.WriteString("You can help us improve the accuracy of the result by opening an issue here: https://aka.ms/WhatIfIssues\n\n")
+	if len(changes) == 0 {
+		p.b.WriteString("No change found.\n")
+		return p.b.String()
+	}
+	p.b.WriteString("Resource and property changes are indicated with these symbols:\n")
+	for _, st := range legendOrder {
+		if p.used[st.label] {
+			fmt.Fprintf(&p.b, "  %s %s\n", p.paint(st, st.symbol), st.label)
+		}
+	}
+	p.b.WriteString("\nThe deployment will update the following scope")
+	if len(scopes) > 1 {
+		p.b.WriteString("s")
+	}
+	p.b.WriteString(":\n")
+	p.b.WriteString(body.b.String())
+
+	var parts []string
+	for _, ct := range resourceOrder {
+		if n := counts[ct]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, summaryLabels[ct]))
+		}
+	}
+	fmt.Fprintf(&p.b, "\nResource changes: %s.\n", strings.Join(parts, ", "))
+	return p.b.String()
+}
+
+func (p *whatIfPrinter) printScope(sc string, changes []*armresources.WhatIfChange) {
+	rank := map[armresources.ChangeType]int{}
+	for i, ct := range resourceOrder {
+		rank[ct] = i
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		ri, rj := rank[changeType(changes[i])], rank[changeType(changes[j])]
+		if ri != rj {
+			return ri < rj
+		}
+		_, a := splitResourceID(deref(changes[i].ResourceID))
+		_, b := splitResourceID(deref(changes[j].ResourceID))
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+
+	fmt.Fprintf(&p.b, "\nScope: %s\n", sc)
+	for _, c := range changes {
+		p.printResource(c)
+	}
+}
+
+func changeType(c *armresources.WhatIfChange) armresources.ChangeType {
+	if c.ChangeType == nil {
+		return armresources.ChangeTypeUnsupported
+	}
+	return *c.ChangeType
+}
+
+func (p *whatIfPrinter) printResource(c *armresources.WhatIfChange) {
+	st, ok := resourceStyles[changeType(c)]
+	if !ok {
+		st = styleUnsupported
+	}
+	p.used[st.label] = true
+	_, rel := splitResourceID(deref(c.ResourceID))
+	line := rel
+	if v := apiVersion(c); v != "" {
+		line += " [" + v + "]"
+	}
+	p.b.WriteString("\n")
+	p.line(st, 2, st.symbol+" "+line)
+
+	switch changeType(c) {
+	case armresources.ChangeTypeCreate:
+		p.printObject(c.After, 6)
+	case armresources.ChangeTypeDelete:
+		p.printObject(c.Before, 6)
+	case armresources.ChangeTypeUnsupported:
+		if c.UnsupportedReason != nil {
+			p.b.WriteString("\n")
+			p.line(st, 6, "Unsupported: "+*c.UnsupportedReason)
+		}
+	default:
+		if len(c.Delta) > 0 {
+			p.b.WriteString("\n")
+			p.printDelta(c.Delta, 4)
+		}
+	}
+}
+
+// printObject prints a resource body as aligned, flattened paths.
+func (p *whatIfPrinter) printObject(v interface{}, indent int) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		return
+	}
+	p.b.WriteString("\n")
+	p.printProperties(obj, indent)
+}
+
+// printProperties prints obj's leaves as "path: value", padding the paths
+// of scalar values to line their values up.
+func (p *whatIfPrinter) printProperties(obj map[string]interface{}, indent int) {
+	leaves := flatten("", obj)
+	width := 0
+	for _, l := range leaves {
+		if !isContainer(l.value) && len(l.path) > width {
+			width = len(l.path)
+		}
+	}
+	for _, l := range leaves {
+		p.printValue(l.path, l.value, indent, width)
+	}
+}
+
+type leaf struct {
+	path  string
+	value interface{}
+}
+
+// flatten turns nested objects into dotted paths. Arrays stay whole; their
+// elements are printed under them.
+func flatten(prefix string, obj map[string]interface{}) []leaf {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return strings.ToLower(keys[i]) < strings.ToLower(keys[j]) })
+	var out []leaf
+	for _, k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if child, ok := obj[k].(map[string]interface{}); ok && len(child) > 0 {
+			out = append(out, flatten(path, child)...)
+			continue
+		}
+		out = append(out, leaf{path, obj[k]})
+	}
+	return out
+}
+
+func isContainer(v interface{}) bool {
+	switch t := v.(type) {
+	case []interface{}:
+		return len(t) > 0
+	case map[string]interface{}:
+		return len(t) > 0
+	}
+	return false
+}
+
+// printValue prints one property with no change marker (inside a created or
+// deleted resource, or an added or removed array element).
+func (p *whatIfPrinter) printValue(path string, v interface{}, indent, width int) {
+	pad := strings.Repeat(" ", indent)
+	switch t := v.(type) {
+	case []interface{}:
+		if len(t) == 0 {
+			fmt.Fprintf(&p.b, "%s%s: []\n", pad, path)
+			return
+		}
+		fmt.Fprintf(&p.b, "%s%s: [\n", pad, path)
+		p.printArray(t, indent+2)
+		fmt.Fprintf(&p.b, "%s]\n", pad)
+	case map[string]interface{}:
+		if len(t) == 0 {
+			fmt.Fprintf(&p.b, "%s%s: {}\n", pad, path)
+			return
+		}
+		fmt.Fprintf(&p.b, "%s%s:\n\n", pad, path)
+		p.printProperties(t, indent+2)
+		p.b.WriteString("\n")
+	default:
+		fmt.Fprintf(&p.b, "%s%s:%s %s\n", pad, path, strings.Repeat(" ", width-len(path)), scalar(v))
+	}
+}
+
+func (p *whatIfPrinter) printArray(items []interface{}, indent int) {
+	for i, item := range items {
+		key := strconv.Itoa(i)
+		if obj, ok := item.(map[string]interface{}); ok && len(obj) > 0 {
+			fmt.Fprintf(&p.b, "%s%s:\n\n", strings.Repeat(" ", indent), key)
+			p.printProperties(obj, indent+2)
+			p.b.WriteString("\n")
+			continue
+		}
+		p.printValue(key, item, indent, len(key))
+	}
+}
+
+// printDelta prints property changes of a modified resource.
+func (p *whatIfPrinter) printDelta(changes []*armresources.WhatIfPropertyChange, indent int) {
+	width := 0
+	for _, c := range changes {
+		if c.PropertyChangeType != nil && *c.PropertyChangeType != armresources.PropertyChangeTypeArray &&
+			!isContainer(c.Before) && !isContainer(c.After) && len(deref(c.Path)) > width {
+			width = len(deref(c.Path))
+		}
+	}
+	for _, c := range changes {
+		p.printPropertyChange(c, indent, width)
+	}
+}
+
+func (p *whatIfPrinter) printPropertyChange(c *armresources.WhatIfPropertyChange, indent, width int) {
+	var ct armresources.PropertyChangeType
+	if c.PropertyChangeType != nil {
+		ct = *c.PropertyChangeType
+	}
+	st, ok := propertyStyles[ct]
+	if !ok {
+		st = styleNoEffect
+	}
+	p.used[st.label] = true
+	path := deref(c.Path)
+	pad := strings.Repeat(" ", max(width-len(path), 0))
+
+	switch ct {
+	case armresources.PropertyChangeTypeArray:
+		p.line(st, indent, st.symbol+" "+path+": [")
+		p.printDelta(c.Children, indent+2)
+		p.line(st, indent, "  ]")
+	case armresources.PropertyChangeTypeCreate:
+		p.printMarked(st, path, pad, c.After, indent)
+	case armresources.PropertyChangeTypeDelete:
+		p.printMarked(st, path, pad, c.Before, indent)
+	case armresources.PropertyChangeTypeNoEffect:
+		p.printMarked(st, path, pad, c.After, indent)
+	default:
+		if len(c.Children) > 0 {
+			p.line(st, indent, st.symbol+" "+path+":")
+			p.b.WriteString("\n")
+			p.printDelta(c.Children, indent+2)
+			p.b.WriteString("\n")
+			return
+		}
+		if isContainer(c.Before) || isContainer(c.After) {
+			p.line(st, indent, st.symbol+" "+path+":")
+			p.b.WriteString("\n")
+			p.printMarked(styleDelete, "", "", c.Before, indent+2)
+			p.printMarked(styleCreate, "", "", c.After, indent+2)
+			p.b.WriteString("\n")
+			return
+		}
+		p.line(st, indent, fmt.Sprintf("%s %s:%s %s => %s", st.symbol, path, pad, scalar(c.Before), scalar(c.After)))
+	}
+}
+
+// printMarked prints "symbol path: value", expanding containers below it.
+// An empty path prints the value alone, for the before/after halves of a
+// modified container.
+func (p *whatIfPrinter) printMarked(st changeStyle, path, pad string, v interface{}, indent int) {
+	p.used[st.label] = true
+	head := st.symbol + " "
+	if path != "" {
+		head += path + ":" + pad + " "
+	}
+	switch t := v.(type) {
+	case []interface{}:
+		if len(t) == 0 {
+			p.line(st, indent, head+"[]")
+			return
+		}
+		p.line(st, indent, head+"[")
+		p.printArray(t, indent+4)
+		p.line(st, indent, "  ]")
+	case map[string]interface{}:
+		if len(t) == 0 {
+			p.line(st, indent, head+"{}")
+			return
+		}
+		p.line(st, indent, strings.TrimRight(head, " "))
+		p.b.WriteString("\n")
+		p.printProperties(t, indent+4)
+		p.b.WriteString("\n")
+	default:
+		p.line(st, indent, head+scalar(v))
+	}
+}
+
+// line writes text at indent, colouring the leading symbol, or the whole
+// line for changes that have no effect.
+func (p *whatIfPrinter) line(st changeStyle, indent int, text string) {
+	p.b.WriteString(strings.Repeat(" ", indent))
+	switch {
+	case !p.color:
+		p.b.WriteString(text)
+	case st.dim:
+		p.b.WriteString(st.color + text + colorReset)
+	case strings.HasPrefix(text, st.symbol):
+		p.b.WriteString(st.color + st.symbol + colorReset + text[len(st.symbol):])
+	default:
+		p.b.WriteString(text)
+	}
+	p.b.WriteString("\n")
+}
+
+func (p *whatIfPrinter) paint(st changeStyle, s string) string {
+	if !p.color {
+		return s
+	}
+	return st.color + s + colorReset
+}
+
+// scalar formats a leaf value as JSON, so strings are quoted.
+func scalar(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// splitResourceID splits a resource ID into the scope it's deployed to and
+// the ID relative to it: ".../resourceGroups/rg" and
+// "Microsoft.Network/virtualNetworks/vnet".
+func splitResourceID(id string) (string, string) {
+	if i := strings.LastIndex(strings.ToLower(id), "/providers/"); i >= 0 {
+		return id[:i], id[i+len("/providers/"):]
+	}
+	if i := strings.Index(strings.ToLower(id), "/resourcegroups/"); i >= 0 {
+		return id[:i], id[i+1:]
+	}
+	return id, ""
+}
+
+func apiVersion(c *armresources.WhatIfChange) string {
+	for _, v := range []interface{}{c.After, c.Before} {
+		if obj, ok := v.(map[string]interface{}); ok {
+			if s, ok := obj["apiVersion"].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
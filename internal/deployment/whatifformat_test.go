@@ -0,0 +1,147 @@
+package deployment
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+const whatIfResult = `{
+  "status": "Succeeded",
+  "properties": {"changes": [
+    {
+      "resourceId": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa1",
+      "changeType": "Create",
+      "after": {
+        "apiVersion": "2023-01-01",
+        "id": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa1",
+        "kind": "StorageV2",
+        "sku": {"name": "Standard_LRS"},
+        "tags": {"env": "dev"},
+        "properties": {"ipRules": ["1.2.3.4"]}
+      }
+    },
+    {
+      "resourceId": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet1",
+      "changeType": "Modify",
+      "before": {"apiVersion": "2023-04-01"},
+      "after": {"apiVersion": "2023-04-01"},
+      "delta": [
+        {"path": "tags.env", "propertyChangeType": "Modify", "before": "dev", "after": "prod"},
+        {"path": "tags.owner", "propertyChangeType": "Delete", "before": "me"},
+        {"path": "properties.addressSpace.addressPrefixes", "propertyChangeType": "Array", "children": [
+          {"path": "0", "propertyChangeType": "Delete", "before": "10.0.0.0/16"},
+          {"path": "0", "propertyChangeType": "Create", "after": "10.1.0.0/16"}
+        ]}
+      ]
+    },
+    {
+      "resourceId": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Web/sites/old",
+      "changeType": "Delete",
+      "before": {"apiVersion": "2022-03-01", "location": "westus"}
+    },
+    {
+      "resourceId": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv",
+      "changeType": "Ignore"
+    },
+    {
+      "resourceId": "/subscriptions/s/resourceGroups/other",
+      "changeType": "NoChange"
+    }
+  ]}
+}`
+
+const wantWhatIf = `Note: The result may contain false positive predictions (noise).
+You can help us improve the accuracy of the result by opening an issue here: https://aka.ms/WhatIfIssues
+
+Resource and property changes are indicated with these symbols:
+  - Delete
+  + Create
+  ~ Modify
+  = NoChange
+  * Ignore
+
+The deployment will update the following scopes:
+
+Scope: /subscriptions/s
+
+  = resourceGroups/other
+
+Scope: /subscriptions/s/resourceGroups/rg
+
+  - Microsoft.Web/sites/old [2022-03-01]
+
+      apiVersion: "2022-03-01"
+      location:   "westus"
+
+  + Microsoft.Storage/storageAccounts/sa1 [2023-01-01]
+
+      apiVersion: "2023-01-01"
+      id:         "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa1"
+      kind:       "StorageV2"
+      properties.ipRules: [
+        0: "1.2.3.4"
+      ]
+      sku.name:   "Standard_LRS"
+      tags.env:   "dev"
+
+  ~ Microsoft.Network/virtualNetworks/vnet1 [2023-04-01]
+
+    ~ tags.env:   "dev" => "prod"
+    - tags.owner: "me"
+    ~ properties.addressSpace.addressPrefixes: [
+      - 0: "10.0.0.0/16"
+      + 0: "10.1.0.0/16"
+      ]
+
+  * Microsoft.KeyVault/vaults/kv
+
+Resource changes: 1 to delete, 1 to create, 1 to modify, 1 no change, 1 to ignore.
+`
+
+func TestFormatWhatIf(t *testing.T) {
+	var result armresources.WhatIfOperationResult
+	if err := json.Unmarshal([]byte(whatIfResult), &result); err != nil {
+		t.Fatal(err)
+	}
+	got := formatWhatIf(result.Properties.Changes, false)
+	if got != wantWhatIf {
+		t.Errorf("got:\n%s\nwant:\n%s", got, wantWhatIf)
+	}
+
+	colored := formatWhatIf(result.Properties.Changes, true)
+	if !strings.Contains(colored, colorGreen+"+"+colorReset+" Microsoft.Storage") || !strings.Contains(colored, colorGray+"* Microsoft.KeyVault/vaults/kv"+colorReset) {
+		t.Errorf("colours missing:\n%q", colored)
+	}
+}
+
+func TestFormatWhatIfNoChanges(t *testing.T) {
+	if got := formatWhatIf(nil, false); !strings.HasSuffix(got, "No change found.\n") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestExcludeChanges(t *testing.T) {
+	var result armresources.WhatIfOperationResult
+	json.Unmarshal([]byte(whatIfResult), &result)
+	got := excludeChanges(result.Properties.Changes, []string{"nochange", "Ignore"})
+	if len(got) != 3 {
+		t.Errorf("kept %d changes", len(got))
+	}
+}
+
+func TestSplitResourceID(t *testing.T) {
+	tests := []struct{ id, scope, rel string }{
+		{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/v/subnets/a", "/subscriptions/s/resourceGroups/rg", "Microsoft.Network/virtualNetworks/v/subnets/a"},
+		{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa/providers/Microsoft.Authorization/roleAssignments/r", "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa", "Microsoft.Authorization/roleAssignments/r"},
+		{"/subscriptions/s/resourceGroups/rg", "/subscriptions/s", "resourceGroups/rg"},
+	}
+	for _, tt := range tests {
+		scope, rel := splitResourceID(tt.id)
+		if scope != tt.scope || rel != tt.rel {
+			t.Errorf("splitResourceID(%q) = %q, %q", tt.id, scope, rel)
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 
 	"github.com/cdobbyn/azure-go-cli/internal/disk/encryptionset"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Compute/disks"
+
 func NewDiskCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "disk",
@@ -28,15 +31,12 @@ func NewDiskCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a managed disk",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, name, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Disk name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -65,17 +65,16 @@ func NewDiskCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a managed disk",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Disk name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd)
 
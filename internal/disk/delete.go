@@ -6,33 +6,28 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, name, resourceGroup string, noWait bool) error {
+func Delete(ctx context.Context, t bulk.Target, noWait bool) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armcompute.NewDisksClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewDisksClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create disk client: %w", err)
 	}
 
-	fmt.Printf("Deleting disk '%s'...\n", name)
-	poller, err := client.BeginDelete(ctx, resourceGroup, name, nil)
+	fmt.Printf("Deleting disk '%s'...\n", t.Name)
+	poller, err := client.BeginDelete(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete disk: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Delete operation started for disk '%s'\n", name)
+		fmt.Printf("Delete operation started for disk '%s'\n", t.Name)
 		return nil
 	}
 
@@ -41,6 +36,6 @@ func Delete(ctx context.Context, name, resourceGroup string, noWait bool) error
 		return fmt.Errorf("failed to complete disk deletion: %w", err)
 	}
 
-	fmt.Printf("Deleted disk '%s'\n", name)
+	fmt.Printf("Deleted disk '%s'\n", t.Name)
 	return nil
 }
@@ -43,7 +43,7 @@ func deleteDiskEncryptionSet(ctx context.Context, cmd *cobra.Command, name, reso
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create disk encryption sets client: %w", err)
 	}
@@ -22,7 +22,7 @@ func newClient() (*armcompute.DiskEncryptionSetsClient, error) {
 		return nil, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create disk encryption sets client: %w", err)
 	}
@@ -44,7 +44,7 @@ func showDiskEncryptionSet(ctx context.Context, cmd *cobra.Command, resourceGrou
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create disk encryption sets client: %w", err)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
 	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/cdobbyn/azure-go-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +34,7 @@ func newUpdateCmd() *cobra.Command {
 	cmd.Flags().Bool("enable-auto-key-rotation", false, "Enable auto-rotation to the latest key version")
 	cmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
 	cmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
+	genericupdate.AddFlags(cmd)
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("resource-group")
 
@@ -50,12 +52,32 @@ func updateDiskEncryptionSet(ctx context.Context, cmd *cobra.Command, name, reso
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewDiskEncryptionSetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create disk encryption sets client: %w", err)
 	}
 
-	props := &armcompute.DiskEncryptionSetUpdateProperties{}
+	update := armcompute.DiskEncryptionSetUpdate{}
+	ops, err := genericupdate.OpsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if len(ops) > 0 {
+		// Generic updates edit the set as it is now; without them only the
+		// properties given are sent.
+		current, err := client.Get(ctx, resourceGroup, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get disk encryption set: %w", err)
+		}
+		if err := genericupdate.Seed(&update, current.DiskEncryptionSet); err != nil {
+			return err
+		}
+	}
+	if update.Properties == nil {
+		update.Properties = &armcompute.DiskEncryptionSetUpdateProperties{}
+	}
+
+	props := update.Properties
 	flags := cmd.Flags()
 
 	if flags.Changed("key-url") {
@@ -76,8 +98,6 @@ func updateDiskEncryptionSet(ctx context.Context, cmd *cobra.Command, name, reso
 		props.RotationToLatestKeyVersionEnabled = to.Ptr(rotate)
 	}
 
-	update := armcompute.DiskEncryptionSetUpdate{Properties: props}
-
 	if flags.Changed("tags") {
 		tags, _ := flags.GetStringToString("tags")
 		azureTags := make(map[string]*string)
@@ -86,6 +106,11 @@ func updateDiskEncryptionSet(ctx context.Context, cmd *cobra.Command, name, reso
 		}
 		update.Tags = azureTags
 	}
+	if len(ops) > 0 {
+		if err := genericupdate.ApplyModel(&update, ops); err != nil {
+			return err
+		}
+	}
 
 	fmt.Printf("Updating disk encryption set '%s'...\n", name)
 	poller, err := client.BeginUpdate(ctx, resourceGroup, name, update, nil)
@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armcompute.NewDisksClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewDisksClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create disk client: %w", err)
 	}
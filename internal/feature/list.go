@@ -30,7 +30,7 @@ func List(ctx context.Context, cmd *cobra.Command, provider string) error {
     return err
   }
 
-  client, err := armfeatures.NewClient(subscriptionID, cred, nil)
+  client, err := armfeatures.NewClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create features client: %w", err)
   }
@@ -22,7 +22,7 @@ func Unregister(ctx context.Context, cmd *cobra.Command, provider, featureName s
     return err
   }
 
-  client, err := armfeatures.NewClient(subscriptionID, cred, nil)
+  client, err := armfeatures.NewClient(subscriptionID, cred, azure.ARMClientOptions())
   if err != nil {
     return fmt.Errorf("failed to create features client: %w", err)
   }
@@ -4,9 +4,12 @@ import (
 	"context"
 
 	"github.com/cdobbyn/azure-go-cli/internal/lock"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const groupResourceType = "Microsoft.Resources/resourceGroups"
+
 func NewGroupCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "group",
@@ -26,12 +29,10 @@ func NewGroupCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a resource group",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			return Show(context.Background(), cmd, name)
+			return bulk.Run(cmd, groupResourceType, Show)
 		},
 	}
-	showCmd.Flags().StringP("name", "n", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
+	addGroupTargetFlags(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -53,16 +54,23 @@ func NewGroupCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a resource group",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, noWait)
+			return bulk.Run(cmd, groupResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
-	deleteCmd.Flags().StringP("name", "n", "", "Resource group name")
+	addGroupTargetFlags(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
 	deleteCmd.Flags().BoolP("yes", "y", false, "Do not prompt for confirmation")
-	deleteCmd.MarkFlagRequired("name")
 
 	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd, lock.NewGroupLockCommand())
 	return cmd
 }
+
+// addGroupTargetFlags adds -n and --ids to a command that acts on one or more
+// existing resource groups. There is no -g: the group is the target.
+func addGroupTargetFlags(c *cobra.Command) {
+	c.Flags().StringP("name", "n", "", "Resource group name")
+	bulk.AddIDsFlag(c)
+}
@@ -0,0 +1,36 @@
+package group
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGroupCommandsTakeIDs(t *testing.T) {
+	vmID := "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1"
+	for _, verb := range []string{"show", "delete"} {
+		t.Run(verb, func(t *testing.T) {
+			root := &cobra.Command{Use: "az", SilenceUsage: true, SilenceErrors: true}
+			root.PersistentFlags().String("subscription", "", "")
+			root.PersistentFlags().String("output", "json", "")
+			root.PersistentFlags().String("query", "", "")
+			root.AddCommand(NewGroupCommand())
+			var out, errOut bytes.Buffer
+			root.SetOut(&out)
+			root.SetErr(&errOut)
+			root.SetArgs([]string{"group", verb, "--ids", vmID})
+
+			// The ID is checked before anything is called, so no -n/-g is
+			// needed and the wrong type fails without reaching Azure.
+			err := root.Execute()
+			if err == nil || err.Error() != "1 of 1 resources failed" {
+				t.Fatalf("err = %v, want the per-ID failure", err)
+			}
+			if !strings.Contains(errOut.String(), "not a "+groupResourceType) {
+				t.Errorf("stderr = %q, want a resource type mismatch", errOut.String())
+			}
+		})
+	}
+}
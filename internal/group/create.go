@@ -23,7 +23,7 @@ func Create(ctx context.Context, cmd *cobra.Command, name, location string, tags
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, nil)
+	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create resource groups client: %w", err)
 	}
@@ -6,41 +6,36 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, name string, noWait bool) error {
+func Delete(ctx context.Context, t bulk.Target, noWait bool) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, nil)
+	client, err := armresources.NewResourceGroupsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create resource groups client: %w", err)
 	}
 
-	poller, err := client.BeginDelete(ctx, name, nil)
+	poller, err := client.BeginDelete(ctx, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin delete resource group: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started deletion of resource group '%s'\n", name)
+		fmt.Printf("Started deletion of resource group '%s'\n", t.Name)
 		return nil
 	}
 
-	fmt.Printf("Deleting resource group '%s'...\n", name)
+	fmt.Printf("Deleting resource group '%s'...\n", t.Name)
 	_, err = poller.PollUntilDone(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete resource group: %w", err)
 	}
 
-	fmt.Printf("Deleted resource group '%s'\n", name)
+	fmt.Printf("Deleted resource group '%s'\n", t.Name)
 	return nil
 }
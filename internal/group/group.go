@@ -22,20 +22,21 @@ func List(ctx context.Context, cmd *cobra.Command) error {
 		return err
 	}
 
-	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, nil)
+	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create resource groups client: %w", err)
 	}
 
 	pager := client.NewListPager(nil)
-	var groups []map[string]interface{}
+	stream := output.NewStream(cmd)
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to list resource groups: %w", err)
+			return stream.Abort(fmt.Errorf("failed to list resource groups: %w", err))
 		}
 
+		groups := make([]map[string]interface{}, 0, len(page.Value))
 		for _, rg := range page.Value {
 			group := map[string]interface{}{
 				"name":     azure.GetStringValue(rg.Name),
@@ -46,7 +47,10 @@ func List(ctx context.Context, cmd *cobra.Command) error {
 			}
 			groups = append(groups, group)
 		}
+		if err := output.StreamPage(stream, groups); err != nil {
+			return err
+		}
 	}
 
-	return output.PrintJSON(cmd, groups)
+	return stream.Close()
 }
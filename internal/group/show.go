@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, resourceGroupName string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armresources.NewResourceGroupsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create resource groups client: %w", err)
 	}
 
-	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, nil)
+	rg, err := client.Get(ctx, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create resource groups client: %w", err)
+		return nil, fmt.Errorf("failed to get resource group: %w", err)
 	}
 
-	rg, err := client.Get(ctx, resourceGroupName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get resource group: %w", err)
-	}
-
-	return output.PrintJSON(cmd, rg)
+	return rg, nil
 }
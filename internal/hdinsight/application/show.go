@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName, a
 		return err
 	}
 
-	client, err := armhdinsight.NewApplicationsClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewApplicationsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create applications client: %w", err)
 	}
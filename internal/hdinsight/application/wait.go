@@ -42,7 +42,7 @@ func Wait(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName, a
 		return err
 	}
 
-	client, err := armhdinsight.NewApplicationsClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewApplicationsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create applications client: %w", err)
 	}
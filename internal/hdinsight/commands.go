@@ -9,9 +9,12 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/hdinsight/host"
 	"github.com/cdobbyn/azure-go-cli/internal/hdinsight/monitor"
 	"github.com/cdobbyn/azure-go-cli/internal/hdinsight/scriptaction"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const clusterResourceType = "Microsoft.HDInsight/clusters"
+
 func NewHDInsightCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "hdinsight",
@@ -33,31 +36,23 @@ func NewHDInsightCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of an HDInsight cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			rg, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, rg, name)
+			return bulk.Run(cmd, clusterResourceType, Show)
 		},
 	}
-	showCmd.Flags().StringP("name", "n", "", "Cluster name")
-	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	addClusterTargetFlags(showCmd)
 
 	deleteCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete an HDInsight cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			rg, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), cmd, rg, name, noWait)
+			return bulk.Run(cmd, clusterResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Delete(ctx, t, noWait)
+			})
 		},
 	}
-	deleteCmd.Flags().StringP("name", "n", "", "Cluster name")
-	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addClusterTargetFlags(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	resizeCmd := &cobra.Command{
 		Use:   "resize",
@@ -120,17 +115,14 @@ func NewHDInsightCommand() *cobra.Command {
 		Use:   "update",
 		Short: "Update the tags of an HDInsight cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			rg, _ := cmd.Flags().GetString("resource-group")
 			tags, _ := cmd.Flags().GetStringSlice("tags")
-			return Update(context.Background(), cmd, rg, name, tags)
+			return bulk.Run(cmd, clusterResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, t, tags)
+			})
 		},
 	}
-	updateCmd.Flags().StringP("name", "n", "", "Cluster name")
-	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addClusterTargetFlags(updateCmd)
 	updateCmd.Flags().StringSlice("tags", nil, "Resource tags as key=value pairs")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	waitCmd := &cobra.Command{
 		Use:   "wait",
@@ -166,3 +158,11 @@ func NewHDInsightCommand() *cobra.Command {
 	)
 	return cmd
 }
+
+// addClusterTargetFlags adds -n/-g and --ids to a command that acts on one or
+// more existing clusters.
+func addClusterTargetFlags(c *cobra.Command) {
+	c.Flags().StringP("name", "n", "", "Cluster name")
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(c)
+}
@@ -42,7 +42,7 @@ func Wait(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName st
 		return err
 	}
 
-	client, err := armhdinsight.NewClustersClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create clusters client: %w", err)
 	}
@@ -6,40 +6,33 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/hdinsight/armhdinsight"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName string, noWait bool) error {
+func Delete(ctx context.Context, t bulk.Target, noWait bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armhdinsight.NewClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create clusters client: %w", err)
 	}
 
-	client, err := armhdinsight.NewClustersClient(subscriptionID, cred, nil)
+	fmt.Printf("Deleting cluster '%s'...\n", t.Name)
+	poller, err := client.BeginDelete(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create clusters client: %w", err)
-	}
-
-	fmt.Printf("Deleting cluster '%s'...\n", clusterName)
-	poller, err := client.BeginDelete(ctx, resourceGroup, clusterName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin delete: %w", err)
+		return nil, fmt.Errorf("failed to begin delete: %w", err)
 	}
 
 	if noWait {
-		return output.PrintJSON(cmd, map[string]string{"status": "delete started"})
+		return map[string]string{"status": "delete started"}, nil
 	}
 
 	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
-		return fmt.Errorf("delete failed: %w", err)
+		return nil, fmt.Errorf("delete failed: %w", err)
 	}
 
-	return output.PrintJSON(cmd, map[string]string{"status": fmt.Sprintf("'%s' deleted.", clusterName)})
+	return map[string]string{"status": fmt.Sprintf("'%s' deleted.", t.Name)}, nil
 }
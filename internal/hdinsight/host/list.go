@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName st
 		return err
 	}
 
-	client, err := armhdinsight.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewVirtualMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual machines client: %w", err)
 	}
@@ -23,7 +23,7 @@ func Restart(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName
 		return err
 	}
 
-	client, err := armhdinsight.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewVirtualMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual machines client: %w", err)
 	}
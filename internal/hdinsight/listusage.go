@@ -22,7 +22,7 @@ func ListUsage(ctx context.Context, cmd *cobra.Command, location string) error {
 		return err
 	}
 
-	client, err := armhdinsight.NewLocationsClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewLocationsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create locations client: %w", err)
 	}
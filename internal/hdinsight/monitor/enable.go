@@ -23,7 +23,7 @@ func Enable(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName,
 		return err
 	}
 
-	client, err := armhdinsight.NewExtensionsClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewExtensionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create extensions client: %w", err)
 	}
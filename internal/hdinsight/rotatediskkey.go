@@ -23,7 +23,7 @@ func RotateDiskEncryptionKey(ctx context.Context, cmd *cobra.Command, resourceGr
 		return err
 	}
 
-	client, err := armhdinsight.NewClustersClient(subscriptionID, cred, nil)
+	client, err := armhdinsight.NewClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create clusters client: %w", err)
 	}
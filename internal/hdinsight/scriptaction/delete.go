@@ -22,7 +22,7 @@ func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName,
 		return err
 	}
 
-	actions, err := armhdinsight.NewScriptActionsClient(subscriptionID, cred, nil)
+	actions, err := armhdinsight.NewScriptActionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create script actions client: %w", err)
 	}
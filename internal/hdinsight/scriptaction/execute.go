@@ -23,7 +23,7 @@ func Execute(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName
 		return err
 	}
 
-	clusters, err := armhdinsight.NewClustersClient(subscriptionID, cred, nil)
+	clusters, err := armhdinsight.NewClustersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create clusters client: %w", err)
 	}
@@ -22,7 +22,7 @@ func Promote(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName
 		return err
 	}
 
-	history, err := armhdinsight.NewScriptExecutionHistoryClient(subscriptionID, cred, nil)
+	history, err := armhdinsight.NewScriptExecutionHistoryClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create script execution history client: %w", err)
 	}
@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/hdinsight/armhdinsight"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armhdinsight.NewClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create clusters client: %w", err)
 	}
 
-	client, err := armhdinsight.NewClustersClient(subscriptionID, cred, nil)
+	resp, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create clusters client: %w", err)
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	resp, err := client.Get(ctx, resourceGroup, clusterName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get cluster: %w", err)
-	}
-
-	return output.PrintJSON(cmd, resp.Cluster)
+	return resp.Cluster, nil
 }
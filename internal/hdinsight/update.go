@@ -8,9 +8,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/hdinsight/armhdinsight"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
 // parseTags turns "key=value" pairs into an ARM tag map. A pair without '='
@@ -31,28 +29,23 @@ func parseTags(pairs []string) map[string]*string {
 	return tags
 }
 
-func Update(ctx context.Context, cmd *cobra.Command, resourceGroup, clusterName string, tagPairs []string) error {
+func Update(ctx context.Context, t bulk.Target, tagPairs []string) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armhdinsight.NewClustersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create clusters client: %w", err)
 	}
 
-	client, err := armhdinsight.NewClustersClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create clusters client: %w", err)
-	}
-
-	resp, err := client.Update(ctx, resourceGroup, clusterName, armhdinsight.ClusterPatchParameters{
+	resp, err := client.Update(ctx, t.ResourceGroup, t.Name, armhdinsight.ClusterPatchParameters{
 		Tags: parseTags(tagPairs),
 	}, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update cluster: %w", err)
+		return nil, fmt.Errorf("failed to update cluster: %w", err)
 	}
 
-	return output.PrintJSON(cmd, resp.Cluster)
+	return resp.Cluster, nil
 }
@@ -3,9 +3,12 @@ package identity
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.ManagedIdentity/userAssignedIdentities"
+
 func NewIdentityCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "identity",
@@ -29,28 +32,12 @@ func NewIdentityCommand() *cobra.Command {
 		Short: "Show details of a managed identity",
 		Long:  "Show details of a managed identity by name and resource group, or by one or more resource IDs",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ids, _ := cmd.Flags().GetStringSlice("ids")
-			subscription, _ := cmd.Flags().GetString("subscription")
-
-			// If --ids is provided, use ShowByIDs
-			if len(ids) > 0 {
-				return ShowByIDs(context.Background(), cmd, ids, subscription)
-			}
-
-			// Otherwise use name and resource-group
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-
-			if name == "" || resourceGroup == "" {
-				return cmd.Usage()
-			}
-
-			return Show(context.Background(), cmd, name, resourceGroup, subscription)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Managed identity name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.Flags().StringSlice("ids", []string{}, "One or more resource IDs (space-separated)")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -76,16 +63,14 @@ func NewIdentityCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a managed identity",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			subscription, _ := cmd.Flags().GetString("subscription")
-			return Delete(context.Background(), name, resourceGroup, subscription)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Managed identity name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(deleteCmd)
 
 	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd)
 	return cmd
@@ -23,7 +23,7 @@ func Create(ctx context.Context, cmd *cobra.Command, name, resourceGroup, locati
 		return err
 	}
 
-	client, err := armmsi.NewUserAssignedIdentitiesClient(subscriptionID, cred, nil)
+	client, err := armmsi.NewUserAssignedIdentitiesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create managed identities client: %w", err)
 	}
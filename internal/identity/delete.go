@@ -6,30 +6,25 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, name, resourceGroup string, subscriptionOverride string) error {
+func Delete(ctx context.Context, t bulk.Target) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetSubscription(subscriptionOverride)
-	if err != nil {
-		return err
-	}
-
-	client, err := armmsi.NewUserAssignedIdentitiesClient(subscriptionID, cred, nil)
+	client, err := armmsi.NewUserAssignedIdentitiesClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create managed identities client: %w", err)
 	}
 
-	_, err = client.Delete(ctx, resourceGroup, name, nil)
+	_, err = client.Delete(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete managed identity: %w", err)
 	}
 
-	fmt.Printf("Deleted managed identity '%s' in resource group '%s'\n", name, resourceGroup)
+	fmt.Printf("Deleted managed identity '%s' in resource group '%s'\n", t.Name, t.ResourceGroup)
 	return nil
 }
@@ -22,12 +22,12 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, subscriptionOv
 		return err
 	}
 
-	client, err := armmsi.NewUserAssignedIdentitiesClient(subscriptionID, cred, nil)
+	client, err := armmsi.NewUserAssignedIdentitiesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create managed identities client: %w", err)
 	}
 
-	var identities []map[string]interface{}
+	stream := output.NewStream(cmd)
 
 	// List by resource group if specified, otherwise list all in subscription
 	if resourceGroup != "" {
@@ -35,11 +35,15 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, subscriptionOv
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list managed identities: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list managed identities: %w", err))
 			}
 
+			items := make([]map[string]interface{}, 0, len(page.Value))
 			for _, identity := range page.Value {
-				identities = append(identities, formatIdentity(identity))
+				items = append(items, formatIdentity(identity))
+			}
+			if err := output.StreamPage(stream, items); err != nil {
+				return err
 			}
 		}
 	} else {
@@ -47,16 +51,20 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, subscriptionOv
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list managed identities: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list managed identities: %w", err))
 			}
 
+			items := make([]map[string]interface{}, 0, len(page.Value))
 			for _, identity := range page.Value {
-				identities = append(identities, formatIdentity(identity))
+				items = append(items, formatIdentity(identity))
+			}
+			if err := output.StreamPage(stream, items); err != nil {
+				return err
 			}
 		}
 	}
 
-	return output.PrintJSON(cmd, identities)
+	return stream.Close()
 }
 
 func formatIdentity(identity *armmsi.Identity) map[string]interface{} {
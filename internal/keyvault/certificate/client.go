@@ -15,8 +15,8 @@ func certificateClient(vaultName string) (*azcertificates.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", vaultName)
-	client, err := azcertificates.NewClient(vaultURL, cred, nil)
+	vaultURL := azure.KeyVaultURL(vaultName)
+	client, err := azcertificates.NewClient(vaultURL, cred, &azcertificates.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create certificate client: %w", err)
 	}
@@ -16,9 +16,9 @@ func List(ctx context.Context, cmd *cobra.Command, vaultName string) error {
 		return err
 	}
 
-	vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", vaultName)
+	vaultURL := azure.KeyVaultURL(vaultName)
 
-	client, err := azcertificates.NewClient(vaultURL, cred, nil)
+	client, err := azcertificates.NewClient(vaultURL, cred, &azcertificates.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return fmt.Errorf("failed to create certificate client: %w", err)
 	}
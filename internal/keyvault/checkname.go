@@ -21,7 +21,7 @@ func CheckName(ctx context.Context, cmd *cobra.Command, name string) error {
 	if err != nil {
 		return err
 	}
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create key vaults client: %w", err)
 	}
@@ -6,9 +6,13 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/keyvault/certificate"
 	"github.com/cdobbyn/azure-go-cli/internal/keyvault/key"
 	"github.com/cdobbyn/azure-go-cli/internal/keyvault/secret"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.KeyVault/vaults"
+
 func NewKeyVaultCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "keyvault",
@@ -30,15 +34,12 @@ func NewKeyVaultCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a key vault",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vaultName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, vaultName, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Key vault name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -63,35 +64,34 @@ func NewKeyVaultCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a key vault",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Delete(context.Background(), name, resourceGroup)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Key vault name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(deleteCmd)
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update a key vault",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			tags, _ := cmd.Flags().GetStringToString("tags")
-			return Update(context.Background(), cmd, name, resourceGroup, tags)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, tags)
+			})
 		},
 	}
 	updateCmd.Flags().StringP("name", "n", "", "Key vault name")
 	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(updateCmd)
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
 	updateCmd.Flags().Bool("enable-rbac-authorization", false, "Enable Azure RBAC for data-plane authorization")
 	updateCmd.Flags().Bool("enabled-for-deployment", false, "Allow VMs to retrieve certificates as secrets")
 	updateCmd.Flags().Bool("enabled-for-disk-encryption", false, "Allow Azure Disk Encryption to retrieve secrets and unwrap keys")
 	updateCmd.Flags().Bool("enabled-for-template-deployment", false, "Allow Resource Manager to retrieve secrets")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	listDeletedCmd := &cobra.Command{
 		Use:   "list-deleted",
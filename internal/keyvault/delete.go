@@ -6,30 +6,25 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, name, resourceGroup string) error {
+func Delete(ctx context.Context, t bulk.Target) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewVaultsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create key vaults client: %w", err)
 	}
 
-	_, err = client.Delete(ctx, resourceGroup, name, nil)
+	_, err = client.Delete(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete key vault: %w", err)
 	}
 
-	fmt.Printf("Deleted key vault '%s' (soft delete enabled, can be recovered)\n", name)
+	fmt.Printf("Deleted key vault '%s' (soft delete enabled, can be recovered)\n", t.Name)
 	return nil
 }
@@ -95,8 +95,8 @@ func Create(ctx context.Context, cmd *cobra.Command, opts CreateOptions) error {
 	if err != nil {
 		return err
 	}
-	vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", opts.VaultName)
-	client, err := azkeys.NewClient(vaultURL, cred, nil)
+	vaultURL := azure.KeyVaultURL(opts.VaultName)
+	client, err := azkeys.NewClient(vaultURL, cred, &azkeys.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return fmt.Errorf("failed to create key client: %w", err)
 	}
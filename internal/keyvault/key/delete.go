@@ -15,8 +15,8 @@ func Delete(ctx context.Context, cmd *cobra.Command, vaultName, name string) err
 	if err != nil {
 		return err
 	}
-	vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", vaultName)
-	client, err := azkeys.NewClient(vaultURL, cred, nil)
+	vaultURL := azure.KeyVaultURL(vaultName)
+	client, err := azkeys.NewClient(vaultURL, cred, &azkeys.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return fmt.Errorf("failed to create key client: %w", err)
 	}
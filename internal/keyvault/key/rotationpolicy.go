@@ -181,8 +181,8 @@ func keyClient(vaultName string) (*azkeys.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", vaultName)
-	client, err := azkeys.NewClient(vaultURL, cred, nil)
+	vaultURL := azure.KeyVaultURL(vaultName)
+	client, err := azkeys.NewClient(vaultURL, cred, &azkeys.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create key client: %w", err)
 	}
@@ -23,7 +23,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return err
 	}
 
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create key vaults client: %w", err)
 	}
@@ -37,7 +37,7 @@ func vaultsClient() (*armkeyvault.VaultsClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create key vaults client: %w", err)
 	}
@@ -58,12 +58,12 @@ func graphGet(ctx context.Context, path string) ([]byte, error) {
 		return nil, err
 	}
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://graph.microsoft.com/.default"},
+		Scopes: []string{azure.GraphScope()},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get a Microsoft Graph token: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://graph.microsoft.com"+path, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, azure.GraphEndpoint()+path, nil)
 	if err != nil {
 		return nil, err
 	}
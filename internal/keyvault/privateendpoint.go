@@ -21,7 +21,7 @@ func privateEndpointClient() (*armkeyvault.PrivateEndpointConnectionsClient, err
 	if err != nil {
 		return nil, err
 	}
-	client, err := armkeyvault.NewPrivateEndpointConnectionsClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewPrivateEndpointConnectionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create private endpoint connections client: %w", err)
 	}
@@ -119,7 +119,7 @@ func ListPrivateLinkResources(ctx context.Context, cmd *cobra.Command, vaultName
 	if err != nil {
 		return err
 	}
-	client, err := armkeyvault.NewPrivateLinkResourcesClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewPrivateLinkResourcesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create private link resources client: %w", err)
 	}
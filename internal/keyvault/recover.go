@@ -22,7 +22,7 @@ func Recover(ctx context.Context, cmd *cobra.Command, name, resourceGroup, locat
 	if err != nil {
 		return err
 	}
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create key vaults client: %w", err)
 	}
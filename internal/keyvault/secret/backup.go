@@ -18,9 +18,9 @@ func Backup(ctx context.Context, cmd *cobra.Command, vaultName, name, file strin
 		return err
 	}
 
-	vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", vaultName)
+	vaultURL := azure.KeyVaultURL(vaultName)
 
-	client, err := azsecrets.NewClient(vaultURL, cred, nil)
+	client, err := azsecrets.NewClient(vaultURL, cred, &azsecrets.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return fmt.Errorf("failed to create secrets client: %w", err)
 	}
@@ -14,10 +14,10 @@ func Delete(ctx context.Context, vaultName, name string) error {
 		return err
 	}
 
-	// Key Vault URL format: https://{vault-name}.vault.azure.net/
-	vaultURL := fmt.Sprintf("https://%s.vault.azure.net/", vaultName)
+	// Key Vault URL format: https://{vault-name}{keyvault DNS suffix}/
+	vaultURL := azure.KeyVaultURL(vaultName)
 
-	client, err := azsecrets.NewClient(vaultURL, cred, nil)
+	client, err := azsecrets.NewClient(vaultURL, cred, &azsecrets.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return fmt.Errorf("failed to create secrets client: %w", err)
 	}
@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, vaultName, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armkeyvault.NewVaultsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create key vaults client: %w", err)
 	}
 
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+	vault, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create key vaults client: %w", err)
+		return nil, fmt.Errorf("failed to get key vault: %w", err)
 	}
 
-	vault, err := client.Get(ctx, resourceGroup, vaultName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get key vault: %w", err)
-	}
-
-	return output.PrintJSON(cmd, vault)
+	return vault, nil
 }
@@ -20,7 +20,7 @@ func ShowDeleted(ctx context.Context, cmd *cobra.Command, name, location string)
 	if err != nil {
 		return err
 	}
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create key vaults client: %w", err)
 	}
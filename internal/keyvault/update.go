@@ -7,28 +7,44 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
 // Update applies a partial (PATCH) update to a vault. Only flags the user set
 // are sent; unset flags leave the corresponding vault property unchanged.
-func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, tags map[string]string) error {
+// --set/--add/--remove edit the vault's current state, which is then sent
+// whole.
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, tags map[string]string) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armkeyvault.NewVaultsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create key vaults client: %w", err)
 	}
-	client, err := armkeyvault.NewVaultsClient(subscriptionID, cred, nil)
+
+	parameters := armkeyvault.VaultPatchParameters{}
+	ops, err := genericupdate.OpsFromFlags(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to create key vaults client: %w", err)
+		return nil, err
+	}
+	if len(ops) > 0 {
+		current, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get key vault: %w", err)
+		}
+		if err := genericupdate.Seed(&parameters, current.Vault); err != nil {
+			return nil, err
+		}
+	}
+	if parameters.Properties == nil {
+		parameters.Properties = &armkeyvault.VaultPatchProperties{}
 	}
 
-	props := &armkeyvault.VaultPatchProperties{}
+	props := parameters.Properties
 	if cmd.Flags().Changed("enable-rbac-authorization") {
 		b, _ := cmd.Flags().GetBool("enable-rbac-authorization")
 		props.EnableRbacAuthorization = to.Ptr(b)
@@ -46,7 +62,6 @@ func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string,
 		props.EnabledForTemplateDeployment = to.Ptr(b)
 	}
 
-	parameters := armkeyvault.VaultPatchParameters{Properties: props}
 	if len(tags) > 0 {
 		azureTags := make(map[string]*string, len(tags))
 		for k, v := range tags {
@@ -54,10 +69,15 @@ func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string,
 		}
 		parameters.Tags = azureTags
 	}
+	if len(ops) > 0 {
+		if err := genericupdate.ApplyModel(&parameters, ops); err != nil {
+			return nil, err
+		}
+	}
 
-	resp, err := client.Update(ctx, resourceGroup, name, parameters, nil)
+	resp, err := client.Update(ctx, t.ResourceGroup, t.Name, parameters, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update key vault: %w", err)
+		return nil, fmt.Errorf("failed to update key vault: %w", err)
 	}
-	return output.PrintJSON(cmd, resp.Vault)
+	return resp.Vault, nil
 }
@@ -0,0 +1,105 @@
+package lock
+
+import (
+  "context"
+  "sort"
+  "strings"
+
+  "github.com/cdobbyn/azure-go-cli/pkg/output"
+  "github.com/spf13/cobra"
+)
+
+// auditRecord says whether one resource group or resource is protected by a
+// lock, and by which. Level is the strongest lock that applies. Fields are
+// alphabetical to match the key order of the other lock records.
+type auditRecord struct {
+  ID            string   `json:"id"`
+  Inherited     bool     `json:"inherited"`
+  Level         string   `json:"level"`
+  Locks         []string `json:"locks"`
+  Name          string   `json:"name"`
+  Protected     bool     `json:"protected"`
+  ResourceGroup string   `json:"resourceGroup,omitempty"`
+  Type          string   `json:"type"`
+}
+
+func newAuditCmd() *cobra.Command {
+  cmd := &cobra.Command{
+    Use:   "audit",
+    Short: "Report which resource groups and resources are protected by a lock",
+    Long: "Report, for every resource group and resource in the subscription, the locks that apply to it: " +
+      "its own and those inherited from its resource group, parent resources and the subscription. " +
+      "inherited is true when every applicable lock sits on a parent scope.",
+    Example: "  az lock audit --unprotected-only -o table",
+    RunE: func(cmd *cobra.Command, args []string) error {
+      return runAudit(cmd)
+    },
+  }
+  cmd.Flags().StringP("resource-group", "g", "", "Only audit this resource group and the resources in it")
+  cmd.Flags().String("tag", "", "Only audit resource groups and resources with this tag, as key or key=value")
+  cmd.Flags().Bool("unprotected-only", false, "Only report resource groups and resources no lock applies to")
+  return cmd
+}
+
+func runAudit(cmd *cobra.Command) error {
+  ctx := context.Background()
+  tag, _ := cmd.Flags().GetString("tag")
+  unprotectedOnly, _ := cmd.Flags().GetBool("unprotected-only")
+
+  scopes, err := listTaggedScopes(ctx, cmd, tag)
+  if err != nil {
+    return err
+  }
+  client, err := newLocksClient(cmd)
+  if err != nil {
+    return err
+  }
+  locks, err := listSubscriptionLocks(ctx, client)
+  if err != nil {
+    return err
+  }
+
+  records := auditScopes(scopes, locks)
+  if unprotectedOnly {
+    unprotected := make([]auditRecord, 0, len(records))
+    for _, r := range records {
+      if !r.Protected {
+        unprotected = append(unprotected, r)
+      }
+    }
+    records = unprotected
+  }
+  format, _ := cmd.Flags().GetString("output")
+  return output.PrintFormatted(cmd, records, format)
+}
+
+// auditScopes works out the locks that apply to each scope. A lock applies
+// to the scope it sits on and everything below it, so a scope is covered by
+// every lock whose scope is the scope itself or a path prefix of it.
+func auditScopes(scopes []taggedScope, locks []lockRecord) []auditRecord {
+  index := locksAt(locks)
+  records := make([]auditRecord, 0, len(scopes))
+  for _, s := range scopes {
+    id := strings.ToLower(s.ID)
+    r := auditRecord{ID: s.ID, Inherited: true, Locks: []string{}, Name: s.Name, ResourceGroup: s.ResourceGroup, Type: s.Type}
+    for scope, ls := range index {
+      if id != scope && !strings.HasPrefix(id, scope+"/") {
+        continue
+      }
+      if id == scope {
+        r.Inherited = false
+      }
+      for _, l := range ls {
+        r.Locks = append(r.Locks, l.ID)
+        if lockTypeOrder[l.Level] > lockTypeOrder[r.Level] {
+          r.Level = l.Level
+        }
+      }
+    }
+    sort.Strings(r.Locks)
+    r.Protected = len(r.Locks) > 0
+    r.Inherited = r.Protected && r.Inherited
+    records = append(records, r)
+  }
+  return records
+}
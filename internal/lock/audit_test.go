@@ -0,0 +1,43 @@
+package lock
+
+import (
+  "testing"
+)
+
+func TestAuditScopes(t *testing.T) {
+  const rg = "/subscriptions/s1/resourceGroups/rg1"
+  const site = rg + "/providers/Microsoft.Web/sites/app"
+  locks := []lockRecord{
+    {ID: rg + "/providers/Microsoft.Authorization/locks/rg-lock", Level: "CanNotDelete"},
+    {ID: site + "/providers/Microsoft.Authorization/locks/site-lock", Level: "ReadOnly"},
+  }
+  scopes := []taggedScope{
+    {ID: rg, Name: "rg1"},
+    {ID: site, Name: "app"},
+    {ID: rg + "/providers/Microsoft.Storage/storageAccounts/st", Name: "st"},
+    // Shares a prefix with rg1 but isn't inside it.
+    {ID: "/subscriptions/s1/resourceGroups/rg10", Name: "rg10"},
+  }
+  got := auditScopes(scopes, locks)
+
+  if r := got[0]; !r.Protected || r.Inherited || r.Level != "CanNotDelete" || len(r.Locks) != 1 {
+    t.Errorf("rg1: %+v", r)
+  }
+  if r := got[1]; !r.Protected || r.Inherited || r.Level != "ReadOnly" || len(r.Locks) != 2 {
+    t.Errorf("site: %+v", r)
+  }
+  if r := got[2]; !r.Protected || !r.Inherited || r.Level != "CanNotDelete" {
+    t.Errorf("storage account should inherit the group lock: %+v", r)
+  }
+  if r := got[3]; r.Protected || r.Inherited || r.Level != "" || r.Locks == nil {
+    t.Errorf("rg10 should be unprotected with an empty lock list: %+v", r)
+  }
+}
+
+func TestAuditScopesIsCaseInsensitive(t *testing.T) {
+  locks := []lockRecord{{ID: "/subscriptions/S1/providers/Microsoft.Authorization/locks/sub", Level: "CanNotDelete"}}
+  got := auditScopes([]taggedScope{{ID: "/subscriptions/s1/resourcegroups/rg"}}, locks)
+  if !got[0].Protected || !got[0].Inherited {
+    t.Errorf("subscription lock should cover the group: %+v", got[0])
+  }
+}
@@ -27,7 +27,7 @@ func newLocksClient(cmd *cobra.Command) (*armlocks.ManagementLocksClient, error)
   if err != nil {
     return nil, err
   }
-  c, err := armlocks.NewManagementLocksClient(sub, cred, nil)
+  c, err := armlocks.NewManagementLocksClient(sub, cred, azure.ARMClientOptions())
   if err != nil {
     return nil, fmt.Errorf("failed to create locks client: %w", err)
   }
@@ -38,9 +38,16 @@ func newGroupCmd(use, short string, kind scopeKind) *cobra.Command {
   return cmd
 }
 
-// NewLockCommand returns the root `az lock` cobra command.
+// NewLockCommand returns the root `az lock` cobra command. Only it gets the
+// bulk verbs: they span a whole subscription rather than one scope.
 func NewLockCommand() *cobra.Command {
-  return newGroupCmd("lock", "Manage Azure locks", kindGeneric)
+  cmd := newGroupCmd("lock", "Manage Azure locks", kindGeneric)
+  bulk := []*cobra.Command{newApplyCmd(), newRemoveCmd(), newAuditCmd()}
+  for _, v := range bulk {
+    v.SilenceUsage = true
+  }
+  cmd.AddCommand(bulk...)
+  return cmd
 }
 
 // NewAccountLockCommand returns `az account lock`.
@@ -0,0 +1,63 @@
+package lock
+
+import (
+  "fmt"
+  "strings"
+
+  "github.com/cdobbyn/azure-go-cli/pkg/config"
+  "github.com/spf13/cobra"
+)
+
+// AddScopeFlags registers -g, --resource, --resource-type, --namespace and
+// --parent for command groups outside az lock that target a subscription,
+// resource group or resource the same way. Callers add their own --scope.
+func AddScopeFlags(cmd *cobra.Command) {
+  addScopeFlags(cmd, kindGeneric)
+  cmd.Flags().Lookup("resource").Usage = "Name or ID of the resource. If an ID is given, other resource arguments should not be given"
+}
+
+// ResolveScopePath returns the ARM scope the flags registered by
+// AddScopeFlags name, e.g. /subscriptions/{id}/resourceGroups/{rg}. A
+// non-empty --scope is returned as given and can't be combined with them.
+func ResolveScopePath(cmd *cobra.Command) (string, error) {
+  if scope := flagOrEmpty(cmd, "scope"); scope != "" {
+    for _, name := range []string{"resource-group", "resource", "resource-type", "namespace", "parent"} {
+      if flagOrEmpty(cmd, name) != "" {
+        return "", fmt.Errorf("cannot mix --scope with --%s", name)
+      }
+    }
+    return "/" + strings.Trim(scope, "/"), nil
+  }
+
+  s, err := resolveScope(cmd)
+  if err != nil {
+    return "", err
+  }
+  // A full resource ID in --resource carries its own subscription.
+  if resource := flagOrEmpty(cmd, "resource"); s.Level == scopeResource && strings.HasPrefix(resource, "/subscriptions/") {
+    return strings.TrimRight(resource, "/"), nil
+  }
+  sub, _ := cmd.Flags().GetString("subscription")
+  subscriptionID, err := config.GetSubscription(sub)
+  if err != nil {
+    return "", fmt.Errorf("failed to get subscription: %w", err)
+  }
+  return s.path(subscriptionID), nil
+}
+
+// path renders s as an ARM scope in subscriptionID.
+func (s lockScope) path(subscriptionID string) string {
+  p := "/subscriptions/" + subscriptionID
+  if s.Level == scopeSubscription {
+    return p
+  }
+  p += "/resourceGroups/" + s.ResourceGroup
+  if s.Level == scopeResourceGroup {
+    return p
+  }
+  p += "/providers/" + s.Namespace
+  if s.Parent != "" {
+    p += "/" + s.Parent
+  }
+  return p + "/" + s.ResourceType + "/" + s.ResourceName
+}
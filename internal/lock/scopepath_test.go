@@ -0,0 +1,46 @@
+package lock
+
+import (
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+)
+
+func TestResolveScopePath(t *testing.T) {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+  t.Setenv("AZ_SESSION", "")
+  os.MkdirAll(filepath.Join(home, ".azure"), 0700)
+  os.WriteFile(filepath.Join(home, ".azure", "azureProfile.json"), []byte(`{"subscriptions": [{"id": "sub-id", "name": "test-sub", "isDefault": true}]}`), 0600)
+
+  vnet := "/subscriptions/other/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/v1"
+  tests := []struct {
+    args []string
+    want string
+  }{
+    {nil, "/subscriptions/sub-id"},
+    {[]string{"-g", "rg1"}, "/subscriptions/sub-id/resourceGroups/rg1"},
+    {[]string{"-g", "rg1", "--resource", "s1", "--resource-type", "Microsoft.Network/subnets", "--parent", "virtualNetworks/v1"}, "/subscriptions/sub-id/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/v1/subnets/s1"},
+    {[]string{"--resource", vnet + "/"}, vnet},
+    {[]string{"--scope", "providers/Microsoft.Management/managementGroups/mg1/"}, "/providers/Microsoft.Management/managementGroups/mg1"},
+  }
+  for _, tt := range tests {
+    c := newScopeCmd(kindGeneric)
+    c.Flags().String("scope", "", "")
+    if err := c.ParseFlags(tt.args); err != nil {
+      t.Fatal(err)
+    }
+    got, err := ResolveScopePath(c)
+    if err != nil || got != tt.want {
+      t.Errorf("%v: got %q, %v\nwant %q", tt.args, got, err, tt.want)
+    }
+  }
+
+  c := newScopeCmd(kindGeneric)
+  c.Flags().String("scope", "", "")
+  c.ParseFlags([]string{"--scope", "/subscriptions/x", "-g", "rg1"})
+  if _, err := ResolveScopePath(c); err == nil || !strings.Contains(err.Error(), "cannot mix --scope with --resource-group") {
+    t.Errorf("err = %v", err)
+  }
+}
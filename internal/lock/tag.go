@@ -0,0 +1,381 @@
+package lock
+
+import (
+  "context"
+  "fmt"
+  "strings"
+
+  "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+  "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+  "github.com/cdobbyn/azure-go-cli/pkg/azure"
+  "github.com/cdobbyn/azure-go-cli/pkg/output"
+  "github.com/spf13/cobra"
+)
+
+// defaultTagLockName is the lock apply creates and remove deletes when no
+// --name is given, so the two undo each other.
+const defaultTagLockName = "tag-lock"
+
+// lockTypeOrder ranks lock levels by strength: ReadOnly also blocks deletes.
+var lockTypeOrder = map[string]int{
+  string(armlocks.LockLevelCanNotDelete): 1,
+  string(armlocks.LockLevelReadOnly):     2,
+}
+
+// taggedScope is a resource or resource group selected by tag.
+type taggedScope struct {
+  ID            string
+  Name          string
+  Type          string
+  ResourceGroup string
+}
+
+// tagLockRecord reports what apply or remove did at one scope. Fields are
+// alphabetical to match the key order of the other lock records.
+type tagLockRecord struct {
+  Action        string `json:"action"`
+  Lock          string `json:"lock"`
+  Name          string `json:"name"`
+  ResourceGroup string `json:"resourceGroup,omitempty"`
+  Scope         string `json:"scope"`
+  Type          string `json:"type"`
+}
+
+func newApplyCmd() *cobra.Command {
+  cmd := &cobra.Command{
+    Use:   "apply",
+    Short: "Lock every resource and resource group with a tag",
+    Long: "Create a lock on every resource and resource group carrying the given tag. " +
+      "Scopes that already hold a lock of the same or a stronger type directly are left alone, " +
+      "so apply can be re-run safely. That includes a stronger lock with the same name; " +
+      "pass --allow-downgrade to weaken it to --lock-type. " +
+      "A scope that fails is reported and the rest are still locked.",
+    Example: "  az lock apply --tag env=prod --lock-type CanNotDelete",
+    RunE: func(cmd *cobra.Command, args []string) error {
+      return runApply(cmd)
+    },
+  }
+  addTagFlags(cmd)
+  cmd.Flags().StringP("lock-type", "t", "", "The type of lock restriction. Allowed values: CanNotDelete, ReadOnly")
+  cmd.Flags().String("notes", "", "Notes about the locks")
+  cmd.Flags().Bool("allow-downgrade", false, "Weaken a stronger lock of the same name to --lock-type instead of leaving it alone")
+  _ = cmd.MarkFlagRequired("lock-type")
+  return cmd
+}
+
+func newRemoveCmd() *cobra.Command {
+  cmd := &cobra.Command{
+    Use:   "remove",
+    Short: "Remove the locks apply created on resources and resource groups with a tag",
+    Long: "Delete the lock called --name directly on every resource and resource group carrying the given tag. " +
+      "Locks inherited from a parent scope are not touched.",
+    Example: "  az lock remove --tag env=prod",
+    RunE: func(cmd *cobra.Command, args []string) error {
+      return runRemove(cmd)
+    },
+  }
+  addTagFlags(cmd)
+  cmd.Flags().StringP("lock-type", "t", "", "Only remove locks of this type. Allowed values: CanNotDelete, ReadOnly")
+  return cmd
+}
+
+func addTagFlags(cmd *cobra.Command) {
+  cmd.Flags().String("tag", "", "Tag selecting the resources and resource groups, as key or key=value")
+  cmd.Flags().StringP("resource-group", "g", "", "Only consider this resource group and the resources in it")
+  cmd.Flags().StringP("name", "n", defaultTagLockName, "Name of the lock")
+  _ = cmd.MarkFlagRequired("tag")
+}
+
+// tagFilter builds the $filter that az resource list uses for --tag. ARM
+// only accepts one tag condition per filter.
+func tagFilter(tag string) (string, error) {
+  key, value, hasValue := strings.Cut(tag, "=")
+  if key == "" {
+    return "", fmt.Errorf("invalid --tag %q: expected key or key=value", tag)
+  }
+  key = strings.ReplaceAll(key, "'", "''")
+  if !hasValue {
+    return fmt.Sprintf("tagName eq '%s'", key), nil
+  }
+  return fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", key, strings.ReplaceAll(value, "'", "''")), nil
+}
+
+// listTaggedScopes returns the resource groups and resources with the tag,
+// or all of them when tag is empty, groups first so a reader sees the widest
+// locks at the top.
+func listTaggedScopes(ctx context.Context, cmd *cobra.Command, tag string) ([]taggedScope, error) {
+  var filter *string
+  if tag != "" {
+    f, err := tagFilter(tag)
+    if err != nil {
+      return nil, err
+    }
+    filter = &f
+  }
+  sub, err := resolveSubscription(cmd)
+  if err != nil {
+    return nil, err
+  }
+  cred, err := azure.GetCredential()
+  if err != nil {
+    return nil, err
+  }
+  group, _ := cmd.Flags().GetString("resource-group")
+
+  var scopes []taggedScope
+  groups, err := armresources.NewResourceGroupsClient(sub, cred, azure.ARMClientOptions())
+  if err != nil {
+    return nil, fmt.Errorf("failed to create resource groups client: %w", err)
+  }
+  if group != "" {
+    resp, err := groups.Get(ctx, group, nil)
+    if err != nil {
+      return nil, fmt.Errorf("get resource group %s: %w", group, err)
+    }
+    if tag == "" || hasTag(resp.Tags, tag) {
+      scopes = append(scopes, taggedScope{ID: deref(resp.ID), Name: deref(resp.Name), Type: deref(resp.Type), ResourceGroup: group})
+    }
+  } else {
+    p := groups.NewListPager(&armresources.ResourceGroupsClientListOptions{Filter: filter})
+    for p.More() {
+      page, err := p.NextPage(ctx)
+      if err != nil {
+        return nil, fmt.Errorf("list resource groups: %w", err)
+      }
+      for _, g := range page.Value {
+        if g == nil || g.ID == nil {
+          continue
+        }
+        scopes = append(scopes, taggedScope{ID: *g.ID, Name: deref(g.Name), Type: deref(g.Type), ResourceGroup: deref(g.Name)})
+      }
+    }
+  }
+
+  resources, err := armresources.NewClient(sub, cred, azure.ARMClientOptions())
+  if err != nil {
+    return nil, fmt.Errorf("failed to create resources client: %w", err)
+  }
+  add := func(page []*armresources.GenericResourceExpanded) {
+    for _, r := range page {
+      if r == nil || r.ID == nil {
+        continue
+      }
+      scopes = append(scopes, taggedScope{ID: *r.ID, Name: deref(r.Name), Type: deref(r.Type), ResourceGroup: resourceGroupFromID(*r.ID)})
+    }
+  }
+  if group != "" {
+    p := resources.NewListByResourceGroupPager(group, &armresources.ClientListByResourceGroupOptions{Filter: filter})
+    for p.More() {
+      page, err := p.NextPage(ctx)
+      if err != nil {
+        return nil, fmt.Errorf("list resources: %w", err)
+      }
+      add(page.Value)
+    }
+  } else {
+    p := resources.NewListPager(&armresources.ClientListOptions{Filter: filter})
+    for p.More() {
+      page, err := p.NextPage(ctx)
+      if err != nil {
+        return nil, fmt.Errorf("list resources: %w", err)
+      }
+      add(page.Value)
+    }
+  }
+  return scopes, nil
+}
+
+// hasTag matches tag (key or key=value) against a resource group's tags the
+// way the ARM tag filter does: keys case-insensitively, values exactly.
+func hasTag(tags map[string]*string, tag string) bool {
+  key, value, hasValue := strings.Cut(tag, "=")
+  for k, v := range tags {
+    if !strings.EqualFold(k, key) {
+      continue
+    }
+    return !hasValue || (v != nil && *v == value)
+  }
+  return false
+}
+
+func deref(s *string) string {
+  if s == nil {
+    return ""
+  }
+  return *s
+}
+
+// listSubscriptionLocks drains the subscription-wide lock list, which holds
+// the locks at every scope within the subscription.
+func listSubscriptionLocks(ctx context.Context, client *armlocks.ManagementLocksClient) ([]lockRecord, error) {
+  var locks []lockRecord
+  p := client.NewListAtSubscriptionLevelPager(nil)
+  for p.More() {
+    page, err := p.NextPage(ctx)
+    if err != nil {
+      return nil, fmt.Errorf("list locks: %w", err)
+    }
+    for _, o := range page.Value {
+      if o != nil && o.ID != nil {
+        locks = append(locks, toLockRecord(o))
+      }
+    }
+  }
+  return locks, nil
+}
+
+// lockScopeOf strips the lock's own segment from its ID, leaving the scope
+// it was created at.
+func lockScopeOf(lockID string) string {
+  const marker = "/providers/microsoft.authorization/locks/"
+  if i := strings.LastIndex(strings.ToLower(lockID), marker); i >= 0 {
+    return lockID[:i]
+  }
+  return lockID
+}
+
+// locksAt indexes locks by the lower-cased scope they were created at.
+func locksAt(locks []lockRecord) map[string][]lockRecord {
+  index := map[string][]lockRecord{}
+  for _, l := range locks {
+    scope := strings.ToLower(lockScopeOf(l.ID))
+    index[scope] = append(index[scope], l)
+  }
+  return index
+}
+
+// applyAction decides what apply does at a scope holding existing: nothing
+// if a lock there is already at least as strong (an equal lock under
+// another name counts), otherwise create the named lock or update it. A
+// stronger lock of the same name is only weakened when downgrade is set.
+func applyAction(existing []lockRecord, name, level string, notes *string, downgrade bool) string {
+  for _, l := range existing {
+    if l.Name != name {
+      continue
+    }
+    if lockTypeOrder[l.Level] > lockTypeOrder[level] && !downgrade {
+      return "unchanged"
+    }
+    if l.Level == level && (notes == nil || (l.Notes != nil && *l.Notes == *notes)) {
+      return "unchanged"
+    }
+    return "updated"
+  }
+  for _, l := range existing {
+    if lockTypeOrder[l.Level] >= lockTypeOrder[level] {
+      return "unchanged"
+    }
+  }
+  return "created"
+}
+
+func runApply(cmd *cobra.Command) error {
+  ctx := context.Background()
+  tag, _ := cmd.Flags().GetString("tag")
+  name, _ := cmd.Flags().GetString("name")
+  lockType, _ := cmd.Flags().GetString("lock-type")
+  level, err := parseLockLevel(lockType)
+  if err != nil {
+    return err
+  }
+  downgrade, _ := cmd.Flags().GetBool("allow-downgrade")
+  var notes *string
+  if cmd.Flags().Changed("notes") {
+    n, _ := cmd.Flags().GetString("notes")
+    notes = &n
+  }
+
+  scopes, err := listTaggedScopes(ctx, cmd, tag)
+  if err != nil {
+    return err
+  }
+  client, err := newLocksClient(cmd)
+  if err != nil {
+    return err
+  }
+  locks, err := listSubscriptionLocks(ctx, client)
+  if err != nil {
+    return err
+  }
+  index := locksAt(locks)
+
+  params := armlocks.ManagementLockObject{
+    Properties: &armlocks.ManagementLockProperties{Level: &level, Notes: notes},
+  }
+  records := make([]tagLockRecord, 0, len(scopes))
+  failed := 0
+  for _, s := range scopes {
+    action := applyAction(index[strings.ToLower(s.ID)], name, string(level), notes, downgrade)
+    if action != "unchanged" {
+      if _, err := client.CreateOrUpdateByScope(ctx, s.ID, name, params, nil); err != nil {
+        fmt.Fprintf(cmd.ErrOrStderr(), "ERROR: create lock %s on %s: %v\n", name, s.ID, err)
+        action = "failed"
+        failed++
+      }
+    }
+    records = append(records, tagLockRecord{Action: action, Lock: name, Name: s.Name, ResourceGroup: s.ResourceGroup, Scope: s.ID, Type: s.Type})
+  }
+
+  return printTagRecords(cmd, records, failed)
+}
+
+// printTagRecords prints what apply or remove did at every scope, including
+// those changed before one failed, then reports the failures.
+func printTagRecords(cmd *cobra.Command, records []tagLockRecord, failed int) error {
+  format, _ := cmd.Flags().GetString("output")
+  if err := output.PrintFormatted(cmd, records, format); err != nil {
+    return err
+  }
+  if failed > 0 {
+    return fmt.Errorf("%d of %d scopes failed", failed, len(records))
+  }
+  return nil
+}
+
+func runRemove(cmd *cobra.Command) error {
+  ctx := context.Background()
+  tag, _ := cmd.Flags().GetString("tag")
+  name, _ := cmd.Flags().GetString("name")
+  var level string
+  if lockType, _ := cmd.Flags().GetString("lock-type"); lockType != "" {
+    l, err := parseLockLevel(lockType)
+    if err != nil {
+      return err
+    }
+    level = string(l)
+  }
+
+  scopes, err := listTaggedScopes(ctx, cmd, tag)
+  if err != nil {
+    return err
+  }
+  client, err := newLocksClient(cmd)
+  if err != nil {
+    return err
+  }
+  locks, err := listSubscriptionLocks(ctx, client)
+  if err != nil {
+    return err
+  }
+  index := locksAt(locks)
+
+  records := make([]tagLockRecord, 0, len(scopes))
+  failed := 0
+  for _, s := range scopes {
+    action := "absent"
+    for _, l := range index[strings.ToLower(s.ID)] {
+      if l.Name != name || (level != "" && l.Level != level) {
+        continue
+      }
+      action = "deleted"
+      if _, err := client.DeleteByScope(ctx, s.ID, name, nil); err != nil {
+        fmt.Fprintf(cmd.ErrOrStderr(), "ERROR: delete lock %s on %s: %v\n", name, s.ID, err)
+        action = "failed"
+        failed++
+      }
+    }
+    records = append(records, tagLockRecord{Action: action, Lock: name, Name: s.Name, ResourceGroup: s.ResourceGroup, Scope: s.ID, Type: s.Type})
+  }
+
+  return printTagRecords(cmd, records, failed)
+}
@@ -0,0 +1,82 @@
+package lock
+
+import (
+  "testing"
+)
+
+func TestTagFilter(t *testing.T) {
+  tests := []struct {
+    tag     string
+    want    string
+    wantErr bool
+  }{
+    {"env", "tagName eq 'env'", false},
+    {"env=prod", "tagName eq 'env' and tagValue eq 'prod'", false},
+    {"owner=o'brien", "tagName eq 'owner' and tagValue eq 'o''brien'", false},
+    {"env=", "tagName eq 'env' and tagValue eq ''", false},
+    {"=prod", "", true},
+  }
+  for _, tt := range tests {
+    t.Run(tt.tag, func(t *testing.T) {
+      got, err := tagFilter(tt.tag)
+      if (err != nil) != tt.wantErr {
+        t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+      }
+      if got != tt.want {
+        t.Errorf("got %q want %q", got, tt.want)
+      }
+    })
+  }
+}
+
+func TestHasTag(t *testing.T) {
+  tags := map[string]*string{"Env": strPtr("prod")}
+  if !hasTag(tags, "env") || !hasTag(tags, "env=prod") {
+    t.Error("expected match on key and key=value")
+  }
+  if hasTag(tags, "env=Prod") || hasTag(tags, "team") {
+    t.Error("values are case-sensitive and missing keys never match")
+  }
+}
+
+func TestLockScopeOf(t *testing.T) {
+  tests := map[string]string{
+    "/subscriptions/s1/providers/Microsoft.Authorization/locks/l1":                      "/subscriptions/s1",
+    "/subscriptions/s1/resourceGroups/rg1/providers/microsoft.authorization/locks/l1": "/subscriptions/s1/resourceGroups/rg1",
+    "/subscriptions/s1/resourceGroups/rg1/providers/Microsoft.Web/sites/a/providers/Microsoft.Authorization/locks/l1": "/subscriptions/s1/resourceGroups/rg1/providers/Microsoft.Web/sites/a",
+  }
+  for id, want := range tests {
+    if got := lockScopeOf(id); got != want {
+      t.Errorf("lockScopeOf(%q) = %q want %q", id, got, want)
+    }
+  }
+}
+
+func TestApplyAction(t *testing.T) {
+  cnd := lockRecord{Name: "tag-lock", Level: "CanNotDelete"}
+  readOnly := lockRecord{Name: "other", Level: "ReadOnly"}
+  tests := []struct {
+    name      string
+    existing  []lockRecord
+    level     string
+    notes     *string
+    downgrade bool
+    want      string
+  }{
+    {"no locks", nil, "CanNotDelete", nil, false, "created"},
+    {"same lock", []lockRecord{cnd}, "CanNotDelete", nil, false, "unchanged"},
+    {"same lock new notes", []lockRecord{cnd}, "CanNotDelete", strPtr("n"), false, "updated"},
+    {"same name stronger level", []lockRecord{cnd}, "ReadOnly", nil, false, "updated"},
+    {"same name weaker level", []lockRecord{{Name: "tag-lock", Level: "ReadOnly"}}, "CanNotDelete", strPtr("n"), false, "unchanged"},
+    {"same name weaker level with downgrade", []lockRecord{{Name: "tag-lock", Level: "ReadOnly"}}, "CanNotDelete", nil, true, "updated"},
+    {"stronger lock under another name", []lockRecord{readOnly}, "CanNotDelete", nil, false, "unchanged"},
+    {"weaker lock under another name", []lockRecord{{Name: "x", Level: "CanNotDelete"}}, "ReadOnly", nil, false, "created"},
+  }
+  for _, tt := range tests {
+    t.Run(tt.name, func(t *testing.T) {
+      if got := applyAction(tt.existing, "tag-lock", tt.level, tt.notes, tt.downgrade); got != tt.want {
+        t.Errorf("got %q want %q", got, tt.want)
+      }
+    })
+  }
+}
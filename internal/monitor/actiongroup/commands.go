@@ -3,9 +3,12 @@ package actiongroup
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const actionGroupResourceType = "Microsoft.Insights/actionGroups"
+
 func NewActionGroupCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "action-group",
@@ -26,15 +29,10 @@ func NewActionGroupCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of an action group",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			name, _ := cmd.Flags().GetString("name")
-			return Show(context.Background(), cmd, resourceGroup, name)
+			return bulk.Run(cmd, actionGroupResourceType, Show)
 		},
 	}
-	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.Flags().StringP("name", "n", "", "Action group name")
-	showCmd.MarkFlagRequired("resource-group")
-	showCmd.MarkFlagRequired("name")
+	addActionGroupTargetFlags(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -63,15 +61,10 @@ func NewActionGroupCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete an action group",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			name, _ := cmd.Flags().GetString("name")
-			return Delete(context.Background(), cmd, resourceGroup, name)
+			return bulk.Run(cmd, actionGroupResourceType, Delete)
 		},
 	}
-	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	deleteCmd.Flags().StringP("name", "n", "", "Action group name")
-	deleteCmd.MarkFlagRequired("resource-group")
-	deleteCmd.MarkFlagRequired("name")
+	addActionGroupTargetFlags(deleteCmd)
 
 	enableReceiverCmd := &cobra.Command{
 		Use:   "enable-receiver",
@@ -94,3 +87,11 @@ func NewActionGroupCommand() *cobra.Command {
 
 	return cmd
 }
+
+// addActionGroupTargetFlags adds -g/-n and --ids to a command that acts on
+// one or more existing action groups.
+func addActionGroupTargetFlags(c *cobra.Command) {
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	c.Flags().StringP("name", "n", "", "Action group name")
+	bulk.AddIDsFlag(c)
+}
@@ -6,26 +6,20 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, name string) error {
+func Delete(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armmonitor.NewActionGroupsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create action group client: %w", err)
 	}
-	client, err := armmonitor.NewActionGroupsClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create action group client: %w", err)
-	}
-	if _, err := client.Delete(ctx, resourceGroup, name, nil); err != nil {
-		return fmt.Errorf("failed to delete action group: %w", err)
+	if _, err := client.Delete(ctx, t.ResourceGroup, t.Name, nil); err != nil {
+		return nil, fmt.Errorf("failed to delete action group: %w", err)
 	}
-	return output.PrintJSON(cmd, map[string]string{"status": fmt.Sprintf("'%s' deleted.", name)})
+	return map[string]string{"status": fmt.Sprintf("'%s' deleted.", t.Name)}, nil
 }
@@ -21,7 +21,7 @@ func EnableReceiver(ctx context.Context, cmd *cobra.Command, resourceGroup, name
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewActionGroupsClient(subscriptionID, cred, nil)
+	client, err := armmonitor.NewActionGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create action group client: %w", err)
 	}
@@ -20,7 +20,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewActionGroupsClient(subscriptionID, cred, nil)
+	client, err := armmonitor.NewActionGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create action group client: %w", err)
 	}
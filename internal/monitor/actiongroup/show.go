@@ -6,27 +6,21 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, resourceGroup, name string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armmonitor.NewActionGroupsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create action group client: %w", err)
 	}
-	client, err := armmonitor.NewActionGroupsClient(subscriptionID, cred, nil)
+	resp, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create action group client: %w", err)
+		return nil, fmt.Errorf("failed to get action group: %w", err)
 	}
-	resp, err := client.Get(ctx, resourceGroup, name, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get action group: %w", err)
-	}
-	return output.PrintJSON(cmd, resp.ActionGroupResource)
+	return resp.ActionGroupResource, nil
 }
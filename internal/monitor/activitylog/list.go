@@ -21,7 +21,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, resourceID, co
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewActivityLogsClient(subscriptionID, cred, nil)
+	client, err := armmonitor.NewActivityLogsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create activity logs client: %w", err)
 	}
@@ -41,14 +41,16 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, resourceID, co
 	}
 	filter := strings.Join(parts, " and ")
 
-	var events []*armmonitor.EventData
+	stream := output.NewStream(cmd)
 	pager := client.NewListPager(filter, nil)
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to list activity logs: %w", err)
+			return stream.Abort(fmt.Errorf("failed to list activity logs: %w", err))
+		}
+		if err := output.StreamPage(stream, page.Value); err != nil {
+			return err
 		}
-		events = append(events, page.Value...)
 	}
-	return output.PrintJSON(cmd, events)
+	return stream.Close()
 }
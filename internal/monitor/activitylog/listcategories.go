@@ -15,7 +15,7 @@ func ListCategories(ctx context.Context, cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewEventCategoriesClient(cred, nil)
+	client, err := armmonitor.NewEventCategoriesClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create event categories client: %w", err)
 	}
@@ -20,7 +20,7 @@ func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, name string)
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewAutoscaleSettingsClient(subscriptionID, cred, nil)
+	client, err := armmonitor.NewAutoscaleSettingsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create autoscale settings client: %w", err)
 	}
@@ -15,7 +15,7 @@ func CategoriesList(ctx context.Context, cmd *cobra.Command, resource string) er
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewDiagnosticSettingsCategoryClient(cred, nil)
+	client, err := armmonitor.NewDiagnosticSettingsCategoryClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create diagnostic settings category client: %w", err)
 	}
@@ -37,7 +37,7 @@ func CategoriesShow(ctx context.Context, cmd *cobra.Command, resource, name stri
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewDiagnosticSettingsCategoryClient(cred, nil)
+	client, err := armmonitor.NewDiagnosticSettingsCategoryClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create diagnostic settings category client: %w", err)
 	}
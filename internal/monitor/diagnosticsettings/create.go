@@ -17,7 +17,7 @@ func Create(ctx context.Context, cmd *cobra.Command, resource, name, workspace,
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewDiagnosticSettingsClient(cred, nil)
+	client, err := armmonitor.NewDiagnosticSettingsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create diagnostic settings client: %w", err)
 	}
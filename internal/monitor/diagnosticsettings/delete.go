@@ -15,7 +15,7 @@ func Delete(ctx context.Context, cmd *cobra.Command, resource, name string) erro
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewDiagnosticSettingsClient(cred, nil)
+	client, err := armmonitor.NewDiagnosticSettingsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create diagnostic settings client: %w", err)
 	}
@@ -15,7 +15,7 @@ func List(ctx context.Context, cmd *cobra.Command, resource string) error {
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewDiagnosticSettingsClient(cred, nil)
+	client, err := armmonitor.NewDiagnosticSettingsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create diagnostic settings client: %w", err)
 	}
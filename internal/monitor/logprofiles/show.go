@@ -20,7 +20,7 @@ func Show(ctx context.Context, cmd *cobra.Command, name string) error {
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewLogProfilesClient(subscriptionID, cred, nil)
+	client, err := armmonitor.NewLogProfilesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create log profiles client: %w", err)
 	}
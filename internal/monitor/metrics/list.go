@@ -21,7 +21,7 @@ func List(ctx context.Context, cmd *cobra.Command, resource, metricNames, aggreg
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewMetricsClient(subscriptionID, cred, nil)
+	client, err := armmonitor.NewMetricsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create metrics client: %w", err)
 	}
@@ -20,7 +20,7 @@ func ListDefinitions(ctx context.Context, cmd *cobra.Command, resource string) e
 	if err != nil {
 		return err
 	}
-	client, err := armmonitor.NewMetricDefinitionsClient(subscriptionID, cred, nil)
+	client, err := armmonitor.NewMetricDefinitionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create metric definitions client: %w", err)
 	}
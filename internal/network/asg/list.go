@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewApplicationSecurityGroupsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewApplicationSecurityGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create ASG client: %w", err)
 	}
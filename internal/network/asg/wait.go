@@ -40,7 +40,7 @@ func Wait(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, d
 	if err != nil {
 		return err
 	}
-	client, err := armnetwork.NewApplicationSecurityGroupsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewApplicationSecurityGroupsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create ASG client: %w", err)
 	}
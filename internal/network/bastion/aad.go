@@ -121,7 +121,7 @@ Then try again. The SSH certificate request requires access to your cached Azure
 	azureCliClientID := "04b07795-8ddb-461a-bbee-02f9e1bf7b46"
 
 	client, err := public.New(azureCliClientID,
-		public.WithAuthority("https://login.microsoftonline.com/common"),
+		public.WithAuthority(azure.Authority("common")),
 		public.WithCache(&fileCache{path: cacheFile}))
 	if err != nil {
 		return "", fmt.Errorf("failed to create MSAL client: %w", err)
@@ -499,7 +499,7 @@ func requestSSHCertViaTokenEndpoint(ctx context.Context, cacheFile string, accou
 	logger.Debug("Successfully extracted refresh token from cache")
 
 	// Token endpoint
-	tokenEndpoint := "https://login.microsoftonline.com/common/oauth2/v2.0/token"
+	tokenEndpoint := azure.Authority("common") + "/oauth2/v2.0/token"
 
 	// Build form data with SSH certificate parameters
 	formData := url.Values{}
@@ -75,7 +75,7 @@ func SSH(ctx context.Context, bastionName, resourceGroup, targetResourceID, auth
 		}
 
 		// Get AAD SSH certificate
-		certData, err := GetAADSSHCertificate(ctx, cred, keyPair, "azurecloud")
+		certData, err := GetAADSSHCertificate(ctx, cred, keyPair, strings.ToLower(azure.ActiveCloud().Name))
 		if err != nil {
 			cancelTunnel()
 			return fmt.Errorf("failed to get AAD certificate: %w", err)
@@ -75,7 +75,7 @@ func tunnelWithProtocol(ctx context.Context, bastionName, resourceGroup, targetR
 
 	// Get Bastion details
 	logger.Debug("Creating Bastion client for resource group: %s", resourceGroup)
-	client, err := armnetwork.NewBastionHostsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewBastionHostsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create bastion client: %w", err)
 	}
@@ -230,7 +230,7 @@ func tunnelWithProtocol(ctx context.Context, bastionName, resourceGroup, targetR
 func getAccessToken(ctx context.Context, cred azcore.TokenCredential) (string, error) {
 	// Get token for Azure Resource Manager
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://management.azure.com/.default"},
+		Scopes: []string{azure.ARMScope()},
 	})
 	if err != nil {
 		return "", err
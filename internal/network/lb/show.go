@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, lbName, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewLoadBalancersClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create load balancers client: %w", err)
 	}
 
-	client, err := armnetwork.NewLoadBalancersClient(subscriptionID, cred, nil)
+	lb, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create load balancers client: %w", err)
+		return nil, fmt.Errorf("failed to get load balancer: %w", err)
 	}
 
-	lb, err := client.Get(ctx, resourceGroup, lbName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get load balancer: %w", err)
-	}
-
-	return output.PrintJSON(cmd, lb)
+	return lb, nil
 }
@@ -3,9 +3,13 @@ package localgateway
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Network/localNetworkGateways"
+
 func NewLocalGatewayCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "local-gateway",
@@ -28,15 +32,12 @@ func NewLocalGatewayCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a local network gateway",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, name, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Local network gateway name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -65,36 +66,35 @@ func NewLocalGatewayCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a local network gateway",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Local network gateway name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update a local network gateway (gateway IP, local address prefixes, tags)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Update(context.Background(), cmd, name, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, noWait)
+			})
 		},
 	}
 	updateCmd.Flags().StringP("name", "n", "", "Local network gateway name")
 	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(updateCmd)
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.Flags().String("gateway-ip-address", "", "IP address of the local network gateway")
 	updateCmd.Flags().String("local-address-prefixes", "", "Comma-separated CIDR address prefixes (e.g., 10.0.0.0/24,10.1.0.0/24)")
 	updateCmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
 	updateCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	waitCmd := &cobra.Command{
 		Use:   "wait",
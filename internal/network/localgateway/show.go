@@ -6,31 +6,24 @@ import (
 
 	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, name, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewLocalNetworkGatewaysClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return nil, fmt.Errorf("failed to create local network gateway client: %w", err)
 	}
 
-	client, err := armnetwork.NewLocalNetworkGatewaysClient(subscriptionID, cred, nil)
+	result, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create local network gateway client: %w", err)
+		return nil, fmt.Errorf("failed to get local network gateway: %w", err)
 	}
 
-	result, err := client.Get(ctx, resourceGroup, name, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get local network gateway: %w", err)
-	}
-
-	return output.PrintJSON(cmd, result.LocalNetworkGateway)
+	return result.LocalNetworkGateway, nil
 }
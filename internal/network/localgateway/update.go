@@ -8,30 +8,25 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
-func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, noWait bool) error {
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, noWait bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewLocalNetworkGatewaysClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return nil, fmt.Errorf("failed to create local network gateway client: %w", err)
 	}
 
-	client, err := armnetwork.NewLocalNetworkGatewaysClient(subscriptionID, cred, nil)
+	current, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create local network gateway client: %w", err)
-	}
-
-	current, err := client.Get(ctx, resourceGroup, name, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get local network gateway: %w", err)
+		return nil, fmt.Errorf("failed to get local network gateway: %w", err)
 	}
 
 	if current.Properties == nil {
@@ -68,23 +63,27 @@ func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string,
 		current.Tags = azureTags
 	}
 
-	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, name, current.LocalNetworkGateway, nil)
+	if err := genericupdate.ApplyFlags(cmd, &current.LocalNetworkGateway); err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginCreateOrUpdate(ctx, t.ResourceGroup, t.Name, current.LocalNetworkGateway, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin update local network gateway: %w", err)
+		return nil, fmt.Errorf("failed to begin update local network gateway: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started update of local network gateway '%s'\n", name)
-		return nil
+		fmt.Printf("Started update of local network gateway '%s'\n", t.Name)
+		return nil, nil
 	}
 
-	fmt.Printf("Updating local network gateway '%s'...\n", name)
+	fmt.Printf("Updating local network gateway '%s'...\n", t.Name)
 	result, err := poller.PollUntilDone(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update local network gateway: %w", err)
+		return nil, fmt.Errorf("failed to update local network gateway: %w", err)
 	}
 
-	return output.PrintJSON(cmd, result.LocalNetworkGateway)
+	return result.LocalNetworkGateway, nil
 }
 
 func splitCSV(s string) []string {
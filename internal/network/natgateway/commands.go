@@ -3,9 +3,13 @@ package natgateway
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Network/natGateways"
+
 func NewNatGatewayCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "nat",
@@ -27,15 +31,12 @@ func NewNatGatewayCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a NAT gateway",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gatewayName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, gatewayName, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "NAT gateway name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -62,35 +63,34 @@ func NewNatGatewayCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a NAT gateway",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "NAT gateway name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update a NAT gateway",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Update(context.Background(), cmd, name, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, noWait)
+			})
 		},
 	}
 	updateCmd.Flags().StringP("name", "n", "", "NAT gateway name")
 	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(updateCmd)
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.Flags().Int32("idle-timeout", 4, "Idle timeout in minutes (4-120)")
 	updateCmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
 	updateCmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	waitCmd := &cobra.Command{
 		Use:   "wait",
@@ -23,7 +23,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return err
 	}
 
-	client, err := armnetwork.NewNatGatewaysClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewNatGatewaysClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create nat gateways client: %w", err)
 	}
@@ -3,9 +3,13 @@ package nic
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Network/networkInterfaces"
+
 func NewNicCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "nic",
@@ -27,15 +31,12 @@ func NewNicCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a network interface",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, name, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Network interface name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -65,37 +66,36 @@ func NewNicCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a network interface",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Network interface name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update a network interface",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			dnsServers, _ := cmd.Flags().GetString("dns-servers")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Update(context.Background(), cmd, name, resourceGroup, splitCSV(dnsServers), noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, splitCSV(dnsServers), noWait)
+			})
 		},
 	}
 	updateCmd.Flags().StringP("name", "n", "", "Network interface name")
 	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(updateCmd)
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.Flags().Bool("ip-forwarding", false, "Enable IP forwarding on the network interface")
 	updateCmd.Flags().String("dns-servers", "", "Comma-separated DNS server IP addresses")
 	updateCmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
 	updateCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	waitCmd := &cobra.Command{
 		Use:   "wait",
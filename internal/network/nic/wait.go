@@ -40,7 +40,7 @@ func Wait(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, d
 	if err != nil {
 		return err
 	}
-	client, err := armnetwork.NewInterfacesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewInterfacesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create NIC client: %w", err)
 	}
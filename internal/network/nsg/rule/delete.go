@@ -20,7 +20,7 @@ func Delete(ctx context.Context, name, nsgName, resourceGroup string, noWait boo
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewSecurityRulesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewSecurityRulesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create security rules client: %w", err)
 	}
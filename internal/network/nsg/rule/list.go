@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, nsgName, resourceGroup string
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewSecurityRulesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewSecurityRulesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create security rules client: %w", err)
 	}
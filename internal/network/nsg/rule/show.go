@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, name, nsgName, resourceGroup
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewSecurityRulesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewSecurityRulesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create security rules client: %w", err)
 	}
@@ -7,30 +7,25 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
-func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, noWait bool) error {
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, noWait bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewSecurityGroupsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return nil, fmt.Errorf("failed to create NSG client: %w", err)
 	}
 
-	client, err := armnetwork.NewSecurityGroupsClient(subscriptionID, cred, nil)
+	current, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create NSG client: %w", err)
-	}
-
-	current, err := client.Get(ctx, resourceGroup, name, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get NSG: %w", err)
+		return nil, fmt.Errorf("failed to get NSG: %w", err)
 	}
 
 	flags := cmd.Flags()
@@ -44,21 +39,25 @@ func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string,
 		current.SecurityGroup.Tags = azureTags
 	}
 
-	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, name, current.SecurityGroup, nil)
+	if err := genericupdate.ApplyFlags(cmd, &current.SecurityGroup); err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginCreateOrUpdate(ctx, t.ResourceGroup, t.Name, current.SecurityGroup, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin update NSG: %w", err)
+		return nil, fmt.Errorf("failed to begin update NSG: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started update of network security group '%s'\n", name)
-		return nil
+		fmt.Printf("Started update of network security group '%s'\n", t.Name)
+		return nil, nil
 	}
 
-	fmt.Printf("Updating network security group '%s'...\n", name)
+	fmt.Printf("Updating network security group '%s'...\n", t.Name)
 	result, err := poller.PollUntilDone(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update NSG: %w", err)
+		return nil, fmt.Errorf("failed to update NSG: %w", err)
 	}
 
-	return output.PrintJSON(cmd, result.SecurityGroup)
+	return result.SecurityGroup, nil
 }
@@ -23,7 +23,7 @@ func Create(ctx context.Context, cmd *cobra.Command, name, vnetName, resourceGro
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewVirtualNetworkPeeringsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewVirtualNetworkPeeringsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual network peerings client: %w", err)
 	}
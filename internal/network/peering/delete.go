@@ -20,7 +20,7 @@ func Delete(ctx context.Context, name, vnetName, resourceGroup string, noWait bo
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewVirtualNetworkPeeringsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewVirtualNetworkPeeringsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual network peerings client: %w", err)
 	}
@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, vnetName, peeringName, resour
 		return err
 	}
 
-	client, err := armnetwork.NewVirtualNetworkPeeringsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewVirtualNetworkPeeringsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual network peerings client: %w", err)
 	}
@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, endpointName, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewPrivateEndpointsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create private endpoints client: %w", err)
 	}
 
-	client, err := armnetwork.NewPrivateEndpointsClient(subscriptionID, cred, nil)
+	endpoint, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create private endpoints client: %w", err)
+		return nil, fmt.Errorf("failed to get private endpoint: %w", err)
 	}
 
-	endpoint, err := client.Get(ctx, resourceGroup, endpointName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get private endpoint: %w", err)
-	}
-
-	return output.PrintJSON(cmd, endpoint)
+	return endpoint, nil
 }
@@ -24,7 +24,7 @@ func Create(ctx context.Context, cmd *cobra.Command, name, resourceGroup, locati
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewPublicIPPrefixesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewPublicIPPrefixesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create public IP prefix client: %w", err)
 	}
@@ -7,30 +7,25 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
-func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, noWait bool) error {
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, noWait bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewPublicIPPrefixesClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return nil, fmt.Errorf("failed to create public IP prefix client: %w", err)
 	}
 
-	client, err := armnetwork.NewPublicIPPrefixesClient(subscriptionID, cred, nil)
+	current, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create public IP prefix client: %w", err)
-	}
-
-	current, err := client.Get(ctx, resourceGroup, name, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get public IP prefix: %w", err)
+		return nil, fmt.Errorf("failed to get public IP prefix: %w", err)
 	}
 
 	flags := cmd.Flags()
@@ -44,21 +39,25 @@ func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string,
 		current.Tags = azureTags
 	}
 
-	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, name, current.PublicIPPrefix, nil)
+	if err := genericupdate.ApplyFlags(cmd, &current.PublicIPPrefix); err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginCreateOrUpdate(ctx, t.ResourceGroup, t.Name, current.PublicIPPrefix, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin update public IP prefix: %w", err)
+		return nil, fmt.Errorf("failed to begin update public IP prefix: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started update of public IP prefix '%s'\n", name)
-		return nil
+		fmt.Printf("Started update of public IP prefix '%s'\n", t.Name)
+		return nil, nil
 	}
 
-	fmt.Printf("Updating public IP prefix '%s'...\n", name)
+	fmt.Printf("Updating public IP prefix '%s'...\n", t.Name)
 	result, err := poller.PollUntilDone(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update public IP prefix: %w", err)
+		return nil, fmt.Errorf("failed to update public IP prefix: %w", err)
 	}
 
-	return output.PrintJSON(cmd, result.PublicIPPrefix)
+	return result.PublicIPPrefix, nil
 }
@@ -6,40 +6,35 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, name, resourceGroup string, noWait bool) error {
+func Delete(ctx context.Context, t bulk.Target, noWait bool) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armnetwork.NewRouteTablesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewRouteTablesClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create route tables client: %w", err)
 	}
 
-	poller, err := client.BeginDelete(ctx, resourceGroup, name, nil)
+	poller, err := client.BeginDelete(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin delete route table: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started deletion of route table '%s'\n", name)
+		fmt.Printf("Started deletion of route table '%s'\n", t.Name)
 		return nil
 	}
 
-	fmt.Printf("Deleting route table '%s'...\n", name)
+	fmt.Printf("Deleting route table '%s'...\n", t.Name)
 	if _, err = poller.PollUntilDone(ctx, nil); err != nil {
 		return fmt.Errorf("failed to delete route table: %w", err)
 	}
 
-	fmt.Printf("Deleted route table '%s'\n", name)
+	fmt.Printf("Deleted route table '%s'\n", t.Name)
 	return nil
 }
@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return err
 	}
 
-	client, err := armnetwork.NewRouteTablesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewRouteTablesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create route tables client: %w", err)
 	}
@@ -23,7 +23,7 @@ func Create(ctx context.Context, cmd *cobra.Command, name, routeTableName, resou
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create routes client: %w", err)
 	}
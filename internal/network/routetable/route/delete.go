@@ -20,7 +20,7 @@ func Delete(ctx context.Context, name, routeTableName, resourceGroup string, noW
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create routes client: %w", err)
 	}
@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, routeTableName, resourceGroup
 		return err
 	}
 
-	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create routes client: %w", err)
 	}
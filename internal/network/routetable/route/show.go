@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, name, routeTableName, resourc
 		return err
 	}
 
-	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewRoutesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create routes client: %w", err)
 	}
@@ -3,6 +3,7 @@ package subnet
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
@@ -103,6 +104,7 @@ func NewSubnetCommand() *cobra.Command {
 	updateCmd.Flags().String("service-endpoints", "", "Comma-separated service endpoints (e.g., Microsoft.Storage,Microsoft.KeyVault); empty string clears")
 	updateCmd.Flags().String("delegations", "", "Comma-separated service delegations (e.g., Microsoft.ContainerInstance/containerGroups); empty string clears")
 	updateCmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.MarkFlagRequired("name")
 	updateCmd.MarkFlagRequired("vnet-name")
 	updateCmd.MarkFlagRequired("resource-group")
@@ -20,7 +20,7 @@ func Delete(ctx context.Context, name, resourceGroup, vnetName string, noWait bo
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewSubnetsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewSubnetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create subnets client: %w", err)
 	}
@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, vnetName, resourceGroup strin
 		return err
 	}
 
-	client, err := armnetwork.NewSubnetsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewSubnetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create subnets client: %w", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
 	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/cdobbyn/azure-go-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -24,7 +25,7 @@ func Update(ctx context.Context, cmd *cobra.Command, name, vnetName, resourceGro
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armnetwork.NewSubnetsClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewSubnetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create subnets client: %w", err)
 	}
@@ -109,6 +110,10 @@ func Update(ctx context.Context, cmd *cobra.Command, name, vnetName, resourceGro
 		}
 	}
 
+	if err := genericupdate.ApplyFlags(cmd, &current.Subnet); err != nil {
+		return err
+	}
+
 	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, vnetName, name, current.Subnet, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin update subnet: %w", err)
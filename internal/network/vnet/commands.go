@@ -4,9 +4,13 @@ import (
 	"context"
 
 	"github.com/cdobbyn/azure-go-cli/internal/network/subnet"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Network/virtualNetworks"
+
 func NewVNetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "vnet",
@@ -28,15 +32,12 @@ func NewVNetCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a virtual network",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vnetName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, vnetName, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Virtual network name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -64,37 +65,36 @@ func NewVNetCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a virtual network",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vnetName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), vnetName, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Virtual network name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update a virtual network",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			addressPrefixesStr, _ := cmd.Flags().GetString("address-prefixes")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
 			addressPrefixes := ParseAddressPrefixes(addressPrefixesStr)
-			return Update(context.Background(), cmd, name, resourceGroup, addressPrefixes, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, addressPrefixes, noWait)
+			})
 		},
 	}
 	updateCmd.Flags().StringP("name", "n", "", "Virtual network name")
 	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(updateCmd)
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.Flags().String("address-prefixes", "", "Comma-separated list of IP address prefixes (e.g., 10.0.0.0/16,10.1.0.0/16)")
 	updateCmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
 	updateCmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	waitCmd := &cobra.Command{
 		Use:   "wait",
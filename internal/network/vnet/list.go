@@ -23,12 +23,12 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return err
 	}
 
-	client, err := armnetwork.NewVirtualNetworksClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewVirtualNetworksClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual networks client: %w", err)
 	}
 
-	var vnets []map[string]interface{}
+	stream := output.NewStream(cmd)
 
 	if resourceGroup != "" {
 		// List VNets in specific resource group
@@ -36,11 +36,15 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list virtual networks: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list virtual networks: %w", err))
 			}
 
+			items := make([]map[string]interface{}, 0, len(page.Value))
 			for _, vnet := range page.Value {
-				vnets = append(vnets, formatVNet(vnet))
+				items = append(items, formatVNet(vnet))
+			}
+			if err := output.StreamPage(stream, items); err != nil {
+				return err
 			}
 		}
 	} else {
@@ -49,16 +53,20 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list virtual networks: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list virtual networks: %w", err))
 			}
 
+			items := make([]map[string]interface{}, 0, len(page.Value))
 			for _, vnet := range page.Value {
-				vnets = append(vnets, formatVNet(vnet))
+				items = append(items, formatVNet(vnet))
+			}
+			if err := output.StreamPage(stream, items); err != nil {
+				return err
 			}
 		}
 	}
 
-	return output.PrintJSON(cmd, vnets)
+	return stream.Close()
 }
 
 func formatVNet(vnet *armnetwork.VirtualNetwork) map[string]interface{} {
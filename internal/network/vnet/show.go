@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, vnetName, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewVirtualNetworksClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create virtual networks client: %w", err)
 	}
 
-	client, err := armnetwork.NewVirtualNetworksClient(subscriptionID, cred, nil)
+	vnet, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create virtual networks client: %w", err)
+		return nil, fmt.Errorf("failed to get virtual network: %w", err)
 	}
 
-	vnet, err := client.Get(ctx, resourceGroup, vnetName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get virtual network: %w", err)
-	}
-
-	return output.PrintJSON(cmd, vnet)
+	return vnet, nil
 }
@@ -7,30 +7,25 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
-func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, addressPrefixes []string, noWait bool) error {
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, addressPrefixes []string, noWait bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewVirtualNetworksClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return nil, fmt.Errorf("failed to create virtual networks client: %w", err)
 	}
 
-	client, err := armnetwork.NewVirtualNetworksClient(subscriptionID, cred, nil)
+	current, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create virtual networks client: %w", err)
-	}
-
-	current, err := client.Get(ctx, resourceGroup, name, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get virtual network: %w", err)
+		return nil, fmt.Errorf("failed to get virtual network: %w", err)
 	}
 
 	flags := cmd.Flags()
@@ -58,21 +53,25 @@ func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string,
 		current.Tags = azureTags
 	}
 
-	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroup, name, current.VirtualNetwork, nil)
+	if err := genericupdate.ApplyFlags(cmd, &current.VirtualNetwork); err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginCreateOrUpdate(ctx, t.ResourceGroup, t.Name, current.VirtualNetwork, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin update virtual network: %w", err)
+		return nil, fmt.Errorf("failed to begin update virtual network: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started update of virtual network '%s'\n", name)
-		return nil
+		fmt.Printf("Started update of virtual network '%s'\n", t.Name)
+		return nil, nil
 	}
 
-	fmt.Printf("Updating virtual network '%s'...\n", name)
+	fmt.Printf("Updating virtual network '%s'...\n", t.Name)
 	result, err := poller.PollUntilDone(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update virtual network: %w", err)
+		return nil, fmt.Errorf("failed to update virtual network: %w", err)
 	}
 
-	return output.PrintJSON(cmd, result.VirtualNetwork)
+	return result.VirtualNetwork, nil
 }
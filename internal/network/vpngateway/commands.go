@@ -3,9 +3,12 @@ package vpngateway
 import (
 	"context"
 
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Network/virtualNetworkGateways"
+
 func NewVpnGatewayCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "vnet-gateway",
@@ -28,15 +31,12 @@ func NewVpnGatewayCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a virtual network gateway",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gatewayName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, gatewayName, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Gateway name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -74,17 +74,16 @@ func NewVpnGatewayCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a virtual network gateway",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Gateway name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the long-running operation to finish")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	cmd.AddCommand(listCmd, showCmd, createCmd, deleteCmd)
 	return cmd
@@ -23,7 +23,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return err
 	}
 
-	client, err := armnetwork.NewVirtualNetworkGatewaysClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewVirtualNetworkGatewaysClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual network gateways client: %w", err)
 	}
@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, gatewayName, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armnetwork.NewVirtualNetworkGatewaysClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create virtual network gateways client: %w", err)
 	}
 
-	client, err := armnetwork.NewVirtualNetworkGatewaysClient(subscriptionID, cred, nil)
+	gateway, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create virtual network gateways client: %w", err)
+		return nil, fmt.Errorf("failed to get virtual network gateway: %w", err)
 	}
 
-	gateway, err := client.Get(ctx, resourceGroup, gatewayName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get virtual network gateway: %w", err)
-	}
-
-	return output.PrintJSON(cmd, gateway)
+	return gateway, nil
 }
@@ -6,9 +6,6 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-
-	pimvendor "github.com/cdobbyn/azure-go-cli/internal/pim/vendor"
-	"github.com/cdobbyn/azure-go-cli/pkg/azure"
 )
 
 type activateGroupArgs struct {
@@ -98,71 +95,5 @@ func runActivateGroup(cmd *cobra.Command, a activateGroupArgs) error {
 		}
 	}
 
-	cred, err := azure.GetCredential()
-	if err != nil {
-		return fmt.Errorf("get credential: %w", err)
-	}
-	ts := NewTokenSource(cred)
-	graphToken, err := ts.GetAccessToken("https://graph.microsoft.com/.default")
-	if err != nil {
-		return err
-	}
-
-	client := pimvendor.AzureClient{ARMBaseURL: pimvendor.ARM_GLOBAL_BASE_URL}
-	info, err := pimvendor.GetUserInfo(graphToken)
-	if err != nil {
-		return err
-	}
-
-	eligible, err := client.GetEligibleGovernanceRoleAssignments(pimvendor.ROLE_TYPE_AAD_GROUPS, info.ObjectId, graphToken)
-	if err != nil {
-		return err
-	}
-
-	matches := []pimvendor.GovernanceRoleAssignment{}
-	for _, e := range eligible.Value {
-		if e.RoleDefinition != nil && e.RoleDefinition.DisplayName == a.Name {
-			matches = append(matches, e)
-		}
-	}
-	switch len(matches) {
-	case 0:
-		return fmt.Errorf("no eligible group named %q", a.Name)
-	case 1:
-		// proceed
-	default:
-		var ids []string
-		for _, m := range matches {
-			if m.RoleDefinition != nil && m.RoleDefinition.Resource != nil {
-				ids = append(ids, m.RoleDefinition.Resource.Id)
-			}
-		}
-		return fmt.Errorf("ambiguous group name %q; candidates by id: %s", a.Name, strings.Join(ids, ", "))
-	}
-
-	roleType, req, err := pimvendor.CreateGovernanceRoleAssignmentRequest(
-		info.ObjectId, pimvendor.ROLE_TYPE_AAD_GROUPS, &matches[0],
-		a.Duration, "", "", a.Justification, "", "")
-	if err != nil {
-		return err
-	}
-
-	ok, err := client.ValidateGovernanceRoleAssignmentRequest(roleType, req, graphToken)
-	if err != nil {
-		return err
-	}
-	if !ok {
-		return fmt.Errorf("Azure rejected the group activation request during validation; check group name and duration")
-	}
-
-	resp, err := client.RequestGovernanceRoleAssignment(roleType, req, graphToken)
-	if err != nil {
-		return err
-	}
-
-	subStatus := ""
-	if resp.Status != nil {
-		subStatus = resp.Status.SubStatus
-	}
-	return renderActivationResult(cmd, subStatus, "—", a.Name, resp.RoleAssignmentEndDateTime, resp.Id)
+	return activateGovernance(cmd, "group", a.Name, a.Justification, "", a.Duration)
 }
@@ -99,34 +99,60 @@ func runActivateResource(cmd *cobra.Command, a activateResourceArgs) error {
 	if err != nil {
 		return fmt.Errorf("get credential: %w", err)
 	}
-	ts := NewTokenSource(cred)
-	token, err := ts.GetAccessToken("https://management.azure.com/.default")
+	result, err := submitResourceActivation(NewTokenSource(cred), a)
 	if err != nil {
 		return err
 	}
 
-	client := pimvendor.AzureClient{ARMBaseURL: pimvendor.ARM_GLOBAL_BASE_URL}
+	if a.SetSubscription {
+		if err := setDefaultSubscriptionFor(result.Scope); err != nil {
+			return err
+		}
+	}
+
+	return renderActivationResult(cmd, result.Status, result.Scope, a.Role, "", result.RequestID)
+}
+
+// activationResult is the outcome of one submitted activation request.
+type activationResult struct {
+	Type      string `json:"type"`
+	Role      string `json:"role"`
+	Scope     string `json:"scope"`
+	Status    string `json:"status"`
+	ExpiresAt string `json:"expiresAt"`
+	RequestID string `json:"requestId"`
+}
+
+// submitResourceActivation resolves a's scope against the caller's eligible
+// resource roles, validates the request and submits it.
+func submitResourceActivation(ts *TokenSource, a activateResourceArgs) (activationResult, error) {
+	token, err := ts.GetAccessToken(azure.ARMScope())
+	if err != nil {
+		return activationResult{}, err
+	}
+
+	client := pimvendor.AzureClient{ARMBaseURL: azure.ARMEndpoint()}
 
 	eligible, err := client.GetEligibleResourceAssignments(token)
 	if err != nil {
-		return err
+		return activationResult{}, err
 	}
 
 	profile, _ := config.Load()
 	index := buildScopeIndex(eligible, profile)
 	scope, err := resolveResourceScope(a, eligible, index)
 	if err != nil {
-		return err
+		return activationResult{}, err
 	}
 
 	assignment, err := findAssignment(eligible, a.Role, scope)
 	if err != nil {
-		return err
+		return activationResult{}, err
 	}
 
 	info, err := pimvendor.GetUserInfo(token)
 	if err != nil {
-		return err
+		return activationResult{}, err
 	}
 
 	system, number := ParseTicket(a.Ticket)
@@ -134,7 +160,7 @@ func runActivateResource(cmd *cobra.Command, a activateResourceArgs) error {
 	// vendor's URL builder expects (sprintf inserts its own slash before it).
 	vendorScope, req, err := pimvendor.CreateResourceAssignmentRequest(info.ObjectId, &assignment, a.Duration, "", "", a.Justification, system, number)
 	if err != nil {
-		return err
+		return activationResult{}, err
 	}
 
 	// Validate first. The vendor's validate path mutates
@@ -144,34 +170,36 @@ func runActivateResource(cmd *cobra.Command, a activateResourceArgs) error {
 	validationReq := *req
 	ok, err := client.ValidateResourceAssignmentRequest(vendorScope, &validationReq, token)
 	if err != nil {
-		return err
+		return activationResult{}, err
 	}
 	if !ok {
-		return fmt.Errorf("Azure rejected the activation request during validation; check role, scope, ticket, and duration")
+		return activationResult{}, fmt.Errorf("Azure rejected the activation request during validation; check role, scope, ticket, and duration")
 	}
 
 	resp, err := client.RequestResourceAssignment(vendorScope, req, token)
 	if err != nil {
-		return err
+		return activationResult{}, err
 	}
 
-	if a.SetSubscription {
-		subID := extractSubscriptionID(scope)
-		if subID != "" && profile != nil {
-			if err := SetDefaultSubscription(profile, subID); err != nil {
-				return err
-			}
-			if err := config.Save(profile); err != nil {
-				return err
-			}
-		}
+	result := activationResult{Type: "resource", Role: a.Role, Scope: scope, RequestID: resp.Id}
+	if resp.Properties != nil {
+		result.Status = resp.Properties.Status
 	}
+	return result, nil
+}
 
-	status := ""
-	if resp.Properties != nil {
-		status = resp.Properties.Status
+// setDefaultSubscriptionFor makes the subscription holding scope the
+// profile's default.
+func setDefaultSubscriptionFor(scope string) error {
+	subID := extractSubscriptionID(scope)
+	profile, _ := config.Load()
+	if subID == "" || profile == nil {
+		return nil
 	}
-	return renderActivationResult(cmd, status, scope, a.Role, "", resp.Id)
+	if err := SetDefaultSubscription(profile, subID); err != nil {
+		return err
+	}
+	return config.Save(profile)
 }
 
 func promptForMissingResourceArgs(p *Prompter, a *activateResourceArgs) error {
@@ -285,6 +313,12 @@ func buildScopeIndex(eligible *pimvendor.ResourceAssignmentResponse, p *config.P
 }
 
 func renderActivationResult(cmd *cobra.Command, status, scope, role, expires, requestID string) error {
+	return renderRequestResult(cmd, "Activated", status, scope, role, expires, requestID)
+}
+
+// renderRequestResult reports a submitted activation, deactivation or
+// extension; verb is the past tense used in table output.
+func renderRequestResult(cmd *cobra.Command, verb, status, scope, role, expires, requestID string) error {
 	format, _ := cmd.Flags().GetString("output")
 	switch strings.ToLower(format) {
 	case "table":
@@ -294,9 +328,9 @@ func renderActivationResult(cmd *cobra.Command, status, scope, role, expires, re
 			return nil
 		}
 		if expires == "" {
-			fmt.Fprintf(w, "Activated %s on %s\n", role, scope)
+			fmt.Fprintf(w, "%s %s on %s\n", verb, role, scope)
 		} else {
-			fmt.Fprintf(w, "Activated %s on %s; expires %s\n", role, scope, expires)
+			fmt.Fprintf(w, "%s %s on %s; expires %s\n", verb, role, scope, expires)
 		}
 		return nil
 	default:
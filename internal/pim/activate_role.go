@@ -0,0 +1,96 @@
+package pim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type activateRoleArgs struct {
+	Role          string
+	Ticket        string
+	Justification string
+	Duration      int
+	NoInput       bool
+}
+
+func newActivateRoleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "role",
+		Short: "Activate an eligible Entra ID directory role",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a := activateRoleArgs{}
+			a.Role, _ = cmd.Flags().GetString("role")
+			a.Ticket, _ = cmd.Flags().GetString("ticket")
+			a.Justification, _ = cmd.Flags().GetString("justification")
+			a.Duration, _ = cmd.Flags().GetInt("duration")
+			a.NoInput, _ = cmd.Flags().GetBool("no-input")
+			return runActivateRole(cmd, a)
+		},
+	}
+	cmd.Flags().String("role", "", "directory role display name (e.g. Global Reader)")
+	cmd.Flags().String("ticket", "", "ticket reference: SYSTEM:NUMBER, for roles whose policy requires one")
+	cmd.Flags().String("justification", "", "reason for activation")
+	cmd.Flags().Int("duration", 0, "activation duration in minutes")
+	cmd.Flags().Bool("no-input", false, "disable interactive prompts")
+	return cmd
+}
+
+func validateActivateRoleArgs(a activateRoleArgs, noInput bool) error {
+	missing := []string{}
+	if a.Role == "" {
+		missing = append(missing, "--role")
+	}
+	if a.Justification == "" {
+		missing = append(missing, "--justification")
+	}
+	if a.Duration <= 0 {
+		missing = append(missing, "--duration")
+	}
+	// --ticket is optional; only some directory role policies require one.
+	if len(missing) == 0 {
+		return nil
+	}
+	if noInput {
+		return fmt.Errorf("%w: %s", errMissingFlag, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func runActivateRole(cmd *cobra.Command, a activateRoleArgs) error {
+	if !a.NoInput {
+		prompter := NewPrompter(a.NoInput)
+		if a.Role == "" {
+			v, err := prompter.PromptString("Directory role")
+			if err != nil {
+				return err
+			}
+			a.Role = v
+		}
+		if a.Justification == "" {
+			v, err := prompter.PromptString("Justification")
+			if err != nil {
+				return err
+			}
+			a.Justification = v
+		}
+		if a.Duration <= 0 {
+			v, err := prompter.PromptString("Duration (minutes)")
+			if err != nil {
+				return err
+			}
+			d, perr := strconv.Atoi(strings.TrimSpace(v))
+			if perr != nil || d <= 0 {
+				return fmt.Errorf("invalid duration %q", v)
+			}
+			a.Duration = d
+		}
+	}
+	if err := validateActivateRoleArgs(a, true); err != nil {
+		return err
+	}
+
+	return activateGovernance(cmd, "role", a.Role, a.Justification, a.Ticket, a.Duration)
+}
@@ -0,0 +1,20 @@
+package pim
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateActivateRoleArgs_TicketOptional(t *testing.T) {
+	err := validateActivateRoleArgs(activateRoleArgs{Role: "Global Reader", Justification: "j", Duration: 60}, true)
+	if err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+}
+
+func TestValidateActivateRoleArgs_MissingRole(t *testing.T) {
+	err := validateActivateRoleArgs(activateRoleArgs{Justification: "j", Duration: 60}, true)
+	if !errors.Is(err, errMissingFlag) {
+		t.Fatalf("want errMissingFlag, got %v", err)
+	}
+}
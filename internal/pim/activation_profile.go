@@ -0,0 +1,469 @@
+package pim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	pimvendor "github.com/cdobbyn/azure-go-cli/internal/pim/vendor"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+)
+
+// activationProfilesFile lives next to azureProfile.json.
+const activationProfilesFile = "pimProfiles.yaml"
+
+// provisioningPollInterval is how often a profile activation rechecks the
+// active assignments while waiting for its requests to be provisioned.
+const provisioningPollInterval = 10 * time.Second
+
+// ActivationProfile is a named bundle of activations. Duration, Ticket and
+// Justification are defaults for activations that don't set their own.
+type ActivationProfile struct {
+	Justification   string              `yaml:"justification"`
+	Ticket          string              `yaml:"ticket"`
+	Duration        int                 `yaml:"duration"`
+	SetSubscription bool                `yaml:"setSubscription"`
+	Activations     []ProfileActivation `yaml:"activations"`
+}
+
+// ProfileActivation is one entry of a profile. Groups are named with Name,
+// resource and directory roles with Role; Scope only applies to resource
+// roles and takes the same forms as --scope.
+type ProfileActivation struct {
+	Type          string `yaml:"type"`
+	Role          string `yaml:"role"`
+	Name          string `yaml:"name"`
+	Scope         string `yaml:"scope"`
+	Duration      int    `yaml:"duration"`
+	Justification string `yaml:"justification"`
+	Ticket        string `yaml:"ticket"`
+}
+
+type activationProfiles struct {
+	Profiles map[string]ActivationProfile `yaml:"profiles"`
+}
+
+// profileRequest is a profile entry with the defaults applied and the
+// justification template expanded.
+type profileRequest struct {
+	Type          string
+	Role          string
+	Scope         string
+	Justification string
+	Ticket        string
+	Duration      int
+}
+
+// justificationData is what a justification template can refer to, e.g.
+// "On-call {{.Date}} ({{.Ticket}}): {{.Role}} on {{.Scope}}".
+type justificationData struct {
+	Profile string
+	Type    string
+	Role    string
+	Scope   string
+	Ticket  string
+	Date    string
+}
+
+func activationProfilesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, config.ConfigDir, activationProfilesFile), nil
+}
+
+// loadActivationProfile reads the named profile from path, or from the
+// default profiles file when path is empty.
+func loadActivationProfile(path, name string) (ActivationProfile, error) {
+	if path == "" {
+		var err error
+		if path, err = activationProfilesPath(); err != nil {
+			return ActivationProfile{}, err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ActivationProfile{}, fmt.Errorf("no PIM activation profiles file at %s", path)
+		}
+		return ActivationProfile{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return parseActivationProfile(data, path, name)
+}
+
+func parseActivationProfile(data []byte, path, name string) (ActivationProfile, error) {
+	var file activationProfiles
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// Typos such as "justificaton" would otherwise silently fall back to
+	// the defaults.
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && err != io.EOF {
+		return ActivationProfile{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	p, ok := file.Profiles[name]
+	if !ok {
+		var names []string
+		for n := range file.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return ActivationProfile{}, fmt.Errorf("no PIM activation profile %q in %s; profiles: %s", name, path, strings.Join(names, ", "))
+	}
+	return p, nil
+}
+
+// expandProfile applies the profile defaults to each activation and expands
+// its justification template. All problems are reported at once, so a
+// profile can be fixed in one pass before anything is submitted.
+func expandProfile(name string, p ActivationProfile, now time.Time) ([]profileRequest, error) {
+	if len(p.Activations) == 0 {
+		return nil, fmt.Errorf("PIM activation profile %q has no activations", name)
+	}
+	var requests []profileRequest
+	var problems []string
+	for i, a := range p.Activations {
+		r := profileRequest{
+			Type:          a.Type,
+			Role:          a.Role,
+			Scope:         a.Scope,
+			Justification: firstNonEmpty(a.Justification, p.Justification),
+			Ticket:        firstNonEmpty(a.Ticket, p.Ticket),
+			Duration:      a.Duration,
+		}
+		if r.Duration <= 0 {
+			r.Duration = p.Duration
+		}
+		where := fmt.Sprintf("activation %d", i+1)
+		switch a.Type {
+		case "resource", "role":
+			if a.Name != "" {
+				problems = append(problems, where+": use role, not name, for "+a.Type+" activations")
+			}
+		case "group":
+			r.Role = a.Name
+			if a.Role != "" {
+				problems = append(problems, where+": use name, not role, for group activations")
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: type must be resource, group or role, not %q", where, a.Type))
+		}
+		if r.Role == "" {
+			problems = append(problems, where+": missing role or group name")
+		} else {
+			where = fmt.Sprintf("%s (%s)", where, r.Role)
+		}
+		if a.Scope != "" && a.Type != "resource" {
+			problems = append(problems, where+": scope only applies to resource activations")
+		}
+		if r.Duration <= 0 {
+			problems = append(problems, where+": missing duration")
+		}
+		if a.Type == "resource" && r.Ticket == "" {
+			problems = append(problems, where+": missing ticket")
+		}
+
+		tmpl, err := template.New(where).Option("missingkey=error").Parse(r.Justification)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: bad justification template: %v", where, err))
+		} else {
+			var b strings.Builder
+			data := justificationData{Profile: name, Type: r.Type, Role: r.Role, Scope: r.Scope, Ticket: r.Ticket, Date: now.Format("2006-01-02")}
+			if err := tmpl.Execute(&b, data); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: bad justification template: %v", where, err))
+			}
+			r.Justification = strings.TrimSpace(b.String())
+		}
+		if r.Justification == "" {
+			problems = append(problems, where+": missing justification")
+		}
+		requests = append(requests, r)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid PIM activation profile %q:\n  %s", name, strings.Join(problems, "\n  "))
+	}
+	return requests, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// profileResult is one row of a profile activation's report.
+type profileResult struct {
+	activationResult
+	Error string `json:"error,omitempty"`
+}
+
+func runActivateProfile(cmd *cobra.Command, name string) error {
+	path, _ := cmd.Flags().GetString("profile-file")
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	setSubscription, _ := cmd.Flags().GetBool("set-subscription")
+
+	p, err := loadActivationProfile(path, name)
+	if err != nil {
+		return err
+	}
+	requests, err := expandProfile(name, p, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return fmt.Errorf("get credential: %w", err)
+	}
+	ts := NewTokenSource(cred)
+
+	results := submitProfile(ts, requests)
+	if !noWait {
+		waitForProvisioning(ts, results, timeout)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed == 0 && (setSubscription || p.SetSubscription) {
+		if scope := firstResourceScope(results); scope != "" {
+			if err := setDefaultSubscriptionFor(scope); err != nil {
+				return err
+			}
+		}
+	}
+
+	format, _ := cmd.Flags().GetString("output")
+	if !cmd.Flags().Changed("output") {
+		format = "table"
+	}
+	if strings.ToLower(format) == "table" {
+		if err := renderProfileTable(cmd.OutOrStdout(), results); err != nil {
+			return err
+		}
+	} else if err := output.PrintJSON(cmd, results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d activations in profile %q failed", failed, len(results), name)
+	}
+	return nil
+}
+
+// submitProfile submits every request at once; each one looks up its own
+// eligibility, so they don't depend on each other.
+func submitProfile(ts *TokenSource, requests []profileRequest) []profileResult {
+	results := make([]profileResult, len(requests))
+	var wg sync.WaitGroup
+	for i, r := range requests {
+		wg.Add(1)
+		go func(i int, r profileRequest) {
+			defer wg.Done()
+			var res activationResult
+			var err error
+			if r.Type == "resource" {
+				res, err = submitResourceActivation(ts, activateResourceArgs{
+					Role: r.Role, Scope: r.Scope, Ticket: r.Ticket,
+					Justification: r.Justification, Duration: r.Duration,
+				})
+			} else {
+				res, err = submitGovernanceActivation(ts, r.Type, r.Role, r.Justification, r.Ticket, r.Duration)
+			}
+			if err != nil {
+				res = activationResult{Type: r.Type, Role: r.Role, Scope: r.Scope, Status: pimvendor.StatusFailed}
+				results[i] = profileResult{activationResult: res, Error: err.Error()}
+				return
+			}
+			results[i] = profileResult{activationResult: res}
+		}(i, r)
+	}
+	wg.Wait()
+	return results
+}
+
+// awaitingApproval reports whether a request is parked until an approver
+// acts on it, which can take far longer than a command should wait.
+func awaitingApproval(status string) bool {
+	switch status {
+	case pimvendor.StatusPendingApproval, pimvendor.StatusPendingAdminDecision, pimvendor.StatusPendingApprovalProvisioning:
+		return true
+	}
+	return false
+}
+
+// waitForProvisioning polls the active assignments until every submitted
+// activation shows up there, then reports them as active with their expiry.
+// Each pending request's own status is re-read too, so one the service has
+// failed or denied is reported straight away instead of at the timeout.
+// Running out of time only logs a warning: the requests were accepted and
+// will still be provisioned.
+func waitForProvisioning(ts *TokenSource, results []profileResult, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for {
+		markFailed(results)
+		waiting := pendingResults(results)
+		if len(waiting) == 0 {
+			return
+		}
+		active, err := collectActive(ts, "")
+		if err != nil {
+			logger.Warning("Failed to check activation status: %v", err)
+		} else {
+			markProvisioned(results, active)
+		}
+		for _, i := range pendingResults(results) {
+			status, err := requestStatus(ts, results[i].activationResult)
+			if err != nil {
+				logger.Debug("Failed to get status of request %s: %v", results[i].RequestID, err)
+				continue
+			}
+			if status != "" {
+				results[i].Status = status
+			}
+		}
+		markFailed(results)
+		if len(pendingResults(results)) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			logger.Warning("Timed out waiting for %d activation(s) to be provisioned; check `az pim active` later", len(pendingResults(results)))
+			return
+		}
+		logger.Info("Waiting for %d activation(s) to be provisioned...", len(pendingResults(results)))
+		time.Sleep(provisioningPollInterval)
+	}
+}
+
+// requestFailed reports whether a request status is final and
+// unsuccessful, using the statuses the vendored client treats as failed.
+func requestFailed(status string) bool {
+	switch status {
+	case pimvendor.StatusAdminDenied, pimvendor.StatusCanceled, pimvendor.StatusDenied, pimvendor.StatusFailed,
+		pimvendor.StatusFailedAsResourceIsLocked, pimvendor.StatusInvalid, pimvendor.StatusRevoked, pimvendor.StatusTimedOut:
+		return true
+	}
+	return false
+}
+
+// markFailed turns submitted activations whose request has failed or been
+// denied into errors, so they stop being waited on and fail the run.
+func markFailed(results []profileResult) {
+	for i := range results {
+		r := &results[i]
+		if r.Error == "" && requestFailed(r.Status) {
+			r.Error = fmt.Sprintf("activation request ended with status %s", r.Status)
+		}
+	}
+}
+
+// requestStatus re-reads the status of a submitted activation request from
+// ARM for resource roles, or the governance API for groups and directory
+// roles.
+func requestStatus(ts *TokenSource, r activationResult) (string, error) {
+	if r.RequestID == "" {
+		return "", nil
+	}
+	if r.Type == "resource" {
+		token, err := ts.GetAccessToken(azure.ARMScope())
+		if err != nil {
+			return "", err
+		}
+		req, err := armRequest("GET", azure.ARMEndpoint()+r.RequestID, token, map[string]string{"api-version": pimvendor.AZ_PIM_API_VERSION}, nil)
+		if err != nil {
+			return "", err
+		}
+		return field(req, "properties", "status"), nil
+	}
+
+	token, err := ts.GetAccessToken(azure.GraphScope())
+	if err != nil {
+		return "", err
+	}
+	resp, err := pimvendor.Request(&pimvendor.PIMRequest{
+		Url:    fmt.Sprintf("%s/%s/%s/roleAssignmentRequests/%s", pimvendor.AZ_RBAC_BASE_URL, pimvendor.AZ_RBAC_BASE_PATH, governanceKinds[r.Type].roleType, r.RequestID),
+		Token:  token,
+		Method: "GET",
+	}, &map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
+	return field(*resp.(*map[string]interface{}), "status", "subStatus"), nil
+}
+
+// pendingResults returns the indexes of submitted activations that aren't
+// active yet and aren't waiting on an approver.
+func pendingResults(results []profileResult) []int {
+	var pending []int
+	for i, r := range results {
+		if r.Error == "" && r.Status != "Active" && !awaitingApproval(r.Status) {
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// markProvisioned flips the results that now have a matching activation.
+func markProvisioned(results []profileResult, active []activeAssignment) {
+	for _, i := range pendingResults(results) {
+		r := &results[i]
+		for _, a := range active {
+			if !a.Activated || a.Type != r.Type || a.Name != r.Role {
+				continue
+			}
+			if r.Type == "resource" && !strings.EqualFold(a.Scope, r.Scope) {
+				continue
+			}
+			r.Status = "Active"
+			r.ExpiresAt = a.EndDateTime
+			break
+		}
+	}
+}
+
+func firstResourceScope(results []profileResult) string {
+	for _, r := range results {
+		if r.Type == "resource" && r.Error == "" {
+			return r.Scope
+		}
+	}
+	return ""
+}
+
+func renderProfileTable(w io.Writer, results []profileResult) error {
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tNAME\tSCOPE\tSTATUS")
+	for _, r := range results {
+		status := formatStatus(r.Status, r.ExpiresAt)
+		if r.Error != "" {
+			status = "Failed: " + r.Error
+		}
+		scope := r.Scope
+		if scope == "" {
+			scope = "—"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Type, r.Role, scope, status)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,174 @@
+package pim
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleProfiles = `
+profiles:
+  oncall:
+    ticket: Jira:OPS-1
+    duration: 240
+    justification: "On-call {{.Date}} ({{.Ticket}}): {{.Role}}"
+    setSubscription: true
+    activations:
+      - type: resource
+        role: Contributor
+        scope: Acme Production
+      - type: resource
+        role: Reader
+        scope: Acme Dev
+        duration: 60
+      - type: group
+        name: customer-acme-admins
+        justification: hand-off
+`
+
+func TestParseActivationProfile(t *testing.T) {
+	p, err := parseActivationProfile([]byte(sampleProfiles), "f", "oncall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Activations) != 3 || !p.SetSubscription || p.Duration != 240 {
+		t.Fatalf("got %+v", p)
+	}
+	_, err = parseActivationProfile([]byte(sampleProfiles), "f", "nope")
+	if err == nil || !strings.Contains(err.Error(), "profiles: oncall") {
+		t.Fatalf("want list of profiles, got %v", err)
+	}
+}
+
+func TestParseActivationProfile_RejectsUnknownKeys(t *testing.T) {
+	data := "profiles:\n  x:\n    justificaton: typo\n"
+	if _, err := parseActivationProfile([]byte(data), "f", "x"); err == nil {
+		t.Fatal("misspelled key accepted")
+	}
+}
+
+func TestLoadActivationProfile_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(path, []byte(sampleProfiles), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadActivationProfile(path, "oncall"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadActivationProfile(filepath.Join(t.TempDir(), "missing.yaml"), "oncall"); err == nil ||
+		!strings.Contains(err.Error(), "no PIM activation profiles file") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestExpandProfile_AppliesDefaultsAndTemplate(t *testing.T) {
+	p, _ := parseActivationProfile([]byte(sampleProfiles), "f", "oncall")
+	reqs, err := expandProfile("oncall", p, time.Date(2026, 5, 14, 8, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []profileRequest{
+		{Type: "resource", Role: "Contributor", Scope: "Acme Production", Ticket: "Jira:OPS-1", Duration: 240,
+			Justification: "On-call 2026-05-14 (Jira:OPS-1): Contributor"},
+		{Type: "resource", Role: "Reader", Scope: "Acme Dev", Ticket: "Jira:OPS-1", Duration: 60,
+			Justification: "On-call 2026-05-14 (Jira:OPS-1): Reader"},
+		{Type: "group", Role: "customer-acme-admins", Ticket: "Jira:OPS-1", Duration: 240, Justification: "hand-off"},
+	}
+	for i := range want {
+		if reqs[i] != want[i] {
+			t.Errorf("request %d = %+v, want %+v", i, reqs[i], want[i])
+		}
+	}
+}
+
+func TestExpandProfile_ReportsAllProblems(t *testing.T) {
+	p := ActivationProfile{Activations: []ProfileActivation{
+		{Type: "resource", Role: "Owner"},
+		{Type: "group", Role: "admins", Scope: "/subscriptions/x", Duration: 30, Justification: "{{.Nope}}"},
+		{Type: "vm"},
+	}}
+	_, err := expandProfile("bad", p, time.Now())
+	if err == nil {
+		t.Fatal("want error")
+	}
+	for _, want := range []string{
+		"activation 1 (Owner): missing duration",
+		"activation 1 (Owner): missing ticket",
+		"activation 1 (Owner): missing justification",
+		"activation 2: use name, not role",
+		"scope only applies to resource activations",
+		"bad justification template",
+		`activation 3: type must be resource, group or role, not "vm"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestMarkProvisioned(t *testing.T) {
+	results := []profileResult{
+		{activationResult: activationResult{Type: "resource", Role: "Contributor", Scope: "/subscriptions/a", Status: "Provisioned"}},
+		{activationResult: activationResult{Type: "group", Role: "admins", Status: "PendingApproval"}},
+		{activationResult: activationResult{Type: "role", Role: "Global Reader", Status: "Failed"}, Error: "boom"},
+		{activationResult: activationResult{Type: "role", Role: "Security Reader", Status: "Granted"}},
+	}
+	if got := pendingResults(results); len(got) != 2 || got[0] != 0 || got[1] != 3 {
+		t.Fatalf("pending = %v", got)
+	}
+	markProvisioned(results, []activeAssignment{
+		{Type: "resource", Name: "Contributor", Scope: "/subscriptions/A", Activated: true, EndDateTime: "2026-05-14T12:00:00Z"},
+		{Type: "role", Name: "Security Reader", Activated: false},
+	})
+	if results[0].Status != "Active" || results[0].ExpiresAt != "2026-05-14T12:00:00Z" {
+		t.Errorf("resource not marked active: %+v", results[0])
+	}
+	if got := pendingResults(results); len(got) != 1 || got[0] != 3 {
+		t.Errorf("standing assignment should not count as provisioned; pending = %v", got)
+	}
+	if got := firstResourceScope(results); got != "/subscriptions/a" {
+		t.Errorf("firstResourceScope = %q", got)
+	}
+}
+
+func TestMarkFailed(t *testing.T) {
+	results := []profileResult{
+		{activationResult: activationResult{Type: "resource", Role: "Contributor", Status: "Provisioned"}},
+		{activationResult: activationResult{Type: "group", Role: "admins", Status: "Denied"}},
+		{activationResult: activationResult{Type: "role", Role: "Global Reader", Status: "AdminDenied"}},
+		{activationResult: activationResult{Type: "role", Role: "Security Reader", Status: "Failed"}, Error: "validation failed"},
+	}
+	markFailed(results)
+	if results[0].Error != "" {
+		t.Errorf("provisioned request marked failed: %q", results[0].Error)
+	}
+	if !strings.Contains(results[1].Error, "Denied") || !strings.Contains(results[2].Error, "AdminDenied") {
+		t.Errorf("denied requests not marked failed: %q, %q", results[1].Error, results[2].Error)
+	}
+	if results[3].Error != "validation failed" {
+		t.Errorf("existing error replaced: %q", results[3].Error)
+	}
+	if got := pendingResults(results); len(got) != 1 || got[0] != 0 {
+		t.Errorf("failed requests should stop being waited on; pending = %v", got)
+	}
+}
+
+func TestRenderProfileTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderProfileTable(&buf, []profileResult{
+		{activationResult: activationResult{Type: "group", Role: "admins", Status: "PendingApproval"}},
+		{activationResult: activationResult{Type: "resource", Role: "Owner"}, Error: "no eligible assignment"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"PendingApproval", "Failed: no eligible assignment", "—"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package pim
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pimvendor "github.com/cdobbyn/azure-go-cli/internal/pim/vendor"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+)
+
+// resourceScheduleInstance is one of the caller's active Azure resource role
+// assignments. AssignmentType tells PIM activations ("Activated") from
+// standing assignments ("Assigned"), which can't be deactivated or extended.
+type resourceScheduleInstance struct {
+	Id         string                              `json:"id"`
+	Name       string                              `json:"name"`
+	Properties *resourceScheduleInstanceProperties `json:"properties"`
+}
+
+type resourceScheduleInstanceProperties struct {
+	Scope                           string                                `json:"scope"`
+	RoleDefinitionId                string                                `json:"roleDefinitionId"`
+	PrincipalId                     string                                `json:"principalId"`
+	Status                          string                                `json:"status"`
+	StartDateTime                   string                                `json:"startDateTime"`
+	EndDateTime                     string                                `json:"endDateTime"`
+	AssignmentType                  string                                `json:"assignmentType"`
+	MemberType                      string                                `json:"memberType"`
+	LinkedRoleEligibilityScheduleId string                                `json:"linkedRoleEligibilityScheduleId"`
+	ExpandedProperties              *pimvendor.ResourceExpandedProperties `json:"expandedProperties"`
+}
+
+type resourceScheduleInstanceResponse struct {
+	Value []resourceScheduleInstance `json:"value"`
+}
+
+// getActiveResourceAssignments is the active counterpart of the vendored
+// GetEligibleResourceAssignments.
+func getActiveResourceAssignments(token string) (*resourceScheduleInstanceResponse, error) {
+	resp, err := pimvendor.Request(&pimvendor.PIMRequest{
+		Url:    fmt.Sprintf("%s/%s/roleAssignmentScheduleInstances", azure.ARMEndpoint(), pimvendor.ARM_BASE_PATH),
+		Token:  token,
+		Method: "GET",
+		Params: map[string]string{
+			"api-version": pimvendor.AZ_PIM_API_VERSION,
+			"$filter":     "asTarget()",
+		},
+	}, &resourceScheduleInstanceResponse{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*resourceScheduleInstanceResponse), nil
+}
+
+// activeAssignment is an active assignment of any type, carrying what
+// deactivate and extend need to build their requests.
+type activeAssignment struct {
+	Type             string
+	Name             string
+	Scope            string // ARM scope for resource roles
+	ScopeName        string
+	RoleDefinitionID string
+	EndDateTime      string
+	Activated        bool // false for standing (permanent) assignments
+	resource         *resourceScheduleInstance
+	governance       *activeGovernanceAssignment
+}
+
+// collectActive gathers the caller's active assignments of the given type,
+// or of every type when typeFilter is empty.
+func collectActive(ts *TokenSource, typeFilter string) ([]activeAssignment, error) {
+	var active []activeAssignment
+
+	if typeFilter == "" || typeFilter == "resource" {
+		armToken, err := ts.GetAccessToken(azure.ARMScope())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := getActiveResourceAssignments(armToken)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Value {
+			inst := &resp.Value[i]
+			p := inst.Properties
+			if p == nil {
+				continue
+			}
+			a := activeAssignment{
+				Type:             "resource",
+				Scope:            p.Scope,
+				RoleDefinitionID: p.RoleDefinitionId,
+				EndDateTime:      p.EndDateTime,
+				Activated:        strings.EqualFold(p.AssignmentType, "Activated"),
+				resource:         inst,
+			}
+			if p.ExpandedProperties != nil {
+				a.Name = displayNameOrEmpty(p.ExpandedProperties.RoleDefinition)
+				a.ScopeName = displayNameOrEmpty(p.ExpandedProperties.Scope)
+			}
+			active = append(active, a)
+		}
+	}
+
+	for _, kind := range []string{"group", "role"} {
+		if typeFilter != "" && typeFilter != kind {
+			continue
+		}
+		graphToken, err := ts.GetAccessToken(azure.GraphScope())
+		if err != nil {
+			return nil, err
+		}
+		info, err := pimvendor.GetUserInfo(graphToken)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := getActiveGovernanceAssignments(governanceKinds[kind].roleType, info.ObjectId, graphToken)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Value {
+			g := &resp.Value[i]
+			a := activeAssignment{
+				Type:             kind,
+				RoleDefinitionID: g.RoleDefinitionId,
+				EndDateTime:      g.EndDateTime,
+				Activated:        g.LinkedEligibleRoleAssignmentId != "",
+				governance:       g,
+			}
+			if g.RoleDefinition != nil {
+				a.Name = g.RoleDefinition.DisplayName
+				if g.RoleDefinition.Resource != nil {
+					a.Scope = g.RoleDefinition.Resource.Id
+				}
+			}
+			active = append(active, a)
+		}
+	}
+
+	return active, nil
+}
+
+// selectActivation finds the one activation of the given type called name,
+// narrowed to scope (a resolved ARM path) when given. Standing assignments
+// are skipped: PIM only deactivates and extends activations.
+func selectActivation(active []activeAssignment, typ, name, scope string) (activeAssignment, error) {
+	var matches []activeAssignment
+	for _, a := range active {
+		if a.Type != typ || a.Name != name || !a.Activated {
+			continue
+		}
+		if scope != "" && !strings.EqualFold(a.Scope, scope) {
+			continue
+		}
+		matches = append(matches, a)
+	}
+	switch len(matches) {
+	case 0:
+		if scope != "" {
+			return activeAssignment{}, fmt.Errorf("no active %s activation %q at scope %s", typ, name, scope)
+		}
+		return activeAssignment{}, fmt.Errorf("no active %s activation %q", typ, name)
+	case 1:
+		return matches[0], nil
+	default:
+		var scopes []string
+		for _, m := range matches {
+			scopes = append(scopes, m.Scope)
+		}
+		return activeAssignment{}, fmt.Errorf("ambiguous: %s %q is active on multiple scopes; pass --scope; candidates: %s",
+			typ, name, strings.Join(scopes, ", "))
+	}
+}
+
+// activeScopeIndex lets --scope on deactivate and extend take the same
+// forms as on activate, resolved against the active resource assignments.
+func activeScopeIndex(active []activeAssignment, p *config.Profile) []ScopeIndexEntry {
+	var index []ScopeIndexEntry
+	for _, a := range active {
+		if a.Type != "resource" {
+			continue
+		}
+		subID := extractSubscriptionID(a.Scope)
+		entry := ScopeIndexEntry{ArmPath: a.Scope, SubscriptionID: subID, SubscriptionName: a.ScopeName}
+		if p != nil {
+			for _, s := range p.Subscriptions {
+				if s.ID == subID {
+					entry.TenantID = s.TenantID
+					entry.TenantDisplayName = s.TenantID
+				}
+			}
+		}
+		index = append(index, entry)
+	}
+	return index
+}
+
+func newActiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "active",
+		Short: "List active PIM assignments and the time left on each",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			typeFilter, _ := cmd.Flags().GetString("type")
+			return runActive(cmd, typeFilter)
+		},
+	}
+	cmd.Flags().String("type", "", "filter by type: resource, group or role")
+	return cmd
+}
+
+func runActive(cmd *cobra.Command, typeFilter string) error {
+	format, _ := cmd.Flags().GetString("output")
+	// Table by default, as for list.
+	if !cmd.Flags().Changed("output") {
+		format = "table"
+	}
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return fmt.Errorf("get credential: %w", err)
+	}
+	active, err := collectActive(NewTokenSource(cred), typeFilter)
+	if err != nil {
+		return err
+	}
+
+	profile, _ := config.Load()
+	rows := activeRows(active, profile)
+	if strings.ToLower(format) != "table" {
+		return output.PrintJSON(cmd, rows)
+	}
+	return RenderListTable(cmd.OutOrStdout(), rows)
+}
+
+func activeRows(active []activeAssignment, p *config.Profile) []ListRow {
+	rows := []ListRow{}
+	for _, a := range active {
+		row := ListRow{
+			Type:   a.Type,
+			Name:   a.Name,
+			Status: formatStatus("Active", a.EndDateTime),
+		}
+		if a.Type == "resource" {
+			row.Tenant, row.Subscription = lookupTenantAndSub(p, a.Scope)
+		} else {
+			row.Tenant = resolveTenantName(p, a.Scope)
+			row.Subscription = "—"
+		}
+		if a.EndDateTime == "" {
+			row.Status = "Active (permanent)"
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
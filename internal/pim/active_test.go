@@ -0,0 +1,122 @@
+package pim
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatStatusAt_ShowsRemainingTime(t *testing.T) {
+	now := time.Date(2026, 5, 14, 14, 37, 0, 0, time.UTC)
+	cases := map[string]string{
+		"2026-05-14T15:42:00Z":         "Active (expires 15:42 UTC, 1h05m left)",
+		"2026-05-14T14:59:30.1234567Z": "Active (expires 14:59 UTC, 23m left)",
+		"2026-05-14T14:00:00Z":         "Active (expires 14:00 UTC, expired)",
+	}
+	for end, want := range cases {
+		if got := formatStatusAt("Active", end, now); got != want {
+			t.Errorf("formatStatusAt(%q) = %q, want %q", end, got, want)
+		}
+	}
+	if got := formatStatusAt("Eligible", "2026-05-14T15:42:00Z", now); got != "Eligible" {
+		t.Errorf("eligible status changed: %q", got)
+	}
+}
+
+func TestFormatRemaining_RoundsUp(t *testing.T) {
+	if got := formatRemaining(30 * time.Second); got != "1m left" {
+		t.Errorf("got %q", got)
+	}
+	if got := formatRemaining(8 * time.Hour); got != "8h00m left" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func sampleActive() []activeAssignment {
+	return []activeAssignment{
+		{Type: "resource", Name: "Contributor", Scope: "/subscriptions/a", Activated: true},
+		{Type: "resource", Name: "Contributor", Scope: "/subscriptions/b", Activated: true},
+		{Type: "resource", Name: "Reader", Scope: "/subscriptions/a"},
+		{Type: "role", Name: "Global Reader", Scope: "tenant", Activated: true},
+	}
+}
+
+func TestSelectActivation_AmbiguousWithoutScope(t *testing.T) {
+	_, err := selectActivation(sampleActive(), "resource", "Contributor", "")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatalf("want ambiguity error, got %v", err)
+	}
+	a, err := selectActivation(sampleActive(), "resource", "Contributor", "/subscriptions/B")
+	if err != nil || a.Scope != "/subscriptions/b" {
+		t.Fatalf("got %+v, %v", a, err)
+	}
+}
+
+func TestSelectActivation_SkipsStandingAssignments(t *testing.T) {
+	if _, err := selectActivation(sampleActive(), "resource", "Reader", ""); err == nil {
+		t.Fatal("standing assignment should not be selectable")
+	}
+	if _, err := selectActivation(sampleActive(), "role", "Global Reader", ""); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+}
+
+func TestActiveRows_PermanentAssignments(t *testing.T) {
+	rows := activeRows([]activeAssignment{{Type: "group", Name: "admins", Scope: "t"}}, nil)
+	if len(rows) != 1 || rows[0].Status != "Active (permanent)" || rows[0].Subscription != "—" {
+		t.Fatalf("got %+v", rows)
+	}
+}
+
+func TestResourceChangeRequest(t *testing.T) {
+	inst := &resourceScheduleInstance{}
+	inst.Properties = &resourceScheduleInstanceProperties{LinkedRoleEligibilityScheduleId: "elig"}
+	a := activeAssignment{Type: "resource", RoleDefinitionID: "rd", resource: inst}
+
+	req, err := resourceChangeRequest(activationChange{Action: "deactivate"}, "me", a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := req.Properties
+	if p.RequestType != "SelfDeactivate" || p.PrincipalId != "me" || p.RoleDefinitionId != "rd" ||
+		p.LinkedRoleEligibilityScheduleId != "elig" || p.ScheduleInfo != nil {
+		t.Fatalf("deactivate request: %+v", p)
+	}
+
+	req, err = resourceChangeRequest(activationChange{Action: "extend", Duration: 90, Ticket: "Jira:TEC-1"}, "me", a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = req.Properties
+	if p.RequestType != "SelfExtend" || p.ScheduleInfo.Expiration.Duration != "PT90M" ||
+		p.TicketInfo.TicketSystem != "Jira" || p.TicketInfo.TicketNumber != "TEC-1" {
+		t.Fatalf("extend request: %+v", p)
+	}
+}
+
+func TestGovernanceRequests(t *testing.T) {
+	a := activeGovernanceAssignment{LinkedEligibleRoleAssignmentId: "elig"}
+	a.RoleDefinitionId, a.ResourceId = "rd", "res"
+
+	remove := governanceRemoveRequest("me", a, "done")
+	if remove.Type != "UserRemove" || remove.AssignmentState != "Active" || remove.LinkedEligibleRoleAssignmentId != "elig" {
+		t.Fatalf("remove request: %+v", remove)
+	}
+	extend, err := governanceExtendRequest("me", a, 60, "more time", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extend.Type != "UserExtend" || extend.Schedule.Duration != "PT60M" || extend.ResourceId != "res" {
+		t.Fatalf("extend request: %+v", extend)
+	}
+}
+
+func TestValidateActivationChange(t *testing.T) {
+	if err := validateActivationChange(activationChange{Action: "deactivate", Name: "x"}, "--role"); err != nil {
+		t.Fatalf("deactivate needs only the name: %v", err)
+	}
+	err := validateActivationChange(activationChange{Action: "extend", Name: "x"}, "--role")
+	if err == nil || !strings.Contains(err.Error(), "--justification, --duration") {
+		t.Fatalf("got %v", err)
+	}
+}
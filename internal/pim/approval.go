@@ -0,0 +1,333 @@
+package pim
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pimvendor "github.com/cdobbyn/azure-go-cli/internal/pim/vendor"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+)
+
+// armApprovalAPIVersion is the first ARM API version with role assignment
+// approvals; the schedule requests themselves use AZ_PIM_API_VERSION.
+const armApprovalAPIVersion = "2021-01-01-preview"
+
+// Graph only has directory role approvals in beta; group approvals are v1.0.
+const (
+	groupRequestsPath  = "/identityGovernance/privilegedAccess/group/assignmentScheduleRequests/filterByCurrentUser(on='approver')"
+	groupApprovalsPath = "/identityGovernance/privilegedAccess/group/assignmentApprovals/"
+	roleRequestsPath   = "/roleManagement/directory/roleAssignmentScheduleRequests/filterByCurrentUser(on='approver')"
+	roleApprovalsPath  = "/beta/roleManagement/directory/roleAssignmentApprovals/"
+)
+
+// ApprovalRow is one request waiting on the caller's decision. ID is the
+// approval ID that approve and deny take.
+type ApprovalRow struct {
+	Type          string `json:"type"`
+	ID            string `json:"id"`
+	Requestor     string `json:"requestor"`
+	Name          string `json:"name"`
+	Scope         string `json:"scope"`
+	Justification string `json:"justification"`
+	Requested     string `json:"requested"`
+}
+
+func newApprovalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approval",
+		Short: "Review PIM activation requests waiting on your approval",
+	}
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List activation requests pending your approval",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			typeFilter, _ := cmd.Flags().GetString("type")
+			return runApprovalList(cmd, typeFilter)
+		},
+	}
+	list.Flags().String("type", "", "filter by type: resource, group or role")
+	cmd.AddCommand(list,
+		newApprovalDecisionCmd("approve", "Approve", "Approve a pending activation request"),
+		newApprovalDecisionCmd("deny", "Deny", "Deny a pending activation request"))
+	return cmd
+}
+
+func newApprovalDecisionCmd(use, result, short string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			typ, _ := cmd.Flags().GetString("type")
+			justification, _ := cmd.Flags().GetString("justification")
+			if id == "" || justification == "" {
+				return fmt.Errorf("%w: --id and --justification", errMissingFlag)
+			}
+			typ, err := approvalType(id, typ)
+			if err != nil {
+				return err
+			}
+			if err := decideApproval(cmd.Context(), typ, id, result, justification); err != nil {
+				return err
+			}
+			return output.PrintJSON(cmd, map[string]string{"type": typ, "id": id, "reviewResult": result})
+		},
+	}
+	cmd.Flags().String("id", "", "approval ID, as shown by `az pim approval list`")
+	cmd.Flags().String("type", "", "request type: resource, group or role (inferred for resource approvals)")
+	cmd.Flags().String("justification", "", "reason for the decision, shown to the requestor")
+	return cmd
+}
+
+// approvalType works out which API owns an approval. ARM approval IDs are
+// resource paths; group and directory role approvals are bare GUIDs and
+// can't be told apart, so those need --type.
+func approvalType(id, typ string) (string, error) {
+	switch typ {
+	case "resource", "group", "role":
+		return typ, nil
+	case "":
+		if strings.HasPrefix(id, "/") {
+			return "resource", nil
+		}
+		return "", fmt.Errorf("cannot tell the type of approval %s; pass --type group or --type role", id)
+	default:
+		return "", fmt.Errorf("invalid --type %q: must be resource, group or role", typ)
+	}
+}
+
+func runApprovalList(cmd *cobra.Command, typeFilter string) error {
+	ctx := cmd.Context()
+	format, _ := cmd.Flags().GetString("output")
+	// Table by default, as for list.
+	if !cmd.Flags().Changed("output") {
+		format = "table"
+	}
+
+	rows := []ApprovalRow{}
+	if typeFilter == "" || typeFilter == "resource" {
+		cred, err := azure.GetCredential()
+		if err != nil {
+			return fmt.Errorf("get credential: %w", err)
+		}
+		token, err := NewTokenSource(cred).GetAccessToken(azure.ARMScope())
+		if err != nil {
+			return err
+		}
+		requests, err := armRequest("GET", fmt.Sprintf("%s/%s/roleAssignmentScheduleRequests", azure.ARMEndpoint(), pimvendor.ARM_BASE_PATH),
+			token, map[string]string{"api-version": pimvendor.AZ_PIM_API_VERSION, "$filter": "asApprover()"}, nil)
+		if err != nil {
+			return err
+		}
+		values, _ := requests["value"].([]interface{})
+		rows = append(rows, resourceApprovalRows(values)...)
+	}
+	if typeFilter == "" || typeFilter == "group" || typeFilter == "role" {
+		client, err := graph.NewClient()
+		if err != nil {
+			return err
+		}
+		pending := url.Values{"$filter": {"status eq 'PendingApproval'"}}
+		if typeFilter != "role" {
+			pending.Set("$expand", "group,principal")
+			values, err := client.List(ctx, groupRequestsPath, pending, 0)
+			if err != nil {
+				return fmt.Errorf("failed to list group approvals: %w", err)
+			}
+			rows = append(rows, graphApprovalRows("group", values)...)
+		}
+		if typeFilter != "group" {
+			pending.Set("$expand", "roleDefinition,principal")
+			values, err := client.List(ctx, roleRequestsPath, pending, 0)
+			if err != nil {
+				return fmt.Errorf("failed to list directory role approvals: %w", err)
+			}
+			rows = append(rows, graphApprovalRows("role", values)...)
+		}
+	}
+
+	if strings.ToLower(format) != "table" {
+		return output.PrintJSON(cmd, rows)
+	}
+	return renderApprovalTable(cmd.OutOrStdout(), rows)
+}
+
+// resourceApprovalRows keeps the ARM schedule requests that are still
+// pending; asApprover() also returns ones already decided.
+func resourceApprovalRows(values []interface{}) []ApprovalRow {
+	var rows []ApprovalRow
+	for _, v := range values {
+		r, _ := v.(map[string]interface{})
+		if field(r, "properties", "status") != pimvendor.StatusPendingApproval {
+			continue
+		}
+		rows = append(rows, ApprovalRow{
+			Type:          "resource",
+			ID:            field(r, "properties", "approvalId"),
+			Requestor:     field(r, "properties", "expandedProperties", "principal", "displayName"),
+			Name:          field(r, "properties", "expandedProperties", "roleDefinition", "displayName"),
+			Scope:         field(r, "properties", "scope"),
+			Justification: field(r, "properties", "justification"),
+			Requested:     field(r, "properties", "createdOn"),
+		})
+	}
+	return rows
+}
+
+func graphApprovalRows(typ string, values []interface{}) []ApprovalRow {
+	var rows []ApprovalRow
+	for _, v := range values {
+		r, _ := v.(map[string]interface{})
+		row := ApprovalRow{
+			Type:          typ,
+			ID:            field(r, "approvalId"),
+			Requestor:     field(r, "principal", "userPrincipalName"),
+			Scope:         field(r, "directoryScopeId"),
+			Justification: field(r, "justification"),
+			Requested:     field(r, "createdDateTime"),
+		}
+		if row.Requestor == "" {
+			row.Requestor = field(r, "principalId")
+		}
+		if typ == "group" {
+			row.Name = field(r, "group", "displayName")
+			row.Scope = field(r, "accessId")
+		} else {
+			row.Name = field(r, "roleDefinition", "displayName")
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// decideApproval records result ("Approve" or "Deny") on the approval's
+// stage that is waiting on the caller.
+func decideApproval(ctx context.Context, typ, id, result, justification string) error {
+	decision := map[string]interface{}{"reviewResult": result, "justification": justification}
+	switch typ {
+	case "resource":
+		cred, err := azure.GetCredential()
+		if err != nil {
+			return fmt.Errorf("get credential: %w", err)
+		}
+		token, err := NewTokenSource(cred).GetAccessToken(azure.ARMScope())
+		if err != nil {
+			return err
+		}
+		params := map[string]string{"api-version": armApprovalAPIVersion}
+		approval, err := armRequest("GET", azure.ARMEndpoint()+id, token, params, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get approval %s: %w", id, err)
+		}
+		stages, _ := fieldValue(approval, "properties", "stages").([]interface{})
+		stage, err := pendingStage(stages)
+		if err != nil {
+			return fmt.Errorf("approval %s: %w", id, err)
+		}
+		// ARM stage IDs are full paths below the approval.
+		if !strings.HasPrefix(stage, "/") {
+			stage = id + "/stages/" + stage
+		}
+		_, err = armRequest("PUT", azure.ARMEndpoint()+stage, token, params, map[string]interface{}{"properties": decision})
+		if err != nil {
+			return fmt.Errorf("failed to record decision on %s: %w", id, err)
+		}
+		return nil
+	default:
+		client, err := graph.NewClient()
+		if err != nil {
+			return err
+		}
+		base, stagesKey := groupApprovalsPath+id, "stages"
+		if typ == "role" {
+			base, stagesKey = azure.GraphEndpoint()+roleApprovalsPath+id, "steps"
+		}
+		approval, err := client.Get(ctx, base, url.Values{"$expand": {stagesKey}})
+		if err != nil {
+			return fmt.Errorf("failed to get approval %s: %w", id, err)
+		}
+		stages, _ := approval[stagesKey].([]interface{})
+		stage, err := pendingStage(stages)
+		if err != nil {
+			return fmt.Errorf("approval %s: %w", id, err)
+		}
+		if err := client.Patch(ctx, base+"/"+stagesKey+"/"+stage, decision); err != nil {
+			return fmt.Errorf("failed to record decision on %s: %w", id, err)
+		}
+		return nil
+	}
+}
+
+// pendingStage returns the ID of the first stage still in progress that is
+// assigned to the caller. ARM nests the stage's fields under "properties";
+// Graph doesn't.
+func pendingStage(stages []interface{}) (string, error) {
+	for _, s := range stages {
+		stage, _ := s.(map[string]interface{})
+		props := stage
+		if p, ok := stage["properties"].(map[string]interface{}); ok {
+			props = p
+		}
+		if status, _ := props["status"].(string); !strings.EqualFold(status, "InProgress") {
+			continue
+		}
+		if mine, ok := props["assignedToMe"].(bool); ok && !mine {
+			continue
+		}
+		if id := field(stage, "id"); id != "" {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("no approval stage is waiting on you; it may already have been decided")
+}
+
+// armRequest sends an ARM request through the vendored PIM transport and
+// decodes the reply as a plain map.
+func armRequest(method, target, token string, params map[string]string, body interface{}) (map[string]interface{}, error) {
+	resp, err := pimvendor.Request(&pimvendor.PIMRequest{
+		Url:     target,
+		Token:   token,
+		Method:  method,
+		Params:  params,
+		Payload: body,
+	}, &map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	return *resp.(*map[string]interface{}), nil
+}
+
+// field returns the string at path in a decoded JSON object, or "".
+func field(o map[string]interface{}, path ...string) string {
+	s, _ := fieldValue(o, path...).(string)
+	return s
+}
+
+func fieldValue(o map[string]interface{}, path ...string) interface{} {
+	var v interface{} = o
+	for _, k := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+func renderApprovalTable(w io.Writer, rows []ApprovalRow) error {
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tREQUESTOR\tNAME\tSCOPE\tJUSTIFICATION\tID")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Type, r.Requestor, r.Name, r.Scope, r.Justification, r.ID)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,80 @@
+package pim
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeValues(t *testing.T, s string) []interface{} {
+	t.Helper()
+	var v []interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestApprovalType(t *testing.T) {
+	if typ, _ := approvalType("/providers/Microsoft.Authorization/roleAssignmentApprovals/x", ""); typ != "resource" {
+		t.Errorf("ARM approval inferred as %q", typ)
+	}
+	if _, err := approvalType("1f0c6f8e-0000-0000-0000-000000000000", ""); err == nil {
+		t.Error("bare GUID should need --type")
+	}
+	if typ, _ := approvalType("x", "role"); typ != "role" {
+		t.Errorf("explicit type ignored: %q", typ)
+	}
+	if _, err := approvalType("x", "bogus"); err == nil {
+		t.Error("invalid type accepted")
+	}
+}
+
+func TestPendingStage(t *testing.T) {
+	arm := decodeValues(t, `[
+		{"id": "/a/stages/1", "properties": {"status": "Completed"}},
+		{"id": "/a/stages/2", "properties": {"status": "InProgress", "assignedToMe": true}}
+	]`)
+	if id, err := pendingStage(arm); err != nil || id != "/a/stages/2" {
+		t.Errorf("ARM stage: %q, %v", id, err)
+	}
+	graph := decodeValues(t, `[
+		{"id": "s1", "status": "InProgress", "assignedToMe": false},
+		{"id": "s2", "status": "InProgress", "assignedToMe": true}
+	]`)
+	if id, err := pendingStage(graph); err != nil || id != "s2" {
+		t.Errorf("Graph stage: %q, %v", id, err)
+	}
+	if _, err := pendingStage(decodeValues(t, `[{"id": "s", "status": "Completed"}]`)); err == nil {
+		t.Error("decided approval should have no pending stage")
+	}
+}
+
+func TestResourceApprovalRows_KeepsPendingOnly(t *testing.T) {
+	rows := resourceApprovalRows(decodeValues(t, `[
+		{"properties": {"status": "PendingApproval", "approvalId": "/approvals/1", "scope": "/subscriptions/a",
+			"justification": "INC-1", "expandedProperties": {"principal": {"displayName": "Jo"}, "roleDefinition": {"displayName": "Owner"}}}},
+		{"properties": {"status": "Provisioned", "approvalId": "/approvals/2"}}
+	]`))
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows", len(rows))
+	}
+	r := rows[0]
+	if r.ID != "/approvals/1" || r.Requestor != "Jo" || r.Name != "Owner" || r.Justification != "INC-1" {
+		t.Errorf("row: %+v", r)
+	}
+}
+
+func TestGraphApprovalRows(t *testing.T) {
+	rows := graphApprovalRows("group", decodeValues(t, `[
+		{"approvalId": "ap", "principalId": "p1", "accessId": "member", "group": {"displayName": "admins"}}
+	]`))
+	if len(rows) != 1 || rows[0].Name != "admins" || rows[0].Requestor != "p1" || rows[0].Scope != "member" {
+		t.Fatalf("group rows: %+v", rows)
+	}
+	rows = graphApprovalRows("role", decodeValues(t, `[
+		{"approvalId": "ap", "principal": {"userPrincipalName": "jo@x"}, "directoryScopeId": "/", "roleDefinition": {"displayName": "Global Reader"}}
+	]`))
+	if len(rows) != 1 || rows[0].Name != "Global Reader" || rows[0].Requestor != "jo@x" || rows[0].Scope != "/" {
+		t.Fatalf("role rows: %+v", rows)
+	}
+}
@@ -1,19 +1,37 @@
 package pim
 
-import "github.com/spf13/cobra"
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
 
 // NewPIMCommand returns the top-level `az pim` command with its subcommands wired.
 func NewPIMCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pim",
 		Short: "Manage Azure Privileged Identity Management (PIM) assignments",
-		Long:  "List and activate eligible PIM role assignments and Entra group memberships.",
+		Long:  "List, activate, extend and deactivate PIM assignments for Azure resource roles, Entra group memberships and Entra directory roles, and review requests waiting on your approval.",
 	}
 	activate := &cobra.Command{
 		Use:   "activate",
-		Short: "Activate an eligible PIM assignment",
+		Short: "Activate an eligible PIM assignment, or every assignment in a profile",
+		Long: "Activate an eligible PIM assignment with one of the subcommands, or pass --profile " +
+			"to submit every activation in a named profile from ~/.azure/pimProfiles.yaml at once.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("profile")
+			if name == "" {
+				return cmd.Help()
+			}
+			return runActivateProfile(cmd, name)
+		},
 	}
-	activate.AddCommand(newActivateResourceCmd(), newActivateGroupCmd())
-	cmd.AddCommand(newListCmd(), activate)
+	activate.Flags().String("profile", "", "name of the activation profile to activate")
+	activate.Flags().String("profile-file", "", "profiles file to read instead of ~/.azure/pimProfiles.yaml")
+	activate.Flags().Bool("set-subscription", false, "set the profile's first resource subscription as the default once all activations succeed")
+	activate.Flags().Bool("no-wait", false, "do not wait for the activations to be provisioned")
+	activate.Flags().Duration("timeout", 5*time.Minute, "how long to wait for the activations to be provisioned")
+	activate.AddCommand(newActivateResourceCmd(), newActivateGroupCmd(), newActivateRoleCmd())
+	cmd.AddCommand(newListCmd(), newActiveCmd(), activate, newDeactivateCmd(), newExtendCmd(), newApprovalCmd())
 	return cmd
 }
@@ -0,0 +1,216 @@
+package pim
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pimvendor "github.com/cdobbyn/azure-go-cli/internal/pim/vendor"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+// activationChange is a deactivate or extend request for one activation.
+// Duration and Ticket only apply to extend.
+type activationChange struct {
+	Action        string // "deactivate" or "extend"
+	Type          string // resource, group or role
+	Name          string
+	Scope         string
+	Justification string
+	Ticket        string
+	Duration      int
+}
+
+func newDeactivateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deactivate",
+		Short: "End an active PIM activation before it expires",
+	}
+	cmd.AddCommand(
+		newActivationChangeCmd("deactivate", "resource", "Deactivate an Azure resource role activation"),
+		newActivationChangeCmd("deactivate", "group", "Deactivate an Entra ID group membership activation"),
+		newActivationChangeCmd("deactivate", "role", "Deactivate an Entra ID directory role activation"),
+	)
+	return cmd
+}
+
+// newActivationChangeCmd builds `deactivate <type>` and `extend <type>`.
+// The name flag matches activate: --name for groups, --role otherwise.
+func newActivationChangeCmd(action, typ, short string) *cobra.Command {
+	nameFlag := "role"
+	if typ == "group" {
+		nameFlag = "name"
+	}
+	cmd := &cobra.Command{
+		Use:   typ,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := activationChange{Action: action, Type: typ}
+			c.Name, _ = cmd.Flags().GetString(nameFlag)
+			c.Justification, _ = cmd.Flags().GetString("justification")
+			if typ == "resource" {
+				c.Scope, _ = cmd.Flags().GetString("scope")
+			}
+			if action == "extend" {
+				c.Duration, _ = cmd.Flags().GetInt("duration")
+				if typ != "group" {
+					c.Ticket, _ = cmd.Flags().GetString("ticket")
+				}
+			}
+			if err := validateActivationChange(c, "--"+nameFlag); err != nil {
+				return err
+			}
+			return runActivationChange(cmd, c)
+		},
+	}
+	switch typ {
+	case "resource":
+		cmd.Flags().String("role", "", "role display name (e.g. Contributor)")
+		cmd.Flags().String("scope", "", "subscription scope: ARM path, UUID, tenant/sub, or sub name")
+	case "group":
+		cmd.Flags().String("name", "", "group display name")
+	case "role":
+		cmd.Flags().String("role", "", "directory role display name (e.g. Global Reader)")
+	}
+	if action == "extend" {
+		cmd.Flags().String("justification", "", "reason for the extension")
+		cmd.Flags().Int("duration", 0, "new activation duration in minutes, counted from now")
+		if typ != "group" {
+			cmd.Flags().String("ticket", "", "ticket reference: SYSTEM:NUMBER (e.g. Jira:TEC-1234)")
+		}
+	} else {
+		cmd.Flags().String("justification", "", "reason for deactivating")
+	}
+	return cmd
+}
+
+func validateActivationChange(c activationChange, nameFlag string) error {
+	missing := []string{}
+	if c.Name == "" {
+		missing = append(missing, nameFlag)
+	}
+	if c.Action == "extend" {
+		if c.Justification == "" {
+			missing = append(missing, "--justification")
+		}
+		if c.Duration <= 0 {
+			missing = append(missing, "--duration")
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errMissingFlag, strings.Join(missing, ", "))
+}
+
+func runActivationChange(cmd *cobra.Command, c activationChange) error {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return fmt.Errorf("get credential: %w", err)
+	}
+	ts := NewTokenSource(cred)
+	active, err := collectActive(ts, c.Type)
+	if err != nil {
+		return err
+	}
+
+	scope := ""
+	if c.Scope != "" {
+		profile, _ := config.Load()
+		scope, err = ResolveScope(c.Scope, activeScopeIndex(active, profile))
+		if err != nil {
+			return err
+		}
+	}
+	a, err := selectActivation(active, c.Type, c.Name, scope)
+	if err != nil {
+		return err
+	}
+
+	verb := "Deactivated"
+	if c.Action == "extend" {
+		verb = "Extended"
+	}
+	client := pimvendor.AzureClient{ARMBaseURL: azure.ARMEndpoint()}
+
+	if a.Type == "resource" {
+		token, err := ts.GetAccessToken(azure.ARMScope())
+		if err != nil {
+			return err
+		}
+		info, err := pimvendor.GetUserInfo(token)
+		if err != nil {
+			return err
+		}
+		req, err := resourceChangeRequest(c, info.ObjectId, a)
+		if err != nil {
+			return err
+		}
+		// The vendor's URL builder inserts its own slash before the scope.
+		resp, err := client.RequestResourceAssignment(strings.TrimPrefix(a.Scope, "/"), req, token)
+		if err != nil {
+			return err
+		}
+		status := ""
+		if resp.Properties != nil {
+			status = resp.Properties.Status
+		}
+		return renderRequestResult(cmd, verb, status, a.Scope, a.Name, "", resp.Id)
+	}
+
+	graphToken, err := ts.GetAccessToken(azure.GraphScope())
+	if err != nil {
+		return err
+	}
+	info, err := pimvendor.GetUserInfo(graphToken)
+	if err != nil {
+		return err
+	}
+	var req *pimvendor.GovernanceRoleAssignmentRequest
+	if c.Action == "extend" {
+		system, number := ParseTicket(c.Ticket)
+		req, err = governanceExtendRequest(info.ObjectId, *a.governance, c.Duration, c.Justification, system, number)
+		if err != nil {
+			return err
+		}
+	} else {
+		req = governanceRemoveRequest(info.ObjectId, *a.governance, c.Justification)
+	}
+	resp, err := client.RequestGovernanceRoleAssignment(governanceKinds[a.Type].roleType, req, graphToken)
+	if err != nil {
+		return err
+	}
+	subStatus := ""
+	if resp.Status != nil {
+		subStatus = resp.Status.SubStatus
+	}
+	return renderRequestResult(cmd, verb, subStatus, "—", a.Name, resp.RoleAssignmentEndDateTime, resp.Id)
+}
+
+// resourceChangeRequest builds the SelfDeactivate or SelfExtend schedule
+// request for an active resource role. Both name the eligibility the
+// activation came from, as SelfActivate does.
+func resourceChangeRequest(c activationChange, principalID string, a activeAssignment) (*pimvendor.ResourceAssignmentRequestRequest, error) {
+	props := pimvendor.ResourceAssignmentRequestProperties{
+		PrincipalId:      principalID,
+		RoleDefinitionId: a.RoleDefinitionID,
+		RequestType:      "SelfDeactivate",
+		Justification:    c.Justification,
+	}
+	if a.resource != nil && a.resource.Properties != nil {
+		props.LinkedRoleEligibilityScheduleId = a.resource.Properties.LinkedRoleEligibilityScheduleId
+	}
+	if c.Action == "extend" {
+		schedule, err := pimvendor.CreateResourceAssignmentScheduleInfo(c.Duration, "", "")
+		if err != nil {
+			return nil, err
+		}
+		system, number := ParseTicket(c.Ticket)
+		props.RequestType = "SelfExtend"
+		props.ScheduleInfo = schedule
+		props.TicketInfo = &pimvendor.TicketInfo{TicketNumber: number, TicketSystem: system}
+	}
+	return &pimvendor.ResourceAssignmentRequestRequest{Properties: props}, nil
+}
@@ -0,0 +1,19 @@
+package pim
+
+import "github.com/spf13/cobra"
+
+func newExtendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extend",
+		Short: "Extend an active PIM activation",
+		Long: "Extend an active PIM activation to --duration minutes from now. The role's " +
+			"PIM policy still applies: extensions beyond its maximum duration are rejected, " +
+			"and roles that need approval to activate need it to extend.",
+	}
+	cmd.AddCommand(
+		newActivationChangeCmd("extend", "resource", "Extend an Azure resource role activation"),
+		newActivationChangeCmd("extend", "group", "Extend an Entra ID group membership activation"),
+		newActivationChangeCmd("extend", "role", "Extend an Entra ID directory role activation"),
+	)
+	return cmd
+}
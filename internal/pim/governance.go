@@ -0,0 +1,177 @@
+package pim
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pimvendor "github.com/cdobbyn/azure-go-cli/internal/pim/vendor"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+)
+
+// governanceKinds names the two assignment types served by the PIM
+// governance API: Entra group memberships and Entra directory roles. The
+// noun is what error messages call one of them.
+var governanceKinds = map[string]struct {
+	roleType string
+	noun     string
+}{
+	"group": {pimvendor.ROLE_TYPE_AAD_GROUPS, "group"},
+	"role":  {pimvendor.ROLE_TYPE_ENTRA_ROLES, "directory role"},
+}
+
+// activateGovernance activates the caller's eligible group membership or
+// directory role called name, validating the request first.
+func activateGovernance(cmd *cobra.Command, kind, name, justification, ticket string, duration int) error {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return fmt.Errorf("get credential: %w", err)
+	}
+	result, err := submitGovernanceActivation(NewTokenSource(cred), kind, name, justification, ticket, duration)
+	if err != nil {
+		return err
+	}
+	return renderActivationResult(cmd, result.Status, result.Scope, name, result.ExpiresAt, result.RequestID)
+}
+
+func submitGovernanceActivation(ts *TokenSource, kind, name, justification, ticket string, duration int) (activationResult, error) {
+	k := governanceKinds[kind]
+	graphToken, err := ts.GetAccessToken(azure.GraphScope())
+	if err != nil {
+		return activationResult{}, err
+	}
+
+	client := pimvendor.AzureClient{ARMBaseURL: azure.ARMEndpoint()}
+	info, err := pimvendor.GetUserInfo(graphToken)
+	if err != nil {
+		return activationResult{}, err
+	}
+
+	eligible, err := client.GetEligibleGovernanceRoleAssignments(k.roleType, info.ObjectId, graphToken)
+	if err != nil {
+		return activationResult{}, err
+	}
+	assignment, err := matchGovernanceAssignment(eligible.Value, name, k.noun)
+	if err != nil {
+		return activationResult{}, err
+	}
+
+	system, number := ParseTicket(ticket)
+	roleType, req, err := pimvendor.CreateGovernanceRoleAssignmentRequest(
+		info.ObjectId, k.roleType, &assignment,
+		duration, "", "", justification, system, number)
+	if err != nil {
+		return activationResult{}, err
+	}
+
+	ok, err := client.ValidateGovernanceRoleAssignmentRequest(roleType, req, graphToken)
+	if err != nil {
+		return activationResult{}, err
+	}
+	if !ok {
+		return activationResult{}, fmt.Errorf("Azure rejected the %s activation request during validation; check %s name and duration", k.noun, k.noun)
+	}
+
+	resp, err := client.RequestGovernanceRoleAssignment(roleType, req, graphToken)
+	if err != nil {
+		return activationResult{}, err
+	}
+
+	result := activationResult{Type: kind, Role: name, Scope: "—", ExpiresAt: resp.RoleAssignmentEndDateTime, RequestID: resp.Id}
+	if resp.Status != nil {
+		result.Status = resp.Status.SubStatus
+	}
+	return result, nil
+}
+
+// activeGovernanceAssignment is a governance role assignment in the Active
+// state. The vendored model only covers eligible assignments, which carry
+// neither an end time nor a link back to the eligibility.
+type activeGovernanceAssignment struct {
+	pimvendor.GovernanceRoleAssignment
+	StartDateTime                  string `json:"startDateTime"`
+	EndDateTime                    string `json:"endDateTime"`
+	LinkedEligibleRoleAssignmentId string `json:"linkedEligibleRoleAssignmentId"`
+}
+
+type activeGovernanceAssignmentResponse struct {
+	Value []activeGovernanceAssignment `json:"value"`
+}
+
+// getActiveGovernanceAssignments mirrors the vendored
+// GetEligibleGovernanceRoleAssignments with the Active state filter.
+func getActiveGovernanceAssignments(roleType, subjectID, token string) (*activeGovernanceAssignmentResponse, error) {
+	resp, err := pimvendor.Request(&pimvendor.PIMRequest{
+		Url:    fmt.Sprintf("%s/%s/%s/roleAssignments", pimvendor.AZ_RBAC_BASE_URL, pimvendor.AZ_RBAC_BASE_PATH, roleType),
+		Token:  token,
+		Method: "GET",
+		Params: map[string]string{
+			"$expand": "linkedEligibleRoleAssignment,subject,scopedResource,roleDefinition($expand=resource)",
+			"$filter": fmt.Sprintf("(subject/id eq '%s') and (assignmentState eq 'Active')", subjectID),
+		},
+	}, &activeGovernanceAssignmentResponse{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*activeGovernanceAssignmentResponse), nil
+}
+
+// matchGovernanceAssignment picks the eligible assignment whose role
+// definition (the group name, for groups) is name.
+func matchGovernanceAssignment(eligible []pimvendor.GovernanceRoleAssignment, name, noun string) (pimvendor.GovernanceRoleAssignment, error) {
+	var matches []pimvendor.GovernanceRoleAssignment
+	for _, e := range eligible {
+		if e.RoleDefinition != nil && e.RoleDefinition.DisplayName == name {
+			matches = append(matches, e)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return pimvendor.GovernanceRoleAssignment{}, fmt.Errorf("no eligible %s named %q", noun, name)
+	case 1:
+		return matches[0], nil
+	default:
+		var ids []string
+		for _, m := range matches {
+			if m.RoleDefinition != nil && m.RoleDefinition.Resource != nil {
+				ids = append(ids, m.RoleDefinition.Resource.Id)
+			}
+		}
+		return pimvendor.GovernanceRoleAssignment{}, fmt.Errorf("ambiguous %s name %q; candidates by id: %s", noun, name, strings.Join(ids, ", "))
+	}
+}
+
+// governanceRemoveRequest builds the request that ends an activation early.
+func governanceRemoveRequest(subjectID string, a activeGovernanceAssignment, reason string) *pimvendor.GovernanceRoleAssignmentRequest {
+	return &pimvendor.GovernanceRoleAssignmentRequest{
+		RoleDefinitionId:               a.RoleDefinitionId,
+		ResourceId:                     a.ResourceId,
+		SubjectId:                      subjectID,
+		AssignmentState:                "Active",
+		Type:                           "UserRemove",
+		Reason:                         reason,
+		LinkedEligibleRoleAssignmentId: a.LinkedEligibleRoleAssignmentId,
+	}
+}
+
+// governanceExtendRequest builds the request that pushes an activation's
+// end time out to duration minutes from now.
+func governanceExtendRequest(subjectID string, a activeGovernanceAssignment, duration int, reason, ticketSystem, ticketNumber string) (*pimvendor.GovernanceRoleAssignmentRequest, error) {
+	schedule, err := pimvendor.CreateGovernanceRoleAssignmentScheduleInfo(duration, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return &pimvendor.GovernanceRoleAssignmentRequest{
+		RoleDefinitionId:               a.RoleDefinitionId,
+		ResourceId:                     a.ResourceId,
+		SubjectId:                      subjectID,
+		AssignmentState:                "Active",
+		Type:                           "UserExtend",
+		Reason:                         reason,
+		TicketNumber:                   ticketNumber,
+		TicketSystem:                   ticketSystem,
+		Schedule:                       schedule,
+		LinkedEligibleRoleAssignmentId: a.LinkedEligibleRoleAssignmentId,
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -33,7 +34,7 @@ func newListCmd() *cobra.Command {
 			return runList(cmd, typeFilter)
 		},
 	}
-	cmd.Flags().String("type", "", "filter by type: resource or group")
+	cmd.Flags().String("type", "", "filter by type: resource, group or role")
 	return cmd
 }
 
@@ -50,7 +51,7 @@ func runList(cmd *cobra.Command, typeFilter string) error {
 		return fmt.Errorf("get credential: %w", err)
 	}
 	ts := NewTokenSource(cred)
-	client := pimvendor.AzureClient{ARMBaseURL: pimvendor.ARM_GLOBAL_BASE_URL}
+	client := pimvendor.AzureClient{ARMBaseURL: azure.ARMEndpoint()}
 
 	_ = ctx
 	rows, err := collectRows(ts, client, typeFilter)
@@ -76,7 +77,7 @@ func collectRows(ts *TokenSource, client pimvendor.AzureClient, typeFilter strin
 	var rows []ListRow
 
 	if typeFilter == "" || typeFilter == "resource" {
-		armToken, err := ts.GetAccessToken("https://management.azure.com/.default")
+		armToken, err := ts.GetAccessToken(azure.ARMScope())
 		if err != nil {
 			return nil, err
 		}
@@ -103,8 +104,11 @@ func collectRows(ts *TokenSource, client pimvendor.AzureClient, typeFilter strin
 		}
 	}
 
-	if typeFilter == "" || typeFilter == "group" {
-		graphToken, err := ts.GetAccessToken("https://graph.microsoft.com/.default")
+	for _, kind := range []string{"group", "role"} {
+		if typeFilter != "" && typeFilter != kind {
+			continue
+		}
+		graphToken, err := ts.GetAccessToken(azure.GraphScope())
 		if err != nil {
 			return nil, err
 		}
@@ -112,7 +116,7 @@ func collectRows(ts *TokenSource, client pimvendor.AzureClient, typeFilter strin
 		if err != nil {
 			return nil, err
 		}
-		resp, err := client.GetEligibleGovernanceRoleAssignments(pimvendor.ROLE_TYPE_AAD_GROUPS, info.ObjectId, graphToken)
+		resp, err := client.GetEligibleGovernanceRoleAssignments(governanceKinds[kind].roleType, info.ObjectId, graphToken)
 		if err != nil {
 			return nil, err
 		}
@@ -125,11 +129,11 @@ func collectRows(ts *TokenSource, client pimvendor.AzureClient, typeFilter strin
 				}
 			}
 			rows = append(rows, ListRow{
-				Type:         "group",
+				Type:         kind,
 				Tenant:       resolveTenantName(profile, tenantID),
 				Subscription: "—",
 				Name:         name,
-				Status:       formatStatus(a.AssignmentState, ""), // eligible groups have no expiry until activated
+				Status:       formatStatus(a.AssignmentState, ""), // eligible assignments have no expiry until activated
 			})
 		}
 	}
@@ -138,15 +142,37 @@ func collectRows(ts *TokenSource, client pimvendor.AzureClient, typeFilter strin
 }
 
 // formatStatus turns ("Eligible", "...") into "Eligible" and
-// ("Active", "2026-05-14T15:42:00Z") into "Active (expires 15:42 UTC)".
+// ("Active", "2026-05-14T15:42:00Z") into
+// "Active (expires 15:42 UTC, 1h05m left)".
 func formatStatus(state, endRFC3339 string) string {
+	return formatStatusAt(state, endRFC3339, time.Now())
+}
+
+func formatStatusAt(state, endRFC3339 string, now time.Time) string {
 	if !strings.EqualFold(state, "Active") || endRFC3339 == "" {
 		return state
 	}
-	if len(endRFC3339) >= 16 {
-		return fmt.Sprintf("Active (expires %s UTC)", endRFC3339[11:16])
+	end, err := time.Parse(time.RFC3339, endRFC3339)
+	if err != nil {
+		if len(endRFC3339) >= 16 {
+			return fmt.Sprintf("Active (expires %s UTC)", endRFC3339[11:16])
+		}
+		return state
+	}
+	return fmt.Sprintf("Active (expires %s UTC, %s)", end.UTC().Format("15:04"), formatRemaining(end.Sub(now)))
+}
+
+// formatRemaining renders the time left on an activation to the minute,
+// rounding up so an activation never reads "0m left" while still usable.
+func formatRemaining(d time.Duration) string {
+	if d <= 0 {
+		return "expired"
+	}
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm left", minutes)
 	}
-	return state
+	return fmt.Sprintf("%dh%02dm left", minutes/60, minutes%60)
 }
 
 func displayNameOrEmpty(p *pimvendor.ResourceExpandedProperty) string {
@@ -0,0 +1,299 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newAssignmentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "assignment",
+		Short: "Manage policy assignments",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Assign a policy or policy set definition to a scope",
+		Long: `Assign a policy or policy set definition to a scope.
+
+Policies that deploy or modify resources need a managed identity: give
+--mi-system-assigned or --mi-user-assigned with --location, and
+--identity-scope to grant it --role there.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateAssignment(context.Background(), cmd)
+		},
+	}
+	createCmd.Flags().StringP("name", "n", "", "Name of the assignment (generated if omitted)")
+	addScopeFlags(createCmd)
+	createCmd.Flags().String("policy", "", "Name or ID of the policy definition to assign")
+	createCmd.Flags().String("policy-set-definition", "", "Name or ID of the policy set definition to assign")
+	createCmd.MarkFlagsMutuallyExclusive("policy", "policy-set-definition")
+	addAssignmentFlags(createCmd)
+	addIdentityFlags(createCmd, "mi-system-assigned", "mi-user-assigned")
+	createCmd.Flags().StringP("location", "l", "", "Location of the assignment, required with a managed identity")
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return UpdateAssignment(context.Background(), cmd)
+		},
+	}
+	updateCmd.Flags().StringP("name", "n", "", "Name of the assignment")
+	updateCmd.MarkFlagRequired("name")
+	addScopeFlags(updateCmd)
+	addAssignmentFlags(updateCmd)
+	genericupdate.AddFlags(updateCmd)
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowAssignment(context.Background(), cmd)
+		},
+	}
+	addAssignmentNameFlags(showCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List policy assignments",
+		Long:  "List the policy assignments at a scope. Assignments inherited from above it or made below it are included with --disable-scope-strict-match.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			strict, _ := cmd.Flags().GetBool("disable-scope-strict-match")
+			return ListAssignments(context.Background(), cmd, !strict)
+		},
+	}
+	addScopeFlags(listCmd)
+	listCmd.Flags().Bool("disable-scope-strict-match", false, "Include assignments inherited from parent scopes and made at child scopes")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteAssignment(context.Background(), cmd)
+		},
+	}
+	addAssignmentNameFlags(deleteCmd)
+
+	cmd.AddCommand(createCmd, updateCmd, showCmd, listCmd, deleteCmd, newIdentityCmd(), newNonComplianceMessageCmd())
+	return cmd
+}
+
+// addAssignmentNameFlags registers the flags naming an existing assignment.
+func addAssignmentNameFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("name", "n", "", "Name of the assignment")
+	cmd.MarkFlagRequired("name")
+	addScopeFlags(cmd)
+}
+
+func addAssignmentFlags(cmd *cobra.Command) {
+	cmd.Flags().String("display-name", "", "Display name")
+	cmd.Flags().String("description", "", "Description")
+	cmd.Flags().String("params", "", "Parameter values ({\"name\": {\"value\": ...}}): JSON, or a path to a JSON file")
+	cmd.Flags().String("enforcement-mode", "", "Enforcement mode: Default or DoNotEnforce")
+	cmd.Flags().StringSlice("not-scopes", nil, "Scopes excluded from the assignment")
+}
+
+func applyAssignmentFlags(cmd *cobra.Command, props map[string]interface{}) error {
+	setString(cmd, props, "display-name", "displayName")
+	setString(cmd, props, "description", "description")
+	if err := setJSON(cmd, props, "params", "parameters"); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("enforcement-mode") {
+		mode, _ := cmd.Flags().GetString("enforcement-mode")
+		switch {
+		case strings.EqualFold(mode, "Default"):
+			props["enforcementMode"] = "Default"
+		case strings.EqualFold(mode, "DoNotEnforce"):
+			props["enforcementMode"] = "DoNotEnforce"
+		default:
+			return fmt.Errorf("invalid --enforcement-mode %q (expected Default or DoNotEnforce)", mode)
+		}
+	}
+	if cmd.Flags().Changed("not-scopes") {
+		notScopes, _ := cmd.Flags().GetStringSlice("not-scopes")
+		props["notScopes"] = notScopes
+	}
+	return nil
+}
+
+func CreateAssignment(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		// 24 characters is the limit at management group scope.
+		name = strings.ReplaceAll(uuid.New().String(), "-", "")[:24]
+	}
+	policy, _ := cmd.Flags().GetString("policy")
+	set, _ := cmd.Flags().GetString("policy-set-definition")
+	if policy == "" && set == "" {
+		return fmt.Errorf("please specify --policy or --policy-set-definition")
+	}
+	identity, err := identityFromFlags(cmd, "mi-system-assigned", "mi-user-assigned")
+	if err != nil {
+		return err
+	}
+	location, _ := cmd.Flags().GetString("location")
+	if identity != nil && location == "" {
+		return fmt.Errorf("--location is required when assigning a managed identity")
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	var definition string
+	if policy != "" {
+		definition, err = definitionID(ctx, c, policyDefinition, scope, policy)
+	} else {
+		definition, err = definitionID(ctx, c, policySetDefinition, scope, set)
+	}
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{}
+	props := properties(body)
+	props["policyDefinitionId"] = definition
+	if err := applyAssignmentFlags(cmd, props); err != nil {
+		return err
+	}
+	if identity != nil {
+		body["identity"] = identity
+		body["location"] = location
+	}
+	result, err := c.put(ctx, assignmentPath(scope, name), authorizationAPIVersion, body)
+	if err != nil {
+		return fmt.Errorf("failed to create policy assignment: %w", err)
+	}
+	if err := grantIdentityRole(ctx, cmd, c, result); err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func UpdateAssignment(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	ops, err := genericupdate.OpsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	path := assignmentPath(scope, name)
+	current, err := c.get(ctx, path, authorizationAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get policy assignment: %w", err)
+	}
+	if err := applyAssignmentFlags(cmd, properties(current)); err != nil {
+		return err
+	}
+	if err := genericupdate.Apply(current, ops); err != nil {
+		return err
+	}
+	result, err := c.put(ctx, path, authorizationAPIVersion, current)
+	if err != nil {
+		return fmt.Errorf("failed to update policy assignment: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+// getAssignment returns the client, path and current state of the
+// assignment named by -n and the scope flags.
+func getAssignment(ctx context.Context, cmd *cobra.Command) (*client, string, map[string]interface{}, error) {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	c, err := newClient()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	path := assignmentPath(scope, name)
+	current, err := c.get(ctx, path, authorizationAPIVersion)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to get policy assignment: %w", err)
+	}
+	return c, path, current, nil
+}
+
+func ShowAssignment(ctx context.Context, cmd *cobra.Command) error {
+	_, _, current, err := getAssignment(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, current)
+}
+
+func ListAssignments(ctx context.Context, cmd *cobra.Command, strict bool) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	if strict {
+		query.Set("$filter", "atScope()")
+	}
+	items, err := c.list(ctx, http.MethodGet, scope+authorizationProvider+"/policyAssignments", authorizationAPIVersion, query, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list policy assignments: %w", err)
+	}
+	if strict {
+		items = atExactScope(items, scope)
+	}
+	return output.PrintJSON(cmd, items)
+}
+
+// atExactScope keeps the items whose scope is scope. atScope() also
+// returns assignments inherited from parent scopes, which the Python CLI
+// leaves out unless --disable-scope-strict-match is given.
+func atExactScope(items []interface{}, scope string) []interface{} {
+	out := []interface{}{}
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		props, _ := m["properties"].(map[string]interface{})
+		if s, _ := props["scope"].(string); strings.EqualFold(s, scope) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func DeleteAssignment(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	if err := c.delete(ctx, assignmentPath(scope, name), authorizationAPIVersion); err != nil {
+		return fmt.Errorf("failed to delete policy assignment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/jsonclient"
+)
+
+// API versions of the two resource providers behind az policy.
+const (
+	authorizationAPIVersion = "2023-04-01"
+	exemptionAPIVersion     = "2022-07-01-preview"
+	statesAPIVersion        = "2019-10-01"
+	remediationAPIVersion   = "2021-10-01"
+)
+
+// client sends requests to ARM through the SDK pipeline (auth, retries,
+// request logging). There's no Go SDK for Microsoft.Authorization policy or
+// Microsoft.PolicyInsights among our dependencies, so the bodies are plain
+// JSON maps, which is also what gets printed.
+type client struct {
+	// arm pages with "nextLink"; insights serves the Policy Insights
+	// queries, which page with POST and "@odata.nextLink".
+	arm      *jsonclient.Client
+	insights *jsonclient.Client
+}
+
+func newClient() (*client, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+	c, err := arm.NewClient("github.com/cdobbyn/azure-go-cli/internal/policy", "", cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ARM client: %w", err)
+	}
+	return &client{
+		arm:      jsonclient.New(c.Pipeline(), azure.ARMEndpoint(), "nextLink"),
+		insights: jsonclient.New(c.Pipeline(), azure.ARMEndpoint(), "@odata.nextLink"),
+	}, nil
+}
+
+// withAPIVersion returns query plus api-version, leaving query untouched.
+func withAPIVersion(query url.Values, apiVersion string) url.Values {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	if apiVersion != "" {
+		q.Set("api-version", apiVersion)
+	}
+	return q
+}
+
+// do sends one request. path is an ARM path such as /subscriptions/{id}/...
+// or an absolute nextLink URL.
+func (c *client) do(ctx context.Context, method, path, apiVersion string, query url.Values, body interface{}) (map[string]interface{}, error) {
+	return c.arm.Do(ctx, method, path, withAPIVersion(query, apiVersion), body)
+}
+
+func (c *client) get(ctx context.Context, path, apiVersion string) (map[string]interface{}, error) {
+	return c.do(ctx, http.MethodGet, path, apiVersion, nil, nil)
+}
+
+func (c *client) put(ctx context.Context, path, apiVersion string, body interface{}) (map[string]interface{}, error) {
+	return c.do(ctx, http.MethodPut, path, apiVersion, nil, body)
+}
+
+func (c *client) delete(ctx context.Context, path, apiVersion string) error {
+	_, err := c.do(ctx, http.MethodDelete, path, apiVersion, nil, nil)
+	return err
+}
+
+// list collects the "value" arrays of every page. Policy Insights pages
+// with POST and "@odata.nextLink"; everything else with GET and "nextLink".
+// top, when positive, stops once that many items are in.
+func (c *client) list(ctx context.Context, method, path, apiVersion string, query url.Values, top int) ([]interface{}, error) {
+	pager := c.arm
+	if method == http.MethodPost {
+		pager = c.insights
+	}
+	return pager.List(ctx, method, path, withAPIVersion(query, apiVersion), top)
+}
+
+// isNotFound reports whether err is a 404 from ARM.
+func isNotFound(err error) bool {
+	return jsonclient.IsNotFound(err)
+}
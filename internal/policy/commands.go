@@ -0,0 +1,24 @@
+package policy
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewPolicyCommand wires `az policy`. Assignments, exemptions, states and
+// remediations take their scope the way `az lock` does: -g and the resource
+// flags, or --scope, plus --management-group.
+func NewPolicyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Manage Azure Policy definitions, assignments, exemptions and compliance",
+	}
+	cmd.AddCommand(
+		newDefinitionCmd(policyDefinition),
+		newDefinitionCmd(policySetDefinition),
+		newAssignmentCmd(),
+		newExemptionCmd(),
+		newStateCmd(),
+		newRemediationCmd(),
+	)
+	return cmd
+}
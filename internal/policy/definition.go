@@ -0,0 +1,288 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// definitionKind tells policy definitions and policy set definitions
+// apart. They share scopes and verbs, and differ only in their bodies.
+type definitionKind struct {
+	use          string
+	resourceType string
+	noun         string
+}
+
+var (
+	policyDefinition    = definitionKind{"definition", "policyDefinitions", "policy definition"}
+	policySetDefinition = definitionKind{"set-definition", "policySetDefinitions", "policy set definition"}
+)
+
+// path returns the definition's path at scope; an empty scope gives the
+// built-in definition of that name.
+func (k definitionKind) path(scope, name string) string {
+	return scope + authorizationProvider + "/" + k.resourceType + "/" + name
+}
+
+func newDefinitionCmd(k definitionKind) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   k.use,
+		Short: fmt.Sprintf("Manage %ss", k.noun),
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: fmt.Sprintf("Create a %s", k.noun),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateDefinition(context.Background(), cmd, k)
+		},
+	}
+	addDefinitionFlags(createCmd, k)
+	createCmd.MarkFlagRequired("name")
+	if k == policyDefinition {
+		createCmd.MarkFlagRequired("rules")
+	} else {
+		createCmd.MarkFlagRequired("definitions")
+	}
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: fmt.Sprintf("Update a %s", k.noun),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return UpdateDefinition(context.Background(), cmd, k)
+		},
+	}
+	addDefinitionFlags(updateCmd, k)
+	updateCmd.MarkFlagRequired("name")
+	genericupdate.AddFlags(updateCmd)
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: fmt.Sprintf("Show a %s", k.noun),
+		Long:  fmt.Sprintf("Show a custom %s, or a built-in one of that name if there is no custom one.", k.noun),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return ShowDefinition(context.Background(), cmd, k, name)
+		},
+	}
+	showCmd.Flags().StringP("name", "n", "", "Name of the "+k.noun)
+	showCmd.MarkFlagRequired("name")
+	addDefinitionScopeFlags(showCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: fmt.Sprintf("List %ss, built-in and custom", k.noun),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListDefinitions(context.Background(), cmd, k)
+		},
+	}
+	addDefinitionScopeFlags(listCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: fmt.Sprintf("Delete a %s", k.noun),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			return DeleteDefinition(context.Background(), cmd, k, name)
+		},
+	}
+	deleteCmd.Flags().StringP("name", "n", "", "Name of the "+k.noun)
+	deleteCmd.MarkFlagRequired("name")
+	addDefinitionScopeFlags(deleteCmd)
+
+	cmd.AddCommand(createCmd, updateCmd, showCmd, listCmd, deleteCmd)
+	return cmd
+}
+
+func addDefinitionFlags(cmd *cobra.Command, k definitionKind) {
+	cmd.Flags().StringP("name", "n", "", "Name of the "+k.noun)
+	addDefinitionScopeFlags(cmd)
+	cmd.Flags().String("display-name", "", "Display name")
+	cmd.Flags().String("description", "", "Description")
+	cmd.Flags().String("params", "", "Parameter definitions: JSON, or a path to a JSON file")
+	cmd.Flags().StringArray("metadata", nil, "Metadata as key=value. Repeatable")
+	if k == policyDefinition {
+		cmd.Flags().String("rules", "", "Policy rule: JSON, or a path to a JSON file")
+		cmd.Flags().String("mode", "", "Mode, e.g. All or Indexed")
+	} else {
+		cmd.Flags().String("definitions", "", "Policy definitions (policyDefinitionId, parameters, ...): JSON array, or a path to a JSON file")
+		cmd.Flags().String("definition-groups", "", "Policy definition groups: JSON array, or a path to a JSON file")
+	}
+}
+
+// applyDefinitionFlags copies the given flags into a definition's properties.
+func applyDefinitionFlags(cmd *cobra.Command, k definitionKind, props map[string]interface{}) error {
+	setString(cmd, props, "display-name", "displayName")
+	setString(cmd, props, "description", "description")
+	if err := setJSON(cmd, props, "params", "parameters"); err != nil {
+		return err
+	}
+	if err := mergeMetadata(cmd, props); err != nil {
+		return err
+	}
+	if k == policySetDefinition {
+		if err := setJSON(cmd, props, "definitions", "policyDefinitions"); err != nil {
+			return err
+		}
+		return setJSON(cmd, props, "definition-groups", "policyDefinitionGroups")
+	}
+	setString(cmd, props, "mode", "mode")
+	if err := setJSON(cmd, props, "rules", "policyRule"); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("rules") {
+		props["policyRule"] = unwrapRule(props["policyRule"])
+	}
+	return nil
+}
+
+// unwrapRule accepts a whole definition file where a rule is expected, as
+// exported from the portal or GitHub: the rule is its (properties.)policyRule.
+func unwrapRule(rule interface{}) interface{} {
+	m, ok := rule.(map[string]interface{})
+	if !ok {
+		return rule
+	}
+	if props, ok := m["properties"].(map[string]interface{}); ok {
+		m = props
+	}
+	if inner, ok := m["policyRule"]; ok {
+		return inner
+	}
+	return rule
+}
+
+func CreateDefinition(ctx context.Context, cmd *cobra.Command, k definitionKind) error {
+	scope, err := resolveDefinitionScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	body := map[string]interface{}{}
+	if err := applyDefinitionFlags(cmd, k, properties(body)); err != nil {
+		return err
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.put(ctx, k.path(scope, name), authorizationAPIVersion, body)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", k.noun, err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func UpdateDefinition(ctx context.Context, cmd *cobra.Command, k definitionKind) error {
+	scope, err := resolveDefinitionScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	ops, err := genericupdate.OpsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	path := k.path(scope, name)
+	current, err := c.get(ctx, path, authorizationAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", k.noun, err)
+	}
+	if err := applyDefinitionFlags(cmd, k, properties(current)); err != nil {
+		return err
+	}
+	if err := genericupdate.Apply(current, ops); err != nil {
+		return err
+	}
+	result, err := c.put(ctx, path, authorizationAPIVersion, current)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", k.noun, err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func ShowDefinition(ctx context.Context, cmd *cobra.Command, k definitionKind, name string) error {
+	scope, err := resolveDefinitionScope(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := getDefinition(ctx, c, k, scope, name)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+// getDefinition gets a custom definition at scope, falling back to the
+// built-in one of that name.
+func getDefinition(ctx context.Context, c *client, k definitionKind, scope, name string) (map[string]interface{}, error) {
+	result, err := c.get(ctx, k.path(scope, name), authorizationAPIVersion)
+	if isNotFound(err) {
+		result, err = c.get(ctx, k.path("", name), authorizationAPIVersion)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s '%s': %w", k.noun, name, err)
+	}
+	return result, nil
+}
+
+// definitionID resolves a definition name, looked up from scope, to its ID.
+// IDs are returned as they are.
+func definitionID(ctx context.Context, c *client, k definitionKind, scope, nameOrID string) (string, error) {
+	if strings.Contains(nameOrID, "/") {
+		return nameOrID, nil
+	}
+	def, err := getDefinition(ctx, c, k, definitionScopeOf(scope), nameOrID)
+	if err != nil {
+		return "", err
+	}
+	id, _ := def["id"].(string)
+	return id, nil
+}
+
+func ListDefinitions(ctx context.Context, cmd *cobra.Command, k definitionKind) error {
+	scope, err := resolveDefinitionScope(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	items, err := c.list(ctx, http.MethodGet, scope+authorizationProvider+"/"+k.resourceType, authorizationAPIVersion, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list %ss: %w", k.noun, err)
+	}
+	return output.PrintJSON(cmd, items)
+}
+
+func DeleteDefinition(ctx context.Context, cmd *cobra.Command, k definitionKind, name string) error {
+	scope, err := resolveDefinitionScope(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	if err := c.delete(ctx, k.path(scope, name), authorizationAPIVersion); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", k.noun, err)
+	}
+	return nil
+}
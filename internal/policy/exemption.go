@@ -0,0 +1,246 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func exemptionPath(scope, name string) string {
+	return scope + authorizationProvider + "/policyExemptions/" + name
+}
+
+func newExemptionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exemption",
+		Short: "Manage policy exemptions",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Exempt a scope from a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateExemption(context.Background(), cmd)
+		},
+	}
+	addExemptionNameFlags(createCmd)
+	createCmd.Flags().StringP("policy-assignment", "a", "", "ID of the policy assignment to exempt from")
+	createCmd.MarkFlagRequired("policy-assignment")
+	addExemptionFlags(createCmd)
+	createCmd.MarkFlagRequired("exemption-category")
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update a policy exemption",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return UpdateExemption(context.Background(), cmd)
+		},
+	}
+	addExemptionNameFlags(updateCmd)
+	addExemptionFlags(updateCmd)
+	genericupdate.AddFlags(updateCmd)
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a policy exemption",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowExemption(context.Background(), cmd)
+		},
+	}
+	addExemptionNameFlags(showCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List policy exemptions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			strict, _ := cmd.Flags().GetBool("disable-scope-strict-match")
+			return ListExemptions(context.Background(), cmd, !strict)
+		},
+	}
+	addScopeFlags(listCmd)
+	listCmd.Flags().Bool("disable-scope-strict-match", false, "Include exemptions that apply at the scope but were made elsewhere")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a policy exemption",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteExemption(context.Background(), cmd)
+		},
+	}
+	addExemptionNameFlags(deleteCmd)
+
+	cmd.AddCommand(createCmd, updateCmd, showCmd, listCmd, deleteCmd)
+	return cmd
+}
+
+func addExemptionNameFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("name", "n", "", "Name of the exemption")
+	cmd.MarkFlagRequired("name")
+	addScopeFlags(cmd)
+}
+
+func addExemptionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("exemption-category", "e", "", "Category: Waiver or Mitigated")
+	cmd.Flags().StringSlice("policy-definition-reference-ids", nil, "For a policy set assignment, the definition reference IDs to exempt from (all if omitted)")
+	cmd.Flags().String("expires-on", "", "Expiry as an RFC 3339 time or a date, e.g. 2026-12-31T23:00:00Z")
+	cmd.Flags().String("display-name", "", "Display name")
+	cmd.Flags().String("description", "", "Description")
+	cmd.Flags().StringArray("metadata", nil, "Metadata as key=value. Repeatable")
+}
+
+func applyExemptionFlags(cmd *cobra.Command, props map[string]interface{}) error {
+	if cmd.Flags().Changed("exemption-category") {
+		category, _ := cmd.Flags().GetString("exemption-category")
+		switch {
+		case strings.EqualFold(category, "Waiver"):
+			props["exemptionCategory"] = "Waiver"
+		case strings.EqualFold(category, "Mitigated"):
+			props["exemptionCategory"] = "Mitigated"
+		default:
+			return fmt.Errorf("invalid --exemption-category %q (expected Waiver or Mitigated)", category)
+		}
+	}
+	if cmd.Flags().Changed("policy-definition-reference-ids") {
+		ids, _ := cmd.Flags().GetStringSlice("policy-definition-reference-ids")
+		props["policyDefinitionReferenceIds"] = ids
+	}
+	if cmd.Flags().Changed("expires-on") {
+		raw, _ := cmd.Flags().GetString("expires-on")
+		expires, err := parseTime(raw)
+		if err != nil {
+			return fmt.Errorf("invalid --expires-on: %w", err)
+		}
+		props["expiresOn"] = expires.UTC().Format(time.RFC3339)
+	}
+	setString(cmd, props, "display-name", "displayName")
+	setString(cmd, props, "description", "description")
+	return mergeMetadata(cmd, props)
+}
+
+// parseTime accepts an RFC 3339 time or a bare date, which means midnight UTC.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func CreateExemption(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	assignment, _ := cmd.Flags().GetString("policy-assignment")
+	if !strings.HasPrefix(assignment, "/") {
+		return fmt.Errorf("--policy-assignment must be the ID of the policy assignment")
+	}
+
+	body := map[string]interface{}{}
+	props := properties(body)
+	props["policyAssignmentId"] = assignment
+	if err := applyExemptionFlags(cmd, props); err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.put(ctx, exemptionPath(scope, name), exemptionAPIVersion, body)
+	if err != nil {
+		return fmt.Errorf("failed to create policy exemption: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func UpdateExemption(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	ops, err := genericupdate.OpsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	path := exemptionPath(scope, name)
+	current, err := c.get(ctx, path, exemptionAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get policy exemption: %w", err)
+	}
+	if err := applyExemptionFlags(cmd, properties(current)); err != nil {
+		return err
+	}
+	if err := genericupdate.Apply(current, ops); err != nil {
+		return err
+	}
+	result, err := c.put(ctx, path, exemptionAPIVersion, current)
+	if err != nil {
+		return fmt.Errorf("failed to update policy exemption: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func ShowExemption(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.get(ctx, exemptionPath(scope, name), exemptionAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get policy exemption: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func ListExemptions(ctx context.Context, cmd *cobra.Command, strict bool) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	if strict {
+		query.Set("$filter", "atScope()")
+	}
+	items, err := c.list(ctx, http.MethodGet, scope+authorizationProvider+"/policyExemptions", exemptionAPIVersion, query, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list policy exemptions: %w", err)
+	}
+	return output.PrintJSON(cmd, items)
+}
+
+func DeleteExemption(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	if err := c.delete(ctx, exemptionPath(scope, name), exemptionAPIVersion); err != nil {
+		return fmt.Errorf("failed to delete policy exemption: %w", err)
+	}
+	return nil
+}
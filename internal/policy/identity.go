@@ -0,0 +1,170 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v3"
+	roleassignment "github.com/cdobbyn/azure-go-cli/internal/role/assignment"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newIdentityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identity",
+		Short: "Manage the managed identity of a policy assignment",
+	}
+
+	assignCmd := &cobra.Command{
+		Use:   "assign",
+		Short: "Add a system- or user-assigned identity to a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return AssignIdentity(context.Background(), cmd)
+		},
+	}
+	addAssignmentNameFlags(assignCmd)
+	addIdentityFlags(assignCmd, "system-assigned", "user-assigned")
+	assignCmd.Flags().StringP("location", "l", "", "Location of the assignment, if it doesn't have one yet")
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the managed identity of a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, _, current, err := getAssignment(context.Background(), cmd)
+			if err != nil {
+				return err
+			}
+			return output.PrintJSON(cmd, identityOf(current))
+		},
+	}
+	addAssignmentNameFlags(showCmd)
+
+	removeCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove the managed identity of a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RemoveIdentity(context.Background(), cmd)
+		},
+	}
+	addAssignmentNameFlags(removeCmd)
+
+	cmd.AddCommand(assignCmd, showCmd, removeCmd)
+	return cmd
+}
+
+// addIdentityFlags registers the identity flags under the given names:
+// create uses --mi-system-assigned, identity assign --system-assigned.
+func addIdentityFlags(cmd *cobra.Command, systemFlag, userFlag string) {
+	cmd.Flags().Bool(systemFlag, false, "Give the assignment a system-assigned managed identity")
+	cmd.Flags().String(userFlag, "", "Resource ID of a user-assigned managed identity to give the assignment")
+	cmd.MarkFlagsMutuallyExclusive(systemFlag, userFlag)
+	cmd.Flags().String("identity-scope", "", "Scope at which to grant the identity --role")
+	cmd.Flags().String("role", "Contributor", "Role to grant the identity at --identity-scope")
+}
+
+// identityFromFlags returns the identity object the flags ask for, or nil.
+func identityFromFlags(cmd *cobra.Command, systemFlag, userFlag string) (map[string]interface{}, error) {
+	system, _ := cmd.Flags().GetBool(systemFlag)
+	user, _ := cmd.Flags().GetString(userFlag)
+	scope, _ := cmd.Flags().GetString("identity-scope")
+	switch {
+	case system:
+		return map[string]interface{}{"type": "SystemAssigned"}, nil
+	case user != "":
+		return map[string]interface{}{
+			"type":                   "UserAssigned",
+			"userAssignedIdentities": map[string]interface{}{user: map[string]interface{}{}},
+		}, nil
+	case scope != "":
+		return nil, fmt.Errorf("--identity-scope requires --%s or --%s", systemFlag, userFlag)
+	}
+	return nil, nil
+}
+
+func identityOf(assignment map[string]interface{}) map[string]interface{} {
+	identity, _ := assignment["identity"].(map[string]interface{})
+	if identity == nil {
+		identity = map[string]interface{}{}
+	}
+	return identity
+}
+
+// principalID returns the object ID of an assignment's identity.
+func principalID(assignment map[string]interface{}) string {
+	identity := identityOf(assignment)
+	if id, _ := identity["principalId"].(string); id != "" {
+		return id
+	}
+	users, _ := identity["userAssignedIdentities"].(map[string]interface{})
+	for _, u := range users {
+		if m, ok := u.(map[string]interface{}); ok {
+			if id, _ := m["principalId"].(string); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// grantIdentityRole grants the assignment's identity --role at
+// --identity-scope, when given.
+func grantIdentityRole(ctx context.Context, cmd *cobra.Command, c *client, assignment map[string]interface{}) error {
+	scope, _ := cmd.Flags().GetString("identity-scope")
+	if scope == "" {
+		return nil
+	}
+	role, _ := cmd.Flags().GetString("role")
+	principal := principalID(assignment)
+	if principal == "" {
+		return fmt.Errorf("the policy assignment has no managed identity to grant '%s' to", role)
+	}
+	_, err := roleassignment.AssignNewPrincipal(ctx, scope, principal, to.Ptr(armauthorization.PrincipalTypeServicePrincipal), role)
+	if err != nil {
+		return fmt.Errorf("failed to grant the assignment's identity '%s' at %s: %w", role, scope, err)
+	}
+	return nil
+}
+
+func AssignIdentity(ctx context.Context, cmd *cobra.Command) error {
+	identity, err := identityFromFlags(cmd, "system-assigned", "user-assigned")
+	if err != nil {
+		return err
+	}
+	if identity == nil {
+		return fmt.Errorf("please specify --system-assigned or --user-assigned")
+	}
+	c, path, current, err := getAssignment(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	if location, _ := cmd.Flags().GetString("location"); location != "" {
+		current["location"] = location
+	}
+	if current["location"] == nil {
+		return fmt.Errorf("--location is required: the policy assignment has none")
+	}
+	current["identity"] = identity
+	result, err := c.put(ctx, path, authorizationAPIVersion, current)
+	if err != nil {
+		return fmt.Errorf("failed to update policy assignment: %w", err)
+	}
+	if err := grantIdentityRole(ctx, cmd, c, result); err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, identityOf(result))
+}
+
+func RemoveIdentity(ctx context.Context, cmd *cobra.Command) error {
+	c, path, current, err := getAssignment(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	current["identity"] = map[string]interface{}{"type": "None"}
+	result, err := c.put(ctx, path, authorizationAPIVersion, current)
+	if err != nil {
+		return fmt.Errorf("failed to update policy assignment: %w", err)
+	}
+	return output.PrintJSON(cmd, identityOf(result))
+}
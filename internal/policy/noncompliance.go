@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newNonComplianceMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "non-compliance-message",
+		Short: "Manage the messages shown when a policy assignment denies a request",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Add a non-compliance message to a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateNonComplianceMessage(context.Background(), cmd)
+		},
+	}
+	addAssignmentNameFlags(createCmd)
+	createCmd.Flags().StringP("message", "m", "", "Message shown when a resource is denied or found non-compliant")
+	createCmd.Flags().StringP("policy-definition-reference-id", "r", "", "For a policy set, the definition reference ID the message applies to (all definitions if omitted)")
+	createCmd.MarkFlagRequired("message")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the non-compliance messages of a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, _, current, err := getAssignment(context.Background(), cmd)
+			if err != nil {
+				return err
+			}
+			return output.PrintJSON(cmd, nonComplianceMessages(current))
+		},
+	}
+	addAssignmentNameFlags(listCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Remove non-compliance messages from a policy assignment",
+		Long:  "Remove the non-compliance messages with the given text and definition reference ID.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteNonComplianceMessage(context.Background(), cmd)
+		},
+	}
+	addAssignmentNameFlags(deleteCmd)
+	deleteCmd.Flags().StringP("message", "m", "", "Text of the message to remove")
+	deleteCmd.Flags().StringP("policy-definition-reference-id", "r", "", "Definition reference ID of the message to remove")
+	deleteCmd.MarkFlagRequired("message")
+
+	cmd.AddCommand(createCmd, listCmd, deleteCmd)
+	return cmd
+}
+
+func nonComplianceMessages(assignment map[string]interface{}) []interface{} {
+	messages, _ := properties(assignment)["nonComplianceMessages"].([]interface{})
+	if messages == nil {
+		messages = []interface{}{}
+	}
+	return messages
+}
+
+func CreateNonComplianceMessage(ctx context.Context, cmd *cobra.Command) error {
+	c, path, current, err := getAssignment(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	text, _ := cmd.Flags().GetString("message")
+	message := map[string]interface{}{"message": text}
+	if ref, _ := cmd.Flags().GetString("policy-definition-reference-id"); ref != "" {
+		message["policyDefinitionReferenceId"] = ref
+	}
+	properties(current)["nonComplianceMessages"] = append(nonComplianceMessages(current), message)
+
+	result, err := c.put(ctx, path, authorizationAPIVersion, current)
+	if err != nil {
+		return fmt.Errorf("failed to update policy assignment: %w", err)
+	}
+	return output.PrintJSON(cmd, nonComplianceMessages(result))
+}
+
+func DeleteNonComplianceMessage(ctx context.Context, cmd *cobra.Command) error {
+	c, path, current, err := getAssignment(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	text, _ := cmd.Flags().GetString("message")
+	ref, _ := cmd.Flags().GetString("policy-definition-reference-id")
+
+	kept := []interface{}{}
+	for _, m := range nonComplianceMessages(current) {
+		msg, _ := m.(map[string]interface{})
+		gotText, _ := msg["message"].(string)
+		gotRef, _ := msg["policyDefinitionReferenceId"].(string)
+		if gotText == text && strings.EqualFold(gotRef, ref) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	if len(kept) == len(nonComplianceMessages(current)) {
+		return fmt.Errorf("no non-compliance message %q found", text)
+	}
+	properties(current)["nonComplianceMessages"] = kept
+
+	result, err := c.put(ctx, path, authorizationAPIVersion, current)
+	if err != nil {
+		return fmt.Errorf("failed to update policy assignment: %w", err)
+	}
+	return output.PrintJSON(cmd, nonComplianceMessages(result))
+}
@@ -0,0 +1,201 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func remediationPath(scope, name string) string {
+	return scope + insightsProvider + "/remediations/" + name
+}
+
+func newRemediationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remediation",
+		Short: "Remediate resources that are non-compliant with deployIfNotExists or modify policies",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Start remediating the non-compliant resources of a policy assignment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateRemediation(context.Background(), cmd)
+		},
+	}
+	addRemediationNameFlags(createCmd)
+	createCmd.Flags().StringP("policy-assignment", "a", "", "Name or ID of the policy assignment to remediate")
+	createCmd.MarkFlagRequired("policy-assignment")
+	createCmd.Flags().String("definition-reference-id", "", "For a policy set assignment, the reference ID of the definition to remediate")
+	createCmd.Flags().String("resource-discovery-mode", "", "ExistingNonCompliant (default) or ReEvaluateCompliance")
+	createCmd.Flags().StringSlice("location-filters", nil, "Only remediate resources in these locations")
+	createCmd.Flags().Int("resource-count", 0, "Maximum number of resources to remediate")
+	createCmd.Flags().Int("parallel-deployments", 0, "Number of resources to remediate at a time")
+	createCmd.Flags().Float64("failure-threshold", 0, "Fraction of failed deployments (0 to 1) at which to stop")
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a remediation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ShowRemediation(context.Background(), cmd)
+		},
+	}
+	addRemediationNameFlags(showCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List remediations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListRemediations(context.Background(), cmd)
+		},
+	}
+	addScopeFlags(listCmd)
+
+	cancelCmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a running remediation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CancelRemediation(context.Background(), cmd)
+		},
+	}
+	addRemediationNameFlags(cancelCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a remediation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeleteRemediation(context.Background(), cmd)
+		},
+	}
+	addRemediationNameFlags(deleteCmd)
+
+	cmd.AddCommand(createCmd, showCmd, listCmd, cancelCmd, deleteCmd)
+	return cmd
+}
+
+func addRemediationNameFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("name", "n", "", "Name of the remediation")
+	cmd.MarkFlagRequired("name")
+	addScopeFlags(cmd)
+}
+
+func CreateRemediation(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	assignment, _ := cmd.Flags().GetString("policy-assignment")
+
+	body := map[string]interface{}{}
+	props := properties(body)
+	props["policyAssignmentId"] = assignmentID(scope, assignment)
+	setString(cmd, props, "definition-reference-id", "policyDefinitionReferenceId")
+	if cmd.Flags().Changed("resource-discovery-mode") {
+		mode, _ := cmd.Flags().GetString("resource-discovery-mode")
+		switch {
+		case strings.EqualFold(mode, "ExistingNonCompliant"):
+			props["resourceDiscoveryMode"] = "ExistingNonCompliant"
+		case strings.EqualFold(mode, "ReEvaluateCompliance"):
+			props["resourceDiscoveryMode"] = "ReEvaluateCompliance"
+		default:
+			return fmt.Errorf("invalid --resource-discovery-mode %q (expected ExistingNonCompliant or ReEvaluateCompliance)", mode)
+		}
+	}
+	if locations, _ := cmd.Flags().GetStringSlice("location-filters"); len(locations) > 0 {
+		props["filters"] = map[string]interface{}{"locations": locations}
+	}
+	if n, _ := cmd.Flags().GetInt("resource-count"); n > 0 {
+		props["resourceCount"] = n
+	}
+	if n, _ := cmd.Flags().GetInt("parallel-deployments"); n > 0 {
+		props["parallelDeployments"] = n
+	}
+	if cmd.Flags().Changed("failure-threshold") {
+		threshold, _ := cmd.Flags().GetFloat64("failure-threshold")
+		if threshold < 0 || threshold > 1 {
+			return fmt.Errorf("--failure-threshold must be between 0 and 1")
+		}
+		props["failureThreshold"] = map[string]interface{}{"percentage": threshold}
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.put(ctx, remediationPath(scope, name), remediationAPIVersion, body)
+	if err != nil {
+		return fmt.Errorf("failed to create remediation: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func ShowRemediation(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.get(ctx, remediationPath(scope, name), remediationAPIVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get remediation: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func ListRemediations(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	items, err := c.list(ctx, http.MethodGet, scope+insightsProvider+"/remediations", remediationAPIVersion, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list remediations: %w", err)
+	}
+	return output.PrintJSON(cmd, items)
+}
+
+func CancelRemediation(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.do(ctx, http.MethodPost, remediationPath(scope, name)+"/cancel", remediationAPIVersion, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to cancel remediation: %w", err)
+	}
+	return output.PrintJSON(cmd, result)
+}
+
+func DeleteRemediation(ctx context.Context, cmd *cobra.Command) error {
+	scope, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	if err := c.delete(ctx, remediationPath(scope, name), remediationAPIVersion); err != nil {
+		return fmt.Errorf("failed to delete remediation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/internal/lock"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	authorizationProvider = "/providers/Microsoft.Authorization"
+	insightsProvider      = "/providers/Microsoft.PolicyInsights"
+	managementGroupPrefix = "/providers/Microsoft.Management/managementGroups/"
+)
+
+// addScopeFlags registers the flags naming the scope an assignment,
+// exemption, state query or remediation applies to: --management-group,
+// --scope, or the resource group and resource flags az lock uses.
+func addScopeFlags(cmd *cobra.Command) {
+	lock.AddScopeFlags(cmd)
+	cmd.Flags().String("scope", "", "Scope, e.g. /subscriptions/{id}/resourceGroups/{rg} or /providers/Microsoft.Management/managementGroups/{id}. Can't be combined with the other scope flags")
+	cmd.Flags().String("management-group", "", "Management group name, for management group scope")
+}
+
+// resolveScope returns the ARM scope named by the addScopeFlags flags,
+// defaulting to the subscription.
+func resolveScope(cmd *cobra.Command) (string, error) {
+	mg, _ := cmd.Flags().GetString("management-group")
+	if mg == "" {
+		return lock.ResolveScopePath(cmd)
+	}
+	for _, name := range []string{"scope", "resource-group", "resource"} {
+		if v, _ := cmd.Flags().GetString(name); v != "" {
+			return "", fmt.Errorf("cannot mix --management-group with --%s", name)
+		}
+	}
+	return managementGroupPrefix + mg, nil
+}
+
+// addDefinitionScopeFlags registers the flags of commands on definitions
+// and set definitions, which live in a subscription or management group.
+func addDefinitionScopeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("management-group", "", "Management group the definition is in. Defaults to the subscription")
+}
+
+func resolveDefinitionScope(cmd *cobra.Command) (string, error) {
+	if mg, _ := cmd.Flags().GetString("management-group"); mg != "" {
+		return managementGroupPrefix + mg, nil
+	}
+	sub, _ := cmd.Flags().GetString("subscription")
+	subscriptionID, err := config.GetSubscription(sub)
+	if err != nil {
+		return "", fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return "/subscriptions/" + subscriptionID, nil
+}
+
+// definitionScopeOf returns the subscription or management group that
+// contains scope: where definitions referenced from it are looked up.
+func definitionScopeOf(scope string) string {
+	parts := strings.Split(strings.Trim(scope, "/"), "/")
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "subscriptions") {
+		return "/subscriptions/" + parts[1]
+	}
+	if len(parts) >= 4 && strings.EqualFold(parts[0], "providers") && strings.EqualFold(parts[1], "Microsoft.Management") {
+		return managementGroupPrefix + parts[3]
+	}
+	return scope
+}
+
+func assignmentPath(scope, name string) string {
+	return scope + authorizationProvider + "/policyAssignments/" + name
+}
+
+// assignmentID returns nameOrID as a policy assignment ID, taking a bare
+// name to be an assignment at scope.
+func assignmentID(scope, nameOrID string) string {
+	if strings.Contains(nameOrID, "/") {
+		return nameOrID
+	}
+	return assignmentPath(scope, nameOrID)
+}
+
+// readJSON reads a flag value that is inline JSON, a file path or @path.
+func readJSON(flag, value string) (interface{}, error) {
+	data := []byte(value)
+	path := strings.TrimPrefix(value, "@")
+	if strings.HasPrefix(value, "@") || !strings.HasPrefix(strings.TrimSpace(value), "{") && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		d, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: expected JSON or a JSON file: %w", flag, err)
+		}
+		data = d
+	}
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("--%s: failed to parse JSON: %w", flag, err)
+	}
+	return v, nil
+}
+
+// parseMetadata turns key=value pairs into a metadata object.
+func parseMetadata(pairs []string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("--metadata %q: expected key=value", p)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// properties returns obj's "properties" object, creating it if needed.
+func properties(obj map[string]interface{}) map[string]interface{} {
+	props, ok := obj["properties"].(map[string]interface{})
+	if !ok {
+		props = map[string]interface{}{}
+		obj["properties"] = props
+	}
+	return props
+}
+
+// setString copies a string flag into props[key] when it was given.
+func setString(cmd *cobra.Command, props map[string]interface{}, flag, key string) {
+	if cmd.Flags().Changed(flag) {
+		v, _ := cmd.Flags().GetString(flag)
+		props[key] = v
+	}
+}
+
+// setJSON copies a JSON flag into props[key] when it was given.
+func setJSON(cmd *cobra.Command, props map[string]interface{}, flag, key string) error {
+	if !cmd.Flags().Changed(flag) {
+		return nil
+	}
+	raw, _ := cmd.Flags().GetString(flag)
+	v, err := readJSON(flag, raw)
+	if err != nil {
+		return err
+	}
+	props[key] = v
+	return nil
+}
+
+// mergeMetadata adds --metadata to props["metadata"], keeping other keys.
+func mergeMetadata(cmd *cobra.Command, props map[string]interface{}) error {
+	if !cmd.Flags().Changed("metadata") {
+		return nil
+	}
+	pairs, _ := cmd.Flags().GetStringArray("metadata")
+	metadata, err := parseMetadata(pairs)
+	if err != nil {
+		return err
+	}
+	existing, _ := props["metadata"].(map[string]interface{})
+	if existing == nil {
+		existing = map[string]interface{}{}
+	}
+	for k, v := range metadata {
+		existing[k] = v
+	}
+	props["metadata"] = existing
+	return nil
+}
@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func scopeCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AZ_SESSION", "")
+	os.MkdirAll(filepath.Join(home, ".azure"), 0700)
+	os.WriteFile(filepath.Join(home, ".azure", "azureProfile.json"), []byte(`{"subscriptions": [{"id": "sub-id", "name": "Dev", "isDefault": true}]}`), 0600)
+
+	cmd := &cobra.Command{Use: "list"}
+	cmd.Flags().String("subscription", "", "")
+	addStateFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestResolveScope(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "/subscriptions/sub-id"},
+		{[]string{"-g", "rg1"}, "/subscriptions/sub-id/resourceGroups/rg1"},
+		{[]string{"--management-group", "mg1"}, "/providers/Microsoft.Management/managementGroups/mg1"},
+		{[]string{"--scope", "/subscriptions/x/resourceGroups/y"}, "/subscriptions/x/resourceGroups/y"},
+	}
+	for _, tt := range tests {
+		got, err := resolveScope(scopeCommand(t, tt.args...))
+		if err != nil || got != tt.want {
+			t.Errorf("%v: got %q, %v", tt.args, got, err)
+		}
+	}
+	if _, err := resolveScope(scopeCommand(t, "--management-group", "mg1", "-g", "rg1")); err == nil || !strings.Contains(err.Error(), "cannot mix --management-group with --resource-group") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestStatesBase(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-g", "rg1"}, "/subscriptions/sub-id/resourceGroups/rg1"},
+		{[]string{"-a", "audit-vms"}, "/subscriptions/sub-id/providers/Microsoft.Authorization/policyAssignments/audit-vms"},
+		{[]string{"-g", "rg1", "--policy-definition", "custom"}, "/subscriptions/sub-id/providers/Microsoft.Authorization/policyDefinitions/custom"},
+		{[]string{"--management-group", "mg1", "--policy-set-definition", "baseline"}, "/providers/Microsoft.Management/managementGroups/mg1/providers/Microsoft.Authorization/policySetDefinitions/baseline"},
+		{[]string{"--policy-definition", "/providers/Microsoft.Authorization/policyDefinitions/abc"}, "/providers/Microsoft.Authorization/policyDefinitions/abc"},
+	}
+	for _, tt := range tests {
+		got, err := statesBase(scopeCommand(t, tt.args...))
+		if err != nil || got != tt.want {
+			t.Errorf("%v: got %q, %v", tt.args, got, err)
+		}
+	}
+}
+
+func TestUnwrapRule(t *testing.T) {
+	rule := map[string]interface{}{"if": map[string]interface{}{"field": "type"}, "then": map[string]interface{}{"effect": "audit"}}
+	for _, in := range []interface{}{
+		rule,
+		map[string]interface{}{"policyRule": rule, "mode": "All"},
+		map[string]interface{}{"properties": map[string]interface{}{"policyRule": rule}},
+	} {
+		if got := unwrapRule(in); !reflect.DeepEqual(got, rule) {
+			t.Errorf("unwrapRule(%v) = %v", in, got)
+		}
+	}
+}
+
+func TestAtExactScope(t *testing.T) {
+	item := func(scope string) interface{} {
+		return map[string]interface{}{"properties": map[string]interface{}{"scope": scope}}
+	}
+	items := []interface{}{item("/subscriptions/s"), item("/providers/Microsoft.Management/managementGroups/root"), item("/SUBSCRIPTIONS/s")}
+	if got := atExactScope(items, "/subscriptions/s"); len(got) != 2 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestPrincipalID(t *testing.T) {
+	system := map[string]interface{}{"identity": map[string]interface{}{"type": "SystemAssigned", "principalId": "p1"}}
+	user := map[string]interface{}{"identity": map[string]interface{}{
+		"type":                   "UserAssigned",
+		"userAssignedIdentities": map[string]interface{}{"/subscriptions/s/.../id1": map[string]interface{}{"principalId": "p2"}},
+	}}
+	if got := principalID(system); got != "p1" {
+		t.Errorf("system: %q", got)
+	}
+	if got := principalID(user); got != "p2" {
+		t.Errorf("user: %q", got)
+	}
+	if got := principalID(map[string]interface{}{}); got != "" {
+		t.Errorf("none: %q", got)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rule.json")
+	os.WriteFile(path, []byte(`{"if": {"field": "location", "notIn": ["westeurope"]}}`), 0600)
+	for _, arg := range []string{path, "@" + path, `{"if": {"field": "location", "notIn": ["westeurope"]}}`} {
+		v, err := readJSON("rules", arg)
+		if err != nil {
+			t.Fatalf("%s: %v", arg, err)
+		}
+		if _, ok := v.(map[string]interface{})["if"]; !ok {
+			t.Errorf("%s: got %v", arg, v)
+		}
+	}
+	if _, err := readJSON("rules", "missing.json"); err == nil || !strings.Contains(err.Error(), "--rules") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName str
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewServerThreatProtectionSettingsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewServerThreatProtectionSettingsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create threat protection settings client: %w", err)
 	}
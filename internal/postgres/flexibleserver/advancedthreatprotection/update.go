@@ -32,7 +32,7 @@ func Update(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName,
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewServerThreatProtectionSettingsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewServerThreatProtectionSettingsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create threat protection settings client: %w", err)
 	}
@@ -22,7 +22,7 @@ func Create(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName,
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewBackupsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewBackupsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create backups client: %w", err)
 	}
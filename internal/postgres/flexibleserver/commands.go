@@ -17,9 +17,13 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/postgres/flexibleserver/replica"
 	"github.com/cdobbyn/azure-go-cli/internal/postgres/flexibleserver/serverlogs"
 	"github.com/cdobbyn/azure-go-cli/internal/postgres/flexibleserver/virtualendpoint"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
+const serverResourceType = "Microsoft.DBforPostgreSQL/flexibleServers"
+
 func NewFlexibleServerCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "flexible-server",
@@ -41,15 +45,10 @@ func NewFlexibleServerCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a PostgreSQL flexible server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			serverName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, serverName, resourceGroup)
+			return bulk.Run(cmd, serverResourceType, Show)
 		},
 	}
-	showCmd.Flags().StringP("name", "n", "", "Server name")
-	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	addServerTargetFlags(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -92,17 +91,14 @@ func NewFlexibleServerCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a PostgreSQL flexible server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, serverResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
-	deleteCmd.Flags().StringP("name", "n", "", "Server name")
-	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addServerTargetFlags(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	listSkusCmd := &cobra.Command{
 		Use:   "list-skus",
@@ -170,63 +166,54 @@ func NewFlexibleServerCommand() *cobra.Command {
 		Use:   "start",
 		Short: "Start a stopped PostgreSQL flexible server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			rg, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Start(context.Background(), cmd, name, rg, noWait)
+			return bulk.Run(cmd, serverResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Start(ctx, t, noWait)
+			})
 		},
 	}
-	startCmd.Flags().StringP("name", "n", "", "Server name")
-	startCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addServerTargetFlags(startCmd)
 	startCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	startCmd.MarkFlagRequired("name")
-	startCmd.MarkFlagRequired("resource-group")
 
 	stopCmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop a running PostgreSQL flexible server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			rg, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Stop(context.Background(), cmd, name, rg, noWait)
+			return bulk.Run(cmd, serverResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Stop(ctx, t, noWait)
+			})
 		},
 	}
-	stopCmd.Flags().StringP("name", "n", "", "Server name")
-	stopCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addServerTargetFlags(stopCmd)
 	stopCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	stopCmd.MarkFlagRequired("name")
-	stopCmd.MarkFlagRequired("resource-group")
 
 	restartCmd := &cobra.Command{
 		Use:   "restart",
 		Short: "Restart a PostgreSQL flexible server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			rg, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Restart(context.Background(), cmd, name, rg, noWait)
+			return bulk.Run(cmd, serverResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Restart(ctx, t, noWait)
+			})
 		},
 	}
-	restartCmd.Flags().StringP("name", "n", "", "Server name")
-	restartCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addServerTargetFlags(restartCmd)
 	restartCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	restartCmd.MarkFlagRequired("name")
-	restartCmd.MarkFlagRequired("resource-group")
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update a PostgreSQL flexible server",
 		Long:  "Update tier, SKU, storage, backup retention, administrator password, or tags of a PostgreSQL flexible server. Only the fields you pass are changed.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			rg, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Update(context.Background(), cmd, name, rg, noWait)
+			return bulk.Run(cmd, serverResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, noWait)
+			})
 		},
 	}
-	updateCmd.Flags().StringP("name", "n", "", "Server name")
-	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addServerTargetFlags(updateCmd)
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.Flags().String("tier", "", "Pricing tier (Burstable, GeneralPurpose, MemoryOptimized)")
 	updateCmd.Flags().String("sku-name", "", "SKU name (e.g., Standard_B1ms, Standard_D2s_v3)")
 	updateCmd.Flags().Int32("storage-size", 0, "Storage size in GB")
@@ -234,8 +221,6 @@ func NewFlexibleServerCommand() *cobra.Command {
 	updateCmd.Flags().String("admin-password", "", "New administrator password")
 	updateCmd.Flags().StringToString("tags", nil, "Space-separated tags: key1=value1 key2=value2")
 	updateCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	upgradeCmd := &cobra.Command{
 		Use:   "upgrade",
@@ -327,3 +312,11 @@ func NewFlexibleServerCommand() *cobra.Command {
 	)
 	return cmd
 }
+
+// addServerTargetFlags adds -n/-g and --ids to a command that acts on one or
+// more existing servers.
+func addServerTargetFlags(c *cobra.Command) {
+	c.Flags().StringP("name", "n", "", "Server name")
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(c)
+}
@@ -23,7 +23,7 @@ func Create(ctx context.Context, cmd *cobra.Command, name, resourceGroup, locati
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create PostgreSQL client: %w", err)
 	}
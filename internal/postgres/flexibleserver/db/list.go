@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName str
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewDatabasesClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewDatabasesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create databases client: %w", err)
 	}
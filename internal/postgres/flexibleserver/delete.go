@@ -4,35 +4,23 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/postgresql/armpostgresqlflexibleservers/v4"
-	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Delete(ctx context.Context, name, resourceGroup string, noWait bool) error {
-	cred, err := azure.GetCredential()
+func Delete(ctx context.Context, t bulk.Target, noWait bool) error {
+	client, err := subscriptionServersClient(t.SubscriptionID)
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create PostgreSQL client: %w", err)
-	}
-
-	fmt.Printf("Deleting PostgreSQL flexible server '%s'...\n", name)
-	poller, err := client.BeginDelete(ctx, resourceGroup, name, nil)
+	fmt.Printf("Deleting PostgreSQL flexible server '%s'...\n", t.Name)
+	poller, err := client.BeginDelete(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin delete PostgreSQL server: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started deletion of PostgreSQL flexible server '%s'\n", name)
+		fmt.Printf("Started deletion of PostgreSQL flexible server '%s'\n", t.Name)
 		return nil
 	}
 
@@ -41,6 +29,6 @@ func Delete(ctx context.Context, name, resourceGroup string, noWait bool) error
 		return fmt.Errorf("failed to delete PostgreSQL server: %w", err)
 	}
 
-	fmt.Printf("Deleted PostgreSQL flexible server '%s'\n", name)
+	fmt.Printf("Deleted PostgreSQL flexible server '%s'\n", t.Name)
 	return nil
 }
@@ -22,7 +22,7 @@ func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName,
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewAdministratorsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewAdministratorsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create administrators client: %w", err)
 	}
@@ -38,7 +38,7 @@ func GeoRestore(ctx context.Context, cmd *cobra.Command, name, resourceGroup, lo
 		props.PointInTimeUTC = to.Ptr(t)
 	}
 
-	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create servers client: %w", err)
 	}
@@ -23,7 +23,7 @@ func Remove(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName s
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create servers client: %w", err)
 	}
@@ -22,7 +22,7 @@ func ListSKUs(ctx context.Context, cmd *cobra.Command, location string) error {
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewLocationBasedCapabilitiesClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewLocationBasedCapabilitiesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create location based capabilities client: %w", err)
 	}
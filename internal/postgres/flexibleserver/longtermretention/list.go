@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName str
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewLtrBackupOperationsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewLtrBackupOperationsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create LTR backup operations client: %w", err)
 	}
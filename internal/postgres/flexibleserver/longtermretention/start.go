@@ -23,7 +23,7 @@ func Start(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName, b
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewFlexibleServerClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewFlexibleServerClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create flexible server client: %w", err)
 	}
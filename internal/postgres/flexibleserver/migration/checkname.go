@@ -21,7 +21,7 @@ func CheckNameAvailability(ctx context.Context, cmd *cobra.Command, name, locati
 	if err != nil {
 		return err
 	}
-	client, err := armpostgresqlflexibleservers.NewCheckNameAvailabilityWithLocationClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewCheckNameAvailabilityWithLocationClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create check name availability client: %w", err)
 	}
@@ -20,7 +20,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, targetServerNa
 	if err != nil {
 		return err
 	}
-	client, err := armpostgresqlflexibleservers.NewMigrationsClient(cred, nil)
+	client, err := armpostgresqlflexibleservers.NewMigrationsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create migrations client: %w", err)
 	}
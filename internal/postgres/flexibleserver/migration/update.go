@@ -21,7 +21,7 @@ func Update(ctx context.Context, cmd *cobra.Command, resourceGroup, targetServer
 	if err != nil {
 		return err
 	}
-	client, err := armpostgresqlflexibleservers.NewMigrationsClient(cred, nil)
+	client, err := armpostgresqlflexibleservers.NewMigrationsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create migrations client: %w", err)
 	}
@@ -23,7 +23,7 @@ func Set(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName, con
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewConfigurationsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewConfigurationsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create configurations client: %w", err)
 	}
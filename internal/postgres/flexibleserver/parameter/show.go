@@ -22,7 +22,7 @@ func Show(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName, co
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewConfigurationsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewConfigurationsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create configurations client: %w", err)
 	}
@@ -22,7 +22,7 @@ func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName,
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewPrivateEndpointConnectionClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewPrivateEndpointConnectionClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create private endpoint connection client: %w", err)
 	}
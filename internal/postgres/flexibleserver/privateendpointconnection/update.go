@@ -23,7 +23,7 @@ func updateStatus(ctx context.Context, cmd *cobra.Command, resourceGroup, server
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewPrivateEndpointConnectionClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewPrivateEndpointConnectionClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create private endpoint connection client: %w", err)
 	}
@@ -20,7 +20,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName str
 	if err != nil {
 		return err
 	}
-	client, err := armpostgresqlflexibleservers.NewPrivateLinkResourcesClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewPrivateLinkResourcesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create private link resources client: %w", err)
 	}
@@ -23,7 +23,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName str
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewReplicasClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewReplicasClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create replicas client: %w", err)
 	}
@@ -31,7 +31,7 @@ func Restore(ctx context.Context, cmd *cobra.Command, name, resourceGroup, locat
 		return fmt.Errorf("invalid --restore-time %q: must be RFC3339 (e.g. 2026-05-08T14:30:00Z): %w", restoreTime, err)
 	}
 
-	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create servers client: %w", err)
 	}
@@ -23,7 +23,7 @@ func Download(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName
 	if err != nil {
 		return err
 	}
-	client, err := armpostgresqlflexibleservers.NewLogFilesClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewLogFilesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create log files client: %w", err)
 	}
@@ -20,7 +20,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName str
 	if err != nil {
 		return err
 	}
-	client, err := armpostgresqlflexibleservers.NewLogFilesClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewLogFilesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create log files client: %w", err)
 	}
@@ -4,33 +4,19 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/postgresql/armpostgresqlflexibleservers/v4"
-	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, serverName, resourceGroup string) error {
-	cred, err := azure.GetCredential()
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
+	client, err := subscriptionServersClient(t.SubscriptionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	server, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to get postgresql flexible server: %w", err)
 	}
 
-	client, err := armpostgresqlflexibleservers.NewServersClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create postgresql flexible servers client: %w", err)
-	}
-
-	server, err := client.Get(ctx, resourceGroup, serverName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get postgresql flexible server: %w", err)
-	}
-
-	return output.PrintJSON(cmd, server.Server)
+	return server.Server, nil
 }
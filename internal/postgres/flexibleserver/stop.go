@@ -4,28 +4,27 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Stop(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, noWait bool) error {
-	client, err := serversClient()
+func Stop(ctx context.Context, t bulk.Target, noWait bool) (interface{}, error) {
+	client, err := subscriptionServersClient(t.SubscriptionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Printf("Stopping PostgreSQL flexible server '%s'...\n", name)
-	poller, err := client.BeginStop(ctx, resourceGroup, name, nil)
+	fmt.Printf("Stopping PostgreSQL flexible server '%s'...\n", t.Name)
+	poller, err := client.BeginStop(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin stop: %w", err)
+		return nil, fmt.Errorf("failed to begin stop: %w", err)
 	}
 
 	if noWait {
-		return output.PrintJSON(cmd, map[string]string{"status": fmt.Sprintf("Stop of server '%s' started.", name)})
+		return map[string]string{"status": fmt.Sprintf("Stop of server '%s' started.", t.Name)}, nil
 	}
 
 	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
-		return fmt.Errorf("failed to stop server: %w", err)
+		return nil, fmt.Errorf("failed to stop server: %w", err)
 	}
-	return output.PrintJSON(cmd, map[string]string{"status": fmt.Sprintf("Server '%s' stopped.", name)})
+	return map[string]string{"status": fmt.Sprintf("Server '%s' stopped.", t.Name)}, nil
 }
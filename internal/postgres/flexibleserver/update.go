@@ -6,39 +6,65 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/postgresql/armpostgresqlflexibleservers/v4"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
-func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string, noWait bool) error {
-	client, err := serversClient()
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, noWait bool) (interface{}, error) {
+	client, err := subscriptionServersClient(t.SubscriptionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	flags := cmd.Flags()
-	props := &armpostgresqlflexibleservers.ServerPropertiesForUpdate{}
-	update := armpostgresqlflexibleservers.ServerForUpdate{Properties: props}
+	update := armpostgresqlflexibleservers.ServerForUpdate{}
+	ops, err := genericupdate.OpsFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) > 0 {
+		// Generic updates edit the server as it is now; without them only the
+		// properties given are sent.
+		current, err := client.Get(ctx, t.ResourceGroup, t.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get server: %w", err)
+		}
+		if err := genericupdate.Seed(&update, current.Server); err != nil {
+			return nil, err
+		}
+	}
+	if update.Properties == nil {
+		update.Properties = &armpostgresqlflexibleservers.ServerPropertiesForUpdate{}
+	}
+	props := update.Properties
 
+	flags := cmd.Flags()
 	if flags.Changed("sku-name") || flags.Changed("tier") {
-		sku := &armpostgresqlflexibleservers.SKU{}
+		if update.SKU == nil {
+			update.SKU = &armpostgresqlflexibleservers.SKU{}
+		}
 		if flags.Changed("sku-name") {
 			v, _ := flags.GetString("sku-name")
-			sku.Name = to.Ptr(v)
+			update.SKU.Name = to.Ptr(v)
 		}
 		if flags.Changed("tier") {
 			v, _ := flags.GetString("tier")
-			sku.Tier = to.Ptr(armpostgresqlflexibleservers.SKUTier(v))
+			update.SKU.Tier = to.Ptr(armpostgresqlflexibleservers.SKUTier(v))
 		}
-		update.SKU = sku
 	}
 	if flags.Changed("storage-size") {
 		v, _ := flags.GetInt32("storage-size")
-		props.Storage = &armpostgresqlflexibleservers.Storage{StorageSizeGB: to.Ptr(v)}
+		if props.Storage == nil {
+			props.Storage = &armpostgresqlflexibleservers.Storage{}
+		}
+		props.Storage.StorageSizeGB = to.Ptr(v)
 	}
 	if flags.Changed("backup-retention") {
 		v, _ := flags.GetInt32("backup-retention")
-		props.Backup = &armpostgresqlflexibleservers.Backup{BackupRetentionDays: to.Ptr(v)}
+		if props.Backup == nil {
+			props.Backup = &armpostgresqlflexibleservers.Backup{}
+		}
+		props.Backup.BackupRetentionDays = to.Ptr(v)
 	}
 	if flags.Changed("admin-password") {
 		v, _ := flags.GetString("admin-password")
@@ -52,20 +78,25 @@ func Update(ctx context.Context, cmd *cobra.Command, name, resourceGroup string,
 		}
 		update.Tags = azureTags
 	}
+	if len(ops) > 0 {
+		if err := genericupdate.ApplyModel(&update, ops); err != nil {
+			return nil, err
+		}
+	}
 
-	fmt.Printf("Updating PostgreSQL flexible server '%s'...\n", name)
-	poller, err := client.BeginUpdate(ctx, resourceGroup, name, update, nil)
+	fmt.Printf("Updating PostgreSQL flexible server '%s'...\n", t.Name)
+	poller, err := client.BeginUpdate(ctx, t.ResourceGroup, t.Name, update, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin update: %w", err)
+		return nil, fmt.Errorf("failed to begin update: %w", err)
 	}
 
 	if noWait {
-		return output.PrintJSON(cmd, map[string]string{"status": fmt.Sprintf("Update of server '%s' started.", name)})
+		return map[string]string{"status": fmt.Sprintf("Update of server '%s' started.", t.Name)}, nil
 	}
 
 	resp, err := poller.PollUntilDone(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update server: %w", err)
+		return nil, fmt.Errorf("failed to update server: %w", err)
 	}
-	return output.PrintJSON(cmd, resp.Server)
+	return resp.Server, nil
 }
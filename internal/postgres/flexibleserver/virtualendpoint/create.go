@@ -23,7 +23,7 @@ func Create(ctx context.Context, cmd *cobra.Command, resourceGroup, serverName,
 		return err
 	}
 
-	client, err := armpostgresqlflexibleservers.NewVirtualEndpointsClient(subscriptionID, cred, nil)
+	client, err := armpostgresqlflexibleservers.NewVirtualEndpointsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual endpoints client: %w", err)
 	}
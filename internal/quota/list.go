@@ -25,7 +25,7 @@ func List(ctx context.Context, cmd *cobra.Command, scope, outputFormat string) e
 		return err
 	}
 
-	client, err := armquota.NewClient(cred, nil)
+	client, err := armquota.NewClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create quota client: %w", err)
 	}
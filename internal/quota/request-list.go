@@ -24,7 +24,7 @@ func RequestList(ctx context.Context, cmd *cobra.Command, scope, outputFormat st
 		return err
 	}
 
-	client, err := armquota.NewRequestStatusClient(cred, nil)
+	client, err := armquota.NewRequestStatusClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create quota request status client: %w", err)
 	}
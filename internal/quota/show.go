@@ -16,7 +16,7 @@ func Show(ctx context.Context, cmd *cobra.Command, scope, resourceName, outputFo
 		return err
 	}
 
-	client, err := armquota.NewClient(cred, nil)
+	client, err := armquota.NewClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create quota client: %w", err)
 	}
@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/quota/armquota"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+)
+
+// Usage returns the limit and current usage of one quota at scope, e.g.
+// resourceName "cores" at
+// subscriptions/{subscriptionId}/providers/Microsoft.Compute/locations/westeurope.
+func Usage(ctx context.Context, scope, resourceName string) (QuotaInfo, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return QuotaInfo{}, err
+	}
+
+	client, err := armquota.NewClient(cred, azure.ARMClientOptions())
+	if err != nil {
+		return QuotaInfo{}, fmt.Errorf("failed to create quota client: %w", err)
+	}
+	usages, err := armquota.NewUsagesClient(cred, azure.ARMClientOptions())
+	if err != nil {
+		return QuotaInfo{}, fmt.Errorf("failed to create quota usages client: %w", err)
+	}
+
+	quota, err := client.Get(ctx, resourceName, scope, nil)
+	if err != nil {
+		return QuotaInfo{}, fmt.Errorf("failed to get quota %s: %w", resourceName, err)
+	}
+	usage, err := usages.Get(ctx, resourceName, scope, nil)
+	if err != nil {
+		return QuotaInfo{}, fmt.Errorf("failed to get usage of %s: %w", resourceName, err)
+	}
+
+	info := QuotaInfo{Name: resourceName}
+	if props := quota.Properties; props != nil {
+		info.Unit = azure.GetStringValue(props.Unit)
+		info.QuotaPeriod = azure.GetStringValue(props.QuotaPeriod)
+		if limitObj, ok := props.Limit.(*armquota.LimitObject); ok && limitObj.Value != nil {
+			info.Limit = *limitObj.Value
+		}
+	}
+	if props := usage.Properties; props != nil && props.Usages != nil && props.Usages.Value != nil {
+		info.CurrentValue = *props.Usages.Value
+	}
+	return info, nil
+}
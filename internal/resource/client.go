@@ -28,7 +28,7 @@ func newGenericClient(cmd *cobra.Command) (*armresources.Client, azcore.TokenCre
   if err != nil {
     return nil, nil, "", err
   }
-  c, err := armresources.NewClient(sub, cred, nil)
+  c, err := armresources.NewClient(sub, cred, azure.ARMClientOptions())
   if err != nil {
     return nil, nil, "", fmt.Errorf("failed to create resources client: %w", err)
   }
@@ -44,7 +44,7 @@ func newTagsClient(cmd *cobra.Command) (*armresources.TagsClient, error) {
   if err != nil {
     return nil, err
   }
-  c, err := armresources.NewTagsClient(sub, cred, nil)
+  c, err := armresources.NewTagsClient(sub, cred, azure.ARMClientOptions())
   if err != nil {
     return nil, fmt.Errorf("failed to create tags client: %w", err)
   }
@@ -64,11 +64,11 @@ func runInvokeAction(cmd *cobra.Command, args []string) error {
     return err
   }
 
-  client, err := arm.NewClient("github.com/cdobbyn/azure-go-cli/internal/resource", "", cred, nil)
+  client, err := arm.NewClient("github.com/cdobbyn/azure-go-cli/internal/resource", "", cred, azure.ARMClientOptions())
   if err != nil {
     return err
   }
-  endpoint := "https://management.azure.com"
+  endpoint := azure.ARMEndpoint()
   url := fmt.Sprintf("%s%s/%s", endpoint, id, action)
 
   req, err := runtime.NewRequest(ctx, http.MethodPost, url)
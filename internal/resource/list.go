@@ -36,7 +36,9 @@ func runList(cmd *cobra.Command, args []string) error {
   filter := buildListFilter(cmd)
   group, _ := cmd.Flags().GetString("resource-group")
 
-  var results []map[string]interface{}
+  // Stream each page as it arrives: a subscription-wide list can run to
+  // thousands of resources across many pages.
+  stream := output.NewStream(cmd)
   if group != "" {
     opts := &armresources.ClientListByResourceGroupOptions{}
     if filter != "" {
@@ -46,10 +48,10 @@ func runList(cmd *cobra.Command, args []string) error {
     for pager.More() {
       page, err := pager.NextPage(ctx)
       if err != nil {
-        return fmt.Errorf("list failed: %w", err)
+        return stream.Abort(fmt.Errorf("list failed: %w", err))
       }
-      for _, r := range page.Value {
-        results = append(results, genericResourceToMap(r))
+      if err := output.StreamPage(stream, genericResourcesToMaps(page.Value)); err != nil {
+        return err
       }
     }
   } else {
@@ -61,18 +63,22 @@ func runList(cmd *cobra.Command, args []string) error {
     for pager.More() {
       page, err := pager.NextPage(ctx)
       if err != nil {
-        return fmt.Errorf("list failed: %w", err)
+        return stream.Abort(fmt.Errorf("list failed: %w", err))
       }
-      for _, r := range page.Value {
-        results = append(results, genericResourceToMap(r))
+      if err := output.StreamPage(stream, genericResourcesToMaps(page.Value)); err != nil {
+        return err
       }
     }
   }
+  return stream.Close()
+}
 
-  if results == nil {
-    results = []map[string]interface{}{}
+func genericResourcesToMaps(resources []*armresources.GenericResourceExpanded) []map[string]interface{} {
+  results := make([]map[string]interface{}, 0, len(resources))
+  for _, r := range resources {
+    results = append(results, genericResourceToMap(r))
   }
-  return output.PrintJSON(cmd, results)
+  return results
 }
 
 func buildListFilter(cmd *cobra.Command) string {
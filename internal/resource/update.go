@@ -4,7 +4,6 @@ import (
   "context"
   "encoding/json"
   "fmt"
-  "strings"
 
   "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
   "github.com/cdobbyn/azure-go-cli/pkg/azure"
@@ -20,9 +19,7 @@ func newUpdateCmd() *cobra.Command {
     RunE:  runUpdate,
   }
   AddSelectorFlags(cmd)
-  cmd.Flags().StringArray("set", nil, "Set a property: path=value (repeatable)")
-  cmd.Flags().StringArray("add", nil, "Append to a list property: path JSON_VALUE (repeatable)")
-  cmd.Flags().StringArray("remove", nil, "Remove a key or list element: path [INDEX] (repeatable)")
+  genericupdate.AddFlags(cmd)
   cmd.Flags().String("api-version", "", "API version (auto-resolved if not set)")
   cmd.Flags().Bool("latest-include-preview", false, "Include preview versions when auto-resolving --api-version")
   return cmd
@@ -39,11 +36,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
   }
   id := ids[0]
 
-  setOps, _ := cmd.Flags().GetStringArray("set")
-  addOps, _ := cmd.Flags().GetStringArray("add")
-  removeOps, _ := cmd.Flags().GetStringArray("remove")
-
-  ops, err := parseUpdateOps(setOps, addOps, removeOps)
+  ops, err := genericupdate.OpsFromFlags(cmd)
   if err != nil {
     return err
   }
@@ -100,30 +93,3 @@ func runUpdate(cmd *cobra.Command, args []string) error {
   }
   return output.PrintJSON(cmd, out.GenericResource)
 }
-
-func parseUpdateOps(setOps, addOps, removeOps []string) ([]genericupdate.Op, error) {
-  out := []genericupdate.Op{}
-  for _, s := range setOps {
-    eq := strings.Index(s, "=")
-    if eq == -1 {
-      return nil, fmt.Errorf("--set %q: expected path=value", s)
-    }
-    out = append(out, genericupdate.Op{Kind: genericupdate.Set, Path: s[:eq], Value: s[eq+1:]})
-  }
-  for _, a := range addOps {
-    sp := strings.IndexAny(a, " \t")
-    if sp == -1 {
-      return nil, fmt.Errorf("--add %q: expected 'path JSON_VALUE'", a)
-    }
-    out = append(out, genericupdate.Op{Kind: genericupdate.Add, Path: a[:sp], Value: strings.TrimSpace(a[sp+1:])})
-  }
-  for _, r := range removeOps {
-    sp := strings.IndexAny(r, " \t")
-    if sp == -1 {
-      out = append(out, genericupdate.Op{Kind: genericupdate.Remove, Path: r})
-      continue
-    }
-    out = append(out, genericupdate.Op{Kind: genericupdate.Remove, Path: r[:sp], Value: strings.TrimSpace(r[sp+1:])})
-  }
-  return out, nil
-}
@@ -9,7 +9,7 @@ import (
 
   "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
   "github.com/cdobbyn/azure-go-cli/pkg/azure"
-  "github.com/jmespath/go-jmespath"
+  "github.com/cdobbyn/azure-go-cli/pkg/query"
   "github.com/spf13/cobra"
 )
 
@@ -106,7 +106,7 @@ func runWait(cmd *cobra.Command, args []string) error {
         body, _ := json.Marshal(resp.GenericResource)
         var parsed interface{}
         json.Unmarshal(body, &parsed)
-        result, jerr := jmespath.Search(custom, parsed)
+        result, jerr := query.ApplyJMESPath(parsed, custom)
         if jerr != nil {
           return fmt.Errorf("--custom JMESPath: %w", jerr)
         }
@@ -164,6 +164,9 @@ func isTruthy(v interface{}) bool {
     return t != ""
   case float64:
     return t != 0
+  case json.Number:
+    f, _ := t.Float64()
+    return f != 0
   case []interface{}:
     return len(t) > 0
   case map[string]interface{}:
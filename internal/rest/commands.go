@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRestCommand() *cobra.Command {
+	var headers, uriParameters, urlParameters []string
+
+	cmd := &cobra.Command{
+		Use:   "rest",
+		Short: "Invoke a custom request",
+		Long: `Invoke a custom request against any Azure REST API.
+
+The access token audience is derived from the URL host: Azure Resource Manager,
+Microsoft Graph, Key Vault and Storage endpoints of the active cloud are
+recognised. For any other host pass --resource, otherwise the request is sent
+without an Authorization header. A URL starting with '/' is relative to the
+resource manager endpoint, and '{subscriptionId}' is replaced with the current
+subscription.`,
+		Example: `  # Get the current subscription
+  az rest --url "/subscriptions/{subscriptionId}?api-version=2022-12-01"
+
+  # Every resource group, following nextLink
+  az rest --url "/subscriptions/{subscriptionId}/resourcegroups" --uri-parameters api-version=2021-04-01 --paginate
+
+  # Update a Microsoft Graph user
+  az rest --method patch --url https://graph.microsoft.com/v1.0/users/<id> --body '{"jobTitle": "Engineer"}'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Invoke(context.Background(), cmd, headers, append(uriParameters, urlParameters...))
+		},
+	}
+
+	cmd.Flags().StringP("method", "m", "get", "HTTP request method: delete, get, head, options, patch, post, put")
+	cmd.Flags().StringP("url", "u", "", "Request URL, absolute or relative to the resource manager endpoint")
+	cmd.Flags().String("uri", "", "Alias of --url")
+	cmd.Flags().StringP("body", "b", "", "Request body, or @file to read it from a file")
+	cmd.Flags().StringArrayVar(&headers, "headers", nil, "Request headers as KEY=VALUE (repeatable) or a JSON object")
+	cmd.Flags().StringArrayVar(&uriParameters, "uri-parameters", nil, "Query parameters as KEY=VALUE (repeatable) or a JSON object")
+	cmd.Flags().StringArrayVar(&urlParameters, "url-parameters", nil, "Alias of --uri-parameters")
+	cmd.Flags().String("resource", "", "Resource (audience) to request the access token for, when it can't be derived from the URL")
+	cmd.Flags().Bool("skip-authorization-header", false, "Do not send an Authorization header")
+	cmd.Flags().Bool("paginate", false, "Follow nextLink / @odata.nextLink and merge every page's 'value' array into one result")
+	cmd.Flags().String("output-file", "", "Save the response body to this file instead of printing it")
+	cmd.Flags().MarkHidden("uri")
+	cmd.Flags().MarkHidden("url-parameters")
+
+	return cmd
+}
@@ -0,0 +1,352 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// restMethods are the HTTP methods `az rest --method` accepts.
+var restMethods = []string{"delete", "get", "head", "options", "patch", "post", "put"}
+
+// maxPages bounds --paginate so a server that keeps returning the same
+// nextLink can't loop forever.
+const maxPages = 1000
+
+func Invoke(ctx context.Context, cmd *cobra.Command, rawHeaders, rawParams []string) error {
+	method, _ := cmd.Flags().GetString("method")
+	rawURL, _ := cmd.Flags().GetString("url")
+	if rawURL == "" {
+		rawURL, _ = cmd.Flags().GetString("uri")
+	}
+	rawBody, _ := cmd.Flags().GetString("body")
+	resource, _ := cmd.Flags().GetString("resource")
+	skipAuth, _ := cmd.Flags().GetBool("skip-authorization-header")
+	paginate, _ := cmd.Flags().GetBool("paginate")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+
+	if rawURL == "" {
+		return fmt.Errorf("--url is required")
+	}
+	method = strings.ToLower(method)
+	if !contains(restMethods, method) {
+		return fmt.Errorf("--method must be one of: %s", strings.Join(restMethods, ", "))
+	}
+
+	if strings.Contains(rawURL, "{subscriptionId}") {
+		subFlag, _ := cmd.Flags().GetString("subscription")
+		sub, err := config.GetSubscription(subFlag)
+		if err != nil {
+			return err
+		}
+		rawURL = strings.ReplaceAll(rawURL, "{subscriptionId}", sub)
+	}
+
+	headers, err := parseKeyValues(rawHeaders, "--headers")
+	if err != nil {
+		return err
+	}
+	params, err := parseKeyValues(rawParams, "--uri-parameters")
+	if err != nil {
+		return err
+	}
+	target, err := buildURL(rawURL, params)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if rawBody != "" {
+		body, err = readBody(rawBody)
+		if err != nil {
+			return err
+		}
+		// Python az defaults Content-Type to JSON when the body parses as JSON.
+		if _, ok := headerValue(headers, "Content-Type"); !ok && json.Valid(body) {
+			headers = append(headers, keyValue{"Content-Type", "application/json"})
+		}
+	}
+
+	var scopes []string
+	if !skipAuth {
+		if resource == "" {
+			resource = resourceForURL(target)
+		}
+		if resource == "" {
+			logger.Warning("Can't derive an access token audience from --url. If the API needs one, specify it with --resource. Sending the request without an Authorization header.")
+		} else {
+			scopes = []string{scopeForResource(resource)}
+			logger.Debug("Requesting token for scope %s", scopes[0])
+		}
+	}
+
+	pipeline, err := newPipeline(scopes)
+	if err != nil {
+		return err
+	}
+
+	data, err := send(ctx, pipeline, strings.ToUpper(method), target, headers, body)
+	if err != nil {
+		return err
+	}
+
+	if paginate {
+		data, err = followNextLinks(ctx, pipeline, headers, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if outputFile != "" {
+		return os.WriteFile(outputFile, data, 0644)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	var parsed interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&parsed); err != nil {
+		// Not JSON (e.g. plain text or XML from a storage API): print as-is.
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	return output.PrintJSON(cmd, parsed)
+}
+
+// newPipeline builds an azcore pipeline (retries, logging, telemetry) that
+// authenticates with the current login for scopes, or not at all when
+// scopes is empty.
+func newPipeline(scopes []string) (runtime.Pipeline, error) {
+	opts := runtime.PipelineOptions{}
+	if len(scopes) > 0 {
+		cred, err := azure.GetCredential()
+		if err != nil {
+			return runtime.Pipeline{}, err
+		}
+		opts.PerRetry = []policy.Policy{runtime.NewBearerTokenPolicy(cred, scopes, nil)}
+	}
+	clientOpts := azure.ClientOptions()
+	return runtime.NewPipeline("azure-go-cli/rest", "", opts, &clientOpts), nil
+}
+
+func send(ctx context.Context, pipeline runtime.Pipeline, method, target string, headers []keyValue, body []byte) ([]byte, error) {
+	req, err := runtime.NewRequest(ctx, method, target)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range headers {
+		req.Raw().Header.Set(h.Key, h.Value)
+	}
+	if body != nil {
+		contentType, _ := headerValue(headers, "Content-Type")
+		if err := req.SetBody(streaming.NopCloser(bytes.NewReader(body)), contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, target, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%s(%s)", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// followNextLinks GETs every page after first and returns first with the
+// "value" arrays of all pages concatenated and the next link removed. ARM
+// uses "nextLink", Microsoft Graph "@odata.nextLink".
+func followNextLinks(ctx context.Context, pipeline runtime.Pipeline, headers []keyValue, first []byte) ([]byte, error) {
+	page, err := decodePage(first)
+	if err != nil {
+		return first, nil
+	}
+	result := page
+	values, _ := result["value"].([]interface{})
+
+	for i := 0; i < maxPages; i++ {
+		next := nextLink(page)
+		if next == "" {
+			break
+		}
+		logger.Debug("Following next link %s", next)
+		data, err := send(ctx, pipeline, http.MethodGet, next, headers, nil)
+		if err != nil {
+			return nil, err
+		}
+		if page, err = decodePage(data); err != nil {
+			return nil, fmt.Errorf("failed to parse page from %s: %w", next, err)
+		}
+		more, _ := page["value"].([]interface{})
+		values = append(values, more...)
+	}
+
+	delete(result, "nextLink")
+	delete(result, "@odata.nextLink")
+	if values != nil {
+		result["value"] = values
+	}
+	return json.Marshal(result)
+}
+
+func decodePage(data []byte) (map[string]interface{}, error) {
+	var page map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func nextLink(page map[string]interface{}) string {
+	for _, key := range []string{"nextLink", "@odata.nextLink"} {
+		if s, ok := page[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// buildURL resolves a URL relative to the resource manager endpoint and
+// appends the --uri-parameters to its query string.
+func buildURL(rawURL string, params []keyValue) (string, error) {
+	if strings.HasPrefix(rawURL, "/") {
+		rawURL = azure.ARMEndpoint() + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid --url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid --url %q: expected an absolute URL or a path starting with '/'", rawURL)
+	}
+	if len(params) > 0 {
+		q := u.Query()
+		for _, p := range params {
+			q.Set(p.Key, p.Value)
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String(), nil
+}
+
+// resourceForURL derives the token audience from the URL host, for the
+// endpoints of the active cloud. Returns "" for unrecognised hosts.
+func resourceForURL(target string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	hostOf := func(endpoint string) string {
+		if e, err := url.Parse(endpoint); err == nil {
+			return strings.ToLower(e.Hostname())
+		}
+		return ""
+	}
+
+	cloud := azure.ActiveCloud()
+	switch {
+	case host == hostOf(cloud.Endpoints.ResourceManager):
+		return azure.ARMEndpoint()
+	case host == hostOf(cloud.Endpoints.MicrosoftGraphResourceID):
+		return azure.GraphEndpoint()
+	case cloud.Suffixes.KeyvaultDNS != "" && strings.HasSuffix(host, strings.ToLower(cloud.Suffixes.KeyvaultDNS)):
+		return "https://" + strings.TrimPrefix(cloud.Suffixes.KeyvaultDNS, ".")
+	case strings.HasSuffix(host, "."+azure.StorageSuffix()):
+		return "https://storage.azure.com"
+	}
+	return ""
+}
+
+// scopeForResource turns a v1 resource (audience) into a v2 scope.
+func scopeForResource(resource string) string {
+	if strings.HasSuffix(resource, "/.default") {
+		return resource
+	}
+	return strings.TrimSuffix(resource, "/") + "/.default"
+}
+
+// keyValue is one header or query parameter, kept in command-line order.
+type keyValue struct{ Key, Value string }
+
+// parseKeyValues accepts KEY=VALUE tokens and JSON objects, as Python az does
+// for --headers and --uri-parameters.
+func parseKeyValues(items []string, flag string) ([]keyValue, error) {
+	var result []keyValue
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if strings.HasPrefix(item, "{") {
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(item), &obj); err != nil {
+				return nil, fmt.Errorf("%s: invalid JSON %q: %w", flag, item, err)
+			}
+			for k, v := range obj {
+				result = append(result, keyValue{k, fmt.Sprint(v)})
+			}
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%s: %q is not in KEY=VALUE format", flag, item)
+		}
+		result = append(result, keyValue{parts[0], parts[1]})
+	}
+	return result, nil
+}
+
+func headerValue(headers []keyValue, name string) (string, bool) {
+	for _, h := range headers {
+		if strings.EqualFold(h.Key, name) {
+			return h.Value, true
+		}
+	}
+	return "", false
+}
+
+// readBody returns the --body value, reading it from a file if it starts
+// with '@'.
+func readBody(raw string) ([]byte, error) {
+	if strings.HasPrefix(raw, "@") {
+		path := raw[1:]
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body file %q: %w", path, err)
+		}
+		return data, nil
+	}
+	return []byte(raw), nil
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
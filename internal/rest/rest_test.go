@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResourceForURL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cases := []struct {
+		url  string
+		want string
+	}{
+		{"https://management.azure.com/subscriptions?api-version=2022-12-01", "https://management.azure.com"},
+		{"https://graph.microsoft.com/v1.0/me", "https://graph.microsoft.com"},
+		{"https://kv1.vault.azure.net/secrets?api-version=7.4", "https://vault.azure.net"},
+		{"https://acct.blob.core.windows.net/container?restype=container", "https://storage.azure.com"},
+		{"https://example.com/api", ""},
+	}
+	for _, tc := range cases {
+		if got := resourceForURL(tc.url); got != tc.want {
+			t.Errorf("resourceForURL(%q) = %q, want %q", tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestScopeForResource(t *testing.T) {
+	cases := map[string]string{
+		"https://management.azure.com":         "https://management.azure.com/.default",
+		"https://management.azure.com/":        "https://management.azure.com/.default",
+		"api://my-app/.default":                "api://my-app/.default",
+		"499b84ac-1321-427f-aa17-267ca6975798": "499b84ac-1321-427f-aa17-267ca6975798/.default",
+	}
+	for in, want := range cases {
+		if got := scopeForResource(in); got != want {
+			t.Errorf("scopeForResource(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	got, err := buildURL("/subscriptions/s1/resourcegroups", []keyValue{{"api-version", "2021-04-01"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://management.azure.com/subscriptions/s1/resourcegroups?api-version=2021-04-01"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, err = buildURL("https://graph.microsoft.com/v1.0/users?$top=5", []keyValue{{"$top", "10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://graph.microsoft.com/v1.0/users?%24top=10"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := buildURL("subscriptions/s1", nil); err == nil {
+		t.Error("expected an error for a relative URL without a leading '/'")
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	got, err := parseKeyValues([]string{"If-Match=*", `{"x-ms-version": "2021-08-06"}`, "a=b=c"}, "--headers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []keyValue{{"If-Match", "*"}, {"x-ms-version", "2021-08-06"}, {"a", "b=c"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"novalue", "=value", "{not json"} {
+		if _, err := parseKeyValues([]string{bad}, "--headers"); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestFollowNextLinks(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "2":
+			fmt.Fprintf(w, `{"value": [{"id": 2}], "nextLink": "%s/items?page=3"}`, server.URL)
+		case "3":
+			fmt.Fprint(w, `{"value": [{"id": 3}]}`)
+		default:
+			http.Error(w, "unexpected page", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	pipeline, err := newPipeline(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := fmt.Sprintf(`{"value": [{"id": 1}], "@odata.nextLink": "%s/items?page=2"}`, server.URL)
+	data, err := followNextLinks(context.Background(), pipeline, nil, []byte(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result["@odata.nextLink"]; ok {
+		t.Error("next link should be removed from the merged result")
+	}
+	values, _ := result["value"].([]interface{})
+	if len(values) != 3 {
+		t.Fatalf("got %d values, want 3: %s", len(values), data)
+	}
+}
@@ -15,6 +15,7 @@ func NewAssignmentCmd() *cobra.Command {
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newCreateCmd())
 	cmd.AddCommand(newDeleteCmd())
+	cmd.AddCommand(newEffectiveCmd())
 
 	return cmd
 }
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v3"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
 	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
 	"github.com/cdobbyn/azure-go-cli/pkg/output"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -28,15 +30,6 @@ func newCreateCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
-			// --assignee and --assignee-object-id both set the principal ID. The
-			// object-id variant skips the Microsoft Graph lookup that --assignee
-			// implies in azure-cli, so it works for principals the caller can't
-			// resolve in Graph (e.g. service principals / cross-tenant).
-			principalID := assignee
-			if assigneeObjectID != "" {
-				principalID = assigneeObjectID
-			}
-
 			// --assignee-principal-type is only valid alongside --assignee-object-id.
 			if assigneePrincipalType != "" && assigneeObjectID == "" {
 				return fmt.Errorf("--assignee-principal-type can only be used with --assignee-object-id")
@@ -47,12 +40,26 @@ func newCreateCmd() *cobra.Command {
 				return err
 			}
 
+			// --assignee is resolved through Microsoft Graph, which also tells
+			// us the principal type. --assignee-object-id skips the lookup, so
+			// it works for principals the caller can't resolve in Graph (e.g.
+			// service principals / cross-tenant).
+			principalID := assigneeObjectID
+			if assignee != "" {
+				principal, err := resolveAssignee(ctx, assignee)
+				if err != nil {
+					return err
+				}
+				principalID = principal.ID
+				principalType, _ = parsePrincipalType(principal.Type)
+			}
+
 			return createRoleAssignment(ctx, cmd, scope, principalID, principalType, role)
 		},
 	}
 
 	cmd.Flags().StringVar(&scope, "scope", "", "Scope for the assignment (required)")
-	cmd.Flags().StringVar(&assignee, "assignee", "", "Object ID of the user, group, or service principal")
+	cmd.Flags().StringVar(&assignee, "assignee", "", "User principal name, service principal name, app ID or display name, group display name, or object ID of the assignee")
 	cmd.Flags().StringVar(&assigneeObjectID, "assignee-object-id", "", "Object ID of the principal, used directly without a Microsoft Graph lookup")
 	cmd.Flags().StringVar(&assigneePrincipalType, "assignee-principal-type", "", "Principal type of the assignee object ID: User, Group, ServicePrincipal, ForeignGroup, or Device (only valid with --assignee-object-id)")
 	cmd.Flags().StringVar(&role, "role", "", "Role name or ID to assign (required)")
@@ -86,25 +93,35 @@ func parsePrincipalType(s string) (*armauthorization.PrincipalType, error) {
 }
 
 func createRoleAssignment(ctx context.Context, cmd *cobra.Command, scope, principalID string, principalType *armauthorization.PrincipalType, roleNameOrID string) error {
+	assignment, err := Assign(ctx, scope, principalID, principalType, roleNameOrID)
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(cmd, assignment)
+}
+
+// Assign grants roleNameOrID to principalID at scope. Other command groups
+// use it to give a managed identity they created the access it needs.
+func Assign(ctx context.Context, scope, principalID string, principalType *armauthorization.PrincipalType, roleNameOrID string) (*armauthorization.RoleAssignment, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return fmt.Errorf("failed to get credentials: %w", err)
+		return nil, fmt.Errorf("failed to get credentials: %w", err)
 	}
 
 	subscriptionID, err := config.GetDefaultSubscription()
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, nil)
+	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return fmt.Errorf("failed to create role assignments client: %w", err)
+		return nil, fmt.Errorf("failed to create role assignments client: %w", err)
 	}
 
 	// Resolve role name to role definition ID if needed
 	roleDefinitionID, err := resolveRoleDefinitionID(ctx, cred, subscriptionID, scope, roleNameOrID)
 	if err != nil {
-		return fmt.Errorf("failed to resolve role: %w", err)
+		return nil, fmt.Errorf("failed to resolve role: %w", err)
 	}
 
 	// Generate a unique name for the role assignment
@@ -121,8 +138,32 @@ func createRoleAssignment(ctx context.Context, cmd *cobra.Command, scope, princi
 
 	resp, err := client.Create(ctx, scope, assignmentName, params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create role assignment: %w", err)
+		return nil, fmt.Errorf("failed to create role assignment: %w", err)
 	}
+	return &resp.RoleAssignment, nil
+}
+
+// A new principal takes a while to reach every Entra ID replica, and until it
+// does role assignments for it fail with PrincipalNotFound.
+const (
+	newPrincipalRetries    = 12
+	newPrincipalRetryDelay = 5 * time.Second
+)
 
-	return output.PrintJSON(cmd, resp.RoleAssignment)
+// AssignNewPrincipal is Assign for a principal that was just created, which
+// ARM may not see yet; it retries while the assignment fails with
+// PrincipalNotFound.
+func AssignNewPrincipal(ctx context.Context, scope, principalID string, principalType *armauthorization.PrincipalType, roleNameOrID string) (*armauthorization.RoleAssignment, error) {
+	for attempt := 1; ; attempt++ {
+		assignment, err := Assign(ctx, scope, principalID, principalType, roleNameOrID)
+		if err == nil || attempt == newPrincipalRetries || !strings.Contains(err.Error(), "PrincipalNotFound") {
+			return assignment, err
+		}
+		logger.Verbose("Principal %s not replicated yet, retrying the role assignment", principalID)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(newPrincipalRetryDelay):
+		}
+	}
 }
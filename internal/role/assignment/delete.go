@@ -38,7 +38,7 @@ func newDeleteCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&scope, "scope", "", "Scope of the assignment")
-	cmd.Flags().StringVar(&assignee, "assignee", "", "Object ID of the user, group, or service principal")
+	cmd.Flags().StringVar(&assignee, "assignee", "", "User principal name, service principal name, app ID or display name, group display name, or object ID of the assignee")
 	cmd.Flags().StringVar(&role, "role", "", "Role name or ID")
 
 	return cmd
@@ -55,7 +55,7 @@ func deleteRoleAssignmentByID(ctx context.Context, assignmentID string) error {
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, nil)
+	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create role assignments client: %w", err)
 	}
@@ -82,7 +82,7 @@ func deleteRoleAssignmentByFilter(ctx context.Context, scope, assignee, roleName
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, nil)
+	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create role assignments client: %w", err)
 	}
@@ -93,8 +93,13 @@ func deleteRoleAssignmentByFilter(ctx context.Context, scope, assignee, roleName
 		return fmt.Errorf("failed to resolve role: %w", err)
 	}
 
+	principal, err := resolveAssignee(ctx, assignee)
+	if err != nil {
+		return err
+	}
+
 	// Find matching assignment
-	filter := fmt.Sprintf("principalId eq '%s'", assignee)
+	filter := fmt.Sprintf("principalId eq '%s'", principal.ID)
 	pager := client.NewListForScopePager(scope, &armauthorization.RoleAssignmentsClientListForScopeOptions{
 		Filter: &filter,
 	})
@@ -0,0 +1,548 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v3"
+	"github.com/cdobbyn/azure-go-cli/internal/account/managementgroup"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// everyone is the principal ID deny assignments use for all principals.
+const everyone = "00000000-0000-0000-0000-000000000000"
+
+const mgScopePrefix = "/providers/Microsoft.Management/managementGroups/"
+
+// scopeLevel is one scope an assignment can be inherited from.
+type scopeLevel struct {
+	Scope string `json:"scope"`
+	Kind  string `json:"kind"`
+}
+
+// grant is a role assignment that applies to the principal at the scope.
+type grant struct {
+	AssignmentID       string `json:"assignmentId"`
+	RoleDefinitionID   string `json:"roleDefinitionId"`
+	RoleDefinitionName string `json:"roleDefinitionName"`
+	Scope              string `json:"scope"`
+	ScopeKind          string `json:"scopeKind"`
+	PrincipalID        string `json:"principalId"`
+	PrincipalName      string `json:"principalName,omitempty"`
+	// Via is "direct", or "group" when made to a group the principal
+	// belongs to.
+	Via string `json:"via"`
+}
+
+// grantRef names the assignment a permission comes from.
+type grantRef struct {
+	Role      string `json:"role"`
+	Scope     string `json:"scope"`
+	Principal string `json:"principal"`
+	Via       string `json:"via"`
+}
+
+type effectivePermission struct {
+	Permission string     `json:"permission"`
+	GrantedBy  []grantRef `json:"grantedBy"`
+	// DeniedBy names the deny assignments that block all or part of it.
+	DeniedBy []string `json:"deniedBy,omitempty"`
+}
+
+type denyRecord struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Scope          string   `json:"scope"`
+	Description    string   `json:"description,omitempty"`
+	Actions        []string `json:"actions"`
+	NotActions     []string `json:"notActions"`
+	DataActions    []string `json:"dataActions"`
+	NotDataActions []string `json:"notDataActions"`
+}
+
+type effectiveAccess struct {
+	Principal       graph.Principal       `json:"principal"`
+	Scope           string                `json:"scope"`
+	Scopes          []scopeLevel          `json:"scopes"`
+	Groups          []graph.Principal     `json:"groups"`
+	Assignments     []grant               `json:"assignments"`
+	Actions         []effectivePermission `json:"actions"`
+	NotActions      []effectivePermission `json:"notActions"`
+	DataActions     []effectivePermission `json:"dataActions"`
+	NotDataActions  []effectivePermission `json:"notDataActions"`
+	DenyAssignments []denyRecord          `json:"denyAssignments"`
+}
+
+func newEffectiveCmd() *cobra.Command {
+	var scope string
+	var assignee string
+
+	cmd := &cobra.Command{
+		Use:   "effective",
+		Short: "Show what a principal can do at a scope",
+		Long: `Show the effective permissions of a user, group or service principal at a
+scope: every role assignment that applies there, whether made at the scope, a
+parent resource, resource group, subscription or management group, directly
+or to a group the principal belongs to, the permissions those roles grant and
+which assignment grants each one, and the deny assignments that apply.
+
+notActions and notDataActions only carve exceptions out of the role that
+lists them; another role can still grant what one role excludes.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showEffectiveAccess(context.Background(), cmd, assignee, scope)
+		},
+	}
+
+	cmd.Flags().StringVar(&assignee, "assignee", "", "User principal name, service principal name, app ID or display name, group display name, or object ID")
+	cmd.Flags().StringVar(&scope, "scope", "", "Scope to evaluate (defaults to subscription scope)")
+	cmd.MarkFlagRequired("assignee")
+
+	return cmd
+}
+
+func showEffectiveAccess(ctx context.Context, cmd *cobra.Command, assignee, scope string) error {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+	subscriptionID, err := config.GetDefaultSubscription()
+	if err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if scope == "" {
+		scope = "/subscriptions/" + subscriptionID
+	}
+	scope = strings.TrimSuffix(scope, "/")
+	if sub := subscriptionOf(scope); sub != "" {
+		subscriptionID = sub
+	}
+
+	principal, err := resolveAssignee(ctx, assignee)
+	if err != nil {
+		return err
+	}
+	gc, err := graph.NewClient()
+	if err != nil {
+		return err
+	}
+	groupIDs, err := gc.MemberGroups(ctx, principal.ID)
+	if err != nil {
+		return err
+	}
+	ids := map[string]string{strings.ToLower(principal.ID): "direct"}
+	for _, g := range groupIDs {
+		ids[strings.ToLower(g)] = "group"
+	}
+
+	result := effectiveAccess{
+		Principal:       principal,
+		Scope:           scope,
+		Scopes:          ancestorScopes(ctx, scope),
+		Groups:          []graph.Principal{},
+		Assignments:     []grant{},
+		DenyAssignments: []denyRecord{},
+	}
+
+	// Principal names are only for reading the report, so a Graph failure
+	// leaves them out rather than failing it.
+	names := map[string]graph.Principal{}
+	if len(groupIDs) > 0 {
+		if names, err = gc.Principals(ctx, groupIDs); err != nil {
+			logger.Warning("Failed to resolve group names: %v", err)
+			names = map[string]graph.Principal{}
+		}
+	}
+	names[principal.ID] = principal
+	for _, g := range groupIDs {
+		p, ok := names[g]
+		if !ok {
+			p = graph.Principal{ID: g, Type: "Group"}
+		}
+		result.Groups = append(result.Groups, p)
+	}
+
+	// atScope() returns the assignments at the scope and every scope above
+	// it, up to the tenant root.
+	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create role assignments client: %w", err)
+	}
+	atScope := "atScope()"
+	pager := client.NewListForScopePager(scope, &armauthorization.RoleAssignmentsClientListForScopeOptions{Filter: &atScope})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list role assignments: %w", err)
+		}
+		for _, a := range page.Value {
+			p := a.Properties
+			if p == nil || p.PrincipalID == nil {
+				continue
+			}
+			via, ok := ids[strings.ToLower(*p.PrincipalID)]
+			if !ok {
+				continue
+			}
+			g := grant{
+				AssignmentID:     azure.GetStringValue(a.ID),
+				RoleDefinitionID: azure.GetStringValue(p.RoleDefinitionID),
+				Scope:            azure.GetStringValue(p.Scope),
+				PrincipalID:      *p.PrincipalID,
+				PrincipalName:    names[*p.PrincipalID].Name,
+				Via:              via,
+			}
+			g.ScopeKind = scopeKind(g.Scope)
+			result.Assignments = append(result.Assignments, g)
+		}
+	}
+	sortGrants(result.Assignments, result.Scopes)
+
+	defs, err := roleDefinitions(ctx, cred, result.Assignments)
+	if err != nil {
+		return err
+	}
+	for i, g := range result.Assignments {
+		result.Assignments[i].RoleDefinitionName = defs[g.RoleDefinitionID].name
+	}
+
+	result.DenyAssignments, err = denyAssignments(ctx, cred, subscriptionID, scope, ids)
+	if err != nil {
+		return err
+	}
+
+	result.Actions, result.NotActions, result.DataActions, result.NotDataActions = buildPermissions(result.Assignments, defs, result.DenyAssignments)
+	return output.PrintJSON(cmd, result)
+}
+
+// subscriptionOf returns the subscription ID in a scope, or "".
+func subscriptionOf(scope string) string {
+	parts := strings.Split(strings.Trim(scope, "/"), "/")
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "subscriptions") {
+		return parts[1]
+	}
+	return ""
+}
+
+func scopeKind(scope string) string {
+	parts := strings.Split(strings.Trim(scope, "/"), "/")
+	switch {
+	case scope == "/" || scope == "":
+		return "root"
+	case strings.HasPrefix(strings.ToLower(scope), strings.ToLower(mgScopePrefix)):
+		return "managementGroup"
+	case len(parts) == 2:
+		return "subscription"
+	case len(parts) == 4 && strings.EqualFold(parts[2], "resourceGroups"):
+		return "resourceGroup"
+	}
+	return "resource"
+}
+
+// parentScopes returns scope and the resource, resource group and
+// subscription scopes above it, most specific first. Child resources such as
+// .../servers/a/databases/b also yield their parent resource.
+func parentScopes(scope string) []scopeLevel {
+	parts := strings.Split(strings.Trim(scope, "/"), "/")
+	if len(parts) < 2 || !strings.EqualFold(parts[0], "subscriptions") {
+		return []scopeLevel{{Scope: scope, Kind: scopeKind(scope)}}
+	}
+
+	base := "/" + strings.Join(parts[:2], "/")
+	prefixes := []string{base}
+	rest := parts[2:]
+	if len(rest) >= 2 && strings.EqualFold(rest[0], "resourceGroups") {
+		base += "/" + strings.Join(rest[:2], "/")
+		prefixes = append(prefixes, base)
+		rest = rest[2:]
+	}
+	// The rest is [providers, namespace,] type, name pairs; every pair ends
+	// a resource.
+	for i := 0; i < len(rest); {
+		if strings.EqualFold(rest[i], "providers") {
+			i += 2
+		}
+		i += 2
+		if i > len(rest) {
+			break
+		}
+		prefixes = append(prefixes, base+"/"+strings.Join(rest[:i], "/"))
+	}
+
+	levels := make([]scopeLevel, 0, len(prefixes))
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		levels = append(levels, scopeLevel{Scope: prefixes[i], Kind: scopeKind(prefixes[i])})
+	}
+	return levels
+}
+
+// ancestorScopes adds the management groups above the subscription (or
+// management group) to parentScopes. Reading the hierarchy needs access to
+// the management groups, so without it they are only left out of the list;
+// the assignments made there are still found.
+func ancestorScopes(ctx context.Context, scope string) []scopeLevel {
+	levels := parentScopes(scope)
+	var groups []string
+	var err error
+	if sub := subscriptionOf(scope); sub != "" {
+		groups, err = managementgroup.SubscriptionAncestors(ctx, sub)
+	} else if strings.HasPrefix(strings.ToLower(scope), strings.ToLower(mgScopePrefix)) {
+		groups, err = managementgroup.GroupAncestors(ctx, scope[len(mgScopePrefix):])
+	}
+	if err != nil {
+		logger.Warning("Failed to read the management group hierarchy: %v", err)
+	}
+	for _, g := range groups {
+		levels = append(levels, scopeLevel{Scope: managementgroup.GroupID(g), Kind: "managementGroup"})
+	}
+	return levels
+}
+
+// sortGrants orders assignments from the most specific scope up, as listed
+// in levels; scopes not in levels (such as the tenant root) go last.
+func sortGrants(grants []grant, levels []scopeLevel) {
+	rank := func(scope string) int {
+		for i, l := range levels {
+			if strings.EqualFold(l.Scope, scope) {
+				return i
+			}
+		}
+		return len(levels)
+	}
+	sort.SliceStable(grants, func(i, j int) bool {
+		return rank(grants[i].Scope) < rank(grants[j].Scope)
+	})
+}
+
+type roleDefinition struct {
+	name        string
+	permissions []*armauthorization.Permission
+}
+
+// roleDefinitions fetches the definition of every role in grants.
+func roleDefinitions(ctx context.Context, cred azcore.TokenCredential, grants []grant) (map[string]roleDefinition, error) {
+	client, err := armauthorization.NewRoleDefinitionsClient(cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role definitions client: %w", err)
+	}
+	defs := map[string]roleDefinition{}
+	for _, g := range grants {
+		if _, ok := defs[g.RoleDefinitionID]; ok {
+			continue
+		}
+		resp, err := client.GetByID(ctx, g.RoleDefinitionID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get role definition %s: %w", g.RoleDefinitionID, err)
+		}
+		def := roleDefinition{}
+		if p := resp.Properties; p != nil {
+			def.name = azure.GetStringValue(p.RoleName)
+			def.permissions = p.Permissions
+		}
+		defs[g.RoleDefinitionID] = def
+	}
+	return defs, nil
+}
+
+// denyAssignments returns the deny assignments that apply to any of ids at
+// scope.
+func denyAssignments(ctx context.Context, cred azcore.TokenCredential, subscriptionID, scope string, ids map[string]string) ([]denyRecord, error) {
+	client, err := armauthorization.NewDenyAssignmentsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deny assignments client: %w", err)
+	}
+	atScope := "atScope()"
+	pager := client.NewListForScopePager(scope, &armauthorization.DenyAssignmentsClientListForScopeOptions{Filter: &atScope})
+	records := []denyRecord{}
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deny assignments: %w", err)
+		}
+		for _, d := range page.Value {
+			p := d.Properties
+			if p == nil || !denyApplies(p, scope, ids) {
+				continue
+			}
+			rec := denyRecord{
+				ID:          azure.GetStringValue(d.ID),
+				Name:        azure.GetStringValue(p.DenyAssignmentName),
+				Scope:       azure.GetStringValue(p.Scope),
+				Description: azure.GetStringValue(p.Description),
+			}
+			for _, perm := range p.Permissions {
+				rec.Actions = append(rec.Actions, strs(perm.Actions)...)
+				rec.NotActions = append(rec.NotActions, strs(perm.NotActions)...)
+				rec.DataActions = append(rec.DataActions, strs(perm.DataActions)...)
+				rec.NotDataActions = append(rec.NotDataActions, strs(perm.NotDataActions)...)
+			}
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+func denyApplies(p *armauthorization.DenyAssignmentProperties, scope string, ids map[string]string) bool {
+	if p.DoNotApplyToChildScopes != nil && *p.DoNotApplyToChildScopes && !strings.EqualFold(azure.GetStringValue(p.Scope), scope) {
+		return false
+	}
+	for _, ex := range p.ExcludePrincipals {
+		if _, ok := ids[strings.ToLower(azure.GetStringValue(ex.ID))]; ok {
+			return false
+		}
+	}
+	for _, pr := range p.Principals {
+		id := strings.ToLower(azure.GetStringValue(pr.ID))
+		if _, ok := ids[id]; ok || id == everyone {
+			return true
+		}
+	}
+	return false
+}
+
+func strs(values []*string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			out = append(out, *v)
+		}
+	}
+	return out
+}
+
+// buildPermissions unions the permissions of the granted roles, recording
+// which assignment grants each and which deny assignments block it.
+func buildPermissions(grants []grant, defs map[string]roleDefinition, denies []denyRecord) (actions, notActions, dataActions, notDataActions []effectivePermission) {
+	type collector struct {
+		order []string
+		byKey map[string]*effectivePermission
+	}
+	newCollector := func() *collector { return &collector{byKey: map[string]*effectivePermission{}} }
+	sets := [4]*collector{newCollector(), newCollector(), newCollector(), newCollector()}
+
+	for _, g := range grants {
+		principal := g.PrincipalName
+		if principal == "" {
+			principal = g.PrincipalID
+		}
+		ref := grantRef{Role: defs[g.RoleDefinitionID].name, Scope: g.Scope, Principal: principal, Via: g.Via}
+		for _, perm := range defs[g.RoleDefinitionID].permissions {
+			for i, values := range [4][]*string{perm.Actions, perm.NotActions, perm.DataActions, perm.NotDataActions} {
+				for _, v := range strs(values) {
+					key := strings.ToLower(v)
+					ep, ok := sets[i].byKey[key]
+					if !ok {
+						ep = &effectivePermission{Permission: v}
+						sets[i].byKey[key] = ep
+						sets[i].order = append(sets[i].order, key)
+					}
+					ep.GrantedBy = append(ep.GrantedBy, ref)
+				}
+			}
+		}
+	}
+
+	result := [4][]effectivePermission{}
+	for i, set := range sets {
+		sort.Strings(set.order)
+		result[i] = make([]effectivePermission, 0, len(set.order))
+		for _, key := range set.order {
+			ep := *set.byKey[key]
+			// Deny assignments only block actions and dataActions.
+			if i == 0 || i == 2 {
+				for _, d := range denies {
+					deny, except := d.Actions, d.NotActions
+					if i == 2 {
+						deny, except = d.DataActions, d.NotDataActions
+					}
+					if overlaps(ep.Permission, deny) && !coveredBy(ep.Permission, except) {
+						ep.DeniedBy = append(ep.DeniedBy, d.Name)
+					}
+				}
+			}
+			result[i] = append(result[i], ep)
+		}
+	}
+	return result[0], result[1], result[2], result[3]
+}
+
+// compiledPatterns caches permission patterns: the same few wildcards are
+// checked against every permission of every role.
+var compiledPatterns sync.Map
+
+// permissionPattern turns an RBAC permission, where * matches anything,
+// into a case-insensitive regexp.
+func permissionPattern(p string) *regexp.Regexp {
+	if re, ok := compiledPatterns.Load(p); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(p), `\*`, ".*") + "$")
+	compiledPatterns.Store(p, re)
+	return re
+}
+
+// overlaps reports whether some action matches both permission and one of
+// patterns, so a deny of Microsoft.Compute/*/delete flags a grant of * or
+// of Microsoft.Compute/* as well as one of
+// Microsoft.Compute/virtualMachines/delete.
+func overlaps(permission string, patterns []string) bool {
+	for _, p := range patterns {
+		if wildcardsIntersect(strings.ToLower(permission), strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardsIntersect reports whether some string matches both a and b,
+// where * matches any run of characters. Matching one pattern's regexp
+// against the other misses pairs like Microsoft.Compute/* and */delete,
+// where neither matches the other but both match
+// Microsoft.Compute/disks/delete.
+func wildcardsIntersect(a, b string) bool {
+	// seen[i][j] records that a[i:] and b[j:] were already tried.
+	seen := make([][]bool, len(a)+1)
+	for i := range seen {
+		seen[i] = make([]bool, len(b)+1)
+	}
+	var walk func(i, j int) bool
+	walk = func(i, j int) bool {
+		if seen[i][j] {
+			return false
+		}
+		seen[i][j] = true
+		switch {
+		case i == len(a) && j == len(b):
+			return true
+		case i < len(a) && a[i] == '*':
+			// The * matches nothing more, or takes b's next character.
+			return walk(i+1, j) || (j < len(b) && walk(i, j+1))
+		case j < len(b) && b[j] == '*':
+			return walk(i, j+1) || (i < len(a) && walk(i+1, j))
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			return walk(i+1, j+1)
+		}
+		return false
+	}
+	return walk(0, 0)
+}
+
+// coveredBy reports whether one of patterns matches everything permission
+// does.
+func coveredBy(permission string, patterns []string) bool {
+	for _, p := range patterns {
+		if permissionPattern(p).MatchString(permission) {
+			return true
+		}
+	}
+	return false
+}
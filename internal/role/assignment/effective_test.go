@@ -0,0 +1,140 @@
+package assignment
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v3"
+)
+
+func TestParentScopes(t *testing.T) {
+	got := parentScopes("/subscriptions/s/resourceGroups/rg/providers/Microsoft.Sql/servers/a/databases/b")
+	want := []scopeLevel{
+		{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Sql/servers/a/databases/b", "resource"},
+		{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Sql/servers/a", "resource"},
+		{"/subscriptions/s/resourceGroups/rg", "resourceGroup"},
+		{"/subscriptions/s", "subscription"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v", got)
+	}
+
+	mg := "/providers/Microsoft.Management/managementGroups/platform"
+	if got := parentScopes(mg); !reflect.DeepEqual(got, []scopeLevel{{mg, "managementGroup"}}) {
+		t.Errorf("management group: got %v", got)
+	}
+}
+
+func TestSortGrants(t *testing.T) {
+	levels := parentScopes("/subscriptions/s/resourceGroups/rg")
+	levels = append(levels, scopeLevel{"/providers/Microsoft.Management/managementGroups/root", "managementGroup"})
+	grants := []grant{
+		{Scope: "/"},
+		{Scope: "/providers/Microsoft.Management/managementGroups/root"},
+		{Scope: "/subscriptions/s"},
+		{Scope: "/subscriptions/S/resourceGroups/RG"},
+	}
+	sortGrants(grants, levels)
+	var got []string
+	for _, g := range grants {
+		got = append(got, g.Scope)
+	}
+	want := []string{"/subscriptions/S/resourceGroups/RG", "/subscriptions/s", "/providers/Microsoft.Management/managementGroups/root", "/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestDenyApplies(t *testing.T) {
+	ids := map[string]string{"user": "direct", "group": "group"}
+	principal := func(id string) []*armauthorization.Principal {
+		return []*armauthorization.Principal{{ID: to.Ptr(id)}}
+	}
+	tests := []struct {
+		name  string
+		props armauthorization.DenyAssignmentProperties
+		want  bool
+	}{
+		{"to a group", armauthorization.DenyAssignmentProperties{Principals: principal("group")}, true},
+		{"to everyone", armauthorization.DenyAssignmentProperties{Principals: principal(everyone)}, true},
+		{"excluded", armauthorization.DenyAssignmentProperties{Principals: principal(everyone), ExcludePrincipals: principal("group")}, false},
+		{"someone else", armauthorization.DenyAssignmentProperties{Principals: principal("other")}, false},
+		{"parent scope only", armauthorization.DenyAssignmentProperties{Principals: principal("user"), Scope: to.Ptr("/subscriptions/s"), DoNotApplyToChildScopes: to.Ptr(true)}, false},
+		{"this scope only", armauthorization.DenyAssignmentProperties{Principals: principal("user"), Scope: to.Ptr("/subscriptions/s/resourceGroups/rg"), DoNotApplyToChildScopes: to.Ptr(true)}, true},
+	}
+	for _, tt := range tests {
+		if got := denyApplies(&tt.props, "/subscriptions/s/resourceGroups/rg", ids); got != tt.want {
+			t.Errorf("%s: got %v", tt.name, got)
+		}
+	}
+}
+
+func TestBuildPermissions(t *testing.T) {
+	defs := map[string]roleDefinition{
+		"contributor": {name: "Contributor", permissions: []*armauthorization.Permission{{
+			Actions:    []*string{to.Ptr("*")},
+			NotActions: []*string{to.Ptr("Microsoft.Authorization/*/Delete")},
+		}}},
+		"reader": {name: "Reader", permissions: []*armauthorization.Permission{{
+			Actions: []*string{to.Ptr("*/read")},
+		}}},
+		"blob": {name: "Storage Blob Data Reader", permissions: []*armauthorization.Permission{{
+			DataActions: []*string{to.Ptr("Microsoft.Storage/storageAccounts/blobServices/containers/blobs/read")},
+		}}},
+	}
+	grants := []grant{
+		{RoleDefinitionID: "contributor", Scope: "/subscriptions/s/resourceGroups/rg", PrincipalName: "Ops", Via: "group"},
+		{RoleDefinitionID: "reader", Scope: "/subscriptions/s", PrincipalID: "u1", Via: "direct"},
+		{RoleDefinitionID: "blob", Scope: "/subscriptions/s", PrincipalID: "u1", Via: "direct"},
+		{RoleDefinitionID: "reader", Scope: "/providers/Microsoft.Management/managementGroups/root", PrincipalName: "Ops", Via: "group"},
+	}
+	denies := []denyRecord{{
+		Name:       "locked-by-blueprint",
+		Actions:    []string{"Microsoft.Compute/*/delete"},
+		NotActions: []string{"Microsoft.Compute/snapshots/delete"},
+	}}
+
+	actions, notActions, dataActions, notDataActions := buildPermissions(grants, defs, denies)
+	if len(actions) != 2 || actions[0].Permission != "*" || actions[1].Permission != "*/read" {
+		t.Fatalf("actions = %+v", actions)
+	}
+	if want := []grantRef{{"Contributor", "/subscriptions/s/resourceGroups/rg", "Ops", "group"}}; !reflect.DeepEqual(actions[0].GrantedBy, want) {
+		t.Errorf("* granted by %+v", actions[0].GrantedBy)
+	}
+	if len(actions[1].GrantedBy) != 2 || actions[1].GrantedBy[0].Principal != "u1" {
+		t.Errorf("*/read granted by %+v", actions[1].GrantedBy)
+	}
+	if !reflect.DeepEqual(actions[0].DeniedBy, []string{"locked-by-blueprint"}) || actions[1].DeniedBy != nil {
+		t.Errorf("denied: * by %v, */read by %v", actions[0].DeniedBy, actions[1].DeniedBy)
+	}
+	if len(notActions) != 1 || notActions[0].Permission != "Microsoft.Authorization/*/Delete" {
+		t.Errorf("notActions = %+v", notActions)
+	}
+	if len(dataActions) != 1 || dataActions[0].DeniedBy != nil || len(notDataActions) != 0 {
+		t.Errorf("dataActions = %+v, notDataActions = %+v", dataActions, notDataActions)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		permission string
+		patterns   []string
+		want       bool
+	}{
+		{"Microsoft.Compute/virtualMachines/delete", []string{"Microsoft.Compute/*/delete"}, true},
+		{"*", []string{"Microsoft.Compute/*/delete"}, true},
+		{"microsoft.compute/virtualmachines/DELETE", []string{"Microsoft.Compute/*/delete"}, true},
+		{"Microsoft.Compute/virtualMachines/read", []string{"Microsoft.Compute/*/delete"}, false},
+		{"Microsoft.Network/*", []string{"Microsoft.Compute/*"}, false},
+		{"Microsoft.Compute/*", []string{"*/delete"}, true},
+		{"Microsoft.Compute/*/read", []string{"*/delete"}, false},
+		{"Microsoft.*/write", []string{"*.Storage/*"}, true},
+		{"Microsoft.Compute/*/read", []string{"Microsoft.Network/*", "*/virtualMachines/*"}, true},
+	}
+	for _, tt := range tests {
+		if got := overlaps(tt.permission, tt.patterns); got != tt.want {
+			t.Errorf("overlaps(%q, %v) = %v", tt.permission, tt.patterns, got)
+		}
+	}
+}
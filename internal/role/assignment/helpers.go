@@ -6,6 +6,10 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v3"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/google/uuid"
 )
 
 func resolveRoleDefinitionID(ctx context.Context, cred azcore.TokenCredential, subscriptionID, scope, roleNameOrID string) (string, error) {
@@ -15,7 +19,7 @@ func resolveRoleDefinitionID(ctx context.Context, cred azcore.TokenCredential, s
 	}
 
 	// Search for role by name
-	client, err := armauthorization.NewRoleDefinitionsClient(cred, nil)
+	client, err := armauthorization.NewRoleDefinitionsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return "", fmt.Errorf("failed to create role definitions client: %w", err)
 	}
@@ -43,7 +47,7 @@ func resolveRoleDefinitionID(ctx context.Context, cred azcore.TokenCredential, s
 // returns a map of role definition GUID -> role display name, so role
 // assignments can be enriched with roleDefinitionName (as azure-cli does).
 func resolveRoleDefinitionNames(ctx context.Context, cred azcore.TokenCredential, scope string) (map[string]string, error) {
-	client, err := armauthorization.NewRoleDefinitionsClient(cred, nil)
+	client, err := armauthorization.NewRoleDefinitionsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create role definitions client: %w", err)
 	}
@@ -63,3 +67,21 @@ func resolveRoleDefinitionNames(ctx context.Context, cred azcore.TokenCredential
 	}
 	return names, nil
 }
+
+// resolveAssignee looks --assignee up in Microsoft Graph. An object ID that
+// can't be looked up (no Graph permission, or a principal in another tenant)
+// is used as given, as it was before names were accepted.
+func resolveAssignee(ctx context.Context, assignee string) (graph.Principal, error) {
+	c, err := graph.NewClient()
+	if err == nil {
+		var p graph.Principal
+		if p, err = c.ResolvePrincipal(ctx, assignee); err == nil {
+			return p, nil
+		}
+	}
+	if _, parseErr := uuid.Parse(assignee); parseErr == nil {
+		logger.Warning("Could not resolve '%s' in Microsoft Graph, using it as an object ID: %v", assignee, err)
+		return graph.Principal{ID: assignee}, nil
+	}
+	return graph.Principal{}, err
+}
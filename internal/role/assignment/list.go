@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v3"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
 	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/graph"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
 	output_ "github.com/cdobbyn/azure-go-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -23,6 +26,7 @@ type roleAssignmentRecord struct {
 	PrincipalType      string  `json:"principalType,omitempty"`
 	RoleDefinitionID   string  `json:"roleDefinitionId"`
 	RoleDefinitionName string  `json:"roleDefinitionName"`
+	PrincipalName      string  `json:"principalName,omitempty"`
 	Scope              string  `json:"scope"`
 	Condition          *string `json:"condition"`
 	ConditionVersion   *string `json:"conditionVersion"`
@@ -65,11 +69,19 @@ func toAssignmentRecords(assignments []*armauthorization.RoleAssignment, names m
 	return records
 }
 
+// listOptions are the filters and enrichment of az role assignment list.
+type listOptions struct {
+	scope            string
+	assignee         string
+	role             string
+	all              bool
+	includeInherited bool
+	includeGroups    bool
+	fillPrincipal    bool
+}
+
 func newListCmd() *cobra.Command {
-	var scope string
-	var assignee string
-	var role string
-	var all bool
+	var opts listOptions
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -77,6 +89,9 @@ func newListCmd() *cobra.Command {
 		Long:  "List Azure RBAC role assignments at a given scope",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
+			if opts.includeGroups && opts.assignee == "" {
+				return fmt.Errorf("--include-groups requires --assignee")
+			}
 			// list historically defaulted to table output, unlike the
 			// global default of json, so only honor an explicitly-passed
 			// format.
@@ -84,19 +99,22 @@ func newListCmd() *cobra.Command {
 			if !cmd.Flags().Changed("output") {
 				format = "table"
 			}
-			return listRoleAssignments(ctx, cmd, format, scope, assignee, role, all)
+			return listRoleAssignments(ctx, cmd, format, opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&scope, "scope", "", "Scope to list assignments for (defaults to subscription scope)")
-	cmd.Flags().StringVar(&assignee, "assignee", "", "Filter by assignee (user, group, or service principal object ID)")
-	cmd.Flags().StringVar(&role, "role", "", "Filter by role name or ID")
-	cmd.Flags().BoolVar(&all, "all", false, "Show all assignments under the current subscription")
+	cmd.Flags().StringVar(&opts.scope, "scope", "", "Scope to list assignments for (defaults to subscription scope)")
+	cmd.Flags().StringVar(&opts.assignee, "assignee", "", "Filter by assignee: user principal name, service principal name, app ID or display name, group display name, or object ID")
+	cmd.Flags().StringVar(&opts.role, "role", "", "Filter by role name or ID")
+	cmd.Flags().BoolVar(&opts.all, "all", false, "Show all assignments under the current subscription")
+	cmd.Flags().BoolVar(&opts.includeInherited, "include-inherited", false, "Include assignments made at parent scopes, such as management groups")
+	cmd.Flags().BoolVar(&opts.includeGroups, "include-groups", false, "Include assignments to groups the assignee belongs to, directly or through nested groups")
+	cmd.Flags().BoolVar(&opts.fillPrincipal, "fill-principal-name", true, "Look up principalName in Microsoft Graph")
 
 	return cmd
 }
 
-func listRoleAssignments(ctx context.Context, cmd *cobra.Command, output, scope, assignee, role string, all bool) error {
+func listRoleAssignments(ctx context.Context, cmd *cobra.Command, output string, opts listOptions) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return fmt.Errorf("failed to get credentials: %w", err)
@@ -108,11 +126,36 @@ func listRoleAssignments(ctx context.Context, cmd *cobra.Command, output, scope,
 	}
 
 	// Default to subscription scope if not specified
+	scope := opts.scope
 	if scope == "" {
 		scope = fmt.Sprintf("/subscriptions/%s", subscriptionID)
 	}
 
-	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, nil)
+	// The principals whose assignments to keep: the assignee, and with
+	// --include-groups every group it is a transitive member of.
+	var principalIDs map[string]bool
+	if opts.assignee != "" {
+		principal, err := resolveAssignee(ctx, opts.assignee)
+		if err != nil {
+			return err
+		}
+		principalIDs = map[string]bool{strings.ToLower(principal.ID): true}
+		if opts.includeGroups {
+			c, err := graph.NewClient()
+			if err != nil {
+				return err
+			}
+			groups, err := c.MemberGroups(ctx, principal.ID)
+			if err != nil {
+				return err
+			}
+			for _, g := range groups {
+				principalIDs[strings.ToLower(g)] = true
+			}
+		}
+	}
+
+	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create role assignments client: %w", err)
 	}
@@ -123,9 +166,10 @@ func listRoleAssignments(ctx context.Context, cmd *cobra.Command, output, scope,
 	// - Resource scope: only supports 'atScope()' or no filter
 	//
 	// When --all is specified, we don't use atScope() to get assignments at all levels.
-	// Otherwise, we use atScope() to only get assignments at the specified scope level.
+	// Otherwise, we use atScope() to only get assignments at the specified scope level
+	// and the scopes above it, which --include-inherited keeps.
 	var filter *string
-	if !all {
+	if !opts.all {
 		filterStr := "atScope()"
 		filter = &filterStr
 	}
@@ -143,47 +187,53 @@ func listRoleAssignments(ctx context.Context, cmd *cobra.Command, output, scope,
 		assignments = append(assignments, page.Value...)
 	}
 
+	if !opts.all && !opts.includeInherited {
+		assignments = filterAssignments(assignments, func(p *armauthorization.RoleAssignmentProperties) bool {
+			return p.Scope != nil && strings.EqualFold(strings.TrimSuffix(*p.Scope, "/"), strings.TrimSuffix(scope, "/"))
+		})
+	}
+
 	// Client-side filter by assignee if specified
-	if assignee != "" {
-		var filtered []*armauthorization.RoleAssignment
-		for _, a := range assignments {
-			if a.Properties != nil && a.Properties.PrincipalID != nil && *a.Properties.PrincipalID == assignee {
-				filtered = append(filtered, a)
-			}
-		}
-		assignments = filtered
+	if principalIDs != nil {
+		assignments = filterAssignments(assignments, func(p *armauthorization.RoleAssignmentProperties) bool {
+			return p.PrincipalID != nil && principalIDs[strings.ToLower(*p.PrincipalID)]
+		})
+	}
+
+	// roleDefinitionName enrichment is best-effort: a caller with
+	// roleAssignments/read but not roleDefinitions/read still gets output
+	// (the name falls back to empty rather than failing the command). Names
+	// are resolved only at `scope`, so --all may leave custom roles defined
+	// at a child scope unnamed.
+	names, err := resolveRoleDefinitionNames(ctx, cred, scope)
+	if err != nil {
+		names = map[string]string{}
 	}
 
 	// Filter by role if specified
-	if role != "" {
-		var filtered []*armauthorization.RoleAssignment
-		for _, a := range assignments {
-			if a.Properties != nil && a.Properties.RoleDefinitionID != nil {
-				roleDefID := *a.Properties.RoleDefinitionID
-				if roleDefID == role || getRoleNameFromID(roleDefID) == role {
-					filtered = append(filtered, a)
-				}
+	if opts.role != "" {
+		assignments = filterAssignments(assignments, func(p *armauthorization.RoleAssignmentProperties) bool {
+			if p.RoleDefinitionID == nil {
+				return false
 			}
-		}
-		assignments = filtered
+			roleDefID := *p.RoleDefinitionID
+			id := getRoleNameFromID(roleDefID)
+			return roleDefID == opts.role || id == opts.role || strings.EqualFold(names[id], opts.role)
+		})
+	}
+
+	records := toAssignmentRecords(assignments, names)
+	if opts.fillPrincipal {
+		fillPrincipalNames(ctx, records)
 	}
 
 	// json/tsv: emit azure-cli-shaped records (flattened, with
-	// roleDefinitionName resolved) so JMESPath --query expressions written for
-	// azure-cli work unchanged. A --query also forces this path so the filter is
-	// never silently dropped in the default (table) mode.
+	// roleDefinitionName and principalName resolved) so JMESPath --query
+	// expressions written for azure-cli work unchanged. A --query also forces
+	// this path so the filter is never silently dropped in the default
+	// (table) mode.
 	queryStr, _ := cmd.Flags().GetString("query")
 	if output != "table" || queryStr != "" {
-		// roleDefinitionName enrichment is best-effort: a caller with
-		// roleAssignments/read but not roleDefinitions/read still gets output
-		// (the name falls back to empty rather than failing the command). Names
-		// are resolved only at `scope`, so --all may leave custom roles defined
-		// at a child scope unnamed.
-		names, err := resolveRoleDefinitionNames(ctx, cred, scope)
-		if err != nil {
-			names = map[string]string{}
-		}
-		records := toAssignmentRecords(assignments, names)
 		format := output
 		if format == "table" {
 			format = "json"
@@ -193,34 +243,66 @@ func listRoleAssignments(ctx context.Context, cmd *cobra.Command, output, scope,
 
 	// Table output
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "PRINCIPAL ID\tROLE\tSCOPE")
+	fmt.Fprintln(w, "PRINCIPAL\tROLE\tSCOPE")
 
-	for _, a := range assignments {
-		principalID := ""
-		if a.Properties != nil && a.Properties.PrincipalID != nil {
-			principalID = *a.Properties.PrincipalID
+	for _, rec := range records {
+		principal := rec.PrincipalName
+		if principal == "" {
+			principal = rec.PrincipalID
 		}
 
-		roleID := ""
-		if a.Properties != nil && a.Properties.RoleDefinitionID != nil {
-			roleID = getRoleNameFromID(*a.Properties.RoleDefinitionID)
+		role := rec.RoleDefinitionName
+		if role == "" {
+			role = getRoleNameFromID(rec.RoleDefinitionID)
 		}
 
-		assignmentScope := ""
-		if a.Properties != nil && a.Properties.Scope != nil {
-			assignmentScope = *a.Properties.Scope
-			// Shorten long scopes for table display
-			if len(assignmentScope) > 60 {
-				assignmentScope = assignmentScope[:57] + "..."
-			}
+		// Shorten long scopes for table display
+		assignmentScope := rec.Scope
+		if len(assignmentScope) > 60 {
+			assignmentScope = assignmentScope[:57] + "..."
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\n", principalID, roleID, assignmentScope)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", principal, role, assignmentScope)
 	}
 
 	return w.Flush()
 }
 
+func filterAssignments(assignments []*armauthorization.RoleAssignment, keep func(*armauthorization.RoleAssignmentProperties) bool) []*armauthorization.RoleAssignment {
+	var filtered []*armauthorization.RoleAssignment
+	for _, a := range assignments {
+		if a.Properties != nil && keep(a.Properties) {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
+
+// fillPrincipalNames sets principalName from Microsoft Graph. Like the role
+// names it is best-effort: without Graph access the names stay empty.
+func fillPrincipalNames(ctx context.Context, records []roleAssignmentRecord) {
+	if len(records) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(records))
+	for _, rec := range records {
+		ids = append(ids, rec.PrincipalID)
+	}
+	c, err := graph.NewClient()
+	if err != nil {
+		logger.Warning("Failed to resolve principal names: %v", err)
+		return
+	}
+	principals, err := c.Principals(ctx, ids)
+	if err != nil {
+		logger.Warning("Failed to resolve principal names: %v", err)
+		return
+	}
+	for i := range records {
+		records[i].PrincipalName = principals[records[i].PrincipalID].Name
+	}
+}
+
 // getRoleNameFromID extracts the role definition ID from a full resource ID
 // Example: /subscriptions/.../providers/Microsoft.Authorization/roleDefinitions/{guid}
 // Returns: {guid}
@@ -59,7 +59,7 @@ func listRoleDefinitions(ctx context.Context, cmd *cobra.Command, output string,
 		scope = fmt.Sprintf("/subscriptions/%s", subscriptionID)
 	}
 
-	client, err := armauthorization.NewRoleDefinitionsClient(cred, nil)
+	client, err := armauthorization.NewRoleDefinitionsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create role definitions client: %w", err)
 	}
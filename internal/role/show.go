@@ -87,7 +87,7 @@ func showRoleDefinition(ctx context.Context, cmd *cobra.Command, roleNameOrID, s
 		scope = fmt.Sprintf("/subscriptions/%s", subscriptionID)
 	}
 
-	client, err := armauthorization.NewRoleDefinitionsClient(cred, nil)
+	client, err := armauthorization.NewRoleDefinitionsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create role definitions client: %w", err)
 	}
@@ -105,7 +105,7 @@ func updateRoleDefinition(ctx context.Context, cmd *cobra.Command, roleDefinitio
 
 	props := buildUpdatedProperties(input, existing)
 
-	client, err := armauthorization.NewRoleDefinitionsClient(cred, nil)
+	client, err := armauthorization.NewRoleDefinitionsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create role definitions client: %w", err)
 	}
@@ -208,7 +208,7 @@ func buildPermissions(input *roleDefinitionInput) []*armauthorization.Permission
 
 // findRoleDefinition resolves a role by full ID, GUID, or display name.
 func findRoleDefinition(ctx context.Context, cred azcore.TokenCredential, scope, roleNameOrID string) (*armauthorization.RoleDefinition, error) {
-	client, err := armauthorization.NewRoleDefinitionsClient(cred, nil)
+	client, err := armauthorization.NewRoleDefinitionsClient(cred, azure.ARMClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create role definitions client: %w", err)
 	}
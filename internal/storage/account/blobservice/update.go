@@ -20,7 +20,7 @@ func Update(ctx context.Context, cmd *cobra.Command, account, resourceGroup stri
 	if err != nil {
 		return err
 	}
-	client, err := armstorage.NewBlobServicesClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewBlobServicesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create blob services client: %w", err)
 	}
@@ -23,7 +23,7 @@ func CheckName(ctx context.Context, cmd *cobra.Command, name string) error {
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewAccountsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
@@ -8,9 +8,13 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/storage/account/keys"
 	"github.com/cdobbyn/azure-go-cli/internal/storage/account/managementpolicy"
 	"github.com/cdobbyn/azure-go-cli/internal/storage/account/networkrule"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Storage/storageAccounts"
+
 func NewAccountCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "account",
@@ -32,15 +36,12 @@ func NewAccountCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a storage account",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			accountName, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, accountName, resourceGroup)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
 	showCmd.Flags().StringP("name", "n", "", "Storage account name")
 	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(showCmd)
 
 	createCmd := &cobra.Command{
 		Use:   "create",
@@ -67,22 +68,19 @@ func NewAccountCommand() *cobra.Command {
 		Use:   "delete",
 		Short: "Delete a storage account",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Delete(context.Background(), name, resourceGroup)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t)
+			})
 		},
 	}
 	deleteCmd.Flags().StringP("name", "n", "", "Storage account name")
 	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
+	bulk.AddIDsFlag(deleteCmd)
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update a storage account",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			sku, _ := cmd.Flags().GetString("sku")
 			accessTier, _ := cmd.Flags().GetString("access-tier")
 			tags, _ := cmd.Flags().GetStringToString("tags")
@@ -91,17 +89,19 @@ func NewAccountCommand() *cobra.Command {
 				v, _ := cmd.Flags().GetBool("allow-blob-public-access")
 				allowBlobPublicAccess = &v
 			}
-			return Update(context.Background(), cmd, resourceGroup, name, sku, accessTier, tags, allowBlobPublicAccess)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return Update(ctx, cmd, t, sku, accessTier, tags, allowBlobPublicAccess)
+			})
 		},
 	}
 	updateCmd.Flags().StringP("name", "n", "", "Storage account name")
 	updateCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	bulk.AddIDsFlag(updateCmd)
+	genericupdate.AddFlags(updateCmd)
 	updateCmd.Flags().String("sku", "", "SKU (Standard_LRS, Standard_GRS, Standard_RAGRS, Standard_ZRS, Premium_LRS, Premium_ZRS, Standard_GZRS, Standard_RAGZRS)")
 	updateCmd.Flags().String("access-tier", "", "Access tier (Hot, Cool, Cold, Premium)")
 	updateCmd.Flags().StringToString("tags", nil, "Tags: key1=value1 key2=value2")
 	updateCmd.Flags().Bool("allow-blob-public-access", false, "Allow or disallow public access to all blobs or containers")
-	updateCmd.MarkFlagRequired("name")
-	updateCmd.MarkFlagRequired("resource-group")
 
 	showConnStrCmd := &cobra.Command{
 		Use:   "show-connection-string",
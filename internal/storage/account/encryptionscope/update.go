@@ -21,7 +21,7 @@ func Update(ctx context.Context, cmd *cobra.Command, account, resourceGroup, nam
 	if err != nil {
 		return err
 	}
-	client, err := armstorage.NewEncryptionScopesClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewEncryptionScopesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create encryption scopes client: %w", err)
 	}
@@ -22,7 +22,7 @@ func Failover(ctx context.Context, cmd *cobra.Command, resourceGroup, name strin
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewAccountsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
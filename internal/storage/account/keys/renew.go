@@ -21,7 +21,7 @@ func Renew(ctx context.Context, cmd *cobra.Command, account, resourceGroup, keyN
 	if err != nil {
 		return err
 	}
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewAccountsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
@@ -23,12 +23,12 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return err
 	}
 
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewAccountsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
 
-	var accounts []map[string]interface{}
+	stream := output.NewStream(cmd)
 
 	if resourceGroup != "" {
 		// List accounts in specific resource group
@@ -36,11 +36,15 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list storage accounts: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list storage accounts: %w", err))
 			}
 
+			items := make([]map[string]interface{}, 0, len(page.Value))
 			for _, account := range page.Value {
-				accounts = append(accounts, formatAccount(account))
+				items = append(items, formatAccount(account))
+			}
+			if err := output.StreamPage(stream, items); err != nil {
+				return err
 			}
 		}
 	} else {
@@ -49,16 +53,20 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list storage accounts: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list storage accounts: %w", err))
 			}
 
+			items := make([]map[string]interface{}, 0, len(page.Value))
 			for _, account := range page.Value {
-				accounts = append(accounts, formatAccount(account))
+				items = append(items, formatAccount(account))
+			}
+			if err := output.StreamPage(stream, items); err != nil {
+				return err
 			}
 		}
 	}
 
-	return output.PrintJSON(cmd, accounts)
+	return stream.Close()
 }
 
 func formatAccount(account *armstorage.Account) map[string]interface{} {
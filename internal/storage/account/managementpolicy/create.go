@@ -32,7 +32,7 @@ func Create(ctx context.Context, cmd *cobra.Command, account, resourceGroup, pol
 		return fmt.Errorf("failed to parse policy file: %w", err)
 	}
 
-	client, err := armstorage.NewManagementPoliciesClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewManagementPoliciesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create management policies client: %w", err)
 	}
@@ -20,7 +20,7 @@ func Show(ctx context.Context, cmd *cobra.Command, account, resourceGroup string
 	if err != nil {
 		return err
 	}
-	client, err := armstorage.NewManagementPoliciesClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewManagementPoliciesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create management policies client: %w", err)
 	}
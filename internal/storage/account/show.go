@@ -6,31 +6,24 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Show(ctx context.Context, cmd *cobra.Command, accountName, resourceGroup string) error {
+func Show(ctx context.Context, t bulk.Target) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armstorage.NewAccountsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
 
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	account, err := client.GetProperties(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create storage accounts client: %w", err)
+		return nil, fmt.Errorf("failed to get storage account: %w", err)
 	}
 
-	account, err := client.GetProperties(ctx, resourceGroup, accountName, nil)
-	if err != nil {
-		return fmt.Errorf("failed to get storage account: %w", err)
-	}
-
-	return output.PrintJSON(cmd, account)
+	return account, nil
 }
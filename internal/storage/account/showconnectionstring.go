@@ -22,7 +22,7 @@ func ShowConnectionString(ctx context.Context, cmd *cobra.Command, resourceGroup
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewAccountsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
@@ -36,8 +36,7 @@ func ShowConnectionString(ctx context.Context, cmd *cobra.Command, resourceGroup
 	}
 
 	key := azure.GetStringValue(resp.Keys[0].Value)
-	// ponytail: public-cloud suffix hardcoded; add cloud-awareness if sovereign clouds needed
-	cs := fmt.Sprintf("DefaultEndpointsProtocol=https;AccountName=%s;AccountKey=%s;EndpointSuffix=core.windows.net", name, key)
+	cs := fmt.Sprintf("DefaultEndpointsProtocol=https;AccountName=%s;AccountKey=%s;EndpointSuffix=%s", name, key, azure.StorageSuffix())
 
 	return output.PrintJSON(cmd, map[string]string{"connectionString": cs})
 }
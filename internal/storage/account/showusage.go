@@ -22,7 +22,7 @@ func ShowUsage(ctx context.Context, cmd *cobra.Command, location string) error {
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armstorage.NewUsagesClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewUsagesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create storage usages client: %w", err)
 	}
@@ -7,28 +7,39 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
+	"github.com/cdobbyn/azure-go-cli/pkg/genericupdate"
 	"github.com/spf13/cobra"
 )
 
-func Update(ctx context.Context, cmd *cobra.Command, resourceGroup, name, sku, accessTier string, tags map[string]string, allowBlobPublicAccess *bool) error {
+func Update(ctx context.Context, cmd *cobra.Command, t bulk.Target, sku, accessTier string, tags map[string]string, allowBlobPublicAccess *bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armstorage.NewAccountsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return nil, fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
 
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	params := armstorage.AccountUpdateParameters{}
+	ops, err := genericupdate.OpsFromFlags(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to create storage accounts client: %w", err)
+		return nil, err
+	}
+	if len(ops) > 0 {
+		// Generic updates edit the account as it is now; without them only
+		// the properties given are sent.
+		current, err := client.GetProperties(ctx, t.ResourceGroup, t.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage account: %w", err)
+		}
+		if err := genericupdate.Seed(&params, current.Account); err != nil {
+			return nil, err
+		}
 	}
 
-	params := armstorage.AccountUpdateParameters{}
 	if sku != "" {
 		params.SKU = &armstorage.SKU{Name: to.Ptr(armstorage.SKUName(sku))}
 	}
@@ -40,19 +51,26 @@ func Update(ctx context.Context, cmd *cobra.Command, resourceGroup, name, sku, a
 		params.Tags = azureTags
 	}
 	if accessTier != "" || allowBlobPublicAccess != nil {
-		props := &armstorage.AccountPropertiesUpdateParameters{
-			AllowBlobPublicAccess: allowBlobPublicAccess,
+		if params.Properties == nil {
+			params.Properties = &armstorage.AccountPropertiesUpdateParameters{}
+		}
+		if allowBlobPublicAccess != nil {
+			params.Properties.AllowBlobPublicAccess = allowBlobPublicAccess
 		}
 		if accessTier != "" {
-			props.AccessTier = to.Ptr(armstorage.AccessTier(accessTier))
+			params.Properties.AccessTier = to.Ptr(armstorage.AccessTier(accessTier))
+		}
+	}
+	if len(ops) > 0 {
+		if err := genericupdate.ApplyModel(&params, ops); err != nil {
+			return nil, err
 		}
-		params.Properties = props
 	}
 
-	resp, err := client.Update(ctx, resourceGroup, name, params, nil)
+	resp, err := client.Update(ctx, t.ResourceGroup, t.Name, params, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update storage account: %w", err)
+		return nil, fmt.Errorf("failed to update storage account: %w", err)
 	}
 
-	return output.PrintJSON(cmd, resp.Account)
+	return resp.Account, nil
 }
@@ -18,9 +18,9 @@ func List(ctx context.Context, cmd *cobra.Command, accountName, containerName st
 	}
 
 	// Build blob service URL
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", accountName)
+	serviceURL := azure.StorageServiceURL(accountName, "blob")
 
-	client, err := azblob.NewClient(serviceURL, cred, nil)
+	client, err := azblob.NewClient(serviceURL, cred, &azblob.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return fmt.Errorf("failed to create blob client: %w", err)
 	}
@@ -16,9 +16,9 @@ func Upload(ctx context.Context, accountName, containerName, blobName, filePath
 	}
 
 	// Build blob service URL
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", accountName)
+	serviceURL := azure.StorageServiceURL(accountName, "blob")
 
-	client, err := azblob.NewClient(serviceURL, cred, nil)
+	client, err := azblob.NewClient(serviceURL, cred, &azblob.ClientOptions{ClientOptions: azure.ClientOptions()})
 	if err != nil {
 		return fmt.Errorf("failed to create blob client: %w", err)
 	}
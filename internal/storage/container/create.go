@@ -23,7 +23,7 @@ func Create(ctx context.Context, cmd *cobra.Command, accountName, containerName,
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create blob containers client: %w", err)
 	}
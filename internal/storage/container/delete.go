@@ -20,7 +20,7 @@ func Delete(ctx context.Context, accountName, containerName, resourceGroup strin
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create blob containers client: %w", err)
 	}
@@ -22,7 +22,7 @@ func List(ctx context.Context, cmd *cobra.Command, accountName, resourceGroup st
 		return err
 	}
 
-	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create blob containers client: %w", err)
 	}
@@ -44,7 +44,7 @@ func Create(ctx context.Context, cmd *cobra.Command, resourceGroup, account, nam
 	if err != nil {
 		return err
 	}
-	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewBlobContainersClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create blob containers client: %w", err)
 	}
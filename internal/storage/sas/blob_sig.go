@@ -177,7 +177,7 @@ func SignBlobScope(ctx context.Context, o BlobScopeOptions, accountKey string, a
 		if err != nil {
 			return "", err
 		}
-		client, err := service.NewClient(serviceURL, cred, nil)
+		client, err := service.NewClient(serviceURL, cred, &service.ClientOptions{ClientOptions: azure.ClientOptions()})
 		if err != nil {
 			return "", fmt.Errorf("failed to create blob service client: %w", err)
 		}
@@ -85,7 +85,7 @@ func FetchAccountKey(ctx context.Context, accountName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	client, err := armstorage.NewAccountsClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewAccountsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return "", fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
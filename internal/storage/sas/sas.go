@@ -18,6 +18,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	azsas "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
 )
 
 // NOTE: this package is itself named sas, so the SDK's sas package MUST be
@@ -138,22 +139,23 @@ func OutputFormat(cmd *cobra.Command) string {
 }
 
 // PublicBlobSuffix is the blob endpoint suffix for the public Azure cloud.
+// The default endpoint follows the active cloud (see ServiceEndpoint); this
+// is kept for recognising public-cloud hosts.
 const PublicBlobSuffix = ".blob.core.windows.net"
 
 // ServiceEndpoint resolves the blob service endpoint, without a trailing slash.
 //
 // Precedence matches azure-cli's --blob-endpoint / account_url handling
 // (__init__.py:304-315): the flag wins, then AZURE_STORAGE_SERVICE_ENDPOINT,
-// then the public-cloud default derived from the account name.
+// then the active cloud's default derived from the account name.
 //
-// This is what makes a non-public endpoint reachable at all: a sovereign cloud,
-// a private endpoint addressed directly, or the Azurite emulator. Without it
-// the account name is the only input and the public suffix is unavoidable.
+// This is what makes a non-standard endpoint reachable at all: a private
+// endpoint addressed directly, a custom domain, or the Azurite emulator.
 func ServiceEndpoint(flagEndpoint, accountName string) string {
 	if ep := RawServiceEndpoint(flagEndpoint); ep != "" {
 		return ep
 	}
-	return "https://" + accountName + PublicBlobSuffix
+	return strings.TrimSuffix(azure.StorageServiceURL(accountName, "blob"), "/")
 }
 
 // RawServiceEndpoint returns the explicitly configured endpoint, or "" when
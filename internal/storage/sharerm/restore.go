@@ -21,7 +21,7 @@ func Restore(ctx context.Context, cmd *cobra.Command, resourceGroup, account, na
 	if err != nil {
 		return err
 	}
-	client, err := armstorage.NewFileSharesClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewFileSharesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create file shares client: %w", err)
 	}
@@ -20,7 +20,7 @@ func List(ctx context.Context, cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
-	client, err := armstorage.NewSKUsClient(subscriptionID, cred, nil)
+	client, err := armstorage.NewSKUsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create storage SKUs client: %w", err)
 	}
@@ -20,7 +20,7 @@ func GetBootLogURIs(ctx context.Context, cmd *cobra.Command, resourceGroup, name
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual machines client: %w", err)
 	}
@@ -21,7 +21,7 @@ func Capture(ctx context.Context, cmd *cobra.Command, resourceGroup, name, vhdPr
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VM client: %w", err)
 	}
@@ -7,9 +7,12 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/vm/extension"
 	"github.com/cdobbyn/azure-go-cli/internal/vm/identity"
 	"github.com/cdobbyn/azure-go-cli/internal/vm/runcommand"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const vmResourceType = "Microsoft.Compute/virtualMachines"
+
 func NewVMCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "vm",
@@ -31,63 +34,49 @@ func NewVMCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a virtual machine",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
-			return Show(context.Background(), cmd, name, resourceGroup)
+			return bulk.Run(cmd, vmResourceType, Show)
 		},
 	}
-	showCmd.Flags().StringP("name", "n", "", "VM name")
-	showCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
-	showCmd.MarkFlagRequired("name")
-	showCmd.MarkFlagRequired("resource-group")
+	addVMTargetFlags(showCmd)
 
 	deleteCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete a virtual machine",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Delete(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, vmResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Delete(ctx, t, noWait)
+			})
 		},
 	}
-	deleteCmd.Flags().StringP("name", "n", "", "VM name")
-	deleteCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addVMTargetFlags(deleteCmd)
 	deleteCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	deleteCmd.MarkFlagRequired("name")
-	deleteCmd.MarkFlagRequired("resource-group")
 
 	startCmd := &cobra.Command{
 		Use:   "start",
 		Short: "Start a virtual machine",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Start(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, vmResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Start(ctx, t, noWait)
+			})
 		},
 	}
-	startCmd.Flags().StringP("name", "n", "", "VM name")
-	startCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addVMTargetFlags(startCmd)
 	startCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	startCmd.MarkFlagRequired("name")
-	startCmd.MarkFlagRequired("resource-group")
 
 	stopCmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop and deallocate a virtual machine",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name, _ := cmd.Flags().GetString("name")
-			resourceGroup, _ := cmd.Flags().GetString("resource-group")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return Stop(context.Background(), name, resourceGroup, noWait)
+			return bulk.Run(cmd, vmResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return nil, Stop(ctx, t, noWait)
+			})
 		},
 	}
-	stopCmd.Flags().StringP("name", "n", "", "VM name")
-	stopCmd.Flags().StringP("resource-group", "g", "", "Resource group name")
+	addVMTargetFlags(stopCmd)
 	stopCmd.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
-	stopCmd.MarkFlagRequired("name")
-	stopCmd.MarkFlagRequired("resource-group")
 
 	listSkusCmd := &cobra.Command{
 		Use:   "list-skus",
@@ -284,19 +273,27 @@ func addVMRGName(c *cobra.Command) {
 	c.MarkFlagRequired("name")
 }
 
-// newVMLRO builds a standard LRO subcommand taking (rg, name, noWait).
-func newVMLRO(use, short string, run func(context.Context, *cobra.Command, string, string, bool) error) *cobra.Command {
+// addVMTargetFlags adds -g/-n and --ids for commands that act on one VM or on
+// many at once.
+func addVMTargetFlags(c *cobra.Command) {
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	c.Flags().StringP("name", "n", "", "VM name")
+	bulk.AddIDsFlag(c)
+}
+
+// newVMLRO builds a standard LRO subcommand taking a VM and --no-wait.
+func newVMLRO(use, short string, run func(context.Context, bulk.Target, bool) (interface{}, error)) *cobra.Command {
 	c := &cobra.Command{
 		Use:   use,
 		Short: short,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rg, _ := cmd.Flags().GetString("resource-group")
-			name, _ := cmd.Flags().GetString("name")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return run(context.Background(), cmd, rg, name, noWait)
+			return bulk.Run(cmd, vmResourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return run(ctx, t, noWait)
+			})
 		},
 	}
-	addVMRGName(c)
+	addVMTargetFlags(c)
 	c.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
 	return c
 }
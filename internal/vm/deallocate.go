@@ -6,35 +6,29 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Deallocate(ctx context.Context, cmd *cobra.Command, resourceGroup, name string, noWait bool) error {
+func Deallocate(ctx context.Context, t bulk.Target, noWait bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armcompute.NewVirtualMachinesClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
-	}
-	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create VM client: %w", err)
+		return nil, fmt.Errorf("failed to create VM client: %w", err)
 	}
 
-	fmt.Printf("Deallocating VM '%s'...\n", name)
-	poller, err := client.BeginDeallocate(ctx, resourceGroup, name, nil)
+	fmt.Printf("Deallocating VM '%s'...\n", t.Name)
+	poller, err := client.BeginDeallocate(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin deallocate: %w", err)
+		return nil, fmt.Errorf("failed to begin deallocate: %w", err)
 	}
 	if noWait {
-		return output.PrintJSON(cmd, map[string]string{"status": "deallocate started"})
+		return map[string]string{"status": "deallocate started"}, nil
 	}
 	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
-		return fmt.Errorf("deallocate failed: %w", err)
+		return nil, fmt.Errorf("deallocate failed: %w", err)
 	}
-	return output.PrintJSON(cmd, map[string]string{"status": fmt.Sprintf("'%s' deallocated.", name)})
+	return map[string]string{"status": fmt.Sprintf("'%s' deallocated.", t.Name)}, nil
 }
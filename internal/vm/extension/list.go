@@ -20,7 +20,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, vmName string)
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineExtensionsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineExtensionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create vm extensions client: %w", err)
 	}
@@ -22,13 +22,13 @@ func Set(ctx context.Context, cmd *cobra.Command, resourceGroup, vmName, name, p
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineExtensionsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineExtensionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create vm extensions client: %w", err)
 	}
 
 	if location == "" {
-		vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+		vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 		if err != nil {
 			return fmt.Errorf("failed to create vm client: %w", err)
 		}
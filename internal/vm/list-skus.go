@@ -33,7 +33,7 @@ func ListSKUs(ctx context.Context, cmd *cobra.Command, location, sizeFilter, res
 		return err
 	}
 
-	client, err := armcompute.NewResourceSKUsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewResourceSKUsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create resource SKUs client: %w", err)
 	}
@@ -152,3 +152,45 @@ func ListSKUs(ctx context.Context, cmd *cobra.Command, location, sizeFilter, res
 
 	return nil
 }
+
+// FindSKU returns the virtual machine size called name as offered in
+// location, or nil when the location doesn't offer it at all. Sizes offered
+// but restricted for the subscription are returned with their Restrictions.
+func FindSKU(ctx context.Context, subscriptionID, location, name string) (*armcompute.ResourceSKU, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armcompute.NewResourceSKUsClient(subscriptionID, cred, azure.ARMClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource SKUs client: %w", err)
+	}
+
+	location = strings.ToLower(strings.ReplaceAll(location, " ", ""))
+	filter := fmt.Sprintf("location eq '%s'", location)
+	pager := client.NewListPager(&armcompute.ResourceSKUsClientListOptions{Filter: &filter})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get SKUs page: %w", err)
+		}
+		for _, sku := range page.Value {
+			if azure.GetStringValue(sku.ResourceType) == "virtualMachines" && strings.EqualFold(azure.GetStringValue(sku.Name), name) {
+				return sku, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// SKUCapability returns the value of a SKU capability such as "vCPUs", or
+// "" when the SKU doesn't list it.
+func SKUCapability(sku *armcompute.ResourceSKU, name string) string {
+	for _, c := range sku.Capabilities {
+		if c != nil && strings.EqualFold(azure.GetStringValue(c.Name), name) {
+			return azure.GetStringValue(c.Value)
+		}
+	}
+	return ""
+}
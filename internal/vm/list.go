@@ -22,77 +22,98 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 		return fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VM client: %w", err)
 	}
 
-	vms := make([]*armcompute.VirtualMachine, 0)
+	// vm list historically defaulted to table output, unlike the global
+	// default of json, so only honor an explicitly-passed format.
+	format, _ := cmd.Flags().GetString("output")
+	if !cmd.Flags().Changed("output") {
+		format = "table"
+	}
+	var stream *output.Stream
+	if format != "table" {
+		stream = output.NewStream(cmd)
+	} else {
+		// Print simple table format
+		fmt.Printf("%-40s %-30s %-15s %-20s\n", "NAME", "RESOURCE GROUP", "LOCATION", "VM SIZE")
+		fmt.Println("-------------------------------------------------------------------------------------------------------------------")
+	}
+	writePage := func(vms []*armcompute.VirtualMachine) error {
+		if stream != nil {
+			return output.StreamPage(stream, vms)
+		}
+		for _, vm := range vms {
+			printVMRow(vm)
+		}
+		return nil
+	}
+	fail := func(err error) error {
+		if stream != nil {
+			return stream.Abort(err)
+		}
+		return err
+	}
 
 	if resourceGroup != "" {
 		pager := client.NewListPager(resourceGroup, nil)
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to get next page: %w", err)
+				return fail(fmt.Errorf("failed to get next page: %w", err))
+			}
+			if err := writePage(page.Value); err != nil {
+				return err
 			}
-			vms = append(vms, page.Value...)
 		}
 	} else {
 		pager := client.NewListAllPager(nil)
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to get next page: %w", err)
+				return fail(fmt.Errorf("failed to get next page: %w", err))
+			}
+			if err := writePage(page.Value); err != nil {
+				return err
 			}
-			vms = append(vms, page.Value...)
 		}
 	}
 
-	// vm list historically defaulted to table output, unlike the global
-	// default of json, so only honor an explicitly-passed format.
-	format, _ := cmd.Flags().GetString("output")
-	if !cmd.Flags().Changed("output") {
-		format = "table"
+	if stream != nil {
+		return stream.Close()
 	}
-	if format != "table" {
-		return output.PrintJSON(cmd, vms)
-	}
-
-	// Print simple table format
-	fmt.Printf("%-40s %-30s %-15s %-20s\n", "NAME", "RESOURCE GROUP", "LOCATION", "VM SIZE")
-	fmt.Println("-------------------------------------------------------------------------------------------------------------------")
+	return nil
+}
 
-	for _, vm := range vms {
-		name := ""
-		if vm.Name != nil {
-			name = *vm.Name
-		}
+func printVMRow(vm *armcompute.VirtualMachine) {
+	name := ""
+	if vm.Name != nil {
+		name = *vm.Name
+	}
 
-		location := ""
-		if vm.Location != nil {
-			location = *vm.Location
-		}
+	location := ""
+	if vm.Location != nil {
+		location = *vm.Location
+	}
 
-		vmSize := ""
-		if vm.Properties != nil && vm.Properties.HardwareProfile != nil && vm.Properties.HardwareProfile.VMSize != nil {
-			vmSize = string(*vm.Properties.HardwareProfile.VMSize)
-		}
+	vmSize := ""
+	if vm.Properties != nil && vm.Properties.HardwareProfile != nil && vm.Properties.HardwareProfile.VMSize != nil {
+		vmSize = string(*vm.Properties.HardwareProfile.VMSize)
+	}
 
-		// Extract resource group from ID
-		resourceGroup := ""
-		if vm.ID != nil {
-			// ID format: /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Compute/virtualMachines/{name}
-			parts := parseResourceID(*vm.ID)
-			if rg, ok := parts["resourceGroups"]; ok {
-				resourceGroup = rg
-			}
+	// Extract resource group from ID
+	resourceGroup := ""
+	if vm.ID != nil {
+		// ID format: /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Compute/virtualMachines/{name}
+		parts := parseResourceID(*vm.ID)
+		if rg, ok := parts["resourceGroups"]; ok {
+			resourceGroup = rg
 		}
-
-		fmt.Printf("%-40s %-30s %-15s %-20s\n", name, resourceGroup, location, vmSize)
 	}
 
-	return nil
+	fmt.Printf("%-40s %-30s %-15s %-20s\n", name, resourceGroup, location, vmSize)
 }
 
 func parseResourceID(id string) map[string]string {
@@ -27,15 +27,15 @@ func ListIPAddresses(ctx context.Context, cmd *cobra.Command, resourceGroup, nam
 	if err != nil {
 		return err
 	}
-	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VM client: %w", err)
 	}
-	nicClient, err := armnetwork.NewInterfacesClient(subscriptionID, cred, nil)
+	nicClient, err := armnetwork.NewInterfacesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create network interfaces client: %w", err)
 	}
-	pipClient, err := armnetwork.NewPublicIPAddressesClient(subscriptionID, cred, nil)
+	pipClient, err := armnetwork.NewPublicIPAddressesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create public IP client: %w", err)
 	}
@@ -20,7 +20,7 @@ func ListSizes(ctx context.Context, cmd *cobra.Command, location string) error {
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineSizesClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineSizesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VM sizes client: %w", err)
 	}
@@ -20,7 +20,7 @@ func Delete(ctx context.Context, cmd *cobra.Command, resourceGroup, vmName, name
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineRunCommandsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineRunCommandsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create run commands client: %w", err)
 	}
@@ -6,33 +6,28 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Stop(ctx context.Context, name, resourceGroup string, noWait bool) error {
+func Stop(ctx context.Context, t bulk.Target, noWait bool) error {
 	cred, err := azure.GetCredential()
 	if err != nil {
 		return err
 	}
 
-	subscriptionID, err := config.GetDefaultSubscription()
-	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
-	}
-
-	client, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachinesClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VM client: %w", err)
 	}
 
-	fmt.Printf("Stopping and deallocating virtual machine '%s'...\n", name)
-	poller, err := client.BeginDeallocate(ctx, resourceGroup, name, nil)
+	fmt.Printf("Stopping and deallocating virtual machine '%s'...\n", t.Name)
+	poller, err := client.BeginDeallocate(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin deallocate VM: %w", err)
 	}
 
 	if noWait {
-		fmt.Printf("Started operation to stop virtual machine '%s'\n", name)
+		fmt.Printf("Started operation to stop virtual machine '%s'\n", t.Name)
 		return nil
 	}
 
@@ -41,6 +36,6 @@ func Stop(ctx context.Context, name, resourceGroup string, noWait bool) error {
 		return fmt.Errorf("failed to deallocate VM: %w", err)
 	}
 
-	fmt.Printf("Stopped and deallocated virtual machine '%s'\n", name)
+	fmt.Printf("Stopped and deallocated virtual machine '%s'\n", t.Name)
 	return nil
 }
@@ -8,9 +8,12 @@ import (
 	"github.com/cdobbyn/azure-go-cli/internal/vmss/nic"
 	"github.com/cdobbyn/azure-go-cli/internal/vmss/rollingupgrade"
 	"github.com/cdobbyn/azure-go-cli/internal/vmss/runcommand"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 	"github.com/spf13/cobra"
 )
 
+const resourceType = "Microsoft.Compute/virtualMachineScaleSets"
+
 func NewVmssCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "vmss",
@@ -33,12 +36,10 @@ func NewVmssCommand() *cobra.Command {
 		Use:   "show",
 		Short: "Show details of a virtual machine scale set",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rg, _ := cmd.Flags().GetString("resource-group")
-			name, _ := cmd.Flags().GetString("name")
-			return Show(context.Background(), cmd, rg, name)
+			return bulk.Run(cmd, resourceType, Show)
 		},
 	}
-	addRGName(showCmd)
+	addTargetFlags(showCmd)
 
 	instanceViewCmd := &cobra.Command{
 		Use:   "get-instance-view",
@@ -214,19 +215,27 @@ func addRGName(c *cobra.Command) {
 	c.MarkFlagRequired("name")
 }
 
-// newLRO builds a standard LRO subcommand taking (rg, name, noWait).
-func newLRO(use, short string, run func(context.Context, *cobra.Command, string, string, bool) error) *cobra.Command {
+// addTargetFlags adds -g/-n and --ids for commands that act on one scale set
+// or on many at once.
+func addTargetFlags(c *cobra.Command) {
+	c.Flags().StringP("resource-group", "g", "", "Resource group name")
+	c.Flags().StringP("name", "n", "", "Scale set name")
+	bulk.AddIDsFlag(c)
+}
+
+// newLRO builds a standard LRO subcommand taking a scale set and --no-wait.
+func newLRO(use, short string, run func(context.Context, bulk.Target, bool) (interface{}, error)) *cobra.Command {
 	c := &cobra.Command{
 		Use:   use,
 		Short: short,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rg, _ := cmd.Flags().GetString("resource-group")
-			name, _ := cmd.Flags().GetString("name")
 			noWait, _ := cmd.Flags().GetBool("no-wait")
-			return run(context.Background(), cmd, rg, name, noWait)
+			return bulk.Run(cmd, resourceType, func(ctx context.Context, t bulk.Target) (interface{}, error) {
+				return run(ctx, t, noWait)
+			})
 		},
 	}
-	addRGName(c)
+	addTargetFlags(c)
 	c.Flags().Bool("no-wait", false, "Do not wait for the operation to complete")
 	return c
 }
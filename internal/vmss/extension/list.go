@@ -20,7 +20,7 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup, vmssName strin
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetExtensionsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetExtensionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create vmss extensions client: %w", err)
 	}
@@ -22,7 +22,7 @@ func Set(ctx context.Context, cmd *cobra.Command, resourceGroup, vmssName, name,
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetExtensionsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetExtensionsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create vmss extensions client: %w", err)
 	}
@@ -20,7 +20,7 @@ func GetInstanceView(ctx context.Context, cmd *cobra.Command, resourceGroup, nam
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VMSS client: %w", err)
 	}
@@ -21,7 +21,7 @@ func Assign(ctx context.Context, cmd *cobra.Command, resourceGroup, name string,
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create virtual machine scale sets client: %w", err)
 	}
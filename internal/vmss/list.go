@@ -20,30 +20,34 @@ func List(ctx context.Context, cmd *cobra.Command, resourceGroup string) error {
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VMSS client: %w", err)
 	}
 
-	var items []*armcompute.VirtualMachineScaleSet
+	stream := output.NewStream(cmd)
 	if resourceGroup != "" {
 		pager := client.NewListPager(resourceGroup, nil)
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list scale sets: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list scale sets: %w", err))
+			}
+			if err := output.StreamPage(stream, page.Value); err != nil {
+				return err
 			}
-			items = append(items, page.Value...)
 		}
 	} else {
 		pager := client.NewListAllPager(nil)
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to list scale sets: %w", err)
+				return stream.Abort(fmt.Errorf("failed to list scale sets: %w", err))
+			}
+			if err := output.StreamPage(stream, page.Value); err != nil {
+				return err
 			}
-			items = append(items, page.Value...)
 		}
 	}
-	return output.PrintJSON(cmd, items)
+	return stream.Close()
 }
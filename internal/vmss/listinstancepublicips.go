@@ -20,7 +20,7 @@ func ListInstancePublicIPs(ctx context.Context, cmd *cobra.Command, resourceGrou
 	if err != nil {
 		return err
 	}
-	client, err := armnetwork.NewPublicIPAddressesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewPublicIPAddressesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create public IP client: %w", err)
 	}
@@ -20,7 +20,7 @@ func ListVMNics(ctx context.Context, cmd *cobra.Command, resourceGroup, vmssName
 	if err != nil {
 		return err
 	}
-	client, err := armnetwork.NewInterfacesClient(subscriptionID, cred, nil)
+	client, err := armnetwork.NewInterfacesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create interfaces client: %w", err)
 	}
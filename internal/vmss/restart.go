@@ -6,35 +6,29 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
 	"github.com/cdobbyn/azure-go-cli/pkg/azure"
-	"github.com/cdobbyn/azure-go-cli/pkg/config"
-	"github.com/cdobbyn/azure-go-cli/pkg/output"
-	"github.com/spf13/cobra"
+	"github.com/cdobbyn/azure-go-cli/pkg/bulk"
 )
 
-func Restart(ctx context.Context, cmd *cobra.Command, resourceGroup, name string, noWait bool) error {
+func Restart(ctx context.Context, t bulk.Target, noWait bool) (interface{}, error) {
 	cred, err := azure.GetCredential()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	subscriptionID, err := config.GetDefaultSubscription()
+	client, err := armcompute.NewVirtualMachineScaleSetsClient(t.SubscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
-		return err
-	}
-	client, err := armcompute.NewVirtualMachineScaleSetsClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create VMSS client: %w", err)
+		return nil, fmt.Errorf("failed to create VMSS client: %w", err)
 	}
 
-	fmt.Printf("Restarting scale set '%s'...\n", name)
-	poller, err := client.BeginRestart(ctx, resourceGroup, name, nil)
+	fmt.Printf("Restarting scale set '%s'...\n", t.Name)
+	poller, err := client.BeginRestart(ctx, t.ResourceGroup, t.Name, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin restart: %w", err)
+		return nil, fmt.Errorf("failed to begin restart: %w", err)
 	}
 	if noWait {
-		return output.PrintJSON(cmd, map[string]string{"status": "restart started"})
+		return map[string]string{"status": "restart started"}, nil
 	}
 	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
-		return fmt.Errorf("restart failed: %w", err)
+		return nil, fmt.Errorf("restart failed: %w", err)
 	}
-	return output.PrintJSON(cmd, map[string]string{"status": fmt.Sprintf("'%s' restarted.", name)})
+	return map[string]string{"status": fmt.Sprintf("'%s' restarted.", t.Name)}, nil
 }
@@ -20,7 +20,7 @@ func Cancel(ctx context.Context, cmd *cobra.Command, resourceGroup, vmssName str
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetRollingUpgradesClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetRollingUpgradesClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create rolling upgrades client: %w", err)
 	}
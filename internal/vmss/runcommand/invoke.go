@@ -21,7 +21,7 @@ func Invoke(ctx context.Context, cmd *cobra.Command, resourceGroup, vmssName, in
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetVMsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetVMsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create scale set VMs client: %w", err)
 	}
@@ -20,7 +20,7 @@ func SimulateEviction(ctx context.Context, cmd *cobra.Command, resourceGroup, na
 	if err != nil {
 		return err
 	}
-	client, err := armcompute.NewVirtualMachineScaleSetVMsClient(subscriptionID, cred, nil)
+	client, err := armcompute.NewVirtualMachineScaleSetVMsClient(subscriptionID, cred, azure.ARMClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create VMSS VM client: %w", err)
 	}
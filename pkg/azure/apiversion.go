@@ -26,7 +26,7 @@ func ResolveAPIVersion(ctx context.Context, cred azcore.TokenCredential, subID,
     return v.(string), nil
   }
 
-  client, err := armresources.NewProvidersClient(subID, cred, nil)
+  client, err := armresources.NewProvidersClient(subID, cred, ARMClientOptions())
   if err != nil {
     return "", fmt.Errorf("failed to create providers client: %w", err)
   }
@@ -64,7 +64,8 @@ func authMode() string {
 }
 
 // BaseCredential returns the credential used to discover tenants and perform
-// the initial sign-in: the Azure CLI credential in azure-cli mode, otherwise
+// the initial sign-in: the Azure CLI credential in azure-cli mode, the saved
+// application or managed identity in the non-interactive modes, otherwise
 // the MSAL interactive credential.
 func BaseCredential() (azcore.TokenCredential, error) {
 	switch authMode() {
@@ -73,6 +74,10 @@ func BaseCredential() (azcore.TokenCredential, error) {
 			return nil, err
 		}
 		return azidentity.NewAzureCLICredential(nil)
+	case AuthModeServicePrincipal, AuthModeFederatedToken:
+		return servicePrincipalCredential("")
+	case AuthModeManagedIdentity:
+		return managedIdentityCredential()
 	case AuthModeBroker:
 		if cred, err := brokerCredential("organizations", true); cred != nil || err != nil {
 			return cred, err
@@ -82,8 +87,10 @@ func BaseCredential() (azcore.TokenCredential, error) {
 }
 
 // TenantCredential returns a credential scoped to a specific tenant: the
-// Azure CLI credential scoped to that tenant in azure-cli mode, otherwise the
-// MSAL silent credential built from the authentication record.
+// Azure CLI credential scoped to that tenant in azure-cli mode, the saved
+// application in that tenant in service principal modes, the managed identity
+// (which only ever lives in one tenant), otherwise the MSAL silent credential
+// built from the authentication record.
 func TenantCredential(tenantID string, authRecord azidentity.AuthenticationRecord) (azcore.TokenCredential, error) {
 	switch authMode() {
 	case AuthModeAzureCLI:
@@ -91,6 +98,10 @@ func TenantCredential(tenantID string, authRecord azidentity.AuthenticationRecor
 			return nil, err
 		}
 		return azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{TenantID: tenantID})
+	case AuthModeServicePrincipal, AuthModeFederatedToken:
+		return servicePrincipalCredential(tenantID)
+	case AuthModeManagedIdentity:
+		return managedIdentityCredential()
 	case AuthModeBroker:
 		if cred, err := brokerCredential(tenantID, false); cred != nil || err != nil {
 			return cred, err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -74,7 +75,7 @@ func NewBrokerCredential(tenantID string, interactive bool) (*BrokerCredential,
 		return nil, err
 	}
 	return &BrokerCredential{
-		authority:   "https://login.microsoftonline.com/" + tenantID,
+		authority:   Authority(tenantID),
 		interactive: interactive,
 		accountID:   BrokerAccountID(),
 	}, nil
@@ -84,12 +85,12 @@ func NewBrokerCredential(tenantID string, interactive bool) (*BrokerCredential,
 // record. Discovery calls this on the base credential; the broker account ID
 // it establishes is what every later silent acquisition keys off.
 func (b *BrokerCredential) Authenticate(ctx context.Context) (azidentity.AuthenticationRecord, error) {
-	res, err := b.token(ctx, []string{"https://management.azure.com/.default"})
+	res, err := b.token(ctx, []string{ARMScope()})
 	if err != nil {
 		return azidentity.AuthenticationRecord{}, err
 	}
 	return azidentity.AuthenticationRecord{
-		Authority:     "login.microsoftonline.com",
+		Authority:     strings.TrimPrefix(AuthorityHost(), "https://"),
 		HomeAccountID: res.HomeAccountID,
 		ClientID:      brokerClientID,
 		Version:       "1.0",
@@ -100,7 +101,7 @@ func (b *BrokerCredential) Authenticate(ctx context.Context) (azidentity.Authent
 func (b *BrokerCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
 	scopes := opts.Scopes
 	if len(scopes) == 0 {
-		scopes = []string{"https://management.azure.com/.default"}
+		scopes = []string{ARMScope()}
 	}
 	res, err := b.token(ctx, scopes)
 	if err != nil {
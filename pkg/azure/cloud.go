@@ -0,0 +1,110 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+// activeCloud caches the active cloud for the life of the process; it only
+// changes through `az cloud set`, which exits right after.
+var activeCloud *config.Cloud
+
+// ActiveCloud returns the cloud selected with `az cloud set` (AzureCloud by
+// default). Every endpoint, token scope and DNS suffix below derives from it.
+func ActiveCloud() *config.Cloud {
+	if activeCloud == nil {
+		activeCloud = config.GetActiveCloud()
+	}
+	return activeCloud
+}
+
+// CloudConfiguration converts the active cloud to the azcore form used by
+// ARM clients and azidentity credentials.
+func CloudConfiguration() cloud.Configuration {
+	c := ActiveCloud()
+	return cloud.Configuration{
+		ActiveDirectoryAuthorityHost: strings.TrimSuffix(c.Endpoints.ActiveDirectory, "/") + "/",
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Audience: ARMEndpoint(),
+				Endpoint: ARMEndpoint(),
+			},
+		},
+	}
+}
+
+// ClientOptions returns azcore client options targeting the active cloud,
+// for azidentity credentials and data-plane clients. Every request made with
+// them is logged at verbose level.
+func ClientOptions() azcore.ClientOptions {
+	return azcore.ClientOptions{
+		Cloud:            CloudConfiguration(),
+		PerRetryPolicies: []policy.Policy{requestLogPolicy{}},
+	}
+}
+
+// ARMClientOptions returns the options every ARM client is created with, so
+// it talks to the active cloud's resource manager.
+func ARMClientOptions() *arm.ClientOptions {
+	return &arm.ClientOptions{ClientOptions: ClientOptions()}
+}
+
+// ARMEndpoint returns the active cloud's resource manager URL without a
+// trailing slash, e.g. https://management.azure.com.
+func ARMEndpoint() string {
+	return strings.TrimSuffix(ActiveCloud().Endpoints.ResourceManager, "/")
+}
+
+// ARMScope returns the token scope for resource manager calls. The resource
+// manager URL is used as the audience (rather than the legacy
+// management.core endpoint) so public-cloud tokens are unchanged.
+func ARMScope() string {
+	return ARMEndpoint() + "/.default"
+}
+
+// AuthorityHost returns the active cloud's Entra ID endpoint without a
+// trailing slash, e.g. https://login.microsoftonline.com.
+func AuthorityHost() string {
+	return strings.TrimSuffix(ActiveCloud().Endpoints.ActiveDirectory, "/")
+}
+
+// Authority returns the Entra ID authority URL for tenant (a tenant ID,
+// domain, "organizations" or "common").
+func Authority(tenant string) string {
+	return AuthorityHost() + "/" + tenant
+}
+
+// GraphEndpoint returns the active cloud's Microsoft Graph URL without a
+// trailing slash, e.g. https://graph.microsoft.com.
+func GraphEndpoint() string {
+	return strings.TrimSuffix(ActiveCloud().Endpoints.MicrosoftGraphResourceID, "/")
+}
+
+// GraphScope returns the token scope for Microsoft Graph calls.
+func GraphScope() string {
+	return GraphEndpoint() + "/.default"
+}
+
+// KeyVaultURL returns the data-plane URL of the named vault, with a trailing
+// slash, e.g. https://myvault.vault.azure.net/.
+func KeyVaultURL(vaultName string) string {
+	return "https://" + vaultName + ActiveCloud().Suffixes.KeyvaultDNS + "/"
+}
+
+// StorageSuffix returns the active cloud's storage endpoint suffix, e.g.
+// core.windows.net.
+func StorageSuffix() string {
+	return strings.TrimPrefix(ActiveCloud().Suffixes.StorageEndpoint, ".")
+}
+
+// StorageServiceURL returns the endpoint of a storage account service
+// ("blob", "file", "queue", "table", "dfs"), with a trailing slash, e.g.
+// https://acct.blob.core.windows.net/.
+func StorageServiceURL(accountName, service string) string {
+	return "https://" + accountName + "." + service + "." + StorageSuffix() + "/"
+}
@@ -0,0 +1,92 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+func TestCloudEndpoints(t *testing.T) {
+	cases := []struct {
+		cloud     string
+		armScope  string
+		authority string
+		graph     string
+		vault     string
+		blob      string
+	}{
+		{
+			cloud:     "AzureCloud",
+			armScope:  "https://management.azure.com/.default",
+			authority: "https://login.microsoftonline.com/contoso.com",
+			graph:     "https://graph.microsoft.com/.default",
+			vault:     "https://kv1.vault.azure.net/",
+			blob:      "https://acct.blob.core.windows.net/",
+		},
+		{
+			cloud:     "AzureUSGovernment",
+			armScope:  "https://management.usgovcloudapi.net/.default",
+			authority: "https://login.microsoftonline.us/contoso.com",
+			graph:     "https://graph.microsoft.us/.default",
+			vault:     "https://kv1.vault.usgovcloudapi.net/",
+			blob:      "https://acct.blob.core.usgovcloudapi.net/",
+		},
+		{
+			cloud:     "AzureChinaCloud",
+			armScope:  "https://management.chinacloudapi.cn/.default",
+			authority: "https://login.chinacloudapi.cn/contoso.com",
+			graph:     "https://microsoftgraph.chinacloudapi.cn/.default",
+			vault:     "https://kv1.vault.azure.cn/",
+			blob:      "https://acct.blob.core.chinacloudapi.cn/",
+		},
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { activeCloud = nil })
+	for _, tc := range cases {
+		t.Run(tc.cloud, func(t *testing.T) {
+			c, err := config.GetCloud(tc.cloud)
+			if err != nil {
+				t.Fatal(err)
+			}
+			activeCloud = c
+
+			if got := ARMScope(); got != tc.armScope {
+				t.Errorf("ARMScope() = %s, want %s", got, tc.armScope)
+			}
+			if got := Authority("contoso.com"); got != tc.authority {
+				t.Errorf("Authority() = %s, want %s", got, tc.authority)
+			}
+			if got := GraphScope(); got != tc.graph {
+				t.Errorf("GraphScope() = %s, want %s", got, tc.graph)
+			}
+			if got := KeyVaultURL("kv1"); got != tc.vault {
+				t.Errorf("KeyVaultURL() = %s, want %s", got, tc.vault)
+			}
+			if got := StorageServiceURL("acct", "blob"); got != tc.blob {
+				t.Errorf("StorageServiceURL() = %s, want %s", got, tc.blob)
+			}
+			if got := ARMClientOptions().Cloud.Services["resourceManager"].Endpoint; got+"/.default" != tc.armScope {
+				t.Errorf("ARMClientOptions() endpoint = %s, want %s", got, tc.armScope)
+			}
+		})
+	}
+}
+
+func TestActiveCloudFollowsCloudSet(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { activeCloud = nil })
+
+	activeCloud = nil
+	if got := ActiveCloud().Name; got != config.DefaultCloudName {
+		t.Fatalf("ActiveCloud() = %s, want %s by default", got, config.DefaultCloudName)
+	}
+
+	if _, err := config.SetActiveCloud("azureusgovernment"); err != nil {
+		t.Fatal(err)
+	}
+	activeCloud = nil
+	if got := ActiveCloud().Name; got != "AzureUSGovernment" {
+		t.Errorf("ActiveCloud() = %s, want AzureUSGovernment", got)
+	}
+}
@@ -35,9 +35,11 @@ func DiscoverAllSubscriptionsWithAuth(ctx context.Context, baseCred azcore.Token
 
 	// First, trigger authentication by calling Authenticate if available
 	// This gets us the authentication record AND authenticates the user
-	// Only the azure-cli mode credential has no explicit Authenticate step: it
-	// borrows an already-authenticated Python Azure CLI session, so authRecord
-	// stays zero-value. Every other mode must still authenticate here.
+	// The azure-cli mode credential has no explicit Authenticate step: it
+	// borrows an already-authenticated Python Azure CLI session. Neither do
+	// the non-interactive modes, which have no user account to record. In
+	// both cases authRecord stays zero-value; every other mode must still
+	// authenticate here.
 	if authCred, ok := baseCred.(interface {
 		Authenticate(context.Context) (azidentity.AuthenticationRecord, error)
 	}); ok {
@@ -45,12 +47,12 @@ func DiscoverAllSubscriptionsWithAuth(ctx context.Context, baseCred azcore.Token
 		if err != nil {
 			return nil, azidentity.AuthenticationRecord{}, fmt.Errorf("failed to authenticate: %w", err)
 		}
-	} else if authMode() != AuthModeAzureCLI {
+	} else if usesAuthRecord(authMode()) {
 		return nil, azidentity.AuthenticationRecord{}, fmt.Errorf("credential does not support Authenticate method")
 	}
 
 	// Step 2: List ALL tenants using the base credential
-	tenantsClient, err := armsubscriptions.NewTenantsClient(baseCred, nil)
+	tenantsClient, err := armsubscriptions.NewTenantsClient(baseCred, ARMClientOptions())
 	if err != nil {
 		return nil, authRecord, fmt.Errorf("failed to create tenants client: %w", err)
 	}
@@ -110,7 +112,7 @@ func DiscoverAllSubscriptionsWithAuth(ctx context.Context, baseCred azcore.Token
 		}
 
 		// Create subscriptions client with tenant-specific credential
-		subsClient, err := armsubscriptions.NewClient(tenantCred, nil)
+		subsClient, err := armsubscriptions.NewClient(tenantCred, ARMClientOptions())
 		if err != nil {
 			logger.Warning("Failed to create subscriptions client for tenant '%s' (%s): %v", tenantDisplay, tenantID, err)
 			continue
@@ -153,7 +155,7 @@ func DiscoverAllSubscriptionsWithAuth(ctx context.Context, baseCred azcore.Token
 					Name:            *sub.DisplayName,
 					State:           string(*sub.State),
 					TenantID:        tenantID, // Use the tenant we're querying from (token tenant)
-					EnvironmentName: "AzureCloud",
+					EnvironmentName: ActiveCloud().Name,
 					IsDefault:       false,
 				}
 
@@ -218,7 +220,7 @@ func DiscoverAllSubscriptionsFromCache(ctx context.Context) ([]TenantInfo, error
 		return nil, fmt.Errorf("not authenticated. Please run 'az login' first: %w", err)
 	}
 
-	if profile.AuthMode != AuthModeAzureCLI && profile.AuthenticationRecord == nil {
+	if usesAuthRecord(profile.AuthMode) && profile.AuthenticationRecord == nil {
 		return nil, fmt.Errorf("no authentication record found. Please run 'az login'")
 	}
 
@@ -229,7 +231,7 @@ func DiscoverAllSubscriptionsFromCache(ctx context.Context) ([]TenantInfo, error
 	}
 
 	// List ALL tenants
-	tenantsClient, err := armsubscriptions.NewTenantsClient(baseCred, nil)
+	tenantsClient, err := armsubscriptions.NewTenantsClient(baseCred, ARMClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create tenants client: %w", err)
 	}
@@ -273,7 +275,7 @@ func DiscoverAllSubscriptionsFromCache(ctx context.Context) ([]TenantInfo, error
 		}
 
 		// Create subscriptions client
-		subsClient, err := armsubscriptions.NewClient(tenantCred, nil)
+		subsClient, err := armsubscriptions.NewClient(tenantCred, ARMClientOptions())
 		if err != nil {
 			continue
 		}
@@ -303,7 +305,7 @@ func DiscoverAllSubscriptionsFromCache(ctx context.Context) ([]TenantInfo, error
 					Name:            *sub.DisplayName,
 					State:           string(*sub.State),
 					TenantID:        tenantID,
-					EnvironmentName: "AzureCloud",
+					EnvironmentName: ActiveCloud().Name,
 					IsDefault:       false,
 				}
 
@@ -359,7 +361,7 @@ func AuthenticateForTenant(ctx context.Context, tenantID string) (azcore.TokenCr
 		return NewBrokerCredential(tenantID, true)
 	}
 
-	authority := fmt.Sprintf("https://login.microsoftonline.com/%s", tenantID)
+	authority := Authority(tenantID)
 
 	cacheAccessor, err := GetSharedMSALCache()
 	if err != nil {
@@ -375,7 +377,7 @@ func AuthenticateForTenant(ctx context.Context, tenantID string) (azcore.TokenCr
 		return nil, fmt.Errorf("failed to create MSAL client for tenant: %w", err)
 	}
 
-	scopes := []string{"https://management.azure.com/.default"}
+	scopes := []string{ARMScope()}
 
 	// Interactive auth targeting this specific tenant — will trigger MFA
 	result, err := client.AcquireTokenInteractive(ctx, scopes, interactiveOpts()...)
@@ -399,7 +401,7 @@ func AuthenticateForTenant(ctx context.Context, tenantID string) (azcore.TokenCr
 
 // DiscoverTenantSubscriptions lists subscriptions for a single tenant using the provided credential
 func DiscoverTenantSubscriptions(ctx context.Context, tenantID string, cred azcore.TokenCredential) ([]config.Subscription, error) {
-	subsClient, err := armsubscriptions.NewClient(cred, nil)
+	subsClient, err := armsubscriptions.NewClient(cred, ARMClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create subscriptions client: %w", err)
 	}
@@ -422,7 +424,7 @@ func DiscoverTenantSubscriptions(ctx context.Context, tenantID string, cred azco
 				Name:            *sub.DisplayName,
 				State:           string(*sub.State),
 				TenantID:        tenantID,
-				EnvironmentName: "AzureCloud",
+				EnvironmentName: ActiveCloud().Name,
 				IsDefault:       false,
 			})
 		}
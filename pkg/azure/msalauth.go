@@ -32,7 +32,7 @@ func interactiveOpts() []public.AcquireInteractiveOption {
 func NewMSALInteractiveCredential() (*MSALInteractiveCredential, error) {
 	// Create MSAL PublicClientApplication with "organizations" authority
 	// This is the same as Python CLI's initial authentication
-	authority := "https://login.microsoftonline.com/organizations"
+	authority := Authority("organizations")
 
 	// Get shared cache
 	cacheAccessor, err := GetSharedMSALCache()
@@ -51,7 +51,7 @@ func NewMSALInteractiveCredential() (*MSALInteractiveCredential, error) {
 
 	return &MSALInteractiveCredential{
 		client: client,
-		scopes: []string{"https://management.azure.com/.default"},
+		scopes: []string{ARMScope()},
 	}, nil
 }
 
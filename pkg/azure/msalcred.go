@@ -23,7 +23,7 @@ type MSALSilentCredential struct {
 func NewMSALSilentCredential(tenantID string, authRecord azidentity.AuthenticationRecord) (*MSALSilentCredential, error) {
 	// Create MSAL PublicClientApplication for this specific tenant
 	// This matches Python CLI: new PublicClientApplication per tenant with tenant-specific authority
-	authority := fmt.Sprintf("https://login.microsoftonline.com/%s", tenantID)
+	authority := Authority(tenantID)
 
 	// Get shared cache - this is critical for silent token acquisition
 	cacheAccessor, err := GetSharedMSALCache()
@@ -63,7 +63,7 @@ func (m *MSALSilentCredential) GetToken(ctx context.Context, opts policy.TokenRe
 	// Use the scopes from opts if provided, otherwise fall back to management API
 	scopes := opts.Scopes
 	if len(scopes) == 0 {
-		scopes = []string{"https://management.azure.com/.default"}
+		scopes = []string{ARMScope()}
 	}
 
 	// Use AcquireTokenSilent - this ONLY uses cache and refresh tokens (no user interaction)
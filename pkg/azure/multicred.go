@@ -2,6 +2,7 @@ package azure
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -16,21 +17,27 @@ func GetCredentialWithTenantSupport() (azcore.TokenCredential, error) {
 		return nil, fmt.Errorf("not authenticated. Please run 'az login' first: %w", err)
 	}
 
-	if profile.AuthMode != AuthModeAzureCLI && profile.AuthenticationRecord == nil {
+	if usesAuthRecord(profile.AuthMode) && profile.AuthenticationRecord == nil {
 		return nil, fmt.Errorf("no authentication record found. Please run 'az login'")
 	}
 
 	// Get the tenant ID from the default subscription
-	var tenantID string
+	var tenantID, environment string
 	for _, sub := range profile.Subscriptions {
 		if sub.IsDefault {
-			tenantID = sub.TenantID
+			tenantID, environment = sub.TenantID, sub.EnvironmentName
 			break
 		}
 	}
 
 	if tenantID == "" && len(profile.Subscriptions) > 0 {
-		tenantID = profile.Subscriptions[0].TenantID
+		tenantID, environment = profile.Subscriptions[0].TenantID, profile.Subscriptions[0].EnvironmentName
+	}
+
+	// Tokens from one cloud are useless against another's endpoints, so fail
+	// up front rather than with an opaque 401 after `az cloud set`.
+	if environment != "" && !strings.EqualFold(environment, ActiveCloud().Name) {
+		return nil, fmt.Errorf("logged in to cloud '%s' but the active cloud is '%s'. Run 'az login' to log in to this cloud, or 'az cloud set -n %s' to switch back", environment, ActiveCloud().Name, environment)
 	}
 
 	if tenantID == "" {
@@ -0,0 +1,56 @@
+package azure
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+)
+
+// requestLogPolicy records every attempt an SDK client makes, with the IDs
+// Azure support asks for when a request needs investigating. It runs per
+// retry so each attempt keeps its own request ID.
+type requestLogPolicy struct{}
+
+func (requestLogPolicy) Do(req *policy.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := req.Next()
+	raw := req.Raw()
+
+	attrs := []slog.Attr{
+		slog.String("method", raw.Method),
+		slog.String("url", logURL(raw.URL)),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+	if id := raw.Header.Get("x-ms-client-request-id"); id != "" {
+		attrs = append(attrs, slog.String("client_request_id", id))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		logger.LogAttrs(raw.Context(), logger.LevelVerbose, "HTTP request failed", attrs...)
+		return resp, err
+	}
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if id := resp.Header.Get("x-ms-request-id"); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if id := resp.Header.Get("x-ms-correlation-request-id"); id != "" {
+		attrs = append(attrs, slog.String("correlation_request_id", id))
+	}
+	logger.LogAttrs(raw.Context(), logger.LevelVerbose, "HTTP request", attrs...)
+	return resp, err
+}
+
+// logURL drops the query string except api-version: data-plane URLs can
+// carry SAS tokens, which mustn't end up in a log file.
+func logURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = ""
+	if v := u.Query().Get("api-version"); v != "" {
+		c.RawQuery = url.Values{"api-version": {v}}.Encode()
+	}
+	return c.String()
+}
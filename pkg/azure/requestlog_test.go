@@ -0,0 +1,74 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+)
+
+type fakeTransport struct{}
+
+func (fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	h := http.Header{}
+	h.Set("x-ms-request-id", "req-1")
+	h.Set("x-ms-correlation-request-id", "corr-1")
+	return &http.Response{StatusCode: 200, Header: h, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+}
+
+func TestRequestLogPolicy(t *testing.T) {
+	var buf bytes.Buffer
+	old := logger.Output
+	logger.Output = &buf
+	t.Cleanup(func() {
+		logger.Output = old
+		logger.Configure(logger.Options{Level: slog.LevelInfo})
+	})
+	logger.Configure(logger.Options{Level: logger.LevelVerbose, Format: logger.FormatJSON})
+
+	opts := ClientOptions()
+	opts.Transport = fakeTransport{}
+	pl := runtime.NewPipeline("test", "v0", runtime.PipelineOptions{}, &opts)
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://example.blob.core.windows.net/c?sig=secret&api-version=2024-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pl.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	var rec map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		json.Unmarshal([]byte(line), &rec)
+		if rec["msg"] == "HTTP request" {
+			break
+		}
+	}
+	if rec["msg"] != "HTTP request" {
+		t.Fatalf("no request record in %q", buf.String())
+	}
+	if rec["request_id"] != "req-1" || rec["correlation_request_id"] != "corr-1" || rec["status"] != float64(200) || rec["method"] != "GET" {
+		t.Errorf("record = %v", rec)
+	}
+	if _, ok := rec["duration_ms"]; !ok {
+		t.Error("no duration")
+	}
+	if strings.Contains(rec["url"].(string), "secret") {
+		t.Errorf("query leaked: %v", rec["url"])
+	}
+}
+
+func TestLogURL(t *testing.T) {
+	u, _ := url.Parse("https://management.azure.com/subscriptions/s?api-version=2022-01-01&$filter=x")
+	if got := logURL(u); got != "https://management.azure.com/subscriptions/s?api-version=2022-01-01" {
+		t.Errorf("got %s", got)
+	}
+}
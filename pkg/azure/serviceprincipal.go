@@ -0,0 +1,111 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+)
+
+// AuthModeServicePrincipal signs in as an application with a client secret
+// or certificate saved by `az login --service-principal`.
+const AuthModeServicePrincipal = "service-principal"
+
+// AuthModeFederatedToken signs in as an application with a federated (OIDC)
+// token, e.g. from GitHub Actions or Azure Pipelines workload identity.
+const AuthModeFederatedToken = "federated-token"
+
+// AuthModeManagedIdentity signs in as the managed identity of the Azure
+// host (VM, App Service, AKS pod, ...) the CLI runs on.
+const AuthModeManagedIdentity = "managed-identity"
+
+// currentManagedIdentity is the user-assigned identity from this process's
+// sign-in. Login authenticates before a profile exists to read it from.
+var currentManagedIdentity *config.ManagedIdentity
+
+// SetManagedIdentity selects the user-assigned identity used in
+// managed-identity mode. Pass nil for the system-assigned identity.
+func SetManagedIdentity(mi *config.ManagedIdentity) {
+	currentManagedIdentity = mi
+}
+
+// IsNonInteractive reports whether mode signs in without a user, in which
+// case there is no MSAL authentication record and the identity is fixed at
+// login time.
+func IsNonInteractive(mode string) bool {
+	switch mode {
+	case AuthModeServicePrincipal, AuthModeFederatedToken, AuthModeManagedIdentity:
+		return true
+	}
+	return false
+}
+
+// usesAuthRecord reports whether mode keeps an MSAL authentication record in
+// the profile. azure-cli mode borrows the Python Azure CLI's session and the
+// non-interactive modes have no user account to record.
+func usesAuthRecord(mode string) bool {
+	return mode != AuthModeAzureCLI && !IsNonInteractive(mode)
+}
+
+// servicePrincipalCredential builds the application credential saved by the
+// last service principal or federated token login, scoped to tenantID ("" for
+// the login tenant). Multi-tenant applications can reach any tenant they are
+// provisioned in.
+func servicePrincipalCredential(tenantID string) (azcore.TokenCredential, error) {
+	entry, err := config.LoadServicePrincipal()
+	if err != nil {
+		return nil, err
+	}
+	if tenantID == "" {
+		tenantID = entry.TenantID
+	}
+
+	switch {
+	case entry.ClientAssertion != "":
+		assertion := entry.ClientAssertion
+		return azidentity.NewClientAssertionCredential(tenantID, entry.ClientID, func(context.Context) (string, error) {
+			return assertion, nil
+		}, &azidentity.ClientAssertionCredentialOptions{ClientOptions: ClientOptions()})
+	case entry.CertificatePath != "":
+		data, err := os.ReadFile(entry.CertificatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate: %w", err)
+		}
+		certs, key, err := azidentity.ParseCertificates(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %s: %w", entry.CertificatePath, err)
+		}
+		return azidentity.NewClientCertificateCredential(tenantID, entry.ClientID, certs, key, &azidentity.ClientCertificateCredentialOptions{ClientOptions: ClientOptions()})
+	case entry.ClientSecret != "":
+		return azidentity.NewClientSecretCredential(tenantID, entry.ClientID, entry.ClientSecret, &azidentity.ClientSecretCredentialOptions{ClientOptions: ClientOptions()})
+	}
+	return nil, fmt.Errorf("service principal %s has no secret, certificate or federated token. Please run 'az login --service-principal' again", entry.ClientID)
+}
+
+// managedIdentityCredential builds the managed identity credential for the
+// identity chosen at login: this process's override if set, otherwise the
+// saved profile's.
+func managedIdentityCredential() (azcore.TokenCredential, error) {
+	mi := currentManagedIdentity
+	if mi == nil {
+		if profile, err := config.Load(); err == nil {
+			mi = profile.ManagedIdentity
+		}
+	}
+
+	opts := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: ClientOptions()}
+	if mi != nil {
+		switch {
+		case mi.ClientID != "":
+			opts.ID = azidentity.ClientID(mi.ClientID)
+		case mi.ObjectID != "":
+			opts.ID = azidentity.ObjectID(mi.ObjectID)
+		case mi.ResourceID != "":
+			opts.ID = azidentity.ResourceID(mi.ResourceID)
+		}
+	}
+	return azidentity.NewManagedIdentityCredential(opts)
+}
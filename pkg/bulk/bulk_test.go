@@ -0,0 +1,182 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const vmType = "Microsoft.Compute/virtualMachines"
+
+func vmID(sub, group, name string) string {
+	return "/subscriptions/" + sub + "/resourceGroups/" + group + "/providers/Microsoft.Compute/virtualMachines/" + name
+}
+
+func testCommand(stdin string, args ...string) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	cmd := &cobra.Command{Use: "show"}
+	cmd.Flags().String("output", "json", "")
+	cmd.Flags().String("query", "", "")
+	cmd.Flags().String("subscription", "", "")
+	cmd.Flags().StringP("name", "n", "", "")
+	cmd.Flags().StringP("resource-group", "g", "", "")
+	AddIDsFlag(cmd)
+	cmd.ParseFlags(args)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetIn(strings.NewReader(stdin))
+	return cmd, &out, &errOut
+}
+
+func TestRunAggregatesInOrderAndReportsFailures(t *testing.T) {
+	ids := []string{vmID("s1", "rg", "a"), vmID("s2", "rg", "b"), vmID("s1", "rg", "bad"), vmID("s1", "rg", "c")}
+	cmd, out, errOut := testCommand("", "--ids", ids[0]+","+ids[1], ids[2], ids[3])
+
+	var inFlight, peak int32
+	err := Run(cmd, vmType, func(ctx context.Context, tg Target) (interface{}, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		// Finish in reverse order so ordering can't come from timing.
+		time.Sleep(time.Duration(5-len(tg.Name)) * time.Millisecond)
+		if tg.Name == "bad" {
+			return nil, errors.New("not found")
+		}
+		return map[string]string{"name": tg.Name, "sub": tg.SubscriptionID}, nil
+	})
+	if err == nil || err.Error() != "1 of 4 resources failed" {
+		t.Fatalf("err = %v", err)
+	}
+	if !strings.Contains(errOut.String(), "ERROR: "+ids[2]+": not found") {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+
+	var got []map[string]string
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output %q: %v", out.String(), err)
+	}
+	if len(got) != 3 || got[0]["name"] != "a" || got[1]["name"] != "b" || got[1]["sub"] != "s2" || got[2]["name"] != "c" {
+		t.Errorf("got %v", got)
+	}
+	if peak > MaxParallel {
+		t.Errorf("%d operations in flight, limit %d", peak, MaxParallel)
+	}
+}
+
+func TestRunReadsIDsFromStdin(t *testing.T) {
+	stdin := vmID("s", "rg", "a") + "\n\n  " + vmID("s", "rg", "b") + "  \n" + vmID("s", "RG", "A") + "\n"
+	cmd, _, _ := testCommand(stdin, "--ids", "@-")
+
+	var names []string
+	ch := make(chan string, 3)
+	err := Run(cmd, vmType, func(ctx context.Context, tg Target) (interface{}, error) {
+		ch <- tg.Name
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(ch)
+	for n := range ch {
+		names = append(names, n)
+	}
+	// Blank lines are skipped and the case-insensitive duplicate dropped.
+	if len(names) != 2 {
+		t.Errorf("called for %v", names)
+	}
+}
+
+func TestRunRejectsWrongResourceType(t *testing.T) {
+	nsg := "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/n"
+	cmd, _, errOut := testCommand("", "--ids", nsg+","+vmID("s", "rg", "ok"))
+	var called int32
+	err := Run(cmd, vmType, func(ctx context.Context, tg Target) (interface{}, error) {
+		atomic.AddInt32(&called, 1)
+		return nil, nil
+	})
+	if err == nil || called != 1 {
+		t.Fatalf("err = %v, called = %d", err, called)
+	}
+	if !strings.Contains(errOut.String(), "not a "+vmType) {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+}
+
+func TestRunSingleResource(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AZ_SESSION", "")
+	os.MkdirAll(filepath.Join(home, ".azure"), 0700)
+	profile := `{"subscriptions": [{"id": "00000000-0000-0000-0000-000000000000", "name": "Dev", "isDefault": true}]}`
+	os.WriteFile(filepath.Join(home, ".azure", "azureProfile.json"), []byte(profile), 0600)
+
+	cmd, out, _ := testCommand("", "-n", "vm1", "-g", "rg1", "--subscription", "Dev")
+	err := Run(cmd, vmType, func(ctx context.Context, tg Target) (interface{}, error) {
+		return tg, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Target
+	json.Unmarshal(out.Bytes(), &got)
+	if got.Name != "vm1" || got.ResourceGroup != "rg1" || got.SubscriptionID != "00000000-0000-0000-0000-000000000000" {
+		t.Errorf("got %+v", got)
+	}
+
+	// Without -g the command acts on resource groups, named by -n alone.
+	groupCmd := &cobra.Command{Use: "show"}
+	groupCmd.Flags().String("output", "json", "")
+	groupCmd.Flags().String("query", "", "")
+	groupCmd.Flags().String("subscription", "", "")
+	groupCmd.Flags().StringP("name", "n", "", "")
+	AddIDsFlag(groupCmd)
+	groupCmd.ParseFlags([]string{"-n", "rg2"})
+	out.Reset()
+	groupCmd.SetOut(out)
+	if err := Run(groupCmd, "Microsoft.Resources/resourceGroups", func(ctx context.Context, tg Target) (interface{}, error) {
+		return tg, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got = Target{}
+	json.Unmarshal(out.Bytes(), &got)
+	if got.Name != "rg2" || got.ResourceGroup != "rg2" {
+		t.Errorf("resource group target = %+v", got)
+	}
+
+	// A single --ids entry prints an object too, not a one-element array.
+	cmd, out, _ = testCommand("", "--ids", vmID("s", "rg", "vm2"))
+	Run(cmd, vmType, func(ctx context.Context, tg Target) (interface{}, error) { return tg, nil })
+	if !strings.HasPrefix(strings.TrimSpace(out.String()), "{") {
+		t.Errorf("single --ids output = %q", out.String())
+	}
+}
+
+func TestRunArgumentErrors(t *testing.T) {
+	noop := func(context.Context, Target) (interface{}, error) { return nil, nil }
+	tests := map[string][]string{
+		"please specify either --ids": {"-n", "vm1"},
+		"cannot mix --ids":            {"--ids", vmID("s", "rg", "a"), "-n", "vm1"},
+		"no resource IDs were found":  {"--ids", "@-"},
+	}
+	for want, args := range tests {
+		cmd, _, _ := testCommand("\n", args...)
+		if err := Run(cmd, vmType, noop); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%v: err = %v, want %q", args, err, want)
+		}
+	}
+}
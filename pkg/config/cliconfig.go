@@ -0,0 +1,291 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CLIConfigFile holds `az config` settings such as defaults.group and
+// core.output, in the same INI format as the Python Azure CLI's ~/.azure/config.
+const CLIConfigFile = "config"
+
+// ConfigEntry is one resolved setting as shown by `az config get`. Source is
+// the file it came from, or the environment variable that overrides it.
+type ConfigEntry struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Value  string `json:"value"`
+}
+
+// configValues is a parsed config file: section -> key -> value.
+type configValues map[string]map[string]string
+
+// GetCLIConfigPath returns the path of the global config file,
+// session-specific when AZ_SESSION is set.
+func GetCLIConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	filename := CLIConfigFile
+	if session := os.Getenv("AZ_SESSION"); session != "" {
+		filename = fmt.Sprintf("config-%s", session)
+	}
+
+	return filepath.Join(home, ConfigDir, filename), nil
+}
+
+// FindLocalCLIConfig returns the nearest .azure/config in the working
+// directory or one of its parents, or "" if there is none. The global file is
+// never treated as a local one, so running from $HOME isn't special.
+func FindLocalCLIConfig() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	global, _ := GetCLIConfigPath()
+	for {
+		path := filepath.Join(dir, ConfigDir, CLIConfigFile)
+		if path != global {
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// localCLIConfigTarget is the file `az config set --local` writes: the
+// nearest existing local config, else one in the working directory.
+func localCLIConfigTarget() (string, error) {
+	if path := FindLocalCLIConfig(); path != "" {
+		return path, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(dir, ConfigDir, CLIConfigFile), nil
+}
+
+// SplitConfigKey splits "section.key" into its lowercased parts.
+func SplitConfigKey(name string) (section, key string, err error) {
+	parts := strings.SplitN(strings.ToLower(strings.TrimSpace(name)), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid config key %q: expected <section>.<key>, e.g. defaults.group", name)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ListConfig returns every setting visible from the working directory, by
+// section. Local settings override global ones, and environment variables
+// named AZURE_<SECTION>_<KEY> (e.g. AZURE_DEFAULTS_GROUP) override both.
+func ListConfig() (map[string][]ConfigEntry, error) {
+	merged := map[string]map[string]ConfigEntry{}
+	add := func(values configValues, source string) {
+		for section, keys := range values {
+			if merged[section] == nil {
+				merged[section] = map[string]ConfigEntry{}
+			}
+			for key, value := range keys {
+				merged[section][key] = ConfigEntry{Name: key, Source: source, Value: value}
+			}
+		}
+	}
+
+	globalPath, err := GetCLIConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	global, err := readConfigValues(globalPath)
+	if err != nil {
+		return nil, err
+	}
+	add(global, globalPath)
+
+	if localPath := FindLocalCLIConfig(); localPath != "" {
+		local, err := readConfigValues(localPath)
+		if err != nil {
+			return nil, err
+		}
+		add(local, localPath)
+	}
+
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		section, key, ok := configKeyFromEnv(name)
+		if !ok {
+			continue
+		}
+		if merged[section] == nil {
+			merged[section] = map[string]ConfigEntry{}
+		}
+		merged[section][key] = ConfigEntry{Name: key, Source: name, Value: value}
+	}
+
+	result := map[string][]ConfigEntry{}
+	for section, keys := range merged {
+		entries := make([]ConfigEntry, 0, len(keys))
+		for _, e := range keys {
+			entries = append(entries, e)
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		result[section] = entries
+	}
+	return result, nil
+}
+
+// configKeyFromEnv maps AZURE_DEFAULTS_GROUP to ("defaults", "group"). Only
+// the sections `az config` documents are recognised, so unrelated AZURE_*
+// variables (AZURE_CLIENT_ID, ...) don't show up as settings.
+func configKeyFromEnv(name string) (section, key string, ok bool) {
+	for _, s := range []string{"core", "defaults", "bicep"} {
+		prefix := "AZURE_" + strings.ToUpper(s) + "_"
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return s, strings.ToLower(name[len(prefix):]), true
+		}
+	}
+	return "", "", false
+}
+
+// GetConfigSection returns the resolved settings of one section.
+func GetConfigSection(section string) ([]ConfigEntry, error) {
+	all, err := ListConfig()
+	if err != nil {
+		return nil, err
+	}
+	return all[strings.ToLower(section)], nil
+}
+
+// GetConfigValue returns the resolved value of section.key and whether it
+// is set anywhere.
+func GetConfigValue(section, key string) (ConfigEntry, bool, error) {
+	entries, err := GetConfigSection(section)
+	if err != nil {
+		return ConfigEntry{}, false, err
+	}
+	key = strings.ToLower(key)
+	for _, e := range entries {
+		if e.Name == key {
+			return e, true, nil
+		}
+	}
+	return ConfigEntry{}, false, nil
+}
+
+// SetConfigValue writes section.key to the global config, or to the
+// directory-local one when local is true.
+func SetConfigValue(section, key, value string, local bool) error {
+	return updateConfigFile(local, func(values configValues) {
+		section, key = strings.ToLower(section), strings.ToLower(key)
+		if values[section] == nil {
+			values[section] = map[string]string{}
+		}
+		values[section][key] = value
+	})
+}
+
+// UnsetConfigValue removes section.key from the global or local config.
+// Removing a key that isn't set is not an error.
+func UnsetConfigValue(section, key string, local bool) error {
+	return updateConfigFile(local, func(values configValues) {
+		section, key = strings.ToLower(section), strings.ToLower(key)
+		delete(values[section], key)
+		if len(values[section]) == 0 {
+			delete(values, section)
+		}
+	})
+}
+
+func updateConfigFile(local bool, update func(configValues)) error {
+	var path string
+	var err error
+	if local {
+		path, err = localCLIConfigTarget()
+	} else {
+		path, err = GetCLIConfigPath()
+	}
+	if err != nil {
+		return err
+	}
+
+	values, err := readConfigValues(path)
+	if err != nil {
+		return err
+	}
+	update(values)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(formatConfigValues(values)), 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// readConfigValues parses an INI config file. A missing file is empty.
+func readConfigValues(path string) (configValues, error) {
+	values := configValues{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	var section string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(strings.Trim(line, "[]")))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section == "" {
+			continue
+		}
+		if values[section] == nil {
+			values[section] = map[string]string{}
+		}
+		values[section][strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return values, nil
+}
+
+func formatConfigValues(values configValues) string {
+	sections := make([]string, 0, len(values))
+	for s := range values {
+		sections = append(sections, s)
+	}
+	sort.Strings(sections)
+
+	var b strings.Builder
+	for i, s := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", s)
+		keys := make([]string, 0, len(values[s]))
+		for k := range values[s] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s = %s\n", k, values[s][k])
+		}
+	}
+	return b.String()
+}
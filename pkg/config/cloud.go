@@ -0,0 +1,319 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CloudsFile holds custom registered clouds and the name of the active one.
+// It is shared by every AZ_SESSION, like the Python Azure CLI's clouds.config.
+const CloudsFile = "clouds.json"
+
+// DefaultCloudName is the public Azure cloud, active until `az cloud set`
+// picks another.
+const DefaultCloudName = "AzureCloud"
+
+// Cloud describes a set of Azure endpoints. The JSON shape matches
+// `az cloud show` in the Python Azure CLI.
+type Cloud struct {
+	Name      string         `json:"name"`
+	Profile   string         `json:"profile"`
+	IsActive  bool           `json:"isActive"`
+	Endpoints CloudEndpoints `json:"endpoints"`
+	Suffixes  CloudSuffixes  `json:"suffixes"`
+}
+
+// CloudEndpoints are the service URLs of a cloud.
+type CloudEndpoints struct {
+	ActiveDirectory                string `json:"activeDirectory"`
+	ActiveDirectoryGraphResourceID string `json:"activeDirectoryGraphResourceId,omitempty"`
+	ActiveDirectoryResourceID      string `json:"activeDirectoryResourceId"`
+	Gallery                        string `json:"gallery,omitempty"`
+	LogAnalyticsResourceID         string `json:"logAnalyticsResourceId,omitempty"`
+	Management                     string `json:"management,omitempty"`
+	MicrosoftGraphResourceID       string `json:"microsoftGraphResourceId"`
+	Portal                         string `json:"portal,omitempty"`
+	ResourceManager                string `json:"resourceManager"`
+}
+
+// CloudSuffixes are the DNS suffixes of a cloud's data-plane services.
+type CloudSuffixes struct {
+	AcrLoginServerEndpoint   string `json:"acrLoginServerEndpoint,omitempty"`
+	KeyvaultDNS              string `json:"keyvaultDns"`
+	PostgresqlServerEndpoint string `json:"postgresqlServerEndpoint,omitempty"`
+	SQLServerHostname        string `json:"sqlServerHostname,omitempty"`
+	StorageEndpoint          string `json:"storageEndpoint"`
+}
+
+// BuiltinClouds are the clouds every install knows about. They can't be
+// unregistered or overwritten.
+var BuiltinClouds = []Cloud{
+	{
+		Name:    "AzureCloud",
+		Profile: "latest",
+		Endpoints: CloudEndpoints{
+			ActiveDirectory:                "https://login.microsoftonline.com",
+			ActiveDirectoryGraphResourceID: "https://graph.windows.net/",
+			ActiveDirectoryResourceID:      "https://management.core.windows.net/",
+			Gallery:                        "https://gallery.azure.com/",
+			LogAnalyticsResourceID:         "https://api.loganalytics.io",
+			Management:                     "https://management.core.windows.net/",
+			MicrosoftGraphResourceID:       "https://graph.microsoft.com/",
+			Portal:                         "https://portal.azure.com",
+			ResourceManager:                "https://management.azure.com/",
+		},
+		Suffixes: CloudSuffixes{
+			AcrLoginServerEndpoint:   ".azurecr.io",
+			KeyvaultDNS:              ".vault.azure.net",
+			PostgresqlServerEndpoint: ".postgres.database.azure.com",
+			SQLServerHostname:        ".database.windows.net",
+			StorageEndpoint:          "core.windows.net",
+		},
+	},
+	{
+		Name:    "AzureChinaCloud",
+		Profile: "latest",
+		Endpoints: CloudEndpoints{
+			ActiveDirectory:                "https://login.chinacloudapi.cn",
+			ActiveDirectoryGraphResourceID: "https://graph.chinacloudapi.cn/",
+			ActiveDirectoryResourceID:      "https://management.core.chinacloudapi.cn/",
+			LogAnalyticsResourceID:         "https://api.loganalytics.azure.cn",
+			Management:                     "https://management.core.chinacloudapi.cn/",
+			MicrosoftGraphResourceID:       "https://microsoftgraph.chinacloudapi.cn/",
+			Portal:                         "https://portal.azure.cn",
+			ResourceManager:                "https://management.chinacloudapi.cn/",
+		},
+		Suffixes: CloudSuffixes{
+			AcrLoginServerEndpoint:   ".azurecr.cn",
+			KeyvaultDNS:              ".vault.azure.cn",
+			PostgresqlServerEndpoint: ".postgres.database.chinacloudapi.cn",
+			SQLServerHostname:        ".database.chinacloudapi.cn",
+			StorageEndpoint:          "core.chinacloudapi.cn",
+		},
+	},
+	{
+		Name:    "AzureUSGovernment",
+		Profile: "latest",
+		Endpoints: CloudEndpoints{
+			ActiveDirectory:                "https://login.microsoftonline.us",
+			ActiveDirectoryGraphResourceID: "https://graph.windows.net/",
+			ActiveDirectoryResourceID:      "https://management.core.usgovcloudapi.net/",
+			Gallery:                        "https://gallery.usgovcloudapi.net/",
+			LogAnalyticsResourceID:         "https://api.loganalytics.us",
+			Management:                     "https://management.core.usgovcloudapi.net/",
+			MicrosoftGraphResourceID:       "https://graph.microsoft.us/",
+			Portal:                         "https://portal.azure.us",
+			ResourceManager:                "https://management.usgovcloudapi.net/",
+		},
+		Suffixes: CloudSuffixes{
+			AcrLoginServerEndpoint:   ".azurecr.us",
+			KeyvaultDNS:              ".vault.usgovcloudapi.net",
+			PostgresqlServerEndpoint: ".postgres.database.usgovcloudapi.net",
+			SQLServerHostname:        ".database.usgovcloudapi.net",
+			StorageEndpoint:          "core.usgovcloudapi.net",
+		},
+	},
+}
+
+// cloudsConfig is the on-disk form of CloudsFile.
+type cloudsConfig struct {
+	Active string  `json:"active,omitempty"`
+	Custom []Cloud `json:"custom,omitempty"`
+}
+
+// GetCloudsPath returns the path of the clouds file.
+func GetCloudsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ConfigDir, CloudsFile), nil
+}
+
+func loadCloudsConfig() (*cloudsConfig, error) {
+	path, err := GetCloudsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &cloudsConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read clouds: %w", err)
+	}
+
+	var cfg cloudsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse clouds: %w", err)
+	}
+	return &cfg, nil
+}
+
+func saveCloudsConfig(cfg *cloudsConfig) error {
+	path, err := GetCloudsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal clouds: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write clouds: %w", err)
+	}
+	return nil
+}
+
+// ListClouds returns the built-in clouds followed by the custom ones, sorted
+// by name within each group, with IsActive set on the active cloud.
+func ListClouds() ([]Cloud, error) {
+	cfg, err := loadCloudsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	active := cfg.Active
+	if active == "" {
+		active = DefaultCloudName
+	}
+
+	custom := append([]Cloud(nil), cfg.Custom...)
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+
+	clouds := append(append([]Cloud(nil), BuiltinClouds...), custom...)
+	for i := range clouds {
+		clouds[i].IsActive = strings.EqualFold(clouds[i].Name, active)
+	}
+	return clouds, nil
+}
+
+// GetCloud returns the named cloud (case-insensitive).
+func GetCloud(name string) (*Cloud, error) {
+	clouds, err := ListClouds()
+	if err != nil {
+		return nil, err
+	}
+	for i := range clouds {
+		if strings.EqualFold(clouds[i].Name, name) {
+			return &clouds[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cloud '%s' is not registered. Use 'az cloud list' to see the available clouds", name)
+}
+
+// GetActiveCloud returns the active cloud. A missing or unreadable clouds
+// file, or an active cloud that has since been unregistered, falls back to
+// the public cloud rather than failing every command.
+func GetActiveCloud() *Cloud {
+	clouds, err := ListClouds()
+	if err == nil {
+		for i := range clouds {
+			if clouds[i].IsActive {
+				return &clouds[i]
+			}
+		}
+	}
+	c := BuiltinClouds[0]
+	c.IsActive = true
+	return &c
+}
+
+// SetActiveCloud makes the named cloud active for later commands.
+func SetActiveCloud(name string) (*Cloud, error) {
+	c, err := GetCloud(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadCloudsConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Active = c.Name
+	if err := saveCloudsConfig(cfg); err != nil {
+		return nil, err
+	}
+	c.IsActive = true
+	return c, nil
+}
+
+// IsBuiltinCloud reports whether name is one of BuiltinClouds.
+func IsBuiltinCloud(name string) bool {
+	for _, c := range BuiltinClouds {
+		if strings.EqualFold(c.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterCloud adds a custom cloud, or replaces the custom cloud of the same
+// name when update is true.
+func RegisterCloud(c Cloud, update bool) error {
+	if c.Name == "" {
+		return fmt.Errorf("cloud name is required")
+	}
+	if IsBuiltinCloud(c.Name) {
+		return fmt.Errorf("cloud '%s' is a built-in cloud and cannot be modified", c.Name)
+	}
+	if c.Endpoints.ResourceManager == "" || c.Endpoints.ActiveDirectory == "" {
+		return fmt.Errorf("cloud '%s' needs at least the resource manager and active directory endpoints", c.Name)
+	}
+	if c.Profile == "" {
+		c.Profile = "latest"
+	}
+	c.IsActive = false
+
+	cfg, err := loadCloudsConfig()
+	if err != nil {
+		return err
+	}
+	for i := range cfg.Custom {
+		if strings.EqualFold(cfg.Custom[i].Name, c.Name) {
+			if !update {
+				return fmt.Errorf("cloud '%s' is already registered. Use 'az cloud update' to change it", c.Name)
+			}
+			cfg.Custom[i] = c
+			return saveCloudsConfig(cfg)
+		}
+	}
+	if update {
+		return fmt.Errorf("cloud '%s' is not registered", c.Name)
+	}
+	cfg.Custom = append(cfg.Custom, c)
+	return saveCloudsConfig(cfg)
+}
+
+// UnregisterCloud removes a custom cloud. The active cloud can't be removed.
+func UnregisterCloud(name string) error {
+	if IsBuiltinCloud(name) {
+		return fmt.Errorf("cloud '%s' is a built-in cloud and cannot be unregistered", name)
+	}
+
+	cfg, err := loadCloudsConfig()
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(cfg.Active, name) {
+		return fmt.Errorf("cloud '%s' is the active cloud. Switch to another cloud with 'az cloud set' first", name)
+	}
+	for i := range cfg.Custom {
+		if strings.EqualFold(cfg.Custom[i].Name, name) {
+			cfg.Custom = append(cfg.Custom[:i], cfg.Custom[i+1:]...)
+			return saveCloudsConfig(cfg)
+		}
+	}
+	return fmt.Errorf("cloud '%s' is not registered", name)
+}
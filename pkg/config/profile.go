@@ -20,12 +20,24 @@ type Profile struct {
 	Subscriptions        []Subscription                   `json:"subscriptions"`
 	AuthenticationRecord *azidentity.AuthenticationRecord `json:"authenticationRecord,omitempty"`
 	// AuthMode is empty for the default MSAL interactive flow, "azure-cli" to
-	// borrow tokens from the Python Azure CLI (az), or "broker" for the
-	// Windows WAM broker.
+	// borrow tokens from the Python Azure CLI (az), "broker" for the
+	// Windows WAM broker, or one of the non-interactive modes:
+	// "service-principal", "federated-token" or "managed-identity".
 	AuthMode string `json:"authMode,omitempty"`
 	// BrokerAccountID identifies the WAM broker account this login used. Only
 	// set in "broker" mode, where it keys every later silent acquisition.
 	BrokerAccountID string `json:"brokerAccountId,omitempty"`
+	// ManagedIdentity selects the user-assigned identity for
+	// "managed-identity" mode. Nil means the system-assigned identity.
+	ManagedIdentity *ManagedIdentity `json:"managedIdentity,omitempty"`
+}
+
+// ManagedIdentity identifies a user-assigned managed identity. At most one
+// field is set.
+type ManagedIdentity struct {
+	ClientID   string `json:"clientId,omitempty"`
+	ObjectID   string `json:"objectId,omitempty"`
+	ResourceID string `json:"resourceId,omitempty"`
 }
 
 type Subscription struct {
@@ -216,6 +228,13 @@ func deleteProfile(keepMSALCache bool) error {
 			}
 		}
 
+		// Remove service principal credentials from a non-interactive login
+		if spPath, err := GetServicePrincipalPath(); err == nil {
+			if _, err := os.Stat(spPath); err == nil {
+				_ = os.Remove(spPath) // Ignore errors, best effort
+			}
+		}
+
 		// Remove MSAL HTTP cache (created by Azure SDK)
 		msalHttpCache := filepath.Join(azureDir, "msal_http_cache.bin")
 		if _, err := os.Stat(msalHttpCache); err == nil {
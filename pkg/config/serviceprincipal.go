@@ -0,0 +1,95 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ServicePrincipalFile holds the credential material of a non-interactive
+// login. Like the Python Azure CLI's file of the same name it is kept apart
+// from azureProfile.json, so the profile can be shared or printed without
+// leaking secrets.
+const ServicePrincipalFile = "service_principal_entries.json"
+
+// ServicePrincipalEntry is the credential for "service-principal" and
+// "federated-token" logins. Exactly one of ClientSecret, CertificatePath and
+// ClientAssertion is set.
+type ServicePrincipalEntry struct {
+	ClientID string `json:"client_id"`
+	TenantID string `json:"tenant"`
+	// ClientSecret is the application password.
+	ClientSecret string `json:"client_secret,omitempty"`
+	// CertificatePath points at a PEM or PFX file holding the certificate
+	// and its private key. The path is stored rather than the key itself,
+	// so rotating the file in place keeps working.
+	CertificatePath string `json:"certificate,omitempty"`
+	// ClientAssertion is the federated (OIDC) token presented in place of a
+	// secret, e.g. the ID token a GitHub Actions or Azure Pipelines job mints.
+	ClientAssertion string `json:"client_assertion,omitempty"`
+}
+
+// GetServicePrincipalPath returns the path of the service principal entries
+// file, session-specific when AZ_SESSION is set.
+func GetServicePrincipalPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	filename := ServicePrincipalFile
+	if session := os.Getenv("AZ_SESSION"); session != "" {
+		filename = fmt.Sprintf("service_principal_entries-%s.json", session)
+	}
+
+	return filepath.Join(home, ConfigDir, filename), nil
+}
+
+// SaveServicePrincipal writes the service principal entry, replacing any
+// previous one.
+func SaveServicePrincipal(entry *ServicePrincipalEntry) error {
+	path, err := GetServicePrincipalPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal service principal: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write service principal: %w", err)
+	}
+
+	return nil
+}
+
+// LoadServicePrincipal reads the service principal entry saved by the last
+// non-interactive login.
+func LoadServicePrincipal() (*ServicePrincipalEntry, error) {
+	path, err := GetServicePrincipalPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no service principal credentials found. Please run 'az login --service-principal'")
+		}
+		return nil, fmt.Errorf("failed to read service principal: %w", err)
+	}
+
+	var entry ServicePrincipalEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse service principal: %w", err)
+	}
+
+	return &entry, nil
+}
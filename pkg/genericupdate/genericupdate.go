@@ -22,6 +22,9 @@ type Op struct {
   Kind  OpKind
   Path  string
   Value string // raw value as supplied on CLI; parsed per Kind
+  // ForceString keeps Value a string even when it parses as JSON
+  // (--force-string), e.g. for a tag whose value is "true" or "123".
+  ForceString bool
 }
 
 // Apply mutates obj per the slice of operations, in order.
@@ -29,11 +32,11 @@ func Apply(obj map[string]interface{}, ops []Op) error {
   for _, op := range ops {
     switch op.Kind {
     case Set:
-      if err := applySet(obj, op.Path, op.Value); err != nil {
+      if err := applySet(obj, op.Path, op.Value, op.ForceString); err != nil {
         return fmt.Errorf("--set %s: %w", op.Path, err)
       }
     case Add:
-      if err := applyAdd(obj, op.Path, op.Value); err != nil {
+      if err := applyAdd(obj, op.Path, op.Value, op.ForceString); err != nil {
         return fmt.Errorf("--add %s: %w", op.Path, err)
       }
     case Remove:
@@ -86,7 +89,10 @@ func parsePath(path string) ([]segment, error) {
 }
 
 // parseValue tries to JSON-unmarshal value; falls back to a plain string.
-func parseValue(value string) interface{} {
+func parseValue(value string, forceString bool) interface{} {
+  if forceString {
+    return value
+  }
   var v interface{}
   if err := json.Unmarshal([]byte(value), &v); err == nil {
     return v
@@ -94,12 +100,12 @@ func parseValue(value string) interface{} {
   return value
 }
 
-func applySet(obj map[string]interface{}, path, value string) error {
+func applySet(obj map[string]interface{}, path, value string, forceString bool) error {
   segs, err := parsePath(path)
   if err != nil {
     return err
   }
-  parsed := parseValue(value)
+  parsed := parseValue(value, forceString)
   return setAtPath(obj, segs, parsed)
 }
 
@@ -157,7 +163,7 @@ func setAtPath(root map[string]interface{}, segs []segment, value interface{}) e
   return nil
 }
 
-func applyAdd(obj map[string]interface{}, path, value string) error {
+func applyAdd(obj map[string]interface{}, path, value string, forceString bool) error {
   segs, err := parsePath(path)
   if err != nil {
     return err
@@ -178,7 +184,7 @@ func applyAdd(obj map[string]interface{}, path, value string) error {
   if !ok {
     return fmt.Errorf("path %q does not refer to a list", last.key)
   }
-  m[last.key] = append(cur, parseValue(value))
+  m[last.key] = append(cur, parseValue(value, forceString))
   return nil
 }
 
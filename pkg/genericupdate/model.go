@@ -0,0 +1,178 @@
+package genericupdate
+
+import (
+  "encoding/json"
+  "fmt"
+  "reflect"
+  "strings"
+
+  "github.com/spf13/cobra"
+)
+
+// AddFlags registers --set, --add, --remove and --force-string, so a typed
+// update command can reach properties it has no dedicated flag for.
+func AddFlags(cmd *cobra.Command) {
+  cmd.Flags().StringArray("set", nil, "Set a property: path=value (repeatable)")
+  cmd.Flags().StringArray("add", nil, "Append to a list property: path JSON_VALUE (repeatable)")
+  cmd.Flags().StringArray("remove", nil, "Remove a key or list element: path [INDEX] (repeatable)")
+  cmd.Flags().Bool("force-string", false, "Keep --set and --add values as strings instead of parsing them as JSON")
+}
+
+// OpsFromFlags returns the operations given through AddFlags' flags: every
+// --set, then every --add, then every --remove.
+func OpsFromFlags(cmd *cobra.Command) ([]Op, error) {
+  setOps, _ := cmd.Flags().GetStringArray("set")
+  addOps, _ := cmd.Flags().GetStringArray("add")
+  removeOps, _ := cmd.Flags().GetStringArray("remove")
+  ops, err := ParseOps(setOps, addOps, removeOps)
+  if err != nil {
+    return nil, err
+  }
+  if force, _ := cmd.Flags().GetBool("force-string"); force {
+    for i := range ops {
+      ops[i].ForceString = true
+    }
+  }
+  return ops, nil
+}
+
+// ParseOps parses raw flag values: --set takes path=value, --add takes
+// 'path JSON_VALUE' and --remove takes 'path [INDEX]'.
+func ParseOps(setOps, addOps, removeOps []string) ([]Op, error) {
+  out := []Op{}
+  for _, s := range setOps {
+    eq := strings.Index(s, "=")
+    if eq == -1 {
+      return nil, fmt.Errorf("--set %q: expected path=value", s)
+    }
+    out = append(out, Op{Kind: Set, Path: s[:eq], Value: s[eq+1:]})
+  }
+  for _, a := range addOps {
+    sp := strings.IndexAny(a, " \t")
+    if sp == -1 {
+      return nil, fmt.Errorf("--add %q: expected 'path JSON_VALUE'", a)
+    }
+    out = append(out, Op{Kind: Add, Path: a[:sp], Value: strings.TrimSpace(a[sp+1:])})
+  }
+  for _, r := range removeOps {
+    sp := strings.IndexAny(r, " \t")
+    if sp == -1 {
+      out = append(out, Op{Kind: Remove, Path: r})
+      continue
+    }
+    out = append(out, Op{Kind: Remove, Path: r[:sp], Value: strings.TrimSpace(r[sp+1:])})
+  }
+  return out, nil
+}
+
+// ApplyFlags applies the operations given on cmd to model, a pointer to an
+// SDK model about to be sent back. It does nothing when none were given.
+func ApplyFlags(cmd *cobra.Command, model interface{}) error {
+  ops, err := OpsFromFlags(cmd)
+  if err != nil || len(ops) == 0 {
+    return err
+  }
+  return ApplyModel(model, ops)
+}
+
+// ApplyModel round-trips model through its JSON form so ops can address it
+// with the same camelCase paths as the REST API and `az resource update`.
+//
+// SDK models silently drop JSON they have no field for, so a --set or --add
+// the model can't carry is reported rather than lost on the way to the PUT.
+func ApplyModel(model interface{}, ops []Op) error {
+  v := reflect.ValueOf(model)
+  if v.Kind() != reflect.Ptr || v.IsNil() {
+    return fmt.Errorf("generic update needs a pointer to a model, got %T", model)
+  }
+  obj, err := toMap(model)
+  if err != nil {
+    return err
+  }
+  if err := Apply(obj, ops); err != nil {
+    return err
+  }
+  raw, err := json.Marshal(obj)
+  if err != nil {
+    return err
+  }
+  // Decode into a zeroed model: unmarshalling merges into existing maps, so
+  // a removed tag would otherwise survive.
+  v.Elem().Set(reflect.Zero(v.Elem().Type()))
+  if err := json.Unmarshal(raw, model); err != nil {
+    return fmt.Errorf("failed to apply generic update: %w", err)
+  }
+
+  after, err := toMap(model)
+  if err != nil {
+    return err
+  }
+  for _, op := range ops {
+    if op.Kind == Remove || (op.Kind == Set && parseValue(op.Value, op.ForceString) == nil) {
+      continue
+    }
+    if !hasPath(after, op.Path) {
+      flag := "--set"
+      if op.Kind == Add {
+        flag = "--add"
+      }
+      return fmt.Errorf("%s %s: this command can't update that property; use 'az resource update' instead", flag, op.Path)
+    }
+  }
+  return nil
+}
+
+// Seed fills dst from the fields of src that share its JSON names. PATCH
+// commands use it to start their update body from the resource's current
+// state, so --add and --remove have existing values to work on.
+func Seed(dst, src interface{}) error {
+  v := reflect.ValueOf(dst)
+  if v.Kind() != reflect.Ptr || v.IsNil() {
+    return fmt.Errorf("generic update needs a pointer to a model, got %T", dst)
+  }
+  raw, err := json.Marshal(src)
+  if err != nil {
+    return err
+  }
+  v.Elem().Set(reflect.Zero(v.Elem().Type()))
+  return json.Unmarshal(raw, dst)
+}
+
+func toMap(model interface{}) (map[string]interface{}, error) {
+  raw, err := json.Marshal(model)
+  if err != nil {
+    return nil, err
+  }
+  obj := map[string]interface{}{}
+  if err := json.Unmarshal(raw, &obj); err != nil {
+    return nil, err
+  }
+  return obj, nil
+}
+
+// hasPath reports whether path resolves to a value in obj.
+func hasPath(obj map[string]interface{}, path string) bool {
+  segs, err := parsePath(path)
+  if err != nil {
+    return false
+  }
+  var cursor interface{} = obj
+  for _, seg := range segs {
+    if seg.isIndex {
+      list, ok := cursor.([]interface{})
+      if !ok || seg.index >= len(list) {
+        return false
+      }
+      cursor = list[seg.index]
+      continue
+    }
+    m, ok := cursor.(map[string]interface{})
+    if !ok {
+      return false
+    }
+    if cursor, ok = m[seg.key]; !ok {
+      return false
+    }
+  }
+  return true
+}
@@ -0,0 +1,112 @@
+package genericupdate
+
+import (
+  "strings"
+  "testing"
+
+  "github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+  "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+  "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+  "github.com/spf13/cobra"
+)
+
+func TestApplyModel(t *testing.T) {
+  nsg := armnetwork.SecurityGroup{
+    Location: to.Ptr("eastus"),
+    Tags:     map[string]*string{"env": to.Ptr("dev"), "owner": to.Ptr("me")},
+    Properties: &armnetwork.SecurityGroupPropertiesFormat{
+      FlushConnection: to.Ptr(false),
+      SecurityRules: []*armnetwork.SecurityRule{
+        {Name: to.Ptr("allow-ssh"), Properties: &armnetwork.SecurityRulePropertiesFormat{Priority: to.Ptr[int32](100)}},
+      },
+    },
+  }
+  err := ApplyModel(&nsg, []Op{
+    {Kind: Set, Path: "properties.flushConnection", Value: "true"},
+    {Kind: Set, Path: "properties.securityRules[0].properties.priority", Value: "200"},
+    {Kind: Add, Path: "properties.securityRules", Value: `{"name": "deny-all"}`},
+    {Kind: Remove, Path: "tags.owner"},
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+  if !*nsg.Properties.FlushConnection || *nsg.Properties.SecurityRules[0].Properties.Priority != 200 {
+    t.Errorf("set not applied: %+v", nsg.Properties)
+  }
+  if len(nsg.Properties.SecurityRules) != 2 || *nsg.Properties.SecurityRules[1].Name != "deny-all" {
+    t.Errorf("add not applied: %v", nsg.Properties.SecurityRules)
+  }
+  if _, ok := nsg.Tags["owner"]; ok || *nsg.Tags["env"] != "dev" || *nsg.Location != "eastus" {
+    t.Errorf("remove or untouched fields wrong: %v %v", nsg.Tags, nsg.Location)
+  }
+}
+
+func TestApplyModelRejectsUnknownProperty(t *testing.T) {
+  nsg := armnetwork.SecurityGroup{Properties: &armnetwork.SecurityGroupPropertiesFormat{}}
+  err := ApplyModel(&nsg, []Op{{Kind: Set, Path: "properties.noSuchThing", Value: "1"}})
+  if err == nil || !strings.Contains(err.Error(), "az resource update") {
+    t.Errorf("err = %v", err)
+  }
+  // Clearing with null isn't an unknown property.
+  if err := ApplyModel(&nsg, []Op{{Kind: Set, Path: "properties.flushConnection", Value: "null"}}); err != nil {
+    t.Error(err)
+  }
+  if err := ApplyModel(nsg, nil); err == nil {
+    t.Error("expected an error for a non-pointer model")
+  }
+}
+
+func TestForceString(t *testing.T) {
+  nsg := armnetwork.SecurityGroup{}
+  if err := ApplyModel(&nsg, []Op{{Kind: Set, Path: "tags.build", Value: "123", ForceString: true}}); err != nil {
+    t.Fatal(err)
+  }
+  if *nsg.Tags["build"] != "123" {
+    t.Errorf("tags = %v", nsg.Tags)
+  }
+  // Without it the value is a JSON number, which a tag can't hold.
+  if err := ApplyModel(&nsg, []Op{{Kind: Set, Path: "tags.build", Value: "123"}}); err == nil {
+    t.Error("expected a decode error")
+  }
+}
+
+func TestSeed(t *testing.T) {
+  account := armstorage.Account{
+    ID:   to.Ptr("/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/a"),
+    Tags: map[string]*string{"env": to.Ptr("prod")},
+    Properties: &armstorage.AccountProperties{
+      MinimumTLSVersion: to.Ptr(armstorage.MinimumTLSVersionTLS12),
+      ProvisioningState: to.Ptr(armstorage.ProvisioningStateSucceeded),
+    },
+  }
+  params := armstorage.AccountUpdateParameters{Kind: to.Ptr(armstorage.KindBlobStorage)}
+  if err := Seed(&params, account); err != nil {
+    t.Fatal(err)
+  }
+  if params.Kind != nil {
+    t.Error("seed kept a field the source doesn't have")
+  }
+  if *params.Tags["env"] != "prod" || *params.Properties.MinimumTLSVersion != armstorage.MinimumTLSVersionTLS12 {
+    t.Errorf("params = %+v", params)
+  }
+}
+
+func TestOpsFromFlags(t *testing.T) {
+  cmd := &cobra.Command{Use: "update"}
+  AddFlags(cmd)
+  cmd.ParseFlags([]string{"--remove", "tags.a", "--set", "tags.b=1", "--add", "list {}", "--force-string"})
+  ops, err := OpsFromFlags(cmd)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(ops) != 3 || ops[0].Kind != Set || ops[1].Kind != Add || ops[2].Kind != Remove || !ops[0].ForceString {
+    t.Errorf("ops = %+v", ops)
+  }
+
+  cmd = &cobra.Command{Use: "update"}
+  AddFlags(cmd)
+  cmd.ParseFlags([]string{"--set", "novalue"})
+  if _, err := OpsFromFlags(cmd); err == nil {
+    t.Error("expected a parse error")
+  }
+}
@@ -0,0 +1,114 @@
+// Package graph calls Microsoft Graph with a Graph-scoped token from the
+// signed-in credential chain, for the directory lookups that ARM can't do.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/cdobbyn/azure-go-cli/pkg/azure"
+	"github.com/cdobbyn/azure-go-cli/pkg/jsonclient"
+)
+
+const moduleName = "github.com/cdobbyn/azure-go-cli/pkg/graph"
+
+// Version is the Graph API version every path is relative to.
+const Version = "v1.0"
+
+// Object is a Graph resource as returned by the service. Commands print it
+// unchanged, so there's no typed model to keep in step with Graph.
+type Object = jsonclient.Object
+
+// Client sends requests to Microsoft Graph through the SDK pipeline, which
+// adds the bearer token, retries and request logging.
+type Client struct {
+	api *jsonclient.Client
+}
+
+// NewClient returns a client authenticated with the current credential.
+func NewClient() (*Client, error) {
+	cred, err := azure.GetCredential()
+	if err != nil {
+		return nil, err
+	}
+	opts := azure.ClientOptions()
+	auth := runtime.NewBearerTokenPolicy(cred, []string{azure.GraphScope()}, nil)
+	return NewClientWithPipeline(runtime.NewPipeline(moduleName, "", runtime.PipelineOptions{
+		PerCall:  []policy.Policy{advancedQueryPolicy{}},
+		PerRetry: []policy.Policy{auth},
+	}, &opts)), nil
+}
+
+// NewClientWithPipeline wraps an existing pipeline; tests use it to swap in
+// a fake transport.
+func NewClientWithPipeline(pipeline runtime.Pipeline) *Client {
+	return &Client{api: jsonclient.New(pipeline, azure.GraphEndpoint()+"/"+Version, "@odata.nextLink")}
+}
+
+// advancedQueryPolicy asks for advanced query support, which $count and
+// $search on directory objects need.
+type advancedQueryPolicy struct{}
+
+func (advancedQueryPolicy) Do(req *policy.Request) (*http.Response, error) {
+	q := req.Raw().URL.Query()
+	if q.Has("$count") || q.Has("$search") {
+		req.Raw().Header.Set("ConsistencyLevel", "eventual")
+	}
+	return req.Next()
+}
+
+// Do sends one request and decodes the JSON reply, if any. path is relative
+// to the versioned endpoint (e.g. /users/{id}) or an absolute nextLink.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body interface{}) (Object, error) {
+	return c.api.Do(ctx, method, path, query, body)
+}
+
+func (c *Client) Get(ctx context.Context, path string, query url.Values) (Object, error) {
+	return c.Do(ctx, http.MethodGet, path, query, nil)
+}
+
+func (c *Client) Post(ctx context.Context, path string, body interface{}) (Object, error) {
+	return c.Do(ctx, http.MethodPost, path, nil, body)
+}
+
+func (c *Client) Patch(ctx context.Context, path string, body interface{}) error {
+	_, err := c.Do(ctx, http.MethodPatch, path, nil, body)
+	return err
+}
+
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.Do(ctx, http.MethodDelete, path, nil, nil)
+	return err
+}
+
+// List collects the "value" arrays of every page. top, when positive, stops
+// once that many items are in.
+func (c *Client) List(ctx context.Context, path string, query url.Values, top int) ([]interface{}, error) {
+	return c.api.List(ctx, http.MethodGet, path, query, top)
+}
+
+// IsNotFound reports whether err is a 404 from Graph.
+func IsNotFound(err error) bool {
+	return jsonclient.IsNotFound(err)
+}
+
+// Quote makes s safe inside a single-quoted OData string literal.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Filter returns a query holding just $filter.
+func Filter(format string, args ...interface{}) url.Values {
+	return url.Values{"$filter": {fmt.Sprintf(format, args...)}}
+}
+
+// ID returns an object's "id".
+func ID(o Object) string {
+	id, _ := o["id"].(string)
+	return id
+}
@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// recordingTransport answers every request with an empty object and records
+// the requests it saw.
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (r *recordingTransport) Do(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	return &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{}`)), Request: req}, nil
+}
+
+func TestAdvancedQueryHeader(t *testing.T) {
+	transport := &recordingTransport{}
+	pl := runtime.NewPipeline("test", "v0", runtime.PipelineOptions{
+		PerCall: []policy.Policy{advancedQueryPolicy{}},
+	}, &policy.ClientOptions{Transport: transport})
+	c := NewClientWithPipeline(pl)
+	c.Get(context.Background(), "/groups", nil)
+	c.Get(context.Background(), "/groups", map[string][]string{"$count": {"true"}})
+	if got := transport.requests[0].Header.Get("ConsistencyLevel"); got != "" {
+		t.Errorf("plain query sent ConsistencyLevel %q", got)
+	}
+	if got := transport.requests[1].Header.Get("ConsistencyLevel"); got != "eventual" {
+		t.Errorf("$count query sent ConsistencyLevel %q", got)
+	}
+	if got := transport.requests[0].URL.String(); got != "https://graph.microsoft.com/v1.0/groups" {
+		t.Errorf("request URL = %s", got)
+	}
+}
@@ -0,0 +1,323 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/config"
+	"github.com/cdobbyn/azure-go-cli/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// Principal is a directory object that role assignments can be made to.
+// Type uses the ARM principalType spelling (User, Group, ServicePrincipal).
+type Principal struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// principalTypes maps Graph's @odata.type to the ARM principalType.
+var principalTypes = map[string]string{
+	"#microsoft.graph.user":                            "User",
+	"#microsoft.graph.group":                           "Group",
+	"#microsoft.graph.servicePrincipal":                "ServicePrincipal",
+	"#microsoft.graph.device":                          "Device",
+	"#microsoft.graph.directoryObjectPartnerReference": "ForeignGroup",
+}
+
+// getByIdsLimit is the most IDs directoryObjects/getByIds takes per call.
+const getByIdsLimit = 1000
+
+// candidateLimit is how many matches an ambiguous assignee lists in its
+// error.
+const candidateLimit = 10
+
+// principalFromObject reads a Graph user, group or service principal. The
+// name is what az role assignment shows as principalName: the UPN for
+// users, the first service principal name (usually the appId) for service
+// principals and the display name otherwise.
+func principalFromObject(o Object) Principal {
+	p := Principal{ID: ID(o)}
+	odataType, _ := o["@odata.type"].(string)
+	p.Type = principalTypes[odataType]
+	if upn, _ := o["userPrincipalName"].(string); upn != "" {
+		p.Name = upn
+	} else if spns, _ := o["servicePrincipalNames"].([]interface{}); len(spns) > 0 {
+		p.Name, _ = spns[0].(string)
+	} else {
+		p.Name, _ = o["displayName"].(string)
+	}
+	return p
+}
+
+// ResolvePrincipal turns a user principal name, service principal name or
+// appId, service principal display name, group display name or object ID
+// into a principal, looking them up in that order as the Python CLI does. Answers are cached on disk, since
+// scripts tend to resolve the same few principals over and over.
+func (c *Client) ResolvePrincipal(ctx context.Context, assignee string) (Principal, error) {
+	cache := loadPrincipalCache()
+	if p, ok := cache.lookup(assignee); ok {
+		return p, nil
+	}
+
+	p, err := c.resolvePrincipal(ctx, assignee)
+	if err != nil {
+		return Principal{}, err
+	}
+	cache.add(p, assignee)
+	cache.save()
+	return p, nil
+}
+
+func (c *Client) resolvePrincipal(ctx context.Context, assignee string) (Principal, error) {
+	var queries []struct{ collection, filter string }
+	add := func(collection, format string) {
+		queries = append(queries, struct{ collection, filter string }{collection, fmt.Sprintf(format, Quote(assignee))})
+	}
+	if strings.Contains(assignee, "@") {
+		add("/users", "userPrincipalName eq %s")
+		add("/users", "mail eq %s")
+	}
+	add("/servicePrincipals", "servicePrincipalNames/any(s:s eq %s)")
+	add("/servicePrincipals", "displayName eq %s")
+	add("/groups", "displayName eq %s")
+
+	for _, q := range queries {
+		items, err := c.List(ctx, q.collection, url.Values{"$filter": {q.filter}}, candidateLimit)
+		if err != nil {
+			return Principal{}, fmt.Errorf("failed to look up '%s': %w", assignee, err)
+		}
+		principals := make([]Principal, len(items))
+		for i, item := range items {
+			obj, _ := item.(Object)
+			principals[i] = principalFromObject(obj)
+			if principals[i].Type == "" {
+				// List results don't always carry @odata.type.
+				principals[i].Type = map[string]string{"/users": "User", "/servicePrincipals": "ServicePrincipal", "/groups": "Group"}[q.collection]
+			}
+		}
+		if len(principals) > 1 {
+			candidates := make([]string, len(principals))
+			for i, p := range principals {
+				candidates[i] = fmt.Sprintf("%s (%s %s)", p.ID, p.Type, p.Name)
+			}
+			return Principal{}, fmt.Errorf("more than one principal matches '%s': %s; please use the object ID", assignee, strings.Join(candidates, ", "))
+		}
+		if len(principals) == 1 {
+			return principals[0], nil
+		}
+	}
+
+	if _, err := uuid.Parse(assignee); err == nil {
+		found, err := c.getByIds(ctx, []string{assignee})
+		if err != nil {
+			return Principal{}, fmt.Errorf("failed to look up '%s': %w", assignee, err)
+		}
+		if len(found) == 1 {
+			return found[0], nil
+		}
+	}
+	return Principal{}, fmt.Errorf("cannot find user or service principal in graph database for '%s'. If the assignee is an appId, make sure the corresponding service principal is created with 'az ad sp create --id %s'", assignee, assignee)
+}
+
+// Principals looks up the given object IDs, from the cache where it can.
+// IDs of deleted objects are missing from the result.
+func (c *Client) Principals(ctx context.Context, ids []string) (map[string]Principal, error) {
+	cache := loadPrincipalCache()
+	result := make(map[string]Principal, len(ids))
+	var missing []string
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if p, ok := cache.get(id); ok {
+			result[id] = p
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := c.getByIds(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range found {
+		result[p.ID] = p
+		cache.add(p, "")
+	}
+	cache.save()
+	return result, nil
+}
+
+func (c *Client) getByIds(ctx context.Context, ids []string) ([]Principal, error) {
+	var principals []Principal
+	for start := 0; start < len(ids); start += getByIdsLimit {
+		end := start + getByIdsLimit
+		if end > len(ids) {
+			end = len(ids)
+		}
+		result, err := c.Post(ctx, "/directoryObjects/getByIds", map[string]interface{}{
+			"ids":   ids[start:end],
+			"types": []string{"user", "group", "servicePrincipal", "directoryObjectPartnerReference"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up directory objects: %w", err)
+		}
+		values, _ := result["value"].([]interface{})
+		for _, v := range values {
+			obj, _ := v.(Object)
+			principals = append(principals, principalFromObject(obj))
+		}
+	}
+	return principals, nil
+}
+
+// MemberGroups returns the IDs of every group id belongs to, directly or
+// through nested groups.
+func (c *Client) MemberGroups(ctx context.Context, id string) ([]string, error) {
+	result, err := c.Post(ctx, "/directoryObjects/"+id+"/getMemberGroups", map[string]interface{}{
+		"securityEnabledOnly": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the groups of %s: %w", id, err)
+	}
+	values, _ := result["value"].([]interface{})
+	groups := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			groups = append(groups, s)
+		}
+	}
+	return groups, nil
+}
+
+// principalCacheTTL bounds how stale a cached principal can be. Principals
+// are rarely renamed, but a deleted and recreated group keeps its name and
+// gets a new ID.
+const principalCacheTTL = 24 * time.Hour
+
+const principalCacheFile = "principalCache.json"
+
+type cachedPrincipal struct {
+	Principal
+	Cached time.Time `json:"cached"`
+}
+
+// tenantPrincipals holds one tenant's principals by object ID, and the
+// object ID each name given to ResolvePrincipal resolved to.
+type tenantPrincipals struct {
+	Principals map[string]cachedPrincipal `json:"principals"`
+	Names      map[string]string          `json:"names"`
+}
+
+// principalCache is keyed by tenant, since object IDs and display names
+// only mean something within one directory.
+type principalCache struct {
+	path    string
+	tenant  string
+	Tenants map[string]*tenantPrincipals `json:"tenants"`
+}
+
+func principalCachePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, config.ConfigDir, principalCacheFile)
+}
+
+// loadPrincipalCache reads the cache for the current tenant. A missing or
+// unreadable cache is just empty.
+func loadPrincipalCache() *principalCache {
+	c := &principalCache{path: principalCachePath(), Tenants: map[string]*tenantPrincipals{}}
+	if sub, err := config.GetDefaultSubscription(); err == nil {
+		c.tenant, _ = config.GetTenantID(sub)
+	}
+	if data, err := os.ReadFile(c.path); err == nil {
+		if err := json.Unmarshal(data, c); err != nil {
+			logger.Debug("Ignoring unreadable principal cache %s: %v", c.path, err)
+			c.Tenants = map[string]*tenantPrincipals{}
+		}
+	}
+	if c.Tenants[c.tenant] == nil {
+		c.Tenants[c.tenant] = &tenantPrincipals{}
+	}
+	t := c.Tenants[c.tenant]
+	if t.Principals == nil {
+		t.Principals = map[string]cachedPrincipal{}
+	}
+	if t.Names == nil {
+		t.Names = map[string]string{}
+	}
+	return c
+}
+
+func (c *principalCache) get(id string) (Principal, bool) {
+	p, ok := c.Tenants[c.tenant].Principals[strings.ToLower(id)]
+	if !ok || time.Since(p.Cached) > principalCacheTTL {
+		return Principal{}, false
+	}
+	return p.Principal, true
+}
+
+func (c *principalCache) lookup(name string) (Principal, bool) {
+	id, ok := c.Tenants[c.tenant].Names[strings.ToLower(name)]
+	if !ok {
+		return Principal{}, false
+	}
+	return c.get(id)
+}
+
+// add records p, and that name resolved to it when name isn't empty.
+func (c *principalCache) add(p Principal, name string) {
+	t := c.Tenants[c.tenant]
+	t.Principals[strings.ToLower(p.ID)] = cachedPrincipal{Principal: p, Cached: time.Now()}
+	if name != "" {
+		t.Names[strings.ToLower(name)] = p.ID
+	}
+}
+
+// save writes the cache, dropping expired entries. Failing to save only
+// costs a lookup next time, so errors are logged, not returned.
+func (c *principalCache) save() {
+	if c.path == "" {
+		return
+	}
+	for _, t := range c.Tenants {
+		for id, p := range t.Principals {
+			if time.Since(p.Cached) > principalCacheTTL {
+				delete(t.Principals, id)
+			}
+		}
+		for name, id := range t.Names {
+			if _, ok := t.Principals[strings.ToLower(id)]; !ok {
+				delete(t.Names, name)
+			}
+		}
+	}
+	data, err := json.Marshal(c)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	}
+	if err == nil {
+		tmp := c.path + ".tmp"
+		if err = os.WriteFile(tmp, data, 0600); err == nil {
+			err = os.Rename(tmp, c.path)
+		}
+	}
+	if err != nil {
+		logger.Debug("Failed to save principal cache %s: %v", c.path, err)
+	}
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// directory answers Graph lookups for a user, a service principal and a
+// group, and counts the requests.
+type directory struct {
+	requests int
+}
+
+func (d *directory) Do(req *http.Request) (*http.Response, error) {
+	d.requests++
+	filter := req.URL.Query().Get("$filter")
+	body := `{"value": []}`
+	switch {
+	case filter == "userPrincipalName eq 'alice@contoso.com'":
+		body = `{"value": [{"id": "u1", "userPrincipalName": "alice@contoso.com"}]}`
+	case filter == "servicePrincipalNames/any(s:s eq '11111111-1111-1111-1111-111111111111')":
+		body = `{"value": [{"id": "sp1", "servicePrincipalNames": ["11111111-1111-1111-1111-111111111111"], "displayName": "deployer"}]}`
+	case filter == "displayName eq 'deployer'" && strings.HasSuffix(req.URL.Path, "/servicePrincipals"):
+		body = `{"value": [{"id": "sp1", "servicePrincipalNames": ["11111111-1111-1111-1111-111111111111"], "displayName": "deployer"}]}`
+	case filter == "displayName eq 'builder'" && strings.HasSuffix(req.URL.Path, "/servicePrincipals"):
+		body = `{"value": [{"id": "sp2", "servicePrincipalNames": ["33333333-3333-3333-3333-333333333333"], "displayName": "builder"}, {"id": "sp3", "servicePrincipalNames": ["44444444-4444-4444-4444-444444444444"], "displayName": "builder"}]}`
+	case filter == "displayName eq 'Ops'" && strings.HasSuffix(req.URL.Path, "/groups"):
+		body = `{"value": [{"id": "g1", "displayName": "Ops"}]}`
+	case filter == "displayName eq 'Twins'" && strings.HasSuffix(req.URL.Path, "/groups"):
+		body = `{"value": [{"id": "g2"}, {"id": "g3"}]}`
+	case strings.HasSuffix(req.URL.Path, "/getByIds"):
+		var in struct{ IDs []string }
+		json.NewDecoder(req.Body).Decode(&in)
+		var out []string
+		for _, id := range in.IDs {
+			if id == "22222222-2222-2222-2222-222222222222" {
+				out = append(out, `{"@odata.type": "#microsoft.graph.group", "id": "`+id+`", "displayName": "Platform"}`)
+			}
+		}
+		body = `{"value": [` + strings.Join(out, ",") + `]}`
+	}
+	return &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func directoryClient(t *testing.T) (*Client, *directory) {
+	t.Setenv("HOME", t.TempDir())
+	d := &directory{}
+	pl := runtime.NewPipeline("test", "v0", runtime.PipelineOptions{}, &policy.ClientOptions{Transport: d})
+	return NewClientWithPipeline(pl), d
+}
+
+func TestResolvePrincipal(t *testing.T) {
+	c, _ := directoryClient(t)
+	tests := []struct {
+		assignee string
+		want     Principal
+	}{
+		{"alice@contoso.com", Principal{ID: "u1", Type: "User", Name: "alice@contoso.com"}},
+		{"11111111-1111-1111-1111-111111111111", Principal{ID: "sp1", Type: "ServicePrincipal", Name: "11111111-1111-1111-1111-111111111111"}},
+		{"deployer", Principal{ID: "sp1", Type: "ServicePrincipal", Name: "11111111-1111-1111-1111-111111111111"}},
+		{"Ops", Principal{ID: "g1", Type: "Group", Name: "Ops"}},
+		{"22222222-2222-2222-2222-222222222222", Principal{ID: "22222222-2222-2222-2222-222222222222", Type: "Group", Name: "Platform"}},
+	}
+	for _, tt := range tests {
+		got, err := c.ResolvePrincipal(context.Background(), tt.assignee)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %+v, %v", tt.assignee, got, err)
+		}
+	}
+	if _, err := c.ResolvePrincipal(context.Background(), "Twins"); err == nil || !strings.Contains(err.Error(), "more than one") {
+		t.Errorf("ambiguous: err = %v", err)
+	}
+	// Ambiguous service principal names list the candidates to choose from.
+	_, err := c.ResolvePrincipal(context.Background(), "builder")
+	if err == nil || !strings.Contains(err.Error(), "sp2 (ServicePrincipal 33333333-3333-3333-3333-333333333333)") || !strings.Contains(err.Error(), "sp3 (ServicePrincipal 44444444-4444-4444-4444-444444444444)") {
+		t.Errorf("ambiguous service principal: err = %v", err)
+	}
+	if _, err := c.ResolvePrincipal(context.Background(), "nobody"); err == nil || !strings.Contains(err.Error(), "cannot find") {
+		t.Errorf("missing: err = %v", err)
+	}
+}
+
+func TestPrincipalCache(t *testing.T) {
+	c, d := directoryClient(t)
+	if _, err := c.ResolvePrincipal(context.Background(), "Ops"); err != nil {
+		t.Fatal(err)
+	}
+	before := d.requests
+	got, err := c.ResolvePrincipal(context.Background(), "ops")
+	if err != nil || got.ID != "g1" || d.requests != before {
+		t.Errorf("cached lookup: got %+v, %v after %d requests", got, err, d.requests-before)
+	}
+
+	// Principals serves g1 from the cache and only asks Graph for the rest;
+	// the deleted principal is simply missing.
+	before = d.requests
+	principals, err := c.Principals(context.Background(), []string{"g1", "22222222-2222-2222-2222-222222222222", "deleted", "g1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.requests-before != 1 || len(principals) != 2 || principals["g1"].Name != "Ops" || principals["22222222-2222-2222-2222-222222222222"].Name != "Platform" {
+		t.Errorf("got %+v after %d requests", principals, d.requests-before)
+	}
+}
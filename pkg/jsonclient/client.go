@@ -0,0 +1,124 @@
+// Package jsonclient sends requests to JSON REST APIs through an SDK
+// pipeline (auth, retries, request logging) and returns the bodies as plain
+// maps. It backs the Graph and Azure Policy commands, which have no typed Go
+// SDK among our dependencies and print what the service returns unchanged.
+package jsonclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+// maxPages bounds next link following, as az rest --paginate does.
+const maxPages = 1000
+
+// Object is a JSON object as returned by the service.
+type Object = map[string]interface{}
+
+// Client sends requests relative to one base URL.
+type Client struct {
+	pipeline runtime.Pipeline
+	baseURL  string
+	nextLink string
+}
+
+// New returns a client for the API at baseURL whose list results name the
+// next page in the nextLink field ("nextLink" for ARM, "@odata.nextLink" for
+// Graph and Policy Insights).
+func New(pipeline runtime.Pipeline, baseURL, nextLink string) *Client {
+	return &Client{pipeline: pipeline, baseURL: baseURL, nextLink: nextLink}
+}
+
+// Do sends one request and decodes the JSON reply, if any. path is relative
+// to the base URL or an absolute next link; query is merged into the
+// path's own query.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body interface{}) (Object, error) {
+	target := path
+	if !strings.HasPrefix(path, "https://") {
+		target = c.baseURL + path
+	}
+	req, err := runtime.NewRequest(ctx, method, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		q := req.Raw().URL.Query()
+		for k, v := range query {
+			q[k] = v
+		}
+		req.Raw().URL.RawQuery = q.Encode()
+	}
+	req.Raw().Header.Set("Accept", "application/json")
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := req.SetBody(streaming.NopCloser(bytes.NewReader(data)), "application/json"); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, runtime.NewResponseError(resp)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var out Object
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return out, nil
+}
+
+// List collects the "value" arrays of every page, requesting each with
+// method. top, when positive, stops once that many items are in.
+func (c *Client) List(ctx context.Context, method, path string, query url.Values, top int) ([]interface{}, error) {
+	items := []interface{}{}
+	page, err := c.Do(ctx, method, path, query, nil)
+	for i := 0; ; i++ {
+		if err != nil {
+			return nil, err
+		}
+		values, _ := page["value"].([]interface{})
+		items = append(items, values...)
+		if top > 0 && len(items) >= top {
+			return items[:top], nil
+		}
+		next, _ := page[c.nextLink].(string)
+		if next == "" || i >= maxPages {
+			return items, nil
+		}
+		// The next link already carries the query.
+		page, err = c.Do(ctx, method, next, nil, nil)
+	}
+}
+
+// IsNotFound reports whether err is a 404 from the service.
+func IsNotFound(err error) bool {
+	var respErr *azcore.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,83 @@
+package jsonclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// pagedTransport serves a two-page result linked by "@odata.nextLink" and
+// records the requests it saw.
+type pagedTransport struct {
+	requests []*http.Request
+}
+
+func (p *pagedTransport) Do(req *http.Request) (*http.Response, error) {
+	p.requests = append(p.requests, req)
+	status, body := 200, `{"value": [{"n": 1}, {"n": 2}], "@odata.nextLink": "https://example.com/api/next?api-version=1&$skiptoken=x"}`
+	switch {
+	case strings.Contains(req.URL.Path, "missing"):
+		status, body = 404, `{"error": {"code": "NotFound", "message": "not found"}}`
+	case len(p.requests) > 1:
+		body = `{"value": [{"n": 3}]}`
+	}
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func testClient(nextLink string) (*Client, *pagedTransport) {
+	transport := &pagedTransport{}
+	pl := runtime.NewPipeline("test", "v0", runtime.PipelineOptions{}, &policy.ClientOptions{Transport: transport})
+	return New(pl, "https://example.com/api", nextLink), transport
+}
+
+func TestListFollowsNextLink(t *testing.T) {
+	c, transport := testClient("@odata.nextLink")
+	items, err := c.List(context.Background(), http.MethodPost, "/results", url.Values{"api-version": {"1"}, "$filter": {"a eq 'b'"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || len(transport.requests) != 2 {
+		t.Fatalf("items = %v, requests = %d", items, len(transport.requests))
+	}
+	first, second := transport.requests[0], transport.requests[1]
+	if first.Method != http.MethodPost || first.URL.Host != "example.com" || first.URL.Path != "/api/results" {
+		t.Errorf("first request = %s %s", first.Method, first.URL)
+	}
+	if first.URL.Query().Get("$filter") != "a eq 'b'" || first.URL.Query().Get("api-version") != "1" {
+		t.Errorf("first query = %s", first.URL.RawQuery)
+	}
+	if second.Method != http.MethodPost || second.URL.Query().Get("$skiptoken") != "x" || second.URL.Query().Get("api-version") != "1" {
+		t.Errorf("second request = %s %s", second.Method, second.URL)
+	}
+
+	// A positive top stops before fetching the second page.
+	c, transport = testClient("@odata.nextLink")
+	items, _ = c.List(context.Background(), http.MethodGet, "/results", nil, 1)
+	if len(items) != 1 || len(transport.requests) != 1 {
+		t.Errorf("top: items = %v, requests = %d", items, len(transport.requests))
+	}
+
+	// Only the configured field is followed.
+	c, transport = testClient("nextLink")
+	items, _ = c.List(context.Background(), http.MethodGet, "/results", nil, 0)
+	if len(items) != 2 || len(transport.requests) != 1 {
+		t.Errorf("nextLink: items = %v, requests = %d", items, len(transport.requests))
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	c, _ := testClient("nextLink")
+	_, err := c.Do(context.Background(), http.MethodGet, "/missing", nil, nil)
+	if !IsNotFound(err) || !strings.Contains(err.Error(), "NotFound") {
+		t.Errorf("err = %v, want a 404", err)
+	}
+	if _, err := c.Do(context.Background(), http.MethodGet, "/present", nil, nil); IsNotFound(err) {
+		t.Errorf("err = %v", err)
+	}
+}
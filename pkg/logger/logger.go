@@ -1,19 +1,40 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
 )
 
+// LogFileEnv names a file that receives every record, including SDK events,
+// as JSON lines regardless of the console level.
+const LogFileEnv = "AZURE_GO_CLI_LOG_FILE"
+
+// LevelVerbose sits between Debug and Info. It carries per-request summaries,
+// which --verbose shows without the full SDK trace of --debug.
+const LevelVerbose = slog.Level(-2)
+
+// Log formats accepted by --log-format.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
 var (
 	// logger is the default slog logger instance
 	logger *slog.Logger
 	// Output is the writer for log messages (defaults to stderr)
 	Output io.Writer = os.Stderr
+
+	mu      sync.Mutex
+	level   = slog.LevelInfo
+	format  = FormatText
+	logFile io.Writer
 )
 
 func init() {
@@ -21,21 +42,90 @@ func init() {
 	SetLogLevel(slog.LevelInfo)
 }
 
-// SetLogLevel sets the logging level
-func SetLogLevel(level slog.Level) {
-	handler := slog.NewTextHandler(Output, &slog.HandlerOptions{
-		Level: level,
-	})
-	logger = slog.New(handler)
+// Options configures the console handler and the optional file sink.
+type Options struct {
+	Level  slog.Level
+	Format string
+	// File is appended to at debug level in JSON, whatever Level and Format
+	// say, so a quiet run still leaves a full record behind.
+	File string
+}
+
+// Configure replaces the logger according to opts. The file sink is opened
+// once; later calls keep the one already open. An invalid format or an
+// unopenable file is reported, but the rest of opts still applies.
+func Configure(opts Options) error {
+	var err error
+	switch opts.Format {
+	case "", FormatText:
+		opts.Format = FormatText
+	case FormatJSON:
+	default:
+		err = fmt.Errorf("invalid log format %q (expected %s or %s)", opts.Format, FormatText, FormatJSON)
+		opts.Format = FormatText
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if opts.File != "" && logFile == nil {
+		f, openErr := os.OpenFile(opts.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if openErr != nil {
+			err = fmt.Errorf("failed to open log file: %w", openErr)
+		} else {
+			logFile = f
+		}
+	}
+	level = opts.Level
+	format = opts.Format
+	rebuild()
+	return err
+}
+
+// SetLogLevel sets the console logging level, keeping the format and any
+// log file.
+func SetLogLevel(l slog.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+	rebuild()
 }
 
 // EnableDebug enables debug logging for both our logger and Azure SDK
 func EnableDebug() {
 	SetLogLevel(slog.LevelDebug)
+}
+
+// DisableDebug returns the console to Info level. A log file keeps receiving
+// SDK events.
+func DisableDebug() {
+	SetLogLevel(slog.LevelInfo)
+}
+
+// rebuild creates the handlers for the current settings and hooks the Azure
+// SDK log into them when anything would record its events. mu must be held.
+func rebuild() {
+	handlerOpts := &slog.HandlerOptions{Level: level, ReplaceAttr: levelNames}
+	var console slog.Handler
+	if format == FormatJSON {
+		console = slog.NewJSONHandler(Output, handlerOpts)
+	} else {
+		console = slog.NewTextHandler(Output, handlerOpts)
+	}
+
+	handler := console
+	if logFile != nil {
+		file := slog.NewJSONHandler(logFile, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: levelNames})
+		handler = fanout{console, file}
+	}
+	logger = slog.New(handler)
 
-	// Enable Azure SDK logging
+	if level > slog.LevelDebug && logFile == nil {
+		log.SetListener(nil)
+		return
+	}
+	l := logger
 	log.SetListener(func(event log.Event, message string) {
-		logger.Debug("Azure SDK event", "event", event, "message", message)
+		l.Debug("Azure SDK event", "event", event, "message", message)
 	})
 
 	// Set which Azure SDK events to log
@@ -46,25 +136,86 @@ func EnableDebug() {
 	)
 }
 
-// DisableDebug disables debug logging (sets to Info level)
-func DisableDebug() {
-	SetLogLevel(slog.LevelInfo)
-	log.SetListener(nil)
+// levelNames prints LevelVerbose as VERBOSE rather than slog's "INFO-2".
+func levelNames(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if l, ok := a.Value.Any().(slog.Level); ok && l == LevelVerbose {
+			a.Value = slog.StringValue("VERBOSE")
+		}
+	}
+	return a
+}
+
+// fanout sends each record to every handler that accepts its level.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var first error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			if err := h.Handle(ctx, r.Clone()); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// LogAttrs logs a structured record, for callers whose details belong in
+// fields (request IDs, durations) rather than in the message.
+func LogAttrs(ctx context.Context, l slog.Level, msg string, attrs ...slog.Attr) {
+	current().LogAttrs(ctx, l, msg, attrs...)
+}
+
+func current() *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	return logger
 }
 
 // Debug logs a debug message
 func Debug(format string, args ...interface{}) {
-	logger.Debug(fmt.Sprintf(format, args...))
+	current().Debug(fmt.Sprintf(format, args...))
+}
+
+// Verbose logs a message shown with --verbose or --debug
+func Verbose(format string, args ...interface{}) {
+	current().Log(context.Background(), LevelVerbose, fmt.Sprintf(format, args...))
 }
 
 // Info logs an informational message
 func Info(format string, args ...interface{}) {
-	logger.Info(fmt.Sprintf(format, args...))
+	current().Info(fmt.Sprintf(format, args...))
 }
 
 // Warning logs a warning message
 func Warning(format string, args ...interface{}) {
-	logger.Warn(fmt.Sprintf(format, args...))
+	current().Warn(fmt.Sprintf(format, args...))
 }
 
 // Warn is an alias for Warning
@@ -74,7 +225,7 @@ func Warn(format string, args ...interface{}) {
 
 // Error logs an error message
 func Error(format string, args ...interface{}) {
-	logger.Error(fmt.Sprintf(format, args...))
+	current().Error(fmt.Sprintf(format, args...))
 }
 
 // Print writes directly to stdout (for user-facing messages like prompts)
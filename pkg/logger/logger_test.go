@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func useOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	old := Output
+	Output = &buf
+	t.Cleanup(func() {
+		Output = old
+		logFile = nil
+		Configure(Options{Level: slog.LevelInfo})
+	})
+	return &buf
+}
+
+func TestConfigureLevels(t *testing.T) {
+	buf := useOutput(t)
+
+	Configure(Options{Level: slog.LevelInfo})
+	Verbose("request summary")
+	Info("logged in")
+	if strings.Contains(buf.String(), "request summary") || !strings.Contains(buf.String(), "logged in") {
+		t.Errorf("info level: %q", buf.String())
+	}
+
+	buf.Reset()
+	Configure(Options{Level: LevelVerbose})
+	Verbose("request summary")
+	Debug("sdk trace")
+	if !strings.Contains(buf.String(), "level=VERBOSE") || strings.Contains(buf.String(), "sdk trace") {
+		t.Errorf("verbose level: %q", buf.String())
+	}
+
+	buf.Reset()
+	Configure(Options{Level: slog.LevelError})
+	Warning("careful")
+	Error("broken")
+	if strings.Contains(buf.String(), "careful") || !strings.Contains(buf.String(), "broken") {
+		t.Errorf("error level: %q", buf.String())
+	}
+}
+
+func TestConfigureJSONFormat(t *testing.T) {
+	buf := useOutput(t)
+	if err := Configure(Options{Level: slog.LevelInfo, Format: FormatJSON}); err != nil {
+		t.Fatal(err)
+	}
+	Info("hello %s", "world")
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("%q: %v", buf.String(), err)
+	}
+	if rec["msg"] != "hello world" || rec["level"] != "INFO" {
+		t.Errorf("record = %v", rec)
+	}
+
+	// SetLogLevel, as used by core.only_show_errors, keeps the format.
+	buf.Reset()
+	SetLogLevel(slog.LevelError)
+	Error("x")
+	if !strings.HasPrefix(buf.String(), "{") {
+		t.Errorf("format lost: %q", buf.String())
+	}
+
+	if err := Configure(Options{Format: "xml"}); err == nil {
+		t.Error("expected an invalid format error")
+	}
+}
+
+func TestLogFileRecordsEverything(t *testing.T) {
+	buf := useOutput(t)
+	path := filepath.Join(t.TempDir(), "az.log")
+
+	if err := Configure(Options{Level: slog.LevelError, File: path}); err != nil {
+		t.Fatal(err)
+	}
+	Debug("sdk trace")
+	LogAttrs(context.Background(), LevelVerbose, "HTTP request", slog.String("request_id", "abc"))
+	if buf.Len() != 0 {
+		t.Errorf("console got %q", buf.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log file = %q", data)
+	}
+	var rec map[string]interface{}
+	json.Unmarshal([]byte(lines[1]), &rec)
+	if rec["request_id"] != "abc" || rec["level"] != "VERBOSE" {
+		t.Errorf("record = %v", rec)
+	}
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ANSI colours for -o jsonc / -o yamlc: keys blue, strings green, numbers
+// cyan, true/false/null magenta.
+const (
+	colorKey     = "\x1b[94m"
+	colorString  = "\x1b[32m"
+	colorNumber  = "\x1b[36m"
+	colorLiteral = "\x1b[35m"
+	colorReset   = "\x1b[0m"
+)
+
+// ColorEnabled reports whether output to w should actually colour: only when
+// w is a terminal and NO_COLOR is unset. Piped -o jsonc/yamlc output falls
+// back to plain json/yaml, as knack does, so `-o jsonc | jq` keeps working.
+func ColorEnabled(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// colorizeJSON adds ANSI colours to already-formatted JSON text. It scans
+// tokens rather than re-rendering, so the uncoloured bytes are exactly the
+// json output's (struct field order included).
+func colorizeJSON(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end++ // closing quote
+			if end > len(s) {
+				end = len(s)
+			}
+			color := colorString
+			if isJSONKey(s[end:]) {
+				color = colorKey
+			}
+			b.WriteString(color + s[i:end] + colorReset)
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+				end++
+			}
+			b.WriteString(colorNumber + s[i:end] + colorReset)
+			i = end
+		case strings.HasPrefix(s[i:], "true"), strings.HasPrefix(s[i:], "false"), strings.HasPrefix(s[i:], "null"):
+			end := i + strings.IndexFunc(s[i:], func(r rune) bool { return r < 'a' || r > 'z' })
+			if end < i {
+				end = len(s)
+			}
+			b.WriteString(colorLiteral + s[i:end] + colorReset)
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// isJSONKey reports whether the text after a string token starts with the
+// ':' that makes it an object key.
+func isJSONKey(rest string) bool {
+	return strings.HasPrefix(strings.TrimLeft(rest, " \t\r\n"), ":")
+}
+
+// yamlLine splits a block-style YAML line into its indentation (including
+// any "- " sequence markers), an optional mapping key with its ':', and the
+// value. yaml.v3 quotes any scalar that would otherwise contain ": ", so a
+// plain key can't swallow part of a value.
+var yamlLine = regexp.MustCompile(`^(\s*(?:- )*)(?:("(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s"'\-?:#][^:]*|-[^\s:][^:]*):(?: |$))?(.*)$`)
+
+var yamlNumber = regexp.MustCompile(`^[-+]?(\.inf|\.Inf|\.INF|\.nan|\.NaN|\.NAN|[0-9][0-9_]*(\.[0-9_]*)?([eE][-+]?[0-9]+)?|\.[0-9]+([eE][-+]?[0-9]+)?|0x[0-9a-fA-F]+|0o[0-7]+)$`)
+
+// colorizeYAML adds ANSI colours to block-style YAML as renderYAML produces
+// it. Lines inside a literal/folded block scalar (after a "|" or ">" header)
+// are string content and coloured as such.
+func colorizeYAML(s string) string {
+	lines := strings.Split(s, "\n")
+	blockIndent := -1 // indentation of the line that opened a block scalar
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if blockIndent >= 0 {
+			if indent > blockIndent {
+				lines[i] = line[:indent] + colorString + line[indent:] + colorReset
+				continue
+			}
+			blockIndent = -1
+		}
+
+		m := yamlLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		prefix, key, value := m[1], m[2], m[3]
+		var b strings.Builder
+		b.WriteString(prefix)
+		if key != "" {
+			b.WriteString(colorKey + key + colorReset + ":")
+			if value != "" {
+				b.WriteString(" ")
+			}
+		}
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = indent
+			b.WriteString(value)
+		} else {
+			b.WriteString(colorizeYAMLScalar(value))
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func colorizeYAMLScalar(v string) string {
+	switch {
+	case v == "" || v == "{}" || v == "[]" || v == "...":
+		return v
+	case v == "null" || v == "~" || v == "true" || v == "false":
+		return colorLiteral + v + colorReset
+	case yamlNumber.MatchString(v):
+		return colorNumber + v + colorReset
+	}
+	return colorString + v + colorReset
+}
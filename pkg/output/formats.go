@@ -0,0 +1,93 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// renderCSV renders v as RFC 4180 CSV (CRLF record separators, fields
+// quoted only when needed) with a header row. Columns and cell values come
+// from tableMatrix, so a csv has exactly the columns -o table would show,
+// with the unstripped values. A nil or empty result renders nothing.
+//
+// encoding/csv is not used: with UseCRLF it also rewrites "\n" inside a
+// quoted field to "\r\n", changing the value.
+func renderCSV(v interface{}, ko *keyOrder) string {
+	if v == nil {
+		return ""
+	}
+	columns, _, rawCells := tableMatrix(v, ko)
+	if len(columns) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	writeCSVRecord(&b, columns)
+	for _, row := range rawCells {
+		writeCSVRecord(&b, row)
+	}
+	return b.String()
+}
+
+func writeCSVRecord(b *strings.Builder, fields []string) {
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(csvField(f))
+	}
+	b.WriteString("\r\n")
+}
+
+// csvField quotes f if it contains a separator, quote or line break, or has
+// leading whitespace some readers would trim, doubling embedded quotes.
+func csvField(f string) string {
+	if f == "" || !(strings.ContainsAny(f, ",\"\r\n") || f[0] == ' ' || f[0] == '\t') {
+		return f
+	}
+	return `"` + strings.ReplaceAll(f, `"`, `""`) + `"`
+}
+
+// renderTSVWithHeaders renders v as tab-separated values under a header
+// row, using the same columns as renderCSV. Like -o tsv, values are not
+// escaped, so a cell containing a tab or newline breaks the row.
+func renderTSVWithHeaders(v interface{}, ko *keyOrder) string {
+	if v == nil {
+		return ""
+	}
+	columns, _, rawCells := tableMatrix(v, ko)
+	if len(columns) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(columns, "\t") + "\n")
+	for _, row := range rawCells {
+		b.WriteString(strings.Join(row, "\t") + "\n")
+	}
+	return b.String()
+}
+
+// renderNDJSON renders v as newline-delimited JSON: one compact document per
+// element of a list, or a single line for any other value. A nil result
+// renders nothing, matching renderTable's treatment of an unpopulated list.
+func renderNDJSON(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
@@ -22,13 +22,16 @@ import (
 // caller's command tree defines it, the global --output/-o flag. When the
 // format is json (or unset) this is byte-for-byte the output it has always
 // produced: the value is marshalled directly, so struct field order is
-// preserved. Any other format is delegated to PrintFormatted, which
-// normalizes through a generic JSON tree.
+// preserved; jsonc is the same bytes, coloured on a terminal. Any other
+// format is delegated to PrintFormatted, which normalizes through a generic
+// JSON tree.
 func PrintJSON(cmd *cobra.Command, data interface{}) error {
 	format, _ := cmd.Flags().GetString("output")
 	switch strings.ToLower(format) {
 	case "", "json":
-		return printJSONIndented(cmd, data)
+		return printJSONIndented(cmd, data, false)
+	case "jsonc":
+		return printJSONIndented(cmd, data, ColorEnabled(cmd.OutOrStdout()))
 	default:
 		return PrintFormatted(cmd, data, format)
 	}
@@ -58,7 +61,7 @@ func marshalIndentNoEscape(v interface{}) ([]byte, error) {
 // printJSONIndented is the historical PrintJSON body: it marshals data
 // directly (preserving struct field declaration order) rather than routing
 // through the generic map[string]interface{} tree PrintFormatted uses.
-func printJSONIndented(cmd *cobra.Command, data interface{}) error {
+func printJSONIndented(cmd *cobra.Command, data interface{}, color bool) error {
 	queryStr, _ := cmd.Flags().GetString("query")
 
 	// Marshal to JSON first
@@ -75,94 +78,24 @@ func printJSONIndented(cmd *cobra.Command, data interface{}) error {
 		}
 	}
 
+	if color {
+		fmt.Fprintln(cmd.OutOrStdout(), colorizeJSON(string(jsonData)))
+		return nil
+	}
 	fmt.Fprintln(cmd.OutOrStdout(), string(jsonData))
 	return nil
 }
 
 // PrintFormatted renders data honoring the global --query flag and the given
-// output format ("json", "table", "tsv", "yaml", or "none"). It matches
-// azure-cli's behavior closely enough for scripting: --query is applied
-// first, then the result is rendered.
+// output format (one of Formats). It matches azure-cli's behavior closely
+// enough for scripting: --query is applied first, then the result is
+// rendered.
 func PrintFormatted(cmd *cobra.Command, data interface{}, format string) error {
 	queryStr, _ := cmd.Flags().GetString("query")
 
-	// Normalize through JSON so query and rendering operate on the same
-	// generic shape regardless of the concrete Go type passed in.
-	jsonData, err := json.Marshal(data)
+	result, err := evaluate(data, queryStr)
 	if err != nil {
-		return fmt.Errorf("failed to format output: %w", err)
-	}
-
-	var result interface{}
-	if queryStr == "" {
-		// No --query: decode numbers as json.Number so large integers and
-		// wire-literal decimals (e.g. int64 values above 2^53, "3.0") render
-		// exactly as received instead of round-tripping through float64.
-		dec := json.NewDecoder(bytes.NewReader(jsonData))
-		dec.UseNumber()
-		if err := dec.Decode(&result); err != nil {
-			return fmt.Errorf("failed to parse output: %w", err)
-		}
-	} else {
-		// go-jmespath v0.4.0 is hardcoded to float64: its numeric builtins
-		// (avg, sum, max, ceil, ...) error on a json.Number argument and its
-		// comparators silently evaluate to false. So the UseNumber decode is
-		// used only for the queries query.PreservesNumberLiterals proves are
-		// free of both (no function call, no comparator, anywhere in the AST)
-		// - which is most real --query usage: field paths, projections and
-		// multiselect hashes. Everything else decodes to float64 exactly as
-		// before. See PreservesNumberLiterals for the full argument.
-		dec := json.NewDecoder(bytes.NewReader(jsonData))
-		if query.PreservesNumberLiterals(queryStr) {
-			dec.UseNumber()
-		}
-		if err := dec.Decode(&result); err != nil {
-			return fmt.Errorf("failed to parse output: %w", err)
-		}
-		result, err = query.ApplyJMESPath(result, queryStr)
-		if err != nil {
-			return err
-		}
-		// Re-encode the query's result and decode it again with UseNumber so
-		// an integer-shaped value stays an integer literal for rendering
-		// (matches azure-cli; a bare float64 would otherwise render "8080.0"
-		// for an ARM integer field selected by --query). json.Marshal writes a
-		// json.Number verbatim, so this is a no-op for values that survived
-		// the UseNumber path above.
-		//
-		// REMAINING, DOCUMENTED DIVERGENCE from knack, for the queries
-		// PreservesNumberLiterals refuses (those containing a function call or
-		// a comparator): the float64 decode has already lost precision before
-		// this re-encode runs, and re-decoding with UseNumber cannot recover
-		// it. Three classes:
-		//   - an integer literal outside float64's exact range, i.e. above
-		//     2^53 (or below -2^53), rounds to the nearest representable
-		//     float64 (e.g. 9007199254740993 -> 9007199254740992,
-		//     9223372036854775807 -> 9223372036854776000); knack, with
-		//     arbitrary-precision Python ints, renders it exactly.
-		//   - a wire literal that is a genuine float but happens to be
-		//     integral (e.g. 3.0, or an ARM value like 25000000000.0) comes
-		//     back from float64 with no way to tell it apart from an integer,
-		//     so it renders without its decimal point (e.g. "3", not knack's
-		//     "3.0"); the same loss applies to -0.0, which renders "0".
-		//   - a wire literal in exponent form from 1e+16 (inclusive) up to
-		//     1e+21 (exclusive) reprints in positional form (1e+16 ->
-		//     10000000000000000), because Go's float64 encoder switches to
-		//     exponent form at 1e+21 while Python's repr switches at 1e+16.
-		//     At or above 1e+21 the two agree again (both print "1e+21").
-		// A separate divergence this cannot address at all: Python jmespath's
-		// sum/max/min/ceil/floor return an int for integral input (knack
-		// prints sum([1,2,3]) as "6", avg([1,2,3]) as "2.0"), while
-		// go-jmespath returns float64 for every one of them, so avg renders
-		// "2" here.
-		if reencoded, err := json.Marshal(result); err == nil {
-			dec := json.NewDecoder(bytes.NewReader(reencoded))
-			dec.UseNumber()
-			var renum interface{}
-			if dec.Decode(&renum) == nil {
-				result = renum
-			}
-		}
+		return err
 	}
 
 	// ko recovers a --query multiselect-hash's declared column order (see
@@ -182,27 +115,78 @@ func PrintFormatted(cmd *cobra.Command, data interface{}, format string) error {
 	case "table":
 		fmt.Fprint(w, renderTable(result, ko))
 		return nil
-	case "yaml":
+	case "tsv-with-headers":
+		fmt.Fprint(w, renderTSVWithHeaders(result, ko))
+		return nil
+	case "csv":
+		fmt.Fprint(w, renderCSV(result, ko))
+		return nil
+	case "yaml", "yamlc":
 		out, err := renderYAML(result)
 		if err != nil {
 			return fmt.Errorf("failed to format output: %w", err)
 		}
+		if strings.EqualFold(format, "yamlc") && ColorEnabled(w) {
+			out = colorizeYAML(out)
+		}
+		fmt.Fprint(w, out)
+		return nil
+	case "ndjson":
+		out, err := renderNDJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
 		fmt.Fprint(w, out)
 		return nil
 	case "none":
 		return nil
-	case "json", "":
+	case "json", "jsonc", "":
 		out, err := marshalIndentNoEscape(result)
 		if err != nil {
 			return fmt.Errorf("failed to format output: %w", err)
 		}
+		if strings.EqualFold(format, "jsonc") && ColorEnabled(w) {
+			out = []byte(colorizeJSON(string(out)))
+		}
 		fmt.Fprintln(w, string(out))
 		return nil
 	default:
-		return fmt.Errorf("argument --output/-o: invalid choice: %q (choose from 'json', 'table', 'tsv', 'yaml', 'none')", format)
+		return fmt.Errorf("argument --output/-o: invalid choice: %q (choose from '%s')", format, strings.Join(Formats, "', '"))
 	}
 }
 
+// evaluate normalizes data to the generic JSON tree the renderers take and
+// applies queryStr (if any) to it.
+func evaluate(data interface{}, queryStr string) (interface{}, error) {
+	// Normalize through JSON so query and rendering operate on the same
+	// generic shape regardless of the concrete Go type passed in.
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format output: %w", err)
+	}
+
+	// Decode numbers as json.Number so large integers and wire-literal
+	// decimals (e.g. int64 values above 2^53, "3.0") render exactly as
+	// received instead of round-tripping through float64. The query engine
+	// works on json.Number directly, so this holds with --query too.
+	var result interface{}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse output: %w", err)
+	}
+	if queryStr != "" {
+		result, err = query.ApplyJMESPath(result, queryStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Formats are the accepted --output values.
+var Formats = []string{"json", "jsonc", "yaml", "yamlc", "table", "tsv", "tsv-with-headers", "csv", "ndjson", "none"}
+
 // keyOrder maps a map's key SET to the order a --query multiselect-hash wrote
 // those keys (azure-go-cli-c41). A nil *keyOrder means "no --query ordering
 // information": every map is rendered in sorted-key order, which is what
@@ -553,60 +537,22 @@ func renderTable(v interface{}, ko *keyOrder) string {
 		return "\n"
 	}
 
-	var items []interface{}
-	if list, ok := v.([]interface{}); ok {
-		items = list
-	} else {
-		items = []interface{}{v}
-	}
-
-	rows := make([]tableRowData, len(items))
-	for i, item := range items {
-		rows[i] = tableEntry(item, ko)
-	}
-
-	// Column set: first-seen union of headers across rows.
-	var columns []string
-	seen := map[string]bool{}
-	for _, row := range rows {
-		for _, h := range row.headers {
-			if !seen[h] {
-				seen[h] = true
-				columns = append(columns, h)
-			}
-		}
-	}
+	columns, cells, rawCells := tableMatrix(v, ko)
 	if len(columns) == 0 {
 		return "\n"
 	}
 
-	// Cell matrix, indexed [row][column]; missing values are "".
-	cells := make([][]string, len(rows))
-	for i, row := range rows {
-		byHeader := make(map[string]string, len(row.headers))
-		for j, h := range row.headers {
-			byHeader[h] = row.cells[j]
-		}
-		cells[i] = make([]string, len(columns))
-		for j, h := range columns {
-			cells[i][j] = byHeader[h]
-		}
-	}
-
-	// Raw (pre-pyStrip) cell matrix, aligned the same way as cells, used
-	// only for the isMultiline decision below.
-	rawCells := make([][]string, len(rows))
-	for i, row := range rows {
-		byHeader := make(map[string]string, len(row.headers))
-		for j, h := range row.headers {
-			byHeader[h] = row.rawCells[j]
-		}
-		rawCells[i] = make([]string, len(columns))
-		for j, h := range columns {
-			rawCells[i][j] = byHeader[h]
-		}
-	}
+	var b strings.Builder
+	writeTable(&b, columns, cells, rawCells, nil)
+	return b.String()
+}
 
+// writeTable writes a table of tableMatrix output to b. With widths nil it
+// sizes every column from this data and writes the header and rule lines,
+// which is all renderTable needs. A Stream passes the widths an earlier
+// batch was laid out with instead, so only the rows are written, aligned to
+// the columns already on screen. The widths used are returned.
+func writeTable(b *strings.Builder, columns []string, cells, rawCells [][]string, widths []int) []int {
 	// tabulate decides multiline mode ONCE for the whole table, by searching
 	// the flattened text of every RAW header and cell for \r or \n — BEFORE
 	// any stripping happens (tabulate/__init__.py:2367-2388, which runs
@@ -658,11 +604,16 @@ func renderTable(v interface{}, ko *keyOrder) string {
 		}
 	}
 
+	if widths != nil {
+		writeTableLines(b, lineRows, heights, widths)
+		return widths
+	}
+
 	// tabulate applies MIN_PADDING (2) as a floor on each header's width, so a
 	// column is at least runeLen(header)+2 wide even when its data is
 	// narrower. Width is measured in runes (code points), matching tabulate
 	// without wcwidth installed.
-	widths := make([]int, len(columns))
+	widths = make([]int, len(columns))
 	for i, h := range columns {
 		widths[i] = utf8.RuneCountInString(h) + 2
 	}
@@ -686,31 +637,97 @@ func renderTable(v interface{}, ko *keyOrder) string {
 		}
 	}
 
-	var b strings.Builder
 	// Headers containing a newline are written as-is rather than split into
 	// continuation lines the way tabulate splits multi-line headers; out of
 	// scope for this fix (very unlikely with real Azure keys, since a
 	// header comes from a JSON object key or a --query alias).
 	headers := make([]string, len(columns))
 	copy(headers, columns)
-	writeTableRow(&b, headers, widths)
+	writeTableRow(b, headers, widths)
 	rules := make([]string, len(columns))
 	for i, w := range widths {
 		rules[i] = strings.Repeat("-", w)
 	}
-	writeTableRow(&b, rules, widths)
+	writeTableRow(b, rules, widths)
+	writeTableLines(b, lineRows, heights, widths)
+	return widths
+}
+
+// writeTableLines writes each row's sub-lines, padded to widths.
+func writeTableLines(b *strings.Builder, lineRows [][][]string, heights, widths []int) {
 	for i, row := range lineRows {
 		for line := 0; line < heights[i]; line++ {
-			lineCells := make([]string, len(columns))
+			lineCells := make([]string, len(widths))
 			for j, sublines := range row {
 				if line < len(sublines) {
 					lineCells[j] = sublines[line]
 				}
 			}
-			writeTableRow(&b, lineCells, widths)
+			writeTableRow(b, lineCells, widths)
 		}
 	}
-	return b.String()
+}
+
+// tableMatrix lays v out the way knack's -o table does: one row per list
+// element (a non-list is a single row), columns in first-seen order across
+// rows, missing values "". cells are pyStrip-ed for display; rawCells hold
+// the same values unstripped. The csv and tsv-with-headers formats share it
+// so every columnar format agrees on which columns exist.
+func tableMatrix(v interface{}, ko *keyOrder) (columns []string, cells, rawCells [][]string) {
+	var items []interface{}
+	if list, ok := v.([]interface{}); ok {
+		items = list
+	} else {
+		items = []interface{}{v}
+	}
+
+	rows := make([]tableRowData, len(items))
+	for i, item := range items {
+		rows[i] = tableEntry(item, ko)
+	}
+
+	// Column set: first-seen union of headers across rows.
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for _, h := range row.headers {
+			if !seen[h] {
+				seen[h] = true
+				columns = append(columns, h)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil, nil, nil
+	}
+
+	// Cell matrix, indexed [row][column]; missing values are "".
+	cells = make([][]string, len(rows))
+	for i, row := range rows {
+		byHeader := make(map[string]string, len(row.headers))
+		for j, h := range row.headers {
+			byHeader[h] = row.cells[j]
+		}
+		cells[i] = make([]string, len(columns))
+		for j, h := range columns {
+			cells[i][j] = byHeader[h]
+		}
+	}
+
+	// Raw (pre-pyStrip) cell matrix, aligned the same way as cells. The
+	// table uses it only for renderTable's isMultiline decision; csv and
+	// tsv-with-headers emit it as the cell values.
+	rawCells = make([][]string, len(rows))
+	for i, row := range rows {
+		byHeader := make(map[string]string, len(row.headers))
+		for j, h := range row.headers {
+			byHeader[h] = row.rawCells[j]
+		}
+		rawCells[i] = make([]string, len(columns))
+		for j, h := range columns {
+			rawCells[i][j] = byHeader[h]
+		}
+	}
+	return columns, cells, rawCells
 }
 
 // tableRowData is one row's ordered (header, cell) pairs, as knack's
@@ -947,7 +964,9 @@ func writeTableRow(b *strings.Builder, cells []string, widths []int) {
 			line[i] = c
 			continue
 		}
-		line[i] = c + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c))
+		// A streamed row can be wider than the widths fixed by the first
+		// batch; it then pushes the following columns right.
+		line[i] = c + strings.Repeat(" ", max(widths[i]-utf8.RuneCountInString(c), 0))
 	}
 	b.WriteString(strings.TrimRightFunc(strings.Join(line, "  "), func(r rune) bool {
 		return unicode.IsSpace(r) || (r >= 0x1c && r <= 0x1f)
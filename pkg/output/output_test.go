@@ -3,10 +3,12 @@ package output
 import (
 	"bytes"
 	_ "embed"
+	"encoding/csv"
 	"encoding/json"
 	"math"
 	"math/rand"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -131,7 +133,7 @@ func TestPrintFormatted_QueryFromPersistentFlag(t *testing.T) {
 // TestPrintFormatted_SupportedFormats confirms every documented -o value
 // (case-insensitively) is accepted.
 func TestPrintFormatted_SupportedFormats(t *testing.T) {
-	for _, format := range []string{"json", "", "JSON", "table", "Table", "tsv", "yaml", "YAML", "none", "NONE"} {
+	for _, format := range []string{"json", "", "JSON", "jsonc", "table", "Table", "tsv", "tsv-with-headers", "csv", "CSV", "ndjson", "yaml", "YAML", "yamlc", "none", "NONE"} {
 		cmd := &cobra.Command{}
 		cmd.Flags().String("query", "", "")
 		var buf bytes.Buffer
@@ -145,7 +147,7 @@ func TestPrintFormatted_SupportedFormats(t *testing.T) {
 // TestPrintFormatted_InvalidFormat confirms an unknown -o value errors
 // instead of silently rendering JSON.
 func TestPrintFormatted_InvalidFormat(t *testing.T) {
-	for _, format := range []string{"tsvv", "jsonc", "yamlc", "xml"} {
+	for _, format := range []string{"tsvv", "jsonl", "tsvh", "xml"} {
 		cmd := &cobra.Command{}
 		cmd.Flags().String("query", "", "")
 		var buf bytes.Buffer
@@ -649,7 +651,7 @@ func TestPrintFormatted_IntegerBeyondYAMLIntRange(t *testing.T) {
 // TestPrintFormatted_QueryIntegerNotFloat is the D7 regression test: an
 // integer selected or computed by --query must render as an integer, not a
 // Python-str(float)-style "N.0". Before this fix, --query results were
-// decoded as plain float64 (go-jmespath's numeric builtins require it), so
+// decoded as plain float64 (the old query engine's builtins required it), so
 // PrintFormatted rendered "8080.0" where knack (and this package's own
 // no-query path) renders "8080".
 func TestPrintFormatted_QueryIntegerNotFloat(t *testing.T) {
@@ -995,12 +997,23 @@ func countOutOfRangeInts(v interface{}) int {
 // "tsv" value is NOT knack's literal output for that input (knack renders
 // "None\n") — it encodes this package's D2a divergence instead (see its
 // tsvDivergenceNote field in goldens.json, and renderTSV's doc comment).
+//
+// The "csv", "tsvWithHeaders", "ndjson", "jsonc" and "yamlc" expectations
+// have no azure-cli counterpart to be diffed against (knack has no csv,
+// tsv-with-headers or ndjson, and its jsonc/yamlc colours come from
+// pygments); they pin this package's own output. csv and tsvWithHeaders must
+// carry the same columns as "table".
 type golden struct {
-	Name  string          `json:"name"`
-	Input json.RawMessage `json:"input"`
-	Table string          `json:"table"`
-	YAML  string          `json:"yaml"`
-	TSV   string          `json:"tsv"`
+	Name           string          `json:"name"`
+	Input          json.RawMessage `json:"input"`
+	Table          string          `json:"table"`
+	YAML           string          `json:"yaml"`
+	TSV            string          `json:"tsv"`
+	CSV            string          `json:"csv"`
+	TSVWithHeaders string          `json:"tsvWithHeaders"`
+	NDJSON         string          `json:"ndjson"`
+	JSONC          string          `json:"jsonc"`
+	YAMLC          string          `json:"yamlc"`
 }
 
 //go:embed testdata/goldens.json
@@ -1042,6 +1055,72 @@ func TestGoldens(t *testing.T) {
 			if got != g.YAML {
 				t.Errorf("renderYAML: got %q, want %q", got, g.YAML)
 			}
+			if got := colorizeYAML(got); got != g.YAMLC {
+				t.Errorf("colorizeYAML: got %q, want %q", got, g.YAMLC)
+			}
+			if got := renderCSV(v, nil); got != g.CSV {
+				t.Errorf("renderCSV: got %q, want %q", got, g.CSV)
+			}
+			if got := renderTSVWithHeaders(v, nil); got != g.TSVWithHeaders {
+				t.Errorf("renderTSVWithHeaders: got %q, want %q", got, g.TSVWithHeaders)
+			}
+			got, err = renderNDJSON(v)
+			if err != nil {
+				t.Fatalf("renderNDJSON: %v", err)
+			}
+			if got != g.NDJSON {
+				t.Errorf("renderNDJSON: got %q, want %q", got, g.NDJSON)
+			}
+			js, err := marshalIndentNoEscape(v)
+			if err != nil {
+				t.Fatalf("marshalIndentNoEscape: %v", err)
+			}
+			if got := colorizeJSON(string(js)) + "\n"; got != g.JSONC {
+				t.Errorf("colorizeJSON: got %q, want %q", got, g.JSONC)
+			}
+		})
+	}
+}
+
+// TestGoldenColorStripsToPlain proves colouring only adds escape sequences:
+// with them removed, jsonc and yamlc are byte-identical to json and yaml.
+func TestGoldenColorStripsToPlain(t *testing.T) {
+	ansi := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	for _, g := range loadGoldens(t) {
+		t.Run(g.Name, func(t *testing.T) {
+			var v interface{}
+			dec := json.NewDecoder(bytes.NewReader(g.Input))
+			dec.UseNumber()
+			if err := dec.Decode(&v); err != nil {
+				t.Fatalf("decode input: %v", err)
+			}
+			js, _ := marshalIndentNoEscape(v)
+			if got := ansi.ReplaceAllString(g.JSONC, ""); got != string(js)+"\n" {
+				t.Errorf("jsonc without colour: got %q, want %q", got, string(js)+"\n")
+			}
+			if got := ansi.ReplaceAllString(g.YAMLC, ""); got != g.YAML {
+				t.Errorf("yamlc without colour: got %q, want %q", got, g.YAML)
+			}
+		})
+	}
+}
+
+// TestGoldenCSVParses proves the csv output is well-formed RFC 4180 that
+// encoding/csv reads back to the header plus one record per row.
+func TestGoldenCSVParses(t *testing.T) {
+	for _, g := range loadGoldens(t) {
+		if g.CSV == "" {
+			continue
+		}
+		t.Run(g.Name, func(t *testing.T) {
+			records, err := csv.NewReader(strings.NewReader(g.CSV)).ReadAll()
+			if err != nil {
+				t.Fatalf("parse csv: %v", err)
+			}
+			header := strings.Split(strings.SplitN(g.TSVWithHeaders, "\n", 2)[0], "\t")
+			if !reflect.DeepEqual(records[0], header) {
+				t.Errorf("csv header %q, want %q", records[0], header)
+			}
 		})
 	}
 }
@@ -1279,10 +1358,8 @@ func TestHashOrderNotBorrowedByPassthroughMap(t *testing.T) {
 		// Hash is NON-terminal: the result is `p`, so `p`'s own order wins.
 		{"{b: p, a: one}.b", "A    B\n---  ---\naa   bb\n"},
 		{"objs[].{b: p, a: x}[].b", "A    B\n---  ---\na1   b1\na2   b2\n"},
-		// `{b: p, a: one}.*` is the third leak shape, but go-jmespath's value
-		// projection iterates a Go map, so its ROW order is nondeterministic
-		// and the rendered table cannot be pinned here. Its guard is asserted
-		// deterministically in pkg/query's TestMultiSelectHashKeyOrders.
+		// `{b: p, a: one}.*` is the third leak shape; its guard is asserted
+		// in pkg/query's TestMultiSelectHashKeyOrders.
 		// Hash IS terminal: the declared order must still be honoured.
 		{"objs[].{b: x, a: y} | [0]", "B    A\n---  ---\nX1   Y1\n"},
 		{"objs[].{Name: x, Location: y}", "Name    Location\n------  ----------\nX1      Y1\nX2      Y2\n"},
@@ -1516,27 +1593,17 @@ func TestQueryNumericFidelity(t *testing.T) {
 	}
 }
 
-// TestQueryNumericBuiltinsUnaffected is the other half of the v39 guard: the
-// UseNumber decode must never reach a query containing a function or a
-// comparator, because go-jmespath v0.4.0 errors on the former and silently
-// returns the wrong rows for the latter. Each want below is what the query
-// must still produce; a regression that widened PreservesNumberLiterals would
-// turn the function rows into errors and empty the comparator rows.
-//
-// Every want was generated by running knack 0.14.0 + jmespath 1.1.0 over
-// numFidelityDoc, and every one matches EXCEPT avg(a): Python jmespath keeps
-// int/float apart in its numeric builtins (sum/max/min/ceil/floor return an
-// int for integral input, avg always returns a float), so knack prints
-// "2.0\n" where go-jmespath's uniformly-float64 avg gives "2\n". That is a
-// go-jmespath return-type divergence, not the v39 input-fidelity one, and is
-// out of this fix's scope.
-func TestQueryNumericBuiltinsUnaffected(t *testing.T) {
+// TestQueryNumericBuiltins pins the numeric builtins and comparators over
+// json.Number input. Every want was generated by running knack 0.14.0 +
+// jmespath 1.1.0 over numFidelityDoc, including Python's int/float split:
+// sum/max/min/ceil/floor of integers print as integers, avg as a float.
+func TestQueryNumericBuiltins(t *testing.T) {
 	data := numFidelityData(t)
 	tests := []struct {
 		query string
 		tsv   string
 	}{
-		{"avg(a)", "2\n"}, // knack: "2.0\n" - see note above
+		{"avg(a)", "2.0\n"},
 		{"sum(a)", "6\n"},
 		{"max(a)", "3\n"},
 		{"min(a)", "1\n"},
@@ -1551,6 +1618,9 @@ func TestQueryNumericBuiltinsUnaffected(t *testing.T) {
 		{"items[?v == `2`].n", "x\n"},
 		{"items[?v != `2`].n", "y\n"},
 		{"items[?n == 'x'].n", "x\n"},
+		{"sum([big, big])", "18014398509481986\n"},
+		{"items[?big == `9223372036854775807`].n", "x\n"},
+		{"items[?big > `9007199254740992`].n", "x\n"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.query, func(t *testing.T) {
@@ -1561,16 +1631,44 @@ func TestQueryNumericBuiltinsUnaffected(t *testing.T) {
 	}
 }
 
-// TestQueryNumericFidelityRemainingDivergence documents, so it cannot regress
-// unnoticed, the part of azure-go-cli-v39 that is NOT fixed: a query that
-// contains a comparator or a function still evaluates over a float64 tree, so
-// int64 max still rounds. knack renders "9223372036854775807" here. Closing
-// this would require teaching go-jmespath about json.Number, i.e. vendoring
-// it (azure-go-cli-xmf).
-func TestQueryNumericFidelityRemainingDivergence(t *testing.T) {
+// TestQueryNumericFidelityThroughFilters confirms a query with a comparator
+// or a function keeps number literals exact, like any other query.
+func TestQueryNumericFidelityThroughFilters(t *testing.T) {
 	data := numFidelityData(t)
-	const want = "9223372036854776000\n" // knack: "9223372036854775807\n"
+	const want = "9223372036854775807\n"
 	if got := printQueried(t, data, "items[?n=='x'].big", "tsv"); got != want {
 		t.Errorf("tsv = %q, want %q", got, want)
 	}
 }
+
+// TestRenderCSVQuoting covers the RFC 4180 quoting rules the goldens don't.
+func TestRenderCSVQuoting(t *testing.T) {
+	v := []interface{}{
+		map[string]interface{}{"name": `say "hi"`, "tags": "a,b", "note": " padded"},
+	}
+	want := "Name,Note,Tags\r\n\"say \"\"hi\"\"\",\" padded\",\"a,b\"\r\n"
+	if got := renderCSV(v, nil); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestPrintFormatted_ColorFallsBackWhenPiped confirms jsonc and yamlc write
+// plain json and yaml when stdout is not a terminal.
+func TestPrintFormatted_ColorFallsBackWhenPiped(t *testing.T) {
+	data := map[string]interface{}{"name": "x", "count": 2}
+	for _, pair := range [][2]string{{"jsonc", "json"}, {"yamlc", "yaml"}} {
+		render := func(format string) string {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("query", "", "")
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			if err := PrintFormatted(cmd, data, format); err != nil {
+				t.Fatalf("format %q: %v", format, err)
+			}
+			return buf.String()
+		}
+		if got, want := render(pair[0]), render(pair[1]); got != want {
+			t.Errorf("-o %s piped: got %q, want %q", pair[0], got, want)
+		}
+	}
+}
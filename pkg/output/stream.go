@@ -0,0 +1,294 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cdobbyn/azure-go-cli/pkg/query"
+	"github.com/spf13/cobra"
+)
+
+// Stream prints a list command's results as its pages arrive instead of
+// after the last one, so `az resource list` over thousands of resources
+// shows output immediately and never holds the whole list in memory:
+//
+//	s := output.NewStream(cmd)
+//	for pager.More() {
+//		page, err := pager.NextPage(ctx)
+//		if err != nil {
+//			return s.Abort(fmt.Errorf("failed to list ...: %w", err))
+//		}
+//		if err := output.StreamPage(s, page.Value); err != nil {
+//			return err
+//		}
+//	}
+//	return s.Close()
+//
+// A failure after the first page has been written goes through Abort (Write
+// does this itself), which closes the JSON array so what was printed still
+// parses.
+//
+// For json, jsonc, yaml, yamlc, tsv and ndjson the output is byte-identical
+// to PrintJSON of the whole list. table, csv and tsv-with-headers fix their
+// columns from the first page: a column that only appears on a later page is
+// not shown, and table column widths don't grow (wider cells push the rest of
+// their row right).
+//
+// --query is applied per element when query.IsElementwise says that is
+// equivalent (`[].name`, `[?location=='eastus']`, ...). Any other query needs
+// the whole list, so the stream buffers every page and prints with PrintJSON
+// on Close, exactly as a non-streaming command would.
+type Stream struct {
+	cmd      *cobra.Command
+	w        io.Writer
+	format   string
+	queryStr string
+	ko       *keyOrder
+	color    bool
+
+	// buffered collects every item when the query can't be applied per
+	// element or the format is unknown (so PrintFormatted reports it).
+	buffer   bool
+	buffered []interface{}
+
+	count   int
+	columns []string // table/csv/tsv-with-headers layout, from the first page
+	widths  []int    // table column widths, from the first page
+}
+
+// NewStream starts a streamed list for cmd, honoring its --output and
+// --query flags. Nothing is written until the first page arrives.
+func NewStream(cmd *cobra.Command) *Stream {
+	format, _ := cmd.Flags().GetString("output")
+	queryStr, _ := cmd.Flags().GetString("query")
+	s := &Stream{
+		cmd:      cmd,
+		w:        cmd.OutOrStdout(),
+		format:   strings.ToLower(format),
+		queryStr: queryStr,
+	}
+	if s.format == "" {
+		s.format = "json"
+	}
+	if queryStr != "" {
+		s.buffer = !query.IsElementwise(queryStr)
+		s.ko = newKeyOrder(query.MultiSelectHashKeyOrders(queryStr))
+	}
+	switch s.format {
+	case "json", "yaml", "tsv", "table", "csv", "tsv-with-headers", "ndjson", "none":
+	case "jsonc", "yamlc":
+		s.color = ColorEnabled(s.w)
+	default:
+		s.buffer = true
+	}
+	return s
+}
+
+// StreamPage writes one page of items to s. Items can be of any type
+// PrintJSON accepts; pass a whole page per call so table output can size
+// its columns from as much data as possible.
+func StreamPage[T any](s *Stream, items []T) error {
+	page := make([]interface{}, len(items))
+	for i, item := range items {
+		page[i] = item
+	}
+	return s.Write(page)
+}
+
+// Write writes one page of items to s. If the page can't be written, the
+// stream is aborted and the error returned.
+func (s *Stream) Write(page []interface{}) error {
+	if err := s.write(page); err != nil {
+		return s.Abort(err)
+	}
+	return nil
+}
+
+func (s *Stream) write(page []interface{}) error {
+	if s.buffer {
+		s.buffered = append(s.buffered, page...)
+		return nil
+	}
+	if s.format == "none" {
+		return nil
+	}
+
+	// json without --query marshals the items directly, as PrintJSON does,
+	// so struct field order survives. Everything else goes through the same
+	// normalization as PrintFormatted.
+	if (s.format == "json" || s.format == "jsonc") && s.queryStr == "" {
+		return s.writeJSON(page)
+	}
+	var items []interface{}
+	for _, item := range page {
+		result, err := evaluate([]interface{}{item}, s.queryStr)
+		if err != nil {
+			return err
+		}
+		list, _ := result.([]interface{})
+		items = append(items, list...)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	switch s.format {
+	case "json", "jsonc":
+		return s.writeJSON(items)
+	case "yaml", "yamlc":
+		out, err := renderYAML(items)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		if s.color {
+			out = colorizeYAML(out)
+		}
+		fmt.Fprint(s.w, out)
+	case "tsv":
+		fmt.Fprint(s.w, renderTSV(items, s.queryStr != "", s.ko))
+	case "ndjson":
+		out, err := renderNDJSON(items)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprint(s.w, out)
+	case "table", "csv", "tsv-with-headers":
+		s.writeColumns(items)
+	}
+	s.count += len(items)
+	return nil
+}
+
+// writeJSON continues the indented JSON array, opening it on the first
+// item. Each element is indented one level, exactly as marshalIndentNoEscape
+// indents it inside the whole list.
+func (s *Stream) writeJSON(items []interface{}) error {
+	for _, item := range items {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("  ", "  ")
+		if err := enc.Encode(item); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		text := string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+		if s.color {
+			text = colorizeJSON(text)
+		}
+		if s.count == 0 {
+			fmt.Fprint(s.w, "[\n  "+text)
+		} else {
+			fmt.Fprint(s.w, ",\n  "+text)
+		}
+		s.count++
+	}
+	return nil
+}
+
+// writeColumns writes items as table, csv or tsv-with-headers rows. The
+// first call fixes the columns (and writes the header); later rows are
+// re-aligned to them.
+func (s *Stream) writeColumns(items []interface{}) {
+	columns, cells, rawCells := tableMatrix(items, s.ko)
+	if s.columns == nil {
+		if len(columns) == 0 {
+			return
+		}
+		s.columns = columns
+		switch s.format {
+		case "table":
+			var b strings.Builder
+			s.widths = writeTable(&b, columns, cells, rawCells, nil)
+			fmt.Fprint(s.w, b.String())
+		case "csv":
+			fmt.Fprint(s.w, renderCSV(items, s.ko))
+		case "tsv-with-headers":
+			fmt.Fprint(s.w, renderTSVWithHeaders(items, s.ko))
+		}
+		return
+	}
+
+	cells = alignColumns(columns, cells, s.columns)
+	rawCells = alignColumns(columns, rawCells, s.columns)
+	switch s.format {
+	case "table":
+		var b strings.Builder
+		writeTable(&b, s.columns, cells, rawCells, s.widths)
+		fmt.Fprint(s.w, b.String())
+	case "csv":
+		var b strings.Builder
+		for _, row := range rawCells {
+			writeCSVRecord(&b, row)
+		}
+		fmt.Fprint(s.w, b.String())
+	case "tsv-with-headers":
+		for _, row := range rawCells {
+			fmt.Fprint(s.w, strings.Join(row, "\t")+"\n")
+		}
+	}
+}
+
+// alignColumns reorders rows laid out under columns to the layout under
+// want, dropping columns want doesn't have and leaving missing ones "".
+func alignColumns(columns []string, rows [][]string, want []string) [][]string {
+	index := make(map[string]int, len(columns))
+	for i, c := range columns {
+		index[c] = i
+	}
+	aligned := make([][]string, len(rows))
+	for r, row := range rows {
+		aligned[r] = make([]string, len(want))
+		for i, c := range want {
+			if j, ok := index[c]; ok {
+				aligned[r][i] = row[j]
+			}
+		}
+	}
+	return aligned
+}
+
+// Abort ends a stream that failed partway, returning err. It closes a JSON
+// array that is already open, so stdout holds valid JSON of the items
+// printed so far; buffered items are dropped, as a non-streaming command
+// prints nothing when it fails. Close must not be called afterwards.
+func (s *Stream) Abort(err error) error {
+	if !s.buffer && (s.format == "json" || s.format == "jsonc") && s.count > 0 {
+		fmt.Fprintln(s.w, "\n]")
+		s.count = 0 // a second Abort writes nothing
+	}
+	s.buffered = nil
+	return err
+}
+
+// Close finishes the output: it closes the JSON array, writes the empty-list
+// rendering if no item arrived, or prints the buffered list.
+func (s *Stream) Close() error {
+	if s.buffer {
+		if s.buffered == nil {
+			s.buffered = []interface{}{}
+		}
+		return PrintJSON(s.cmd, s.buffered)
+	}
+
+	switch s.format {
+	case "json", "jsonc":
+		if s.count == 0 {
+			fmt.Fprintln(s.w, "[]")
+		} else {
+			fmt.Fprintln(s.w, "\n]")
+		}
+	case "yaml", "yamlc":
+		if s.count == 0 {
+			out, _ := renderYAML([]interface{}{})
+			fmt.Fprint(s.w, out)
+		}
+	case "table":
+		if s.columns == nil {
+			fmt.Fprint(s.w, "\n")
+		}
+	}
+	return nil
+}
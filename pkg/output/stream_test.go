@@ -0,0 +1,164 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func streamTestCommand(format, queryStr string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", format, "")
+	cmd.Flags().String("query", queryStr, "")
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	return cmd, &buf
+}
+
+type streamTestResource struct {
+	Name     string            `json:"name"`
+	Location string            `json:"location"`
+	ID       string            `json:"id"`
+	Size     int64             `json:"size"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+var streamTestPages = [][]streamTestResource{
+	{
+		{Name: "vnet-alpha-long-name", Location: "westeurope", ID: "/r/1", Size: 9007199254740993, Tags: map[string]string{"env": "dev"}},
+		{Name: "b", Location: "eastus", ID: "/r/2", Size: 2},
+	},
+	{},
+	{
+		{Name: "c", Location: "eastus", ID: "/r/3", Size: 3, Tags: map[string]string{"env": "prod & <test>"}},
+	},
+}
+
+// TestStreamMatchesPrintJSON confirms streaming page by page prints exactly
+// what PrintJSON prints for the whole list, for every format and query the
+// stream handles without buffering, and for the queries it must buffer.
+func TestStreamMatchesPrintJSON(t *testing.T) {
+	var all []streamTestResource
+	for _, page := range streamTestPages {
+		all = append(all, page...)
+	}
+
+	formats := []string{"json", "jsonc", "yaml", "yamlc", "tsv", "ndjson", "table", "csv", "tsv-with-headers", "none"}
+	queries := []string{
+		"",
+		"[].name",
+		"[?location=='eastus'].{Name:name, Size:size}",
+		"[].[name, location]",
+		"[?tags.env]",
+		"[?location=='nowhere']",
+		// Not elementwise: buffered and printed on Close.
+		"[0]",
+		"length(@)",
+		"[].name | sort(@)",
+	}
+	for _, format := range formats {
+		for _, q := range queries {
+			cmd, want := streamTestCommand(format, q)
+			if err := PrintJSON(cmd, all); err != nil {
+				t.Fatalf("-o %s --query %q: PrintJSON: %v", format, q, err)
+			}
+
+			cmd, got := streamTestCommand(format, q)
+			s := NewStream(cmd)
+			for _, page := range streamTestPages {
+				if err := StreamPage(s, page); err != nil {
+					t.Fatalf("-o %s --query %q: StreamPage: %v", format, q, err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("-o %s --query %q: Close: %v", format, q, err)
+			}
+
+			if got.String() != want.String() {
+				t.Errorf("-o %s --query %q:\nstreamed %q\nwant     %q", format, q, got.String(), want.String())
+			}
+		}
+	}
+}
+
+// TestStreamEmpty confirms a stream that never receives an item renders the
+// empty list the way PrintJSON does.
+func TestStreamEmpty(t *testing.T) {
+	for _, format := range []string{"json", "yaml", "tsv", "ndjson", "table", "csv"} {
+		cmd, want := streamTestCommand(format, "")
+		if err := PrintJSON(cmd, []streamTestResource{}); err != nil {
+			t.Fatal(err)
+		}
+		cmd, got := streamTestCommand(format, "")
+		s := NewStream(cmd)
+		if err := StreamPage(s, []streamTestResource{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("-o %s: streamed %q, want %q", format, got.String(), want.String())
+		}
+	}
+}
+
+// TestStreamTableFixesColumnsFromFirstPage pins the documented table
+// divergence: columns come from the first page and later rows keep their
+// widths.
+func TestStreamTableFixesColumnsFromFirstPage(t *testing.T) {
+	cmd, got := streamTestCommand("table", "")
+	s := NewStream(cmd)
+	StreamPage(s, []map[string]interface{}{{"name": "a", "location": "eastus"}})
+	StreamPage(s, []map[string]interface{}{{"name": "longer-name", "location": "westus", "kind": "new"}})
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "Location    Name\n----------  ------\neastus      a\nwestus      longer-name\n"
+	if got.String() != want {
+		t.Errorf("got %q, want %q", got.String(), want)
+	}
+}
+
+// TestStreamInvalidFormat confirms an unknown -o value still errors, on
+// Close, with PrintFormatted's message.
+func TestStreamInvalidFormat(t *testing.T) {
+	cmd, _ := streamTestCommand("xml", "")
+	s := NewStream(cmd)
+	StreamPage(s, []string{"x"})
+	if err := s.Close(); err == nil {
+		t.Error("expected an error for -o xml")
+	}
+}
+
+// TestStreamAbortClosesJSON confirms a list whose pager fails after the
+// first page still leaves valid JSON of the items already printed.
+func TestStreamAbortClosesJSON(t *testing.T) {
+	failure := errors.New("page 2 failed")
+	for _, q := range []string{"", "[].name", "[0]"} {
+		cmd, got := streamTestCommand("json", q)
+		s := NewStream(cmd)
+		if err := StreamPage(s, streamTestPages[0]); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Abort(failure); err != failure {
+			t.Errorf("--query %q: Abort returned %v", q, err)
+		}
+		s.Abort(failure)
+
+		if q == "[0]" {
+			// Buffered for the whole list, so nothing was printed.
+			if got.Len() != 0 {
+				t.Errorf("--query %q: printed %q", q, got.String())
+			}
+			continue
+		}
+		var items []interface{}
+		if err := json.Unmarshal(got.Bytes(), &items); err != nil || len(items) != len(streamTestPages[0]) {
+			t.Errorf("--query %q: output %q is not the first page as JSON: %v", q, got.String(), err)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// argType is a JMESPath function-signature type. "array-number" and
+// "array-string" are arrays whose elements all have that type.
+type argType string
+
+const (
+	jpAny         argType = "any"
+	jpNumber      argType = "number"
+	jpString      argType = "string"
+	jpArray       argType = "array"
+	jpObject      argType = "object"
+	jpExpref      argType = "expref"
+	jpArrayNumber argType = "array-number"
+	jpArrayString argType = "array-string"
+)
+
+type function struct {
+	// args lists the accepted types of each argument. With variadic set the
+	// last entry repeats, and is required at least once.
+	args     [][]argType
+	variadic bool
+	call     func(ip *interpreter, args []interface{}) (interface{}, error)
+}
+
+// functions are the JMESPath built-ins plus the jmespath-community
+// additions: group_by, items, from_items and zip.
+var functions map[string]function
+
+func init() {
+	one := func(types ...argType) [][]argType { return [][]argType{types} }
+	functions = map[string]function{
+		"abs":         {args: one(jpNumber), call: fnAbs},
+		"avg":         {args: one(jpArrayNumber), call: fnAvg},
+		"ceil":        {args: one(jpNumber), call: fnCeil},
+		"contains":    {args: [][]argType{{jpArray, jpString}, {jpAny}}, call: fnContains},
+		"ends_with":   {args: [][]argType{{jpString}, {jpString}}, call: fnEndsWith},
+		"floor":       {args: one(jpNumber), call: fnFloor},
+		"from_items":  {args: one(jpArray), call: fnFromItems},
+		"group_by":    {args: [][]argType{{jpArray}, {jpExpref}}, call: fnGroupBy},
+		"items":       {args: one(jpObject), call: fnItems},
+		"join":        {args: [][]argType{{jpString}, {jpArrayString}}, call: fnJoin},
+		"keys":        {args: one(jpObject), call: fnKeys},
+		"length":      {args: one(jpString, jpArray, jpObject), call: fnLength},
+		"map":         {args: [][]argType{{jpExpref}, {jpArray}}, call: fnMap},
+		"max":         {args: one(jpArrayNumber, jpArrayString), call: fnMax},
+		"max_by":      {args: [][]argType{{jpArray}, {jpExpref}}, call: fnMaxBy},
+		"merge":       {args: one(jpObject), variadic: true, call: fnMerge},
+		"min":         {args: one(jpArrayNumber, jpArrayString), call: fnMin},
+		"min_by":      {args: [][]argType{{jpArray}, {jpExpref}}, call: fnMinBy},
+		"not_null":    {args: one(jpAny), variadic: true, call: fnNotNull},
+		"reverse":     {args: one(jpArray, jpString), call: fnReverse},
+		"sort":        {args: one(jpArrayNumber, jpArrayString), call: fnSort},
+		"sort_by":     {args: [][]argType{{jpArray}, {jpExpref}}, call: fnSortBy},
+		"starts_with": {args: [][]argType{{jpString}, {jpString}}, call: fnStartsWith},
+		"sum":         {args: one(jpArrayNumber), call: fnSum},
+		"to_array":    {args: one(jpAny), call: fnToArray},
+		"to_number":   {args: one(jpAny), call: fnToNumber},
+		"to_string":   {args: one(jpAny), call: fnToString},
+		"type":        {args: one(jpAny), call: fnType},
+		"values":      {args: one(jpObject), call: fnValues},
+		"zip":         {args: one(jpArray), variadic: true, call: fnZip},
+	}
+}
+
+func (ip *interpreter) call(name string, args []interface{}) (interface{}, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s()", name)
+	}
+	if err := checkArgs(name, fn, args); err != nil {
+		return nil, err
+	}
+	return fn.call(ip, args)
+}
+
+func checkArgs(name string, fn function, args []interface{}) error {
+	switch {
+	case fn.variadic && len(args) < len(fn.args):
+		return fmt.Errorf("%s() takes at least %d argument(s) but received %d", name, len(fn.args), len(args))
+	case !fn.variadic && len(args) != len(fn.args):
+		return fmt.Errorf("%s() takes %d argument(s) but received %d", name, len(fn.args), len(args))
+	}
+	for i, arg := range args {
+		types := fn.args[min(i, len(fn.args)-1)]
+		if !matchesAny(arg, types) {
+			names := make([]string, len(types))
+			for j, t := range types {
+				names[j] = string(t)
+			}
+			return fmt.Errorf("invalid type for argument %d of %s(): expected one of [%s], received %q",
+				i+1, name, strings.Join(names, ", "), jsonType(arg))
+		}
+	}
+	return nil
+}
+
+func matchesAny(v interface{}, types []argType) bool {
+	for _, t := range types {
+		if matchesType(v, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(v interface{}, t argType) bool {
+	switch t {
+	case jpAny:
+		return jsonType(v) != "expref"
+	case jpArrayNumber, jpArrayString:
+		list, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, el := range list {
+			if (t == jpArrayNumber && !isNumber(el)) || (t == jpArrayString && jsonType(el) != "string") {
+				return false
+			}
+		}
+		return true
+	}
+	return jsonType(v) == string(t)
+}
+
+func fnAbs(_ *interpreter, args []interface{}) (interface{}, error) {
+	if i, ok := integerValue(args[0]); ok {
+		return intNumber(i.Abs(i)), nil
+	}
+	return floatNumber(math.Abs(floatValue(args[0]))), nil
+}
+
+// fnAvg is always a float, as in Python: avg([1, 2, 3]) is 2.0.
+func fnAvg(_ *interpreter, args []interface{}) (interface{}, error) {
+	list := args[0].([]interface{})
+	if len(list) == 0 {
+		return nil, nil
+	}
+	if sum, ok := integerSum(list); ok {
+		f, _ := new(big.Rat).SetFrac(sum, big.NewInt(int64(len(list)))).Float64()
+		return floatNumber(f), nil
+	}
+	return floatNumber(floatSum(list) / float64(len(list))), nil
+}
+
+// fnCeil and fnFloor return an integer, as Python's math.ceil does.
+func fnCeil(_ *interpreter, args []interface{}) (interface{}, error) {
+	return roundToInteger(args[0], math.Ceil), nil
+}
+
+func fnFloor(_ *interpreter, args []interface{}) (interface{}, error) {
+	return roundToInteger(args[0], math.Floor), nil
+}
+
+func roundToInteger(v interface{}, round func(float64) float64) interface{} {
+	if i, ok := integerValue(v); ok {
+		return intNumber(i)
+	}
+	f := round(floatValue(v))
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil
+	}
+	i, _ := big.NewFloat(f).Int(nil)
+	return intNumber(i)
+}
+
+func fnContains(_ *interpreter, args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		sub, ok := args[1].(string)
+		return ok && strings.Contains(s, sub), nil
+	}
+	for _, el := range args[0].([]interface{}) {
+		if valuesEqual(el, args[1]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func fnEndsWith(_ *interpreter, args []interface{}) (interface{}, error) {
+	return strings.HasSuffix(args[0].(string), args[1].(string)), nil
+}
+
+func fnStartsWith(_ *interpreter, args []interface{}) (interface{}, error) {
+	return strings.HasPrefix(args[0].(string), args[1].(string)), nil
+}
+
+// fnFromItems builds an object from [key, value] pairs; a later pair wins.
+func fnFromItems(_ *interpreter, args []interface{}) (interface{}, error) {
+	out := map[string]interface{}{}
+	for _, el := range args[0].([]interface{}) {
+		pair, ok := el.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("invalid type for from_items(): expected [key, value] pairs, received %q", jsonType(el))
+		}
+		key, ok := pair[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid type for from_items(): keys must be strings, received %q", jsonType(pair[0]))
+		}
+		out[key] = pair[1]
+	}
+	return out, nil
+}
+
+// fnGroupBy groups elements by the string expr yields for them. Elements for
+// which it yields null are left out.
+func fnGroupBy(ip *interpreter, args []interface{}) (interface{}, error) {
+	out := map[string]interface{}{}
+	ref := args[1].(expRef)
+	for _, el := range args[0].([]interface{}) {
+		key, err := ip.evalRef(ref, el)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		k, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid type for group_by(): expression must return a string, received %q", jsonType(key))
+		}
+		group, _ := out[k].([]interface{})
+		out[k] = append(group, el)
+	}
+	return out, nil
+}
+
+func fnItems(_ *interpreter, args []interface{}) (interface{}, error) {
+	m := args[0].(map[string]interface{})
+	out := make([]interface{}, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		out = append(out, []interface{}{k, m[k]})
+	}
+	return out, nil
+}
+
+func fnJoin(_ *interpreter, args []interface{}) (interface{}, error) {
+	list := args[1].([]interface{})
+	parts := make([]string, len(list))
+	for i, el := range list {
+		parts[i] = el.(string)
+	}
+	return strings.Join(parts, args[0].(string)), nil
+}
+
+func fnKeys(_ *interpreter, args []interface{}) (interface{}, error) {
+	keys := sortedKeys(args[0].(map[string]interface{}))
+	out := make([]interface{}, len(keys))
+	for i, k := range keys {
+		out[i] = k
+	}
+	return out, nil
+}
+
+func fnValues(_ *interpreter, args []interface{}) (interface{}, error) {
+	m := args[0].(map[string]interface{})
+	out := make([]interface{}, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		out = append(out, m[k])
+	}
+	return out, nil
+}
+
+func fnLength(_ *interpreter, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		return json.Number(strconv.Itoa(utf8.RuneCountInString(v))), nil
+	case []interface{}:
+		return json.Number(strconv.Itoa(len(v))), nil
+	default:
+		return json.Number(strconv.Itoa(len(v.(map[string]interface{})))), nil
+	}
+}
+
+// fnMap keeps null results, unlike a projection.
+func fnMap(ip *interpreter, args []interface{}) (interface{}, error) {
+	list := args[1].([]interface{})
+	out := make([]interface{}, len(list))
+	for i, el := range list {
+		v, err := ip.evalRef(args[0].(expRef), el)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func fnMax(_ *interpreter, args []interface{}) (interface{}, error) {
+	return extreme(args[0].([]interface{}), 1), nil
+}
+
+func fnMin(_ *interpreter, args []interface{}) (interface{}, error) {
+	return extreme(args[0].([]interface{}), -1), nil
+}
+
+// extreme returns the largest (sign 1) or smallest (sign -1) element itself,
+// so its literal is printed unchanged.
+func extreme(list []interface{}, sign int) interface{} {
+	var best interface{}
+	for i, el := range list {
+		if i == 0 || compareSortable(el, best)*sign > 0 {
+			best = el
+		}
+	}
+	return best
+}
+
+func fnMaxBy(ip *interpreter, args []interface{}) (interface{}, error) {
+	return extremeBy(ip, "max_by", args, 1)
+}
+
+func fnMinBy(ip *interpreter, args []interface{}) (interface{}, error) {
+	return extremeBy(ip, "min_by", args, -1)
+}
+
+func extremeBy(ip *interpreter, name string, args []interface{}, sign int) (interface{}, error) {
+	list := args[0].([]interface{})
+	keys, err := sortKeys(ip, name, list, args[1].(expRef))
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	best := 0
+	for i := 1; i < len(list); i++ {
+		if compareSortable(keys[i], keys[best])*sign > 0 {
+			best = i
+		}
+	}
+	return list[best], nil
+}
+
+func fnMerge(_ *interpreter, args []interface{}) (interface{}, error) {
+	out := map[string]interface{}{}
+	for _, arg := range args {
+		for k, v := range arg.(map[string]interface{}) {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+func fnNotNull(_ *interpreter, args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnReverse(_ *interpreter, args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	}
+	list := args[0].([]interface{})
+	out := make([]interface{}, len(list))
+	for i, el := range list {
+		out[len(list)-1-i] = el
+	}
+	return out, nil
+}
+
+func fnSort(_ *interpreter, args []interface{}) (interface{}, error) {
+	out := append([]interface{}{}, args[0].([]interface{})...)
+	sort.SliceStable(out, func(i, j int) bool { return compareSortable(out[i], out[j]) < 0 })
+	return out, nil
+}
+
+func fnSortBy(ip *interpreter, args []interface{}) (interface{}, error) {
+	list := args[0].([]interface{})
+	keys, err := sortKeys(ip, "sort_by", list, args[1].(expRef))
+	if err != nil {
+		return nil, err
+	}
+	order := make([]int, len(list))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return compareSortable(keys[order[a]], keys[order[b]]) < 0 })
+	out := make([]interface{}, len(list))
+	for i, j := range order {
+		out[i] = list[j]
+	}
+	return out, nil
+}
+
+// sortKeys evaluates ref for each element; the keys must be all numbers or
+// all strings.
+func sortKeys(ip *interpreter, name string, list []interface{}, ref expRef) ([]interface{}, error) {
+	keys := make([]interface{}, len(list))
+	for i, el := range list {
+		k, err := ip.evalRef(ref, el)
+		if err != nil {
+			return nil, err
+		}
+		if !isNumber(k) && jsonType(k) != "string" {
+			return nil, fmt.Errorf("invalid type for %s(): expression must return a number or a string, received %q", name, jsonType(k))
+		}
+		if i > 0 && isNumber(k) != isNumber(keys[0]) {
+			return nil, fmt.Errorf("invalid type for %s(): expression returned both numbers and strings", name)
+		}
+		keys[i] = k
+	}
+	return keys, nil
+}
+
+// compareSortable orders two numbers or two strings.
+func compareSortable(a, b interface{}) int {
+	if isNumber(a) && isNumber(b) {
+		return compareNumbers(a, b)
+	}
+	return strings.Compare(a.(string), b.(string))
+}
+
+func fnSum(_ *interpreter, args []interface{}) (interface{}, error) {
+	list := args[0].([]interface{})
+	if sum, ok := integerSum(list); ok {
+		return intNumber(sum), nil
+	}
+	return floatNumber(floatSum(list)), nil
+}
+
+// integerSum sums list exactly if every element is an integer.
+func integerSum(list []interface{}) (*big.Int, bool) {
+	sum := new(big.Int)
+	for _, el := range list {
+		i, ok := integerValue(el)
+		if !ok {
+			return nil, false
+		}
+		sum.Add(sum, i)
+	}
+	return sum, true
+}
+
+func floatSum(list []interface{}) float64 {
+	var sum float64
+	for _, el := range list {
+		sum += floatValue(el)
+	}
+	return sum
+}
+
+func fnToArray(_ *interpreter, args []interface{}) (interface{}, error) {
+	if list, ok := args[0].([]interface{}); ok {
+		return list, nil
+	}
+	return []interface{}{args[0]}, nil
+}
+
+// fnToNumber parses a string the way jmespath.py does, as an int if it can
+// and a float otherwise, so to_number('3') is 3 and to_number('3.0') is 3.0.
+func fnToNumber(_ *interpreter, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		if i, ok := new(big.Int).SetString(s, 10); ok {
+			return intNumber(i), nil
+		}
+		f, ok := parsePythonFloat(s)
+		if !ok {
+			return nil, nil
+		}
+		return floatNumber(f), nil
+	default:
+		if isNumber(v) {
+			return v, nil
+		}
+		return nil, nil
+	}
+}
+
+// pythonFloat matches the finite literals Python's float() accepts.
+var pythonFloat = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+func parsePythonFloat(s string) (float64, bool) {
+	if !pythonFloat.MatchString(s) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+// fnToString returns a string unchanged and anything else as compact JSON.
+func fnToString(_ *interpreter, args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		return s, nil
+	}
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(args[0]); err != nil {
+		return nil, err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func fnType(_ *interpreter, args []interface{}) (interface{}, error) {
+	return jsonType(args[0]), nil
+}
+
+// fnZip pairs up the arrays' elements, stopping at the shortest array.
+func fnZip(_ *interpreter, args []interface{}) (interface{}, error) {
+	n := -1
+	for _, arg := range args {
+		if l := len(arg.([]interface{})); n < 0 || l < n {
+			n = l
+		}
+	}
+	out := make([]interface{}, n)
+	for i := range out {
+		row := make([]interface{}, len(args))
+		for j, arg := range args {
+			row[j] = arg.([]interface{})[i]
+		}
+		out[i] = row
+	}
+	return out, nil
+}
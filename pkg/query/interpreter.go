@@ -0,0 +1,476 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// scope holds the variables a let expression bound, chained to the
+// enclosing let's.
+type scope struct {
+	vars   map[string]interface{}
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (interface{}, bool) {
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// expRef is the value of an &expression argument: the expression, with the
+// variables in scope where it was written.
+type expRef struct {
+	node  node
+	scope *scope
+}
+
+type interpreter struct {
+	root interface{}
+}
+
+// search evaluates n against data, which must be a JSON tree as normalize
+// returns it.
+func search(n node, data interface{}) (interface{}, error) {
+	ip := &interpreter{root: data}
+	return ip.eval(n, data, nil)
+}
+
+func (ip *interpreter) eval(n node, value interface{}, sc *scope) (interface{}, error) {
+	switch n.typ {
+	case nodeField:
+		if m, ok := value.(map[string]interface{}); ok {
+			return m[n.value.(string)], nil
+		}
+		return nil, nil
+	case nodeCurrent, nodeIdentity:
+		return value, nil
+	case nodeRoot:
+		return ip.root, nil
+	case nodeVariable:
+		v, ok := sc.lookup(n.value.(string))
+		if !ok {
+			return nil, fmt.Errorf("undefined variable $%s", n.value)
+		}
+		return v, nil
+	case nodeLiteral:
+		return n.value, nil
+	case nodeIndex:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		i := n.value.(int)
+		if i < 0 {
+			i += len(list)
+		}
+		if i < 0 || i >= len(list) {
+			return nil, nil
+		}
+		return list[i], nil
+	case nodeSlice:
+		return sliceValue(value, n.value.([3]*int))
+	case nodeIndexExpression, nodeSubexpression, nodePipe:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		return ip.eval(n.children[1], left, sc)
+	case nodeProjection:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		// A slice of a string is a string, not something to project over.
+		if s, ok := left.(string); ok && isSliceOf(n.children[0]) {
+			return ip.eval(n.children[1], s, sc)
+		}
+		list, ok := left.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		return ip.project(n.children[1], list, sc)
+	case nodeValueProjection:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := left.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		values := make([]interface{}, 0, len(m))
+		for _, k := range sortedKeys(m) {
+			values = append(values, m[k])
+		}
+		return ip.project(n.children[1], values, sc)
+	case nodeFilterProjection:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := left.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		var matched []interface{}
+		for _, el := range list {
+			cond, err := ip.eval(n.children[2], el, sc)
+			if err != nil {
+				return nil, err
+			}
+			if isTrue(cond) {
+				matched = append(matched, el)
+			}
+		}
+		return ip.project(n.children[1], matched, sc)
+	case nodeFlatten:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := left.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		flat := []interface{}{}
+		for _, el := range list {
+			if inner, ok := el.([]interface{}); ok {
+				flat = append(flat, inner...)
+			} else {
+				flat = append(flat, el)
+			}
+		}
+		return flat, nil
+	case nodeMultiSelectList:
+		if value == nil {
+			return nil, nil
+		}
+		out := make([]interface{}, len(n.children))
+		for i, child := range n.children {
+			v, err := ip.eval(child, value, sc)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case nodeMultiSelectHash:
+		if value == nil {
+			return nil, nil
+		}
+		out := make(map[string]interface{}, len(n.children))
+		for _, kv := range n.children {
+			v, err := ip.eval(kv.children[0], value, sc)
+			if err != nil {
+				return nil, err
+			}
+			out[kv.value.(string)] = v
+		}
+		return out, nil
+	case nodeComparator:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ip.eval(n.children[1], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		return compareValues(n.value.(tokenType), left, right), nil
+	case nodeOr:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil || isTrue(left) {
+			return left, err
+		}
+		return ip.eval(n.children[1], value, sc)
+	case nodeAnd:
+		left, err := ip.eval(n.children[0], value, sc)
+		if err != nil || !isTrue(left) {
+			return left, err
+		}
+		return ip.eval(n.children[1], value, sc)
+	case nodeNot:
+		v, err := ip.eval(n.children[0], value, sc)
+		if err != nil {
+			return nil, err
+		}
+		return !isTrue(v), nil
+	case nodeExpRef:
+		return expRef{node: n.children[0], scope: sc}, nil
+	case nodeFunction:
+		args := make([]interface{}, len(n.children))
+		for i, child := range n.children {
+			v, err := ip.eval(child, value, sc)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		return ip.call(n.value.(string), args)
+	case nodeLet:
+		bindings := n.children[:len(n.children)-1]
+		inner := &scope{vars: make(map[string]interface{}, len(bindings)), parent: sc}
+		for _, b := range bindings {
+			v, err := ip.eval(b.children[0], value, sc)
+			if err != nil {
+				return nil, err
+			}
+			inner.vars[b.value.(string)] = v
+		}
+		return ip.eval(n.children[len(n.children)-1], value, inner)
+	}
+	return nil, fmt.Errorf("unknown expression node %d", n.typ)
+}
+
+// project applies n to each element, dropping null results.
+func (ip *interpreter) project(n node, list []interface{}, sc *scope) (interface{}, error) {
+	out := []interface{}{}
+	for _, el := range list {
+		v, err := ip.eval(n, el, sc)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// evalRef evaluates an &expression against value.
+func (ip *interpreter) evalRef(ref expRef, value interface{}) (interface{}, error) {
+	return ip.eval(ref.node, value, ref.scope)
+}
+
+func isSliceOf(n node) bool {
+	return n.typ == nodeIndexExpression && n.children[1].typ == nodeSlice
+}
+
+// sliceValue applies a [start:stop:step] slice to a list, or to a string's
+// code points.
+func sliceValue(value interface{}, parts [3]*int) (interface{}, error) {
+	var length int
+	var runes []rune
+	switch v := value.(type) {
+	case []interface{}:
+		length = len(v)
+	case string:
+		runes = []rune(v)
+		length = len(runes)
+	default:
+		return nil, nil
+	}
+
+	step := 1
+	if parts[2] != nil {
+		step = *parts[2]
+	}
+	if step == 0 {
+		return nil, fmt.Errorf("slice step cannot be 0")
+	}
+	start, stop := sliceBound(parts[0], length, step, true), sliceBound(parts[1], length, step, false)
+
+	var indices []int
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		indices = append(indices, i)
+	}
+	if list, ok := value.([]interface{}); ok {
+		out := make([]interface{}, len(indices))
+		for j, i := range indices {
+			out[j] = list[i]
+		}
+		return out, nil
+	}
+	out := make([]rune, len(indices))
+	for j, i := range indices {
+		out[j] = runes[i]
+	}
+	return string(out), nil
+}
+
+// sliceBound resolves a slice start or stop the way Python does.
+func sliceBound(p *int, length, step int, isStart bool) int {
+	if p == nil {
+		switch {
+		case step > 0 && isStart:
+			return 0
+		case step > 0:
+			return length
+		case isStart:
+			return length - 1
+		default:
+			return -1
+		}
+	}
+	v := *p
+	if v < 0 {
+		v += length
+		if v < 0 {
+			if step < 0 {
+				return -1
+			}
+			return 0
+		}
+	} else if v >= length {
+		if step < 0 {
+			return length - 1
+		}
+		return length
+	}
+	return v
+}
+
+// isTrue implements JMESPath truthiness: false, null and empty strings,
+// lists and objects are false; everything else, including 0, is true.
+func isTrue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []interface{}:
+		return len(t) > 0
+	case map[string]interface{}:
+		return len(t) > 0
+	}
+	return true
+}
+
+// compareValues implements the comparators. == and != compare any values
+// deeply, with numbers compared by value; the ordering comparators apply to
+// two numbers or two strings and are null otherwise.
+func compareValues(op tokenType, left, right interface{}) interface{} {
+	switch op {
+	case tEQ:
+		return valuesEqual(left, right)
+	case tNE:
+		return !valuesEqual(left, right)
+	}
+	var c int
+	switch {
+	case isNumber(left) && isNumber(right):
+		c = compareNumbers(left, right)
+	default:
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if !lok || !rok {
+			return nil
+		}
+		c = strings.Compare(ls, rs)
+	}
+	switch op {
+	case tLT:
+		return c < 0
+	case tLTE:
+		return c <= 0
+	case tGT:
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+func valuesEqual(a, b interface{}) bool {
+	if isNumber(a) || isNumber(b) {
+		return isNumber(a) && isNumber(b) && compareNumbers(a, b) == 0
+	}
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !valuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			w, ok := bv[k]
+			if !ok || !valuesEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// sortedKeys returns m's keys in order. Go maps have no insertion order, so
+// values(), keys(), items() and .* list an object's members by key.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonType names v's JMESPath type.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case expRef:
+		return "expref"
+	}
+	if isNumber(v) {
+		return "number"
+	}
+	return "unknown"
+}
+
+// isJSONTree reports whether v is made only of the types encoding/json
+// decodes into, so it can be searched without normalizing.
+func isJSONTree(v interface{}) bool {
+	switch t := v.(type) {
+	case nil, bool, string, json.Number, float64:
+		return true
+	case []interface{}:
+		for _, el := range t {
+			if !isJSONTree(el) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		for _, el := range t {
+			if !isJSONTree(el) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func decodeNumbers(t *testing.T, doc string) interface{} {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("decode %s: %v", doc, err)
+	}
+	return v
+}
+
+// TestSearch runs queries against the JMESPath specification's examples
+// and the jmespath-community additions, comparing the compact JSON of each
+// result so number literals are compared exactly.
+func TestSearch(t *testing.T) {
+	const doc = `{
+		"people": [
+			{"name": "a", "age": 30, "team": "red", "tags": ["x", "y"]},
+			{"name": "b", "age": 25, "team": "blue", "tags": ["y"]},
+			{"name": "c", "age": 35, "team": "red", "tags": []},
+			{"name": "d", "team": null}
+		],
+		"nested": [[1, 2], [3], 4, [[5]]],
+		"obj": {"b": 2, "a": 1, "c": 3},
+		"str": "abcdef",
+		"uni": "héllo",
+		"big": 9223372036854775807,
+		"f": 2.50,
+		"neg": -3,
+		"empty": [],
+		"pairs": [["k1", "v1"], ["k2", 2]]
+	}`
+	data := decodeNumbers(t, doc)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		// Navigation.
+		{"people[0].name", `"a"`},
+		{"people[-1].name", `"d"`},
+		{"people[10]", `null`},
+		{"missing.field", `null`},
+		{`"str"`, `"abcdef"`},
+		{"@.str", `"abcdef"`},
+
+		// Projections and slices.
+		{"people[*].name", `["a","b","c","d"]`},
+		{"people[].age", `[30,25,35]`},
+		{"people[1:3].name", `["b","c"]`},
+		{"people[::-1].name", `["d","c","b","a"]`},
+		{"people[::2].name", `["a","c"]`},
+		{"people[-2:].name", `["c","d"]`},
+		{"obj.*", `[1,2,3]`},
+		{"nested[]", `[1,2,3,4,[5]]`},
+		{"nested[][]", `[1,2,3,4,5]`},
+		{"people[].tags[]", `["x","y","y"]`},
+		{"people[*].tags[0]", `["x","y"]`},
+
+		// Filters and comparators.
+		{"people[?age > `28`].name", `["a","c"]`},
+		{"people[?age >= `30` && team == 'red'].name", `["a","c"]`},
+		{"people[?team == 'blue' || age == `35`].name", `["b","c"]`},
+		{"people[?!age].name", `["d"]`},
+		{"people[?team != 'red'].name", `["b","d"]`},
+		{"people[?name < 'c'].name", `["a","b"]`},
+		{"people[?age == `30.0`].name", `["a"]`},
+		{"people[?tags].name", `["a","b"]`},
+		{"people[?age > 'x'].name", `[]`},
+
+		// Multiselect and pipes.
+		{"people[0].[name, age]", `["a",30]`},
+		{"people[0].{N: name, A: age}", `{"A":30,"N":"a"}`},
+		{"people[].name | [0]", `"a"`},
+		{"missing.[a, b]", `null`},
+		{"missing.{a: a}", `null`},
+
+		// Literals.
+		{"`[1, 2]`", `[1,2]`},
+		{"'it\\'s'", `"it's"`},
+		{"`\"x\"`", `"x"`},
+		{"`foo`", `"foo"`},
+
+		// Functions.
+		{"length(people)", `4`},
+		{"length(uni)", `5`},
+		{"keys(obj)", `["a","b","c"]`},
+		{"values(obj)", `[1,2,3]`},
+		{"sort_by(people[?age], &age)[].name", `["b","a","c"]`},
+		{"max_by(people[?age], &age).name", `"c"`},
+		{"min_by(people[?age], &age).name", `"b"`},
+		{"sort(people[].name)", `["a","b","c","d"]`},
+		{"reverse(str)", `"fedcba"`},
+		{"join(', ', people[].name)", `"a, b, c, d"`},
+		{"contains(people[].team, 'blue')", `true`},
+		{"contains(str, 'cd')", `true`},
+		{"starts_with(str, 'ab')", `true`},
+		{"ends_with(str, 'ef')", `true`},
+		{"map(&age, people)", `[30,25,35,null]`},
+		{"merge(obj, `{\"a\": 9}`)", `{"a":9,"b":2,"c":3}`},
+		{"not_null(missing, people[0].name)", `"a"`},
+		{"to_array(str)", `["abcdef"]`},
+		{"to_string(obj)", `"{\"a\":1,\"b\":2,\"c\":3}"`},
+		{"type(f)", `"number"`},
+		{"type(people)", `"array"`},
+
+		// Exact numbers: literals pass through untouched and integer
+		// arithmetic is arbitrary precision.
+		{"big", `9223372036854775807`},
+		{"f", `2.50`},
+		{"sum([big, `1`])", `9223372036854775808`},
+		{"sum(people[].age)", `90`},
+		{"sum([f, `1`])", `3.5`},
+		{"avg(people[].age)", `30.0`},
+		{"avg(empty)", `null`},
+		{"sum(empty)", `0`},
+		{"max(people[].age)", `35`},
+		{"abs(neg)", `3`},
+		{"ceil(f)", `3`},
+		{"floor(f)", `2`},
+		{"to_number('42')", `42`},
+		{"to_number('4.0')", `4.0`},
+		{"to_number('x')", `null`},
+		{"big == `9223372036854775806`", `false`},
+		{"big > `9223372036854775806`", `true`},
+
+		// let and variables.
+		{"let $min = `30` in people[?age >= $min].name", `["a","c"]`},
+		{"let $a = obj.a, $b = obj.b in [$a, $b]", `[1,2]`},
+		{"let $x = `1` in let $x = `2` in $x", `2`},
+		{"people[?age > `30`] | let $t = [0].team in $t", `"red"`},
+		{"people[].[name, $.str] | [0]", `["a","abcdef"]`},
+
+		// group_by, items, from_items, zip.
+		{"group_by(people, &team)", `{"blue":[{"age":25,"name":"b","tags":["y"],"team":"blue"}],"red":[{"age":30,"name":"a","tags":["x","y"],"team":"red"},{"age":35,"name":"c","tags":[],"team":"red"}]}`},
+		{"keys(group_by(people, &team))", `["blue","red"]`},
+		{"items(obj)", `[["a",1],["b",2],["c",3]]`},
+		{"from_items(pairs)", `{"k1":"v1","k2":2}`},
+		{"from_items(items(obj))", `{"a":1,"b":2,"c":3}`},
+		{"zip(people[].name, people[].age)", `[["a",30],["b",25],["c",35]]`},
+		{"zip(people[].name)", `[["a"],["b"],["c"],["d"]]`},
+
+		// String slicing.
+		{"str[1:3]", `"bc"`},
+		{"str[::-1]", `"fedcba"`},
+		{"str[-2:]", `"ef"`},
+		{"uni[1:3]", `"él"`},
+		{"people[0].name[0:1]", `"a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := ApplyJMESPath(data, tt.query)
+			if err != nil {
+				t.Fatalf("ApplyJMESPath: %v", err)
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	data := decodeNumbers(t, `{"a": [1, "x"], "s": "str", "o": {}}`)
+	tests := []struct {
+		query  string
+		syntax bool
+	}{
+		{"a[", true},
+		{"a.", true},
+		{"{a}", true},
+		{"a ==", true},
+		{"foo(a b)", true},
+		{`"f"(a)`, true},
+		{"let $x = a", true},
+		{"a[?b", true},
+		{"'unterminated", true},
+		{"a ~ b", true},
+		{"sum(a)", false},
+		{"length(`1`)", false},
+		{"nope(a)", false},
+		{"abs()", false},
+		{"a[::0]", false},
+		{"$undefined", false},
+		{"sort_by(a, &@)", false},
+		{"group_by(a, &@)", false},
+		{"from_items(`[[1, 2]]`)", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ApplyJMESPath(data, tt.query)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var syntaxErr SyntaxError
+			if errors.As(err, &syntaxErr) != tt.syntax {
+				t.Errorf("SyntaxError = %v, want %v: %v", !tt.syntax, tt.syntax, err)
+			}
+		})
+	}
+}
+
+// TestApplyJMESPathNormalizesGoValues covers callers that pass rows built
+// in Go rather than decoded from JSON.
+func TestApplyJMESPathNormalizesGoValues(t *testing.T) {
+	row := map[string]any{"ID": 42, "Fields": map[string]any{"State": "Active"}}
+	got, err := ApplyJMESPath(row, "Fields.State")
+	if err != nil || got != "Active" {
+		t.Errorf("got %v, %v", got, err)
+	}
+	got, err = ApplyJMESPath(row, "ID")
+	if err != nil || got != json.Number("42") {
+		t.Errorf("got %#v, %v", got, err)
+	}
+
+	// A float64 tree, as plain json.Unmarshal produces, still works.
+	var plain interface{}
+	json.Unmarshal([]byte(`{"a": [1, 2, 3.5]}`), &plain)
+	got, err = ApplyJMESPath(plain, "sum(a)")
+	if err != nil || got != json.Number("6.5") {
+		t.Errorf("got %#v, %v", got, err)
+	}
+}
+
+func TestFloatNumberMatchesPythonRepr(t *testing.T) {
+	tests := map[float64]string{
+		2:          "2.0",
+		-0.0:       "0.0",
+		2.0 / 3:    "0.6666666666666666",
+		1e16:       "1e+16",
+		1234567.0:  "1234567.0",
+		1.5e-5:     "1.5e-05",
+		0.0001:     "0.0001",
+		123.456e10: "1234560000000.0",
+	}
+	for f, want := range tests {
+		if got := floatNumber(f); got != json.Number(want) {
+			t.Errorf("floatNumber(%v) = %v, want %s", f, got, want)
+		}
+	}
+}
@@ -4,10 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"reflect"
 	"sync"
-
-	"github.com/jmespath/go-jmespath"
 )
 
 // marshalIndentNoEscape behaves like json.MarshalIndent(v, "", "  ") except
@@ -31,13 +28,23 @@ func marshalIndentNoEscape(v interface{}) ([]byte, error) {
 	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
 }
 
-// ApplyJMESPath applies a JMESPath query to JSON data
+// ApplyJMESPath applies a JMESPath query to JSON data. data is normally a
+// tree decoded with json.Decoder.UseNumber, whose numbers the query keeps
+// exact; any other Go value is first normalized through encoding/json.
 func ApplyJMESPath(data interface{}, queryStr string) (interface{}, error) {
 	if queryStr == "" {
 		return data, nil
 	}
 
-	result, err := jmespath.Search(queryStr, data)
+	n, err := compile(queryStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JMESPath query: %w", err)
+	}
+	data, err = normalize(data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := search(n, data)
 	if err != nil {
 		return nil, fmt.Errorf("invalid JMESPath query: %w", err)
 	}
@@ -51,14 +58,10 @@ func ApplyJMESPathToJSON(jsonData []byte, queryStr string) ([]byte, error) {
 		return jsonData, nil
 	}
 
-	// Parse JSON into interface{}, keeping number literals verbatim when the
-	// query is one go-jmespath can evaluate over json.Number (see
-	// PreservesNumberLiterals).
+	// Parse JSON into interface{}, keeping number literals verbatim
 	var data interface{}
 	dec := json.NewDecoder(bytes.NewReader(jsonData))
-	if PreservesNumberLiterals(queryStr) {
-		dec.UseNumber()
-	}
+	dec.UseNumber()
 	if err := dec.Decode(&data); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
@@ -78,305 +81,94 @@ func ApplyJMESPathToJSON(jsonData []byte, queryStr string) ([]byte, error) {
 	return output, nil
 }
 
-// PreservesNumberLiterals reports whether queryStr can be evaluated against a
-// tree decoded with json.Decoder.UseNumber (i.e. one whose numbers are
-// json.Number, not float64) and still produce exactly the result go-jmespath
-// produces from a plain float64 decode - only with the input's number
-// literals preserved verbatim.
-//
-// # Why this predicate has to exist
-//
-// go-jmespath v0.4.0 is hardcoded to float64. Its argument type-checker
-// accepts a number only via a concrete type assertion:
-//
-//	case jpNumber:
-//		if _, ok := arg.(float64); ok {  // functions.go:353
-//
-// and every numeric builtin then asserts unconditionally (jpfAbs,
-// functions.go:405: `num := arguments[0].(float64)`). Its comparators do the
-// same inline (interpreter.go:48-55). There is no hook to change this: the
-// entire exported surface is Compile/MustCompile/Search/JMESPath.Search
-// (api.go) - no Options value, no function registry, no number type.
-//
-// Feeding json.Number to go-jmespath therefore has two distinct consequences,
-// and only the first is loud:
-//
-//   - Every numeric builtin FAILS with an error - avg, sum, max, min, ceil,
-//     floor, abs, sort, sort_by, max_by, min_by, to_number, type.
-//   - Every comparator SILENTLY returns a WRONG answer. `<`, `>`, `<=`, `>=`
-//     return nil (falsy) when either operand is not a float64
-//     (interpreter.go:49-55), and `==`/`!=` use reflect.DeepEqual
-//     (util.go:51), which never equates json.Number("80") with the float64(80)
-//     a backtick literal decodes to (parser.go:321 uses plain json.Unmarshal).
-//     So a filter on a JSON-literal number silently yields the wrong rows:
-//     "[?port > <lit>]" and "[?port == <lit>]" yield nothing at all, and
-//     "[?port != <lit>]" yields everything. contains() is affected the same
-//     way.
-//
-// So this is a strict whitelist over AST node types, in the same spirit as
-// MultiSelectHashKeyOrders: ASTFunctionExpression and ASTComparator are
-// refused anywhere in the tree, as is any node type this function has not
-// been taught about (ASTExpRef, ASTEmpty, anything a future go-jmespath
-// adds). Returning false is always safe - it just means the caller decodes to
-// float64 exactly as before.
-//
-// # Why the permitted types are safe
-//
-// Navigation and construction nodes (field, current node, identity, index,
-// index expression, slice, flatten, pipe, subexpression, projection, value
-// projection, multiselect list, multiselect hash, key/value pair) move values
-// around without ever inspecting their numeric type, so a json.Number rides
-// through untouched.
-//
-// ASTFilterProjection, ASTOrExpression, ASTAndExpression and ASTNotExpression
-// evaluate truthiness through isFalse (util.go:13-45), whose type switch
-// matches bool/[]interface{}/map[string]interface{}/string/nil and whose
-// reflect fallback matches Struct/Slice/Map/Ptr. Neither float64 nor
-// json.Number matches anything, so both fall through to `return false`
-// (truthy) identically - a bare `[?enabled]` filter behaves the same either
-// way. A filter whose condition uses a comparator or a function is rejected
-// by the whitelist walk, which descends into the condition child too.
-//
-// ASTLiteral is permitted because a backtick literal is decoded by
-// go-jmespath itself with plain json.Unmarshal (parser.go:321), so it is a
-// float64 in both worlds - permitting it changes nothing.
-func PreservesNumberLiterals(queryStr string) bool {
-	if queryStr == "" {
-		return false
+// compiled caches parsed queries: a streamed list applies the same --query
+// to every element.
+var compiled sync.Map // string -> node
+
+func compile(queryStr string) (node, error) {
+	if n, ok := compiled.Load(queryStr); ok {
+		return n.(node), nil
 	}
-	calibrateAST()
-	if !astUsable || numberSafeTypes == nil {
-		return false
+	n, err := parse(queryStr)
+	if err != nil {
+		return node{}, err
+	}
+	compiled.Store(queryStr, n)
+	return n, nil
+}
+
+// normalize returns data as a JSON tree, round-tripping it through
+// encoding/json (with UseNumber) when it holds anything else, such as a
+// struct or a map[string]any row with Go integers in it.
+func normalize(data interface{}) (interface{}, error) {
+	if isJSONTree(data) {
+		return data, nil
 	}
-	node, err := jmespath.NewParser().Parse(queryStr)
+	raw, err := json.Marshal(data)
 	if err != nil {
-		return false
+		return nil, fmt.Errorf("failed to format query input: %w", err)
 	}
-	return numberSafeNode(reflect.ValueOf(node))
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to parse query input: %w", err)
+	}
+	return v, nil
 }
 
-// numberSafeNode reports whether node and every node beneath it is in
-// numberSafeTypes. Every reflect access is guarded so an unexpected AST shape
-// answers false rather than panicking.
-func numberSafeNode(node reflect.Value) bool {
-	nodeType, ok := astNodeTypeInt(node)
-	if !ok || !numberSafeTypes[nodeType] {
+// IsElementwise reports whether queryStr, applied to a list, is a projection
+// over that list's elements - `[].x`, `[*].{a:b}`, `[?cond].x`, `[]` - so
+// applying it to each element on its own (wrapped in a one-element list) and
+// concatenating the results gives exactly what applying it to the whole list
+// gives. Anything else - an index or slice, a pipe, a function over the list
+// - needs the whole list and returns false.
+func IsElementwise(queryStr string) bool {
+	if queryStr == "" {
 		return false
 	}
-	children := node.FieldByName("children")
-	if !children.IsValid() {
-		return true
+	n, err := compile(queryStr)
+	if err != nil {
+		return false
 	}
-	if children.Kind() != reflect.Slice {
+	return elementwiseNode(n)
+}
+
+// elementwiseNode implements IsElementwise. Projections evaluate their
+// right-hand side per element and drop nulls, and a flatten concatenates per
+// element, so only the source (first child) matters: it must be the input
+// itself (`@`, or the identity a bare `[...]` implies) or, recursively,
+// another elementwise expression. A query using variables or `$` is never
+// elementwise, since those can see the whole input.
+func elementwiseNode(n node) bool {
+	if n.typ != nodeProjection && n.typ != nodeFilterProjection && n.typ != nodeFlatten {
 		return false
 	}
-	for i := 0; i < children.Len(); i++ {
-		if !numberSafeNode(children.Index(i)) {
-			return false
-		}
+	if usesRoot(n) {
+		return false
 	}
-	return true
+	source := n.children[0]
+	return source.typ == nodeIdentity || source.typ == nodeCurrent || elementwiseNode(source)
 }
 
-// astCalibration holds the go-jmespath AST node-type integers this package
-// introspects for. They are read via reflect rather than hardcoded, because
-// jmespath.ASTNode's fields (nodeType, value, children) are unexported and
-// astNodeType.String() cannot be called through reflect on a read-only
-// field.
-var (
-	astOnce   sync.Once
-	astUsable bool
-
-	// multiSelectHashType/keyValPairType/comparatorType/filterProjectionType/
-	// literalType get individual handling in collectHashKeys (see there for
-	// why each is special-cased rather than a plain whitelist entry).
-	multiSelectHashType  int64
-	keyValPairType       int64
-	comparatorType       int64
-	filterProjectionType int64
-	literalType          int64
-
-	// pipeType/subexpressionType are the two "left then right" node types.
-	// They are NOT generic flow types: their left child is only in output
-	// position when the right child selects hash-built ELEMENTS (an index,
-	// a slice, a projection) rather than reaching INSIDE a value. See
-	// rhsSelectsIntoValue.
-	pipeType          int64
-	subexpressionType int64
-
-	// projectionType/valueProjectionType are (source, applied-to-each-element)
-	// pairs and get the same treatment as pipe/subexpression: the source is
-	// only in output position when what is applied to each element does not
-	// reach inside a value. valueProjection ("*") always reaches inside, so
-	// its source is never in output position.
-	projectionType int64
-
-	// fieldType/valueProjectionType are the two node types that select a
-	// value out of a map, i.e. arbitrary payload data rather than something
-	// the hash itself built.
-	fieldType           int64
-	valueProjectionType int64
-
-	// numberSafeTypes are the node types over which a query can be evaluated
-	// against a json.Number-typed tree without go-jmespath either erroring or
-	// silently computing a wrong answer. See PreservesNumberLiterals.
-	numberSafeTypes map[int64]bool
-
-	// genericFlowTypes are node types that (a) can never themselves evaluate
-	// to a map other than one forwarded unchanged from a child, and (b) pass
-	// the "is this in output position" question straight through to every
-	// child unchanged. Walking into any child of one of these with the same
-	// collect-mode as the parent is therefore safe. See collectHashKeys.
-	genericFlowTypes map[int64]bool
-)
-
-// calibrateAST determines the current go-jmespath nodeType values this
-// package relies on by parsing known expressions and reading the node types
-// they produce. If the AST no longer has the shape this function expects
-// (fields renamed/removed, unexpected kinds), it leaves astUsable false and
-// MultiSelectHashKeyOrders degrades to returning nil for every query.
-func calibrateAST() {
-	astOnce.Do(func() {
-		types := map[string]int64{}
-		get := func(name, expr string, path ...int) bool {
-			node, err := jmespath.NewParser().Parse(expr)
-			if err != nil {
-				return false
-			}
-			v := reflect.ValueOf(node)
-			for _, idx := range path {
-				children := v.FieldByName("children")
-				if !children.IsValid() || children.Kind() != reflect.Slice || idx >= children.Len() {
-					return false
-				}
-				v = children.Index(idx)
-			}
-			t, ok := astNodeTypeInt(v)
-			if !ok {
-				return false
-			}
-			types[name] = t
+// usesRoot reports whether n refers to `$` or a variable anywhere.
+func usesRoot(n node) bool {
+	if n.typ == nodeRoot || n.typ == nodeVariable {
+		return true
+	}
+	for _, child := range n.children {
+		if usesRoot(child) {
 			return true
 		}
-
-		// ASTMultiSelectHash (root) / ASTKeyValPair (its child).
-		ok := get("hash", "{a:b}")
-		ok = ok && get("keyval", "{a:b}", 0)
-		// ASTField (root).
-		ok = ok && get("field", "a")
-		// ASTCurrentNode (root).
-		ok = ok && get("current", "@")
-		// ASTIdentity: left child of the ASTValueProjection produced by "*".
-		ok = ok && get("identity", "*", 0)
-		// ASTIndexExpression (root of "a[0]") / ASTIndex (its second child).
-		ok = ok && get("indexExpr", "a[0]")
-		ok = ok && get("index", "a[0]", 1)
-		// ASTProjection (root of "a[0:1]") / ASTSlice (nested under its
-		// ASTIndexExpression child).
-		ok = ok && get("projection", "a[0:1]")
-		ok = ok && get("slice", "a[0:1]", 0, 1)
-		// ASTFilterProjection (root of "a[?b]").
-		ok = ok && get("filterProjection", "a[?b]")
-		// ASTComparator (root of "a==b").
-		ok = ok && get("comparator", "a==b")
-		// ASTPipe (root of "a|b").
-		ok = ok && get("pipe", "a|b")
-		// ASTSubexpression (root of "a.b").
-		ok = ok && get("subexpr", "a.b")
-		// ASTFlatten: first child of the ASTProjection produced by "a[]".
-		ok = ok && get("flatten", "a[]", 0)
-		// ASTValueProjection (root of "*").
-		ok = ok && get("valueProjection", "*")
-		// ASTLiteral (root of a backtick JSON literal).
-		ok = ok && get("literal", "`1`")
-		if !ok {
-			return
-		}
-
-		multiSelectHashType = types["hash"]
-		keyValPairType = types["keyval"]
-		comparatorType = types["comparator"]
-		filterProjectionType = types["filterProjection"]
-		literalType = types["literal"]
-		pipeType = types["pipe"]
-		subexpressionType = types["subexpr"]
-		projectionType = types["projection"]
-		fieldType = types["field"]
-		valueProjectionType = types["valueProjection"]
-		genericFlowTypes = map[int64]bool{
-			types["field"]:     true,
-			types["current"]:   true,
-			types["identity"]:  true,
-			types["indexExpr"]: true,
-			types["index"]:     true,
-			types["slice"]:     true,
-			types["flatten"]:   true,
-		}
-		astUsable = true
-
-		// Node types permitted by PreservesNumberLiterals. Calibrated after
-		// astUsable is set, and gated on its own bool, so a parse failure here
-		// can only disable PreservesNumberLiterals - never
-		// MultiSelectHashKeyOrders.
-		// ASTMultiSelectList (root of "[a,b]").
-		okNum := get("multiSelectList", "[a,b]")
-		// ASTOrExpression / ASTAndExpression / ASTNotExpression.
-		okNum = okNum && get("or", "a||b")
-		okNum = okNum && get("and", "a&&b")
-		okNum = okNum && get("not", "!a")
-		if !okNum {
-			return
-		}
-		numberSafeTypes = map[int64]bool{
-			types["field"]:            true,
-			types["current"]:          true,
-			types["identity"]:         true,
-			types["indexExpr"]:        true,
-			types["index"]:            true,
-			types["slice"]:            true,
-			types["flatten"]:          true,
-			types["pipe"]:             true,
-			types["subexpr"]:          true,
-			types["projection"]:       true,
-			types["valueProjection"]:  true,
-			types["filterProjection"]: true,
-			types["hash"]:             true,
-			types["keyval"]:           true,
-			types["literal"]:          true,
-			types["multiSelectList"]:  true,
-			types["or"]:               true,
-			types["and"]:              true,
-			types["not"]:              true,
-		}
-	})
-}
-
-// astNodeTypeInt reads the unexported nodeType field of a reflect.Value
-// wrapping a jmespath.ASTNode, returning false if the field is missing or
-// not an integer kind.
-func astNodeTypeInt(node reflect.Value) (int64, bool) {
-	if !node.IsValid() || node.Kind() != reflect.Struct {
-		return 0, false
-	}
-	nt := node.FieldByName("nodeType")
-	if !nt.IsValid() {
-		return 0, false
-	}
-	switch nt.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return nt.Int(), true
-	default:
-		return 0, false
 	}
+	return false
 }
 
 // MultiSelectHashKeyOrders returns the declared key order of the query's
 // multiselect-hash ({...}), if and only if the query is provably safe to
 // recover an order for. It returns nil for: an empty or unparseable query; a
 // query with no multiselect-hash; a query with more than one
-// multiselect-hash anywhere in its tree (nested included); a query the
-// go-jmespath AST no longer has the shape this function introspects for; and
-// — the bulk of the guarantee — a query containing any AST node type this
+// multiselect-hash anywhere in its tree (nested included); and — the bulk of the guarantee — a query containing any AST node type this
 // function has not individually verified safe, in any position, or a
 // multiselect-hash reachable only through a position this function has
 // verified UNSAFE (see below). On success it returns a single-element
@@ -398,7 +190,7 @@ func astNodeTypeInt(node reflect.Value) (int64, bool) {
 // growing. So instead: every node type below is individually permitted only
 // after checking it cannot produce a map other than one the hash itself
 // constructs (or forwards unchanged). Anything else is refused, including
-// every ASTFunctionExpression (`not_null`, `merge`, `sort_by`, `to_array`,
+// every function call (`not_null`, `merge`, `sort_by`, `to_array`,
 // ...) — this deliberately gives up recovering order for a query like
 // `sort_by(@,&n)[].{...}`, which is structurally recoverable but not proven
 // safe without reasoning about every function's semantics individually.
@@ -407,28 +199,28 @@ func astNodeTypeInt(node reflect.Value) (int64, bool) {
 //
 // # Permitted node types and why each is safe
 //
-//   - ASTField, ASTCurrentNode, ASTIdentity, ASTIndex, ASTIndexExpression,
-//     ASTSlice: pure navigation into existing data (field/index/slice
+//   - field, current node, identity, index, index expression,
+//     slice: pure navigation into existing data (field/index/slice
 //     access). None of these can construct a new map; they only select an
 //     existing value or produce a scalar/nil.
-//   - ASTFlatten (`[]`): maps a child expression over a list; the shape of
+//   - flatten (`[]`): maps a child expression over a list; the shape of
 //     any resulting map comes entirely from that child expression, which is
 //     walked with the same scrutiny.
-//   - ASTPipe, ASTSubexpression, ASTProjection (`|`, `.`, `[*]`): a
+//   - pipe, subexpression, projection (`|`, `.`, `[*]`): a
 //     (left, right) pair where the right-hand child is evaluated against the
 //     left-hand child's result — see the output-position rule below.
-//   - ASTValueProjection (`.*`): extracts the VALUES of its left child, so
+//   - value projection (`.*`): extracts the VALUES of its left child, so
 //     that child's own shape never survives; its left child is always walked
 //     in non-collecting mode.
-//   - ASTLiteral: permitted ONLY when its decoded value is a scalar (string,
+//   - literal: permitted ONLY when its decoded value is a scalar (string,
 //     number, bool, or null) — never an object or array. A backtick JSON
 //     literal can embed an arbitrary object (“ `{"b":1,"a":2}` “), which
 //     would be exactly the same "map with a spuriously borrowed order" bug
 //     the whitelist exists to prevent; a scalar literal cannot.
-//   - ASTMultiSelectHash, ASTKeyValPair: the hash construct itself and its
+//   - multiselect hash, key/value pair: the hash construct itself and its
 //     key/value children — this is the thing being measured.
-//   - ASTComparator (`==`, `!=`, `<`, ...) and the condition child of
-//     ASTFilterProjection (`[?...]`): permitted as node types, but their
+//   - comparator (`==`, `!=`, `<`, ...) and the condition child of
+//     filter projection (`[?...]`): permitted as node types, but their
 //     operands/condition are walked in "non-collecting" mode (see below) —
 //     a comparator's result is always a boolean, never the map it compared,
 //     and a filter condition's result is always discarded after filtering.
@@ -438,17 +230,16 @@ func astNodeTypeInt(node reflect.Value) (int64, bool) {
 //     rejected (nil) rather than silently ignoring it, since a hash placed
 //     there is unusual enough that erring conservative costs nothing.
 //
-// Every other node type — ASTFunctionExpression, ASTOrExpression,
-// ASTAndExpression, ASTNotExpression, ASTMultiSelectList, ASTExpRef,
-// ASTEmpty, and any type this function has not been taught about — is
-// refused unconditionally, anywhere in the tree.
+// Every other node type — function call, or, and, not, multiselect list,
+// expression reference, let, variable, root, and any type this function has
+// not been taught about — is refused unconditionally, anywhere in the tree.
 //
 // # The output-position rule
 //
 // For the (left, right) node types, the left child is only in output
 // position when the right child selects something the left child BUILT — an
 // element, by index, slice or sub-projection. The moment the right child
-// reaches INSIDE a value (any ASTField or ASTValueProjection anywhere in its
+// reaches INSIDE a value (any field or value projection anywhere in its
 // subtree — see rhsSelectsIntoValue) what comes out is arbitrary payload
 // data, so a hash on the left is non-terminal and is walked in
 // non-collecting mode.
@@ -466,28 +257,24 @@ func MultiSelectHashKeyOrders(queryStr string) [][]string {
 	if queryStr == "" {
 		return nil
 	}
-	calibrateAST()
-	if !astUsable {
-		return nil
-	}
-	node, err := jmespath.NewParser().Parse(queryStr)
+	n, err := compile(queryStr)
 	if err != nil {
 		return nil
 	}
 	var out [][]string
 	hashCount := 0
 	unsafe := false
-	collectHashKeys(reflect.ValueOf(node), true, &out, &hashCount, &unsafe)
+	collectHashKeys(n, true, &out, &hashCount, &unsafe)
 	if unsafe || hashCount != 1 || len(out) == 0 {
 		return nil
 	}
 	return out
 }
 
-// collectHashKeys recursively walks the AST rooted at node. collect reports
-// whether node is in a position whose evaluated value can become (part of)
+// collectHashKeys recursively walks the AST rooted at n. collect reports
+// whether n is in a position whose evaluated value can become (part of)
 // the query's final output ("flow position"); it starts true at the root and
-// is forced false for an ASTComparator's operands and an ASTFilterProjection
+// is forced false for a comparator's operands and a filter projection's
 // condition child (see MultiSelectHashKeyOrders), since neither ever
 // contributes to the rendered result.
 //
@@ -500,111 +287,48 @@ func MultiSelectHashKeyOrders(queryStr string) [][]string {
 // nil even in the (impossible today, since hashCount>1 already nils out)
 // case where it was the query's only hash.
 //
-// Any node type not individually recognised sets *unsafe. Every reflect
-// access is guarded so a hostile or unexpected AST shape is a no-op rather
-// than a panic. A malformed hash (an unexpected child kind) is simply
-// skipped, not treated as a reason to abandon the rest of the walk.
-func collectHashKeys(node reflect.Value, collect bool, out *[][]string, hashCount *int, unsafe *bool) {
-	nodeType, ok := astNodeTypeInt(node)
-	if !ok {
-		return
-	}
-
-	if nodeType == literalType {
-		val := node.FieldByName("value")
-		if val.IsValid() && val.Kind() == reflect.Interface && val.Elem().IsValid() {
-			switch val.Elem().Kind() {
-			case reflect.Map, reflect.Slice:
-				*unsafe = true
-			}
+// Any node type not individually recognised sets *unsafe.
+func collectHashKeys(n node, collect bool, out *[][]string, hashCount *int, unsafe *bool) {
+	switch n.typ {
+	case nodeLiteral:
+		switch n.value.(type) {
+		case map[string]interface{}, []interface{}:
+			*unsafe = true
 		}
-		return
-	}
 
-	if nodeType == multiSelectHashType {
+	case nodeMultiSelectHash:
 		*hashCount++
 		if !collect {
 			*unsafe = true
 		}
-		children := node.FieldByName("children")
-		if children.IsValid() && children.Kind() == reflect.Slice {
-			keys := make([]string, 0, children.Len())
-			malformed := false
-			for i := 0; i < children.Len(); i++ {
-				kv := children.Index(i)
-				kvType, ok := astNodeTypeInt(kv)
-				if !ok || kvType != keyValPairType {
-					malformed = true
-					break
-				}
-				val := kv.FieldByName("value")
-				if !val.IsValid() || val.Kind() != reflect.Interface || val.Elem().Kind() != reflect.String {
-					malformed = true
-					break
-				}
-				keys = append(keys, val.Elem().String())
-			}
-			if !malformed && collect {
-				*out = append(*out, keys)
-			}
-			for i := 0; i < children.Len(); i++ {
-				collectHashKeys(children.Index(i), collect, out, hashCount, unsafe)
-			}
+		keys := make([]string, 0, len(n.children))
+		for _, kv := range n.children {
+			keys = append(keys, kv.value.(string))
 		}
-		return
-	}
-
-	if nodeType == keyValPairType {
-		children := node.FieldByName("children")
-		if children.IsValid() && children.Kind() == reflect.Slice {
-			for i := 0; i < children.Len(); i++ {
-				collectHashKeys(children.Index(i), collect, out, hashCount, unsafe)
-			}
+		if collect {
+			*out = append(*out, keys)
 		}
-		return
-	}
-
-	if nodeType == comparatorType {
-		children := node.FieldByName("children")
-		if children.IsValid() && children.Kind() == reflect.Slice {
-			for i := 0; i < children.Len(); i++ {
-				collectHashKeys(children.Index(i), false, out, hashCount, unsafe)
-			}
+		for _, kv := range n.children {
+			collectHashKeys(kv.children[0], collect, out, hashCount, unsafe)
 		}
-		return
-	}
 
-	if nodeType == filterProjectionType {
-		children := node.FieldByName("children")
-		if !children.IsValid() || children.Kind() != reflect.Slice || children.Len() != 3 {
-			*unsafe = true
-			return
+	case nodeComparator:
+		for _, child := range n.children {
+			collectHashKeys(child, false, out, hashCount, unsafe)
 		}
-		collectHashKeys(children.Index(0), collect, out, hashCount, unsafe) // source
-		collectHashKeys(children.Index(1), collect, out, hashCount, unsafe) // projection RHS
-		collectHashKeys(children.Index(2), false, out, hashCount, unsafe)   // condition: discarded
-		return
-	}
 
-	if nodeType == valueProjectionType {
+	case nodeFilterProjection:
+		collectHashKeys(n.children[0], collect, out, hashCount, unsafe) // source
+		collectHashKeys(n.children[1], collect, out, hashCount, unsafe) // projection RHS
+		collectHashKeys(n.children[2], false, out, hashCount, unsafe)   // condition: discarded
+
+	case nodeValueProjection:
 		// "X.*" extracts the VALUES of X, so whatever X built is never what
 		// comes out. A hash on the left is therefore always non-terminal.
-		children := node.FieldByName("children")
-		if !children.IsValid() || children.Kind() != reflect.Slice || children.Len() != 2 {
-			*unsafe = true
-			return
-		}
-		collectHashKeys(children.Index(0), false, out, hashCount, unsafe)
-		collectHashKeys(children.Index(1), collect, out, hashCount, unsafe)
-		return
-	}
+		collectHashKeys(n.children[0], false, out, hashCount, unsafe)
+		collectHashKeys(n.children[1], collect, out, hashCount, unsafe)
 
-	if nodeType == pipeType || nodeType == subexpressionType || nodeType == projectionType {
-		children := node.FieldByName("children")
-		if !children.IsValid() || children.Kind() != reflect.Slice || children.Len() != 2 {
-			*unsafe = true
-			return
-		}
+	case nodePipe, nodeSubexpression, nodeProjection:
 		// The right child is always in the parent's output position. The left
 		// child is only in output position when the right child selects
 		// something the hash itself BUILT (an element, by index/slice/
@@ -612,28 +336,21 @@ func collectHashKeys(node reflect.Value, collect bool, out *[][]string, hashCoun
 		// any field access or value projection — what comes out is arbitrary
 		// payload data, so a hash on the left is non-terminal and its declared
 		// key order must not be attributed to the result (azure-go-cli-c41).
-		leftCollect := collect && !rhsSelectsIntoValue(children.Index(1))
-		collectHashKeys(children.Index(0), leftCollect, out, hashCount, unsafe)
-		collectHashKeys(children.Index(1), collect, out, hashCount, unsafe)
-		return
-	}
-
-	if genericFlowTypes[nodeType] {
-		children := node.FieldByName("children")
-		if !children.IsValid() {
-			return
-		}
-		if children.Kind() != reflect.Slice {
-			*unsafe = true
-			return
+		leftCollect := collect && !rhsSelectsIntoValue(n.children[1])
+		collectHashKeys(n.children[0], leftCollect, out, hashCount, unsafe)
+		collectHashKeys(n.children[1], collect, out, hashCount, unsafe)
+
+	case nodeField, nodeCurrent, nodeIdentity, nodeIndexExpression, nodeIndex, nodeSlice, nodeFlatten:
+		// Generic flow types: they can never themselves evaluate to a map
+		// other than one forwarded unchanged from a child, and they pass the
+		// "is this in output position" question straight through.
+		for _, child := range n.children {
+			collectHashKeys(child, collect, out, hashCount, unsafe)
 		}
-		for i := 0; i < children.Len(); i++ {
-			collectHashKeys(children.Index(i), collect, out, hashCount, unsafe)
-		}
-		return
-	}
 
-	*unsafe = true
+	default:
+		*unsafe = true
+	}
 }
 
 // rhsSelectsIntoValue reports whether the right-hand side of a pipe or
@@ -645,20 +362,12 @@ func collectHashKeys(node reflect.Value, collect bool, out *[][]string, hashCoun
 // "yes". That bias is deliberate: a false "yes" only demotes a hash to sorted
 // column order (the behaviour before azure-go-cli-c41), while a false "no"
 // stamps a hash's declared order onto an unrelated map.
-func rhsSelectsIntoValue(node reflect.Value) bool {
-	nodeType, ok := astNodeTypeInt(node)
-	if !ok {
-		return true
-	}
-	if nodeType == fieldType || nodeType == valueProjectionType {
+func rhsSelectsIntoValue(n node) bool {
+	if n.typ == nodeField || n.typ == nodeValueProjection {
 		return true
 	}
-	children := node.FieldByName("children")
-	if !children.IsValid() || children.Kind() != reflect.Slice {
-		return false
-	}
-	for i := 0; i < children.Len(); i++ {
-		if rhsSelectsIntoValue(children.Index(i)) {
+	for _, child := range n.children {
+		if rhsSelectsIntoValue(child) {
 			return true
 		}
 	}
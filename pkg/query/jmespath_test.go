@@ -5,17 +5,14 @@ import (
 	"testing"
 )
 
-// TestASTIntrospectionCalibration is a canary: it pins the reflect-based
-// key-order recovery to go-jmespath's current AST shape. If go-jmespath is
-// ever upgraded and ASTNode's fields (or the ordering guarantees of
-// ASTMultiSelectHash's children) change shape, this test fails loudly rather
-// than MultiSelectHashKeyOrders silently degrading to "always nil" in
-// production.
-func TestASTIntrospectionCalibration(t *testing.T) {
+// TestMultiSelectHashKeyOrdersDeclaredOrder is the basic promise callers
+// rely on: a hash's keys come back in the order the query wrote them, not
+// sorted.
+func TestMultiSelectHashKeyOrdersDeclaredOrder(t *testing.T) {
 	got := MultiSelectHashKeyOrders("{b:x, a:y}")
 	want := [][]string{{"b", "a"}}
 	if !reflect.DeepEqual(got, want) {
-		t.Fatalf("MultiSelectHashKeyOrders(%q) = %v, want %v (go-jmespath AST shape may have changed)", "{b:x, a:y}", got, want)
+		t.Fatalf("MultiSelectHashKeyOrders(%q) = %v, want %v", "{b:x, a:y}", got, want)
 	}
 }
 
@@ -106,62 +103,61 @@ func TestMultiSelectHashKeyOrders(t *testing.T) {
 	}
 }
 
-// TestPreservesNumberLiterals pins the whitelist. A query answers true only
-// when its whole AST is free of function expressions and comparators, which
-// are the two places go-jmespath v0.4.0 type-asserts a number to float64
-// (functions.go:353 / functions.go:405 and interpreter.go:48-55).
-func TestPreservesNumberLiterals(t *testing.T) {
+func TestIsElementwise(t *testing.T) {
 	tests := []struct {
-		name  string
 		query string
 		want  bool
 	}{
-		// Safe: pure navigation / construction.
-		{"field", "name", true},
-		{"subexpression", "properties.provisioningState", true},
-		{"index", "[0].id", true},
-		{"slice", "[1:3]", true},
-		{"flatten projection", "[].id", true},
-		{"list projection", "[*].id", true},
-		{"value projection", "a.*", true},
-		{"multiselect hash", "[].{Name: name, Port: port}", true},
-		{"multiselect list", "[].[name, port]", true},
-		{"pipe", "[].{Name: name} | [0]", true},
-		{"current node", "@", true},
-		{"scalar literal", "`1`", true},
-		{"truthiness filter", "[?enabled].name", true},
-		{"or", "a || b", true},
-		{"and", "a && b", true},
-		{"not", "!a", true},
-
-		// Unsafe: comparator anywhere. go-jmespath's <,>,<=,>= return nil for
-		// a json.Number operand and ==/!= compare it with reflect.DeepEqual
-		// against a float64 literal, so these silently mis-filter.
-		{"comparator at root", "a == `1`", false},
-		{"comparator in filter condition", "[?port > `80`].name", false},
-		{"string comparator in filter condition", "[?name == 'x'].id", false},
-		{"comparator under a hash", "{A: a == `1`}", false},
-		{"comparator behind a pipe", "[].{Name: name} | [?Name == 'x']", false},
-
-		// Unsafe: any function. These error outright on a json.Number.
-		{"avg", "avg(a)", false},
-		{"sum", "sum([].port)", false},
-		{"to_number", "to_number(a)", false},
-		{"max", "max(a)", false},
-		{"sort_by", "sort_by(items, &v)", false},
-		{"length even though it is number-safe", "length(@)", false},
-		{"function nested under a hash", "{N: length(a)}", false},
-		{"function nested in a filter condition", "[?contains(name, 'x')].id", false},
+		{"", false},
+		{"[].name", true},
+		{"[*].name", true},
+		{"@[].name", true},
+		{"[].{Name:name, Location:location}", true},
+		{"[?location=='eastus'].name", true},
+		{"[?tags.env]", true},
+		{"[]", true},
+		{"[].properties.subnets[].id", true},
+		{"[0]", false},
+		{"[0:5].name", false},
+		{"[].name | [0]", false},
+		{"length(@)", false},
+		{"sort_by(@, &name)[].name", false},
+		{"value[].name", false},
+		{"*.name", false},
+		{"name", false},
+		{"[", false},
+	}
+	for _, tt := range tests {
+		if got := IsElementwise(tt.query); got != tt.want {
+			t.Errorf("IsElementwise(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
 
-		// Degenerate.
-		{"empty query", "", false},
-		{"unparseable query", "{{{", false},
+	// The property IsElementwise promises: per-element evaluation matches
+	// whole-list evaluation.
+	list := []interface{}{
+		map[string]interface{}{"name": "a", "location": "eastus", "tags": map[string]interface{}{"env": "dev"}},
+		map[string]interface{}{"name": "b", "location": "westus"},
+		map[string]interface{}{"location": "eastus"},
 	}
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := PreservesNumberLiterals(tt.query); got != tt.want {
-				t.Errorf("PreservesNumberLiterals(%q) = %v, want %v", tt.query, got, tt.want)
+		if !tt.want {
+			continue
+		}
+		whole, err := ApplyJMESPath(list, tt.query)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.query, err)
+		}
+		var perElement []interface{}
+		for _, el := range list {
+			r, err := ApplyJMESPath([]interface{}{el}, tt.query)
+			if err != nil {
+				t.Fatalf("%q: %v", tt.query, err)
 			}
-		})
+			perElement = append(perElement, r.([]interface{})...)
+		}
+		if w, _ := whole.([]interface{}); !reflect.DeepEqual(w, perElement) && !(len(w) == 0 && len(perElement) == 0) {
+			t.Errorf("%q: whole list %v, per element %v", tt.query, whole, perElement)
+		}
 	}
 }
@@ -0,0 +1,278 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	tEOF tokenType = iota
+	tUnquotedIdentifier
+	tQuotedIdentifier
+	tStringLiteral // 'raw string'
+	tJSONLiteral   // `json`
+	tNumber
+	tVariable // $name
+	tRoot     // bare $
+	tDot
+	tStar
+	tFilter  // [?
+	tFlatten // []
+	tLbracket
+	tRbracket
+	tLbrace
+	tRbrace
+	tLparen
+	tRparen
+	tComma
+	tColon
+	tPipe
+	tOr
+	tAnd
+	tNot
+	tCurrent
+	tExpref
+	tAssign
+	tEQ
+	tNE
+	tLT
+	tLTE
+	tGT
+	tGTE
+)
+
+var tokenNames = map[tokenType]string{
+	tEOF:                "end of expression",
+	tUnquotedIdentifier: "identifier",
+	tQuotedIdentifier:   "quoted identifier",
+	tStringLiteral:      "raw string literal",
+	tJSONLiteral:        "JSON literal",
+	tNumber:             "number",
+	tVariable:           "variable",
+	tRoot:               "'$'",
+	tDot:                "'.'",
+	tStar:               "'*'",
+	tFilter:             "'[?'",
+	tFlatten:            "'[]'",
+	tLbracket:           "'['",
+	tRbracket:           "']'",
+	tLbrace:             "'{'",
+	tRbrace:             "'}'",
+	tLparen:             "'('",
+	tRparen:             "')'",
+	tComma:              "','",
+	tColon:              "':'",
+	tPipe:               "'|'",
+	tOr:                 "'||'",
+	tAnd:                "'&&'",
+	tNot:                "'!'",
+	tCurrent:            "'@'",
+	tExpref:             "'&'",
+	tAssign:             "'='",
+	tEQ:                 "'=='",
+	tNE:                 "'!='",
+	tLT:                 "'<'",
+	tLTE:                "'<='",
+	tGT:                 "'>'",
+	tGTE:                "'>='",
+}
+
+func (t tokenType) String() string {
+	return tokenNames[t]
+}
+
+type token struct {
+	typ      tokenType
+	value    string
+	literal  interface{} // decoded value of a tJSONLiteral
+	position int
+}
+
+// SyntaxError reports a query that does not lex or parse, with the byte
+// offset the problem was found at.
+type SyntaxError struct {
+	msg        string
+	Expression string
+	Offset     int
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("%s at column %d of %q", e.msg, e.Offset, e.Expression)
+}
+
+// lex splits expression into tokens, ending with a tEOF token.
+func lex(expression string) ([]token, error) {
+	var tokens []token
+	fail := func(pos int, format string, args ...interface{}) ([]token, error) {
+		return nil, SyntaxError{msg: fmt.Sprintf(format, args...), Expression: expression, Offset: pos}
+	}
+	// two emits a two-character token if the next byte is second, else the
+	// one-character fallback.
+	two := func(i int, second byte, both, one tokenType) int {
+		if i+1 < len(expression) && expression[i+1] == second {
+			tokens = append(tokens, token{typ: both, value: expression[i : i+2], position: i})
+			return i + 2
+		}
+		tokens = append(tokens, token{typ: one, value: expression[i : i+1], position: i})
+		return i + 1
+	}
+
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			end := identEnd(expression, i)
+			tokens = append(tokens, token{typ: tUnquotedIdentifier, value: expression[i:end], position: i})
+			i = end
+		case c == '"':
+			end, err := delimitedEnd(expression, i, '"')
+			if err != nil {
+				return fail(i, "unterminated quoted identifier")
+			}
+			var name string
+			if err := json.Unmarshal([]byte(expression[i:end]), &name); err != nil {
+				return fail(i, "invalid quoted identifier %s", expression[i:end])
+			}
+			tokens = append(tokens, token{typ: tQuotedIdentifier, value: name, position: i})
+			i = end
+		case c == '\'':
+			end, err := delimitedEnd(expression, i, '\'')
+			if err != nil {
+				return fail(i, "unterminated raw string literal")
+			}
+			raw := strings.ReplaceAll(expression[i+1:end-1], `\'`, `'`)
+			tokens = append(tokens, token{typ: tStringLiteral, value: raw, position: i})
+			i = end
+		case c == '`':
+			end, err := delimitedEnd(expression, i, '`')
+			if err != nil {
+				return fail(i, "unterminated JSON literal")
+			}
+			raw := strings.ReplaceAll(expression[i+1:end-1], "\\`", "`")
+			v, err := decodeJSONLiteral(raw)
+			if err != nil {
+				return fail(i, "invalid JSON literal %s", expression[i:end])
+			}
+			tokens = append(tokens, token{typ: tJSONLiteral, value: raw, literal: v, position: i})
+			i = end
+		case c == '-' || isDigit(c):
+			end := i + 1
+			for end < len(expression) && isDigit(expression[end]) {
+				end++
+			}
+			if c == '-' && end == i+1 {
+				return fail(i, "'-' must be followed by a digit")
+			}
+			tokens = append(tokens, token{typ: tNumber, value: expression[i:end], position: i})
+			i = end
+		case c == '$':
+			if i+1 < len(expression) && isIdentStart(expression[i+1]) {
+				end := identEnd(expression, i+1)
+				tokens = append(tokens, token{typ: tVariable, value: expression[i+1 : end], position: i})
+				i = end
+			} else {
+				tokens = append(tokens, token{typ: tRoot, value: "$", position: i})
+				i++
+			}
+		case c == '[':
+			switch {
+			case i+1 < len(expression) && expression[i+1] == '?':
+				tokens = append(tokens, token{typ: tFilter, value: "[?", position: i})
+				i += 2
+			case i+1 < len(expression) && expression[i+1] == ']':
+				tokens = append(tokens, token{typ: tFlatten, value: "[]", position: i})
+				i += 2
+			default:
+				tokens = append(tokens, token{typ: tLbracket, value: "[", position: i})
+				i++
+			}
+		case c == '|':
+			i = two(i, '|', tOr, tPipe)
+		case c == '&':
+			i = two(i, '&', tAnd, tExpref)
+		case c == '<':
+			i = two(i, '=', tLTE, tLT)
+		case c == '>':
+			i = two(i, '=', tGTE, tGT)
+		case c == '!':
+			i = two(i, '=', tNE, tNot)
+		case c == '=':
+			i = two(i, '=', tEQ, tAssign)
+		default:
+			typ, ok := singleCharTokens[c]
+			if !ok {
+				r, _ := utf8.DecodeRuneInString(expression[i:])
+				return fail(i, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{typ: typ, value: expression[i : i+1], position: i})
+			i++
+		}
+	}
+	tokens = append(tokens, token{typ: tEOF, position: len(expression)})
+	return tokens, nil
+}
+
+var singleCharTokens = map[byte]tokenType{
+	'.': tDot,
+	'*': tStar,
+	']': tRbracket,
+	'{': tLbrace,
+	'}': tRbrace,
+	'(': tLparen,
+	')': tRparen,
+	',': tComma,
+	':': tColon,
+	'@': tCurrent,
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func identEnd(s string, i int) int {
+	for i < len(s) && (isIdentStart(s[i]) || isDigit(s[i])) {
+		i++
+	}
+	return i
+}
+
+// delimitedEnd returns the index just past the delimiter closing the token
+// that opens at s[start], skipping backslash-escaped characters.
+func delimitedEnd(s string, start int, delim byte) (int, error) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case delim:
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated")
+}
+
+// decodeJSONLiteral decodes a backtick literal with numbers kept as
+// json.Number. Like jmespath.py it still accepts the deprecated unquoted
+// string form (`foo` meaning "foo").
+func decodeJSONLiteral(raw string) (interface{}, error) {
+	if !json.Valid([]byte(raw)) {
+		return strings.TrimLeft(raw, " \t\n\r"), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
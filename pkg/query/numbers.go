@@ -0,0 +1,134 @@
+package query
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Numbers flow through a query as the json.Number literals they were
+// decoded from, so a value is printed exactly as the API sent it however
+// large it is. They are only converted where a query does arithmetic or
+// compares them, and then with Python's int/float split: an integer literal
+// stays an arbitrary-precision integer and anything with a fraction or
+// exponent is a float64, which is what knack does with the same JSON.
+//
+// float64 and Go integer values are accepted as well, for callers that pass
+// a tree decoded without UseNumber; an integral float64 counts as an integer.
+
+// numberPrecision is the mantissa size numbers are compared at. It is exact
+// for any integer of up to 300 digits, well past anything an API returns.
+const numberPrecision = 1024
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case json.Number, float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	}
+	return false
+}
+
+// bigFloat returns v's exact value (up to numberPrecision bits).
+func bigFloat(v interface{}) *big.Float {
+	f := new(big.Float).SetPrec(numberPrecision)
+	switch n := v.(type) {
+	case json.Number:
+		if _, ok := f.SetString(string(n)); ok {
+			return f
+		}
+		x, _ := strconv.ParseFloat(string(n), 64)
+		return f.SetFloat64(x)
+	case float64:
+		if math.IsNaN(n) {
+			return f
+		}
+		return f.SetFloat64(n)
+	case float32:
+		return f.SetFloat64(float64(n))
+	case int:
+		return f.SetInt64(int64(n))
+	case int8:
+		return f.SetInt64(int64(n))
+	case int16:
+		return f.SetInt64(int64(n))
+	case int32:
+		return f.SetInt64(int64(n))
+	case int64:
+		return f.SetInt64(n)
+	case uint:
+		return f.SetUint64(uint64(n))
+	case uint8:
+		return f.SetUint64(uint64(n))
+	case uint16:
+		return f.SetUint64(uint64(n))
+	case uint32:
+		return f.SetUint64(uint64(n))
+	case uint64:
+		return f.SetUint64(n)
+	}
+	return f
+}
+
+func compareNumbers(a, b interface{}) int {
+	return bigFloat(a).Cmp(bigFloat(b))
+}
+
+// integerValue returns v as an integer if it is one in Python's sense: an
+// integer literal, a Go integer, or an integral float64.
+func integerValue(v interface{}) (*big.Int, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		if strings.ContainsAny(string(n), ".eE") {
+			return nil, false
+		}
+		i, ok := new(big.Int).SetString(string(n), 10)
+		return i, ok
+	case float64:
+		if math.IsInf(n, 0) || math.IsNaN(n) || n != math.Trunc(n) {
+			return nil, false
+		}
+		i, _ := big.NewFloat(n).Int(nil)
+		return i, true
+	case float32:
+		return nil, false
+	}
+	if !isNumber(v) {
+		return nil, false
+	}
+	i, _ := bigFloat(v).Int(nil)
+	return i, true
+}
+
+func floatValue(v interface{}) float64 {
+	if n, ok := v.(float64); ok {
+		return n
+	}
+	f, _ := bigFloat(v).Float64()
+	return f
+}
+
+func intNumber(i *big.Int) json.Number {
+	return json.Number(i.String())
+}
+
+// floatNumber renders f the way Python's repr does, so a float result
+// prints as knack prints it: always with a decimal point or an exponent
+// ("2.0", "1e+16"), switching to exponent form below 1e-4 and from 1e16.
+// NaN and infinities are not JSON and become null.
+func floatNumber(f float64) interface{} {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil
+	}
+	exp := strconv.FormatFloat(f, 'e', -1, 64)
+	e, _ := strconv.Atoi(exp[strings.IndexByte(exp, 'e')+1:])
+	if e < -4 || e >= 16 {
+		return json.Number(exp)
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return json.Number(s)
+}
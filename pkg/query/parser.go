@@ -0,0 +1,499 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type nodeType int
+
+const (
+	nodeField            nodeType = iota // value: name
+	nodeCurrent                          // @
+	nodeIdentity                         // the implicit input of a bare [..] or *
+	nodeRoot                             // $
+	nodeVariable                         // value: name
+	nodeLiteral                          // value: decoded literal
+	nodeIndex                            // value: int
+	nodeSlice                            // value: [3]*int
+	nodeIndexExpression                  // children: source, index/slice
+	nodeSubexpression                    // children: left, right
+	nodePipe                             // children: left, right
+	nodeProjection                       // children: source, applied to each element
+	nodeValueProjection                  // children: source, applied to each value
+	nodeFilterProjection                 // children: source, applied to each match, condition
+	nodeFlatten                          // children: source
+	nodeMultiSelectList                  // children: elements
+	nodeMultiSelectHash                  // children: nodeKeyValPair
+	nodeKeyValPair                       // value: key; children: value
+	nodeComparator                       // value: tokenType; children: left, right
+	nodeOr                               // children: left, right
+	nodeAnd                              // children: left, right
+	nodeNot                              // children: operand
+	nodeFunction                         // value: name; children: arguments
+	nodeExpRef                           // children: expression
+	nodeLet                              // children: nodeBinding..., body
+	nodeBinding                          // value: variable name; children: value
+)
+
+// node is a parsed query. Its shape matches the JMESPath reference
+// implementations, so `a[*].b` is a projection whose source is `a`.
+type node struct {
+	typ      nodeType
+	value    interface{}
+	children []node
+}
+
+// bindingPowers drive the Pratt parser: a token continues the expression on
+// its left only if it binds tighter than the caller's right binding power.
+// Values follow jmespath.py.
+var bindingPowers = map[tokenType]int{
+	tPipe:     1,
+	tOr:       2,
+	tAnd:      3,
+	tEQ:       5,
+	tNE:       5,
+	tLT:       5,
+	tLTE:      5,
+	tGT:       5,
+	tGTE:      5,
+	tFlatten:  9,
+	tStar:     20,
+	tFilter:   21,
+	tDot:      40,
+	tNot:      45,
+	tLbrace:   50,
+	tLbracket: 55,
+	tLparen:   60,
+}
+
+// projectionStop is the binding power below which a token ends a
+// projection's right-hand side.
+const projectionStop = 10
+
+type parser struct {
+	expression string
+	tokens     []token
+	index      int
+}
+
+// parse compiles expression into its AST.
+func parse(expression string) (node, error) {
+	tokens, err := lex(expression)
+	if err != nil {
+		return node{}, err
+	}
+	p := &parser{expression: expression, tokens: tokens}
+	n, err := p.parseExpression(0)
+	if err != nil {
+		return node{}, err
+	}
+	if p.current() != tEOF {
+		return node{}, p.errorf("unexpected %s after the end of the expression", p.current())
+	}
+	return n, nil
+}
+
+func (p *parser) parseExpression(bindingPower int) (node, error) {
+	tok := p.lookaheadToken(0)
+	p.advance()
+	left, err := p.nud(tok)
+	if err != nil {
+		return node{}, err
+	}
+	for bindingPower < bindingPowers[p.current()] {
+		tok := p.lookaheadToken(0)
+		p.advance()
+		left, err = p.led(tok, left)
+		if err != nil {
+			return node{}, err
+		}
+	}
+	return left, nil
+}
+
+// nud parses a token that starts an expression.
+func (p *parser) nud(tok token) (node, error) {
+	switch tok.typ {
+	case tJSONLiteral:
+		return node{typ: nodeLiteral, value: tok.literal}, nil
+	case tStringLiteral:
+		return node{typ: nodeLiteral, value: tok.value}, nil
+	case tUnquotedIdentifier:
+		if tok.value == "let" && p.current() == tVariable {
+			return p.parseLet()
+		}
+		return node{typ: nodeField, value: tok.value}, nil
+	case tQuotedIdentifier:
+		if p.current() == tLparen {
+			return node{}, p.errorf("a quoted identifier cannot be a function name")
+		}
+		return node{typ: nodeField, value: tok.value}, nil
+	case tVariable:
+		return node{typ: nodeVariable, value: tok.value}, nil
+	case tRoot:
+		return node{typ: nodeRoot}, nil
+	case tCurrent:
+		return node{typ: nodeCurrent}, nil
+	case tStar:
+		left := node{typ: nodeIdentity}
+		right := node{typ: nodeIdentity}
+		if p.current() != tRbracket {
+			var err error
+			if right, err = p.parseProjectionRHS(bindingPowers[tStar]); err != nil {
+				return node{}, err
+			}
+		}
+		return node{typ: nodeValueProjection, children: []node{left, right}}, nil
+	case tFilter:
+		return p.parseFilter(node{typ: nodeIdentity})
+	case tLbrace:
+		return p.parseMultiSelectHash()
+	case tFlatten:
+		left := node{typ: nodeFlatten, children: []node{{typ: nodeIdentity}}}
+		right, err := p.parseProjectionRHS(bindingPowers[tFlatten])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeProjection, children: []node{left, right}}, nil
+	case tLbracket:
+		switch {
+		case p.current() == tNumber || p.current() == tColon:
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return node{}, err
+			}
+			return p.projectIfSlice(node{typ: nodeIdentity}, right)
+		case p.current() == tStar && p.lookahead(1) == tRbracket:
+			p.advance()
+			p.advance()
+			right, err := p.parseProjectionRHS(bindingPowers[tStar])
+			if err != nil {
+				return node{}, err
+			}
+			return node{typ: nodeProjection, children: []node{{typ: nodeIdentity}, right}}, nil
+		}
+		return p.parseMultiSelectList()
+	case tExpref:
+		expr, err := p.parseExpression(bindingPowers[tExpref])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeExpRef, children: []node{expr}}, nil
+	case tNot:
+		expr, err := p.parseExpression(bindingPowers[tNot])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeNot, children: []node{expr}}, nil
+	case tLparen:
+		expr, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		if err := p.match(tRparen); err != nil {
+			return node{}, err
+		}
+		return expr, nil
+	case tEOF:
+		return node{}, SyntaxError{msg: "incomplete expression", Expression: p.expression, Offset: tok.position}
+	}
+	return node{}, SyntaxError{msg: fmt.Sprintf("unexpected %s", tok.typ), Expression: p.expression, Offset: tok.position}
+}
+
+// led parses a token that continues the expression left.
+func (p *parser) led(tok token, left node) (node, error) {
+	switch tok.typ {
+	case tDot:
+		if p.current() != tStar {
+			right, err := p.parseDotRHS(bindingPowers[tDot])
+			if err != nil {
+				return node{}, err
+			}
+			return node{typ: nodeSubexpression, children: []node{left, right}}, nil
+		}
+		p.advance()
+		right, err := p.parseProjectionRHS(bindingPowers[tDot])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeValueProjection, children: []node{left, right}}, nil
+	case tPipe, tOr, tAnd:
+		right, err := p.parseExpression(bindingPowers[tok.typ])
+		if err != nil {
+			return node{}, err
+		}
+		typ := map[tokenType]nodeType{tPipe: nodePipe, tOr: nodeOr, tAnd: nodeAnd}[tok.typ]
+		return node{typ: typ, children: []node{left, right}}, nil
+	case tEQ, tNE, tLT, tLTE, tGT, tGTE:
+		right, err := p.parseExpression(bindingPowers[tok.typ])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeComparator, value: tok.typ, children: []node{left, right}}, nil
+	case tLparen:
+		if left.typ != nodeField {
+			return node{}, SyntaxError{msg: "invalid function call", Expression: p.expression, Offset: tok.position}
+		}
+		var args []node
+		for p.current() != tRparen {
+			arg, err := p.parseExpression(0)
+			if err != nil {
+				return node{}, err
+			}
+			args = append(args, arg)
+			if p.current() != tRparen {
+				if err := p.match(tComma); err != nil {
+					return node{}, err
+				}
+			}
+		}
+		if err := p.match(tRparen); err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeFunction, value: left.value, children: args}, nil
+	case tFilter:
+		return p.parseFilter(left)
+	case tFlatten:
+		source := node{typ: nodeFlatten, children: []node{left}}
+		right, err := p.parseProjectionRHS(bindingPowers[tFlatten])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeProjection, children: []node{source, right}}, nil
+	case tLbracket:
+		if p.current() == tNumber || p.current() == tColon {
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return node{}, err
+			}
+			return p.projectIfSlice(left, right)
+		}
+		if err := p.match(tStar); err != nil {
+			return node{}, err
+		}
+		if err := p.match(tRbracket); err != nil {
+			return node{}, err
+		}
+		right, err := p.parseProjectionRHS(bindingPowers[tStar])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeProjection, children: []node{left, right}}, nil
+	}
+	return node{}, SyntaxError{msg: fmt.Sprintf("unexpected %s", tok.typ), Expression: p.expression, Offset: tok.position}
+}
+
+// parseLet parses `let $a = expr, $b = expr in body`; the "let" has been
+// consumed. Bindings are evaluated in the enclosing scope, so one binding
+// cannot see another.
+func (p *parser) parseLet() (node, error) {
+	var children []node
+	for {
+		if p.current() != tVariable {
+			return node{}, p.errorf("expected a variable, got %s", p.current())
+		}
+		name := p.lookaheadToken(0).value
+		p.advance()
+		if err := p.match(tAssign); err != nil {
+			return node{}, err
+		}
+		value, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		children = append(children, node{typ: nodeBinding, value: name, children: []node{value}})
+		if p.current() != tComma {
+			break
+		}
+		p.advance()
+	}
+	if tok := p.lookaheadToken(0); tok.typ != tUnquotedIdentifier || tok.value != "in" {
+		return node{}, p.errorf("expected 'in', got %s", tok.typ)
+	}
+	p.advance()
+	body, err := p.parseExpression(0)
+	if err != nil {
+		return node{}, err
+	}
+	return node{typ: nodeLet, children: append(children, body)}, nil
+}
+
+func (p *parser) parseIndexExpression() (node, error) {
+	if p.lookahead(0) == tColon || p.lookahead(1) == tColon {
+		return p.parseSliceExpression()
+	}
+	n, err := strconv.Atoi(p.lookaheadToken(0).value)
+	if err != nil {
+		return node{}, p.errorf("invalid index %s", p.lookaheadToken(0).value)
+	}
+	p.advance()
+	if err := p.match(tRbracket); err != nil {
+		return node{}, err
+	}
+	return node{typ: nodeIndex, value: n}, nil
+}
+
+func (p *parser) parseSliceExpression() (node, error) {
+	var parts [3]*int
+	index := 0
+	for p.current() != tRbracket && index < 3 {
+		switch p.current() {
+		case tColon:
+			index++
+			p.advance()
+		case tNumber:
+			n, err := strconv.Atoi(p.lookaheadToken(0).value)
+			if err != nil {
+				return node{}, p.errorf("invalid slice index %s", p.lookaheadToken(0).value)
+			}
+			parts[index] = &n
+			p.advance()
+		default:
+			return node{}, p.errorf("unexpected %s in slice", p.current())
+		}
+	}
+	if err := p.match(tRbracket); err != nil {
+		return node{}, err
+	}
+	return node{typ: nodeSlice, value: parts}, nil
+}
+
+// projectIfSlice wraps an index expression whose index is a slice in a
+// projection, since `a[0:2].b` projects `.b` over the sliced elements.
+func (p *parser) projectIfSlice(left, right node) (node, error) {
+	indexExpr := node{typ: nodeIndexExpression, children: []node{left, right}}
+	if right.typ != nodeSlice {
+		return indexExpr, nil
+	}
+	rhs, err := p.parseProjectionRHS(bindingPowers[tStar])
+	if err != nil {
+		return node{}, err
+	}
+	return node{typ: nodeProjection, children: []node{indexExpr, rhs}}, nil
+}
+
+func (p *parser) parseFilter(left node) (node, error) {
+	condition, err := p.parseExpression(0)
+	if err != nil {
+		return node{}, err
+	}
+	if err := p.match(tRbracket); err != nil {
+		return node{}, err
+	}
+	right := node{typ: nodeIdentity}
+	if p.current() != tFlatten {
+		if right, err = p.parseProjectionRHS(bindingPowers[tFilter]); err != nil {
+			return node{}, err
+		}
+	}
+	return node{typ: nodeFilterProjection, children: []node{left, right, condition}}, nil
+}
+
+func (p *parser) parseDotRHS(bindingPower int) (node, error) {
+	switch p.current() {
+	case tQuotedIdentifier, tUnquotedIdentifier, tStar:
+		return p.parseExpression(bindingPower)
+	case tLbracket:
+		p.advance()
+		return p.parseMultiSelectList()
+	case tLbrace:
+		p.advance()
+		return p.parseMultiSelectHash()
+	}
+	return node{}, p.errorf("expected an identifier, '[' or '{' after '.', got %s", p.current())
+}
+
+func (p *parser) parseProjectionRHS(bindingPower int) (node, error) {
+	switch current := p.current(); {
+	case bindingPowers[current] < projectionStop:
+		return node{typ: nodeIdentity}, nil
+	case current == tLbracket || current == tFilter:
+		return p.parseExpression(bindingPower)
+	case current == tDot:
+		p.advance()
+		return p.parseDotRHS(bindingPower)
+	}
+	return node{}, p.errorf("unexpected %s after a projection", p.current())
+}
+
+func (p *parser) parseMultiSelectList() (node, error) {
+	var children []node
+	for {
+		expr, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		children = append(children, expr)
+		if p.current() == tRbracket {
+			break
+		}
+		if err := p.match(tComma); err != nil {
+			return node{}, err
+		}
+	}
+	if err := p.match(tRbracket); err != nil {
+		return node{}, err
+	}
+	return node{typ: nodeMultiSelectList, children: children}, nil
+}
+
+func (p *parser) parseMultiSelectHash() (node, error) {
+	var children []node
+	for {
+		tok := p.lookaheadToken(0)
+		if tok.typ != tUnquotedIdentifier && tok.typ != tQuotedIdentifier {
+			return node{}, p.errorf("expected a key name, got %s", tok.typ)
+		}
+		p.advance()
+		if err := p.match(tColon); err != nil {
+			return node{}, err
+		}
+		value, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		children = append(children, node{typ: nodeKeyValPair, value: tok.value, children: []node{value}})
+		if p.current() == tRbrace {
+			p.advance()
+			break
+		}
+		if err := p.match(tComma); err != nil {
+			return node{}, err
+		}
+	}
+	return node{typ: nodeMultiSelectHash, children: children}, nil
+}
+
+func (p *parser) match(typ tokenType) error {
+	if p.current() != typ {
+		return p.errorf("expected %s, got %s", typ, p.current())
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) advance() {
+	if p.index < len(p.tokens)-1 {
+		p.index++
+	}
+}
+
+func (p *parser) current() tokenType {
+	return p.lookahead(0)
+}
+
+func (p *parser) lookahead(n int) tokenType {
+	return p.lookaheadToken(n).typ
+}
+
+func (p *parser) lookaheadToken(n int) token {
+	if p.index+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.index+n]
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return SyntaxError{msg: fmt.Sprintf(format, args...), Expression: p.expression, Offset: p.lookaheadToken(0).position}
+}